@@ -21,8 +21,13 @@
 //	-interactive        Enable interactive command mode
 //	-auto-commission    Automatically commission discovered devices
 //	-state-dir string   Directory for persistent state
+//	-state-backend string State backend: file, sqlite (default "file")
 //	-reset              Clear all persisted state before starting
+//	-key-passphrase-file string Encrypt private keys with the passphrase in this file
+//	-key-signer string  Unix socket of a mash-signer holding the private keys
 //	-protocol-log string File path for protocol event logging (CBOR format)
+//	-gateway string     Serve the WebSocket device gateway on this address (e.g. localhost:8090)
+//	-gateway-token-file string Require the token in this file on the gateway and allow writes and commands
 //
 // Examples:
 //
@@ -58,6 +63,7 @@ import (
 	"io"
 	"log"
 	"log/slog"
+	"net/http"
 	"os"
 	"os/signal"
 	"path/filepath"
@@ -66,6 +72,7 @@ import (
 	"time"
 
 	"github.com/mash-protocol/mash-go/cmd/mash-controller/interactive"
+	"github.com/mash-protocol/mash-go/internal/gateway"
 	"github.com/mash-protocol/mash-go/pkg/cert"
 	"github.com/mash-protocol/mash-go/pkg/discovery"
 	"github.com/mash-protocol/mash-go/internal/examples"
@@ -73,6 +80,7 @@ import (
 	mashlog "github.com/mash-protocol/mash-go/pkg/log"
 	"github.com/mash-protocol/mash-go/pkg/model"
 	"github.com/mash-protocol/mash-go/pkg/persistence"
+	"github.com/mash-protocol/mash-go/pkg/persistence/sqlite"
 	"github.com/mash-protocol/mash-go/pkg/service"
 	"github.com/mash-protocol/mash-go/pkg/usecase"
 	"github.com/mash-protocol/mash-go/pkg/wire"
@@ -89,11 +97,20 @@ type Config struct {
 	AutoCommission bool
 
 	// Persistence settings
-	StateDir string
-	Reset    bool
+	StateDir     string
+	StateBackend string
+	Reset        bool
+
+	// Private key storage
+	KeyPassphraseFile string
+	KeySigner         string
 
 	// Protocol logging
 	ProtocolLogFile string
+
+	// WebSocket gateway for browser tools
+	GatewayAddr      string
+	GatewayTokenFile string
 }
 
 // ZoneName implements interactive.ControllerConfig.
@@ -110,8 +127,27 @@ var (
 	config Config
 	cem    *examples.CEM
 	svc    *service.ControllerService
+	gw     *gateway.Gateway
 )
 
+// newKeyStore returns the private key store selected by the key flags.
+func newKeyStore() cert.KeyStore {
+	switch {
+	case config.KeySigner != "" && config.KeyPassphraseFile != "":
+		log.Fatal("-key-signer and -key-passphrase-file are mutually exclusive")
+	case config.KeySigner != "":
+		log.Printf("Using remote signer: %s", config.KeySigner)
+		return cert.NewRemoteKeyStore("unix", config.KeySigner)
+	case config.KeyPassphraseFile != "":
+		passphrase, err := cert.ReadPassphraseFile(config.KeyPassphraseFile)
+		if err != nil {
+			log.Fatalf("Failed to read key passphrase: %v", err)
+		}
+		return cert.NewEncryptedFileKeyStore(config.StateDir, passphrase)
+	}
+	return cert.NewFileKeyStore(config.StateDir)
+}
+
 type levelFilterWriter struct {
 	out      io.Writer
 	minLevel slog.Level
@@ -154,9 +190,14 @@ func init() {
 	flag.BoolVar(&config.AutoCommission, "auto-commission", false, "Automatically commission discovered devices")
 
 	flag.StringVar(&config.StateDir, "state-dir", "", "Directory for persistent state")
+	flag.StringVar(&config.StateBackend, "state-backend", "file", "State backend: file, sqlite")
 	flag.BoolVar(&config.Reset, "reset", false, "Clear all persisted state before starting")
+	flag.StringVar(&config.KeyPassphraseFile, "key-passphrase-file", "", "Encrypt private keys with the passphrase in this file")
+	flag.StringVar(&config.KeySigner, "key-signer", "", "Unix socket of a mash-signer holding the private keys")
 
 	flag.StringVar(&config.ProtocolLogFile, "protocol-log", "", "File path for protocol event logging (CBOR format)")
+	flag.StringVar(&config.GatewayAddr, "gateway", "", "Serve the WebSocket device gateway on this address (e.g. localhost:8090)")
+	flag.StringVar(&config.GatewayTokenFile, "gateway-token-file", "", "Require the token in this file on the gateway and allow writes and commands")
 }
 
 func main() {
@@ -219,10 +260,26 @@ func main() {
 		log.Printf("Using state directory: %s", config.StateDir)
 
 		// Create certificate store
-		certStore := cert.NewFileControllerStore(config.StateDir)
+		certStore := cert.NewFileControllerStoreWithKeyStore(config.StateDir, newKeyStore())
 
 		// Create state store
-		stateStore := persistence.NewControllerStateStore(filepath.Join(config.StateDir, "state.json"))
+		var stateStore *persistence.ControllerStateStore
+		switch config.StateBackend {
+		case "file":
+			stateStore = persistence.NewControllerStateStore(filepath.Join(config.StateDir, "state.json"))
+		case "sqlite":
+			if err := os.MkdirAll(config.StateDir, 0755); err != nil {
+				log.Fatalf("Failed to create state directory: %v", err)
+			}
+			backend, err := sqlite.NewBackend(filepath.Join(config.StateDir, "state.db"))
+			if err != nil {
+				log.Fatalf("Failed to open state database: %v", err)
+			}
+			defer backend.Close()
+			stateStore = persistence.NewControllerStateStoreWithBackend(backend)
+		default:
+			log.Fatalf("Unknown state backend %q (want file or sqlite)", config.StateBackend)
+		}
 
 		// Handle --reset flag
 		if config.Reset {
@@ -250,6 +307,31 @@ func main() {
 	// Register event handler
 	svc.OnEvent(handleEvent)
 
+	// Serve the WebSocket gateway for browser tools
+	if config.GatewayAddr != "" {
+		var gatewayConfig gateway.Config
+		if config.GatewayTokenFile != "" {
+			token, err := cert.ReadPassphraseFile(config.GatewayTokenFile)
+			if err != nil {
+				log.Fatalf("Failed to read gateway token: %v", err)
+			}
+			gatewayConfig.Token = string(token)
+		} else {
+			log.Printf("Gateway is read-only; use -gateway-token-file to allow writes and commands")
+		}
+		gw = gateway.New(gateway.FromController(svc), gatewayConfig)
+		mux := http.NewServeMux()
+		mux.Handle("/ws", gw)
+		gatewayServer := &http.Server{Addr: config.GatewayAddr, Handler: mux}
+		go func() {
+			log.Printf("WebSocket gateway listening on ws://%s/ws", config.GatewayAddr)
+			if err := gatewayServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				log.Printf("Gateway failed: %v", err)
+			}
+		}()
+		defer gatewayServer.Close()
+	}
+
 	// Start service
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -448,6 +530,9 @@ func setupDeviceMonitoring(deviceID string) {
 	// Set up notification handler to route to CEM and display updates
 	session.SetNotificationHandler(func(notif *wire.Notification) {
 		cem.HandleNotification(deviceID, notif.EndpointID, notif.FeatureID, notif.Changes)
+		if gw != nil {
+			gw.HandleNotification(deviceID, notif)
+		}
 
 		// Log power updates in real-time
 		if notif.FeatureID == uint8(model.FeatureMeasurement) {
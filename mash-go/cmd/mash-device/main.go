@@ -130,6 +130,15 @@ var (
 
 	// Device service (used by simulation and event handling)
 	deviceSvc *service.DeviceService
+
+	// Setpoint resolver for device types that accept setpoints (nil otherwise)
+	setpointResolver *features.SetpointResolver
+
+	// Per-phase current resolver for device types that accept current limits (nil otherwise)
+	phaseCurrentResolver *features.PhaseCurrentResolver
+
+	// Constraint signal scheduler for device types with Signals (nil otherwise)
+	constraintScheduler *features.ConstraintScheduler
 )
 
 func init() {
@@ -278,6 +287,25 @@ func main() {
 		}
 		svc.SetLimitResolver(limitResolver)
 	}
+	if setpointResolver != nil {
+		const endpointID uint8 = 1
+		featureID := uint8(model.FeatureEnergyControl)
+		setpointResolver.OnZoneMyChange = func(zoneID string, changes map[uint16]any) {
+			svc.NotifyZoneAttributeChange(zoneID, endpointID, featureID, changes)
+		}
+		svc.SetSetpointResolver(setpointResolver)
+	}
+	if phaseCurrentResolver != nil {
+		const endpointID uint8 = 1
+		featureID := uint8(model.FeatureEnergyControl)
+		phaseCurrentResolver.OnZoneMyChange = func(zoneID string, changes map[uint16]any) {
+			svc.NotifyZoneAttributeChange(zoneID, endpointID, featureID, changes)
+		}
+		svc.SetPhaseCurrentResolver(phaseCurrentResolver)
+	}
+	if constraintScheduler != nil {
+		svc.SetConstraintScheduler(constraintScheduler)
+	}
 
 	// Store for simulation
 	deviceSvc = svc
@@ -476,6 +504,8 @@ func createDevice() (*model.Device, *features.LimitResolver, discovery.DeviceCat
 			NominalMinPower:       1380000,
 			SupportsBidirectional: false,
 		})
+		phaseCurrentResolver = evse.PhaseCurrentResolver()
+		constraintScheduler = evse.ConstraintScheduler()
 		return evse.Device(), evse.LimitResolver(), discovery.CategoryEMobility
 
 	case DeviceTypeInverter:
@@ -595,6 +625,10 @@ func createBatteryDevice() (*model.Device, *features.LimitResolver) {
 	_ = energyControl.SetControlState(features.ControlStateAutonomous)
 	energyControl.SetCapabilities(true, false, true, false, true, true, true)
 	resolver := setupEnergyControlHandler(energyControl)
+	setpointResolver = features.NewSetpointResolver(energyControl)
+	setpointResolver.MaxConsumption = electrical.NominalMaxConsumption()
+	setpointResolver.MaxProduction = electrical.NominalMaxProduction()
+	setpointResolver.Register()
 	battery.AddFeature(energyControl.Feature)
 
 	// Status
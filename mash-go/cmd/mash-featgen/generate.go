@@ -31,6 +31,11 @@ func GenerateFeature(def *specparse.RawFeatureDef, shared *specparse.RawSharedTy
 		generateCallbackSetters(&b, def)
 	}
 
+	if len(def.Events) > 0 {
+		generateEventConstants(&b, def)
+		generateAddEvents(&b, def)
+	}
+
 	return b.String(), nil
 }
 
@@ -137,6 +142,7 @@ func generateConstructor(b *strings.Builder, def *specparse.RawFeatureDef, share
 		FeatureTypeConst: "model.Feature" + def.Name,
 		Attributes:       attrs,
 		HasCommands:      len(def.Commands) > 0,
+		HasEvents:        len(def.Events) > 0,
 	})
 }
 
@@ -339,20 +345,7 @@ func generateSetters(b *strings.Builder, def *specparse.RawFeatureDef) {
 				fmt.Fprintf(b, "if %s == nil {\n", paramName)
 				fmt.Fprintf(b, "return attr.SetValueInternal(nil)\n")
 				fmt.Fprintf(b, "}\n")
-				fmt.Fprintf(b, "data := make([]any, len(%s))\n", paramName)
-				fmt.Fprintf(b, "for i, item := range %s {\n", paramName)
-				b.WriteString("data[i] = map[string]any{\n")
-				for _, field := range attr.Items.Fields {
-					fieldTitle := goTitleCase(field.Name)
-					if field.Enum != "" {
-						fmt.Fprintf(b, "%q: %s(item.%s),\n", field.Name, goTypeName(field.Type), fieldTitle)
-					} else {
-						fmt.Fprintf(b, "%q: item.%s,\n", field.Name, fieldTitle)
-					}
-				}
-				b.WriteString("}\n")
-				b.WriteString("}\n")
-				fmt.Fprintf(b, "return attr.SetValueInternal(data)\n")
+				fmt.Fprintf(b, "return attr.SetValueInternal(Encode%ss(%s))\n", structType, paramName)
 				fmt.Fprintf(b, "}\n\n")
 			} else if attr.Items.Enum != "" {
 				enumType := attr.Items.Enum
@@ -471,6 +464,16 @@ func generateAddCommands(b *strings.Builder, def *specparse.RawFeatureDef) {
 	renderTemplate(b, "addCommands", def)
 }
 
+// --- Event generation ---
+
+func generateEventConstants(b *strings.Builder, def *specparse.RawFeatureDef) {
+	renderTemplate(b, "eventConstants", def)
+}
+
+func generateAddEvents(b *strings.Builder, def *specparse.RawFeatureDef) {
+	renderTemplate(b, "addEvents", def)
+}
+
 func generateCommandHandlers(b *strings.Builder, def *specparse.RawFeatureDef) {
 	name := def.Name
 	recv := strings.ToLower(name[:1])
@@ -580,11 +583,10 @@ func writeParamParse(b *strings.Builder, p specparse.RawParameterDef, fieldName,
 		} else {
 			fmt.Fprintf(b, "%s.%s = v\n", structVar, fieldName)
 		}
-		b.WriteString("}\n")
 	} else if p.Type == "map" {
-		fmt.Fprintf(b, "if v, ok := raw.(map[string]any); ok {\n")
+		// CBOR decodes nested maps as map[any]any; normalize the keys.
+		fmt.Fprintf(b, "if v := wire.ToStringMap(raw); v != nil {\n")
 		fmt.Fprintf(b, "%s.%s = v\n", structVar, fieldName)
-		b.WriteString("}\n")
 	} else {
 		goType := goTypeName(p.Type)
 		fmt.Fprintf(b, "if v, ok := raw.(%s); ok {\n", goType)
@@ -593,8 +595,12 @@ func writeParamParse(b *strings.Builder, p specparse.RawParameterDef, fieldName,
 		} else {
 			fmt.Fprintf(b, "%s.%s = v\n", structVar, fieldName)
 		}
-		b.WriteString("}\n")
 	}
+	// A present value of the wrong type is rejected; null means omitted.
+	b.WriteString("} else if raw != nil {\n")
+	fmt.Fprintf(b, "return nil, wire.NewFieldError(wire.StatusInvalidParameter, wire.FieldError{Path: %q, Message: %q, Constraint: wire.ConstraintType})\n",
+		p.Name, "expected "+p.Type)
+	b.WriteString("}\n")
 }
 
 func generateCallbackSetters(b *strings.Builder, def *specparse.RawFeatureDef) {
@@ -787,6 +793,26 @@ var yamlTypes = map[string]yamlTypeSpec{
 }
 
 // goTypeName converts YAML type strings to Go type names.
+// itemValue returns the wire value expression for an array item field.
+func itemValue(field specparse.RawArrayFieldDef) string {
+	if field.Enum != "" {
+		return fmt.Sprintf("%s(item.%s)", goTypeName(field.Type), goTitleCase(field.Name))
+	}
+	return "item." + goTitleCase(field.Name)
+}
+
+// zeroValue returns the Go zero value literal for a YAML type.
+func zeroValue(yamlType string) string {
+	switch goTypeName(yamlType) {
+	case "string":
+		return `""`
+	case "bool":
+		return "false"
+	default:
+		return "0"
+	}
+}
+
 func goTypeName(yamlType string) string {
 	if spec, ok := yamlTypes[yamlType]; ok {
 		return spec.GoType
@@ -0,0 +1,232 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mash-protocol/mash-go/internal/specparse"
+)
+
+// GenerateClient produces the Go source of the typed client proxy for a
+// feature in package featureclient: <Name>Client with attribute readers,
+// writers for writable attributes and one method per command, plus
+// <Name>Attributes for decoding reads and subscription reports. Feature
+// types are referenced from package features.
+func GenerateClient(def *specparse.RawFeatureDef, shared *specparse.RawSharedTypes) (string, error) {
+	var b strings.Builder
+
+	b.WriteString("// Code generated by mash-featgen. DO NOT EDIT.\n\n")
+	b.WriteString("package featureclient\n\n")
+	// goimports cannot resolve module packages reliably, so list them all
+	// and let it drop the unused ones.
+	b.WriteString("import (\n")
+	b.WriteString("\t\"context\"\n")
+	b.WriteString("\t\"fmt\"\n\n")
+	b.WriteString("\t\"github.com/mash-protocol/mash-go/pkg/features\"\n")
+	b.WriteString("\t\"github.com/mash-protocol/mash-go/pkg/interaction\"\n")
+	b.WriteString("\t\"github.com/mash-protocol/mash-go/pkg/model\"\n")
+	b.WriteString("\t\"github.com/mash-protocol/mash-go/pkg/wire\"\n")
+	b.WriteString(")\n\n")
+
+	renderTemplate(&b, "client", buildClientData(def, shared))
+
+	return b.String(), nil
+}
+
+// --- Client template data ---
+
+type clientData struct {
+	Name       string
+	Attributes []clientAttrData
+	Structs    []clientStructData
+	Commands   []clientCommandData
+}
+
+type clientAttrData struct {
+	Name        string
+	ConstName   string
+	Field       string
+	Description string
+	GoType      string // Go type of the decoded value
+	Decoder     string // expression of type func(any) (GoType, bool)
+	Collection  bool   // map or slice; null decodes to nil
+	Nullable    bool
+	Writable    bool
+	Encode      string // expression encoding the written value for the wire
+}
+
+type clientStructData struct {
+	Name     string
+	AttrName string
+	Fields   []clientFieldData
+}
+
+type clientFieldData struct {
+	Key     string
+	Field   string
+	Decoder string
+}
+
+type clientCommandData struct {
+	Method      string
+	Name        string
+	Description string
+	ConstName   string
+	HasParams   bool
+	Simple      bool
+	Params      []clientParamData
+	Response    []clientParamData
+}
+
+type clientParamData struct {
+	Key      string
+	Field    string
+	Optional bool
+	Encode   string // expression encoding the field value for the wire
+	Decoder  string
+}
+
+func buildClientData(def *specparse.RawFeatureDef, shared *specparse.RawSharedTypes) clientData {
+	enums := buildEnumLookup(def, shared)
+	data := clientData{Name: def.Name}
+
+	seenStructs := make(map[string]bool)
+	for _, attr := range def.Attributes {
+		field := goTitleCase(attr.Name)
+		a := clientAttrData{
+			Name:        attr.Name,
+			ConstName:   "features." + def.Name + "Attr" + field,
+			Field:       field,
+			Description: clientSentence(attr.Description),
+			Nullable:    attr.Nullable,
+			Writable:    attr.Access == "readWrite",
+		}
+
+		switch {
+		case attr.Type == "map" && attr.MapKeyType != "":
+			keyType, keyDec := clientTypeDecoder(attr.MapKeyType, enums)
+			valType, valDec := clientTypeDecoder(attr.MapValueType, enums)
+			a.GoType = fmt.Sprintf("map[%s]%s", keyType, valType)
+			a.Decoder = fmt.Sprintf("mapDecoder(%s, %s)", keyDec, valDec)
+			a.Collection = true
+		case attr.Type == "array" && attr.Items != nil && attr.Items.Type == "object":
+			s := attr.Items.StructName
+			a.GoType = "[]features." + s
+			a.Decoder = fmt.Sprintf("sliceDecoder(decode%s)", s)
+			a.Collection = true
+			if !seenStructs[s] {
+				seenStructs[s] = true
+				sd := clientStructData{Name: s, AttrName: attr.Name}
+				for _, f := range attr.Items.Fields {
+					_, dec := clientValueDecoder(f.Type, f.Enum, enums)
+					sd.Fields = append(sd.Fields, clientFieldData{Key: f.Name, Field: goTitleCase(f.Name), Decoder: dec})
+				}
+				data.Structs = append(data.Structs, sd)
+			}
+		case attr.Type == "array" && attr.Items != nil:
+			itemType, itemDec := clientValueDecoder(attr.Items.Type, attr.Items.Enum, enums)
+			a.GoType = "[]" + itemType
+			a.Decoder = fmt.Sprintf("sliceDecoder(%s)", itemDec)
+			a.Collection = true
+		default:
+			goType, dec := clientValueDecoder(attr.Type, attr.Enum, enums)
+			a.GoType = goType
+			a.Decoder = dec
+			a.Collection = attr.Type == "map" || attr.Type == "array" || attr.Type == "bytes"
+		}
+		a.Encode = clientEncode(attr.Type, attr.Enum, "v")
+		if a.Nullable && !a.Collection {
+			a.Encode = clientEncode(attr.Type, attr.Enum, "*v")
+		}
+		data.Attributes = append(data.Attributes, a)
+	}
+
+	for _, cmd := range def.Commands {
+		method := goTitleCase(cmd.Name)
+		c := clientCommandData{
+			Method:      method,
+			Name:        cmd.Name,
+			Description: clientSentence(cmd.Description),
+			ConstName:   "features." + def.Name + "Cmd" + method,
+			HasParams:   hasParameters(cmd),
+			Simple:      isSimpleResponse(cmd),
+		}
+		for _, p := range cmd.Parameters {
+			field := goTitleCase(p.Name)
+			value := "req." + field
+			if !p.Required {
+				value = "*" + value
+			}
+			c.Params = append(c.Params, clientParamData{
+				Key:      p.Name,
+				Field:    field,
+				Optional: !p.Required,
+				Encode:   clientEncode(p.Type, p.Enum, value),
+			})
+		}
+		if !c.Simple {
+			for _, r := range cmd.Response {
+				_, dec := clientValueDecoder(r.Type, r.Enum, enums)
+				c.Response = append(c.Response, clientParamData{
+					Key:      r.Name,
+					Field:    goTitleCase(r.Name),
+					Optional: !r.Required,
+					Decoder:  dec,
+				})
+			}
+		}
+		data.Commands = append(data.Commands, c)
+	}
+
+	return data
+}
+
+// clientValueDecoder returns the Go type and decoder expression for a value
+// of the given YAML type, or of the named enum if enum is set.
+func clientValueDecoder(yamlType, enum string, enums map[string]specparse.RawEnumDef) (string, string) {
+	if enum != "" {
+		return clientTypeDecoder(enum, enums)
+	}
+	return clientTypeDecoder(yamlType, enums)
+}
+
+// clientTypeDecoder returns the Go type and decoder expression for a YAML
+// primitive type or an enum name. Names that are neither a primitive nor a
+// known enum are treated as uint8 enums, as enum types always are.
+func clientTypeDecoder(name string, enums map[string]specparse.RawEnumDef) (string, string) {
+	base, goType := name, goTypeName(name)
+	if _, ok := yamlTypes[name]; !ok {
+		base, goType = enums[name].Type, "features."+name
+		if base == "" {
+			base = "uint8"
+		}
+	}
+
+	switch base {
+	case "uint8", "uint16", "uint32", "uint64":
+		return goType, "decodeUint[" + goType + "]"
+	case "int8", "int16", "int32", "int64":
+		return goType, "decodeInt[" + goType + "]"
+	case "float32", "float64":
+		return goType, "decodeFloat[" + goType + "]"
+	case "map":
+		return goType, "decodeStringMap"
+	default: // bool, string, bytes, untyped array
+		return goType, "decodeAs[" + goType + "]"
+	}
+}
+
+// clientEncode returns the expression that puts value on the wire. Enums are
+// sent as their underlying integer type.
+func clientEncode(yamlType, enum, value string) string {
+	if enum != "" {
+		return fmt.Sprintf("%s(%s)", goTypeName(yamlType), value)
+	}
+	return value
+}
+
+// clientSentence turns a YAML description into the tail of a doc comment
+// sentence.
+func clientSentence(desc string) string {
+	return strings.TrimSuffix(firstLower(desc), ".") + "."
+}
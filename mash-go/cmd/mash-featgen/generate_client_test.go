@@ -0,0 +1,130 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/mash-protocol/mash-go/internal/specparse"
+)
+
+func TestGenerateClient_Attributes(t *testing.T) {
+	def := statusDef()
+	output, err := GenerateClient(def, nil)
+	if err != nil {
+		t.Fatalf("GenerateClient failed: %v", err)
+	}
+
+	mustContain(t, output, "// Code generated by mash-featgen. DO NOT EDIT.")
+	mustContain(t, output, "package featureclient")
+	mustContain(t, output, "func NewStatusClient(client DeviceClient, endpointID uint8) *StatusClient {")
+	mustContain(t, output, "OperatingState *features.OperatingState")
+	mustContain(t, output, "a.OperatingState = decodePtr(raw, decodeUint[features.OperatingState])")
+	mustContain(t, output, "func (c *StatusClient) Subscribe(ctx context.Context, opts *interaction.SubscribeOptions) (uint32, *StatusAttributes, error) {")
+
+	// Non-nullable attributes return the value, nullable ones a pointer.
+	mustContain(t, output, "func (c *StatusClient) ReadOperatingState(ctx context.Context) (features.OperatingState, error) {")
+	mustContain(t, output, "func (c *StatusClient) ReadFaultMessage(ctx context.Context) (*string, error) {")
+	mustContain(t, output, "return decodeNullable(raw, decodeAs[string])")
+
+	// Read-only attributes have no writer.
+	mustNotContain(t, output, "WriteOperatingState")
+}
+
+func TestGenerateClient_Collections(t *testing.T) {
+	def := &specparse.RawFeatureDef{
+		Name:     "ChargingSession",
+		ID:       0x06,
+		Revision: 1,
+		Attributes: []specparse.RawAttributeDef{
+			{ID: 1, Name: "currentPerPhase", Type: "map", MapKeyType: "Phase", MapValueType: "int64", Access: "readOnly", Nullable: true, Description: "Current per phase"},
+			{ID: 2, Name: "supportedModes", Type: "array", Items: &specparse.RawArrayItemDef{Type: "uint8", Enum: "ChargingMode"}, Access: "readOnly", Description: "Supported modes"},
+			{ID: 3, Name: "evIdentifications", Type: "array", Access: "readOnly", Description: "EV identifiers", Items: &specparse.RawArrayItemDef{
+				Type:       "object",
+				StructName: "EVIdentification",
+				Fields: []specparse.RawArrayFieldDef{
+					{Name: "type", Type: "uint8", Enum: "EVIDType"},
+					{Name: "value", Type: "string"},
+				},
+			}},
+		},
+	}
+	output, err := GenerateClient(def, nil)
+	if err != nil {
+		t.Fatalf("GenerateClient failed: %v", err)
+	}
+
+	mustContain(t, output, "CurrentPerPhase map[features.Phase]int64")
+	mustContain(t, output, "a.CurrentPerPhase, _ = mapDecoder(decodeUint[features.Phase], decodeInt[int64])(raw)")
+	mustContain(t, output, "func (c *ChargingSessionClient) ReadSupportedModes(ctx context.Context) ([]features.ChargingMode, error) {")
+	mustContain(t, output, "sliceDecoder(decodeUint[features.ChargingMode])")
+	mustContain(t, output, "func decodeEVIdentification(raw any) (features.EVIdentification, bool) {")
+	mustContain(t, output, `decodeField(m, "type", decodeUint[features.EVIDType], &item.Type)`)
+}
+
+func TestGenerateClient_Writers(t *testing.T) {
+	def := &specparse.RawFeatureDef{
+		Name:     "EnergyControl",
+		ID:       0x05,
+		Revision: 1,
+		Attributes: []specparse.RawAttributeDef{
+			{ID: 1, Name: "optOutState", Type: "uint8", Enum: "OptOutState", Access: "readWrite", Description: "Opt-out state"},
+			{ID: 2, Name: "myConsumptionLimit", Type: "int64", Access: "readWrite", Nullable: true, Description: "This zone's consumption limit"},
+		},
+	}
+	output, err := GenerateClient(def, nil)
+	if err != nil {
+		t.Fatalf("GenerateClient failed: %v", err)
+	}
+
+	mustContain(t, output, "func (c *EnergyControlClient) WriteOptOutState(ctx context.Context, v features.OptOutState) error {")
+	mustContain(t, output, "features.EnergyControlAttrOptOutState: uint8(v)}")
+	mustContain(t, output, "func (c *EnergyControlClient) WriteMyConsumptionLimit(ctx context.Context, v *int64) error {")
+	mustContain(t, output, "value = *v")
+}
+
+func TestGenerateClient_Commands(t *testing.T) {
+	def := &specparse.RawFeatureDef{
+		Name:     "EnergyControl",
+		ID:       0x05,
+		Revision: 1,
+		Commands: []specparse.RawCommandDef{
+			{
+				ID:          1,
+				Name:        "setLimit",
+				Description: "Set power limits.",
+				Parameters: []specparse.RawParameterDef{
+					{Name: "consumptionLimit", Type: "int64", Required: false},
+					{Name: "cause", Type: "uint8", Enum: "LimitCause", Required: true},
+				},
+				Response: []specparse.RawParameterDef{
+					{Name: "applied", Type: "bool", Required: true},
+					{Name: "rejectReason", Type: "uint8", Enum: "LimitRejectReason", Required: false},
+				},
+			},
+			{
+				ID:   2,
+				Name: "pause",
+				Response: []specparse.RawParameterDef{
+					{Name: "success", Type: "bool", Required: true},
+				},
+			},
+		},
+	}
+	output, err := GenerateClient(def, nil)
+	if err != nil {
+		t.Fatalf("GenerateClient failed: %v", err)
+	}
+
+	mustContain(t, output, "// SetLimit invokes the setLimit command: set power limits.\n")
+	mustContain(t, output, "func (c *EnergyControlClient) SetLimit(ctx context.Context, req features.SetLimitRequest) (features.SetLimitResponse, error) {")
+	mustContain(t, output, "params[\"consumptionLimit\"] = *req.ConsumptionLimit")
+	mustContain(t, output, "params[\"cause\"] = uint8(req.Cause)")
+	mustContain(t, output, `if !decodeField(m, "applied", decodeAs[bool], &resp.Applied) {`)
+	mustContain(t, output, `resp.RejectReason = decodePtr(m["rejectReason"], decodeUint[features.LimitRejectReason])`)
+
+	// Simple responses only report failure.
+	mustContain(t, output, "func (c *EnergyControlClient) Pause(ctx context.Context) error {")
+	mustContain(t, output, "return checkSuccess(m)")
+
+	// Features without attributes get no attribute set.
+	mustNotContain(t, output, "EnergyControlAttributes")
+}
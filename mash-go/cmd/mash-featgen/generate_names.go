@@ -51,6 +51,7 @@ func GenerateNameTables(
 	b.WriteString("\t\t\"featureMap\":    model.AttrIDFeatureMap,\n")
 	b.WriteString("\t\t\"attributeList\": model.AttrIDAttributeList,\n")
 	b.WriteString("\t\t\"commandList\":   model.AttrIDCommandList,\n")
+	b.WriteString("\t\t\"dataVersion\":   model.AttrIDDataVersion,\n")
 	b.WriteString("\t}\n\n")
 
 	// Per-feature attribute tables
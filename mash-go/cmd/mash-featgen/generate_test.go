@@ -281,6 +281,24 @@ func TestGenerateCommandConstants(t *testing.T) {
 	mustContain(t, output, "EnergyControlCmdClearLimit uint8 = 2")
 }
 
+func TestGenerateEvents(t *testing.T) {
+	def := statusDef()
+	def.Events = []specparse.RawEventDef{
+		{ID: 1, Name: "faultRaised", Description: "Fault raised"},
+		{ID: 2, Name: "faultCleared", Description: "Fault cleared"},
+	}
+	output, err := GenerateFeature(def, nil)
+	if err != nil {
+		t.Fatalf("GenerateFeature failed: %v", err)
+	}
+
+	mustContain(t, output, "StatusEventFaultRaised uint8 = 1")
+	mustContain(t, output, "StatusEventFaultCleared uint8 = 2")
+	mustContain(t, output, "s.addEvents()")
+	mustContain(t, output, "func (s *Status) addEvents()")
+	mustContain(t, output, "s.AddEvent(&model.EventMetadata{")
+}
+
 func TestGenerateCommandStructs(t *testing.T) {
 	def := &specparse.RawFeatureDef{
 		Name:     "EnergyControl",
@@ -740,6 +758,9 @@ func TestGenerateHandler_SimpleWithParams(t *testing.T) {
 	mustContain(t, output, `if e.onPause == nil {`)
 	// Request parsing
 	mustContain(t, output, `req := PauseRequest{}`)
+	// Wrong-typed values are rejected with a field error
+	mustContain(t, output, `} else if raw != nil {`)
+	mustContain(t, output, `wire.FieldError{Path: "duration", Message: "expected uint32", Constraint: wire.ConstraintType}`)
 	// Call handler
 	mustContain(t, output, `err := e.onPause(ctx, req)`)
 	// Return
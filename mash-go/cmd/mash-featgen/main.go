@@ -19,22 +19,23 @@ func main() {
 	outputDir := flag.String("output", "", "Output directory for generated Go files")
 	modelOutput := flag.String("model-output", "", "Output directory for generated model type files")
 	inspectOutput := flag.String("inspect-output", "", "Output directory for generated inspect name tables")
+	clientOutput := flag.String("client-output", "", "Output directory for generated feature clients")
 	specOutput := flag.String("spec-output", "", "Output path for derived spec manifest")
 	flag.Parse()
 
 	if *featuresDir == "" || *sharedPath == "" || *outputDir == "" {
-		fmt.Fprintln(os.Stderr, "Usage: mash-featgen -features <dir> -shared <path> -output <dir> [-protocol <path>] [-version <ver>] [-model-output <dir>] [-spec-output <path>]")
+		fmt.Fprintln(os.Stderr, "Usage: mash-featgen -features <dir> -shared <path> -output <dir> [-protocol <path>] [-version <ver>] [-model-output <dir>] [-client-output <dir>] [-spec-output <path>]")
 		flag.PrintDefaults()
 		os.Exit(1)
 	}
 
-	if err := run(*featuresDir, *sharedPath, *protocolPath, *version, *outputDir, *modelOutput, *inspectOutput, *specOutput); err != nil {
+	if err := run(*featuresDir, *sharedPath, *protocolPath, *version, *outputDir, *modelOutput, *inspectOutput, *clientOutput, *specOutput); err != nil {
 		fmt.Fprintf(os.Stderr, "error: %v\n", err)
 		os.Exit(1)
 	}
 }
 
-func run(featuresDir, sharedPath, protocolPath, version, outputDir, modelOutput, inspectOutput, specOutput string) error {
+func run(featuresDir, sharedPath, protocolPath, version, outputDir, modelOutput, inspectOutput, clientOutput, specOutput string) error {
 	// Load shared types
 	shared, err := specparse.LoadSharedTypes(sharedPath)
 	if err != nil {
@@ -113,6 +114,12 @@ func run(featuresDir, sharedPath, protocolPath, version, outputDir, modelOutput,
 	// every `make generate`).
 	var allDefs []*specparse.RawFeatureDef
 
+	if clientOutput != "" {
+		if err := os.MkdirAll(clientOutput, 0o755); err != nil {
+			return fmt.Errorf("creating client output dir: %w", err)
+		}
+	}
+
 	for _, ft := range ver.FeatureTypes {
 		featureName := ft.Name
 		featureVer, ok := featureVersions[featureName]
@@ -143,6 +150,20 @@ func run(featuresDir, sharedPath, protocolPath, version, outputDir, modelOutput,
 			return fmt.Errorf("writing %s: %w", outFileName, err)
 		}
 		fmt.Printf("  generated %s\n", outPath)
+
+		// Generate the typed client if client output directory is specified
+		if clientOutput != "" {
+			clientCode, err := GenerateClient(def, shared)
+			if err != nil {
+				return fmt.Errorf("generating client %s: %w", featureName, err)
+			}
+
+			clientPath := filepath.Join(clientOutput, outFileName)
+			if err := writeFormatted(clientPath, clientCode); err != nil {
+				return fmt.Errorf("writing client %s: %w", outFileName, err)
+			}
+			fmt.Printf("  generated %s\n", clientPath)
+		}
 	}
 
 	// Validate feature IDs against registry
@@ -173,7 +194,7 @@ func run(featuresDir, sharedPath, protocolPath, version, outputDir, modelOutput,
 		if specDescription == "" {
 			specDescription = fmt.Sprintf("MASH Protocol Specification v%s", version)
 		}
-		manifest, err := DeriveSpecManifest(allDefs, version, specDescription)
+		manifest, err := DeriveSpecManifest(allDefs, shared, version, specDescription)
 		if err != nil {
 			return fmt.Errorf("deriving spec manifest: %w", err)
 		}
@@ -10,7 +10,8 @@ import (
 
 // DeriveSpecManifest produces the spec manifest YAML from parsed feature definitions.
 // Features are sorted by ID. The output matches the structure of pkg/version/specs/1.0.yaml.
-func DeriveSpecManifest(features []*specparse.RawFeatureDef, version, description string) (string, error) {
+// Shared enums (may be nil) resolve enum references in the payload schema.
+func DeriveSpecManifest(features []*specparse.RawFeatureDef, shared *specparse.RawSharedTypes, version, description string) (string, error) {
 	// Sort features by ID
 	sorted := make([]*specparse.RawFeatureDef, len(features))
 	copy(sorted, features)
@@ -25,7 +26,7 @@ func DeriveSpecManifest(features []*specparse.RawFeatureDef, version, descriptio
 	b.WriteString("\nfeatures:\n")
 
 	for _, def := range sorted {
-		writeFeatureSpec(&b, def)
+		writeFeatureSpec(&b, def, shared)
 	}
 
 	return b.String(), nil
@@ -38,7 +39,7 @@ type specItem struct {
 	Mandatory bool
 }
 
-func writeFeatureSpec(b *strings.Builder, def *specparse.RawFeatureDef) {
+func writeFeatureSpec(b *strings.Builder, def *specparse.RawFeatureDef, shared *specparse.RawSharedTypes) {
 	fmt.Fprintf(b, "  %s:\n", def.Name)
 	fmt.Fprintf(b, "    id: 0x%02X\n", def.ID)
 	fmt.Fprintf(b, "    revision: %d\n", def.Revision)
@@ -56,6 +57,8 @@ func writeFeatureSpec(b *strings.Builder, def *specparse.RawFeatureDef) {
 	}
 	writeSpecItems(b, "commands", cmds)
 
+	writeFeatureSchema(b, def, buildEnumLookup(def, shared))
+
 	b.WriteString("\n")
 }
 
@@ -101,3 +104,188 @@ func formatCmdID(id uint8) string {
 	}
 	return fmt.Sprintf("%d", id)
 }
+
+// writeFeatureSchema emits the "schema:" block describing the payloads a
+// feature accepts: writable attributes by ID and command parameters by name.
+// pkg/version compiles it into a Validator. Skips the block when the feature
+// accepts neither.
+func writeFeatureSchema(b *strings.Builder, def *specparse.RawFeatureDef, enums map[string]specparse.RawEnumDef) {
+	structs := make(map[string][]specparse.RawArrayFieldDef)
+	for _, a := range def.Attributes {
+		if a.Items != nil && a.Items.StructName != "" {
+			structs[a.Items.StructName] = a.Items.Fields
+		}
+	}
+
+	var attrs []string
+	for _, a := range def.Attributes {
+		if a.Access != "readWrite" {
+			continue
+		}
+		t := attrSchemaType(a, enums, structs)
+		attrs = append(attrs, fmt.Sprintf("        %d: %s\n", a.ID, t))
+	}
+
+	var cmds []string
+	for _, c := range def.Commands {
+		if len(c.Parameters) == 0 {
+			continue
+		}
+		var rows strings.Builder
+		fmt.Fprintf(&rows, "        %s:\n", formatCmdID(c.ID))
+		for _, p := range c.Parameters {
+			t := paramSchemaType(p, enums, structs)
+			fmt.Fprintf(&rows, "          %s: %s\n", p.Name, t)
+		}
+		cmds = append(cmds, rows.String())
+	}
+
+	if len(attrs) == 0 && len(cmds) == 0 {
+		return
+	}
+	b.WriteString("    schema:\n")
+	if len(attrs) > 0 {
+		b.WriteString("      attributes:\n")
+		for _, row := range attrs {
+			b.WriteString(row)
+		}
+	}
+	if len(cmds) > 0 {
+		b.WriteString("      commands:\n")
+		for _, rows := range cmds {
+			b.WriteString(rows)
+		}
+	}
+}
+
+// schemaType is a value type in the manifest schema. Enum references are
+// resolved to their values so the manifest is self-contained.
+type schemaType struct {
+	Type     string
+	Enum     []int
+	Nullable bool
+	Required bool
+	Min, Max any
+	MaxItems int
+	Key      *schemaType
+	Value    *schemaType
+	Items    *schemaType
+	Fields   []schemaField
+}
+
+type schemaField struct {
+	Name string
+	Type *schemaType
+}
+
+func attrSchemaType(a specparse.RawAttributeDef, enums map[string]specparse.RawEnumDef, structs map[string][]specparse.RawArrayFieldDef) *schemaType {
+	t := baseSchemaType(a.Type, a.Enum, enums)
+	t.Nullable = a.Nullable
+	t.Min, t.Max = a.Min, a.Max
+	t.MaxItems = a.MaxItems
+	if a.MapKeyType != "" {
+		t.Key = namedSchemaType(a.MapKeyType, enums)
+	}
+	if a.MapValueType != "" {
+		t.Value = namedSchemaType(a.MapValueType, enums)
+	}
+	if a.Items != nil {
+		t.Items = itemSchemaType(a.Items, enums, structs)
+	}
+	return t
+}
+
+func paramSchemaType(p specparse.RawParameterDef, enums map[string]specparse.RawEnumDef, structs map[string][]specparse.RawArrayFieldDef) *schemaType {
+	t := baseSchemaType(p.Type, p.Enum, enums)
+	t.Nullable = p.Nullable
+	t.Required = p.Required
+	t.MaxItems = p.MaxItems
+	if p.Items != nil {
+		t.Items = itemSchemaType(p.Items, enums, structs)
+	}
+	return t
+}
+
+// itemSchemaType describes array items. Object items without fields of
+// their own take the fields of the attribute struct they name.
+func itemSchemaType(items *specparse.RawArrayItemDef, enums map[string]specparse.RawEnumDef, structs map[string][]specparse.RawArrayFieldDef) *schemaType {
+	t := baseSchemaType(items.Type, items.Enum, enums)
+	fields := items.Fields
+	if len(fields) == 0 && items.StructName != "" {
+		fields = structs[items.StructName]
+	}
+	for _, f := range fields {
+		ft := baseSchemaType(f.Type, f.Enum, enums)
+		ft.Nullable = f.Nullable
+		t.Fields = append(t.Fields, schemaField{Name: f.Name, Type: ft})
+	}
+	return t
+}
+
+func baseSchemaType(typ, enum string, enums map[string]specparse.RawEnumDef) *schemaType {
+	t := &schemaType{Type: typ}
+	if e, ok := enums[enum]; ok {
+		for _, v := range e.Values {
+			t.Enum = append(t.Enum, v.Value)
+		}
+	}
+	return t
+}
+
+// namedSchemaType resolves a map key or value type, which is either a
+// primitive type or an enum name.
+func namedSchemaType(name string, enums map[string]specparse.RawEnumDef) *schemaType {
+	e, ok := enums[name]
+	if !ok {
+		return &schemaType{Type: name}
+	}
+	typ := e.Type
+	if typ == "" {
+		typ = "uint8"
+	}
+	return baseSchemaType(typ, name, enums)
+}
+
+// String renders the type as a YAML flow mapping.
+func (t *schemaType) String() string {
+	parts := []string{"type: " + t.Type}
+	if len(t.Enum) > 0 {
+		values := make([]string, len(t.Enum))
+		for i, v := range t.Enum {
+			values[i] = fmt.Sprintf("%d", v)
+		}
+		parts = append(parts, "enum: ["+strings.Join(values, ", ")+"]")
+	}
+	if t.Nullable {
+		parts = append(parts, "nullable: true")
+	}
+	if t.Required {
+		parts = append(parts, "required: true")
+	}
+	if t.Min != nil {
+		parts = append(parts, fmt.Sprintf("min: %v", t.Min))
+	}
+	if t.Max != nil {
+		parts = append(parts, fmt.Sprintf("max: %v", t.Max))
+	}
+	if t.MaxItems > 0 {
+		parts = append(parts, fmt.Sprintf("maxItems: %d", t.MaxItems))
+	}
+	if t.Key != nil {
+		parts = append(parts, "key: "+t.Key.String())
+	}
+	if t.Value != nil {
+		parts = append(parts, "value: "+t.Value.String())
+	}
+	if t.Items != nil {
+		parts = append(parts, "items: "+t.Items.String())
+	}
+	if len(t.Fields) > 0 {
+		fields := make([]string, len(t.Fields))
+		for i, f := range t.Fields {
+			fields[i] = f.Name + ": " + f.Type.String()
+		}
+		parts = append(parts, "fields: { "+strings.Join(fields, ", ")+" }")
+	}
+	return "{ " + strings.Join(parts, ", ") + " }"
+}
@@ -10,7 +10,7 @@ import (
 func TestDeriveSpecManifest_StatusFeature(t *testing.T) {
 	features := []*specparse.RawFeatureDef{statusDef()}
 
-	output, err := DeriveSpecManifest(features, "1.0", "MASH Protocol Specification v1.0 -- Initial release")
+	output, err := DeriveSpecManifest(features, nil, "1.0", "MASH Protocol Specification v1.0 -- Initial release")
 	if err != nil {
 		t.Fatalf("DeriveSpecManifest failed: %v", err)
 	}
@@ -45,7 +45,7 @@ func TestDeriveSpecManifest_MandatoryFeature(t *testing.T) {
 		},
 	}
 
-	output, err := DeriveSpecManifest([]*specparse.RawFeatureDef{def}, "1.0", "test")
+	output, err := DeriveSpecManifest([]*specparse.RawFeatureDef{def}, nil, "1.0", "test")
 	if err != nil {
 		t.Fatalf("DeriveSpecManifest failed: %v", err)
 	}
@@ -71,7 +71,7 @@ func TestDeriveSpecManifest_WithCommands(t *testing.T) {
 		},
 	}
 
-	output, err := DeriveSpecManifest([]*specparse.RawFeatureDef{def}, "1.0", "test")
+	output, err := DeriveSpecManifest([]*specparse.RawFeatureDef{def}, nil, "1.0", "test")
 	if err != nil {
 		t.Fatalf("DeriveSpecManifest failed: %v", err)
 	}
@@ -94,7 +94,7 @@ func TestDeriveSpecManifest_NoOptionalAttributes(t *testing.T) {
 		},
 	}
 
-	output, err := DeriveSpecManifest([]*specparse.RawFeatureDef{def}, "1.0", "test")
+	output, err := DeriveSpecManifest([]*specparse.RawFeatureDef{def}, nil, "1.0", "test")
 	if err != nil {
 		t.Fatalf("DeriveSpecManifest failed: %v", err)
 	}
@@ -109,7 +109,7 @@ func TestDeriveSpecManifest_NoOptionalAttributes(t *testing.T) {
 func TestDeriveSpecManifest_NoCommands(t *testing.T) {
 	def := statusDef()
 
-	output, err := DeriveSpecManifest([]*specparse.RawFeatureDef{def}, "1.0", "test")
+	output, err := DeriveSpecManifest([]*specparse.RawFeatureDef{def}, nil, "1.0", "test")
 	if err != nil {
 		t.Fatalf("DeriveSpecManifest failed: %v", err)
 	}
@@ -126,7 +126,7 @@ func TestDeriveSpecManifest_FeatureOrder(t *testing.T) {
 		{Name: "DeviceInfo", ID: 0x01, Revision: 1, Mandatory: true, Attributes: []specparse.RawAttributeDef{{ID: 1, Name: "id", Mandatory: true}}},
 	}
 
-	output, err := DeriveSpecManifest(features, "1.0", "test")
+	output, err := DeriveSpecManifest(features, nil, "1.0", "test")
 	if err != nil {
 		t.Fatalf("DeriveSpecManifest failed: %v", err)
 	}
@@ -156,7 +156,7 @@ func TestDeriveSpecManifest_HexIDs(t *testing.T) {
 		},
 	}
 
-	output, err := DeriveSpecManifest([]*specparse.RawFeatureDef{def}, "1.0", "test")
+	output, err := DeriveSpecManifest([]*specparse.RawFeatureDef{def}, nil, "1.0", "test")
 	if err != nil {
 		t.Fatalf("DeriveSpecManifest failed: %v", err)
 	}
@@ -166,3 +166,51 @@ func TestDeriveSpecManifest_HexIDs(t *testing.T) {
 	// Command ID 0x10 = 16, but should be formatted as hex in the spec
 	mustContain(t, output, "id: 0x10")
 }
+
+func TestDeriveSpecManifest_Schema(t *testing.T) {
+	def := &specparse.RawFeatureDef{
+		Name:     "Signals",
+		ID:       0x08,
+		Revision: 1,
+		Enums: []specparse.RawEnumDef{
+			{Name: "SignalSource", Type: "uint8", Values: []specparse.RawEnumValue{{Name: "GRID", Value: 0}, {Name: "LOCAL", Value: 1}}},
+		},
+		Attributes: []specparse.RawAttributeDef{
+			{ID: 1, Name: "activeSource", Type: "uint8", Access: "readOnly"},
+			{ID: 2, Name: "threshold", Type: "uint32", Access: "readWrite", Nullable: true, Min: 10, Max: 500},
+			{ID: 3, Name: "perPhase", Type: "map", MapKeyType: "Phase", MapValueType: "int64", Access: "readWrite"},
+			{ID: 10, Name: "priceSlots", Type: "array", MaxItems: 96, Access: "readOnly", Items: &specparse.RawArrayItemDef{
+				Type: "object", StructName: "PriceSlot",
+				Fields: []specparse.RawArrayFieldDef{
+					{Name: "duration", Type: "uint32"},
+					{Name: "priceLevel", Type: "uint8", Nullable: true},
+				},
+			}},
+		},
+		Commands: []specparse.RawCommandDef{
+			{ID: 1, Name: "sendPriceSignal", Parameters: []specparse.RawParameterDef{
+				{Name: "source", Type: "uint8", Enum: "SignalSource", Required: true},
+				{Name: "slots", Type: "array", MaxItems: 96, Required: true, Items: &specparse.RawArrayItemDef{Type: "object", StructName: "PriceSlot"}},
+			}},
+			{ID: 4, Name: "clearSignals"},
+		},
+	}
+	shared := &specparse.RawSharedTypes{Enums: []specparse.RawEnumDef{
+		{Name: "Phase", Type: "uint8", Values: []specparse.RawEnumValue{{Name: "A", Value: 0}, {Name: "B", Value: 1}}},
+	}}
+
+	output, err := DeriveSpecManifest([]*specparse.RawFeatureDef{def}, shared, "1.0", "test")
+	if err != nil {
+		t.Fatalf("DeriveSpecManifest failed: %v", err)
+	}
+
+	// Only writable attributes are described.
+	mustNotContain(t, output, "        1: { type: uint8 }")
+	mustContain(t, output, "        2: { type: uint32, nullable: true, min: 10, max: 500 }")
+	mustContain(t, output, "        3: { type: map, key: { type: uint8, enum: [0, 1] }, value: { type: int64 } }")
+
+	// Enum references resolve to values; struct names resolve to fields.
+	mustContain(t, output, "          source: { type: uint8, enum: [0, 1], required: true }")
+	mustContain(t, output, "          slots: { type: array, required: true, maxItems: 96, items: { type: object, fields: { duration: { type: uint32 }, priceLevel: { type: uint8, nullable: true } } } }")
+	mustNotContain(t, output, "        4:")
+}
@@ -25,6 +25,8 @@ var funcMap = template.FuncMap{
 	"hexByte":            func(v int) string { return fmt.Sprintf("0x%02X", v) },
 	"quote":              func(s string) string { return fmt.Sprintf("%q", s) },
 	"recv":               func(name string) string { return strings.ToLower(name[:1]) },
+	"itemValue":          itemValue,
+	"zeroValue":          zeroValue,
 }
 
 // templates holds all parsed code generation templates.
@@ -38,8 +40,11 @@ var templates = template.Must(template.New("").Funcs(funcMap).Parse(
 		callbackSettersTmpl +
 		constructorTmpl +
 		addCommandsTmpl +
+		eventConstantsTmpl +
+		addEventsTmpl +
 		featureTypesTmpl +
-		endpointTypesTmpl,
+		endpointTypesTmpl +
+		clientTmpl,
 ))
 
 // renderTemplate executes a named template into the builder.
@@ -57,6 +62,7 @@ type constructorData struct {
 	FeatureTypeConst string
 	Attributes       []constructorAttrData
 	HasCommands      bool
+	HasEvents        bool
 }
 
 type constructorAttrData struct {
@@ -156,6 +162,30 @@ type {{.Items.StructName}} struct {
 {{- end}}
 }
 
+// Encode{{.Items.StructName}}s encodes {{.Name}} items for the wire. Nullable
+// fields are omitted when zero.
+func Encode{{.Items.StructName}}s(items []{{.Items.StructName}}) []any {
+data := make([]any, len(items))
+for i, item := range items {
+m := map[string]any{
+{{- range .Items.Fields}}
+{{- if not .Nullable}}
+{{quote .Name}}: {{itemValue .}},
+{{- end}}
+{{- end}}
+}
+{{- range .Items.Fields}}
+{{- if .Nullable}}
+if item.{{goTitleCase .Name}} != {{zeroValue .Type}} {
+m[{{quote .Name}}] = {{itemValue .}}
+}
+{{- end}}
+{{- end}}
+data[i] = m
+}
+return data
+}
+
 {{end}}
 {{- end}}
 {{- end}}
@@ -240,9 +270,14 @@ Description: {{quote .Description}},
 }))
 
 {{end}}
-{{- if .HasCommands}}
+{{- if or .HasCommands .HasEvents}}
 {{$recv}} := &{{$name}}{Feature: f}
+{{- if .HasCommands}}
 {{$recv}}.addCommands()
+{{- end}}
+{{- if .HasEvents}}
+{{$recv}}.addEvents()
+{{- end}}
 
 return {{$recv}}
 {{- else}}
@@ -276,6 +311,33 @@ Parameters: []model.ParameterMetadata{
 
 {{end}}`
 
+const eventConstantsTmpl = `{{define "eventConstants"}}
+// {{.Name}} event IDs.
+const (
+{{- range .Events}}
+{{concat $.Name (concat "Event" (goTitleCase .Name))}} uint8 = {{.ID}}
+{{- end}}
+)
+
+{{end}}`
+
+const addEventsTmpl = `{{define "addEvents"}}
+{{- $name := .Name}}
+{{- $recv := recv .Name}}
+// addEvents registers the {{$name}} events.
+func ({{$recv}} *{{$name}}) addEvents() {
+{{range .Events -}}
+{{$recv}}.AddEvent(&model.EventMetadata{
+ID: {{concat $name (concat "Event" (goTitleCase .Name))}},
+Name: {{quote .Name}},
+Description: {{quote .Description}},
+})
+
+{{end -}}
+}
+
+{{end}}`
+
 // --- Model type templates ---
 
 // modelTypeData holds data for model type generation templates.
@@ -359,3 +421,203 @@ return "UNKNOWN"
 {{- end}}
 
 {{end}}`
+
+// --- Client templates ---
+
+const clientTmpl = `{{define "client"}}
+{{- $name := .Name}}
+{{- $client := concat .Name "Client"}}
+{{- $attrs := concat .Name "Attributes"}}
+// {{$client}} is a typed client for the {{$name}} feature of a remote device.
+type {{$client}} struct {
+client     DeviceClient
+endpointID uint8
+}
+
+// New{{$client}} returns a client for the {{$name}} feature on the given
+// endpoint of the device behind client.
+func New{{$client}}(client DeviceClient, endpointID uint8) *{{$client}} {
+return &{{$client}}{client: client, endpointID: endpointID}
+}
+
+// EndpointID returns the endpoint the client addresses.
+func (c *{{$client}}) EndpointID() uint8 {
+return c.endpointID
+}
+{{- if .Attributes}}
+
+// {{$attrs}} holds decoded {{$name}} attribute values. Attributes that were
+// not reported, or were reported as null, are nil.
+type {{$attrs}} struct {
+{{- range .Attributes}}
+{{- if .Collection}}
+{{.Field}} {{.GoType}}
+{{- else}}
+{{.Field}} *{{.GoType}}
+{{- end}}
+{{- end}}
+}
+
+// Decode{{$attrs}} decodes attribute values as returned by Read and
+// Subscribe and carried in subscription notifications.
+func Decode{{$attrs}}(values map[uint16]any) *{{$attrs}} {
+a := &{{$attrs}}{}
+a.Update(values)
+return a
+}
+
+// Update applies attribute values, e.g. from a subscription notification.
+// Attributes not present in values are left unchanged; values that cannot be
+// decoded clear the attribute.
+func (a *{{$attrs}}) Update(values map[uint16]any) {
+for id, raw := range values {
+switch id {
+{{- range .Attributes}}
+case {{.ConstName}}:
+{{- if .Collection}}
+a.{{.Field}}, _ = {{.Decoder}}(raw)
+{{- else}}
+a.{{.Field}} = decodePtr(raw, {{.Decoder}})
+{{- end}}
+{{- end}}
+}
+}
+}
+
+// Read reads the given attributes, or all attributes if none are given.
+func (c *{{$client}}) Read(ctx context.Context, attrIDs ...uint16) (*{{$attrs}}, error) {
+values, err := c.client.Read(ctx, c.endpointID, uint8(model.Feature{{$name}}), attrIDs)
+if err != nil {
+return nil, err
+}
+return Decode{{$attrs}}(values), nil
+}
+
+// Subscribe subscribes to {{$name}} attribute changes. It returns the
+// subscription ID and the decoded priming report; decode the subsequent
+// notifications with {{$attrs}}.Update.
+func (c *{{$client}}) Subscribe(ctx context.Context, opts *interaction.SubscribeOptions) (uint32, *{{$attrs}}, error) {
+subID, values, err := c.client.Subscribe(ctx, c.endpointID, uint8(model.Feature{{$name}}), opts)
+if err != nil {
+return 0, nil, err
+}
+return subID, Decode{{$attrs}}(values), nil
+}
+{{- end}}
+{{- range .Attributes}}
+
+// Read{{.Field}} reads the {{.Name}} attribute: {{.Description}}
+{{- if .Collection}}
+func (c *{{$client}}) Read{{.Field}}(ctx context.Context) ({{.GoType}}, error) {
+raw, err := readAttribute(ctx, c.client, c.endpointID, uint8(model.Feature{{$name}}), {{.ConstName}})
+if err != nil {
+return nil, err
+}
+return decodeCollection(raw, {{.Decoder}})
+}
+{{- else if .Nullable}}
+func (c *{{$client}}) Read{{.Field}}(ctx context.Context) (*{{.GoType}}, error) {
+raw, err := readAttribute(ctx, c.client, c.endpointID, uint8(model.Feature{{$name}}), {{.ConstName}})
+if err != nil {
+return nil, err
+}
+return decodeNullable(raw, {{.Decoder}})
+}
+{{- else}}
+func (c *{{$client}}) Read{{.Field}}(ctx context.Context) ({{.GoType}}, error) {
+raw, err := readAttribute(ctx, c.client, c.endpointID, uint8(model.Feature{{$name}}), {{.ConstName}})
+if err != nil {
+var zero {{.GoType}}
+return zero, err
+}
+return decodeValue(raw, {{.Decoder}})
+}
+{{- end}}
+{{- if .Writable}}
+
+// Write{{.Field}} writes the {{.Name}} attribute: {{.Description}}
+{{- if and .Nullable (not .Collection)}}
+// A nil value clears it.
+func (c *{{$client}}) Write{{.Field}}(ctx context.Context, v *{{.GoType}}) error {
+var value any
+if v != nil {
+value = {{.Encode}}
+}
+_, err := c.client.Write(ctx, c.endpointID, uint8(model.Feature{{$name}}), map[uint16]any{ {{.ConstName}}: value})
+return err
+}
+{{- else}}
+func (c *{{$client}}) Write{{.Field}}(ctx context.Context, v {{.GoType}}) error {
+_, err := c.client.Write(ctx, c.endpointID, uint8(model.Feature{{$name}}), map[uint16]any{ {{.ConstName}}: {{.Encode}}})
+return err
+}
+{{- end}}
+{{- end}}
+{{- end}}
+{{- range .Commands}}
+
+// {{.Method}} invokes the {{.Name}} command: {{.Description}}
+{{- if .HasParams}}
+{{- if .Simple}}
+func (c *{{$client}}) {{.Method}}(ctx context.Context, req features.{{.Method}}Request) error {
+{{- else}}
+func (c *{{$client}}) {{.Method}}(ctx context.Context, req features.{{.Method}}Request) (features.{{.Method}}Response, error) {
+{{- end}}
+params := make(map[string]any, {{len .Params}})
+{{- range .Params}}
+{{- if .Optional}}
+if req.{{.Field}} != nil {
+params[{{quote .Key}}] = {{.Encode}}
+}
+{{- else}}
+params[{{quote .Key}}] = {{.Encode}}
+{{- end}}
+{{- end}}
+{{- else}}
+{{- if .Simple}}
+func (c *{{$client}}) {{.Method}}(ctx context.Context) error {
+{{- else}}
+func (c *{{$client}}) {{.Method}}(ctx context.Context) (features.{{.Method}}Response, error) {
+{{- end}}
+var params map[string]any
+{{- end}}
+m, err := invokeCommand(ctx, c.client, c.endpointID, uint8(model.Feature{{$name}}), {{.ConstName}}, params)
+{{- if .Simple}}
+if err != nil {
+return err
+}
+return checkSuccess(m)
+}
+{{- else}}
+if err != nil {
+return features.{{.Method}}Response{}, err
+}
+var resp features.{{.Method}}Response
+{{- range .Response}}
+{{- if .Optional}}
+resp.{{.Field}} = decodePtr(m[{{quote .Key}}], {{.Decoder}})
+{{- else}}
+if !decodeField(m, {{quote .Key}}, {{.Decoder}}, &resp.{{.Field}}) {
+return resp, fmt.Errorf("%w: {{.Key}}", ErrUnexpectedValue)
+}
+{{- end}}
+{{- end}}
+return resp, nil
+}
+{{- end}}
+{{- end}}
+{{- range .Structs}}
+
+// decode{{.Name}} decodes an item of the {{.AttrName}} array.
+func decode{{.Name}}(raw any) (features.{{.Name}}, bool) {
+var item features.{{.Name}}
+m := wire.ToStringMap(raw)
+if m == nil {
+return item, false
+}
+ok := {{range $i, $f := .Fields}}{{if $i}} &&
+{{end}}decodeField(m, {{quote $f.Key}}, {{$f.Decoder}}, &item.{{$f.Field}}){{end}}
+return item, ok
+}
+{{- end}}
+{{end}}`
@@ -0,0 +1,81 @@
+// Command mash-signer holds MASH private keys in a separate process and
+// signs on behalf of controllers over a Unix socket.
+//
+// Keys are stored as PEM files under -key-dir, encrypted with the passphrase
+// in -passphrase-file when given. A controller started with
+// -key-signer <socket> generates and uses its Zone CA and operational keys
+// through this process and never sees the key material.
+//
+// Usage:
+//
+//	mash-signer [flags]
+//
+// Flags:
+//
+//	-socket string           Unix socket to listen on (default "mash-signer.sock")
+//	-key-dir string          Directory holding the keys (required)
+//	-passphrase-file string  Encrypt keys with the passphrase in this file
+//
+// Examples:
+//
+//	# Serve encrypted keys to a controller
+//	mash-signer -key-dir /var/lib/mash-signer -passphrase-file /etc/mash/key.pass \
+//	    -socket /run/mash/signer.sock
+//	mash-controller -state-dir /var/lib/mash-controller -key-signer /run/mash/signer.sock
+package main
+
+import (
+	"flag"
+	"log"
+	"net"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/mash-protocol/mash-go/pkg/cert"
+)
+
+func main() {
+	socket := flag.String("socket", "mash-signer.sock", "Unix socket to listen on")
+	keyDir := flag.String("key-dir", "", "Directory holding the keys (required)")
+	passphraseFile := flag.String("passphrase-file", "", "Encrypt keys with the passphrase in this file")
+	flag.Parse()
+
+	if *keyDir == "" {
+		log.Fatal("-key-dir is required")
+	}
+
+	keys := cert.NewFileKeyStore(*keyDir)
+	if *passphraseFile != "" {
+		passphrase, err := cert.ReadPassphraseFile(*passphraseFile)
+		if err != nil {
+			log.Fatalf("Failed to read passphrase: %v", err)
+		}
+		keys = cert.NewEncryptedFileKeyStore(*keyDir, passphrase)
+	} else {
+		log.Println("Warning: keys are stored unencrypted; use -passphrase-file")
+	}
+
+	// Remove a stale socket from a previous run
+	_ = os.Remove(*socket)
+	listener, err := net.Listen("unix", *socket)
+	if err != nil {
+		log.Fatalf("Failed to listen: %v", err)
+	}
+	if err := os.Chmod(*socket, 0600); err != nil {
+		log.Fatalf("Failed to restrict socket permissions: %v", err)
+	}
+
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
+	go func() {
+		<-sigCh
+		listener.Close()
+	}()
+
+	log.Printf("Serving keys from %s on %s", *keyDir, *socket)
+	if err := cert.NewSignerServer(keys).Serve(listener); err != nil {
+		log.Fatalf("Serve failed: %v", err)
+	}
+	log.Println("Shutting down")
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"sync"
 
+	"github.com/mash-protocol/mash-go/pkg/featureclient"
 	"github.com/mash-protocol/mash-go/pkg/features"
 	"github.com/mash-protocol/mash-go/pkg/interaction"
 	"github.com/mash-protocol/mash-go/pkg/model"
@@ -14,13 +15,8 @@ import (
 
 // DeviceClient is an interface for interacting with a remote device.
 // Both interaction.Client and service.DeviceSession implement this interface.
-type DeviceClient interface {
-	Read(ctx context.Context, endpointID uint8, featureID uint8, attrIDs []uint16) (map[uint16]any, error)
-	Write(ctx context.Context, endpointID uint8, featureID uint8, attrs map[uint16]any) (map[uint16]any, error)
-	Subscribe(ctx context.Context, endpointID uint8, featureID uint8, opts *interaction.SubscribeOptions) (uint32, map[uint16]any, error)
-	Unsubscribe(ctx context.Context, subscriptionID uint32) error
-	Invoke(ctx context.Context, endpointID uint8, featureID uint8, commandID uint8, params map[string]any) (any, error)
-}
+// It is the interface the generated feature clients are built on.
+type DeviceClient = featureclient.DeviceClient
 
 // CEM represents a Central Energy Manager that controls other devices.
 // It demonstrates how to build a MASH controller that:
@@ -479,58 +475,21 @@ func (c *CEM) SetPowerLimitFull(ctx context.Context, deviceID string, endpointID
 		effectiveCause = zoneType
 	}
 
-	params := map[string]any{
-		"consumptionLimit": limitMW,
-		"cause":            uint8(effectiveCause),
-	}
-	if durationSec != nil {
-		params["duration"] = *durationSec
-	}
-
-	rawResult, err := device.Client.Invoke(ctx, endpointID, uint8(model.FeatureEnergyControl),
-		features.EnergyControlCmdSetLimit, params)
+	resp, err := featureclient.NewEnergyControlClient(device.Client, endpointID).SetLimit(ctx, features.SetLimitRequest{
+		ConsumptionLimit: &limitMW,
+		Duration:         durationSec,
+		Cause:            effectiveCause,
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	result := &SetLimitResult{}
-
-	// Parse enhanced response using type-safe coercion
-	// CBOR decodes maps as map[any]any when target is any, so normalize first
-	if resultMap := wire.ToStringMap(rawResult); resultMap != nil {
-		// Parse applied
-		if rawVal, exists := resultMap["applied"]; exists {
-			if v, ok := rawVal.(bool); ok {
-				result.Applied = v
-			}
-		}
-
-		// Parse controlState
-		if rawVal, exists := resultMap["controlState"]; exists {
-			if v, ok := wire.ToUint8Public(rawVal); ok {
-				result.ControlState = features.ControlState(v)
-			}
-		}
-
-		// Parse rejectReason (only if not applied)
-		if rawVal, exists := resultMap["rejectReason"]; exists {
-			if v, ok := wire.ToUint8Public(rawVal); ok {
-				reason := features.LimitRejectReason(v)
-				result.RejectReason = &reason
-			}
-		}
-
-		// Parse effective limits
-		if rawVal, exists := resultMap["effectiveConsumptionLimit"]; exists {
-			if v, ok := wire.ToInt64(rawVal); ok {
-				result.EffectiveConsumptionLimit = &v
-			}
-		}
-		if rawVal, exists := resultMap["effectiveProductionLimit"]; exists {
-			if v, ok := wire.ToInt64(rawVal); ok {
-				result.EffectiveProductionLimit = &v
-			}
-		}
+	result := &SetLimitResult{
+		Applied:                   resp.Applied,
+		ControlState:              resp.ControlState,
+		RejectReason:              resp.RejectReason,
+		EffectiveConsumptionLimit: resp.EffectiveConsumptionLimit,
+		EffectiveProductionLimit:  resp.EffectiveProductionLimit,
 	}
 
 	// Update cached device state
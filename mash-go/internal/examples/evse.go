@@ -33,7 +33,9 @@ type EVSE struct {
 	plan            *features.Plan
 
 	// Limit resolution
-	limitResolver *features.LimitResolver
+	limitResolver        *features.LimitResolver
+	phaseCurrentResolver *features.PhaseCurrentResolver
+	constraintScheduler  *features.ConstraintScheduler
 
 	// Internal state
 	currentPower int64 // mW - actual charging power
@@ -187,6 +189,16 @@ func (e *EVSE) setupCommandHandlers() {
 	e.limitResolver.MaxConsumption = e.electrical.NominalMaxConsumption()
 	e.limitResolver.Register()
 
+	// Per-phase current limits (acceptsCurrentLimits), validated against
+	// the Electrical phase configuration.
+	e.phaseCurrentResolver = features.NewPhaseCurrentResolver(e.energyControl, e.electrical)
+	e.phaseCurrentResolver.Register()
+
+	// Constraint signals (POEN) are applied as a synthetic limit
+	// contribution, re-evaluated at each slot boundary.
+	e.constraintScheduler = features.NewConstraintScheduler(e.signals, e.limitResolver)
+	e.constraintScheduler.Register()
+
 	// Pause handler
 	e.energyControl.OnPause(func(ctx context.Context, req features.PauseRequest) error {
 		e.mu.Lock()
@@ -234,20 +246,24 @@ func (e *EVSE) setupCommandHandlers() {
 		}
 		return nil
 	})
-	e.signals.OnSendConstraintSignal(func(ctx context.Context, req features.SendConstraintSignalRequest) error {
-		_ = e.signals.SetSignalSource(req.Source)
-		_ = e.signals.SetStartTime(req.StartTime)
-		if req.ValidUntil != nil {
-			_ = e.signals.SetValidUntil(*req.ValidUntil)
-		}
-		return nil
-	})
 	e.signals.OnClearSignals(func(ctx context.Context, req features.ClearSignalsRequest) (features.ClearSignalsResponse, error) {
-		_ = e.signals.ClearPriceSlots()
-		_ = e.signals.ClearConstraintSlots()
-		_ = e.signals.ClearForecastSlots()
-		_ = e.signals.ClearSignalSource()
-		return features.ClearSignalsResponse{Cleared: 1}, nil
+		var cleared uint8
+		if req.SignalType == nil || *req.SignalType == "price" {
+			_ = e.signals.ClearPriceSlots()
+			cleared++
+		}
+		if req.SignalType == nil || *req.SignalType == "forecast" {
+			_ = e.signals.ClearForecastSlots()
+			cleared++
+		}
+		if req.SignalType == nil || *req.SignalType == "constraint" {
+			e.constraintScheduler.Clear()
+			cleared++
+		}
+		if req.SignalType == nil {
+			_ = e.signals.ClearSignalSource()
+		}
+		return features.ClearSignalsResponse{Cleared: cleared}, nil
 	})
 
 	// Plan handlers - respond with plan data
@@ -376,6 +392,16 @@ func (e *EVSE) LimitResolver() *features.LimitResolver {
 	return e.limitResolver
 }
 
+// PhaseCurrentResolver returns the PhaseCurrentResolver for external wiring.
+func (e *EVSE) PhaseCurrentResolver() *features.PhaseCurrentResolver {
+	return e.phaseCurrentResolver
+}
+
+// ConstraintScheduler returns the ConstraintScheduler for external wiring.
+func (e *EVSE) ConstraintScheduler() *features.ConstraintScheduler {
+	return e.constraintScheduler
+}
+
 // AcceptController marks the EVSE as being controlled.
 func (e *EVSE) AcceptController() {
 	e.mu.Lock()
@@ -0,0 +1,56 @@
+// Package gateway bridges browser WebSocket sessions to MASH operational
+// sessions.
+//
+// The browser speaks JSON over a WebSocket; the gateway relays each request
+// over the controller's TLS session to the device and streams subscription
+// notifications back. Endpoints, features, attributes and commands are named
+// as in the inspect CLI, e.g. "1/measurement/acActivePower" or
+// "1/energyControl/cmd/setLimit".
+//
+// # Usage
+//
+//	gw := gateway.New(gateway.FromController(svc), gateway.Config{})
+//	http.Handle("/ws", gw)
+//
+//	// Forward notifications from each device session.
+//	session.SetNotificationHandler(func(notif *wire.Notification) {
+//	    gw.HandleNotification(deviceID, notif)
+//	})
+//
+// # Security
+//
+// Connections must carry an Origin header naming the gateway's own host or
+// one of Config.AllowedOrigins. Without Config.Token the gateway is
+// read-only: reads and subscriptions are relayed, writes and commands are
+// refused. With a token, clients must present it and may also write and
+// invoke:
+//
+//	gw := gateway.New(gateway.FromController(svc), gateway.Config{Token: token})
+//	// ws://localhost:8090/ws?token=... or "Authorization: Bearer ..."
+//
+// # Messages
+//
+// Requests carry a client-chosen id that is echoed in the response:
+//
+//	{"id": 1, "op": "devices"}
+//	{"id": 2, "op": "read", "device": "a1b2...", "path": "1/measurement/acActivePower"}
+//	{"id": 3, "op": "read", "device": "a1b2...", "path": "1/measurement"}
+//	{"id": 4, "op": "write", "device": "a1b2...", "path": "1/energyControl/failsafeConsumptionLimit", "value": 5000000}
+//	{"id": 5, "op": "subscribe", "device": "a1b2...", "path": "1/measurement", "minIntervalMs": 1000}
+//	{"id": 6, "op": "unsubscribe", "device": "a1b2...", "subscription": 7}
+//	{"id": 7, "op": "invoke", "device": "a1b2...", "path": "1/energyControl/cmd/setLimit", "params": {...}}
+//
+// The device may also be given as the first path segment, as in the CLI.
+// Responses echo the id and carry either an error or the result:
+//
+//	{"type": "response", "id": 2, "values": {"acActivePower": 7400000}}
+//	{"type": "response", "id": 5, "subscription": 7, "values": {...}}
+//	{"type": "response", "id": 9, "error": "device not connected: a1b2..."}
+//
+// Notifications for subscriptions made on the WebSocket are pushed as:
+//
+//	{"type": "notification", "device": "a1b2...", "subscription": 7,
+//	 "endpoint": 1, "feature": "Measurement", "changes": {"acActivePower": 7350000}}
+//
+// Subscriptions are removed from the device when the WebSocket closes.
+package gateway
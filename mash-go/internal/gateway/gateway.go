@@ -0,0 +1,436 @@
+package gateway
+
+import (
+	"bytes"
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/net/websocket"
+
+	"github.com/mash-protocol/mash-go/internal/inspect"
+	"github.com/mash-protocol/mash-go/pkg/interaction"
+	"github.com/mash-protocol/mash-go/pkg/service"
+	"github.com/mash-protocol/mash-go/pkg/wire"
+)
+
+// Gateway errors, returned to the browser in Response.Error.
+var (
+	ErrUnknownOp           = errors.New("unknown op")
+	ErrNoDevice            = errors.New("no device given")
+	ErrNotConnected        = errors.New("device not connected")
+	ErrInvalidPath         = errors.New("invalid path")
+	ErrUnknownSubscription = errors.New("unknown subscription")
+	ErrOriginNotAllowed    = errors.New("origin not allowed")
+	ErrUnauthorized        = errors.New("missing or invalid token")
+	ErrReadOnly            = errors.New("gateway is read-only")
+)
+
+// Defaults.
+const (
+	DefaultRequestTimeout = 10 * time.Second
+	DefaultMaxMessageSize = 1 << 20
+)
+
+// Session is the part of service.DeviceSession the gateway relays to.
+type Session interface {
+	inspect.SessionReader
+	Subscribe(ctx context.Context, endpointID uint8, featureID uint8, opts *interaction.SubscribeOptions) (uint32, map[uint16]any, error)
+	Unsubscribe(ctx context.Context, subscriptionID uint32) error
+}
+
+// Devices provides the operational sessions of a controller.
+type Devices interface {
+	// DeviceIDs returns the IDs of all commissioned devices.
+	DeviceIDs() []string
+
+	// Session returns the session to a device, or nil if the device is
+	// not connected.
+	Session(deviceID string) Session
+}
+
+// FromController returns the devices of a controller service.
+func FromController(svc *service.ControllerService) Devices {
+	return controllerDevices{svc: svc}
+}
+
+type controllerDevices struct {
+	svc *service.ControllerService
+}
+
+func (c controllerDevices) DeviceIDs() []string {
+	devices := c.svc.GetAllDevices()
+	ids := make([]string, 0, len(devices))
+	for _, d := range devices {
+		ids = append(ids, d.ID)
+	}
+	return ids
+}
+
+func (c controllerDevices) Session(deviceID string) Session {
+	// Avoid returning a typed nil.
+	if session := c.svc.GetSession(deviceID); session != nil {
+		return session
+	}
+	return nil
+}
+
+// Config configures a Gateway.
+type Config struct {
+	// AllowedOrigins lists the browser origins (scheme://host[:port]) that
+	// may connect. If empty, only pages served from the gateway's own host
+	// may connect. Clients that send no Origin header are rejected.
+	AllowedOrigins []string
+
+	// Token, if set, must be presented by every client, either as a
+	// "token" query parameter or as an "Authorization: Bearer" header.
+	// Without a token the gateway is read-only: write and invoke are
+	// refused with ErrReadOnly.
+	Token string
+
+	// RequestTimeout bounds each relayed request and each write to the
+	// browser (default: 10s).
+	RequestTimeout time.Duration
+
+	// MaxMessageSize is the largest request accepted from the browser
+	// (default: 1 MiB).
+	MaxMessageSize int
+}
+
+// Gateway is an http.Handler that relays browser WebSocket sessions to
+// MASH device sessions.
+type Gateway struct {
+	devices Devices
+	config  Config
+	server  websocket.Server
+
+	mu   sync.Mutex
+	subs map[subscriptionKey]*conn
+}
+
+// subscriptionKey identifies a subscription; IDs are per device session.
+type subscriptionKey struct {
+	deviceID       string
+	subscriptionID uint32
+}
+
+// New creates a gateway relaying to devices.
+func New(devices Devices, config Config) *Gateway {
+	if config.RequestTimeout == 0 {
+		config.RequestTimeout = DefaultRequestTimeout
+	}
+	if config.MaxMessageSize == 0 {
+		config.MaxMessageSize = DefaultMaxMessageSize
+	}
+
+	g := &Gateway{
+		devices: devices,
+		config:  config,
+		subs:    make(map[subscriptionKey]*conn),
+	}
+	g.server = websocket.Server{
+		Handshake: g.handshake,
+		Handler:   g.serve,
+	}
+	return g
+}
+
+// ServeHTTP upgrades the request to a WebSocket and serves it until the
+// browser disconnects.
+func (g *Gateway) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	g.server.ServeHTTP(w, r)
+}
+
+// HandleNotification forwards a notification from a device session to the
+// WebSocket that made the subscription, if any.
+func (g *Gateway) HandleNotification(deviceID string, notif *wire.Notification) {
+	g.mu.Lock()
+	c := g.subs[subscriptionKey{deviceID, notif.SubscriptionID}]
+	g.mu.Unlock()
+	if c == nil {
+		return
+	}
+
+	_ = c.send(&Notification{
+		Type:         TypeNotification,
+		Device:       deviceID,
+		Subscription: notif.SubscriptionID,
+		Endpoint:     notif.EndpointID,
+		Feature:      inspect.GetFeatureName(notif.FeatureID),
+		Changes:      attributeValues(notif.FeatureID, notif.Changes),
+	})
+}
+
+// handshake admits a WebSocket connection if it comes from an allowed
+// origin and carries the configured token.
+func (g *Gateway) handshake(config *websocket.Config, r *http.Request) error {
+	if err := g.checkOrigin(config, r); err != nil {
+		return err
+	}
+	return g.checkToken(r)
+}
+
+// checkToken rejects clients that do not present the configured token.
+func (g *Gateway) checkToken(r *http.Request) error {
+	if g.config.Token == "" {
+		return nil
+	}
+	token := r.URL.Query().Get("token")
+	if auth, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); ok {
+		token = auth
+	}
+	if subtle.ConstantTimeCompare([]byte(token), []byte(g.config.Token)) != 1 {
+		return ErrUnauthorized
+	}
+	return nil
+}
+
+// checkOrigin rejects cross-site connections from browsers and clients
+// that do not say where they come from.
+func (g *Gateway) checkOrigin(config *websocket.Config, r *http.Request) error {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return fmt.Errorf("%w: no origin", ErrOriginNotAllowed)
+	}
+	u, err := url.Parse(origin)
+	if err != nil {
+		return fmt.Errorf("%w: %q", ErrOriginNotAllowed, origin)
+	}
+	config.Origin = u
+
+	if len(g.config.AllowedOrigins) == 0 {
+		if strings.EqualFold(u.Host, r.Host) {
+			return nil
+		}
+		return fmt.Errorf("%w: %q", ErrOriginNotAllowed, origin)
+	}
+	for _, allowed := range g.config.AllowedOrigins {
+		if strings.EqualFold(u.Scheme+"://"+u.Host, allowed) {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %q", ErrOriginNotAllowed, origin)
+}
+
+// serve runs one WebSocket session.
+func (g *Gateway) serve(ws *websocket.Conn) {
+	ws.MaxPayloadBytes = g.config.MaxMessageSize
+
+	ctx, cancel := context.WithCancel(ws.Request().Context())
+	c := &conn{gw: g, ws: ws, ctx: ctx}
+	defer func() {
+		cancel()
+		c.wg.Wait()
+		g.closeSubscriptions(c)
+		ws.Close()
+	}()
+
+	for {
+		var data []byte
+		if err := websocket.Message.Receive(ws, &data); err != nil {
+			return
+		}
+
+		var req Request
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.UseNumber()
+		if err := dec.Decode(&req); err != nil {
+			_ = c.send(&Response{Type: TypeResponse, Error: fmt.Sprintf("invalid request: %v", err)})
+			continue
+		}
+
+		// Requests run concurrently so a slow command does not hold up
+		// reads; the browser matches responses by ID.
+		c.wg.Add(1)
+		go func() {
+			defer c.wg.Done()
+			c.handle(&req)
+		}()
+	}
+}
+
+// closeSubscriptions removes the subscriptions made on c from the devices.
+func (g *Gateway) closeSubscriptions(c *conn) {
+	g.mu.Lock()
+	var keys []subscriptionKey
+	for key, owner := range g.subs {
+		if owner == c {
+			keys = append(keys, key)
+			delete(g.subs, key)
+		}
+	}
+	g.mu.Unlock()
+
+	for _, key := range keys {
+		session := g.devices.Session(key.deviceID)
+		if session == nil {
+			continue
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), g.config.RequestTimeout)
+		_ = session.Unsubscribe(ctx, key.subscriptionID)
+		cancel()
+	}
+}
+
+// conn is one browser WebSocket session.
+type conn struct {
+	gw  *Gateway
+	ws  *websocket.Conn
+	ctx context.Context
+	wg  sync.WaitGroup
+
+	writeMu sync.Mutex
+}
+
+// send writes a JSON message to the browser.
+func (c *conn) send(msg any) error {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	_ = c.ws.SetWriteDeadline(time.Now().Add(c.gw.config.RequestTimeout))
+	return websocket.Message.Send(c.ws, string(data))
+}
+
+// handle relays a request and sends the response.
+func (c *conn) handle(req *Request) {
+	ctx, cancel := context.WithTimeout(c.ctx, c.gw.config.RequestTimeout)
+	defer cancel()
+
+	resp := &Response{Type: TypeResponse, ID: req.ID}
+	if err := c.do(ctx, req, resp); err != nil {
+		resp = &Response{Type: TypeResponse, ID: req.ID, Error: err.Error()}
+	}
+	_ = c.send(resp)
+}
+
+func (c *conn) do(ctx context.Context, req *Request, resp *Response) error {
+	if req.Op == OpDevices {
+		for _, id := range c.gw.devices.DeviceIDs() {
+			resp.Devices = append(resp.Devices, DeviceStatus{
+				ID:        id,
+				Connected: c.gw.devices.Session(id) != nil,
+			})
+		}
+		return nil
+	}
+
+	deviceID := req.Device
+	var path *inspect.Path
+	if req.Op != OpUnsubscribe {
+		var err error
+		path, err = inspect.ParsePath(req.Path)
+		if err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidPath, err)
+		}
+		if deviceID == "" {
+			deviceID = path.DeviceID
+		}
+		if path.IsPartial && path.FeatureID == 0 {
+			return fmt.Errorf("%w: %q names no feature", ErrInvalidPath, req.Path)
+		}
+	}
+	if deviceID == "" {
+		return ErrNoDevice
+	}
+	session := c.gw.devices.Session(deviceID)
+	if session == nil {
+		return fmt.Errorf("%w: %s", ErrNotConnected, deviceID)
+	}
+
+	switch req.Op {
+	case OpRead:
+		if path.IsCommand {
+			return fmt.Errorf("%w: cannot read command path %q", ErrInvalidPath, req.Path)
+		}
+		var attrIDs []uint16
+		if !path.IsPartial {
+			attrIDs = []uint16{path.AttributeID}
+		}
+		values, err := session.Read(ctx, path.EndpointID, path.FeatureID, attrIDs)
+		if err != nil {
+			return err
+		}
+		resp.Values = attributeValues(path.FeatureID, values)
+
+	case OpWrite:
+		if c.gw.config.Token == "" {
+			return ErrReadOnly
+		}
+		if path.IsPartial || path.IsCommand {
+			return fmt.Errorf("%w: write needs an attribute path, got %q", ErrInvalidPath, req.Path)
+		}
+		values, err := session.Write(ctx, path.EndpointID, path.FeatureID, map[uint16]any{
+			path.AttributeID: fromJSON(req.Value),
+		})
+		if err != nil {
+			return err
+		}
+		resp.Values = attributeValues(path.FeatureID, values)
+
+	case OpSubscribe:
+		if path.IsCommand {
+			return fmt.Errorf("%w: cannot subscribe to command path %q", ErrInvalidPath, req.Path)
+		}
+		opts := &interaction.SubscribeOptions{
+			MinInterval: time.Duration(req.MinIntervalMs) * time.Millisecond,
+			MaxInterval: time.Duration(req.MaxIntervalMs) * time.Millisecond,
+		}
+		if !path.IsPartial {
+			opts.AttributeIDs = []uint16{path.AttributeID}
+		}
+		subID, values, err := session.Subscribe(ctx, path.EndpointID, path.FeatureID, opts)
+		if err != nil {
+			return err
+		}
+		c.gw.mu.Lock()
+		c.gw.subs[subscriptionKey{deviceID, subID}] = c
+		c.gw.mu.Unlock()
+		resp.Subscription = subID
+		resp.Values = attributeValues(path.FeatureID, values)
+
+	case OpUnsubscribe:
+		key := subscriptionKey{deviceID, req.Subscription}
+		c.gw.mu.Lock()
+		owned := c.gw.subs[key] == c
+		if owned {
+			delete(c.gw.subs, key)
+		}
+		c.gw.mu.Unlock()
+		if !owned {
+			return fmt.Errorf("%w: %d", ErrUnknownSubscription, req.Subscription)
+		}
+		return session.Unsubscribe(ctx, req.Subscription)
+
+	case OpInvoke:
+		if c.gw.config.Token == "" {
+			return ErrReadOnly
+		}
+		if !path.IsCommand {
+			return fmt.Errorf("%w: invoke needs a command path, got %q", ErrInvalidPath, req.Path)
+		}
+		var params map[string]any
+		if req.Params != nil {
+			params = fromJSON(req.Params).(map[string]any)
+		}
+		result, err := session.Invoke(ctx, path.EndpointID, path.FeatureID, path.CommandID, params)
+		if err != nil {
+			return err
+		}
+		resp.Result = toJSON(result)
+
+	default:
+		return fmt.Errorf("%w: %q", ErrUnknownOp, req.Op)
+	}
+	return nil
+}
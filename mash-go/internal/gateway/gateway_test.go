@@ -0,0 +1,332 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	neturl "net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"golang.org/x/net/websocket"
+
+	"github.com/mash-protocol/mash-go/pkg/features"
+	"github.com/mash-protocol/mash-go/pkg/interaction"
+	"github.com/mash-protocol/mash-go/pkg/model"
+	"github.com/mash-protocol/mash-go/pkg/wire"
+)
+
+// fakeSession records the requests relayed to a device.
+type fakeSession struct {
+	id string
+
+	mu           sync.Mutex
+	values       map[uint16]any
+	written      map[uint16]any
+	subscribed   *interaction.SubscribeOptions
+	unsubscribed []uint32
+	invoked      uint8
+	params       map[string]any
+}
+
+func (s *fakeSession) DeviceID() string { return s.id }
+
+func (s *fakeSession) Read(_ context.Context, _ uint8, _ uint8, attrIDs []uint16) (map[uint16]any, error) {
+	if attrIDs == nil {
+		return s.values, nil
+	}
+	result := make(map[uint16]any)
+	for _, id := range attrIDs {
+		result[id] = s.values[id]
+	}
+	return result, nil
+}
+
+func (s *fakeSession) Write(_ context.Context, _ uint8, _ uint8, attrs map[uint16]any) (map[uint16]any, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.written = attrs
+	return attrs, nil
+}
+
+func (s *fakeSession) Invoke(_ context.Context, _ uint8, _ uint8, commandID uint8, params map[string]any) (any, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.invoked = commandID
+	s.params = params
+	return map[any]any{uint64(1): true}, nil
+}
+
+func (s *fakeSession) Subscribe(_ context.Context, _ uint8, _ uint8, opts *interaction.SubscribeOptions) (uint32, map[uint16]any, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.subscribed = opts
+	return 7, s.values, nil
+}
+
+func (s *fakeSession) Unsubscribe(_ context.Context, subscriptionID uint32) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.unsubscribed = append(s.unsubscribed, subscriptionID)
+	return nil
+}
+
+func (s *fakeSession) unsubscribedIDs() []uint32 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]uint32(nil), s.unsubscribed...)
+}
+
+type fakeDevices map[string]*fakeSession
+
+func (d fakeDevices) DeviceIDs() []string {
+	ids := make([]string, 0, len(d))
+	for id := range d {
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+func (d fakeDevices) Session(deviceID string) Session {
+	if s, ok := d[deviceID]; ok {
+		return s
+	}
+	return nil
+}
+
+func startGateway(t *testing.T, config Config) (*Gateway, *fakeSession, *httptest.Server) {
+	t.Helper()
+	session := &fakeSession{
+		id: "dev1",
+		values: map[uint16]any{
+			features.MeasurementAttrACActivePower: int64(7400000),
+			999:                                   map[any]any{uint64(1): "x"},
+		},
+	}
+	gw := New(fakeDevices{"dev1": session}, config)
+	srv := httptest.NewServer(gw)
+	t.Cleanup(srv.Close)
+	return gw, session, srv
+}
+
+// dial connects from the gateway's own origin, presenting token if set.
+func dial(t *testing.T, srv *httptest.Server, token string) *websocket.Conn {
+	t.Helper()
+	url := "ws" + strings.TrimPrefix(srv.URL, "http")
+	if token != "" {
+		url += "/?token=" + token
+	}
+	ws, err := websocket.Dial(url, "", srv.URL)
+	if err != nil {
+		t.Fatalf("Dial failed: %v", err)
+	}
+	t.Cleanup(func() { ws.Close() })
+	return ws
+}
+
+// roundTrip sends a request and returns the next message as a generic map.
+func roundTrip(t *testing.T, ws *websocket.Conn, req string) map[string]any {
+	t.Helper()
+	if err := websocket.Message.Send(ws, req); err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+	return receive(t, ws)
+}
+
+func receive(t *testing.T, ws *websocket.Conn) map[string]any {
+	t.Helper()
+	_ = ws.SetReadDeadline(time.Now().Add(2 * time.Second))
+	var msg map[string]any
+	if err := websocket.JSON.Receive(ws, &msg); err != nil {
+		t.Fatalf("Receive failed: %v", err)
+	}
+	return msg
+}
+
+func TestGatewayReadWriteInvoke(t *testing.T) {
+	_, session, srv := startGateway(t, Config{Token: "secret"})
+	ws := dial(t, srv, "secret")
+
+	resp := roundTrip(t, ws, `{"id":1,"op":"read","device":"dev1","path":"1/measurement/acActivePower"}`)
+	if resp["id"] != float64(1) || resp["error"] != nil {
+		t.Fatalf("read response = %v", resp)
+	}
+	if values := resp["values"].(map[string]any); values["acActivePower"] != float64(7400000) {
+		t.Errorf("values = %v, want acActivePower 7400000", values)
+	}
+
+	// The device may be the first path segment; CBOR maps are converted.
+	resp = roundTrip(t, ws, `{"id":2,"op":"read","path":"dev1/1/measurement"}`)
+	values := resp["values"].(map[string]any)
+	if nested, ok := values["999"].(map[string]any); !ok || nested["1"] != "x" {
+		t.Errorf("values = %v, want 999 as {1: x}", values)
+	}
+
+	resp = roundTrip(t, ws, `{"id":3,"op":"write","device":"dev1","path":"1/energyControl/failsafeConsumptionLimit","value":5000000}`)
+	if resp["error"] != nil {
+		t.Fatalf("write response = %v", resp)
+	}
+	if v := session.written[features.EnergyControlAttrFailsafeConsumptionLimit]; v != int64(5000000) {
+		t.Errorf("written value = %v (%T), want int64 5000000", v, v)
+	}
+
+	resp = roundTrip(t, ws, `{"id":4,"op":"invoke","device":"dev1","path":"1/energyControl/cmd/setLimit","params":{"consumptionLimit":6000000}}`)
+	if resp["error"] != nil {
+		t.Fatalf("invoke response = %v", resp)
+	}
+	if session.invoked != features.EnergyControlCmdSetLimit || session.params["consumptionLimit"] != int64(6000000) {
+		t.Errorf("invoked %d with %v", session.invoked, session.params)
+	}
+	if result := resp["result"].(map[string]any); result["1"] != true {
+		t.Errorf("result = %v", result)
+	}
+}
+
+func TestGatewayErrors(t *testing.T) {
+	_, _, srv := startGateway(t, Config{Token: "secret"})
+	ws := dial(t, srv, "secret")
+
+	tests := []struct {
+		req  string
+		want string
+	}{
+		{`{"id":1,"op":"read","device":"nope","path":"1/measurement"}`, ErrNotConnected.Error()},
+		{`{"id":2,"op":"read","path":"1/measurement"}`, ErrNoDevice.Error()},
+		{`{"id":3,"op":"write","device":"dev1","path":"1/measurement"}`, ErrInvalidPath.Error()},
+		{`{"id":4,"op":"invoke","device":"dev1","path":"1/measurement/acActivePower"}`, ErrInvalidPath.Error()},
+		{`{"id":5,"op":"unsubscribe","device":"dev1","subscription":7}`, ErrUnknownSubscription.Error()},
+		{`{"id":6,"op":"reboot","device":"dev1","path":"1/measurement"}`, ErrUnknownOp.Error()},
+		{`not json`, "invalid request"},
+	}
+	for _, tt := range tests {
+		resp := roundTrip(t, ws, tt.req)
+		if msg, _ := resp["error"].(string); !strings.Contains(msg, tt.want) {
+			t.Errorf("%s: error = %q, want %q", tt.req, msg, tt.want)
+		}
+	}
+}
+
+func TestGatewaySubscribe(t *testing.T) {
+	gw, session, srv := startGateway(t, Config{})
+	ws := dial(t, srv, "")
+
+	resp := roundTrip(t, ws, `{"id":1,"op":"subscribe","device":"dev1","path":"1/measurement","minIntervalMs":500}`)
+	if resp["subscription"] != float64(7) {
+		t.Fatalf("subscribe response = %v", resp)
+	}
+	if session.subscribed.MinInterval != 500*time.Millisecond {
+		t.Errorf("MinInterval = %v, want 500ms", session.subscribed.MinInterval)
+	}
+
+	// Notifications for other subscriptions are not forwarded.
+	gw.HandleNotification("dev1", &wire.Notification{SubscriptionID: 8, EndpointID: 1, FeatureID: uint8(model.FeatureMeasurement)})
+	gw.HandleNotification("dev1", &wire.Notification{
+		SubscriptionID: 7,
+		EndpointID:     1,
+		FeatureID:      uint8(model.FeatureMeasurement),
+		Changes:        map[uint16]any{features.MeasurementAttrACActivePower: int64(7350000)},
+	})
+
+	notif := receive(t, ws)
+	if notif["type"] != TypeNotification || notif["subscription"] != float64(7) || notif["feature"] != "Measurement" {
+		t.Fatalf("notification = %v", notif)
+	}
+	if changes := notif["changes"].(map[string]any); changes["acActivePower"] != float64(7350000) {
+		t.Errorf("changes = %v", changes)
+	}
+
+	// Closing the WebSocket removes the subscription from the device.
+	ws.Close()
+	deadline := time.Now().Add(2 * time.Second)
+	for len(session.unsubscribedIDs()) == 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if got := session.unsubscribedIDs(); len(got) != 1 || got[0] != 7 {
+		t.Errorf("unsubscribed = %v, want [7]", got)
+	}
+}
+
+func TestGatewayDevices(t *testing.T) {
+	_, _, srv := startGateway(t, Config{})
+	ws := dial(t, srv, "")
+
+	resp := roundTrip(t, ws, `{"id":1,"op":"devices"}`)
+	data, _ := json.Marshal(resp["devices"])
+	if string(data) != `[{"connected":true,"id":"dev1"}]` {
+		t.Errorf("devices = %s", data)
+	}
+}
+
+func TestGatewayOrigin(t *testing.T) {
+	_, _, srv := startGateway(t, Config{})
+	url := "ws" + strings.TrimPrefix(srv.URL, "http")
+
+	if _, err := websocket.Dial(url, "", "http://evil.example"); err == nil {
+		t.Error("cross-site origin accepted")
+	}
+
+	_, _, srv = startGateway(t, Config{AllowedOrigins: []string{"http://console.example:8080"}})
+	url = "ws" + strings.TrimPrefix(srv.URL, "http")
+	ws, err := websocket.Dial(url, "", "http://console.example:8080")
+	if err != nil {
+		t.Fatalf("allowed origin rejected: %v", err)
+	}
+	ws.Close()
+
+	// Clients without an Origin header are not trusted
+	config, err := websocket.NewConfig(url, "http://console.example:8080")
+	if err != nil {
+		t.Fatal(err)
+	}
+	config.Origin = &neturl.URL{}
+	if _, err := websocket.DialConfig(config); err == nil {
+		t.Error("missing origin accepted")
+	}
+}
+
+func TestGatewayToken(t *testing.T) {
+	_, _, srv := startGateway(t, Config{Token: "secret"})
+	url := "ws" + strings.TrimPrefix(srv.URL, "http")
+
+	for _, u := range []string{url, url + "/?token=wrong"} {
+		if _, err := websocket.Dial(u, "", srv.URL); err == nil {
+			t.Errorf("%s: connection without the token accepted", u)
+		}
+	}
+
+	config, err := websocket.NewConfig(url, srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	config.Header.Set("Authorization", "Bearer secret")
+	ws, err := websocket.DialConfig(config)
+	if err != nil {
+		t.Fatalf("bearer token rejected: %v", err)
+	}
+	ws.Close()
+}
+
+func TestGatewayReadOnly(t *testing.T) {
+	_, session, srv := startGateway(t, Config{})
+	ws := dial(t, srv, "")
+
+	for _, req := range []string{
+		`{"id":1,"op":"write","device":"dev1","path":"1/energyControl/failsafeConsumptionLimit","value":5000000}`,
+		`{"id":2,"op":"invoke","device":"dev1","path":"1/energyControl/cmd/setLimit","params":{"consumptionLimit":6000000}}`,
+	} {
+		resp := roundTrip(t, ws, req)
+		if resp["error"] != ErrReadOnly.Error() {
+			t.Errorf("%s: error = %v, want %q", req, resp["error"], ErrReadOnly)
+		}
+	}
+	if len(session.written) != 0 || session.invoked != 0 {
+		t.Errorf("read-only gateway wrote %v, invoked %d", session.written, session.invoked)
+	}
+
+	resp := roundTrip(t, ws, `{"id":3,"op":"read","device":"dev1","path":"1/measurement/acActivePower"}`)
+	if resp["error"] != nil {
+		t.Errorf("read response = %v", resp)
+	}
+}
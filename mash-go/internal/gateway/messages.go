@@ -0,0 +1,163 @@
+package gateway
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/mash-protocol/mash-go/internal/inspect"
+)
+
+// Request operations.
+const (
+	OpDevices     = "devices"
+	OpRead        = "read"
+	OpWrite       = "write"
+	OpSubscribe   = "subscribe"
+	OpUnsubscribe = "unsubscribe"
+	OpInvoke      = "invoke"
+)
+
+// Message types sent to the browser.
+const (
+	TypeResponse     = "response"
+	TypeNotification = "notification"
+)
+
+// Request is a message from the browser.
+type Request struct {
+	// ID is chosen by the browser and echoed in the response.
+	ID uint32 `json:"id"`
+
+	// Op is the operation, one of the Op constants.
+	Op string `json:"op"`
+
+	// Device is the device ID. It may instead be given as the first
+	// segment of Path.
+	Device string `json:"device,omitempty"`
+
+	// Path is an inspect path: endpoint/feature for all attributes,
+	// endpoint/feature/attribute, or endpoint/feature/cmd/command.
+	Path string `json:"path,omitempty"`
+
+	// Value is the attribute value for OpWrite.
+	Value any `json:"value,omitempty"`
+
+	// Params are the command parameters for OpInvoke.
+	Params map[string]any `json:"params,omitempty"`
+
+	// Subscription is the subscription ID for OpUnsubscribe.
+	Subscription uint32 `json:"subscription,omitempty"`
+
+	// MinIntervalMs and MaxIntervalMs set the notification intervals for
+	// OpSubscribe.
+	MinIntervalMs uint32 `json:"minIntervalMs,omitempty"`
+	MaxIntervalMs uint32 `json:"maxIntervalMs,omitempty"`
+}
+
+// Response answers a Request.
+type Response struct {
+	Type  string `json:"type"`
+	ID    uint32 `json:"id"`
+	Error string `json:"error,omitempty"`
+
+	// Values holds attribute values by name for read, write and subscribe.
+	Values map[string]any `json:"values,omitempty"`
+
+	// Result is the command result for invoke.
+	Result any `json:"result,omitempty"`
+
+	// Subscription is the subscription ID for subscribe.
+	Subscription uint32 `json:"subscription,omitempty"`
+
+	// Devices lists the controller's devices for devices.
+	Devices []DeviceStatus `json:"devices,omitempty"`
+}
+
+// Notification carries attribute changes for a subscription.
+type Notification struct {
+	Type         string         `json:"type"`
+	Device       string         `json:"device"`
+	Subscription uint32         `json:"subscription"`
+	Endpoint     uint8          `json:"endpoint"`
+	Feature      string         `json:"feature"`
+	Changes      map[string]any `json:"changes"`
+}
+
+// DeviceStatus describes a commissioned device.
+type DeviceStatus struct {
+	ID        string `json:"id"`
+	Connected bool   `json:"connected"`
+}
+
+// attributeValues keys attribute values by their inspect name, falling
+// back to the decimal ID for attributes without one.
+func attributeValues(featureID uint8, values map[uint16]any) map[string]any {
+	if values == nil {
+		return nil
+	}
+	named := make(map[string]any, len(values))
+	for id, v := range values {
+		name := inspect.GetAttributeName(featureID, id)
+		if name == "" {
+			name = strconv.Itoa(int(id))
+		}
+		named[name] = toJSON(v)
+	}
+	return named
+}
+
+// toJSON converts decoded CBOR values to values encoding/json can marshal.
+func toJSON(v any) any {
+	switch val := v.(type) {
+	case map[any]any:
+		result := make(map[string]any, len(val))
+		for k, v2 := range val {
+			result[fmt.Sprintf("%v", k)] = toJSON(v2)
+		}
+		return result
+	case map[string]any:
+		result := make(map[string]any, len(val))
+		for k, v2 := range val {
+			result[k] = toJSON(v2)
+		}
+		return result
+	case []any:
+		result := make([]any, len(val))
+		for i, v2 := range val {
+			result[i] = toJSON(v2)
+		}
+		return result
+	default:
+		return v
+	}
+}
+
+// fromJSON converts a value decoded with json.Decoder.UseNumber for
+// sending to a device: whole numbers become int64, other numbers float64.
+func fromJSON(v any) any {
+	switch val := v.(type) {
+	case json.Number:
+		if i, err := val.Int64(); err == nil {
+			return i
+		}
+		if f, err := val.Float64(); err == nil {
+			return f
+		}
+		return val.String()
+	case map[string]any:
+		result := make(map[string]any, len(val))
+		for k, v2 := range val {
+			result[k] = fromJSON(v2)
+		}
+		return result
+	case []any:
+		result := make([]any, len(val))
+		for i, v2 := range val {
+			result[i] = fromJSON(v2)
+		}
+		return result
+	default:
+		return v
+	}
+}
@@ -46,6 +46,7 @@ func initGeneratedNameTables() {
 		"featureMap":    model.AttrIDFeatureMap,
 		"attributeList": model.AttrIDAttributeList,
 		"commandList":   model.AttrIDCommandList,
+		"dataVersion":   model.AttrIDDataVersion,
 	}
 
 	deviceInfoAttrs := map[string]uint16{
@@ -55,7 +55,7 @@ type Path struct {
 // Supported formats:
 //   - "endpoint/feature/attribute" - local path
 //   - "device/endpoint/feature/attribute" - remote path
-//   - "endpoint/feature/cmd/commandID" - command path
+//   - "endpoint/feature/cmd/command" - command path (ID or name)
 //   - "endpoint/feature" - partial (for listing attributes)
 //   - "endpoint" - partial (for listing features)
 //
@@ -126,7 +126,7 @@ func ParsePath(input string) (*Path, error) {
 		if len(pathParts) < 4 {
 			return nil, fmt.Errorf("command path missing command ID")
 		}
-		cmdID, err := parseUint8(pathParts[3])
+		cmdID, err := parseCommandID(pathParts[3], p.FeatureID)
 		if err != nil {
 			return nil, fmt.Errorf("command ID: %w", err)
 		}
@@ -233,6 +233,19 @@ func parseAttributeID(s string, featureID uint8) (uint16, error) {
 	return 0, fmt.Errorf("%w: %s", ErrInvalidNumber, s)
 }
 
+// parseCommandID parses a command ID from string.
+func parseCommandID(s string, featureID uint8) (uint8, error) {
+	// Try numeric first
+	if id, err := parseUint8(s); err == nil {
+		return id, nil
+	}
+	// Try name resolution based on feature (case-insensitive)
+	if id, ok := ResolveCommandName(featureID, s); ok {
+		return id, nil
+	}
+	return 0, fmt.Errorf("%w: %s", ErrInvalidNumber, s)
+}
+
 // parseUint8 parses a uint8 from decimal or hex string.
 func parseUint8(s string) (uint8, error) {
 	var v uint64
@@ -3,6 +3,7 @@ package inspect
 import (
 	"testing"
 
+	"github.com/mash-protocol/mash-go/pkg/features"
 	"github.com/mash-protocol/mash-go/pkg/model"
 )
 
@@ -59,6 +60,21 @@ func TestParsePath(t *testing.T) {
 				IsCommand:  true,
 			},
 		},
+		{
+			name:  "command path with name",
+			input: "1/energyControl/cmd/setLimit",
+			want: &Path{
+				EndpointID: 1,
+				FeatureID:  uint8(model.FeatureEnergyControl),
+				CommandID:  features.EnergyControlCmdSetLimit,
+				IsCommand:  true,
+			},
+		},
+		{
+			name:    "unknown command name",
+			input:   "1/energyControl/cmd/noSuchCommand",
+			wantErr: true,
+		},
 		{
 			name:    "empty path",
 			input:   "",
@@ -20,6 +20,7 @@ type RawFeatureDef struct {
 	Enums       []RawEnumDef      `yaml:"enums"`
 	Attributes  []RawAttributeDef `yaml:"attributes"`
 	Commands    []RawCommandDef   `yaml:"commands"`
+	Events      []RawEventDef     `yaml:"events"`
 }
 
 // RawEnumDef represents an enum type definition.
@@ -46,6 +47,7 @@ type RawAttributeDef struct {
 	MapKeyType   string           `yaml:"mapKeyType"`   // For map types: "Phase", "PhasePair"
 	MapValueType string           `yaml:"mapValueType"` // For map types: "int64", "uint32"
 	Items        *RawArrayItemDef `yaml:"items"`        // For typed array attributes
+	MaxItems     int              `yaml:"maxItems"`     // For array types: maximum length (0 = unbounded)
 	Access       string           `yaml:"access"`       // "readOnly", "readWrite"
 	Mandatory    bool             `yaml:"mandatory"`
 	Nullable     bool             `yaml:"nullable"`
@@ -66,9 +68,10 @@ type RawArrayItemDef struct {
 
 // RawArrayFieldDef describes a field within an object array item.
 type RawArrayFieldDef struct {
-	Name string `yaml:"name"`
-	Type string `yaml:"type"`
-	Enum string `yaml:"enum"`
+	Name     string `yaml:"name"`
+	Type     string `yaml:"type"`
+	Enum     string `yaml:"enum"`
+	Nullable bool   `yaml:"nullable"`
 }
 
 // RawCommandDef represents a command definition.
@@ -83,11 +86,22 @@ type RawCommandDef struct {
 
 // RawParameterDef represents a command parameter or response field.
 type RawParameterDef struct {
-	Name        string `yaml:"name"`
-	Type        string `yaml:"type"`
-	Enum        string `yaml:"enum"`
-	Required    bool   `yaml:"required"`
-	Description string `yaml:"description"`
+	Name        string           `yaml:"name"`
+	Type        string           `yaml:"type"`
+	Enum        string           `yaml:"enum"`
+	Items       *RawArrayItemDef `yaml:"items"`    // For array types; object items may reference an attribute's structName
+	MaxItems    int              `yaml:"maxItems"` // For array types: maximum length (0 = unbounded)
+	Required    bool             `yaml:"required"`
+	Nullable    bool             `yaml:"nullable"`
+	Description string           `yaml:"description"`
+}
+
+// RawEventDef represents an event definition.
+type RawEventDef struct {
+	ID          uint8             `yaml:"id"`
+	Name        string            `yaml:"name"`
+	Description string            `yaml:"description"`
+	Fields      []RawParameterDef `yaml:"fields"`
 }
 
 // ParseFeatureDef parses a feature definition from YAML bytes.
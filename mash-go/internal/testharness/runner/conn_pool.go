@@ -162,7 +162,10 @@ func (p *connPoolImpl) SendRequestWithDeadline(ctx context.Context, data []byte,
 
 		// Read frames until we get a matching response. Skip notifications
 		// (messageId=0) and discard orphaned responses from previous operations.
-		for range 10 {
+		// Chunks of the matching response are reassembled and do not count
+		// towards the interleaved frame limit.
+		chunks := wire.NewChunkAssembler()
+		for skipped := 0; skipped < 10; {
 			respData, err := p.main.framer.ReadFrame()
 			if err != nil {
 				var ne net.Error
@@ -184,16 +187,26 @@ func (p *connPoolImpl) SendRequestWithDeadline(ctx context.Context, data []byte,
 			if resp.MessageID == 0 {
 				p.debugf("sendRequest(%s): skipping notification frame (buffered)", op)
 				p.notifications = append(p.notifications, respData)
+				skipped++
 				continue
 			}
 
 			// Discard orphaned responses from previous operations.
 			if resp.MessageID != expectedMsgID {
 				p.debugf("sendRequest(%s): discarding orphaned response (got msgID=%d, want %d)", op, resp.MessageID, expectedMsgID)
+				skipped++
 				continue
 			}
 
-			return resp, nil
+			full, err := chunks.Add(resp)
+			if err != nil {
+				return nil, fmt.Errorf("failed to reassemble %s response: %w", op, err)
+			}
+			if full == nil {
+				p.debugf("sendRequest(%s): received chunk %d, waiting for more", op, resp.Chunk)
+				continue
+			}
+			return full, nil
 		}
 		if !retryOnTimeout {
 			return nil, fmt.Errorf("failed to read %s response: too many interleaved frames", op)
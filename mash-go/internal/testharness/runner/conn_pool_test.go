@@ -4,6 +4,7 @@ import (
 	"context"
 	"net"
 	"sort"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"testing"
@@ -214,6 +215,48 @@ func TestConnPool_SendRequest_DiscardsOrphanedResponses(t *testing.T) {
 	}
 }
 
+func TestConnPool_SendRequest_ReassemblesChunkedResponse(t *testing.T) {
+	pool, server := newPipedPool()
+	defer server.Close()
+
+	msgID := pool.NextMessageID()
+	value := strings.Repeat("x", 100000) // more than 10 frames at 8 KB
+
+	go func() {
+		framer := transport.NewFramer(server)
+		_, _ = framer.ReadFrame() // drain request
+
+		resp := &wire.Response{MessageID: msgID, Status: wire.StatusSuccess, Payload: map[uint16]any{1: value}}
+		frames, _ := wire.EncodeResponseChunks(resp, transport.DefaultMaxMessageSize)
+		for i, frame := range frames {
+			_ = framer.WriteFrame(frame)
+			if i == 0 {
+				// A notification interleaved between chunks.
+				notifData, _ := wire.EncodeNotification(&wire.Notification{SubscriptionID: 1, Changes: map[uint16]any{1: 1}})
+				_ = framer.WriteFrame(notifData)
+			}
+		}
+	}()
+
+	req := &wire.Request{MessageID: msgID, Operation: wire.OpRead}
+	data, _ := wire.EncodeRequest(req)
+
+	resp, err := pool.SendRequest(data, "read", msgID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.IsChunk() {
+		t.Fatal("expected the reassembled response, got a chunk")
+	}
+	payload, ok := resp.Payload.(map[any]any)
+	if !ok || payload[uint64(1)] != value {
+		t.Errorf("reassembled payload does not match")
+	}
+	if n := len(pool.PendingNotifications()); n != 1 {
+		t.Errorf("expected 1 buffered notification, got %d", n)
+	}
+}
+
 func TestConnPool_SendRequest_TooManyInterleavedFrames_ReturnsError(t *testing.T) {
 	pool, server := newPipedPool()
 	defer server.Close()
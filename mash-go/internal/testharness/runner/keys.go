@@ -279,6 +279,14 @@ const (
 	KeySubscriptionID   = "subscription_id"
 )
 
+// Multi-path output keys.
+const (
+	KeyPathCount       = "path_count"
+	KeyPathResults     = "path_results"
+	KeyPathStatuses    = "path_statuses"
+	KeyAllPathsSuccess = "all_paths_success"
+)
+
 // Utility output keys.
 const (
 	KeyWaited = "waited"
@@ -918,6 +926,7 @@ const (
 	ParamChanges        = "changes"
 	ParamEndpoints      = "endpoints"
 	ParamAttributes     = "attributes"
+	ParamPaths          = "paths"
 	ParamFaultMessage   = "fault_message"
 	ParamExpectedState  = "expected_state"
 	ParamSimulateNoResponse = "simulate_no_response"
@@ -1224,3 +1233,9 @@ const (
 const (
 	ActionTriggerTestEvent = "trigger_test_event"
 )
+
+// Multi-path actions (multipath_handlers.go).
+const (
+	ActionReadPaths      = "read_paths"
+	ActionSubscribePaths = "subscribe_paths"
+)
@@ -0,0 +1,201 @@
+package runner
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mash-protocol/mash-go/internal/testharness/engine"
+	"github.com/mash-protocol/mash-go/internal/testharness/loader"
+	"github.com/mash-protocol/mash-go/pkg/wire"
+)
+
+// registerMultiPathHandlers registers the batched Read/Subscribe handlers.
+func (r *Runner) registerMultiPathHandlers() {
+	r.engine.RegisterHandler(ActionReadPaths, r.handleReadPaths)
+	r.engine.RegisterHandler(ActionSubscribePaths, r.handleSubscribePaths)
+}
+
+// handleReadPaths sends a multi-path Read request.
+//
+// Parameters:
+//   - paths: list of {endpoint, feature, attributes} (attributes optional)
+//
+// Outputs: read_success, status, path_count, path_results (one map per path
+// with endpoint, feature, status and values), path_statuses, all_paths_success.
+func (r *Runner) handleReadPaths(ctx context.Context, step *loader.Step, state *engine.ExecutionState) (map[string]any, error) {
+	if !r.pool.Main().isConnected() {
+		return nil, fmt.Errorf("not connected")
+	}
+
+	params := engine.InterpolateParams(step.Params, state)
+	paths, err := r.resolvePaths(params[ParamPaths])
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := r.sendPathsRequest(wire.OpRead, &wire.MultiPathPayload{Paths: paths})
+	if err != nil {
+		return nil, err
+	}
+
+	outputs := pathOutputs(resp)
+	outputs[KeyReadSuccess] = resp.IsSuccess()
+	return outputs, nil
+}
+
+// handleSubscribePaths sends a multi-path Subscribe request.
+//
+// Parameters:
+//   - paths: list of {endpoint, feature, attributes} (attributes optional)
+//   - minInterval, maxInterval: seconds, as for subscribe
+//   - save_subscription_id: optional state key for the subscription ID
+//
+// Outputs: subscribe_success, subscription_id, priming_received and the
+// per-path outputs of read_paths.
+func (r *Runner) handleSubscribePaths(ctx context.Context, step *loader.Step, state *engine.ExecutionState) (map[string]any, error) {
+	if !r.pool.Main().isConnected() {
+		return nil, fmt.Errorf("not connected")
+	}
+
+	params := engine.InterpolateParams(step.Params, state)
+	paths, err := r.resolvePaths(params[ParamPaths])
+	if err != nil {
+		return nil, err
+	}
+
+	payload := &wire.MultiPathPayload{Paths: paths}
+	if _, ok := params["minInterval"]; ok {
+		payload.MinInterval = uint32(paramInt(params, "minInterval", 0) * 1000) // YAML seconds → wire milliseconds
+	}
+	if _, ok := params["maxInterval"]; ok {
+		payload.MaxInterval = uint32(paramInt(params, "maxInterval", 0) * 1000)
+	}
+
+	resp, err := r.sendPathsRequest(wire.OpSubscribe, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	outputs := pathOutputs(resp)
+	outputs[KeySubscribeSuccess] = resp.IsSuccess()
+
+	var subID uint32
+	if mr := wire.ExtractMultiPathResponse(resp.Payload); mr != nil {
+		subID = mr.SubscriptionID
+	}
+	outputs[KeySubscriptionID] = subID
+	outputs[KeyPrimingReceived] = subID != 0
+
+	// Track for auto-unsubscribe in teardown and for later unsubscribe steps.
+	if subID != 0 {
+		r.trackSubscription(subID)
+		state.Set(StateSavedSubscriptionID, subID)
+		if saveAs, ok := params[ParamSaveSubscriptionID].(string); ok && saveAs != "" {
+			state.Set(saveAs, subID)
+			outputs[KeySaveSubscriptionID] = saveAs
+		}
+	}
+
+	return outputs, nil
+}
+
+// resolvePaths converts the YAML paths parameter to wire attribute paths.
+func (r *Runner) resolvePaths(raw any) ([]wire.AttributePath, error) {
+	list, ok := raw.([]any)
+	if !ok || len(list) == 0 {
+		return nil, fmt.Errorf("%s must be a non-empty list", ParamPaths)
+	}
+
+	paths := make([]wire.AttributePath, 0, len(list))
+	for i, item := range list {
+		entry, ok := item.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("path %d: expected a map, got %T", i, item)
+		}
+
+		endpointID, err := r.resolver.ResolveEndpoint(entry[KeyEndpoint])
+		if err != nil {
+			return nil, fmt.Errorf("path %d: resolving endpoint: %w", i, err)
+		}
+		featureID, err := r.resolver.ResolveFeature(entry[KeyFeature])
+		if err != nil {
+			return nil, fmt.Errorf("path %d: resolving feature: %w", i, err)
+		}
+
+		path := wire.AttributePath{EndpointID: endpointID, FeatureID: featureID}
+		attrs, _ := entry[ParamAttributes].([]any)
+		for _, a := range attrs {
+			attrID, err := r.resolver.ResolveAttribute(entry[KeyFeature], a)
+			if err != nil {
+				return nil, fmt.Errorf("path %d: resolving attribute: %w", i, err)
+			}
+			path.AttributeIDs = append(path.AttributeIDs, attrID)
+		}
+		paths = append(paths, path)
+	}
+	return paths, nil
+}
+
+// sendPathsRequest encodes and sends a multi-path request on the main
+// connection.
+func (r *Runner) sendPathsRequest(op wire.Operation, payload *wire.MultiPathPayload) (*wire.Response, error) {
+	req := &wire.Request{
+		MessageID:  r.nextMessageID(),
+		Operation:  op,
+		EndpointID: wire.MultiPathEndpointID,
+		FeatureID:  wire.MultiPathFeatureID,
+		Payload:    payload,
+	}
+
+	data, err := wire.EncodeRequest(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode %s paths request: %w", op, err)
+	}
+	return r.sendRequest(data, op.String(), req.MessageID)
+}
+
+// pathOutputs builds the per-path outputs shared by read_paths and
+// subscribe_paths. Attribute values are keyed by decimal attribute ID.
+func pathOutputs(resp *wire.Response) map[string]any {
+	outputs := map[string]any{
+		KeyResponse: resp,
+		KeyStatus:   resp.Status,
+	}
+	if !resp.IsSuccess() {
+		outputs[KeyErrorCode] = resp.Status.String()
+		outputs[KeyAllPathsSuccess] = false
+		return outputs
+	}
+
+	mr := wire.ExtractMultiPathResponse(resp.Payload)
+	if mr == nil {
+		outputs[KeyAllPathsSuccess] = false
+		return outputs
+	}
+
+	results := make([]any, len(mr.Results))
+	statuses := make([]any, len(mr.Results))
+	allSuccess := true
+	for i, res := range mr.Results {
+		values := make(map[string]any, len(res.Values))
+		for id, v := range res.Values {
+			values[fmt.Sprintf("%d", id)] = v
+		}
+		results[i] = map[string]any{
+			KeyEndpoint: res.EndpointID,
+			KeyFeature:  res.FeatureID,
+			KeyStatus:   res.Status.String(),
+			KeyValue:    values,
+		}
+		statuses[i] = res.Status.String()
+		if res.Status != wire.StatusSuccess {
+			allSuccess = false
+		}
+	}
+
+	outputs[KeyPathCount] = len(mr.Results)
+	outputs[KeyPathResults] = results
+	outputs[KeyPathStatuses] = statuses
+	outputs[KeyAllPathsSuccess] = allSuccess
+	return outputs
+}
@@ -0,0 +1,136 @@
+package runner
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mash-protocol/mash-go/internal/testharness/loader"
+	"github.com/mash-protocol/mash-go/pkg/transport"
+	"github.com/mash-protocol/mash-go/pkg/wire"
+)
+
+// serveMultiPath answers one multi-path request on the server side with a
+// success result for endpoint 0 and INVALID_ENDPOINT for any other.
+func serveMultiPath(framer *transport.Framer, gotReq chan<- *wire.Request) {
+	reqData, err := framer.ReadFrame()
+	if err != nil {
+		return
+	}
+	req, err := wire.DecodeRequest(reqData)
+	if err != nil {
+		return
+	}
+	gotReq <- req
+
+	mp := wire.ExtractMultiPathPayload(req.Payload)
+	results := make([]wire.PathResult, len(mp.Paths))
+	for i, p := range mp.Paths {
+		results[i] = wire.PathResult{EndpointID: p.EndpointID, FeatureID: p.FeatureID, Status: wire.StatusInvalidEndpoint}
+		if p.EndpointID == 0 {
+			results[i].Status = wire.StatusSuccess
+			results[i].Values = map[uint16]any{1: "test-vendor"}
+		}
+	}
+	var subID uint32
+	if req.Operation == wire.OpSubscribe {
+		subID = 7
+	}
+	resp := &wire.Response{
+		MessageID: req.MessageID,
+		Status:    wire.StatusSuccess,
+		Payload:   &wire.MultiPathResponsePayload{SubscriptionID: subID, Results: results},
+	}
+	respData, _ := wire.EncodeResponse(resp)
+	_ = framer.WriteFrame(respData)
+}
+
+func TestHandleReadPaths(t *testing.T) {
+	r, server := newPipedRunner()
+	defer server.Close()
+	r.resolver = NewResolver()
+	state := newTestState()
+
+	gotReq := make(chan *wire.Request, 1)
+	go serveMultiPath(transport.NewFramer(server), gotReq)
+
+	step := &loader.Step{Params: map[string]any{
+		"paths": []any{
+			map[string]any{"endpoint": float64(0), "feature": "DeviceInfo", "attributes": []any{"vendorName"}},
+			map[string]any{"endpoint": float64(9), "feature": "Measurement"},
+		},
+	}}
+	out, err := r.handleReadPaths(context.Background(), step, state)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	req := <-gotReq
+	if !req.IsMultiPath() || req.Operation != wire.OpRead {
+		t.Errorf("expected multi-path Read, got op=%v ep=%d feat=%d", req.Operation, req.EndpointID, req.FeatureID)
+	}
+	if mp := wire.ExtractMultiPathPayload(req.Payload); len(mp.Paths) != 2 || len(mp.Paths[0].AttributeIDs) != 1 {
+		t.Errorf("unexpected paths: %+v", mp)
+	}
+
+	if out[KeyReadSuccess] != true {
+		t.Errorf("expected read_success=true, got %v", out[KeyReadSuccess])
+	}
+	if out[KeyPathCount] != 2 {
+		t.Errorf("expected path_count=2, got %v", out[KeyPathCount])
+	}
+	if out[KeyAllPathsSuccess] != false {
+		t.Errorf("expected all_paths_success=false, got %v", out[KeyAllPathsSuccess])
+	}
+	statuses, _ := out[KeyPathStatuses].([]any)
+	if len(statuses) != 2 || statuses[1] != "INVALID_ENDPOINT" {
+		t.Errorf("unexpected path_statuses: %v", out[KeyPathStatuses])
+	}
+}
+
+func TestHandleSubscribePaths(t *testing.T) {
+	r, server := newPipedRunner()
+	defer server.Close()
+	r.resolver = NewResolver()
+	state := newTestState()
+
+	gotReq := make(chan *wire.Request, 1)
+	go serveMultiPath(transport.NewFramer(server), gotReq)
+
+	step := &loader.Step{Params: map[string]any{
+		"paths": []any{
+			map[string]any{"endpoint": float64(0), "feature": "DeviceInfo"},
+		},
+		"minInterval":          float64(2),
+		"save_subscription_id": "multi_sub",
+	}}
+	out, err := r.handleSubscribePaths(context.Background(), step, state)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	req := <-gotReq
+	if mp := wire.ExtractMultiPathPayload(req.Payload); mp.MinInterval != 2000 {
+		t.Errorf("expected minInterval 2000ms, got %d", mp.MinInterval)
+	}
+
+	if out[KeySubscribeSuccess] != true || out[KeyPrimingReceived] != true {
+		t.Errorf("expected subscribe_success and priming_received, got %v", out)
+	}
+	if out[KeySubscriptionID] != uint32(7) {
+		t.Errorf("expected subscription_id=7, got %v", out[KeySubscriptionID])
+	}
+	if v, _ := state.Get("multi_sub"); v != uint32(7) {
+		t.Errorf("expected subscription ID saved as multi_sub, got %v", v)
+	}
+}
+
+func TestHandleReadPaths_InvalidParams(t *testing.T) {
+	r, server := newPipedRunner()
+	defer server.Close()
+	r.resolver = NewResolver()
+
+	step := &loader.Step{Params: map[string]any{"paths": []any{}}}
+	if _, err := r.handleReadPaths(context.Background(), step, newTestState()); err == nil {
+		t.Error("expected error for empty paths")
+	}
+}
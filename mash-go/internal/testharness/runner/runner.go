@@ -712,6 +712,7 @@ func (r *Runner) registerHandlers() {
 	r.registerConnectionHandlers()
 	r.registerCertHandlers()
 	r.registerNetworkHandlers()
+	r.registerMultiPathHandlers()
 }
 
 // handleConnect establishes a connection to the target.
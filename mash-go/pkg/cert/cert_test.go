@@ -253,6 +253,29 @@ func TestOperationalCertExpiry(t *testing.T) {
 		}
 	})
 
+	t.Run("AtTimes", func(t *testing.T) {
+		notAfter := opCert.ExpiresAt()
+
+		if opCert.NeedsRenewalAt(notAfter.Add(-RenewalWindow - time.Hour)) {
+			t.Error("should not need renewal before the renewal window")
+		}
+		if !opCert.NeedsRenewalAt(notAfter.Add(-RenewalWindow + time.Hour)) {
+			t.Error("should need renewal inside the renewal window")
+		}
+		if opCert.IsExpiredAt(notAfter.Add(-time.Second)) {
+			t.Error("should not be expired before NotAfter")
+		}
+		if !opCert.IsExpiredAt(notAfter.Add(time.Second)) {
+			t.Error("should be expired after NotAfter")
+		}
+		if !opCert.IsInGracePeriodAt(notAfter.Add(GracePeriod - time.Hour)) {
+			t.Error("should be in grace period shortly after expiry")
+		}
+		if opCert.IsInGracePeriodAt(notAfter.Add(GracePeriod + time.Hour)) {
+			t.Error("should not be in grace period once it has elapsed")
+		}
+	})
+
 	t.Run("SKI", func(t *testing.T) {
 		ski := opCert.SKI()
 		if len(ski) == 0 {
@@ -392,12 +415,13 @@ func TestGenerateControllerOperationalCertSubject(t *testing.T) {
 		t.Errorf("Subject.Organization = %v, want [\"MASH Controller\"]", cert.Subject.Organization)
 	}
 
-	// Verify OU contains zone type and zone ID (order may vary due to ASN.1 encoding)
-	if len(cert.Subject.OrganizationalUnit) != 2 {
-		t.Fatalf("Subject.OrganizationalUnit has %d elements, want 2", len(cert.Subject.OrganizationalUnit))
+	// Verify OU contains zone type, zone ID and role (order may vary due to ASN.1 encoding)
+	if len(cert.Subject.OrganizationalUnit) != 3 {
+		t.Fatalf("Subject.OrganizationalUnit has %d elements, want 3", len(cert.Subject.OrganizationalUnit))
 	}
 	hasZoneType := false
 	hasZoneID := false
+	hasRole := false
 	for _, ou := range cert.Subject.OrganizationalUnit {
 		if ou == "LOCAL" {
 			hasZoneType = true
@@ -405,6 +429,9 @@ func TestGenerateControllerOperationalCertSubject(t *testing.T) {
 		if ou == "home-ems" {
 			hasZoneID = true
 		}
+		if ou == "OWNER" {
+			hasRole = true
+		}
 	}
 	if !hasZoneType {
 		t.Errorf("Subject.OrganizationalUnit should contain zone type, got %v", cert.Subject.OrganizationalUnit)
@@ -412,6 +439,9 @@ func TestGenerateControllerOperationalCertSubject(t *testing.T) {
 	if !hasZoneID {
 		t.Errorf("Subject.OrganizationalUnit should contain zone ID, got %v", cert.Subject.OrganizationalUnit)
 	}
+	if !hasRole {
+		t.Errorf("Subject.OrganizationalUnit should contain role, got %v", cert.Subject.OrganizationalUnit)
+	}
 }
 
 // TC-IMPL-CERT-GEN-003: Controller Cert Uses Fresh Key Pair
@@ -0,0 +1,113 @@
+package cert
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrInvalidDelegation is returned when a delegated signing request is not
+// signed by the admin certificate it carries.
+var ErrInvalidDelegation = errors.New("invalid delegated signing request")
+
+// delegationContext separates delegated signing signatures from any other
+// use of an admin key.
+const delegationContext = "MASH delegated CSR v1"
+
+// DelegatedSigningRequest asks a zone owner to sign a device CSR that a zone
+// admin obtained while commissioning the device (multi-zone.md §4.3). The
+// admin signs the CSR with its admin key so the owner can check who is
+// adding the device.
+type DelegatedSigningRequest struct {
+	// AdminCert is the admin's certificate (DER), issued by the Zone CA.
+	AdminCert []byte `cbor:"1,keyasint"`
+
+	// CSR is the device's PKCS#10 CSR (DER).
+	CSR []byte `cbor:"2,keyasint"`
+
+	// Signature is the admin key's ECDSA signature over the CSR.
+	Signature []byte `cbor:"3,keyasint"`
+}
+
+// DelegatedSigningResponse carries the device certificate issued by the
+// zone owner.
+type DelegatedSigningResponse struct {
+	// Certificate is the device's operational certificate (DER).
+	Certificate []byte `cbor:"1,keyasint"`
+
+	// ZoneCA is the Zone CA certificate (DER) that issued it.
+	ZoneCA []byte `cbor:"2,keyasint"`
+}
+
+// NewDelegatedSigningRequest builds a request for csrDER signed with the
+// admin's operational key.
+func NewDelegatedSigningRequest(admin *OperationalCert, csrDER []byte) (*DelegatedSigningRequest, error) {
+	if admin == nil || admin.Certificate == nil || admin.PrivateKey == nil {
+		return nil, ErrInvalidCert
+	}
+	if len(csrDER) == 0 {
+		return nil, fmt.Errorf("CSR is required")
+	}
+
+	digest := delegationDigest(csrDER)
+	sig, err := admin.PrivateKey.Sign(rand.Reader, digest, crypto.SHA256)
+	if err != nil {
+		return nil, fmt.Errorf("sign request: %w", err)
+	}
+
+	return &DelegatedSigningRequest{
+		AdminCert: admin.Certificate.Raw,
+		CSR:       csrDER,
+		Signature: sig,
+	}, nil
+}
+
+// VerifyDelegatedSigningRequest checks that req was signed by a valid admin
+// certificate issued by zoneCA and returns that certificate. Owner
+// certificates are accepted too, so an owner can sign on its own behalf.
+func VerifyDelegatedSigningRequest(req *DelegatedSigningRequest, zoneCA *x509.Certificate, now time.Time) (*x509.Certificate, error) {
+	if req == nil || zoneCA == nil {
+		return nil, ErrInvalidDelegation
+	}
+
+	adminCert, err := x509.ParseCertificate(req.AdminCert)
+	if err != nil {
+		return nil, fmt.Errorf("%w: parse admin certificate: %v", ErrInvalidDelegation, err)
+	}
+
+	roots := x509.NewCertPool()
+	roots.AddCert(zoneCA)
+	if _, err := adminCert.Verify(x509.VerifyOptions{
+		Roots:       roots,
+		CurrentTime: now,
+		KeyUsages:   []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidChain, err)
+	}
+
+	if role := CertRole(adminCert); role != RoleAdmin && role != RoleOwner {
+		return nil, ErrNotAdmin
+	}
+
+	pub, ok := adminCert.PublicKey.(*ecdsa.PublicKey)
+	if !ok {
+		return nil, ErrUnsupportedEC
+	}
+	if !ecdsa.VerifyASN1(pub, delegationDigest(req.CSR), req.Signature) {
+		return nil, ErrInvalidDelegation
+	}
+
+	return adminCert, nil
+}
+
+func delegationDigest(csrDER []byte) []byte {
+	h := sha256.New()
+	h.Write([]byte(delegationContext))
+	h.Write(csrDER)
+	return h.Sum(nil)
+}
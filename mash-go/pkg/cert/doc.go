@@ -16,6 +16,15 @@
 //   - Devices generate key pairs and CSRs using [GenerateKeyPair] and [CreateCSR]
 //   - Zone owners sign CSRs using [SignCSR] to issue operational certificates
 //   - Certificates are stored using the [Store] interface
+//   - Private keys are used as [crypto.Signer] and persisted through a [KeyStore]:
+//     plaintext or passphrase-encrypted PEM files ([FileKeyStore]) or a separate
+//     signing process ([RemoteKeyStore], [SignerServer])
+//   - A Zone CA is replaced by cross-signing its successor with [CrossSignZoneCA];
+//     devices check the cross-certificate with [VerifyZoneCACrossSign] and trust
+//     both CAs until the old one is retired
+//   - Controller certificates carry a zone role ([CertRole]). Owners issue admin
+//     certificates with [SignAdminCSR]; admins have device CSRs signed by the
+//     owner with a [DelegatedSigningRequest]
 //
 // Certificate identification uses the Subject Key Identifier (SKI), which is
 // derived from the public key and serves as the device's unique identifier
@@ -1,6 +1,7 @@
 package cert
 
 import (
+	"crypto"
 	"crypto/ecdsa"
 	"crypto/elliptic"
 	"crypto/rand"
@@ -71,8 +72,19 @@ func GenerateZoneCA(zoneID string, zoneType ZoneType) (*ZoneCA, error) {
 	if err != nil {
 		return nil, fmt.Errorf("generate key pair: %w", err)
 	}
+	return NewZoneCA(zoneID, zoneType, keyPair.PrivateKey)
+}
+
+// NewZoneCA creates a Zone Certificate Authority whose certificate is
+// self-signed by signer. The signer must hold a P-256 ECDSA key; it may be
+// backed by a KeyStore that never exposes the key material.
+func NewZoneCA(zoneID string, zoneType ZoneType, signer crypto.Signer) (*ZoneCA, error) {
+	pub, err := signerPublicKey(signer)
+	if err != nil {
+		return nil, err
+	}
 
-	ski, err := ComputeSKI(keyPair.PublicKey)
+	ski, err := ComputeSKI(pub)
 	if err != nil {
 		return nil, fmt.Errorf("compute SKI: %w", err)
 	}
@@ -105,7 +117,7 @@ func GenerateZoneCA(zoneID string, zoneType ZoneType) (*ZoneCA, error) {
 	}
 
 	// Self-sign the CA certificate
-	certDER, err := x509.CreateCertificate(rand.Reader, template, template, keyPair.PublicKey, keyPair.PrivateKey)
+	certDER, err := x509.CreateCertificate(rand.Reader, template, template, pub, signer)
 	if err != nil {
 		return nil, fmt.Errorf("create certificate: %w", err)
 	}
@@ -117,7 +129,7 @@ func GenerateZoneCA(zoneID string, zoneType ZoneType) (*ZoneCA, error) {
 
 	return &ZoneCA{
 		Certificate: cert,
-		PrivateKey:  keyPair.PrivateKey,
+		PrivateKey:  signer,
 		ZoneID:      zoneID,
 		ZoneType:    zoneType,
 	}, nil
@@ -170,7 +182,9 @@ func SignCSR(ca *ZoneCA, csrDER []byte) (*x509.Certificate, error) {
 // This follows the Matter pattern where the commissioner assigns the Node ID and embeds it
 // in the certificate subject. The device ID becomes the CommonName in the certificate subject.
 //
-// If deviceID is empty, the CSR's subject is used as-is (backward compatibility).
+// The issued subject carries only the CommonName: the CSR's Organization and
+// OrganizationalUnit are ignored so a CSR cannot claim a controller role. If
+// deviceID is empty, the CSR's CommonName is used (backward compatibility).
 func SignCSRWithDeviceID(ca *ZoneCA, csrDER []byte, deviceID string) (*x509.Certificate, error) {
 	if ca == nil || ca.PrivateKey == nil || ca.Certificate == nil {
 		return nil, fmt.Errorf("valid Zone CA is required")
@@ -201,12 +215,13 @@ func SignCSRWithDeviceID(ca *ZoneCA, csrDER []byte, deviceID string) (*x509.Cert
 		return nil, fmt.Errorf("generate serial number: %w", err)
 	}
 
-	// Build subject - either from CSR or with controller-assigned device ID
-	subject := csr.Subject
+	// Build subject from the CommonName only - either from the CSR or the
+	// controller-assigned device ID (Matter-style)
+	commonName := csr.Subject.CommonName
 	if deviceID != "" {
-		// Controller-assigned device ID replaces CommonName (Matter-style)
-		subject.CommonName = deviceID
+		commonName = deviceID
 	}
+	subject := pkix.Name{CommonName: commonName}
 
 	now := time.Now()
 
@@ -251,7 +266,19 @@ func SignCSRWithDeviceID(ca *ZoneCA, csrDER []byte, deviceID string) (*x509.Cert
 // This is signed by the Zone CA and used for mutual TLS authentication with devices.
 // Unlike device certificates (which are issued via CSR during commissioning),
 // controller certificates are generated directly by the controller that owns the Zone CA.
+// The certificate carries the zone owner role; admins obtain theirs via SignAdminCSR.
 func GenerateControllerOperationalCert(ca *ZoneCA, controllerID string) (*OperationalCert, error) {
+	// Generate a fresh key pair for this certificate
+	keyPair, err := GenerateKeyPair()
+	if err != nil {
+		return nil, fmt.Errorf("generate key pair: %w", err)
+	}
+	return NewControllerOperationalCert(ca, controllerID, keyPair.PrivateKey)
+}
+
+// NewControllerOperationalCert is like GenerateControllerOperationalCert but
+// issues the certificate for an existing key, e.g. one created in a KeyStore.
+func NewControllerOperationalCert(ca *ZoneCA, controllerID string, key crypto.Signer) (*OperationalCert, error) {
 	if ca == nil || ca.PrivateKey == nil || ca.Certificate == nil {
 		return nil, fmt.Errorf("valid Zone CA is required")
 	}
@@ -259,13 +286,38 @@ func GenerateControllerOperationalCert(ca *ZoneCA, controllerID string) (*Operat
 		return nil, fmt.Errorf("controller ID is required")
 	}
 
-	// Generate a fresh key pair for this certificate
-	keyPair, err := GenerateKeyPair()
+	pub, err := signerPublicKey(key)
 	if err != nil {
-		return nil, fmt.Errorf("generate key pair: %w", err)
+		return nil, err
 	}
 
-	ski, err := ComputeSKI(keyPair.PublicKey)
+	cert, err := issueControllerCert(ca, controllerID, pub, RoleOwner)
+	if err != nil {
+		return nil, err
+	}
+
+	return &OperationalCert{
+		Certificate: cert,
+		PrivateKey:  key,
+		ZoneID:      ca.ZoneID,
+		ZoneType:    ca.ZoneType,
+		ZoneCACert:  ca.Certificate,
+	}, nil
+}
+
+// issueControllerCert signs a controller operational certificate for pub
+// with the given zone role.
+func issueControllerCert(ca *ZoneCA, controllerID string, pub any, role Role) (*x509.Certificate, error) {
+	if ca == nil || ca.PrivateKey == nil || ca.Certificate == nil {
+		return nil, fmt.Errorf("valid Zone CA is required")
+	}
+
+	ecPub, ok := pub.(*ecdsa.PublicKey)
+	if !ok || ecPub.Curve != elliptic.P256() {
+		return nil, ErrUnsupportedEC
+	}
+
+	ski, err := ComputeSKI(ecPub)
 	if err != nil {
 		return nil, fmt.Errorf("compute SKI: %w", err)
 	}
@@ -280,10 +332,11 @@ func GenerateControllerOperationalCert(ca *ZoneCA, controllerID string) (*Operat
 		SerialNumber: serialNumber,
 		Subject: pkix.Name{
 			CommonName:   controllerID,
-			Organization: []string{"MASH Controller"},
+			Organization: []string{controllerOrganization},
 			OrganizationalUnit: []string{
 				ca.ZoneType.String(),
 				ca.ZoneID,
+				role.String(),
 			},
 		},
 		NotBefore:             now,
@@ -297,23 +350,24 @@ func GenerateControllerOperationalCert(ca *ZoneCA, controllerID string) (*Operat
 		DNSNames:              []string{controllerID}, // Required for Go TLS verification
 	}
 
-	certDER, err := x509.CreateCertificate(rand.Reader, template, ca.Certificate, keyPair.PublicKey, ca.PrivateKey)
+	certDER, err := x509.CreateCertificate(rand.Reader, template, ca.Certificate, ecPub, ca.PrivateKey)
 	if err != nil {
 		return nil, fmt.Errorf("create certificate: %w", err)
 	}
 
-	cert, err := x509.ParseCertificate(certDER)
-	if err != nil {
-		return nil, fmt.Errorf("parse certificate: %w", err)
-	}
+	return x509.ParseCertificate(certDER)
+}
 
-	return &OperationalCert{
-		Certificate: cert,
-		PrivateKey:  keyPair.PrivateKey,
-		ZoneID:      ca.ZoneID,
-		ZoneType:    ca.ZoneType,
-		ZoneCACert:  ca.Certificate,
-	}, nil
+// signerPublicKey returns the P-256 ECDSA public key of signer.
+func signerPublicKey(signer crypto.Signer) (*ecdsa.PublicKey, error) {
+	if signer == nil {
+		return nil, fmt.Errorf("signer is required")
+	}
+	pub, ok := signer.Public().(*ecdsa.PublicKey)
+	if !ok || pub.Curve != elliptic.P256() {
+		return nil, ErrUnsupportedEC
+	}
+	return pub, nil
 }
 
 // generateSerialNumber generates a random serial number for certificates.
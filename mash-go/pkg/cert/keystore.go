@@ -0,0 +1,249 @@
+package cert
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"encoding/pem"
+	"errors"
+	"os"
+	"path/filepath"
+)
+
+// Key store errors.
+var (
+	ErrKeyNotExportable      = errors.New("private key cannot be exported")
+	ErrKeyImportNotSupported = errors.New("key store does not support importing keys")
+	ErrKeyExists             = errors.New("key already exists")
+)
+
+// Names under which certificate stores keep their private keys. Names are
+// slash-separated paths relative to the store.
+const (
+	ZoneCAKeyName     = controllerDir + "/" + zoneCAKeyFile
+	ControllerKeyName = controllerDir + "/" + controllerKeyFile
+
+	// ControllerNextKeyName is the alternate name of the controller key,
+	// see NextControllerKeyName.
+	ControllerNextKeyName = controllerDir + "/" + controllerNextKeyFile
+)
+
+// NextControllerKeyName returns the key name to generate a replacement for
+// the controller key of current under. Renewals alternate between
+// ControllerKeyName and ControllerNextKeyName so the key in use is never
+// replaced before the new certificate is saved.
+func NextControllerKeyName(current *OperationalCert) string {
+	if current == nil {
+		return ControllerKeyName
+	}
+	if current.KeyName == "" || current.KeyName == ControllerKeyName {
+		return ControllerNextKeyName
+	}
+	return ControllerKeyName
+}
+
+// OperationalKeyName returns the key name for a device's operational key
+// in a zone.
+func OperationalKeyName(zoneID string) string {
+	return "zones/" + zoneID + "/operational.key"
+}
+
+// KeyStore holds private keys by name and hands them out as crypto.Signer.
+// Implementations may keep keys in files, in a separate signing process or
+// in hardware; callers only ever sign through the returned signer.
+type KeyStore interface {
+	// GenerateKey creates a new P-256 key to be stored under name. Stores
+	// that keep keys outside the process persist it immediately and return
+	// ErrKeyExists if name is taken (see KeyReplacer); file stores persist
+	// it on the next SaveKey.
+	GenerateKey(name string) (crypto.Signer, error)
+
+	// SaveKey stores key under name. File stores return
+	// ErrKeyNotExportable for signers whose key material they cannot read;
+	// stores that keep keys elsewhere return ErrKeyImportNotSupported for
+	// keys they did not generate.
+	SaveKey(name string, key crypto.Signer) error
+
+	// LoadKey returns the signer for the key stored under name. A missing
+	// key is reported with an error wrapping os.ErrNotExist.
+	LoadKey(name string) (crypto.Signer, error)
+
+	// DeleteKey removes the key stored under name. Deleting a missing key
+	// is not an error.
+	DeleteKey(name string) error
+}
+
+// KeyStoreProvider is implemented by certificate stores that keep their
+// private keys in a KeyStore. Services use it to generate new keys in the
+// same place the store will persist them.
+type KeyStoreProvider interface {
+	KeyStore() KeyStore
+}
+
+// KeyReplacer is implemented by key stores whose GenerateKey refuses to
+// overwrite an existing key. ReplaceKey generates a key under name whether
+// or not one exists.
+type KeyReplacer interface {
+	ReplaceKey(name string) (crypto.Signer, error)
+}
+
+// GenerateKey generates a P-256 signer in ks, or a software key if ks is nil.
+func GenerateKey(ks KeyStore, name string) (crypto.Signer, error) {
+	if ks == nil {
+		kp, err := GenerateKeyPair()
+		if err != nil {
+			return nil, err
+		}
+		return kp.PrivateKey, nil
+	}
+	return ks.GenerateKey(name)
+}
+
+// ReplaceKey is GenerateKey for keys that are meant to be replaced, such as
+// the controller key on renewal.
+func ReplaceKey(ks KeyStore, name string) (crypto.Signer, error) {
+	if r, ok := ks.(KeyReplacer); ok {
+		return r.ReplaceKey(name)
+	}
+	return GenerateKey(ks, name)
+}
+
+// FileKeyStore stores ECDSA keys as PEM files under a directory. Without a
+// passphrase keys are written as plain "EC PRIVATE KEY" PEM; with one they
+// are written as scrypt-encrypted PKCS#8.
+type FileKeyStore struct {
+	dir        string
+	passphrase []byte
+}
+
+// NewFileKeyStore creates a key store that writes plaintext PEM keys.
+func NewFileKeyStore(dir string) *FileKeyStore {
+	return &FileKeyStore{dir: dir}
+}
+
+// NewEncryptedFileKeyStore creates a key store that encrypts keys with
+// passphrase. Plaintext keys written by an earlier NewFileKeyStore are still
+// loaded and are encrypted the next time they are saved.
+func NewEncryptedFileKeyStore(dir string, passphrase []byte) *FileKeyStore {
+	return &FileKeyStore{dir: dir, passphrase: append([]byte(nil), passphrase...)}
+}
+
+// Encrypted returns true if keys are written encrypted.
+func (ks *FileKeyStore) Encrypted() bool {
+	return len(ks.passphrase) > 0
+}
+
+// GenerateKey creates a software P-256 key. It is written on SaveKey.
+func (ks *FileKeyStore) GenerateKey(name string) (crypto.Signer, error) {
+	return GenerateKey(nil, name)
+}
+
+// SaveKey writes key to its file with 0600 permissions. The file is
+// replaced atomically, so a failed write leaves the previous key intact.
+func (ks *FileKeyStore) SaveKey(name string, key crypto.Signer) error {
+	ecKey, ok := key.(*ecdsa.PrivateKey)
+	if !ok {
+		return ErrKeyNotExportable
+	}
+
+	var data []byte
+	var err error
+	if ks.Encrypted() {
+		data, err = EncodeEncryptedKeyPEM(ecKey, ks.passphrase)
+	} else {
+		data, err = EncodeKeyPEM(ecKey)
+	}
+	if err != nil {
+		return err
+	}
+
+	path := ks.path(name)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return writeKeyFile(path, data)
+}
+
+// writeKeyFile writes data to path with 0600 permissions via a synced
+// temporary file in the same directory and a rename.
+func writeKeyFile(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tmp-"+filepath.Base(path)+"-*")
+	if err != nil {
+		return err
+	}
+	tmpName := tmp.Name()
+	committed := false
+	defer func() {
+		if !committed {
+			_ = os.Remove(tmpName)
+		}
+	}()
+
+	// CreateTemp already uses 0600
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmpName, path); err != nil {
+		return err
+	}
+	committed = true
+	return nil
+}
+
+// LoadKey reads and, if needed, decrypts the key file.
+func (ks *FileKeyStore) LoadKey(name string) (crypto.Signer, error) {
+	data, err := os.ReadFile(ks.path(name))
+	if err != nil {
+		return nil, err
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, ErrInvalidPEM
+	}
+	if block.Type == encryptedKeyPEMType {
+		if !ks.Encrypted() {
+			return nil, ErrIncorrectPassphrase
+		}
+		return DecodeEncryptedKeyPEM(data, ks.passphrase)
+	}
+	return DecodeKeyPEM(data)
+}
+
+// DeleteKey removes the key file.
+func (ks *FileKeyStore) DeleteKey(name string) error {
+	err := os.Remove(ks.path(name))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
+
+func (ks *FileKeyStore) path(name string) string {
+	return filepath.Join(ks.dir, filepath.FromSlash(name))
+}
+
+// ReadPassphraseFile reads a key passphrase from path, dropping a trailing
+// newline. An empty passphrase is rejected.
+func ReadPassphraseFile(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	data = bytes.TrimRight(data, "\r\n")
+	if len(data) == 0 {
+		return nil, errors.New("passphrase file is empty")
+	}
+	return data, nil
+}
+
+// Verify FileKeyStore implements KeyStore.
+var _ KeyStore = (*FileKeyStore)(nil)
@@ -0,0 +1,224 @@
+package cert
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/pem"
+	"errors"
+	"fmt"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+// Encrypted key errors.
+var (
+	ErrIncorrectPassphrase  = errors.New("incorrect passphrase")
+	ErrUnsupportedKeyCipher = errors.New("unsupported encrypted key algorithm")
+)
+
+// PEM block type for PKCS#8 EncryptedPrivateKeyInfo (RFC 5958).
+const encryptedKeyPEMType = "ENCRYPTED PRIVATE KEY"
+
+// scrypt parameters for key encryption. N=2^14, r=8 keeps the work factor
+// within OpenSSL's default memory limit so keys can be inspected with
+// `openssl pkey`.
+const (
+	scryptCost        = 1 << 14
+	scryptBlockSize   = 8
+	scryptParallelism = 1
+	scryptSaltSize    = 16
+	aes256KeySize     = 32
+)
+
+// Algorithm identifiers (RFC 8018, RFC 7914, NIST CSOR).
+var (
+	oidPBES2     = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 5, 13}
+	oidScrypt    = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 11591, 4, 11}
+	oidAES256CBC = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 42}
+)
+
+type encryptedPrivateKeyInfo struct {
+	Algorithm     pkix.AlgorithmIdentifier
+	EncryptedData []byte
+}
+
+type pbes2Params struct {
+	KeyDerivationFunc pkix.AlgorithmIdentifier
+	EncryptionScheme  pkix.AlgorithmIdentifier
+}
+
+type scryptParams struct {
+	Salt            []byte
+	CostParameter   int
+	BlockSize       int
+	Parallelization int
+	KeyLength       int `asn1:"optional"`
+}
+
+// EncodeEncryptedKeyPEM encrypts an ECDSA private key with passphrase and
+// encodes it as a PKCS#8 "ENCRYPTED PRIVATE KEY" PEM block. The key is
+// derived with scrypt and encrypted with AES-256-CBC (PBES2).
+func EncodeEncryptedKeyPEM(key *ecdsa.PrivateKey, passphrase []byte) ([]byte, error) {
+	plain, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return nil, err
+	}
+
+	salt := make([]byte, scryptSaltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	iv := make([]byte, aes.BlockSize)
+	if _, err := rand.Read(iv); err != nil {
+		return nil, err
+	}
+
+	kek, err := scrypt.Key(passphrase, salt, scryptCost, scryptBlockSize, scryptParallelism, aes256KeySize)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(kek)
+	if err != nil {
+		return nil, err
+	}
+
+	padded := pkcs7Pad(plain, aes.BlockSize)
+	encrypted := make([]byte, len(padded))
+	cipher.NewCBCEncrypter(block, iv).CryptBlocks(encrypted, padded)
+
+	kdfParams, err := asn1.Marshal(scryptParams{
+		Salt:            salt,
+		CostParameter:   scryptCost,
+		BlockSize:       scryptBlockSize,
+		Parallelization: scryptParallelism,
+		KeyLength:       aes256KeySize,
+	})
+	if err != nil {
+		return nil, err
+	}
+	ivParam, err := asn1.Marshal(iv)
+	if err != nil {
+		return nil, err
+	}
+	schemeParams, err := asn1.Marshal(pbes2Params{
+		KeyDerivationFunc: pkix.AlgorithmIdentifier{
+			Algorithm:  oidScrypt,
+			Parameters: asn1.RawValue{FullBytes: kdfParams},
+		},
+		EncryptionScheme: pkix.AlgorithmIdentifier{
+			Algorithm:  oidAES256CBC,
+			Parameters: asn1.RawValue{FullBytes: ivParam},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	der, err := asn1.Marshal(encryptedPrivateKeyInfo{
+		Algorithm: pkix.AlgorithmIdentifier{
+			Algorithm:  oidPBES2,
+			Parameters: asn1.RawValue{FullBytes: schemeParams},
+		},
+		EncryptedData: encrypted,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return pem.EncodeToMemory(&pem.Block{Type: encryptedKeyPEMType, Bytes: der}), nil
+}
+
+// DecodeEncryptedKeyPEM decrypts a PKCS#8 "ENCRYPTED PRIVATE KEY" PEM block
+// produced by EncodeEncryptedKeyPEM (or `openssl pkcs8 -topk8 -scrypt`).
+// Returns ErrIncorrectPassphrase if the passphrase does not match.
+func DecodeEncryptedKeyPEM(data, passphrase []byte) (*ecdsa.PrivateKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil || block.Type != encryptedKeyPEMType {
+		return nil, ErrInvalidPEM
+	}
+
+	var info encryptedPrivateKeyInfo
+	if _, err := asn1.Unmarshal(block.Bytes, &info); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidKey, err)
+	}
+	if !info.Algorithm.Algorithm.Equal(oidPBES2) {
+		return nil, ErrUnsupportedKeyCipher
+	}
+
+	var params pbes2Params
+	if _, err := asn1.Unmarshal(info.Algorithm.Parameters.FullBytes, &params); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidKey, err)
+	}
+	if !params.KeyDerivationFunc.Algorithm.Equal(oidScrypt) ||
+		!params.EncryptionScheme.Algorithm.Equal(oidAES256CBC) {
+		return nil, ErrUnsupportedKeyCipher
+	}
+
+	var kdf scryptParams
+	if _, err := asn1.Unmarshal(params.KeyDerivationFunc.Parameters.FullBytes, &kdf); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidKey, err)
+	}
+	var iv []byte
+	if _, err := asn1.Unmarshal(params.EncryptionScheme.Parameters.FullBytes, &iv); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidKey, err)
+	}
+	if len(iv) != aes.BlockSize || len(info.EncryptedData) == 0 || len(info.EncryptedData)%aes.BlockSize != 0 {
+		return nil, ErrInvalidKey
+	}
+	if kdf.KeyLength != 0 && kdf.KeyLength != aes256KeySize {
+		return nil, ErrUnsupportedKeyCipher
+	}
+
+	kek, err := scrypt.Key(passphrase, kdf.Salt, kdf.CostParameter, kdf.BlockSize, kdf.Parallelization, aes256KeySize)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidKey, err)
+	}
+	c, err := aes.NewCipher(kek)
+	if err != nil {
+		return nil, err
+	}
+
+	plain := make([]byte, len(info.EncryptedData))
+	cipher.NewCBCDecrypter(c, iv).CryptBlocks(plain, info.EncryptedData)
+
+	plain, ok := pkcs7Unpad(plain, aes.BlockSize)
+	if !ok {
+		return nil, ErrIncorrectPassphrase
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(plain)
+	if err != nil {
+		return nil, ErrIncorrectPassphrase
+	}
+	key, ok := parsed.(*ecdsa.PrivateKey)
+	if !ok {
+		return nil, ErrUnsupportedEC
+	}
+	return key, nil
+}
+
+func pkcs7Pad(data []byte, blockSize int) []byte {
+	n := blockSize - len(data)%blockSize
+	return append(append([]byte(nil), data...), bytes.Repeat([]byte{byte(n)}, n)...)
+}
+
+func pkcs7Unpad(data []byte, blockSize int) ([]byte, bool) {
+	if len(data) == 0 {
+		return nil, false
+	}
+	n := int(data[len(data)-1])
+	if n == 0 || n > blockSize || n > len(data) {
+		return nil, false
+	}
+	for _, b := range data[len(data)-n:] {
+		if int(b) != n {
+			return nil, false
+		}
+	}
+	return data[:len(data)-n], true
+}
@@ -0,0 +1,318 @@
+package cert
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/fxamacker/cbor/v2"
+)
+
+// Remote signer operations.
+const (
+	remoteOpPublic   uint8 = 1
+	remoteOpSign     uint8 = 2
+	remoteOpGenerate uint8 = 3
+	remoteOpDelete   uint8 = 4
+)
+
+// remoteSignTimeout bounds a single request to the signer process.
+const remoteSignTimeout = 10 * time.Second
+
+// remoteRequest is sent by RemoteKeyStore to a SignerServer.
+type remoteRequest struct {
+	Op     uint8  `cbor:"1,keyasint"`
+	Name   string `cbor:"2,keyasint"`
+	Digest []byte `cbor:"3,keyasint,omitempty"`
+	Hash   uint   `cbor:"4,keyasint,omitempty"`
+
+	// Overwrite lets remoteOpGenerate replace an existing key.
+	Overwrite bool `cbor:"5,keyasint,omitempty"`
+}
+
+// remoteResponse is returned by a SignerServer.
+type remoteResponse struct {
+	PublicKey []byte `cbor:"1,keyasint,omitempty"` // PKIX DER
+	Signature []byte `cbor:"2,keyasint,omitempty"`
+	NotFound  bool   `cbor:"3,keyasint,omitempty"`
+	Error     string `cbor:"4,keyasint,omitempty"`
+	Exists    bool   `cbor:"5,keyasint,omitempty"`
+}
+
+// errRemoteKeyNotFound is returned by RemoteKeyStore.LoadKey for missing
+// keys. It wraps os.ErrNotExist like the file stores' errors.
+var errRemoteKeyNotFound = fmt.Errorf("remote signer: key %w", os.ErrNotExist)
+
+// SignerServer serves the keys of a KeyStore to RemoteKeyStore clients over
+// a stream listener, typically a Unix socket. Running it in a separate,
+// privileged process keeps key material out of the controller process; a
+// hardware keystore can be exposed by serving a KeyStore adapter for it.
+type SignerServer struct {
+	keys KeyStore
+
+	mu      sync.Mutex
+	signers map[string]crypto.Signer
+}
+
+// NewSignerServer creates a server for the keys in keys.
+func NewSignerServer(keys KeyStore) *SignerServer {
+	return &SignerServer{
+		keys:    keys,
+		signers: make(map[string]crypto.Signer),
+	}
+}
+
+// Serve accepts connections on l until it is closed. Each connection may
+// carry any number of requests.
+func (s *SignerServer) Serve(l net.Listener) error {
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return nil
+			}
+			return err
+		}
+		go s.handleConn(conn)
+	}
+}
+
+func (s *SignerServer) handleConn(conn net.Conn) {
+	defer conn.Close()
+
+	dec := cbor.NewDecoder(conn)
+	enc := cbor.NewEncoder(conn)
+	for {
+		var req remoteRequest
+		if err := dec.Decode(&req); err != nil {
+			return
+		}
+		if err := enc.Encode(s.handle(&req)); err != nil {
+			return
+		}
+	}
+}
+
+func (s *SignerServer) handle(req *remoteRequest) *remoteResponse {
+	switch req.Op {
+	case remoteOpPublic:
+		signer, err := s.signer(req.Name)
+		if err != nil {
+			return errorResponse(err)
+		}
+		return publicKeyResponse(signer)
+
+	case remoteOpSign:
+		signer, err := s.signer(req.Name)
+		if err != nil {
+			return errorResponse(err)
+		}
+		sig, err := signer.Sign(rand.Reader, req.Digest, crypto.Hash(req.Hash))
+		if err != nil {
+			return errorResponse(err)
+		}
+		return &remoteResponse{Signature: sig}
+
+	case remoteOpGenerate:
+		if !req.Overwrite {
+			_, err := s.signer(req.Name)
+			if err == nil {
+				return errorResponse(ErrKeyExists)
+			}
+			if !errors.Is(err, os.ErrNotExist) {
+				return errorResponse(err)
+			}
+		}
+		signer, err := s.keys.GenerateKey(req.Name)
+		if err != nil {
+			return errorResponse(err)
+		}
+		if err := s.keys.SaveKey(req.Name, signer); err != nil {
+			return errorResponse(err)
+		}
+		s.mu.Lock()
+		s.signers[req.Name] = signer
+		s.mu.Unlock()
+		return publicKeyResponse(signer)
+
+	case remoteOpDelete:
+		if err := s.keys.DeleteKey(req.Name); err != nil {
+			return errorResponse(err)
+		}
+		s.mu.Lock()
+		delete(s.signers, req.Name)
+		s.mu.Unlock()
+		return &remoteResponse{}
+
+	default:
+		return &remoteResponse{Error: fmt.Sprintf("unknown operation %d", req.Op)}
+	}
+}
+
+// signer returns the cached signer for name, loading it on first use.
+func (s *SignerServer) signer(name string) (crypto.Signer, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if signer, ok := s.signers[name]; ok {
+		return signer, nil
+	}
+	signer, err := s.keys.LoadKey(name)
+	if err != nil {
+		return nil, err
+	}
+	s.signers[name] = signer
+	return signer, nil
+}
+
+func publicKeyResponse(signer crypto.Signer) *remoteResponse {
+	der, err := x509.MarshalPKIXPublicKey(signer.Public())
+	if err != nil {
+		return errorResponse(err)
+	}
+	return &remoteResponse{PublicKey: der}
+}
+
+func errorResponse(err error) *remoteResponse {
+	if errors.Is(err, os.ErrNotExist) {
+		return &remoteResponse{NotFound: true}
+	}
+	if errors.Is(err, ErrKeyExists) {
+		return &remoteResponse{Exists: true}
+	}
+	return &remoteResponse{Error: err.Error()}
+}
+
+// RemoteKeyStore is a KeyStore whose keys live in a SignerServer. Signing
+// requests are forwarded over the socket; key material never enters this
+// process, so keys can only be generated remotely, not imported.
+type RemoteKeyStore struct {
+	network string
+	address string
+}
+
+// NewRemoteKeyStore creates a key store that talks to the SignerServer
+// listening on network/address, e.g. ("unix", "/run/mash/signer.sock").
+func NewRemoteKeyStore(network, address string) *RemoteKeyStore {
+	return &RemoteKeyStore{network: network, address: address}
+}
+
+// GenerateKey asks the signer to create and persist a key under name. It
+// returns ErrKeyExists if the signer already holds one.
+func (ks *RemoteKeyStore) GenerateKey(name string) (crypto.Signer, error) {
+	return ks.fetchSigner(&remoteRequest{Op: remoteOpGenerate, Name: name})
+}
+
+// ReplaceKey asks the signer to create and persist a key under name,
+// replacing any key it holds.
+func (ks *RemoteKeyStore) ReplaceKey(name string) (crypto.Signer, error) {
+	return ks.fetchSigner(&remoteRequest{Op: remoteOpGenerate, Name: name, Overwrite: true})
+}
+
+// SaveKey accepts only signers obtained from this store for the same name,
+// which the signer has already persisted.
+func (ks *RemoteKeyStore) SaveKey(name string, key crypto.Signer) error {
+	rs, ok := key.(*RemoteSigner)
+	if !ok || rs.store.network != ks.network || rs.store.address != ks.address || rs.name != name {
+		return ErrKeyImportNotSupported
+	}
+	return nil
+}
+
+// LoadKey returns a signer for the key stored under name.
+func (ks *RemoteKeyStore) LoadKey(name string) (crypto.Signer, error) {
+	return ks.fetchSigner(&remoteRequest{Op: remoteOpPublic, Name: name})
+}
+
+// DeleteKey asks the signer to delete the key stored under name.
+func (ks *RemoteKeyStore) DeleteKey(name string) error {
+	_, err := ks.roundTrip(&remoteRequest{Op: remoteOpDelete, Name: name})
+	return err
+}
+
+func (ks *RemoteKeyStore) fetchSigner(req *remoteRequest) (crypto.Signer, error) {
+	resp, err := ks.roundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	pub, err := x509.ParsePKIXPublicKey(resp.PublicKey)
+	if err != nil {
+		return nil, fmt.Errorf("remote signer: parse public key: %w", err)
+	}
+	ecPub, ok := pub.(*ecdsa.PublicKey)
+	if !ok {
+		return nil, ErrUnsupportedEC
+	}
+
+	return &RemoteSigner{store: ks, name: req.Name, pub: ecPub}, nil
+}
+
+func (ks *RemoteKeyStore) roundTrip(req *remoteRequest) (*remoteResponse, error) {
+	conn, err := net.DialTimeout(ks.network, ks.address, remoteSignTimeout)
+	if err != nil {
+		return nil, fmt.Errorf("remote signer: %w", err)
+	}
+	defer conn.Close()
+	_ = conn.SetDeadline(time.Now().Add(remoteSignTimeout))
+
+	if err := cbor.NewEncoder(conn).Encode(req); err != nil {
+		return nil, fmt.Errorf("remote signer: send: %w", err)
+	}
+
+	var resp remoteResponse
+	if err := cbor.NewDecoder(conn).Decode(&resp); err != nil {
+		if errors.Is(err, io.EOF) {
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, fmt.Errorf("remote signer: receive: %w", err)
+	}
+	if resp.NotFound {
+		return nil, errRemoteKeyNotFound
+	}
+	if resp.Exists {
+		return nil, fmt.Errorf("remote signer: %w", ErrKeyExists)
+	}
+	if resp.Error != "" {
+		return nil, fmt.Errorf("remote signer: %s", resp.Error)
+	}
+	return &resp, nil
+}
+
+// RemoteSigner is a crypto.Signer for a key held by a SignerServer.
+type RemoteSigner struct {
+	store *RemoteKeyStore
+	name  string
+	pub   *ecdsa.PublicKey
+}
+
+// Public returns the key's public half.
+func (s *RemoteSigner) Public() crypto.PublicKey {
+	return s.pub
+}
+
+// Sign forwards digest to the signer process. rand is ignored; the signer
+// uses its own entropy source.
+func (s *RemoteSigner) Sign(_ io.Reader, digest []byte, opts crypto.SignerOpts) ([]byte, error) {
+	resp, err := s.store.roundTrip(&remoteRequest{
+		Op:     remoteOpSign,
+		Name:   s.name,
+		Digest: digest,
+		Hash:   uint(opts.HashFunc()),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Signature, nil
+}
+
+// Verify RemoteKeyStore implements KeyStore.
+var _ KeyStore = (*RemoteKeyStore)(nil)
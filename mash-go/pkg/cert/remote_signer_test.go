@@ -0,0 +1,180 @@
+package cert
+
+import (
+	"crypto/ecdsa"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// startSigner serves keys from dir on a Unix socket and returns a client
+// store for it.
+func startSigner(t *testing.T, dir string) *RemoteKeyStore {
+	t.Helper()
+
+	sock := filepath.Join(t.TempDir(), "signer.sock")
+	l, err := net.Listen("unix", sock)
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	t.Cleanup(func() { l.Close() })
+
+	go NewSignerServer(NewEncryptedFileKeyStore(dir, []byte("signer-pass"))).Serve(l)
+	return NewRemoteKeyStore("unix", sock)
+}
+
+func TestRemoteKeyStore(t *testing.T) {
+	keyDir := t.TempDir()
+	ks := startSigner(t, keyDir)
+
+	caKey, err := ks.GenerateKey(ZoneCAKeyName)
+	if err != nil {
+		t.Fatalf("GenerateKey() error = %v", err)
+	}
+	ca, err := NewZoneCA("zone-remote", ZoneTypeLocal, caKey)
+	if err != nil {
+		t.Fatalf("NewZoneCA() error = %v", err)
+	}
+
+	t.Run("SignCSR", func(t *testing.T) {
+		deviceKP, _ := GenerateKeyPair()
+		csr, _ := CreateCSR(deviceKP, &CSRInfo{Identity: DeviceIdentity{DeviceID: "dev-1"}})
+		opCert, err := SignCSRWithDeviceID(ca, csr, "dev-1")
+		if err != nil {
+			t.Fatalf("SignCSRWithDeviceID() error = %v", err)
+		}
+
+		roots := x509.NewCertPool()
+		roots.AddCert(ca.Certificate)
+		if _, err := opCert.Verify(x509.VerifyOptions{
+			Roots:     roots,
+			KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+		}); err != nil {
+			t.Errorf("certificate signed by remote CA does not verify: %v", err)
+		}
+	})
+
+	t.Run("TLSHandshake", func(t *testing.T) {
+		ctrlKey, err := ks.GenerateKey(ControllerKeyName)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ctrl, err := NewControllerOperationalCert(ca, "controller-zone-remote", ctrlKey)
+		if err != nil {
+			t.Fatalf("NewControllerOperationalCert() error = %v", err)
+		}
+
+		roots := ca.TLSClientCAs()
+		serverConn, clientConn := net.Pipe()
+		defer serverConn.Close()
+		defer clientConn.Close()
+
+		server := tls.Server(serverConn, &tls.Config{
+			Certificates: []tls.Certificate{ctrl.TLSCertificate()},
+			MinVersion:   tls.VersionTLS13,
+		})
+		client := tls.Client(clientConn, &tls.Config{
+			RootCAs:    roots,
+			ServerName: "controller-zone-remote",
+			MinVersion: tls.VersionTLS13,
+		})
+
+		errCh := make(chan error, 1)
+		go func() { errCh <- server.Handshake() }()
+		if err := client.Handshake(); err != nil {
+			t.Fatalf("client handshake: %v", err)
+		}
+		if err := <-errCh; err != nil {
+			t.Fatalf("server handshake: %v", err)
+		}
+	})
+
+	t.Run("LoadAfterRestart", func(t *testing.T) {
+		restarted := startSigner(t, keyDir)
+		key, err := restarted.LoadKey(ZoneCAKeyName)
+		if err != nil {
+			t.Fatalf("LoadKey() error = %v", err)
+		}
+		if !key.Public().(*ecdsa.PublicKey).Equal(caKey.Public()) {
+			t.Error("reloaded key has a different public key")
+		}
+	})
+
+	t.Run("MissingKey", func(t *testing.T) {
+		if _, err := ks.LoadKey("missing.key"); !errors.Is(err, os.ErrNotExist) {
+			t.Errorf("LoadKey() error = %v, want os.ErrNotExist", err)
+		}
+	})
+
+	t.Run("ExistingKeyKept", func(t *testing.T) {
+		if _, err := ks.GenerateKey(ZoneCAKeyName); !errors.Is(err, ErrKeyExists) {
+			t.Fatalf("GenerateKey(existing) error = %v, want ErrKeyExists", err)
+		}
+		key, err := ks.LoadKey(ZoneCAKeyName)
+		if err != nil {
+			t.Fatalf("LoadKey() error = %v", err)
+		}
+		if !key.Public().(*ecdsa.PublicKey).Equal(caKey.Public()) {
+			t.Error("rejected GenerateKey replaced the key")
+		}
+
+		first, err := ks.GenerateKey("replaced.key")
+		if err != nil {
+			t.Fatalf("GenerateKey() error = %v", err)
+		}
+		second, err := ReplaceKey(ks, "replaced.key")
+		if err != nil {
+			t.Fatalf("ReplaceKey() error = %v", err)
+		}
+		if second.Public().(*ecdsa.PublicKey).Equal(first.Public()) {
+			t.Error("ReplaceKey kept the old key")
+		}
+
+		if err := ks.DeleteKey("replaced.key"); err != nil {
+			t.Fatalf("DeleteKey() error = %v", err)
+		}
+		if _, err := ks.LoadKey("replaced.key"); !errors.Is(err, os.ErrNotExist) {
+			t.Errorf("LoadKey(deleted) error = %v, want os.ErrNotExist", err)
+		}
+	})
+
+	t.Run("ImportRejected", func(t *testing.T) {
+		kp, _ := GenerateKeyPair()
+		if err := ks.SaveKey("k", kp.PrivateKey); !errors.Is(err, ErrKeyImportNotSupported) {
+			t.Errorf("SaveKey(software key) error = %v, want ErrKeyImportNotSupported", err)
+		}
+		if err := ks.SaveKey("other", caKey); !errors.Is(err, ErrKeyImportNotSupported) {
+			t.Errorf("SaveKey(renamed key) error = %v, want ErrKeyImportNotSupported", err)
+		}
+		if err := ks.SaveKey(ZoneCAKeyName, caKey); err != nil {
+			t.Errorf("SaveKey(own key) error = %v", err)
+		}
+	})
+
+	t.Run("ControllerStore", func(t *testing.T) {
+		dir := t.TempDir()
+		store := NewFileControllerStoreWithKeyStore(dir, ks)
+		if err := store.SetZoneCA(ca); err != nil {
+			t.Fatal(err)
+		}
+		if err := store.Save(); err != nil {
+			t.Fatalf("Save() error = %v", err)
+		}
+		if _, err := os.Stat(filepath.Join(dir, filepath.FromSlash(ZoneCAKeyName))); !os.IsNotExist(err) {
+			t.Error("Zone CA key should not be written to the certificate store directory")
+		}
+
+		loaded := NewFileControllerStoreWithKeyStore(dir, ks)
+		if err := loaded.Load(); err != nil {
+			t.Fatalf("Load() error = %v", err)
+		}
+		got, _ := loaded.GetZoneCA()
+		if _, ok := got.PrivateKey.(*RemoteSigner); !ok {
+			t.Errorf("loaded Zone CA key is %T, want *RemoteSigner", got.PrivateKey)
+		}
+	})
+}
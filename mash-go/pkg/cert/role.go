@@ -0,0 +1,130 @@
+package cert
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// ErrNotAdmin is returned when a certificate does not carry the role an
+// operation requires.
+var ErrNotAdmin = errors.New("certificate is not a zone admin certificate")
+
+// Role is a controller's role within a zone (security.md §7).
+type Role uint8
+
+// Zone roles.
+const (
+	RoleOwner  Role = 1 // Holds the Zone CA, issues certificates, removes the zone
+	RoleAdmin  Role = 2 // Commissions devices on the owner's behalf (phone app, installer tool)
+	RoleMember Role = 3 // Normal zone participant (devices)
+)
+
+// String returns a human-readable role name. It is also the value encoded in
+// controller certificates.
+func (r Role) String() string {
+	switch r {
+	case RoleOwner:
+		return "OWNER"
+	case RoleAdmin:
+		return "ADMIN"
+	case RoleMember:
+		return "MEMBER"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+// controllerOrganization is the subject Organization of controller
+// operational certificates, for owners and admins alike.
+const controllerOrganization = "MASH Controller"
+
+// CertRole returns the zone role an operational certificate was issued for.
+// Controller certificates carry their role as an OrganizationalUnit next to
+// the zone type and zone ID; a controller certificate without one has no
+// role (0), which is denied everything. All other certificates are members.
+func CertRole(c *x509.Certificate) Role {
+	if c == nil {
+		return 0
+	}
+	if !slices.Contains(c.Subject.Organization, controllerOrganization) {
+		return RoleMember
+	}
+	for _, ou := range c.Subject.OrganizationalUnit {
+		switch ou {
+		case RoleAdmin.String():
+			return RoleAdmin
+		case RoleOwner.String():
+			return RoleOwner
+		}
+	}
+	return 0
+}
+
+// CreateAdminCSR creates the CSR a zone admin sends to the zone owner to
+// obtain its admin certificate. key stays with the admin.
+func CreateAdminCSR(key crypto.Signer, adminID string) ([]byte, error) {
+	if adminID == "" {
+		return nil, fmt.Errorf("admin ID is required")
+	}
+	if _, err := signerPublicKey(key); err != nil {
+		return nil, err
+	}
+
+	template := &x509.CertificateRequest{
+		Subject: pkix.Name{
+			CommonName:   adminID,
+			Organization: []string{controllerOrganization},
+		},
+		SignatureAlgorithm: x509.ECDSAWithSHA256,
+	}
+
+	csrDER, err := x509.CreateCertificateRequest(rand.Reader, template, key)
+	if err != nil {
+		return nil, fmt.Errorf("create CSR: %w", err)
+	}
+	return csrDER, nil
+}
+
+// SignAdminCSR issues an admin certificate for the key in csrDER. It is
+// called by the zone owner after the user has authorised the admin. The
+// CSR's CommonName becomes the admin's controller ID.
+func SignAdminCSR(ca *ZoneCA, csrDER []byte) (*x509.Certificate, error) {
+	csr, err := x509.ParseCertificateRequest(csrDER)
+	if err != nil {
+		return nil, fmt.Errorf("parse CSR: %w", err)
+	}
+	if err := csr.CheckSignature(); err != nil {
+		return nil, fmt.Errorf("invalid CSR signature: %w", err)
+	}
+	if csr.Subject.CommonName == "" {
+		return nil, fmt.Errorf("admin ID is required")
+	}
+
+	return issueControllerCert(ca, csr.Subject.CommonName, csr.PublicKey, RoleAdmin)
+}
+
+// NewAdminZoneCA returns the Zone CA as a zone admin holds it: the owner's
+// CA certificate with zone ID and type, but no private key.
+func NewAdminZoneCA(caCert *x509.Certificate) (*ZoneCA, error) {
+	if caCert == nil || !caCert.IsCA {
+		return nil, ErrInvalidCert
+	}
+	zoneType, err := ExtractZoneTypeFromCert(caCert)
+	if err != nil {
+		return nil, err
+	}
+	if len(caCert.Subject.OrganizationalUnit) < 2 {
+		return nil, fmt.Errorf("%w: no zone ID in Zone CA certificate", ErrInvalidCert)
+	}
+
+	return &ZoneCA{
+		Certificate: caCert,
+		ZoneID:      caCert.Subject.OrganizationalUnit[1],
+		ZoneType:    zoneType,
+	}, nil
+}
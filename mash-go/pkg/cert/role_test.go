@@ -0,0 +1,183 @@
+package cert
+
+import (
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+)
+
+// newTestAdmin issues an admin certificate under ca.
+func newTestAdmin(t *testing.T, ca *ZoneCA, adminID string) *OperationalCert {
+	t.Helper()
+
+	kp, err := GenerateKeyPair()
+	if err != nil {
+		t.Fatalf("GenerateKeyPair() error = %v", err)
+	}
+	csrDER, err := CreateAdminCSR(kp.PrivateKey, adminID)
+	if err != nil {
+		t.Fatalf("CreateAdminCSR() error = %v", err)
+	}
+	adminCert, err := SignAdminCSR(ca, csrDER)
+	if err != nil {
+		t.Fatalf("SignAdminCSR() error = %v", err)
+	}
+	return &OperationalCert{
+		Certificate: adminCert,
+		PrivateKey:  kp.PrivateKey,
+		ZoneID:      ca.ZoneID,
+		ZoneType:    ca.ZoneType,
+		ZoneCACert:  ca.Certificate,
+	}
+}
+
+// newTestDeviceCSR returns a device CSR as sent during commissioning.
+func newTestDeviceCSR(t *testing.T) []byte {
+	t.Helper()
+
+	kp, _ := GenerateKeyPair()
+	csrDER, err := CreateCSR(kp, &CSRInfo{
+		Identity: DeviceIdentity{DeviceID: "device-001", VendorID: 1234, ProductID: 5678},
+		ZoneID:   "zone-1",
+	})
+	if err != nil {
+		t.Fatalf("CreateCSR() error = %v", err)
+	}
+	return csrDER
+}
+
+func TestCertRole(t *testing.T) {
+	ca, _ := GenerateZoneCA("zone-1", ZoneTypeLocal)
+
+	owner, _ := GenerateControllerOperationalCert(ca, "controller-1")
+	if got := CertRole(owner.Certificate); got != RoleOwner {
+		t.Errorf("CertRole(owner) = %v, want OWNER", got)
+	}
+
+	admin := newTestAdmin(t, ca, "installer-app")
+	if got := CertRole(admin.Certificate); got != RoleAdmin {
+		t.Errorf("CertRole(admin) = %v, want ADMIN", got)
+	}
+	if admin.Certificate.Subject.CommonName != "installer-app" {
+		t.Errorf("admin CN = %q, want installer-app", admin.Certificate.Subject.CommonName)
+	}
+	if err := VerifyOperationalCert(admin.Certificate, ca.Certificate); err != nil {
+		t.Errorf("VerifyOperationalCert(admin) error = %v", err)
+	}
+
+	deviceCert, err := SignCSR(ca, newTestDeviceCSR(t))
+	if err != nil {
+		t.Fatalf("SignCSR() error = %v", err)
+	}
+	if got := CertRole(deviceCert); got != RoleMember {
+		t.Errorf("CertRole(device) = %v, want MEMBER", got)
+	}
+
+	// A CSR cannot claim a controller role: only its CommonName is kept.
+	kp, _ := GenerateKeyPair()
+	forgedCSR, _ := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{
+		Subject: pkix.Name{
+			CommonName:         "device-002",
+			Organization:       []string{controllerOrganization},
+			OrganizationalUnit: []string{RoleOwner.String()},
+		},
+	}, kp.PrivateKey)
+	forged, err := SignCSRWithDeviceID(ca, forgedCSR, "device-002")
+	if err != nil {
+		t.Fatalf("SignCSRWithDeviceID() error = %v", err)
+	}
+	if got := CertRole(forged); got != RoleMember {
+		t.Errorf("CertRole(forged) = %v, want MEMBER", got)
+	}
+
+	// A controller certificate without a role OU grants nothing.
+	noRole := *owner.Certificate
+	noRole.Subject.OrganizationalUnit = slices.DeleteFunc(slices.Clone(noRole.Subject.OrganizationalUnit), func(ou string) bool {
+		return ou == RoleOwner.String()
+	})
+	if got := CertRole(&noRole); got != 0 {
+		t.Errorf("CertRole(no role) = %v, want 0", got)
+	}
+
+	if got := CertRole(nil); got != 0 {
+		t.Errorf("CertRole(nil) = %v, want 0", got)
+	}
+}
+
+func TestNewAdminZoneCA(t *testing.T) {
+	ca, _ := GenerateZoneCA("zone-1", ZoneTypeLocal)
+
+	adminCA, err := NewAdminZoneCA(ca.Certificate)
+	if err != nil {
+		t.Fatalf("NewAdminZoneCA() error = %v", err)
+	}
+	if adminCA.ZoneID != "zone-1" || adminCA.ZoneType != ZoneTypeLocal {
+		t.Errorf("NewAdminZoneCA() = %s/%v, want zone-1/LOCAL", adminCA.ZoneID, adminCA.ZoneType)
+	}
+	if adminCA.PrivateKey != nil {
+		t.Error("admin Zone CA has a private key")
+	}
+
+	owner, _ := GenerateControllerOperationalCert(ca, "controller-1")
+	if _, err := NewAdminZoneCA(owner.Certificate); !errors.Is(err, ErrInvalidCert) {
+		t.Errorf("NewAdminZoneCA(non-CA) error = %v, want ErrInvalidCert", err)
+	}
+}
+
+func TestDelegatedSigningRequest(t *testing.T) {
+	ca, _ := GenerateZoneCA("zone-1", ZoneTypeLocal)
+	admin := newTestAdmin(t, ca, "installer-app")
+	now := time.Now()
+
+	t.Run("Valid", func(t *testing.T) {
+		req, err := NewDelegatedSigningRequest(admin, newTestDeviceCSR(t))
+		if err != nil {
+			t.Fatalf("NewDelegatedSigningRequest() error = %v", err)
+		}
+		got, err := VerifyDelegatedSigningRequest(req, ca.Certificate, now)
+		if err != nil {
+			t.Fatalf("VerifyDelegatedSigningRequest() error = %v", err)
+		}
+		if got.Subject.CommonName != "installer-app" {
+			t.Errorf("admin CN = %q, want installer-app", got.Subject.CommonName)
+		}
+	})
+
+	t.Run("TamperedCSR", func(t *testing.T) {
+		req, _ := NewDelegatedSigningRequest(admin, newTestDeviceCSR(t))
+		req.CSR = newTestDeviceCSR(t)
+		_, err := VerifyDelegatedSigningRequest(req, ca.Certificate, now)
+		if !errors.Is(err, ErrInvalidDelegation) {
+			t.Errorf("error = %v, want ErrInvalidDelegation", err)
+		}
+	})
+
+	t.Run("NotAdmin", func(t *testing.T) {
+		kp, _ := GenerateKeyPair()
+		csrDER, _ := CreateCSR(kp, &CSRInfo{Identity: DeviceIdentity{DeviceID: "device-002"}, ZoneID: "zone-1"})
+		deviceCert, err := SignCSR(ca, csrDER)
+		if err != nil {
+			t.Fatalf("SignCSR() error = %v", err)
+		}
+		device := &OperationalCert{Certificate: deviceCert, PrivateKey: kp.PrivateKey}
+
+		req, _ := NewDelegatedSigningRequest(device, newTestDeviceCSR(t))
+		_, err = VerifyDelegatedSigningRequest(req, ca.Certificate, now)
+		if !errors.Is(err, ErrNotAdmin) {
+			t.Errorf("error = %v, want ErrNotAdmin", err)
+		}
+	})
+
+	t.Run("OtherZone", func(t *testing.T) {
+		otherCA, _ := GenerateZoneCA("zone-2", ZoneTypeLocal)
+		req, _ := NewDelegatedSigningRequest(newTestAdmin(t, otherCA, "other-app"), newTestDeviceCSR(t))
+		_, err := VerifyDelegatedSigningRequest(req, ca.Certificate, now)
+		if !errors.Is(err, ErrInvalidChain) {
+			t.Errorf("error = %v, want ErrInvalidChain", err)
+		}
+	})
+}
@@ -0,0 +1,103 @@
+package cert
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Zone CA rotation errors.
+var (
+	ErrCrossCertMismatch = errors.New("cross-certificate does not match new Zone CA")
+	ErrUntrustedZoneCA   = errors.New("new Zone CA is not endorsed by a trusted Zone CA")
+)
+
+// ZoneCANextKeyName is the alternate key name used for a Zone CA that is
+// being rotated in while the current one still lives under ZoneCAKeyName.
+const ZoneCANextKeyName = controllerDir + "/" + zoneCANextKeyFile
+
+// NextZoneCAKeyName returns the key name to generate a replacement for
+// current under. Rotations alternate between ZoneCAKeyName and
+// ZoneCANextKeyName so the outgoing key stays usable until the rotation
+// completes.
+func NextZoneCAKeyName(current *ZoneCA) string {
+	if current != nil && current.KeyName == ZoneCANextKeyName {
+		return ZoneCAKeyName
+	}
+	return ZoneCANextKeyName
+}
+
+// CrossSignZoneCA issues a certificate for newCA's subject and public key,
+// signed by issuer. Devices that only trust issuer use it to authenticate
+// newCA when the zone's CA is rotated. The certificate is valid from now
+// and never outlives issuer.
+func CrossSignZoneCA(issuer *ZoneCA, newCA *x509.Certificate, now time.Time) (*x509.Certificate, error) {
+	if issuer == nil || issuer.Certificate == nil || issuer.PrivateKey == nil || newCA == nil {
+		return nil, ErrInvalidCert
+	}
+	if !newCA.IsCA {
+		return nil, fmt.Errorf("%w: not a CA certificate", ErrInvalidCert)
+	}
+
+	serialNumber, err := generateSerialNumber()
+	if err != nil {
+		return nil, fmt.Errorf("generate serial number: %w", err)
+	}
+
+	notAfter := newCA.NotAfter
+	if issuer.Certificate.NotAfter.Before(notAfter) {
+		notAfter = issuer.Certificate.NotAfter
+	}
+
+	template := &x509.Certificate{
+		SerialNumber:          serialNumber,
+		Subject:               newCA.Subject,
+		NotBefore:             now,
+		NotAfter:              notAfter,
+		KeyUsage:              newCA.KeyUsage,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+		MaxPathLen:            0,
+		MaxPathLenZero:        true,
+		SubjectKeyId:          newCA.SubjectKeyId,
+	}
+
+	certDER, err := x509.CreateCertificate(rand.Reader, template, issuer.Certificate, newCA.PublicKey, issuer.PrivateKey)
+	if err != nil {
+		return nil, fmt.Errorf("create certificate: %w", err)
+	}
+	return x509.ParseCertificate(certDER)
+}
+
+// VerifyZoneCACrossSign checks that newCA is a self-signed CA and that
+// cross, valid at now, certifies the same subject and public key under one
+// of trusted.
+func VerifyZoneCACrossSign(newCA, cross *x509.Certificate, trusted []*x509.Certificate, now time.Time) error {
+	if newCA == nil || cross == nil {
+		return ErrInvalidCert
+	}
+	if !newCA.IsCA || newCA.CheckSignatureFrom(newCA) != nil {
+		return fmt.Errorf("%w: Zone CA must be a self-signed CA", ErrInvalidCert)
+	}
+	if !cross.IsCA ||
+		!bytes.Equal(cross.RawSubjectPublicKeyInfo, newCA.RawSubjectPublicKeyInfo) ||
+		!bytes.Equal(cross.RawSubject, newCA.RawSubject) {
+		return ErrCrossCertMismatch
+	}
+	if now.Before(cross.NotBefore) {
+		return ErrCertNotYetValid
+	}
+	if now.After(cross.NotAfter) {
+		return ErrCertExpired
+	}
+
+	for _, ca := range trusted {
+		if cross.CheckSignatureFrom(ca) == nil {
+			return nil
+		}
+	}
+	return ErrUntrustedZoneCA
+}
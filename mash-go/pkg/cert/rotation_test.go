@@ -0,0 +1,74 @@
+package cert
+
+import (
+	"crypto/x509"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCrossSignZoneCA(t *testing.T) {
+	oldCA, _ := GenerateZoneCA("zone-1", ZoneTypeLocal)
+	newCA, _ := GenerateZoneCA("zone-1", ZoneTypeLocal)
+
+	now := time.Now()
+	cross, err := CrossSignZoneCA(oldCA, newCA.Certificate, now)
+	if err != nil {
+		t.Fatalf("CrossSignZoneCA() error = %v", err)
+	}
+	if cross.NotAfter.After(oldCA.Certificate.NotAfter) {
+		t.Error("cross-certificate outlives its issuer")
+	}
+
+	t.Run("Trusted", func(t *testing.T) {
+		if err := VerifyZoneCACrossSign(newCA.Certificate, cross, []*x509.Certificate{oldCA.Certificate}, now); err != nil {
+			t.Errorf("VerifyZoneCACrossSign() error = %v", err)
+		}
+	})
+
+	t.Run("UntrustedIssuer", func(t *testing.T) {
+		otherCA, _ := GenerateZoneCA("zone-2", ZoneTypeLocal)
+		err := VerifyZoneCACrossSign(newCA.Certificate, cross, []*x509.Certificate{otherCA.Certificate}, now)
+		if !errors.Is(err, ErrUntrustedZoneCA) {
+			t.Errorf("error = %v, want ErrUntrustedZoneCA", err)
+		}
+	})
+
+	t.Run("KeyMismatch", func(t *testing.T) {
+		otherCA, _ := GenerateZoneCA("zone-1", ZoneTypeLocal)
+		err := VerifyZoneCACrossSign(otherCA.Certificate, cross, []*x509.Certificate{oldCA.Certificate}, now)
+		if !errors.Is(err, ErrCrossCertMismatch) {
+			t.Errorf("error = %v, want ErrCrossCertMismatch", err)
+		}
+	})
+
+	t.Run("NotYetValid", func(t *testing.T) {
+		err := VerifyZoneCACrossSign(newCA.Certificate, cross, []*x509.Certificate{oldCA.Certificate}, now.Add(-time.Hour))
+		if !errors.Is(err, ErrCertNotYetValid) {
+			t.Errorf("error = %v, want ErrCertNotYetValid", err)
+		}
+	})
+
+	t.Run("Expired", func(t *testing.T) {
+		err := VerifyZoneCACrossSign(newCA.Certificate, cross, []*x509.Certificate{oldCA.Certificate}, cross.NotAfter.Add(time.Second))
+		if !errors.Is(err, ErrCertExpired) {
+			t.Errorf("error = %v, want ErrCertExpired", err)
+		}
+	})
+
+	t.Run("NotCA", func(t *testing.T) {
+		ctrl, _ := GenerateControllerOperationalCert(oldCA, "controller-1")
+		if _, err := CrossSignZoneCA(oldCA, ctrl.Certificate, now); err == nil {
+			t.Error("expected error cross-signing a non-CA certificate")
+		}
+	})
+}
+
+func TestNextZoneCAKeyName(t *testing.T) {
+	if got := NextZoneCAKeyName(&ZoneCA{}); got != ZoneCANextKeyName {
+		t.Errorf("NextZoneCAKeyName(default) = %q, want %q", got, ZoneCANextKeyName)
+	}
+	if got := NextZoneCAKeyName(&ZoneCA{KeyName: ZoneCANextKeyName}); got != ZoneCAKeyName {
+		t.Errorf("NextZoneCAKeyName(next) = %q, want %q", got, ZoneCAKeyName)
+	}
+}
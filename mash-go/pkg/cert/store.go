@@ -47,10 +47,21 @@ type Store interface {
 	// Returns ErrCertNotFound if not found.
 	GetZoneCACert(zoneID string) (*x509.Certificate, error)
 
-	// SetZoneCACert stores a Zone CA certificate for a zone.
+	// SetZoneCACert stores a Zone CA certificate for a zone. It replaces
+	// any CA added with AddZoneCACert.
 	SetZoneCACert(zoneID string, cert *x509.Certificate) error
 
-	// GetAllZoneCAs returns all stored Zone CA certificates.
+	// AddZoneCACert trusts cert for a zone alongside its current Zone CA
+	// while the zone's CA is being rotated. A later AddZoneCACert replaces
+	// it; SetZoneCACert ends the transition.
+	AddZoneCACert(zoneID string, cert *x509.Certificate) error
+
+	// GetZoneCACerts returns all Zone CA certificates trusted for a zone,
+	// the current one first.
+	GetZoneCACerts(zoneID string) []*x509.Certificate
+
+	// GetAllZoneCAs returns all stored Zone CA certificates, including
+	// those added with AddZoneCACert.
 	GetAllZoneCAs() []*x509.Certificate
 
 	// Persistence (optional, depends on implementation)
@@ -70,12 +81,21 @@ type ControllerStore interface {
 	Store
 
 	// GetZoneCA returns the full Zone CA (including private key).
-	// This is only available on the zone owner (controller).
+	// On a zone admin the PrivateKey is nil: admins hold only the
+	// certificate and have device CSRs signed by the owner.
 	GetZoneCA() (*ZoneCA, error)
 
-	// SetZoneCA stores the Zone CA (including private key).
+	// SetZoneCA stores the Zone CA, including its private key if present.
 	SetZoneCA(ca *ZoneCA) error
 
+	// GetPendingZoneCA returns the Zone CA being rotated in, or
+	// ErrCertNotFound if no rotation is in progress.
+	GetPendingZoneCA() (*ZoneCA, error)
+
+	// SetPendingZoneCA stores the Zone CA being rotated in. A nil CA
+	// clears it.
+	SetPendingZoneCA(ca *ZoneCA) error
+
 	// GetControllerCert returns the controller's operational certificate.
 	// This certificate is used for mutual TLS with devices.
 	// Returns ErrCertNotFound if no controller certificate exists.
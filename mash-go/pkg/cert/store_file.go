@@ -12,6 +12,12 @@ const (
 	zoneCACertFile = "zone-ca.pem"
 	zoneCAKeyFile  = "zone-ca.key"
 	zoneCAMetaFile = "zone-ca.json"
+
+	// Zone CA being rotated in (see ControllerStore.SetPendingZoneCA and
+	// Store.AddZoneCACert).
+	zoneCANextCertFile = "zone-ca-next.pem"
+	zoneCANextKeyFile  = "zone-ca-next.key"
+	zoneCANextMetaFile = "zone-ca-next.json"
 )
 
 // FileStore is a file-based implementation of the Store interface.
@@ -19,10 +25,12 @@ const (
 type FileStore struct {
 	mu      sync.RWMutex
 	baseDir string
+	keys    KeyStore
 
 	// In-memory state (same as MemoryStore)
 	operationalCerts map[string]*OperationalCert
 	zoneCACerts      map[string]*x509.Certificate
+	nextZoneCACerts  map[string]*x509.Certificate
 
 	// Track removed zones for cleanup on Save
 	removedZones map[string]bool
@@ -30,15 +38,29 @@ type FileStore struct {
 
 // NewFileStore creates a new file-based certificate store.
 // The baseDir is the root directory for storing certificates.
+// Private keys are written as plaintext PEM files next to the certificates.
 func NewFileStore(baseDir string) *FileStore {
+	return NewFileStoreWithKeyStore(baseDir, NewFileKeyStore(baseDir))
+}
+
+// NewFileStoreWithKeyStore creates a file-based certificate store that keeps
+// private keys in keys, e.g. an encrypted FileKeyStore or a RemoteKeyStore.
+func NewFileStoreWithKeyStore(baseDir string, keys KeyStore) *FileStore {
 	return &FileStore{
 		baseDir:          baseDir,
+		keys:             keys,
 		operationalCerts: make(map[string]*OperationalCert),
 		zoneCACerts:      make(map[string]*x509.Certificate),
+		nextZoneCACerts:  make(map[string]*x509.Certificate),
 		removedZones:     make(map[string]bool),
 	}
 }
 
+// KeyStore returns the key store holding the private keys.
+func (s *FileStore) KeyStore() KeyStore {
+	return s.keys
+}
+
 // GetOperationalCert returns the operational certificate for a zone.
 func (s *FileStore) GetOperationalCert(zoneID string) (*OperationalCert, error) {
 	s.mu.RLock()
@@ -83,6 +105,7 @@ func (s *FileStore) RemoveOperationalCert(zoneID string) error {
 
 	delete(s.operationalCerts, zoneID)
 	delete(s.zoneCACerts, zoneID)
+	delete(s.nextZoneCACerts, zoneID)
 	s.removedZones[zoneID] = true
 	return nil
 }
@@ -128,6 +151,7 @@ func (s *FileStore) SetZoneCACert(zoneID string, cert *x509.Certificate) error {
 	defer s.mu.Unlock()
 
 	s.zoneCACerts[zoneID] = cert
+	delete(s.nextZoneCACerts, zoneID)
 
 	// Also update the operational cert's ZoneCACert field if it exists
 	// This ensures the Zone CA is persisted when Save() is called
@@ -138,15 +162,46 @@ func (s *FileStore) SetZoneCACert(zoneID string, cert *x509.Certificate) error {
 	return nil
 }
 
+// AddZoneCACert trusts cert for a zone alongside its current Zone CA.
+func (s *FileStore) AddZoneCACert(zoneID string, cert *x509.Certificate) error {
+	if cert == nil {
+		return ErrInvalidCert
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.nextZoneCACerts[zoneID] = cert
+	return nil
+}
+
+// GetZoneCACerts returns all Zone CA certificates trusted for a zone.
+func (s *FileStore) GetZoneCACerts(zoneID string) []*x509.Certificate {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var certs []*x509.Certificate
+	if cert, exists := s.zoneCACerts[zoneID]; exists {
+		certs = append(certs, cert)
+	}
+	if cert, exists := s.nextZoneCACerts[zoneID]; exists {
+		certs = append(certs, cert)
+	}
+	return certs
+}
+
 // GetAllZoneCAs returns all stored Zone CA certificates.
 func (s *FileStore) GetAllZoneCAs() []*x509.Certificate {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
-	certs := make([]*x509.Certificate, 0, len(s.zoneCACerts))
+	certs := make([]*x509.Certificate, 0, len(s.zoneCACerts)+len(s.nextZoneCACerts))
 	for _, cert := range s.zoneCACerts {
 		certs = append(certs, cert)
 	}
+	for _, cert := range s.nextZoneCACerts {
+		certs = append(certs, cert)
+	}
 	return certs
 }
 
@@ -171,6 +226,7 @@ func (s *FileStore) Save() error {
 	for zoneID := range s.removedZones {
 		zoneDir := filepath.Join(s.baseDir, "zones", zoneID)
 		_ = os.RemoveAll(zoneDir)
+		_ = s.keys.DeleteKey(OperationalKeyName(zoneID))
 	}
 
 	return nil
@@ -213,6 +269,14 @@ func (s *FileStore) Load() error {
 		if opCert.ZoneCACert != nil {
 			s.zoneCACerts[zoneID] = opCert.ZoneCACert
 		}
+
+		next, err := ReadCertFile(filepath.Join(zonesDir, zoneID, zoneCANextCertFile))
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		if next != nil {
+			s.nextZoneCACerts[zoneID] = next
+		}
 	}
 
 	return nil
@@ -231,8 +295,7 @@ func (s *FileStore) saveOperationalCert(zoneID string, opCert *OperationalCert)
 	}
 
 	// Save private key
-	keyPath := filepath.Join(dir, "operational.key")
-	if err := WriteKeyFile(keyPath, opCert.PrivateKey); err != nil {
+	if err := s.keys.SaveKey(OperationalKeyName(zoneID), opCert.PrivateKey); err != nil {
 		return err
 	}
 
@@ -244,6 +307,16 @@ func (s *FileStore) saveOperationalCert(zoneID string, opCert *OperationalCert)
 		}
 	}
 
+	// Save or clear the Zone CA being rotated in
+	nextPath := filepath.Join(dir, zoneCANextCertFile)
+	if next, exists := s.nextZoneCACerts[zoneID]; exists {
+		if err := WriteCertFile(nextPath, next); err != nil {
+			return err
+		}
+	} else if err := os.Remove(nextPath); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
 	return nil
 }
 
@@ -258,8 +331,7 @@ func (s *FileStore) loadOperationalCert(zoneID string) (*OperationalCert, error)
 	}
 
 	// Load private key
-	keyPath := filepath.Join(dir, "operational.key")
-	key, err := ReadKeyFile(keyPath)
+	key, err := s.keys.LoadKey(OperationalKeyName(zoneID))
 	if err != nil {
 		return nil, err
 	}
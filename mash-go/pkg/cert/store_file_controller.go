@@ -1,9 +1,11 @@
 package cert
 
 import (
+	"crypto"
 	"encoding/hex"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
 )
@@ -14,6 +16,9 @@ const (
 	controllerDir      = "controller"
 	controllerCertFile = "controller.pem"
 	controllerKeyFile  = "controller.key"
+
+	// Replacement controller key, see NextControllerKeyName
+	controllerNextKeyFile = "controller-next.key"
 )
 
 // Controller store errors.
@@ -34,19 +39,31 @@ type CommissionedDevice struct {
 type FileControllerStore struct {
 	*FileStore
 	zoneCA         *ZoneCA
+	pendingZoneCA  *ZoneCA
 	controllerCert *OperationalCert
 	devices        map[string]*CommissionedDevice
 
 	// Track removed devices for cleanup on Save
 	removedDevices map[string]bool
+
+	// Keys of replaced Zone CAs, deleted on Save
+	retiredKeys map[string]bool
 }
 
 // NewFileControllerStore creates a new file-based controller certificate store.
+// Private keys, including the Zone CA key, are written as plaintext PEM.
 func NewFileControllerStore(baseDir string) *FileControllerStore {
+	return NewFileControllerStoreWithKeyStore(baseDir, NewFileKeyStore(baseDir))
+}
+
+// NewFileControllerStoreWithKeyStore creates a file-based controller
+// certificate store that keeps private keys in keys.
+func NewFileControllerStoreWithKeyStore(baseDir string, keys KeyStore) *FileControllerStore {
 	return &FileControllerStore{
-		FileStore:      NewFileStore(baseDir),
+		FileStore:      NewFileStoreWithKeyStore(baseDir, keys),
 		devices:        make(map[string]*CommissionedDevice),
 		removedDevices: make(map[string]bool),
+		retiredKeys:    make(map[string]bool),
 	}
 }
 
@@ -63,17 +80,50 @@ func (s *FileControllerStore) GetZoneCA() (*ZoneCA, error) {
 
 // SetZoneCA stores the Zone CA (including private key).
 func (s *FileControllerStore) SetZoneCA(ca *ZoneCA) error {
-	if ca == nil || ca.Certificate == nil || ca.PrivateKey == nil {
+	if ca == nil || ca.Certificate == nil {
 		return ErrInvalidCert
 	}
 
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	if s.zoneCA != nil && zoneCAKeyName(s.zoneCA) != zoneCAKeyName(ca) {
+		s.retiredKeys[zoneCAKeyName(s.zoneCA)] = true
+	}
+	delete(s.retiredKeys, zoneCAKeyName(ca))
 	s.zoneCA = ca
 	return nil
 }
 
+// GetPendingZoneCA returns the Zone CA being rotated in.
+func (s *FileControllerStore) GetPendingZoneCA() (*ZoneCA, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.pendingZoneCA == nil {
+		return nil, ErrCertNotFound
+	}
+	return s.pendingZoneCA, nil
+}
+
+// SetPendingZoneCA stores the Zone CA being rotated in. A nil CA clears it.
+// Its key must be named differently from the current Zone CA's, see
+// NextZoneCAKeyName.
+func (s *FileControllerStore) SetPendingZoneCA(ca *ZoneCA) error {
+	if ca != nil && (ca.Certificate == nil || ca.PrivateKey == nil) {
+		return ErrInvalidCert
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.pendingZoneCA = ca
+	if ca != nil {
+		delete(s.retiredKeys, zoneCAKeyName(ca))
+	}
+	return nil
+}
+
 // GetControllerCert returns the controller's operational certificate.
 func (s *FileControllerStore) GetControllerCert() (*OperationalCert, error) {
 	s.mu.RLock()
@@ -85,7 +135,9 @@ func (s *FileControllerStore) GetControllerCert() (*OperationalCert, error) {
 	return s.controllerCert, nil
 }
 
-// SetControllerCert stores the controller's operational certificate.
+// SetControllerCert stores the controller's operational certificate. If its
+// key is named differently from the current certificate's, the old key is
+// deleted once the new certificate has been saved.
 func (s *FileControllerStore) SetControllerCert(cert *OperationalCert) error {
 	if cert == nil || cert.Certificate == nil || cert.PrivateKey == nil {
 		return ErrInvalidCert
@@ -94,6 +146,10 @@ func (s *FileControllerStore) SetControllerCert(cert *OperationalCert) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	if s.controllerCert != nil && controllerKeyName(s.controllerCert) != controllerKeyName(cert) {
+		s.retiredKeys[controllerKeyName(s.controllerCert)] = true
+	}
+	delete(s.retiredKeys, controllerKeyName(cert))
 	s.controllerCert = cert
 	return nil
 }
@@ -162,9 +218,23 @@ func (s *FileControllerStore) Save() error {
 
 	// Save Zone CA
 	if s.zoneCA != nil {
-		if err := s.saveZoneCA(); err != nil {
+		if err := s.saveZoneCA(s.zoneCA, zoneCACertFile, zoneCAMetaFile); err != nil {
+			return err
+		}
+	}
+
+	// Save or clear the Zone CA being rotated in
+	if s.pendingZoneCA != nil {
+		if err := s.saveZoneCA(s.pendingZoneCA, zoneCANextCertFile, zoneCANextMetaFile); err != nil {
 			return err
 		}
+	} else {
+		dir := filepath.Join(s.baseDir, controllerDir)
+		for _, name := range []string{zoneCANextCertFile, zoneCANextMetaFile} {
+			if err := os.Remove(filepath.Join(dir, name)); err != nil && !os.IsNotExist(err) {
+				return err
+			}
+		}
 	}
 
 	// Save Controller Operational Cert
@@ -174,6 +244,14 @@ func (s *FileControllerStore) Save() error {
 		}
 	}
 
+	// Delete keys of replaced Zone CAs and controller certificates, now
+	// that nothing saved refers to them
+	for name := range s.retiredKeys {
+		if err := s.keys.DeleteKey(name); err != nil {
+			return err
+		}
+	}
+
 	// Save devices
 	for deviceID, device := range s.devices {
 		if err := s.saveDevice(deviceID, device); err != nil {
@@ -201,9 +279,22 @@ func (s *FileControllerStore) Load() error {
 	defer s.mu.Unlock()
 
 	// Load Zone CA
-	if err := s.loadZoneCA(); err != nil && !os.IsNotExist(err) {
+	zoneCA, err := s.loadZoneCA(zoneCACertFile, zoneCAMetaFile)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
 		return err
 	}
+	if zoneCA != nil {
+		s.zoneCA = zoneCA
+	}
+
+	// Load the Zone CA being rotated in, if any
+	pendingZoneCA, err := s.loadZoneCA(zoneCANextCertFile, zoneCANextMetaFile)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	if pendingZoneCA != nil {
+		s.pendingZoneCA = pendingZoneCA
+	}
 
 	// Load Controller Operational Cert
 	if err := s.loadControllerCert(); err != nil && !os.IsNotExist(err) {
@@ -236,32 +327,45 @@ func (s *FileControllerStore) Load() error {
 type zoneCAMetadata struct {
 	ZoneID   string   `json:"zone_id"`
 	ZoneType ZoneType `json:"zone_type"`
+	KeyName  string   `json:"key_name,omitempty"`
+	CertOnly bool     `json:"cert_only,omitempty"` // Zone admins hold no Zone CA key
+}
+
+// zoneCAKeyName returns the KeyStore name of ca's private key.
+func zoneCAKeyName(ca *ZoneCA) string {
+	if ca.KeyName == "" {
+		return ZoneCAKeyName
+	}
+	return ca.KeyName
 }
 
-func (s *FileControllerStore) saveZoneCA() error {
+func (s *FileControllerStore) saveZoneCA(ca *ZoneCA, certFile, metaFile string) error {
 	dir := filepath.Join(s.baseDir, controllerDir)
 	if err := os.MkdirAll(dir, 0755); err != nil {
 		return err
 	}
 
 	// Save certificate
-	certPath := filepath.Join(dir, zoneCACertFile)
-	if err := WriteCertFile(certPath, s.zoneCA.Certificate); err != nil {
+	certPath := filepath.Join(dir, certFile)
+	if err := WriteCertFile(certPath, ca.Certificate); err != nil {
 		return err
 	}
 
 	// Save private key
-	keyPath := filepath.Join(dir, zoneCAKeyFile)
-	if err := WriteKeyFile(keyPath, s.zoneCA.PrivateKey); err != nil {
-		return err
+	if ca.PrivateKey != nil {
+		if err := s.keys.SaveKey(zoneCAKeyName(ca), ca.PrivateKey); err != nil {
+			return err
+		}
 	}
 
 	// Save metadata
 	meta := zoneCAMetadata{
-		ZoneID:   s.zoneCA.ZoneID,
-		ZoneType: s.zoneCA.ZoneType,
+		ZoneID:   ca.ZoneID,
+		ZoneType: ca.ZoneType,
+		KeyName:  ca.KeyName,
+		CertOnly: ca.PrivateKey == nil,
 	}
-	metaPath := filepath.Join(dir, zoneCAMetaFile)
+	metaPath := filepath.Join(dir, metaFile)
 	data, err := json.MarshalIndent(meta, "", "  ")
 	if err != nil {
 		return err
@@ -273,42 +377,46 @@ func (s *FileControllerStore) saveZoneCA() error {
 	return nil
 }
 
-func (s *FileControllerStore) loadZoneCA() error {
+func (s *FileControllerStore) loadZoneCA(certFile, metaFile string) (*ZoneCA, error) {
 	dir := filepath.Join(s.baseDir, controllerDir)
 
 	// Load certificate
-	certPath := filepath.Join(dir, zoneCACertFile)
+	certPath := filepath.Join(dir, certFile)
 	cert, err := ReadCertFile(certPath)
 	if err != nil {
-		return err
-	}
-
-	// Load private key
-	keyPath := filepath.Join(dir, zoneCAKeyFile)
-	key, err := ReadKeyFile(keyPath)
-	if err != nil {
-		return err
+		return nil, err
 	}
 
 	// Load metadata
 	meta := zoneCAMetadata{}
-	metaPath := filepath.Join(dir, zoneCAMetaFile)
+	metaPath := filepath.Join(dir, metaFile)
 	data, err := os.ReadFile(metaPath)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	if err := json.Unmarshal(data, &meta); err != nil {
-		return err
+		return nil, err
 	}
 
-	s.zoneCA = &ZoneCA{
+	ca := &ZoneCA{
 		Certificate: cert,
-		PrivateKey:  key,
 		ZoneID:      meta.ZoneID,
 		ZoneType:    meta.ZoneType,
+		KeyName:     meta.KeyName,
 	}
 
-	return nil
+	if meta.CertOnly {
+		return ca, nil
+	}
+
+	// Load private key
+	key, err := s.keys.LoadKey(zoneCAKeyName(ca))
+	if err != nil {
+		return nil, err
+	}
+	ca.PrivateKey = key
+
+	return ca, nil
 }
 
 func (s *FileControllerStore) saveControllerCert() error {
@@ -324,14 +432,21 @@ func (s *FileControllerStore) saveControllerCert() error {
 	}
 
 	// Save private key
-	keyPath := filepath.Join(dir, controllerKeyFile)
-	if err := WriteKeyFile(keyPath, s.controllerCert.PrivateKey); err != nil {
+	if err := s.keys.SaveKey(controllerKeyName(s.controllerCert), s.controllerCert.PrivateKey); err != nil {
 		return err
 	}
 
 	return nil
 }
 
+// controllerKeyName returns the KeyStore name of oc's private key.
+func controllerKeyName(oc *OperationalCert) string {
+	if oc.KeyName == "" {
+		return ControllerKeyName
+	}
+	return oc.KeyName
+}
+
 func (s *FileControllerStore) loadControllerCert() error {
 	dir := filepath.Join(s.baseDir, controllerDir)
 
@@ -342,17 +457,34 @@ func (s *FileControllerStore) loadControllerCert() error {
 		return err
 	}
 
-	// Load private key
-	keyPath := filepath.Join(dir, controllerKeyFile)
-	key, err := ReadKeyFile(keyPath)
-	if err != nil {
-		return err
+	// Load the private key under whichever name matches the certificate,
+	// which also covers a save interrupted during a key replacement
+	var (
+		key     crypto.Signer
+		keyName string
+	)
+	for _, name := range []string{ControllerKeyName, ControllerNextKeyName} {
+		k, err := s.keys.LoadKey(name)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if pub, ok := k.Public().(interface{ Equal(crypto.PublicKey) bool }); ok && pub.Equal(cert.PublicKey) {
+			key, keyName = k, name
+			break
+		}
+	}
+	if key == nil {
+		return fmt.Errorf("controller key: %w", os.ErrNotExist)
 	}
 
 	// Controller cert metadata comes from Zone CA (same zone)
 	s.controllerCert = &OperationalCert{
 		Certificate: cert,
 		PrivateKey:  key,
+		KeyName:     keyName,
 		ZoneID:      s.zoneCA.ZoneID,
 		ZoneType:    s.zoneCA.ZoneType,
 		ZoneCACert:  s.zoneCA.Certificate,
@@ -1,6 +1,7 @@
 package cert
 
 import (
+	"crypto"
 	"os"
 	"path/filepath"
 	"testing"
@@ -455,6 +456,122 @@ func TestFileControllerStore(t *testing.T) {
 
 // TC-IMPL-CERT-STORE: Verify identity files are NOT created
 // (commissioning certs are never persisted)
+func TestFileStore_ZoneCARotationRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	store := NewFileStore(dir)
+
+	oldCA, _ := GenerateZoneCA("zone-1", ZoneTypeLocal)
+	newCA, _ := GenerateZoneCA("zone-1", ZoneTypeLocal)
+	deviceKP, _ := GenerateKeyPair()
+	csrDER, _ := CreateCSR(deviceKP, &CSRInfo{
+		Identity: DeviceIdentity{DeviceID: "device-001", VendorID: 1, ProductID: 1},
+		ZoneID:   "zone-1",
+	})
+	cert, _ := SignCSR(oldCA, csrDER)
+
+	_ = store.SetOperationalCert(&OperationalCert{
+		Certificate: cert,
+		PrivateKey:  deviceKP.PrivateKey,
+		ZoneID:      "zone-1",
+		ZoneCACert:  oldCA.Certificate,
+	})
+	_ = store.SetZoneCACert("zone-1", oldCA.Certificate)
+	_ = store.AddZoneCACert("zone-1", newCA.Certificate)
+	if err := store.Save(); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	store2 := NewFileStore(dir)
+	if err := store2.Load(); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	certs := store2.GetZoneCACerts("zone-1")
+	if len(certs) != 2 || !certs[0].Equal(oldCA.Certificate) || !certs[1].Equal(newCA.Certificate) {
+		t.Fatalf("GetZoneCACerts() after reload = %d certs, want [old, new]", len(certs))
+	}
+
+	// Retiring the old CA removes the extra file
+	_ = store2.SetZoneCACert("zone-1", newCA.Certificate)
+	if err := store2.Save(); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "zones", "zone-1", zoneCANextCertFile)); !os.IsNotExist(err) {
+		t.Errorf("%s should be removed after retire", zoneCANextCertFile)
+	}
+
+	store3 := NewFileStore(dir)
+	if err := store3.Load(); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	certs = store3.GetZoneCACerts("zone-1")
+	if len(certs) != 1 || !certs[0].Equal(newCA.Certificate) {
+		t.Errorf("GetZoneCACerts() after retire = %d certs, want [new]", len(certs))
+	}
+}
+
+func TestFileControllerStore_PendingZoneCA(t *testing.T) {
+	dir := t.TempDir()
+	store := NewFileControllerStore(dir)
+
+	oldCA, _ := GenerateZoneCA("my-zone", ZoneTypeLocal)
+	_ = store.SetZoneCA(oldCA)
+
+	keyName := NextZoneCAKeyName(oldCA)
+	newCA, _ := GenerateZoneCA("my-zone", ZoneTypeLocal)
+	newCA.KeyName = keyName
+	if err := store.SetPendingZoneCA(newCA); err != nil {
+		t.Fatalf("SetPendingZoneCA() error = %v", err)
+	}
+	if err := store.Save(); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	// Both CAs survive a restart with their own keys
+	store2 := NewFileControllerStore(dir)
+	if err := store2.Load(); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	gotOld, err := store2.GetZoneCA()
+	if err != nil || !gotOld.Certificate.Equal(oldCA.Certificate) {
+		t.Fatalf("GetZoneCA() = %v, %v; want old CA", gotOld, err)
+	}
+	gotNew, err := store2.GetPendingZoneCA()
+	if err != nil || !gotNew.Certificate.Equal(newCA.Certificate) {
+		t.Fatalf("GetPendingZoneCA() = %v, %v; want new CA", gotNew, err)
+	}
+	if gotNew.KeyName != keyName {
+		t.Errorf("KeyName = %q, want %q", gotNew.KeyName, keyName)
+	}
+	if !gotNew.Certificate.PublicKey.(interface{ Equal(crypto.PublicKey) bool }).Equal(gotNew.PrivateKey.Public()) {
+		t.Error("pending Zone CA loaded with the wrong key")
+	}
+
+	// Promote the pending CA; the old key and the pending files go away
+	_ = store2.SetZoneCA(gotNew)
+	_ = store2.SetPendingZoneCA(nil)
+	if err := store2.Save(); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	ctrlDir := filepath.Join(dir, "controller")
+	for _, name := range []string{zoneCAKeyFile, zoneCANextCertFile, zoneCANextMetaFile} {
+		if _, err := os.Stat(filepath.Join(ctrlDir, name)); !os.IsNotExist(err) {
+			t.Errorf("%s should be removed after promotion", name)
+		}
+	}
+
+	store3 := NewFileControllerStore(dir)
+	if err := store3.Load(); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	got, err := store3.GetZoneCA()
+	if err != nil || !got.Certificate.Equal(newCA.Certificate) {
+		t.Fatalf("GetZoneCA() after promotion = %v, %v; want new CA", got, err)
+	}
+	if _, err := store3.GetPendingZoneCA(); err != ErrCertNotFound {
+		t.Errorf("GetPendingZoneCA() error = %v, want ErrCertNotFound", err)
+	}
+}
+
 func TestCertStore_NoIdentityFiles(t *testing.T) {
 	dir := t.TempDir()
 	store := NewFileStore(dir)
@@ -15,6 +15,9 @@ type MemoryStore struct {
 
 	// Zone CA certificates by zone ID
 	zoneCACerts map[string]*x509.Certificate
+
+	// Zone CAs being rotated in, by zone ID
+	nextZoneCACerts map[string]*x509.Certificate
 }
 
 // NewMemoryStore creates a new in-memory certificate store.
@@ -22,6 +25,7 @@ func NewMemoryStore() *MemoryStore {
 	return &MemoryStore{
 		operationalCerts: make(map[string]*OperationalCert),
 		zoneCACerts:      make(map[string]*x509.Certificate),
+		nextZoneCACerts:  make(map[string]*x509.Certificate),
 	}
 }
 
@@ -68,6 +72,7 @@ func (s *MemoryStore) RemoveOperationalCert(zoneID string) error {
 
 	delete(s.operationalCerts, zoneID)
 	delete(s.zoneCACerts, zoneID) // Also remove the Zone CA
+	delete(s.nextZoneCACerts, zoneID)
 	return nil
 }
 
@@ -112,6 +117,7 @@ func (s *MemoryStore) SetZoneCACert(zoneID string, cert *x509.Certificate) error
 	defer s.mu.Unlock()
 
 	s.zoneCACerts[zoneID] = cert
+	delete(s.nextZoneCACerts, zoneID)
 
 	// Also update the operational cert's ZoneCACert field if it exists
 	if opCert, exists := s.operationalCerts[zoneID]; exists {
@@ -121,15 +127,46 @@ func (s *MemoryStore) SetZoneCACert(zoneID string, cert *x509.Certificate) error
 	return nil
 }
 
+// AddZoneCACert trusts cert for a zone alongside its current Zone CA.
+func (s *MemoryStore) AddZoneCACert(zoneID string, cert *x509.Certificate) error {
+	if cert == nil {
+		return ErrInvalidCert
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.nextZoneCACerts[zoneID] = cert
+	return nil
+}
+
+// GetZoneCACerts returns all Zone CA certificates trusted for a zone.
+func (s *MemoryStore) GetZoneCACerts(zoneID string) []*x509.Certificate {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var certs []*x509.Certificate
+	if cert, exists := s.zoneCACerts[zoneID]; exists {
+		certs = append(certs, cert)
+	}
+	if cert, exists := s.nextZoneCACerts[zoneID]; exists {
+		certs = append(certs, cert)
+	}
+	return certs
+}
+
 // GetAllZoneCAs returns all stored Zone CA certificates.
 func (s *MemoryStore) GetAllZoneCAs() []*x509.Certificate {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
-	certs := make([]*x509.Certificate, 0, len(s.zoneCACerts))
+	certs := make([]*x509.Certificate, 0, len(s.zoneCACerts)+len(s.nextZoneCACerts))
 	for _, cert := range s.zoneCACerts {
 		certs = append(certs, cert)
 	}
+	for _, cert := range s.nextZoneCACerts {
+		certs = append(certs, cert)
+	}
 	return certs
 }
 
@@ -150,6 +187,7 @@ var _ Store = (*MemoryStore)(nil)
 type MemoryControllerStore struct {
 	*MemoryStore
 	zoneCA         *ZoneCA
+	pendingZoneCA  *ZoneCA
 	controllerCert *OperationalCert
 }
 
@@ -173,7 +211,7 @@ func (s *MemoryControllerStore) GetZoneCA() (*ZoneCA, error) {
 
 // SetZoneCA stores the Zone CA (including private key).
 func (s *MemoryControllerStore) SetZoneCA(ca *ZoneCA) error {
-	if ca == nil || ca.Certificate == nil || ca.PrivateKey == nil {
+	if ca == nil || ca.Certificate == nil {
 		return ErrInvalidCert
 	}
 
@@ -184,6 +222,30 @@ func (s *MemoryControllerStore) SetZoneCA(ca *ZoneCA) error {
 	return nil
 }
 
+// GetPendingZoneCA returns the Zone CA being rotated in.
+func (s *MemoryControllerStore) GetPendingZoneCA() (*ZoneCA, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.pendingZoneCA == nil {
+		return nil, ErrCertNotFound
+	}
+	return s.pendingZoneCA, nil
+}
+
+// SetPendingZoneCA stores the Zone CA being rotated in. A nil CA clears it.
+func (s *MemoryControllerStore) SetPendingZoneCA(ca *ZoneCA) error {
+	if ca != nil && (ca.Certificate == nil || ca.PrivateKey == nil) {
+		return ErrInvalidCert
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.pendingZoneCA = ca
+	return nil
+}
+
 // GetControllerCert returns the controller's operational certificate.
 func (s *MemoryControllerStore) GetControllerCert() (*OperationalCert, error) {
 	s.mu.RLock()
@@ -186,6 +186,38 @@ func TestMemoryStoreZoneCACerts(t *testing.T) {
 	}
 }
 
+func TestMemoryStoreZoneCARotation(t *testing.T) {
+	store := NewMemoryStore()
+
+	oldCA, _ := GenerateZoneCA("zone-1", ZoneTypeLocal)
+	newCA, _ := GenerateZoneCA("zone-1", ZoneTypeLocal)
+	_ = store.SetZoneCACert("zone-1", oldCA.Certificate)
+
+	if err := store.AddZoneCACert("zone-1", newCA.Certificate); err != nil {
+		t.Fatalf("AddZoneCACert() error = %v", err)
+	}
+
+	// Both CAs are trusted during the transition, current one first
+	certs := store.GetZoneCACerts("zone-1")
+	if len(certs) != 2 || certs[0] != oldCA.Certificate || certs[1] != newCA.Certificate {
+		t.Fatalf("GetZoneCACerts() = %d certs, want [old, new]", len(certs))
+	}
+	if len(store.GetAllZoneCAs()) != 2 {
+		t.Errorf("GetAllZoneCAs() = %d certs, want 2", len(store.GetAllZoneCAs()))
+	}
+
+	// SetZoneCACert ends the transition
+	_ = store.SetZoneCACert("zone-1", newCA.Certificate)
+	certs = store.GetZoneCACerts("zone-1")
+	if len(certs) != 1 || certs[0] != newCA.Certificate {
+		t.Errorf("GetZoneCACerts() after retire = %d certs, want [new]", len(certs))
+	}
+
+	if err := store.AddZoneCACert("zone-1", nil); err != ErrInvalidCert {
+		t.Errorf("AddZoneCACert(nil) error = %v, want ErrInvalidCert", err)
+	}
+}
+
 func TestMemoryControllerStore(t *testing.T) {
 	store := NewMemoryControllerStore()
 
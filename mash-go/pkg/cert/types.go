@@ -1,6 +1,7 @@
 package cert
 
 import (
+	"crypto"
 	"crypto/ecdsa"
 	"crypto/tls"
 	"crypto/x509"
@@ -44,14 +45,20 @@ type ZoneCA struct {
 	// Certificate is the Zone CA X.509 certificate.
 	Certificate *x509.Certificate
 
-	// PrivateKey is the Zone CA private key (only held by zone owner).
-	PrivateKey *ecdsa.PrivateKey
+	// PrivateKey is the Zone CA signing key (only held by zone owner).
+	// It may be an *ecdsa.PrivateKey or a signer backed by a KeyStore
+	// that never exposes the key material.
+	PrivateKey crypto.Signer
 
 	// ZoneID is a unique identifier for this zone.
 	ZoneID string
 
 	// ZoneType indicates the type of zone (GRID or LOCAL).
 	ZoneType ZoneType
+
+	// KeyName is the name of PrivateKey in the controller store's KeyStore.
+	// Empty means ZoneCAKeyName.
+	KeyName string
 }
 
 // ZoneType represents the type of zone, which determines priority.
@@ -89,8 +96,9 @@ type OperationalCert struct {
 	// Certificate is the X.509 operational certificate.
 	Certificate *x509.Certificate
 
-	// PrivateKey is the device's private key for this certificate.
-	PrivateKey *ecdsa.PrivateKey
+	// PrivateKey is the signing key for this certificate. It may be an
+	// *ecdsa.PrivateKey or a signer backed by a KeyStore.
+	PrivateKey crypto.Signer
 
 	// ZoneID identifies which zone this certificate is for.
 	ZoneID string
@@ -100,6 +108,10 @@ type OperationalCert struct {
 
 	// ZoneCACert is the Zone CA certificate (for chain verification).
 	ZoneCACert *x509.Certificate
+
+	// KeyName is the name of PrivateKey in the controller store's KeyStore,
+	// for the controller's own certificate. Empty means ControllerKeyName.
+	KeyName string
 }
 
 // SKI returns the Subject Key Identifier of the operational certificate.
@@ -121,26 +133,41 @@ func (oc *OperationalCert) ExpiresAt() time.Time {
 
 // NeedsRenewal returns true if the certificate should be renewed.
 func (oc *OperationalCert) NeedsRenewal() bool {
+	return oc.NeedsRenewalAt(time.Now())
+}
+
+// NeedsRenewalAt returns true if the certificate is within the renewal window at now.
+func (oc *OperationalCert) NeedsRenewalAt(now time.Time) bool {
 	if oc.Certificate == nil {
 		return true
 	}
-	return time.Now().Add(RenewalWindow).After(oc.Certificate.NotAfter)
+	return now.Add(RenewalWindow).After(oc.Certificate.NotAfter)
 }
 
 // IsExpired returns true if the certificate has expired.
 func (oc *OperationalCert) IsExpired() bool {
+	return oc.IsExpiredAt(time.Now())
+}
+
+// IsExpiredAt returns true if the certificate has expired at now.
+func (oc *OperationalCert) IsExpiredAt(now time.Time) bool {
 	if oc.Certificate == nil {
 		return true
 	}
-	return time.Now().After(oc.Certificate.NotAfter)
+	return now.After(oc.Certificate.NotAfter)
 }
 
 // IsInGracePeriod returns true if the certificate is expired but within grace period.
 func (oc *OperationalCert) IsInGracePeriod() bool {
+	return oc.IsInGracePeriodAt(time.Now())
+}
+
+// IsInGracePeriodAt returns true if the certificate is expired but within
+// grace period at now.
+func (oc *OperationalCert) IsInGracePeriodAt(now time.Time) bool {
 	if oc.Certificate == nil {
 		return false
 	}
-	now := time.Now()
 	return now.After(oc.Certificate.NotAfter) && now.Before(oc.Certificate.NotAfter.Add(GracePeriod))
 }
 
@@ -0,0 +1,263 @@
+package clock
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// Clock is a source of time and timers.
+type Clock interface {
+	// Now returns the current time.
+	Now() time.Time
+
+	// Since returns the time elapsed since t.
+	Since(t time.Time) time.Duration
+
+	// AfterFunc calls f once d has elapsed and returns a Timer that can
+	// cancel the call.
+	AfterFunc(d time.Duration, f func()) Timer
+
+	// NewTicker returns a Ticker that delivers ticks every d.
+	NewTicker(d time.Duration) Ticker
+}
+
+// Timer is a cancellable one-shot timer created by Clock.AfterFunc.
+type Timer interface {
+	// Stop prevents the timer from firing. It returns false if the timer
+	// has already fired or been stopped.
+	Stop() bool
+
+	// Reset changes the timer to fire after d. It returns true if the
+	// timer had been active.
+	Reset(d time.Duration) bool
+}
+
+// Ticker delivers periodic ticks on a channel.
+type Ticker interface {
+	// C returns the channel on which ticks are delivered.
+	C() <-chan time.Time
+
+	// Stop turns off the ticker.
+	Stop()
+}
+
+// Real returns a Clock backed by the time package.
+func Real() Clock {
+	return realClock{}
+}
+
+// OrReal returns c, or Real if c is nil.
+func OrReal(c Clock) Clock {
+	if c == nil {
+		return Real()
+	}
+	return c
+}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time                  { return time.Now() }
+func (realClock) Since(t time.Time) time.Duration { return time.Since(t) }
+
+func (realClock) AfterFunc(d time.Duration, f func()) Timer {
+	return time.AfterFunc(d, f)
+}
+
+func (realClock) NewTicker(d time.Duration) Ticker {
+	return realTicker{time.NewTicker(d)}
+}
+
+type realTicker struct {
+	t *time.Ticker
+}
+
+func (r realTicker) C() <-chan time.Time { return r.t.C }
+func (r realTicker) Stop()               { r.t.Stop() }
+
+// Fake is a Clock whose time only moves when told to. It is safe for
+// concurrent use.
+type Fake struct {
+	mu      sync.Mutex
+	now     time.Time
+	waiters []*fakeWaiter
+	nextSeq uint64
+}
+
+// fakeWaiter is a pending timer or ticker on a Fake clock.
+type fakeWaiter struct {
+	clock    *Fake
+	deadline time.Time
+	seq      uint64 // creation order, breaks deadline ties
+
+	fn     func()         // AfterFunc callback (timers)
+	ch     chan time.Time // tick channel (tickers)
+	period time.Duration  // tick period (tickers)
+	active bool
+}
+
+// NewFake creates a Fake clock set to now.
+func NewFake(now time.Time) *Fake {
+	return &Fake{now: now}
+}
+
+// Now returns the fake current time.
+func (f *Fake) Now() time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.now
+}
+
+// Since returns the fake time elapsed since t.
+func (f *Fake) Since(t time.Time) time.Duration {
+	return f.Now().Sub(t)
+}
+
+// AfterFunc schedules f to run when the clock is advanced past d.
+func (f *Fake) AfterFunc(d time.Duration, fn func()) Timer {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	w := &fakeWaiter{clock: f, fn: fn}
+	f.scheduleLocked(w, d)
+	return w
+}
+
+// NewTicker creates a ticker that ticks each time the clock passes a
+// multiple of d. Like time.Ticker, ticks are dropped if the channel is full.
+func (f *Fake) NewTicker(d time.Duration) Ticker {
+	if d <= 0 {
+		panic("clock: non-positive interval for NewTicker")
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	w := &fakeWaiter{clock: f, ch: make(chan time.Time, 1), period: d}
+	f.scheduleLocked(w, d)
+	return fakeTicker{w}
+}
+
+// Advance moves the clock forward by d, firing every timer and ticker that
+// falls due in deadline order. Timer callbacks run on the calling goroutine
+// and may schedule further timers; those fire too if they fall within d.
+func (f *Fake) Advance(d time.Duration) {
+	f.mu.Lock()
+	target := f.now.Add(d)
+	f.mu.Unlock()
+
+	f.advanceTo(target)
+}
+
+// Set moves the clock to t. Moving backwards does not fire any timers.
+func (f *Fake) Set(t time.Time) {
+	f.mu.Lock()
+	if !t.After(f.now) {
+		f.now = t
+		f.mu.Unlock()
+		return
+	}
+	f.mu.Unlock()
+
+	f.advanceTo(t)
+}
+
+// PendingTimers returns the number of active timers and tickers.
+func (f *Fake) PendingTimers() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.waiters)
+}
+
+// advanceTo fires waiters one at a time until none is due before target.
+func (f *Fake) advanceTo(target time.Time) {
+	for {
+		f.mu.Lock()
+		if len(f.waiters) == 0 || f.waiters[0].deadline.After(target) {
+			f.now = target
+			f.mu.Unlock()
+			return
+		}
+
+		w := f.waiters[0]
+		f.waiters = f.waiters[1:]
+		if w.deadline.After(f.now) {
+			f.now = w.deadline
+		}
+
+		var fn func()
+		if w.ch != nil {
+			select {
+			case w.ch <- f.now:
+			default:
+			}
+			f.scheduleLocked(w, w.period)
+		} else {
+			w.active = false
+			fn = w.fn
+		}
+		f.mu.Unlock()
+
+		if fn != nil {
+			fn()
+		}
+	}
+}
+
+// scheduleLocked (re)inserts w with a deadline d from now. Must be called
+// with mu held.
+func (f *Fake) scheduleLocked(w *fakeWaiter, d time.Duration) {
+	f.removeLocked(w)
+	w.deadline = f.now.Add(d)
+	w.seq = f.nextSeq
+	f.nextSeq++
+	w.active = true
+	f.waiters = append(f.waiters, w)
+	sort.SliceStable(f.waiters, func(i, j int) bool {
+		a, b := f.waiters[i], f.waiters[j]
+		if a.deadline.Equal(b.deadline) {
+			return a.seq < b.seq
+		}
+		return a.deadline.Before(b.deadline)
+	})
+}
+
+// removeLocked removes w from the waiter list. Must be called with mu held.
+func (f *Fake) removeLocked(w *fakeWaiter) bool {
+	for i, other := range f.waiters {
+		if other == w {
+			f.waiters = append(f.waiters[:i], f.waiters[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// Stop implements Timer.
+func (w *fakeWaiter) Stop() bool {
+	w.clock.mu.Lock()
+	defer w.clock.mu.Unlock()
+
+	wasActive := w.active
+	w.active = false
+	w.clock.removeLocked(w)
+	return wasActive
+}
+
+// Reset implements Timer.
+func (w *fakeWaiter) Reset(d time.Duration) bool {
+	w.clock.mu.Lock()
+	defer w.clock.mu.Unlock()
+
+	wasActive := w.active
+	w.clock.scheduleLocked(w, d)
+	return wasActive
+}
+
+// fakeTicker adapts a fakeWaiter to the Ticker interface.
+type fakeTicker struct {
+	w *fakeWaiter
+}
+
+func (t fakeTicker) C() <-chan time.Time { return t.w.ch }
+func (t fakeTicker) Stop()               { t.w.Stop() }
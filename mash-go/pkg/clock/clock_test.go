@@ -0,0 +1,218 @@
+package clock
+
+import (
+	"testing"
+	"time"
+)
+
+var epoch = time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func TestOrReal(t *testing.T) {
+	if _, ok := OrReal(nil).(realClock); !ok {
+		t.Error("OrReal(nil) should return the real clock")
+	}
+
+	fc := NewFake(epoch)
+	if OrReal(fc) != fc {
+		t.Error("OrReal should return a non-nil clock unchanged")
+	}
+}
+
+func TestFakeNowAndSince(t *testing.T) {
+	fc := NewFake(epoch)
+
+	if !fc.Now().Equal(epoch) {
+		t.Errorf("Now() = %v, want %v", fc.Now(), epoch)
+	}
+
+	fc.Advance(90 * time.Second)
+	if got := fc.Since(epoch); got != 90*time.Second {
+		t.Errorf("Since() = %v, want 90s", got)
+	}
+}
+
+func TestFakeAfterFuncFiresOnAdvance(t *testing.T) {
+	fc := NewFake(epoch)
+
+	fired := false
+	fc.AfterFunc(time.Hour, func() { fired = true })
+
+	fc.Advance(59 * time.Minute)
+	if fired {
+		t.Fatal("timer fired early")
+	}
+
+	fc.Advance(time.Minute)
+	if !fired {
+		t.Fatal("timer did not fire at its deadline")
+	}
+	if fc.PendingTimers() != 0 {
+		t.Errorf("PendingTimers() = %d, want 0", fc.PendingTimers())
+	}
+}
+
+func TestFakeAfterFuncSeesDeadlineTime(t *testing.T) {
+	fc := NewFake(epoch)
+
+	var at time.Time
+	fc.AfterFunc(10*time.Minute, func() { at = fc.Now() })
+
+	fc.Advance(time.Hour)
+	if want := epoch.Add(10 * time.Minute); !at.Equal(want) {
+		t.Errorf("callback saw Now() = %v, want %v", at, want)
+	}
+	if want := epoch.Add(time.Hour); !fc.Now().Equal(want) {
+		t.Errorf("Now() after Advance = %v, want %v", fc.Now(), want)
+	}
+}
+
+func TestFakeFiresInDeadlineOrder(t *testing.T) {
+	fc := NewFake(epoch)
+
+	var order []int
+	fc.AfterFunc(3*time.Second, func() { order = append(order, 3) })
+	fc.AfterFunc(1*time.Second, func() { order = append(order, 1) })
+	fc.AfterFunc(2*time.Second, func() { order = append(order, 2) })
+	fc.AfterFunc(2*time.Second, func() { order = append(order, 22) })
+
+	fc.Advance(5 * time.Second)
+
+	want := []int{1, 2, 22, 3}
+	if len(order) != len(want) {
+		t.Fatalf("order = %v, want %v", order, want)
+	}
+	for i := range want {
+		if order[i] != want[i] {
+			t.Fatalf("order = %v, want %v", order, want)
+		}
+	}
+}
+
+func TestFakeCallbackCanScheduleTimer(t *testing.T) {
+	fc := NewFake(epoch)
+
+	count := 0
+	var tick func()
+	tick = func() {
+		count++
+		fc.AfterFunc(time.Second, tick)
+	}
+	fc.AfterFunc(time.Second, tick)
+
+	fc.Advance(5 * time.Second)
+	if count != 5 {
+		t.Errorf("count = %d, want 5", count)
+	}
+}
+
+func TestFakeTimerStopAndReset(t *testing.T) {
+	fc := NewFake(epoch)
+
+	fired := 0
+	timer := fc.AfterFunc(time.Minute, func() { fired++ })
+
+	if !timer.Stop() {
+		t.Error("Stop() on active timer should return true")
+	}
+	if timer.Stop() {
+		t.Error("Stop() on stopped timer should return false")
+	}
+
+	fc.Advance(2 * time.Minute)
+	if fired != 0 {
+		t.Fatal("stopped timer fired")
+	}
+
+	if timer.Reset(time.Minute) {
+		t.Error("Reset() on stopped timer should return false")
+	}
+	fc.Advance(time.Minute)
+	if fired != 1 {
+		t.Fatalf("fired = %d after Reset, want 1", fired)
+	}
+}
+
+func TestFakeSetBackwardsDoesNotFire(t *testing.T) {
+	fc := NewFake(epoch)
+
+	fired := false
+	fc.AfterFunc(time.Minute, func() { fired = true })
+
+	fc.Set(epoch.Add(-time.Hour))
+	if fired {
+		t.Fatal("moving backwards fired a timer")
+	}
+
+	fc.Set(epoch.Add(30 * time.Second))
+	if fired {
+		t.Fatal("timer fired before its deadline")
+	}
+
+	fc.Set(epoch.Add(time.Minute))
+	if !fired {
+		t.Fatal("timer did not fire after Set past its deadline")
+	}
+}
+
+func TestFakeTicker(t *testing.T) {
+	fc := NewFake(epoch)
+
+	ticker := fc.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	select {
+	case <-ticker.C():
+		t.Fatal("tick before advancing")
+	default:
+	}
+
+	fc.Advance(time.Second)
+	select {
+	case got := <-ticker.C():
+		if want := epoch.Add(time.Second); !got.Equal(want) {
+			t.Errorf("tick = %v, want %v", got, want)
+		}
+	default:
+		t.Fatal("no tick after one period")
+	}
+
+	// Ticks are dropped while the channel is full, like time.Ticker.
+	fc.Advance(10 * time.Second)
+	<-ticker.C()
+	select {
+	case <-ticker.C():
+		t.Fatal("ticker should drop ticks while the channel is full")
+	default:
+	}
+
+	ticker.Stop()
+	fc.Advance(10 * time.Second)
+	select {
+	case <-ticker.C():
+		t.Fatal("tick after Stop")
+	default:
+	}
+	if fc.PendingTimers() != 0 {
+		t.Errorf("PendingTimers() = %d, want 0", fc.PendingTimers())
+	}
+}
+
+func TestFakeTickerPanicsOnNonPositiveInterval(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("NewTicker(0) should panic")
+		}
+	}()
+	NewFake(epoch).NewTicker(0)
+}
+
+func TestRealAfterFunc(t *testing.T) {
+	done := make(chan struct{})
+	Real().AfterFunc(time.Millisecond, func() { close(done) })
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("real timer did not fire")
+	}
+}
@@ -0,0 +1,27 @@
+// Package clock provides an injectable time source for MASH timers.
+//
+// Failsafe timers, duration timers, commissioning windows, subscription
+// heartbeats and certificate renewal all depend on wall-clock time. Reading
+// time.Now and calling time.AfterFunc directly makes that behaviour slow and
+// flaky to test: a 4-hour failsafe or a 7-day grace period cannot be waited
+// out. Components accept a Clock instead.
+//
+// # Implementations
+//
+// Real delegates to the time package and is the default everywhere.
+//
+// Fake holds a manually controlled time. Advance moves it forward and fires
+// every timer and ticker that falls due, in deadline order, on the calling
+// goroutine. Timer callbacks therefore complete before Advance returns, which
+// keeps tests deterministic:
+//
+//	fc := clock.NewFake(time.Unix(0, 0))
+//	timer, _ := failsafe.NewTimerWithConfig(failsafe.Config{Clock: fc})
+//	timer.Start()
+//	fc.Advance(failsafe.DefaultDuration) // timer is now in FAILSAFE
+//
+// # Nil Clocks
+//
+// Config structs treat a nil Clock as Real, so existing callers are
+// unaffected. Use OrReal to apply that default.
+package clock
@@ -50,6 +50,24 @@ const (
 	MsgCertRenewalAck uint8 = 33
 )
 
+// Zone CA rotation message types. They share the renewal channel so a zone
+// can move to a new CA without re-commissioning its devices.
+//
+// Their fields use CBOR keys 10 and up, which no request or response uses,
+// so a response whose messageId happens to be 34-36 cannot be mistaken for
+// one of them.
+const (
+	// MsgZoneCAUpdate distributes a new Zone CA to trust alongside the
+	// current one.
+	MsgZoneCAUpdate uint8 = 34
+
+	// MsgZoneCARetire makes the given Zone CA the only trusted one.
+	MsgZoneCARetire uint8 = 35
+
+	// MsgZoneCAAck confirms a ZoneCAUpdate or ZoneCARetire.
+	MsgZoneCAAck uint8 = 36
+)
+
 // Renewal status codes.
 const (
 	RenewalStatusSuccess       uint8 = 0
@@ -57,6 +75,7 @@ const (
 	RenewalStatusInstallFailed uint8 = 2
 	RenewalStatusInvalidCert   uint8 = 3
 	RenewalStatusInvalidNonce  uint8 = 4 // DEC-047: Nonce binding validation failed
+	RenewalStatusUntrustedCA   uint8 = 5 // New Zone CA not endorsed by a trusted Zone CA
 )
 
 // CertRenewalRequest initiates certificate renewal.
@@ -104,6 +123,43 @@ type CertRenewalAck struct {
 	ActiveSequence uint32 `cbor:"3,keyasint"` // Sequence number of now-active cert
 }
 
+// ZoneCAUpdate distributes a new Zone CA during rotation.
+// Sent by controller to device over the operational connection.
+//
+// CrossCert certifies the new CA's subject and public key under the Zone CA
+// the device currently trusts, so the device only accepts roots endorsed by
+// its zone. The device trusts both CAs until it receives ZoneCARetire.
+//
+// CBOR: { 1: msgType, 10: zoneCA, 11: crossCert }
+type ZoneCAUpdate struct {
+	MsgType   uint8  `cbor:"1,keyasint"`
+	ZoneCA    []byte `cbor:"10,keyasint"` // X.509 DER-encoded new Zone CA cert
+	CrossCert []byte `cbor:"11,keyasint"` // X.509 DER-encoded cross-certificate
+}
+
+// ZoneCARetire ends a Zone CA rotation.
+// Sent by controller after the device holds a certificate from the new CA.
+// CBOR: { 1: msgType, 10: zoneCA }
+type ZoneCARetire struct {
+	MsgType uint8  `cbor:"1,keyasint"`
+	ZoneCA  []byte `cbor:"10,keyasint"` // X.509 DER-encoded Zone CA that remains trusted
+}
+
+// ZoneCAAck confirms a ZoneCAUpdate or ZoneCARetire.
+// Sent by device in response to either message.
+// CBOR: { 1: msgType, 12: status, 13: zoneCAHash }
+type ZoneCAAck struct {
+	MsgType    uint8  `cbor:"1,keyasint"`
+	Status     uint8  `cbor:"12,keyasint"` // 0=success, see RenewalStatus* constants
+	ZoneCAHash []byte `cbor:"13,keyasint"` // SHA256 of the Zone CA acted on
+}
+
+// ComputeZoneCAHash computes SHA256(zoneCA) for ZoneCAAck.ZoneCAHash.
+func ComputeZoneCAHash(zoneCA []byte) []byte {
+	hash := sha256.Sum256(zoneCA)
+	return hash[:]
+}
+
 // EncodeRenewalMessage encodes a renewal message to CBOR bytes.
 func EncodeRenewalMessage(msg any) ([]byte, error) {
 	return cbor.Marshal(msg)
@@ -149,6 +205,27 @@ func DecodeRenewalMessage(data []byte) (any, error) {
 		}
 		return &msg, nil
 
+	case MsgZoneCAUpdate:
+		var msg ZoneCAUpdate
+		if err := cbor.Unmarshal(data, &msg); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidMessage, err)
+		}
+		return &msg, nil
+
+	case MsgZoneCARetire:
+		var msg ZoneCARetire
+		if err := cbor.Unmarshal(data, &msg); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidMessage, err)
+		}
+		return &msg, nil
+
+	case MsgZoneCAAck:
+		var msg ZoneCAAck
+		if err := cbor.Unmarshal(data, &msg); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidMessage, err)
+		}
+		return &msg, nil
+
 	default:
 		return nil, fmt.Errorf("%w: unknown renewal message type %d", ErrInvalidMessage, header.MsgType)
 	}
@@ -165,6 +242,12 @@ func RenewalMessageType(msg any) uint8 {
 		return m.MsgType
 	case *CertRenewalAck:
 		return m.MsgType
+	case *ZoneCAUpdate:
+		return m.MsgType
+	case *ZoneCARetire:
+		return m.MsgType
+	case *ZoneCAAck:
+		return m.MsgType
 	default:
 		return 0
 	}
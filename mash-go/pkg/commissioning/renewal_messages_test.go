@@ -320,6 +320,73 @@ func TestDecodeRenewalMessage(t *testing.T) {
 		}
 	})
 
+	t.Run("ZoneCAUpdate", func(t *testing.T) {
+		update := &commissioning.ZoneCAUpdate{
+			MsgType:   commissioning.MsgZoneCAUpdate,
+			ZoneCA:    []byte{0x30, 0x82},
+			CrossCert: []byte{0x30, 0x83},
+		}
+		data, _ := cbor.Marshal(update)
+
+		decoded, err := commissioning.DecodeRenewalMessage(data)
+		if err != nil {
+			t.Fatalf("Failed to decode: %v", err)
+		}
+
+		got, ok := decoded.(*commissioning.ZoneCAUpdate)
+		if !ok {
+			t.Fatalf("Expected *ZoneCAUpdate, got %T", decoded)
+		}
+		if !bytes.Equal(got.CrossCert, update.CrossCert) {
+			t.Errorf("CrossCert mismatch: got %x", got.CrossCert)
+		}
+		if commissioning.RenewalMessageType(decoded) != commissioning.MsgZoneCAUpdate {
+			t.Errorf("RenewalMessageType = %d", commissioning.RenewalMessageType(decoded))
+		}
+	})
+
+	t.Run("ZoneCARetire", func(t *testing.T) {
+		retire := &commissioning.ZoneCARetire{
+			MsgType: commissioning.MsgZoneCARetire,
+			ZoneCA:  []byte{0x30, 0x82},
+		}
+		data, _ := cbor.Marshal(retire)
+
+		decoded, err := commissioning.DecodeRenewalMessage(data)
+		if err != nil {
+			t.Fatalf("Failed to decode: %v", err)
+		}
+
+		if _, ok := decoded.(*commissioning.ZoneCARetire); !ok {
+			t.Errorf("Expected *ZoneCARetire, got %T", decoded)
+		}
+	})
+
+	t.Run("ZoneCAAck", func(t *testing.T) {
+		ack := &commissioning.ZoneCAAck{
+			MsgType:    commissioning.MsgZoneCAAck,
+			Status:     commissioning.RenewalStatusUntrustedCA,
+			ZoneCAHash: commissioning.ComputeZoneCAHash([]byte{0x30, 0x82}),
+		}
+		data, _ := cbor.Marshal(ack)
+
+		decoded, err := commissioning.DecodeRenewalMessage(data)
+		if err != nil {
+			t.Fatalf("Failed to decode: %v", err)
+		}
+
+		got, ok := decoded.(*commissioning.ZoneCAAck)
+		if !ok {
+			t.Fatalf("Expected *ZoneCAAck, got %T", decoded)
+		}
+		if got.Status != commissioning.RenewalStatusUntrustedCA {
+			t.Errorf("Status = %d, want %d", got.Status, commissioning.RenewalStatusUntrustedCA)
+		}
+		if len(got.ZoneCAHash) != 32 {
+			t.Errorf("ZoneCAHash length = %d, want 32", len(got.ZoneCAHash))
+		}
+	})
+
 	t.Run("UnknownType", func(t *testing.T) {
 		// Create message with unknown type
 		data, _ := cbor.Marshal(map[int]any{1: 99})
@@ -0,0 +1,241 @@
+package commissioning
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/fxamacker/cbor/v2"
+)
+
+// Commissioning ticket errors.
+var (
+	ErrInvalidTicket   = errors.New("invalid commissioning ticket")
+	ErrTicketSignature = fmt.Errorf("%w: bad signature", ErrInvalidTicket)
+	ErrUnknownIssuer   = fmt.Errorf("%w: unknown issuer", ErrInvalidTicket)
+	ErrTicketExpired   = fmt.Errorf("%w: expired", ErrInvalidTicket)
+	ErrTicketNotYet    = fmt.Errorf("%w: not yet valid", ErrInvalidTicket)
+	ErrTicketUsed      = fmt.Errorf("%w: already used", ErrInvalidTicket)
+)
+
+// ticketContext separates ticket signatures from any other use of an
+// issuer key.
+const ticketContext = "MASH commissioning ticket v1"
+
+// Ticket authorises a controller to commission one device on behalf of an
+// external party such as a DSO backend (security.md §9). It carries what the
+// user scanned from the device's QR code plus the issuer's constraints.
+type Ticket struct {
+	// ID identifies the ticket to the issuer so outcomes can be reported back.
+	ID string `cbor:"1,keyasint"`
+
+	// Issuer names the party that signed the ticket. It selects the
+	// verification key.
+	Issuer string `cbor:"2,keyasint"`
+
+	// Discriminator is the device discriminator from the QR code.
+	Discriminator uint16 `cbor:"3,keyasint"`
+
+	// SetupCode is the 8-digit setup code from the QR code.
+	SetupCode string `cbor:"4,keyasint"`
+
+	// DeviceID is the DeviceInfo deviceId the commissioned device must
+	// report. Empty accepts any device that completes PASE.
+	DeviceID string `cbor:"5,keyasint,omitempty"`
+
+	// NotBefore is when the ticket becomes valid (Unix seconds).
+	NotBefore int64 `cbor:"6,keyasint"`
+
+	// NotAfter is when the ticket expires (Unix seconds). Commissioning
+	// must complete before then.
+	NotAfter int64 `cbor:"7,keyasint"`
+}
+
+// SignedTicket is the wire form of a ticket: the encoded Ticket and the
+// issuer's signature over it. Signing the encoded bytes rather than the
+// struct means verification never depends on re-encoding.
+type SignedTicket struct {
+	// Ticket is the CBOR-encoded Ticket.
+	Ticket []byte `cbor:"1,keyasint"`
+
+	// Signature is the issuer's ECDSA signature (ASN.1) over Ticket.
+	Signature []byte `cbor:"2,keyasint"`
+}
+
+// Validate checks the ticket's fields, ignoring time.
+func (t *Ticket) Validate() error {
+	if t.ID == "" {
+		return fmt.Errorf("%w: missing ID", ErrInvalidTicket)
+	}
+	if t.Issuer == "" {
+		return fmt.Errorf("%w: missing issuer", ErrInvalidTicket)
+	}
+	if t.Discriminator > DiscriminatorMax {
+		return fmt.Errorf("%w: discriminator %d out of range", ErrInvalidTicket, t.Discriminator)
+	}
+	sc, err := ParseSetupCode(t.SetupCode)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidTicket, err)
+	}
+	if err := sc.Validate(); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidTicket, err)
+	}
+	if t.NotAfter <= t.NotBefore {
+		return fmt.Errorf("%w: notAfter must be after notBefore", ErrInvalidTicket)
+	}
+	return nil
+}
+
+// Expires returns NotAfter as a time.
+func (t *Ticket) Expires() time.Time {
+	return time.Unix(t.NotAfter, 0)
+}
+
+// CheckTime checks that the ticket is valid at now.
+func (t *Ticket) CheckTime(now time.Time) error {
+	if now.Before(time.Unix(t.NotBefore, 0)) {
+		return ErrTicketNotYet
+	}
+	if !now.Before(t.Expires()) {
+		return ErrTicketExpired
+	}
+	return nil
+}
+
+// SignTicket encodes t and signs it with the issuer's key. The result is
+// what an issuer hands to a controller.
+func SignTicket(t *Ticket, key crypto.Signer) ([]byte, error) {
+	if err := t.Validate(); err != nil {
+		return nil, err
+	}
+
+	body, err := cbor.Marshal(t)
+	if err != nil {
+		return nil, fmt.Errorf("encode ticket: %w", err)
+	}
+	sig, err := key.Sign(rand.Reader, ticketDigest(body), crypto.SHA256)
+	if err != nil {
+		return nil, fmt.Errorf("sign ticket: %w", err)
+	}
+
+	return cbor.Marshal(&SignedTicket{Ticket: body, Signature: sig})
+}
+
+// VerifyTicket decodes a signed ticket, checks its signature against the
+// key configured for its issuer and checks that it is valid at now.
+func VerifyTicket(data []byte, issuers map[string]*ecdsa.PublicKey, now time.Time) (*Ticket, error) {
+	var signed SignedTicket
+	if err := cbor.Unmarshal(data, &signed); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidTicket, err)
+	}
+
+	var t Ticket
+	if err := cbor.Unmarshal(signed.Ticket, &t); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidTicket, err)
+	}
+
+	pub, ok := issuers[t.Issuer]
+	if !ok || pub == nil {
+		return nil, fmt.Errorf("%w %q", ErrUnknownIssuer, t.Issuer)
+	}
+	if !ecdsa.VerifyASN1(pub, ticketDigest(signed.Ticket), signed.Signature) {
+		return nil, ErrTicketSignature
+	}
+
+	if err := t.Validate(); err != nil {
+		return nil, err
+	}
+	if err := t.CheckTime(now); err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// TicketLedger records the tickets a controller has accepted so that none
+// is used twice. A ticket is remembered until it expires, after which
+// VerifyTicket rejects it anyway. It is safe for concurrent use.
+type TicketLedger struct {
+	mu   sync.Mutex
+	used map[ticketKey]int64 // -> NotAfter
+}
+
+// ticketKey identifies a ticket; IDs are per issuer.
+type ticketKey struct {
+	issuer string
+	id     string
+}
+
+// NewTicketLedger creates an empty ticket ledger.
+func NewTicketLedger() *TicketLedger {
+	return &TicketLedger{used: make(map[ticketKey]int64)}
+}
+
+// Use records t as used at now. It returns ErrTicketUsed if t was used
+// before and has not expired yet.
+func (l *TicketLedger) Use(t *Ticket, now time.Time) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for key, notAfter := range l.used {
+		if !now.Before(time.Unix(notAfter, 0)) {
+			delete(l.used, key)
+		}
+	}
+
+	key := ticketKey{t.Issuer, t.ID}
+	if _, ok := l.used[key]; ok {
+		return fmt.Errorf("%w: %q", ErrTicketUsed, t.ID)
+	}
+	l.used[key] = t.NotAfter
+	return nil
+}
+
+// UsedTicket is a ticket recorded in a TicketLedger. It is what a
+// controller persists so that tickets stay used across restarts.
+type UsedTicket struct {
+	Issuer   string
+	ID       string
+	NotAfter int64 // Unix seconds
+}
+
+// Snapshot returns the tickets currently recorded as used.
+func (l *TicketLedger) Snapshot() []UsedTicket {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	used := make([]UsedTicket, 0, len(l.used))
+	for key, notAfter := range l.used {
+		used = append(used, UsedTicket{Issuer: key.issuer, ID: key.id, NotAfter: notAfter})
+	}
+	return used
+}
+
+// Restore records the tickets of a previous Snapshot as used.
+func (l *TicketLedger) Restore(used []UsedTicket) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for _, u := range used {
+		l.used[ticketKey{u.Issuer, u.ID}] = u.NotAfter
+	}
+}
+
+// Release forgets t, so that it can be used again after an attempt that
+// did not commission a device.
+func (l *TicketLedger) Release(t *Ticket) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	delete(l.used, ticketKey{t.Issuer, t.ID})
+}
+
+func ticketDigest(body []byte) []byte {
+	h := sha256.New()
+	h.Write([]byte(ticketContext))
+	h.Write(body)
+	return h.Sum(nil)
+}
@@ -0,0 +1,197 @@
+package commissioning
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/fxamacker/cbor/v2"
+)
+
+func testTicket(now time.Time) *Ticket {
+	return &Ticket{
+		ID:            "ticket-1",
+		Issuer:        "dso.example",
+		Discriminator: 1234,
+		SetupCode:     "20202021",
+		DeviceID:      "PEN12345.EVSE-001",
+		NotBefore:     now.Add(-time.Minute).Unix(),
+		NotAfter:      now.Add(time.Hour).Unix(),
+	}
+}
+
+func TestTicketSignVerify(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Unix(1_800_000_000, 0)
+	issuers := map[string]*ecdsa.PublicKey{"dso.example": &key.PublicKey}
+
+	data, err := SignTicket(testTicket(now), key)
+	if err != nil {
+		t.Fatalf("SignTicket: %v", err)
+	}
+
+	got, err := VerifyTicket(data, issuers, now)
+	if err != nil {
+		t.Fatalf("VerifyTicket: %v", err)
+	}
+	if *got != *testTicket(now) {
+		t.Errorf("VerifyTicket = %+v, want %+v", got, testTicket(now))
+	}
+
+	tests := []struct {
+		name    string
+		data    func() []byte
+		issuers map[string]*ecdsa.PublicKey
+		now     time.Time
+		want    error
+	}{
+		{
+			name:    "wrong key",
+			data:    func() []byte { return data },
+			issuers: map[string]*ecdsa.PublicKey{"dso.example": &other.PublicKey},
+			now:     now,
+			want:    ErrTicketSignature,
+		},
+		{
+			name:    "unknown issuer",
+			data:    func() []byte { return data },
+			issuers: map[string]*ecdsa.PublicKey{"other.example": &key.PublicKey},
+			now:     now,
+			want:    ErrUnknownIssuer,
+		},
+		{
+			name:    "expired",
+			data:    func() []byte { return data },
+			issuers: issuers,
+			now:     now.Add(time.Hour),
+			want:    ErrTicketExpired,
+		},
+		{
+			name:    "not yet valid",
+			data:    func() []byte { return data },
+			issuers: issuers,
+			now:     now.Add(-2 * time.Minute),
+			want:    ErrTicketNotYet,
+		},
+		{
+			name: "tampered",
+			data: func() []byte {
+				var signed SignedTicket
+				if err := cbor.Unmarshal(data, &signed); err != nil {
+					t.Fatal(err)
+				}
+				var tk Ticket
+				if err := cbor.Unmarshal(signed.Ticket, &tk); err != nil {
+					t.Fatal(err)
+				}
+				tk.DeviceID = "PEN12345.EVSE-002"
+				signed.Ticket, _ = cbor.Marshal(&tk)
+				out, _ := cbor.Marshal(&signed)
+				return out
+			},
+			issuers: issuers,
+			now:     now,
+			want:    ErrTicketSignature,
+		},
+		{
+			name:    "garbage",
+			data:    func() []byte { return []byte{0xff, 0x00} },
+			issuers: issuers,
+			now:     now,
+			want:    ErrInvalidTicket,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := VerifyTicket(tt.data(), tt.issuers, tt.now)
+			if !errors.Is(err, tt.want) {
+				t.Errorf("VerifyTicket error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestTicketValidate(t *testing.T) {
+	now := time.Unix(1_800_000_000, 0)
+
+	tests := []struct {
+		name   string
+		modify func(*Ticket)
+	}{
+		{"missing ID", func(tk *Ticket) { tk.ID = "" }},
+		{"missing issuer", func(tk *Ticket) { tk.Issuer = "" }},
+		{"discriminator out of range", func(tk *Ticket) { tk.Discriminator = DiscriminatorMax + 1 }},
+		{"bad setup code", func(tk *Ticket) { tk.SetupCode = "1234" }},
+		{"prohibited setup code", func(tk *Ticket) { tk.SetupCode = "12345678" }},
+		{"empty validity", func(tk *Ticket) { tk.NotAfter = tk.NotBefore }},
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tk := testTicket(now)
+			tt.modify(tk)
+			if err := tk.Validate(); !errors.Is(err, ErrInvalidTicket) {
+				t.Errorf("Validate error = %v, want ErrInvalidTicket", err)
+			}
+			if _, err := SignTicket(tk, key); !errors.Is(err, ErrInvalidTicket) {
+				t.Errorf("SignTicket error = %v, want ErrInvalidTicket", err)
+			}
+		})
+	}
+}
+
+func TestTicketLedger(t *testing.T) {
+	now := time.Unix(1_800_000_000, 0)
+	ledger := NewTicketLedger()
+	tk := testTicket(now)
+
+	if err := ledger.Use(tk, now); err != nil {
+		t.Fatalf("first Use: %v", err)
+	}
+	if err := ledger.Use(tk, now.Add(time.Minute)); !errors.Is(err, ErrTicketUsed) {
+		t.Errorf("replay error = %v, want ErrTicketUsed", err)
+	}
+
+	// The same ID from another issuer is a different ticket
+	other := testTicket(now)
+	other.Issuer = "other.example"
+	if err := ledger.Use(other, now); err != nil {
+		t.Errorf("other issuer: %v", err)
+	}
+
+	// A released ticket can be used again
+	ledger.Release(tk)
+	if err := ledger.Use(tk, now); err != nil {
+		t.Errorf("Use after Release: %v", err)
+	}
+
+	// Expired tickets are forgotten
+	if err := ledger.Use(tk, tk.Expires()); err != nil {
+		t.Errorf("Use at expiry: %v", err)
+	}
+	if n := len(ledger.used); n != 1 {
+		t.Errorf("ledger holds %d tickets after expiry, want 1", n)
+	}
+
+	// A restored ledger still rejects the tickets it held
+	restored := NewTicketLedger()
+	restored.Restore(ledger.Snapshot())
+	if err := restored.Use(tk, tk.Expires().Add(-time.Second)); !errors.Is(err, ErrTicketUsed) {
+		t.Errorf("Use after Restore = %v, want ErrTicketUsed", err)
+	}
+}
@@ -97,7 +97,7 @@ func (a *MDNSAdvertiser) AdvertiseCommissionable(ctx context.Context, info *Comm
 	opts := a.serverOptions()
 
 	host := resolvedHost(info.Host)
-	ips := interfaceIPs(ifaces)
+	ips := advertisedIPs(info.Addresses, ifaces)
 
 	server, err := zeroconf.RegisterProxy(
 		instanceName,
@@ -170,7 +170,7 @@ func (a *MDNSAdvertiser) AdvertiseOperational(ctx context.Context, info *Operati
 	opts := a.serverOptions()
 
 	host := resolvedHost(info.Host)
-	ips := interfaceIPs(ifaces)
+	ips := advertisedIPs(info.Addresses, ifaces)
 
 	server, err := zeroconf.RegisterProxy(
 		instanceName,
@@ -387,6 +387,14 @@ func interfaceIPs(ifaces []net.Interface) []string {
 	return ips
 }
 
+// advertisedIPs returns addrs if set, else the interface addresses.
+func advertisedIPs(addrs []string, ifaces []net.Interface) []string {
+	if len(addrs) > 0 {
+		return addrs
+	}
+	return interfaceIPs(ifaces)
+}
+
 // AnnouncePairingRequest starts advertising a pairing request.
 // Controllers use this to signal devices that they want to commission them.
 func (a *MDNSAdvertiser) AnnouncePairingRequest(ctx context.Context, info *PairingRequestInfo) error {
@@ -338,6 +338,10 @@ type CommissionableInfo struct {
 
 	// Host is the hostname to advertise.
 	Host string
+
+	// Addresses are the IP addresses the service is reachable on. If
+	// empty, the addresses of the advertised interfaces are used.
+	Addresses []string
 }
 
 // OperationalInfo contains information for advertising an operational device.
@@ -362,6 +366,10 @@ type OperationalInfo struct {
 
 	// Host is the hostname to advertise.
 	Host string
+
+	// Addresses are the IP addresses the service is reachable on. If
+	// empty, the addresses of the advertised interfaces are used.
+	Addresses []string
 }
 
 // CommissionerInfo contains information for advertising a commissioner.
@@ -4,6 +4,8 @@ import (
 	"errors"
 	"sync"
 	"time"
+
+	"github.com/mash-protocol/mash-go/pkg/clock"
 )
 
 // Duration timer errors.
@@ -105,8 +107,11 @@ type Timer struct {
 	// Value is the command value that will be cleared on expiry
 	Value any
 
-	// timer is the Go timer for automatic expiry
-	timer *time.Timer
+	// timer is the timer for automatic expiry
+	timer clock.Timer
+
+	// clock is the manager's time source
+	clock clock.Clock
 }
 
 // ExpiresAt returns when the timer will expire.
@@ -116,7 +121,7 @@ func (t *Timer) ExpiresAt() time.Time {
 
 // RemainingTime returns time until expiry.
 func (t *Timer) RemainingTime() time.Duration {
-	remaining := t.Duration - time.Since(t.StartTime)
+	remaining := t.Duration - t.since()
 	if remaining < 0 {
 		return 0
 	}
@@ -125,7 +130,12 @@ func (t *Timer) RemainingTime() time.Duration {
 
 // IsExpired returns true if the timer has expired.
 func (t *Timer) IsExpired() bool {
-	return time.Since(t.StartTime) >= t.Duration
+	return t.since() >= t.Duration
+}
+
+// since returns the time elapsed since StartTime.
+func (t *Timer) since() time.Duration {
+	return clock.OrReal(t.clock).Since(t.StartTime)
 }
 
 // Manager manages duration timers for commands.
@@ -137,12 +147,22 @@ type Manager struct {
 
 	// Callback when timer expires
 	onExpiry func(zoneID uint8, cmdType CommandType, value any)
+
+	// Time source for timer start and expiry
+	clock clock.Clock
 }
 
 // NewManager creates a new duration timer manager.
 func NewManager() *Manager {
+	return NewManagerWithClock(clock.Real())
+}
+
+// NewManagerWithClock creates a duration timer manager that reads time
+// from c instead of the real clock.
+func NewManagerWithClock(c clock.Clock) *Manager {
 	return &Manager{
 		timers: make(map[timerKey]*Timer),
+		clock:  clock.OrReal(c),
 	}
 }
 
@@ -172,13 +192,14 @@ func (m *Manager) SetTimer(zoneID uint8, cmdType CommandType, duration time.Dura
 	// Create new timer
 	timer := &Timer{
 		Key:       key,
-		StartTime: time.Now(),
+		StartTime: m.clock.Now(),
 		Duration:  duration,
 		Value:     value,
+		clock:     m.clock,
 	}
 
 	// Set up automatic expiry
-	timer.timer = time.AfterFunc(duration, func() {
+	timer.timer = m.clock.AfterFunc(duration, func() {
 		m.expireTimer(key)
 	})
 
@@ -234,6 +255,7 @@ func (m *Manager) GetTimer(zoneID uint8, cmdType CommandType) *Timer {
 			StartTime: timer.StartTime,
 			Duration:  timer.Duration,
 			Value:     timer.Value,
+			clock:     timer.clock,
 		}
 	}
 	return nil
@@ -252,6 +274,7 @@ func (m *Manager) GetZoneTimers(zoneID uint8) []*Timer {
 				StartTime: timer.StartTime,
 				Duration:  timer.Duration,
 				Value:     timer.Value,
+				clock:     timer.clock,
 			})
 		}
 	}
@@ -4,6 +4,8 @@ import (
 	"sync"
 	"testing"
 	"time"
+
+	"github.com/mash-protocol/mash-go/pkg/clock"
 )
 
 func TestTimerBasic(t *testing.T) {
@@ -450,3 +452,45 @@ func TestTimerReplacementCancelsCallback(t *testing.T) {
 	}
 	mu.Unlock()
 }
+
+func TestManagerTimerExpiryWithFakeClock(t *testing.T) {
+	fc := clock.NewFake(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+	m := NewManagerWithClock(fc)
+
+	var expired []CommandType
+	m.OnExpiry(func(zoneID uint8, cmdType CommandType, value any) {
+		expired = append(expired, cmdType)
+	})
+
+	if err := m.SetTimer(1, CmdLimitConsumption, time.Hour, int64(5000000)); err != nil {
+		t.Fatalf("SetTimer() error = %v", err)
+	}
+	if err := m.SetTimer(1, CmdLimitProduction, 2*time.Hour, int64(3000000)); err != nil {
+		t.Fatalf("SetTimer() error = %v", err)
+	}
+
+	fc.Advance(45 * time.Minute)
+	timer := m.GetTimer(1, CmdLimitConsumption)
+	if timer == nil {
+		t.Fatal("GetTimer() = nil before expiry")
+	}
+	if got := timer.RemainingTime(); got != 15*time.Minute {
+		t.Errorf("RemainingTime() = %v, want 15m", got)
+	}
+
+	fc.Advance(15 * time.Minute)
+	if len(expired) != 1 || expired[0] != CmdLimitConsumption {
+		t.Fatalf("expired = %v, want [CmdLimitConsumption]", expired)
+	}
+	if m.Count() != 1 {
+		t.Errorf("Count() = %d, want 1", m.Count())
+	}
+
+	fc.Advance(time.Hour)
+	if len(expired) != 2 || expired[1] != CmdLimitProduction {
+		t.Fatalf("expired = %v, want [CmdLimitConsumption CmdLimitProduction]", expired)
+	}
+	if m.Count() != 0 {
+		t.Errorf("Count() = %d after expiry, want 0", m.Count())
+	}
+}
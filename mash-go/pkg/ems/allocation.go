@@ -0,0 +1,183 @@
+package ems
+
+import (
+	"cmp"
+	"slices"
+
+	"github.com/mash-protocol/mash-go/pkg/features"
+)
+
+// Load is a controllable device as seen by an allocation strategy.
+// All powers are in mW.
+type Load struct {
+	// ID identifies the device.
+	ID string
+
+	// DeviceType is the EnergyControl device type.
+	DeviceType features.DeviceType
+
+	// Priority orders loads for PriorityFairShare; higher is served first.
+	Priority int
+
+	// Power is the measured consumption.
+	Power int64
+
+	// MinPower is the lowest operating point. A load that cannot get at
+	// least MinPower is allocated 0 (paused).
+	MinPower int64
+
+	// MaxPower is the nominal maximum consumption.
+	MaxPower int64
+}
+
+// Strategy distributes the power budget available to controllable loads.
+//
+// Allocate returns a consumption limit for every load. The limits never
+// add up to more than budget, and each is either 0 or between the load's
+// MinPower and MaxPower. Strategies are deterministic: the same budget and
+// loads give the same result regardless of the order of loads.
+type Strategy interface {
+	Allocate(budget int64, loads []Load) map[string]int64
+}
+
+// Proportional shares the budget in proportion to each load's MaxPower.
+type Proportional struct{}
+
+// Allocate implements Strategy.
+func (Proportional) Allocate(budget int64, loads []Load) map[string]int64 {
+	return allocateWithMinimums(budget, sortedLoads(loads, nil), proportionalShares)
+}
+
+// PriorityFairShare serves loads in descending Priority. Loads with the
+// same priority share what is left equally, up to their MaxPower; a lower
+// priority only gets power that higher priorities cannot use.
+type PriorityFairShare struct{}
+
+// Allocate implements Strategy.
+func (PriorityFairShare) Allocate(budget int64, loads []Load) map[string]int64 {
+	return allocateByRank(budget, loads, func(l Load) int { return l.Priority })
+}
+
+// EVFirst serves EV chargers before all other loads, and otherwise behaves
+// like PriorityFairShare.
+type EVFirst struct{}
+
+// Allocate implements Strategy.
+func (EVFirst) Allocate(budget int64, loads []Load) map[string]int64 {
+	return allocateByRank(budget, loads, func(l Load) int {
+		if l.DeviceType == features.DeviceTypeEVSE {
+			return 1
+		}
+		return 0
+	})
+}
+
+// allocateByRank fair-shares the budget within each rank, highest first.
+func allocateByRank(budget int64, loads []Load, rank func(Load) int) map[string]int64 {
+	sorted := sortedLoads(loads, rank)
+	result := make(map[string]int64, len(loads))
+	for start := 0; start < len(sorted); {
+		end := start + 1
+		for end < len(sorted) && rank(sorted[end]) == rank(sorted[start]) {
+			end++
+		}
+		for id, limit := range allocateWithMinimums(budget, sorted[start:end], fairShares) {
+			result[id] = limit
+			budget -= limit
+		}
+		start = end
+	}
+	return result
+}
+
+// allocateWithMinimums computes shares for loads and pauses the last load
+// whose share is below its MinPower until every share is usable. loads
+// must be sorted with the most important first.
+func allocateWithMinimums(budget int64, loads []Load, shares func(int64, []Load) []int64) map[string]int64 {
+	result := make(map[string]int64, len(loads))
+	active := slices.Clone(loads)
+	for len(active) > 0 {
+		s := shares(max(budget, 0), active)
+		drop := -1
+		for i := len(active) - 1; i >= 0; i-- {
+			if s[i] < active[i].MinPower || s[i] <= 0 {
+				drop = i
+				break
+			}
+		}
+		if drop < 0 {
+			for i, l := range active {
+				result[l.ID] = s[i]
+			}
+			break
+		}
+		result[active[drop].ID] = 0
+		active = slices.Delete(active, drop, drop+1)
+	}
+	for _, l := range loads {
+		if _, ok := result[l.ID]; !ok {
+			result[l.ID] = 0
+		}
+	}
+	return result
+}
+
+// fairShares splits budget equally, capped at each load's MaxPower, and
+// redistributes what capped loads cannot use (water filling).
+func fairShares(budget int64, loads []Load) []int64 {
+	order := make([]int, len(loads))
+	for i := range order {
+		order[i] = i
+	}
+	slices.SortStableFunc(order, func(a, b int) int { return cmp.Compare(loads[a].MaxPower, loads[b].MaxPower) })
+
+	shares := make([]int64, len(loads))
+	remaining := budget
+	for n, i := range order {
+		share := min(remaining/int64(len(order)-n), max(loads[i].MaxPower, 0))
+		shares[i] = share
+		remaining -= share
+	}
+	return shares
+}
+
+// proportionalShares splits budget in proportion to MaxPower, capped at
+// MaxPower.
+func proportionalShares(budget int64, loads []Load) []int64 {
+	var total int64
+	for _, l := range loads {
+		total += max(l.MaxPower, 0)
+	}
+	shares := make([]int64, len(loads))
+	if total == 0 {
+		return shares
+	}
+	if budget >= total {
+		for i, l := range loads {
+			shares[i] = max(l.MaxPower, 0)
+		}
+		return shares
+	}
+	// float64 avoids overflowing budget*MaxPower; flooring keeps the sum
+	// within budget
+	ratio := float64(budget) / float64(total)
+	for i, l := range loads {
+		shares[i] = int64(float64(max(l.MaxPower, 0)) * ratio)
+	}
+	return shares
+}
+
+// sortedLoads orders loads by descending rank, then ID. A nil rank sorts by
+// ID only.
+func sortedLoads(loads []Load, rank func(Load) int) []Load {
+	sorted := slices.Clone(loads)
+	slices.SortFunc(sorted, func(a, b Load) int {
+		if rank != nil {
+			if c := cmp.Compare(rank(b), rank(a)); c != 0 {
+				return c
+			}
+		}
+		return cmp.Compare(a.ID, b.ID)
+	})
+	return sorted
+}
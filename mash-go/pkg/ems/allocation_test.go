@@ -0,0 +1,135 @@
+package ems
+
+import (
+	"maps"
+	"math/rand/v2"
+	"testing"
+
+	"github.com/mash-protocol/mash-go/pkg/features"
+)
+
+const kW = 1_000_000
+
+func wallbox(id string, priority int) Load {
+	return Load{ID: id, DeviceType: features.DeviceTypeEVSE, Priority: priority, MinPower: 4 * kW, MaxPower: 11 * kW}
+}
+
+func heatPump(id string, priority int) Load {
+	return Load{ID: id, DeviceType: features.DeviceTypeHeatPump, Priority: priority, MinPower: 1 * kW, MaxPower: 6 * kW}
+}
+
+func TestStrategies(t *testing.T) {
+	tests := []struct {
+		name     string
+		strategy Strategy
+		budget   int64
+		loads    []Load
+		want     map[string]int64
+	}{
+		{
+			name:     "proportional, enough budget",
+			strategy: Proportional{},
+			budget:   100 * kW,
+			loads:    []Load{wallbox("ev1", 0), heatPump("hp1", 0)},
+			want:     map[string]int64{"ev1": 11 * kW, "hp1": 6 * kW},
+		},
+		{
+			name:     "proportional share of capacity",
+			strategy: Proportional{},
+			budget:   17 * kW / 2,
+			loads:    []Load{wallbox("ev1", 0), heatPump("hp1", 0)},
+			want:     map[string]int64{"ev1": 5_500_000, "hp1": 3 * kW},
+		},
+		{
+			name:     "proportional pauses loads below minimum",
+			strategy: Proportional{},
+			budget:   10 * kW,
+			loads:    []Load{wallbox("ev1", 0), wallbox("ev2", 0), wallbox("ev3", 0)},
+			want:     map[string]int64{"ev1": 5 * kW, "ev2": 5 * kW, "ev3": 0},
+		},
+		{
+			name:     "fair share redistributes capped loads",
+			strategy: PriorityFairShare{},
+			budget:   20 * kW,
+			loads:    []Load{wallbox("ev1", 0), wallbox("ev2", 0), heatPump("hp1", 0)},
+			want:     map[string]int64{"ev1": 7 * kW, "ev2": 7 * kW, "hp1": 6 * kW},
+		},
+		{
+			name:     "priority served first",
+			strategy: PriorityFairShare{},
+			budget:   15 * kW,
+			loads:    []Load{wallbox("ev1", 0), wallbox("ev2", 5), heatPump("hp1", 1)},
+			want:     map[string]int64{"ev2": 11 * kW, "hp1": 4 * kW, "ev1": 0},
+		},
+		{
+			name:     "EV first",
+			strategy: EVFirst{},
+			budget:   18 * kW,
+			loads:    []Load{heatPump("hp1", 9), wallbox("ev1", 0), wallbox("ev2", 0)},
+			want:     map[string]int64{"ev1": 9 * kW, "ev2": 9 * kW, "hp1": 0},
+		},
+		{
+			name:     "no budget",
+			strategy: EVFirst{},
+			budget:   -3 * kW,
+			loads:    []Load{heatPump("hp1", 0), wallbox("ev1", 0)},
+			want:     map[string]int64{"ev1": 0, "hp1": 0},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.strategy.Allocate(tt.budget, tt.loads)
+			if !maps.Equal(got, tt.want) {
+				t.Errorf("Allocate = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestStrategies_Invariants checks the Strategy contract on random sites.
+func TestStrategies_Invariants(t *testing.T) {
+	rng := rand.New(rand.NewPCG(1, 2))
+	strategies := map[string]Strategy{
+		"proportional": Proportional{},
+		"fair share":   PriorityFairShare{},
+		"EV first":     EVFirst{},
+	}
+
+	for range 200 {
+		loads := make([]Load, 1+rng.IntN(12))
+		for i := range loads {
+			if rng.IntN(2) == 0 {
+				loads[i] = wallbox(string(rune('a'+i)), rng.IntN(3))
+			} else {
+				loads[i] = heatPump(string(rune('a'+i)), rng.IntN(3))
+			}
+		}
+		budget := rng.Int64N(100 * kW)
+
+		for name, s := range strategies {
+			got := s.Allocate(budget, loads)
+
+			var total int64
+			for _, l := range loads {
+				limit, ok := got[l.ID]
+				if !ok {
+					t.Fatalf("%s: no limit for %s", name, l.ID)
+				}
+				if limit != 0 && (limit < l.MinPower || limit > l.MaxPower) {
+					t.Fatalf("%s: limit %d for %s outside [%d, %d]", name, limit, l.ID, l.MinPower, l.MaxPower)
+				}
+				total += limit
+			}
+			if total > budget {
+				t.Fatalf("%s: allocated %d, budget %d", name, total, budget)
+			}
+
+			// Order of loads does not matter
+			shuffled := append([]Load(nil), loads...)
+			rng.Shuffle(len(shuffled), func(i, j int) { shuffled[i], shuffled[j] = shuffled[j], shuffled[i] })
+			if again := s.Allocate(budget, shuffled); !maps.Equal(got, again) {
+				t.Fatalf("%s: result depends on load order: %v vs %v", name, got, again)
+			}
+		}
+	}
+}
@@ -0,0 +1,32 @@
+// Package ems keeps a site under the limit of its grid connection point by
+// distributing consumption limits over the controllable devices of a zone.
+//
+// A Manager reads each device's Electrical capacity and EnergyControl
+// capabilities, follows its Measurement active power and, on every
+// Rebalance, lets a Strategy split the budget (site limit minus the load it
+// does not control) into per-device limits. Limits are sent with SetLimit,
+// or SetCurrentLimits for devices that only accept per-phase currents:
+//
+//	m := ems.NewManager(ems.Config{SiteLimit: 35_000_000, Strategy: ems.EVFirst{}})
+//	session := svc.GetSession(deviceID)
+//	session.SetNotificationHandler(func(n *wire.Notification) { m.HandleNotification(deviceID, n) })
+//	_ = m.AddDevice(ctx, deviceID, session, 1, 0)
+//	go m.Run(ctx)
+//
+// To avoid thrashing devices, a limit is only raised when it grows by at
+// least Config.Hysteresis, and at most once per Config.MinUpdateInterval.
+// Lowered limits are always sent, immediately and before raised ones, so
+// the site stays under its limit while devices are updated; if a device
+// does not apply its lowered limit, nothing is raised in that Rebalance.
+// Without a site limit (Config.SiteLimit zero) the Manager sends nothing.
+//
+// Strategies are pure functions of the budget and the loads and can be
+// tested, or replaced, without a network.
+//
+// An Optimizer schedules a flexible device against price and CO2 slots.
+// Optimize turns the device's Plan (the power range of each slot), the
+// energy it needs and its departure time into a cost-minimal Schedule;
+// Negotiate requests the device's plan, accepts it if it is close enough
+// to that schedule, and otherwise sends the schedule as a constraint
+// signal.
+package ems
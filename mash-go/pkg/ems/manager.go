@@ -0,0 +1,444 @@
+package ems
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/mash-protocol/mash-go/pkg/clock"
+	"github.com/mash-protocol/mash-go/pkg/featureclient"
+	"github.com/mash-protocol/mash-go/pkg/features"
+	"github.com/mash-protocol/mash-go/pkg/interaction"
+	"github.com/mash-protocol/mash-go/pkg/wire"
+)
+
+// Manager errors.
+var (
+	ErrDeviceExists    = errors.New("device already managed")
+	ErrDeviceNotFound  = errors.New("device not managed")
+	ErrNotControllable = errors.New("device accepts neither power nor current limits")
+	ErrNoCapacity      = errors.New("device reports no maximum consumption")
+)
+
+// Config configures a Manager.
+type Config struct {
+	// SiteLimit is the maximum import at the grid connection point in mW.
+	// Zero or less means no site limit: Rebalance sends nothing.
+	SiteLimit int64
+
+	// Strategy distributes the budget. Default: PriorityFairShare.
+	Strategy Strategy
+
+	// Hysteresis is the smallest increase of a device's limit, in mW, that
+	// is sent. Decreases are always sent. Default: 500 W.
+	Hysteresis int64
+
+	// MinUpdateInterval is how long a device's limit is held before it is
+	// raised again. Lowering a limit is never delayed. Default: 10s.
+	MinUpdateInterval time.Duration
+
+	// Interval is how often Run rebalances. Default: 5s.
+	Interval time.Duration
+
+	// Cause is sent with every limit. Default: LimitCauseLocalProtection.
+	Cause features.LimitCause
+
+	// Clock is the time source. Nil uses the real clock.
+	Clock clock.Clock
+}
+
+// DefaultConfig returns a Config with sensible defaults and no site limit,
+// so a Manager built from it leaves devices alone until SetSiteLimit.
+func DefaultConfig() Config {
+	return Config{
+		Strategy:          PriorityFairShare{},
+		Hysteresis:        500_000,
+		MinUpdateInterval: 10 * time.Second,
+		Interval:          5 * time.Second,
+		Cause:             features.LimitCauseLocalProtection,
+	}
+}
+
+// Manager keeps a site under its grid connection limit by distributing
+// consumption limits over the devices it manages.
+type Manager struct {
+	config Config
+	clock  clock.Clock
+
+	// Serializes rebalancing
+	rebalanceMu sync.Mutex
+
+	mu        sync.Mutex
+	siteLimit int64
+	baseLoad  int64
+	devices   map[string]*managedDevice
+}
+
+// managedDevice is the Manager's view of one device.
+type managedDevice struct {
+	client     featureclient.DeviceClient
+	endpointID uint8
+	load       Load
+
+	// Current-only devices are limited per phase
+	useCurrentLimits bool
+	phaseCount       int64
+	voltage          int64
+
+	subscriptionID uint32
+
+	// Last limit sent, and when
+	limit     *int64
+	updatedAt time.Time
+}
+
+// NewManager creates a Manager. Zero config fields take their defaults.
+func NewManager(config Config) *Manager {
+	defaults := DefaultConfig()
+	if config.Strategy == nil {
+		config.Strategy = defaults.Strategy
+	}
+	if config.Hysteresis <= 0 {
+		config.Hysteresis = defaults.Hysteresis
+	}
+	if config.MinUpdateInterval <= 0 {
+		config.MinUpdateInterval = defaults.MinUpdateInterval
+	}
+	if config.Interval <= 0 {
+		config.Interval = defaults.Interval
+	}
+	return &Manager{
+		config:    config,
+		clock:     clock.OrReal(config.Clock),
+		siteLimit: config.SiteLimit,
+		devices:   make(map[string]*managedDevice),
+	}
+}
+
+// AddDevice starts managing the device behind client. It reads the
+// endpoint's Electrical capacity and EnergyControl capabilities and
+// subscribes to its Measurement active power. Notifications must be passed
+// to HandleNotification.
+func (m *Manager) AddDevice(ctx context.Context, deviceID string, client featureclient.DeviceClient, endpointID uint8, priority int) error {
+	m.mu.Lock()
+	_, exists := m.devices[deviceID]
+	m.mu.Unlock()
+	if exists {
+		return fmt.Errorf("%w: %s", ErrDeviceExists, deviceID)
+	}
+
+	ec, err := featureclient.NewEnergyControlClient(client, endpointID).Read(ctx,
+		features.EnergyControlAttrDeviceType,
+		features.EnergyControlAttrAcceptsLimits,
+		features.EnergyControlAttrAcceptsCurrentLimits,
+	)
+	if err != nil {
+		return fmt.Errorf("read EnergyControl: %w", err)
+	}
+	elec, err := featureclient.NewElectricalClient(client, endpointID).Read(ctx)
+	if err != nil {
+		return fmt.Errorf("read Electrical: %w", err)
+	}
+
+	device, err := newManagedDevice(deviceID, priority, ec, elec)
+	if err != nil {
+		return fmt.Errorf("%s: %w", deviceID, err)
+	}
+	device.client = client
+	device.endpointID = endpointID
+
+	subID, meas, err := featureclient.NewMeasurementClient(client, endpointID).Subscribe(ctx, &interaction.SubscribeOptions{
+		AttributeIDs: []uint16{features.MeasurementAttrACActivePower},
+	})
+	if err != nil {
+		return fmt.Errorf("subscribe to Measurement: %w", err)
+	}
+	device.subscriptionID = subID
+	if meas.ACActivePower != nil {
+		device.load.Power = *meas.ACActivePower
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, exists := m.devices[deviceID]; exists {
+		return fmt.Errorf("%w: %s", ErrDeviceExists, deviceID)
+	}
+	m.devices[deviceID] = device
+	return nil
+}
+
+// newManagedDevice derives a device's load from its capabilities.
+func newManagedDevice(deviceID string, priority int, ec *featureclient.EnergyControlAttributes, elec *featureclient.ElectricalAttributes) (*managedDevice, error) {
+	device := &managedDevice{
+		load: Load{ID: deviceID, Priority: priority, DeviceType: features.DeviceTypeOther},
+	}
+	if ec.DeviceType != nil {
+		device.load.DeviceType = *ec.DeviceType
+	}
+
+	acceptsLimits := ec.AcceptsLimits != nil && *ec.AcceptsLimits
+	acceptsCurrent := ec.AcceptsCurrentLimits != nil && *ec.AcceptsCurrentLimits
+	if !acceptsLimits && !acceptsCurrent {
+		return nil, ErrNotControllable
+	}
+
+	device.phaseCount = 1
+	if elec.PhaseCount != nil && *elec.PhaseCount > 0 {
+		device.phaseCount = int64(*elec.PhaseCount)
+	}
+	if elec.NominalVoltage != nil {
+		device.voltage = int64(*elec.NominalVoltage)
+	}
+	// mA * V = mW
+	perPhaseToPower := func(current *int64) int64 {
+		if current == nil {
+			return 0
+		}
+		return *current * device.voltage * device.phaseCount
+	}
+
+	if elec.NominalMaxConsumption != nil && *elec.NominalMaxConsumption > 0 {
+		device.load.MaxPower = *elec.NominalMaxConsumption
+	} else {
+		device.load.MaxPower = perPhaseToPower(elec.MaxCurrentPerPhase)
+	}
+	if elec.NominalMinPower != nil {
+		device.load.MinPower = *elec.NominalMinPower
+	} else {
+		device.load.MinPower = perPhaseToPower(elec.MinCurrentPerPhase)
+	}
+	if device.load.MaxPower <= 0 {
+		return nil, ErrNoCapacity
+	}
+
+	if !acceptsLimits {
+		if device.voltage == 0 {
+			return nil, fmt.Errorf("%w: current limits need a nominal voltage", ErrNotControllable)
+		}
+		device.useCurrentLimits = true
+	}
+	return device, nil
+}
+
+// RemoveDevice stops managing a device and ends its Measurement
+// subscription. The last limit sent stays in force on the device until the
+// caller clears it.
+func (m *Manager) RemoveDevice(ctx context.Context, deviceID string) error {
+	m.mu.Lock()
+	device, ok := m.devices[deviceID]
+	delete(m.devices, deviceID)
+	m.mu.Unlock()
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrDeviceNotFound, deviceID)
+	}
+	return device.client.Unsubscribe(ctx, device.subscriptionID)
+}
+
+// HandleNotification applies a subscription notification from deviceID and
+// reports whether it was the Manager's Measurement subscription.
+func (m *Manager) HandleNotification(deviceID string, notif *wire.Notification) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	device, ok := m.devices[deviceID]
+	if !ok || notif.SubscriptionID != device.subscriptionID {
+		return false
+	}
+	attrs := featureclient.DecodeMeasurementAttributes(notif.Changes)
+	if attrs.ACActivePower != nil {
+		device.load.Power = *attrs.ACActivePower
+	}
+	return true
+}
+
+// SetSiteLimit changes the grid connection limit (mW). It takes effect on
+// the next Rebalance. Zero or less means no site limit; limits already sent
+// stay in place.
+func (m *Manager) SetSiteLimit(limit int64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.siteLimit = limit
+}
+
+// SetBaseLoad sets the site consumption that the Manager does not control,
+// in mW (for example a grid meter reading minus ManagedPower). It is
+// subtracted from the site limit before allocation.
+func (m *Manager) SetBaseLoad(power int64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.baseLoad = power
+}
+
+// ManagedPower returns the measured consumption of the managed devices.
+func (m *Manager) ManagedPower() int64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var total int64
+	for _, device := range m.devices {
+		total += device.load.Power
+	}
+	return total
+}
+
+// SitePower returns the estimated power at the grid connection point:
+// the base load plus the managed devices' measured consumption.
+func (m *Manager) SitePower() int64 {
+	power := m.ManagedPower()
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.baseLoad + power
+}
+
+// Limits returns the last limit sent to each managed device (mW). Devices
+// that have not been limited yet are absent.
+func (m *Manager) Limits() map[string]int64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	result := make(map[string]int64, len(m.devices))
+	for id, device := range m.devices {
+		if device.limit != nil {
+			result[id] = *device.limit
+		}
+	}
+	return result
+}
+
+// Run rebalances every Interval until ctx is done.
+func (m *Manager) Run(ctx context.Context) error {
+	ticker := m.clock.NewTicker(m.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C():
+			_ = m.Rebalance(ctx)
+		}
+	}
+}
+
+// limitUpdate is a limit to send to one device.
+type limitUpdate struct {
+	id     string
+	device *managedDevice
+	limit  int64
+}
+
+// Rebalance allocates the budget (site limit minus base load) and sends
+// the limits that changed. Lowered limits are sent first so the site does
+// not exceed its limit while devices are being updated; if any of them is
+// not applied, no limit is raised and the errors are returned. Without a
+// site limit Rebalance does nothing.
+func (m *Manager) Rebalance(ctx context.Context) error {
+	m.rebalanceMu.Lock()
+	defer m.rebalanceMu.Unlock()
+
+	m.mu.Lock()
+	if m.siteLimit <= 0 {
+		m.mu.Unlock()
+		return nil
+	}
+	budget := m.siteLimit - m.baseLoad
+	loads := make([]Load, 0, len(m.devices))
+	for _, device := range m.devices {
+		loads = append(loads, device.load)
+	}
+	allocation := m.config.Strategy.Allocate(budget, loads)
+
+	now := m.clock.Now()
+	var lower, raise []limitUpdate
+	for id, limit := range allocation {
+		device, ok := m.devices[id]
+		if !ok {
+			continue
+		}
+		switch {
+		case device.limit == nil:
+			// An unlimited device can only go down
+			lower = append(lower, limitUpdate{id, device, limit})
+		case limit < *device.limit:
+			// Any decrease keeps the site under its limit
+			lower = append(lower, limitUpdate{id, device, limit})
+		case limit > *device.limit && limit-*device.limit >= m.config.Hysteresis,
+			limit != 0 && *device.limit == 0:
+			if now.Sub(device.updatedAt) >= m.config.MinUpdateInterval {
+				raise = append(raise, limitUpdate{id, device, limit})
+			}
+		}
+	}
+	m.mu.Unlock()
+
+	byID := func(a, b limitUpdate) int { return cmp.Compare(a.id, b.id) }
+	slices.SortFunc(lower, byID)
+	slices.SortFunc(raise, byID)
+
+	var errs []error
+	for _, u := range lower {
+		if err := m.sendLimit(ctx, u); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", u.id, err))
+		}
+	}
+	if len(errs) > 0 {
+		// The power a raise would hand out may still be drawn by the
+		// device that kept its old limit
+		return errors.Join(errs...)
+	}
+	for _, u := range raise {
+		if err := m.sendLimit(ctx, u); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", u.id, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// sendLimit sends one limit and records it if the device applied it.
+func (m *Manager) sendLimit(ctx context.Context, u limitUpdate) error {
+	ec := featureclient.NewEnergyControlClient(u.device.client, u.device.endpointID)
+	if u.device.useCurrentLimits {
+		// mW / V = mA, spread over the phases
+		current := u.limit / (u.device.voltage * u.device.phaseCount)
+		phases := make(map[string]any, u.device.phaseCount)
+		for p := range u.device.phaseCount {
+			phases[features.Phase(p).String()] = current
+		}
+		err := ec.SetCurrentLimits(ctx, features.SetCurrentLimitsRequest{
+			Phases:    phases,
+			Direction: features.DirectionConsumption,
+			Cause:     m.config.Cause,
+		})
+		if err != nil {
+			return err
+		}
+	} else {
+		limit := u.limit
+		resp, err := ec.SetLimit(ctx, features.SetLimitRequest{
+			ConsumptionLimit: &limit,
+			Cause:            m.config.Cause,
+		})
+		if err != nil {
+			return err
+		}
+		if !resp.Applied {
+			if resp.RejectReason != nil {
+				return fmt.Errorf("limit rejected: %s", *resp.RejectReason)
+			}
+			return errors.New("limit rejected")
+		}
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.devices[u.id] == u.device {
+		limit := u.limit
+		u.device.limit = &limit
+		u.device.updatedAt = m.clock.Now()
+	}
+	return nil
+}
@@ -0,0 +1,350 @@
+package ems
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mash-protocol/mash-go/pkg/clock"
+	"github.com/mash-protocol/mash-go/pkg/features"
+	"github.com/mash-protocol/mash-go/pkg/interaction"
+	"github.com/mash-protocol/mash-go/pkg/model"
+	"github.com/mash-protocol/mash-go/pkg/wire"
+)
+
+// sentLimit is a limit command received by a fakeDevice.
+type sentLimit struct {
+	device  string
+	command uint8
+	params  map[string]any
+}
+
+// fakeDevice serves one endpoint's EnergyControl, Electrical and
+// Measurement attributes and records the limits it receives in log.
+type fakeDevice struct {
+	id          string
+	energy      map[uint16]any
+	electrical  map[uint16]any
+	power       int64
+	unsubscribe []uint32
+	reject      bool // SetLimit is not applied
+
+	mu  *sync.Mutex
+	log *[]sentLimit
+}
+
+func (d *fakeDevice) Read(ctx context.Context, endpointID uint8, featureID uint8, attrIDs []uint16) (map[uint16]any, error) {
+	switch model.FeatureType(featureID) {
+	case model.FeatureEnergyControl:
+		return d.energy, nil
+	case model.FeatureElectrical:
+		return d.electrical, nil
+	}
+	return nil, errors.New("unsupported feature")
+}
+
+func (d *fakeDevice) Write(ctx context.Context, endpointID uint8, featureID uint8, attrs map[uint16]any) (map[uint16]any, error) {
+	return nil, errors.New("unsupported")
+}
+
+func (d *fakeDevice) Subscribe(ctx context.Context, endpointID uint8, featureID uint8, opts *interaction.SubscribeOptions) (uint32, map[uint16]any, error) {
+	if model.FeatureType(featureID) != model.FeatureMeasurement {
+		return 0, nil, errors.New("unsupported feature")
+	}
+	return 7, map[uint16]any{features.MeasurementAttrACActivePower: d.power}, nil
+}
+
+func (d *fakeDevice) Unsubscribe(ctx context.Context, subscriptionID uint32) error {
+	d.unsubscribe = append(d.unsubscribe, subscriptionID)
+	return nil
+}
+
+func (d *fakeDevice) Invoke(ctx context.Context, endpointID uint8, featureID uint8, commandID uint8, params map[string]any) (any, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	*d.log = append(*d.log, sentLimit{d.id, commandID, params})
+	if commandID == features.EnergyControlCmdSetCurrentLimits {
+		return map[string]any{"success": true}, nil
+	}
+	if d.reject {
+		return map[string]any{"applied": false, "controlState": uint8(features.ControlStateAutonomous)}, nil
+	}
+	return map[string]any{"applied": true, "controlState": uint8(features.ControlStateLimited)}, nil
+}
+
+// testSite holds fake devices sharing one command log.
+type testSite struct {
+	mu  sync.Mutex
+	log []sentLimit
+}
+
+func (s *testSite) device(id string, deviceType features.DeviceType, maxPower, minPower, power int64) *fakeDevice {
+	return &fakeDevice{
+		id: id,
+		energy: map[uint16]any{
+			features.EnergyControlAttrDeviceType:           uint8(deviceType),
+			features.EnergyControlAttrAcceptsLimits:        true,
+			features.EnergyControlAttrAcceptsCurrentLimits: false,
+		},
+		electrical: map[uint16]any{
+			features.ElectricalAttrPhaseCount:            uint8(3),
+			features.ElectricalAttrNominalVoltage:        uint16(230),
+			features.ElectricalAttrNominalMaxConsumption: maxPower,
+			features.ElectricalAttrNominalMinPower:       minPower,
+		},
+		power: power,
+		mu:    &s.mu,
+		log:   &s.log,
+	}
+}
+
+// take returns and clears the commands sent so far.
+func (s *testSite) take() []sentLimit {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	log := s.log
+	s.log = nil
+	return log
+}
+
+func TestManager_SitePower(t *testing.T) {
+	site := &testSite{}
+	m := NewManager(Config{SiteLimit: 30 * kW})
+	ctx := context.Background()
+
+	ev := site.device("ev", features.DeviceTypeEVSE, 11*kW, 4*kW, 7*kW)
+	if err := m.AddDevice(ctx, "ev", ev, 1, 0); err != nil {
+		t.Fatalf("AddDevice failed: %v", err)
+	}
+	if err := m.AddDevice(ctx, "ev", ev, 1, 0); !errors.Is(err, ErrDeviceExists) {
+		t.Errorf("second AddDevice error = %v, want ErrDeviceExists", err)
+	}
+	m.SetBaseLoad(2 * kW)
+
+	if got := m.SitePower(); got != 9*kW {
+		t.Errorf("SitePower = %d, want %d (priming report + base load)", got, 9*kW)
+	}
+
+	if !m.HandleNotification("ev", &wire.Notification{SubscriptionID: 7, Changes: map[uint16]any{features.MeasurementAttrACActivePower: uint64(3 * kW)}}) {
+		t.Error("Measurement notification not handled")
+	}
+	if m.HandleNotification("ev", &wire.Notification{SubscriptionID: 8}) {
+		t.Error("foreign notification handled")
+	}
+	if got := m.ManagedPower(); got != 3*kW {
+		t.Errorf("ManagedPower = %d, want %d", got, 3*kW)
+	}
+
+	if err := m.RemoveDevice(ctx, "ev"); err != nil {
+		t.Fatalf("RemoveDevice failed: %v", err)
+	}
+	if len(ev.unsubscribe) != 1 || ev.unsubscribe[0] != 7 {
+		t.Errorf("unsubscribed %v, want [7]", ev.unsubscribe)
+	}
+	if err := m.RemoveDevice(ctx, "ev"); !errors.Is(err, ErrDeviceNotFound) {
+		t.Errorf("second RemoveDevice error = %v, want ErrDeviceNotFound", err)
+	}
+}
+
+func TestManager_Rebalance(t *testing.T) {
+	site := &testSite{}
+	fake := clock.NewFake(time.Now())
+	m := NewManager(Config{
+		SiteLimit:         22 * kW,
+		Strategy:          PriorityFairShare{},
+		Hysteresis:        1 * kW,
+		MinUpdateInterval: time.Minute,
+		Clock:             fake,
+	})
+	ctx := context.Background()
+
+	for _, id := range []string{"ev1", "ev2"} {
+		if err := m.AddDevice(ctx, id, site.device(id, features.DeviceTypeEVSE, 11*kW, 4*kW, 0), 1, 0); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Initial limits for everyone
+	if err := m.Rebalance(ctx); err != nil {
+		t.Fatalf("Rebalance failed: %v", err)
+	}
+	if sent := site.take(); len(sent) != 2 || sent[0].params["consumptionLimit"] != int64(11*kW) {
+		t.Fatalf("sent %v, want two 11 kW limits", sent)
+	}
+
+	// A decrease below the hysteresis is still sent
+	m.SetBaseLoad(1 * kW / 2)
+	if err := m.Rebalance(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if sent := site.take(); len(sent) != 2 || sent[0].params["consumptionLimit"] != int64(10_750_000) {
+		t.Fatalf("sent %v, want two 10.75 kW limits", sent)
+	}
+
+	// An increase below the hysteresis is not
+	m.SetBaseLoad(0)
+	fake.Advance(time.Minute)
+	if err := m.Rebalance(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if sent := site.take(); len(sent) != 0 {
+		t.Errorf("sent %v for an increase below hysteresis", sent)
+	}
+
+	// Lowering is immediate
+	m.SetBaseLoad(6 * kW)
+	if err := m.Rebalance(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if sent := site.take(); len(sent) != 2 || sent[0].params["consumptionLimit"] != int64(8*kW) {
+		t.Fatalf("sent %v, want two 8 kW limits", sent)
+	}
+	if limits := m.Limits(); limits["ev1"] != 8*kW || limits["ev2"] != 8*kW {
+		t.Errorf("Limits = %v", limits)
+	}
+
+	// Raising waits for MinUpdateInterval
+	m.SetBaseLoad(0)
+	if err := m.Rebalance(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if sent := site.take(); len(sent) != 0 {
+		t.Errorf("sent %v before MinUpdateInterval", sent)
+	}
+	fake.Advance(time.Minute)
+	if err := m.Rebalance(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if sent := site.take(); len(sent) != 2 || sent[0].params["consumptionLimit"] != int64(11*kW) {
+		t.Fatalf("sent %v, want two 11 kW limits", sent)
+	}
+}
+
+func TestManager_LowersBeforeRaising(t *testing.T) {
+	site := &testSite{}
+	fake := clock.NewFake(time.Now())
+	m := NewManager(Config{SiteLimit: 15 * kW, Strategy: PriorityFairShare{}, Clock: fake})
+	ctx := context.Background()
+
+	if err := m.AddDevice(ctx, "a-ev", site.device("a-ev", features.DeviceTypeEVSE, 11*kW, 4*kW, 0), 1, 0); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.AddDevice(ctx, "b-hp", site.device("b-hp", features.DeviceTypeHeatPump, 6*kW, 1*kW, 0), 1, 5); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Rebalance(ctx); err != nil {
+		t.Fatal(err)
+	}
+	site.take()
+
+	// Moving the heat pump to the bottom shifts power to the wallbox; the
+	// heat pump must give it up first
+	m.mu.Lock()
+	m.devices["b-hp"].load.Priority = -1
+	m.mu.Unlock()
+	fake.Advance(time.Hour)
+	if err := m.Rebalance(ctx); err != nil {
+		t.Fatal(err)
+	}
+	sent := site.take()
+	if len(sent) != 2 || sent[0].device != "b-hp" || sent[1].device != "a-ev" {
+		t.Fatalf("sent %v, want b-hp lowered before a-ev raised", sent)
+	}
+	if limits := m.Limits(); limits["a-ev"] != 11*kW || limits["b-hp"] != 4*kW {
+		t.Errorf("Limits = %v", limits)
+	}
+}
+
+func TestManager_FailedLowerSkipsRaise(t *testing.T) {
+	site := &testSite{}
+	fake := clock.NewFake(time.Now())
+	m := NewManager(Config{SiteLimit: 15 * kW, Strategy: PriorityFairShare{}, Clock: fake})
+	ctx := context.Background()
+
+	heatPump := site.device("b-hp", features.DeviceTypeHeatPump, 6*kW, 1*kW, 0)
+	if err := m.AddDevice(ctx, "a-ev", site.device("a-ev", features.DeviceTypeEVSE, 11*kW, 4*kW, 0), 1, 0); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.AddDevice(ctx, "b-hp", heatPump, 1, 5); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Rebalance(ctx); err != nil {
+		t.Fatal(err)
+	}
+	site.take()
+
+	// The heat pump refuses to give up power, so the wallbox must not get it
+	heatPump.reject = true
+	m.mu.Lock()
+	m.devices["b-hp"].load.Priority = -1
+	m.mu.Unlock()
+	fake.Advance(time.Hour)
+	if err := m.Rebalance(ctx); err == nil {
+		t.Fatal("Rebalance succeeded although the lowered limit was rejected")
+	}
+	if sent := site.take(); len(sent) != 1 || sent[0].device != "b-hp" {
+		t.Fatalf("sent %v, want only the b-hp decrease", sent)
+	}
+	if limits := m.Limits(); limits["a-ev"] != 9*kW || limits["b-hp"] != 6*kW {
+		t.Errorf("Limits = %v, want the previous limits", limits)
+	}
+}
+
+func TestManager_NoSiteLimit(t *testing.T) {
+	site := &testSite{}
+	m := NewManager(DefaultConfig())
+	ctx := context.Background()
+
+	if err := m.AddDevice(ctx, "a-ev", site.device("a-ev", features.DeviceTypeEVSE, 11*kW, 4*kW, 0), 1, 0); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Rebalance(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if sent := site.take(); len(sent) != 0 {
+		t.Errorf("sent %v without a site limit, want nothing", sent)
+	}
+}
+
+func TestManager_CurrentLimits(t *testing.T) {
+	site := &testSite{}
+	m := NewManager(Config{SiteLimit: 6900 * 1000 * 3 / 2})
+	ctx := context.Background()
+
+	ev := site.device("ev", features.DeviceTypeEVSE, 0, 0, 0)
+	ev.energy[features.EnergyControlAttrAcceptsLimits] = false
+	ev.energy[features.EnergyControlAttrAcceptsCurrentLimits] = true
+	delete(ev.electrical, features.ElectricalAttrNominalMaxConsumption)
+	delete(ev.electrical, features.ElectricalAttrNominalMinPower)
+	ev.electrical[features.ElectricalAttrMaxCurrentPerPhase] = int64(16000)
+	ev.electrical[features.ElectricalAttrMinCurrentPerPhase] = int64(6000)
+	if err := m.AddDevice(ctx, "ev", ev, 1, 0); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := m.Rebalance(ctx); err != nil {
+		t.Fatal(err)
+	}
+	sent := site.take()
+	if len(sent) != 1 || sent[0].command != features.EnergyControlCmdSetCurrentLimits {
+		t.Fatalf("sent %v, want SetCurrentLimits", sent)
+	}
+	phases, _ := sent[0].params["phases"].(map[string]any)
+	// 10.35 kW over 3 x 230 V
+	if len(phases) != 3 || phases["A"] != int64(15000) || phases["C"] != int64(15000) {
+		t.Errorf("phases = %v, want 15 A on A, B and C", phases)
+	}
+}
+
+func TestManager_NotControllable(t *testing.T) {
+	site := &testSite{}
+	m := NewManager(DefaultConfig())
+
+	meter := site.device("meter", features.DeviceTypeOther, 0, 0, 0)
+	meter.energy[features.EnergyControlAttrAcceptsLimits] = false
+	if err := m.AddDevice(context.Background(), "meter", meter, 1, 0); !errors.Is(err, ErrNotControllable) {
+		t.Errorf("AddDevice error = %v, want ErrNotControllable", err)
+	}
+}
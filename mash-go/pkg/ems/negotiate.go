@@ -0,0 +1,127 @@
+package ems
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/mash-protocol/mash-go/pkg/featureclient"
+	"github.com/mash-protocol/mash-go/pkg/features"
+	"github.com/mash-protocol/mash-go/pkg/interaction"
+	"github.com/mash-protocol/mash-go/pkg/wire"
+)
+
+// Negotiation is the outcome of Negotiate.
+type Negotiation struct {
+	*Decision
+
+	// PlanID and PlanVersion identify the plan that was decided on.
+	PlanID      uint32
+	PlanVersion uint32
+
+	// Commitment is the plan's commitment after AcceptPlan (accepted
+	// plans only).
+	Commitment features.Commitment
+}
+
+// Negotiate asks the device on endpointID for a plan covering the price
+// slots and decides on it. An accepted plan is confirmed with AcceptPlan;
+// otherwise the optimal schedule is sent as a constraint signal. If the
+// endpoint has a ChargingSession, its evTargetEnergyRequest and
+// evDepartureTime set the energy and deadline; otherwise the plan's own
+// energy is rescheduled.
+func (o *Optimizer) Negotiate(ctx context.Context, client featureclient.DeviceClient, endpointID uint8, prices Prices) (*Negotiation, error) {
+	if len(prices.Slots) > 0 && prices.Start.IsZero() {
+		return nil, ErrNoPriceStart
+	}
+	plan := featureclient.NewPlanClient(client, endpointID)
+
+	req := features.RequestPlanRequest{}
+	if !prices.Start.IsZero() {
+		start := uint64(prices.Start.Unix())
+		var total uint32
+		for _, slot := range prices.Slots {
+			total += slot.Duration
+		}
+		req.StartTime, req.Duration = &start, &total
+	}
+	if _, err := plan.RequestPlan(ctx, req); err != nil {
+		return nil, fmt.Errorf("request plan: %w", err)
+	}
+	attrs, err := plan.Read(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("read plan: %w", err)
+	}
+
+	p := Problem{Prices: prices, Plan: attrs.Slots, PlanStart: prices.Start}
+	if attrs.StartTime != nil {
+		p.PlanStart = time.Unix(int64(*attrs.StartTime), 0)
+	}
+	switch {
+	case attrs.TotalEnergyPlanned != nil:
+		p.Energy = *attrs.TotalEnergyPlanned
+	default:
+		for _, slot := range attrs.Slots {
+			p.Energy += intervalEnergy(slot.PlannedPower, int64(slot.Duration))
+		}
+	}
+
+	session, err := featureclient.NewChargingSessionClient(client, endpointID).Read(ctx,
+		features.ChargingSessionAttrEVTargetEnergyRequest,
+		features.ChargingSessionAttrEVDepartureTime,
+	)
+	var statusErr *interaction.StatusError
+	switch {
+	case err == nil:
+		if session.EVTargetEnergyRequest != nil {
+			p.Energy = *session.EVTargetEnergyRequest
+		}
+		if session.EVDepartureTime != nil {
+			p.Departure = time.Unix(int64(*session.EVDepartureTime), 0)
+		}
+	case errors.As(err, &statusErr) && statusErr.Status == wire.StatusInvalidFeature:
+		// Not a charger
+	default:
+		return nil, fmt.Errorf("read charging session: %w", err)
+	}
+
+	decision, err := o.Decide(p)
+	if err != nil {
+		return nil, err
+	}
+	result := &Negotiation{Decision: decision}
+	if attrs.PlanID != nil {
+		result.PlanID = *attrs.PlanID
+	}
+	if attrs.PlanVersion != nil {
+		result.PlanVersion = *attrs.PlanVersion
+	}
+
+	if decision.Accept {
+		resp, err := plan.AcceptPlan(ctx, features.AcceptPlanRequest{PlanID: result.PlanID, PlanVersion: result.PlanVersion})
+		if err != nil {
+			return nil, fmt.Errorf("accept plan: %w", err)
+		}
+		result.Commitment = resp.NewCommitment
+		return result, nil
+	}
+
+	slots := decision.Optimal.ConstraintSlots()
+	var total uint32
+	for _, s := range slots {
+		total += s.Duration
+	}
+	start := uint64(p.PlanStart.Unix())
+	validUntil := start + uint64(total)
+	err = featureclient.NewSignalsClient(client, endpointID).SendConstraintSignal(ctx, features.SendConstraintSignalRequest{
+		Source:     o.config.Source,
+		StartTime:  start,
+		ValidUntil: &validUntil,
+		Slots:      features.EncodeConstraintSlots(slots),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("send constraint signal: %w", err)
+	}
+	return result, nil
+}
@@ -0,0 +1,170 @@
+package ems
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/mash-protocol/mash-go/pkg/features"
+	"github.com/mash-protocol/mash-go/pkg/interaction"
+	"github.com/mash-protocol/mash-go/pkg/model"
+	"github.com/mash-protocol/mash-go/pkg/wire"
+)
+
+// loopbackSender connects an interaction.Client to an interaction.Server
+// through the CBOR codec.
+type loopbackSender struct {
+	server *interaction.Server
+	client *interaction.Client
+}
+
+func (l *loopbackSender) Send(data []byte) error {
+	req, err := wire.DecodeRequest(data)
+	if err != nil {
+		return err
+	}
+	data, err = wire.EncodeResponse(l.server.HandleRequest(context.Background(), req))
+	if err != nil {
+		return err
+	}
+	resp, err := wire.DecodeResponse(data)
+	if err != nil {
+		return err
+	}
+	go func() { _ = l.client.HandleResponse(resp) }()
+	return nil
+}
+
+// planDevice is a device endpoint with Plan and Signals (and optionally
+// ChargingSession) that proposes a fixed plan.
+type planDevice struct {
+	client      *interaction.Client
+	accepted    []features.AcceptPlanRequest
+	constraints []features.SendConstraintSignalRequest
+}
+
+func newPlanDevice(t *testing.T, plan []features.PlanSlot, session *features.ChargingSession) *planDevice {
+	t.Helper()
+	d := &planDevice{}
+
+	device := model.NewDevice("test-device", 0x1234, 0x5678)
+	ep := model.NewEndpoint(1, model.EndpointEVCharger, "Charger")
+
+	p := features.NewPlan()
+	p.OnRequestPlan(func(ctx context.Context, req features.RequestPlanRequest) (features.RequestPlanResponse, error) {
+		_ = p.SetPlanID(7)
+		_ = p.SetPlanVersion(2)
+		_ = p.SetStartTime(*req.StartTime)
+		_ = p.SetSlots(plan)
+		return features.RequestPlanResponse{PlanID: 7}, nil
+	})
+	p.OnAcceptPlan(func(ctx context.Context, req features.AcceptPlanRequest) (features.AcceptPlanResponse, error) {
+		d.accepted = append(d.accepted, req)
+		return features.AcceptPlanResponse{NewCommitment: features.CommitmentCommitted}, nil
+	})
+	ep.AddFeature(p.Feature)
+
+	s := features.NewSignals()
+	s.OnSendConstraintSignal(func(ctx context.Context, req features.SendConstraintSignalRequest) error {
+		d.constraints = append(d.constraints, req)
+		return nil
+	})
+	ep.AddFeature(s.Feature)
+
+	if session != nil {
+		ep.AddFeature(session.Feature)
+	}
+	if err := device.AddEndpoint(ep); err != nil {
+		t.Fatal(err)
+	}
+
+	server := interaction.NewServer(device)
+	sender := &loopbackSender{server: server}
+	d.client = interaction.NewClient(sender)
+	sender.client = d.client
+	d.client.SetTimeout(5 * time.Second)
+	return d
+}
+
+func TestOptimizer_NegotiateConstrainsEV(t *testing.T) {
+	session := features.NewChargingSession()
+	_ = session.SetEVTargetEnergyRequest(22_000_000)
+	_ = session.SetEVDepartureTime(uint64(optimizerStart.Add(150 * time.Minute).Unix()))
+	d := newPlanDevice(t, chargeNow(4), session)
+
+	prices := hourlyPrices([]int{3000, 1000, 2000, 500}, []int{0, 0, 0, 0})
+	n, err := NewOptimizer(DefaultOptimizerConfig()).Negotiate(context.Background(), d.client, 1, prices)
+	if err != nil {
+		t.Fatalf("Negotiate failed: %v", err)
+	}
+	if n.Accept || len(d.accepted) != 0 {
+		t.Fatal("charge-now plan accepted")
+	}
+	if n.PlanID != 7 || n.PlanVersion != 2 {
+		t.Errorf("plan = %d/%d, want 7/2", n.PlanID, n.PlanVersion)
+	}
+
+	if len(d.constraints) != 1 {
+		t.Fatalf("%d constraint signals sent, want 1", len(d.constraints))
+	}
+	sent := d.constraints[0]
+	if sent.Source != features.SignalSourceLocalEms || sent.StartTime != uint64(optimizerStart.Unix()) {
+		t.Errorf("signal source %d, start %d", sent.Source, sent.StartTime)
+	}
+	if sent.ValidUntil == nil || *sent.ValidUntil != uint64(optimizerStart.Add(4*time.Hour).Unix()) {
+		t.Errorf("validUntil = %v, want end of plan", sent.ValidUntil)
+	}
+	var caps []int64
+	for _, raw := range sent.Slots {
+		m := wire.ToStringMap(raw)
+		for _, bound := range []string{"consumptionMin", "productionMax", "productionMin"} {
+			if _, ok := m[bound]; ok {
+				t.Errorf("slot sent with %s", bound)
+			}
+		}
+		v, _ := wire.ToInt64(m["consumptionMax"])
+		caps = append(caps, v)
+	}
+	// Departure after 2.5 h
+	if want := []int64{5_500_000, 11 * kW, 0}; !reflect.DeepEqual(caps, want) {
+		t.Errorf("consumption caps = %v, want %v", caps, want)
+	}
+}
+
+func TestOptimizer_NegotiateNoPriceStart(t *testing.T) {
+	d := newPlanDevice(t, chargeNow(4), nil)
+
+	prices := hourlyPrices([]int{3000, 1000, 2000, 500}, []int{0, 0, 0, 0})
+	prices.Start = time.Time{}
+	if _, err := NewOptimizer(DefaultOptimizerConfig()).Negotiate(context.Background(), d.client, 1, prices); !errors.Is(err, ErrNoPriceStart) {
+		t.Fatalf("Negotiate error = %v, want ErrNoPriceStart", err)
+	}
+	if len(d.constraints) != 0 || len(d.accepted) != 0 {
+		t.Error("device contacted for price slots without a start time")
+	}
+}
+
+func TestOptimizer_NegotiateAcceptsPlan(t *testing.T) {
+	// Already charging in the two cheapest hours; no ChargingSession, so
+	// the plan's own energy is kept
+	plan := chargeNow(4)
+	plan[0].PlannedPower, plan[1].PlannedPower, plan[3].PlannedPower = 0, 11*kW, 11*kW
+	d := newPlanDevice(t, plan, nil)
+
+	prices := hourlyPrices([]int{3000, 1000, 2000, 500}, []int{0, 0, 0, 0})
+	n, err := NewOptimizer(DefaultOptimizerConfig()).Negotiate(context.Background(), d.client, 1, prices)
+	if err != nil {
+		t.Fatalf("Negotiate failed: %v", err)
+	}
+	if !n.Accept || n.Commitment != features.CommitmentCommitted {
+		t.Errorf("accept %v, commitment %v; want accepted and COMMITTED", n.Accept, n.Commitment)
+	}
+	if len(d.accepted) != 1 || d.accepted[0].PlanID != 7 || d.accepted[0].PlanVersion != 2 {
+		t.Errorf("AcceptPlan calls = %+v", d.accepted)
+	}
+	if len(d.constraints) != 0 {
+		t.Errorf("constraint signal sent for an accepted plan")
+	}
+}
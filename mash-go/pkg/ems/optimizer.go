@@ -0,0 +1,356 @@
+package ems
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/mash-protocol/mash-go/pkg/features"
+)
+
+// Optimizer errors.
+var (
+	ErrNoPlan               = errors.New("device plan has no slots")
+	ErrUnsupportedPriceUnit = errors.New("price unit not supported")
+	ErrNoPriceStart         = errors.New("price slots have no start time")
+)
+
+// Prices is a price and CO2 signal: a tariff's unit and currency with the
+// Signals price slots that follow Start back to back.
+type Prices struct {
+	// Start is when the first slot begins.
+	Start time.Time
+
+	// Slots hold the price (0.0001 currency units per PriceUnit) and the
+	// CO2 intensity (g/kWh) of each slot.
+	Slots []features.PriceSlot
+
+	// Currency is the tariff currency (ISO 4217), for reporting only.
+	Currency string
+
+	// PriceUnit must be PriceUnitPerKwh.
+	PriceUnit features.PriceUnit
+}
+
+// Problem is what the Optimizer schedules: a device's flexibility as
+// reported in its Plan, the energy it needs and the prices.
+type Problem struct {
+	// Prices are the price and CO2 slots.
+	Prices Prices
+
+	// PlanStart is when the first plan slot begins.
+	PlanStart time.Time
+
+	// Plan gives the power range of each slot. A slot's range always
+	// includes its PlannedPower.
+	Plan []features.PlanSlot
+
+	// Energy is the energy to deliver within the plan, in mWh (for an EV,
+	// its evTargetEnergyRequest).
+	Energy int64
+
+	// Departure ends the schedule early (for an EV, its evDepartureTime).
+	// Zero means the end of the plan.
+	Departure time.Time
+}
+
+// ScheduleSlot is a slot of a Schedule.
+type ScheduleSlot struct {
+	// Duration is the slot length in seconds.
+	Duration uint32
+
+	// Power is the scheduled power in mW.
+	Power int64
+}
+
+// Schedule is a power schedule for a device, starting at the plan start.
+type Schedule struct {
+	Start time.Time
+	Slots []ScheduleSlot
+
+	// Energy is the scheduled energy in mWh.
+	Energy int64
+
+	// Shortfall is how much of Problem.Energy the device cannot get before
+	// departure, in mWh.
+	Shortfall int64
+
+	// Cost is the energy cost in 0.0001 currency units. Energy outside the
+	// price slots is not included.
+	Cost int64
+
+	// CO2 is the emitted CO2 in grams.
+	CO2 int64
+}
+
+// ConstraintSlots converts the schedule to Signals constraint slots that
+// cap the device's consumption at the scheduled power. Only Duration and
+// ConsumptionMax are set; like the other bounds, a zero cap is not sent.
+func (s *Schedule) ConstraintSlots() []features.ConstraintSlot {
+	result := make([]features.ConstraintSlot, 0, len(s.Slots))
+	for _, slot := range s.Slots {
+		result = append(result, features.ConstraintSlot{
+			Duration:       slot.Duration,
+			ConsumptionMax: max(slot.Power, 0),
+		})
+	}
+	return result
+}
+
+// Decision is the Optimizer's verdict on a device's plan.
+type Decision struct {
+	// Accept is true if the device's plan is within tolerance of the
+	// optimal schedule.
+	Accept bool
+
+	// Optimal is the cost-minimal schedule.
+	Optimal *Schedule
+
+	// Proposed is the device's plan evaluated against the same prices.
+	Proposed *Schedule
+}
+
+// OptimizerConfig configures an Optimizer.
+type OptimizerConfig struct {
+	// CO2Price is what one gram of CO2 is worth in 0.0001 currency units.
+	// It is added to the energy price when ranking slots. Default: 0.
+	CO2Price int64
+
+	// TolerancePercent is how much more a device's plan may cost than the
+	// optimal schedule and still be accepted. Default: 0.
+	TolerancePercent int64
+
+	// Source is sent with constraint signals. Default: SignalSourceLocalEms.
+	Source features.SignalSource
+}
+
+// DefaultOptimizerConfig returns an OptimizerConfig that ranks by price only.
+func DefaultOptimizerConfig() OptimizerConfig {
+	return OptimizerConfig{Source: features.SignalSourceLocalEms}
+}
+
+// Optimizer schedules flexible devices against price signals. Optimize and
+// Decide are deterministic and use no network; Negotiate drives a device's
+// Plan feature with them.
+type Optimizer struct {
+	config OptimizerConfig
+}
+
+// NewOptimizer creates an Optimizer.
+func NewOptimizer(config OptimizerConfig) *Optimizer {
+	return &Optimizer{config: config}
+}
+
+// interval is a stretch of the timeline with one plan slot and one price.
+type interval struct {
+	duration int64 // s
+	minPower int64 // mW
+	maxPower int64 // mW
+	planned  int64 // mW
+	priced   bool
+	price    int64 // 0.0001 currency units per kWh
+	co2      int64 // g/kWh
+}
+
+// Optimize returns the cost-minimal schedule for p. Every slot starts at
+// its minimum power; the remaining energy goes to the cheapest slots
+// first, earlier slots winning ties. Slots without a price are used last.
+func (o *Optimizer) Optimize(p Problem) (*Schedule, error) {
+	intervals, err := o.intervals(p)
+	if err != nil {
+		return nil, err
+	}
+
+	power := make([]int64, len(intervals))
+	var energy int64
+	for i, iv := range intervals {
+		power[i] = iv.minPower
+		energy += intervalEnergy(iv.minPower, iv.duration)
+	}
+
+	order := make([]int, len(intervals))
+	for i := range order {
+		order[i] = i
+	}
+	slices.SortStableFunc(order, func(a, b int) int {
+		ia, ib := intervals[a], intervals[b]
+		if ia.priced != ib.priced {
+			if ia.priced {
+				return -1
+			}
+			return 1
+		}
+		return cmp.Compare(o.rank(ia), o.rank(ib))
+	})
+
+	for _, i := range order {
+		need := p.Energy - energy
+		if need <= 0 {
+			break
+		}
+		iv := intervals[i]
+		room := intervalEnergy(iv.maxPower-iv.minPower, iv.duration)
+		if room <= need {
+			power[i] = iv.maxPower
+			energy += room
+			continue
+		}
+		// Round up so the target is met despite integer energy
+		extra := min((need*3600+iv.duration-1)/iv.duration, iv.maxPower-iv.minPower)
+		power[i] = iv.minPower + extra
+		energy += intervalEnergy(extra, iv.duration)
+	}
+
+	return evaluate(p, intervals, power), nil
+}
+
+// Decide evaluates the device's planned power against the optimal schedule.
+func (o *Optimizer) Decide(p Problem) (*Decision, error) {
+	optimal, err := o.Optimize(p)
+	if err != nil {
+		return nil, err
+	}
+	intervals, err := o.intervals(p)
+	if err != nil {
+		return nil, err
+	}
+	planned := make([]int64, len(intervals))
+	for i, iv := range intervals {
+		planned[i] = iv.planned
+	}
+	proposed := evaluate(p, intervals, planned)
+
+	// Integer energy may round down by up to 1 mWh per interval
+	enough := proposed.Energy+int64(len(intervals)) >= optimal.Energy
+	best := o.objective(optimal)
+	allowed := best + abs(best)*o.config.TolerancePercent/100
+	return &Decision{
+		Accept:   enough && o.objective(proposed) <= allowed,
+		Optimal:  optimal,
+		Proposed: proposed,
+	}, nil
+}
+
+// rank is an interval's cost per kWh including CO2.
+func (o *Optimizer) rank(iv interval) int64 {
+	return iv.price + o.config.CO2Price*iv.co2
+}
+
+// objective is a schedule's cost including CO2.
+func (o *Optimizer) objective(s *Schedule) int64 {
+	return s.Cost + o.config.CO2Price*s.CO2
+}
+
+// intervals splits the plan at every plan slot, price slot and departure
+// boundary.
+func (o *Optimizer) intervals(p Problem) ([]interval, error) {
+	if len(p.Plan) == 0 {
+		return nil, ErrNoPlan
+	}
+	if len(p.Prices.Slots) > 0 && p.Prices.PriceUnit != features.PriceUnitPerKwh {
+		return nil, fmt.Errorf("%w: %d", ErrUnsupportedPriceUnit, p.Prices.PriceUnit)
+	}
+	if len(p.Prices.Slots) > 0 && p.Prices.Start.IsZero() {
+		return nil, ErrNoPriceStart
+	}
+
+	// Boundaries in seconds from the plan start
+	var planEnd int64
+	bounds := []int64{0}
+	for _, slot := range p.Plan {
+		planEnd += int64(slot.Duration)
+		bounds = append(bounds, planEnd)
+	}
+	priceOffset := int64(p.Prices.Start.Sub(p.PlanStart) / time.Second)
+	priceStarts := make([]int64, len(p.Prices.Slots)+1)
+	priceStarts[0] = priceOffset
+	for i, slot := range p.Prices.Slots {
+		priceStarts[i+1] = priceStarts[i] + int64(slot.Duration)
+	}
+	bounds = append(bounds, priceStarts...)
+	departure := planEnd
+	if !p.Departure.IsZero() {
+		departure = min(max(int64(p.Departure.Sub(p.PlanStart)/time.Second), 0), planEnd)
+		bounds = append(bounds, departure)
+	}
+	slices.Sort(bounds)
+	bounds = slices.Compact(bounds)
+
+	var (
+		result    []interval
+		planSlot  int
+		planStart int64
+	)
+	for i := 0; i+1 < len(bounds); i++ {
+		from, to := bounds[i], bounds[i+1]
+		if from < 0 || to > planEnd {
+			continue
+		}
+		for planStart+int64(p.Plan[planSlot].Duration) <= from {
+			planStart += int64(p.Plan[planSlot].Duration)
+			planSlot++
+		}
+		slot := p.Plan[planSlot]
+		iv := interval{
+			duration: to - from,
+			minPower: min(slot.MinPower, slot.PlannedPower),
+			maxPower: max(slot.MaxPower, slot.PlannedPower),
+			planned:  slot.PlannedPower,
+		}
+		if from >= departure {
+			iv.minPower, iv.maxPower = 0, 0
+		}
+		if j, ok := priceSlotAt(priceStarts, from); ok {
+			iv.priced = true
+			iv.price = int64(p.Prices.Slots[j].Price)
+			iv.co2 = int64(p.Prices.Slots[j].Co2Intensity)
+		}
+		result = append(result, iv)
+	}
+	return result, nil
+}
+
+// priceSlotAt returns the price slot covering offset.
+func priceSlotAt(starts []int64, offset int64) (int, bool) {
+	for j := 0; j+1 < len(starts); j++ {
+		if offset >= starts[j] && offset < starts[j+1] {
+			return j, true
+		}
+	}
+	return 0, false
+}
+
+// evaluate builds the schedule for power over intervals, merging adjacent
+// slots with the same power.
+func evaluate(p Problem, intervals []interval, power []int64) *Schedule {
+	s := &Schedule{Start: p.PlanStart}
+	for i, iv := range intervals {
+		e := intervalEnergy(power[i], iv.duration)
+		s.Energy += e
+		if iv.priced {
+			s.Cost += iv.price * e / 1_000_000
+			s.CO2 += iv.co2 * e / 1_000_000
+		}
+		if n := len(s.Slots); n > 0 && s.Slots[n-1].Power == power[i] {
+			s.Slots[n-1].Duration += uint32(iv.duration)
+			continue
+		}
+		s.Slots = append(s.Slots, ScheduleSlot{Duration: uint32(iv.duration), Power: power[i]})
+	}
+	s.Shortfall = max(p.Energy-s.Energy, 0)
+	return s
+}
+
+// intervalEnergy returns the energy in mWh of power mW over seconds.
+func intervalEnergy(power, seconds int64) int64 {
+	return power * seconds / 3600
+}
+
+func abs(v int64) int64 {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
@@ -0,0 +1,185 @@
+package ems
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/mash-protocol/mash-go/pkg/features"
+)
+
+var optimizerStart = time.Date(2025, 1, 25, 18, 0, 0, 0, time.UTC)
+
+// hourlyPrices returns back-to-back one-hour price slots.
+func hourlyPrices(prices, co2 []int) Prices {
+	p := Prices{Start: optimizerStart, Currency: "EUR", PriceUnit: features.PriceUnitPerKwh}
+	for i := range prices {
+		p.Slots = append(p.Slots, features.PriceSlot{Duration: 3600, Price: int32(prices[i]), Co2Intensity: uint16(co2[i])})
+	}
+	return p
+}
+
+// chargeNow is an EV plan charging at 11 kW for the first two of n hours,
+// with 0-11 kW flexibility in every hour.
+func chargeNow(n int) []features.PlanSlot {
+	slots := make([]features.PlanSlot, n)
+	for i := range slots {
+		slots[i] = features.PlanSlot{Duration: 3600, MinPower: 0, MaxPower: 11 * kW}
+		if i < 2 {
+			slots[i].PlannedPower = 11 * kW
+		}
+	}
+	return slots
+}
+
+func TestOptimizer_Optimize(t *testing.T) {
+	prices := hourlyPrices([]int{3000, 1000, 2000, 500}, []int{100, 400, 200, 300})
+
+	tests := []struct {
+		name      string
+		config    OptimizerConfig
+		problem   Problem
+		want      []ScheduleSlot
+		cost      int64
+		shortfall int64
+	}{
+		{
+			name:    "cheapest hours",
+			problem: Problem{Prices: prices, PlanStart: optimizerStart, Plan: chargeNow(4), Energy: 22_000_000},
+			want:    []ScheduleSlot{{3600, 0}, {3600, 11 * kW}, {3600, 0}, {3600, 11 * kW}},
+			cost:    16500,
+		},
+		{
+			name:    "CO2 weighted",
+			config:  OptimizerConfig{CO2Price: 100},
+			problem: Problem{Prices: prices, PlanStart: optimizerStart, Plan: chargeNow(4), Energy: 22_000_000},
+			want:    []ScheduleSlot{{3600, 11 * kW}, {3600, 0}, {3600, 11 * kW}, {3600, 0}},
+			cost:    55000,
+		},
+		{
+			name: "departure",
+			problem: Problem{
+				Prices: prices, PlanStart: optimizerStart, Plan: chargeNow(4), Energy: 22_000_000,
+				Departure: optimizerStart.Add(150 * time.Minute),
+			},
+			want: []ScheduleSlot{{3600, 5_500_000}, {5400, 11 * kW}, {5400, 0}},
+			cost: 16500 + 11000 + 11000,
+		},
+		{
+			name: "shortfall",
+			problem: Problem{
+				Prices: prices, PlanStart: optimizerStart, Plan: chargeNow(4), Energy: 40_000_000,
+				Departure: optimizerStart.Add(150 * time.Minute),
+			},
+			want:      []ScheduleSlot{{9000, 11 * kW}, {5400, 0}},
+			cost:      33000 + 11000 + 11000,
+			shortfall: 12_500_000,
+		},
+		{
+			name:    "unpriced hours last",
+			problem: Problem{Prices: prices, PlanStart: optimizerStart.Add(2 * time.Hour), Plan: chargeNow(3), Energy: 22_000_000},
+			want:    []ScheduleSlot{{7200, 11 * kW}, {3600, 0}},
+			cost:    22000 + 5500,
+		},
+		{
+			name:    "minimum power is kept",
+			problem: Problem{Prices: prices, PlanStart: optimizerStart, Plan: []features.PlanSlot{{Duration: 7200, PlannedPower: 2 * kW, MinPower: 2 * kW, MaxPower: 3 * kW}}, Energy: 1},
+			want:    []ScheduleSlot{{7200, 2 * kW}},
+			cost:    6000 + 2000,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewOptimizer(tt.config).Optimize(tt.problem)
+			if err != nil {
+				t.Fatalf("Optimize failed: %v", err)
+			}
+			if !reflect.DeepEqual(got.Slots, tt.want) {
+				t.Errorf("slots = %v, want %v", got.Slots, tt.want)
+			}
+			if got.Cost != tt.cost {
+				t.Errorf("cost = %d, want %d", got.Cost, tt.cost)
+			}
+			if got.Shortfall != tt.shortfall {
+				t.Errorf("shortfall = %d, want %d", got.Shortfall, tt.shortfall)
+			}
+		})
+	}
+}
+
+func TestOptimizer_PartialSlot(t *testing.T) {
+	prices := hourlyPrices([]int{2000, 1000}, []int{0, 0})
+	plan := []features.PlanSlot{{Duration: 7200, MaxPower: 11 * kW}}
+
+	// 7 Wh more than a full cheap hour spills into the first hour, rounded up
+	got, err := NewOptimizer(DefaultOptimizerConfig()).Optimize(Problem{Prices: prices, PlanStart: optimizerStart, Plan: plan, Energy: 11_007_000})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Energy < 11_007_000 || got.Shortfall != 0 {
+		t.Errorf("energy = %d, shortfall %d; want at least the target", got.Energy, got.Shortfall)
+	}
+	if !reflect.DeepEqual(got.Slots, []ScheduleSlot{{3600, 7000}, {3600, 11 * kW}}) {
+		t.Errorf("slots = %v", got.Slots)
+	}
+}
+
+func TestOptimizer_Decide(t *testing.T) {
+	prices := hourlyPrices([]int{3000, 1000, 2000, 500}, []int{0, 0, 0, 0})
+
+	// Charging straight away costs 44000 against an optimum of 16500
+	p := Problem{Prices: prices, PlanStart: optimizerStart, Plan: chargeNow(4), Energy: 22_000_000}
+	d, err := NewOptimizer(DefaultOptimizerConfig()).Decide(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.Accept || d.Proposed.Cost != 44000 || d.Optimal.Cost != 16500 {
+		t.Errorf("decision = accept %v, proposed %d, optimal %d", d.Accept, d.Proposed.Cost, d.Optimal.Cost)
+	}
+
+	// Hours 1 and 2 cost 33000: within 100% but not 50%
+	p.Plan = chargeNow(4)
+	p.Plan[0].PlannedPower, p.Plan[2].PlannedPower = 0, 11*kW
+	for _, tc := range []struct {
+		tolerance int64
+		accept    bool
+	}{{50, false}, {100, true}} {
+		d, err := NewOptimizer(OptimizerConfig{TolerancePercent: tc.tolerance}).Decide(p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if d.Accept != tc.accept {
+			t.Errorf("tolerance %d%%: accept = %v, want %v", tc.tolerance, d.Accept, tc.accept)
+		}
+	}
+
+	// A cheap plan that does not deliver the energy is rejected
+	p.Plan = chargeNow(4)
+	p.Plan[0].PlannedPower, p.Plan[1].PlannedPower = 0, 0
+	p.Plan[3].PlannedPower = 11 * kW
+	d, err = NewOptimizer(OptimizerConfig{TolerancePercent: 100}).Decide(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.Accept {
+		t.Error("plan short of the energy target accepted")
+	}
+}
+
+func TestOptimizer_Errors(t *testing.T) {
+	o := NewOptimizer(DefaultOptimizerConfig())
+	if _, err := o.Optimize(Problem{Prices: hourlyPrices([]int{1}, []int{1})}); !errors.Is(err, ErrNoPlan) {
+		t.Errorf("empty plan error = %v, want ErrNoPlan", err)
+	}
+	prices := hourlyPrices([]int{1}, []int{1})
+	prices.PriceUnit = features.PriceUnitPerKvah
+	if _, err := o.Optimize(Problem{Prices: prices, Plan: chargeNow(1)}); !errors.Is(err, ErrUnsupportedPriceUnit) {
+		t.Errorf("kVAh error = %v, want ErrUnsupportedPriceUnit", err)
+	}
+	prices = hourlyPrices([]int{1}, []int{1})
+	prices.Start = time.Time{}
+	if _, err := o.Optimize(Problem{Prices: prices, Plan: chargeNow(1), PlanStart: optimizerStart}); !errors.Is(err, ErrNoPriceStart) {
+		t.Errorf("zero price start error = %v, want ErrNoPriceStart", err)
+	}
+}
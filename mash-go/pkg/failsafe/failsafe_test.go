@@ -4,6 +4,8 @@ import (
 	"sync"
 	"testing"
 	"time"
+
+	"github.com/mash-protocol/mash-go/pkg/clock"
 )
 
 func TestTimerInitialState(t *testing.T) {
@@ -532,3 +534,59 @@ func TestTimerRestore(t *testing.T) {
 		timer.Stop()
 	})
 }
+
+func TestTimerWithFakeClock(t *testing.T) {
+	fc := clock.NewFake(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+	timer, err := NewTimerWithConfig(Config{
+		Duration:    4 * time.Hour,
+		GracePeriod: DefaultGracePeriod,
+		Clock:       fc,
+	})
+	if err != nil {
+		t.Fatalf("NewTimerWithConfig() error = %v", err)
+	}
+
+	var entered, exited int
+	timer.OnFailsafeEnter(func(Limits) { entered++ })
+	timer.OnFailsafeExit(func() { exited++ })
+
+	timer.Start()
+
+	fc.Advance(3 * time.Hour)
+	if timer.State() != StateTimerRunning {
+		t.Fatalf("State() = %v, want StateTimerRunning", timer.State())
+	}
+	if got := timer.RemainingTime(); got != time.Hour {
+		t.Errorf("RemainingTime() = %v, want 1h", got)
+	}
+
+	fc.Advance(time.Hour)
+	if timer.State() != StateFailsafe {
+		t.Fatalf("State() = %v, want StateFailsafe", timer.State())
+	}
+	if entered != 1 {
+		t.Errorf("OnFailsafeEnter called %d times, want 1", entered)
+	}
+
+	// Reconnect: grace period, then back to normal.
+	timer.Stop()
+	if timer.State() != StateGracePeriod {
+		t.Fatalf("State() = %v, want StateGracePeriod", timer.State())
+	}
+
+	fc.Advance(DefaultGracePeriod - time.Second)
+	if timer.State() != StateGracePeriod {
+		t.Fatalf("State() = %v, want StateGracePeriod before it elapses", timer.State())
+	}
+
+	fc.Advance(time.Second)
+	if timer.State() != StateNormal {
+		t.Errorf("State() = %v, want StateNormal after grace period", timer.State())
+	}
+	if exited != 1 {
+		t.Errorf("OnFailsafeExit called %d times, want 1", exited)
+	}
+	if fc.PendingTimers() != 0 {
+		t.Errorf("PendingTimers() = %d, want 0", fc.PendingTimers())
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"errors"
 	"sync"
 	"time"
+
+	"github.com/mash-protocol/mash-go/pkg/clock"
 )
 
 // Failsafe timer constants.
@@ -100,9 +102,10 @@ type Timer struct {
 	// Failsafe limits to apply
 	limits Limits
 
-	// Timer instances
-	failsafeTimer   *time.Timer
-	gracePeriodTimer *time.Timer
+	// Time source and timer instances
+	clock            clock.Clock
+	failsafeTimer    clock.Timer
+	gracePeriodTimer clock.Timer
 
 	// When the timer was started
 	startedAt time.Time
@@ -118,10 +121,17 @@ type Timer struct {
 
 // NewTimer creates a new failsafe timer with default settings.
 func NewTimer() *Timer {
+	return NewTimerWithClock(clock.Real())
+}
+
+// NewTimerWithClock creates a failsafe timer with default settings that
+// reads time from c instead of the real clock.
+func NewTimerWithClock(c clock.Clock) *Timer {
 	return &Timer{
 		state:       StateNormal,
 		duration:    DefaultDuration,
 		gracePeriod: DefaultGracePeriod,
+		clock:       clock.OrReal(c),
 	}
 }
 
@@ -131,6 +141,10 @@ type Config struct {
 	GracePeriod      time.Duration
 	Limits           Limits
 	PersistEnabled   bool
+
+	// Clock is the time source for the failsafe and grace period timers.
+	// Nil uses the real clock.
+	Clock clock.Clock
 }
 
 // NewTimerWithConfig creates a failsafe timer with custom configuration.
@@ -145,6 +159,7 @@ func NewTimerWithConfig(cfg Config) (*Timer, error) {
 		gracePeriod:    cfg.GracePeriod,
 		limits:         cfg.Limits,
 		persistEnabled: cfg.PersistEnabled,
+		clock:          clock.OrReal(cfg.Clock),
 	}
 
 	if t.duration == 0 {
@@ -159,7 +174,8 @@ func NewTimerWithConfig(cfg Config) (*Timer, error) {
 
 // NewTestTimer creates a failsafe timer for testing purposes.
 // This bypasses duration validation and allows any duration.
-// Should only be used in tests.
+// Should only be used in tests; prefer NewTimerWithConfig with a
+// clock.Fake to exercise real durations without waiting.
 func NewTestTimer(duration, gracePeriod time.Duration, limits Limits) *Timer {
 	if duration == 0 {
 		duration = 100 * time.Millisecond // Fast default for tests
@@ -169,9 +185,16 @@ func NewTestTimer(duration, gracePeriod time.Duration, limits Limits) *Timer {
 		duration:    duration,
 		gracePeriod: gracePeriod,
 		limits:      limits,
+		clock:       clock.Real(),
 	}
 }
 
+// timeSource returns the timer's clock, defaulting to the real clock for
+// zero-value timers.
+func (t *Timer) timeSource() clock.Clock {
+	return clock.OrReal(t.clock)
+}
+
 // State returns the current failsafe state.
 func (t *Timer) State() State {
 	t.mu.RLock()
@@ -238,10 +261,10 @@ func (t *Timer) Start() {
 
 	oldState := t.state
 	t.state = StateTimerRunning
-	t.startedAt = time.Now()
+	t.startedAt = t.timeSource().Now()
 
 	// Start the timer
-	t.failsafeTimer = time.AfterFunc(t.duration, func() {
+	t.failsafeTimer = t.timeSource().AfterFunc(t.duration, func() {
 		t.enterFailsafe()
 	})
 
@@ -272,7 +295,7 @@ func (t *Timer) Stop() {
 	// If we were in failsafe, enter grace period (if configured)
 	if wasFailsafe && t.gracePeriod > 0 {
 		t.state = StateGracePeriod
-		t.gracePeriodTimer = time.AfterFunc(t.gracePeriod, func() {
+		t.gracePeriodTimer = t.timeSource().AfterFunc(t.gracePeriod, func() {
 			t.exitGracePeriod()
 		})
 	} else {
@@ -327,7 +350,7 @@ func (t *Timer) RemainingTime() time.Duration {
 		return 0
 	}
 
-	elapsed := time.Since(t.startedAt)
+	elapsed := t.timeSource().Since(t.startedAt)
 	remaining := t.duration - elapsed
 	if remaining < 0 {
 		return 0
@@ -446,7 +469,7 @@ func (t *Timer) Snapshot() *TimerSnapshot {
 
 	if t.state == StateTimerRunning {
 		snap.StartedAt = t.startedAt
-		elapsed := time.Since(t.startedAt)
+		elapsed := t.timeSource().Since(t.startedAt)
 		remaining := t.duration - elapsed
 		if remaining < 0 {
 			remaining = 0
@@ -497,7 +520,7 @@ func (t *Timer) Restore(snap *TimerSnapshot) error {
 			remaining = snap.Remaining
 		} else if !snap.StartedAt.IsZero() {
 			// Calculate from start time
-			elapsed := time.Since(snap.StartedAt)
+			elapsed := t.timeSource().Since(snap.StartedAt)
 			remaining = snap.Duration - elapsed
 		}
 
@@ -516,9 +539,9 @@ func (t *Timer) Restore(snap *TimerSnapshot) error {
 		} else {
 			// Restart timer with remaining time
 			t.state = StateTimerRunning
-			t.startedAt = time.Now()
+			t.startedAt = t.timeSource().Now()
 			t.duration = remaining // Adjust duration to remaining time
-			t.failsafeTimer = time.AfterFunc(remaining, func() {
+			t.failsafeTimer = t.timeSource().AfterFunc(remaining, func() {
 				t.enterFailsafe()
 			})
 		}
@@ -0,0 +1,265 @@
+// Package featureclient provides typed controller-side proxies for remote
+// features. For each feature mash-featgen generates a <Name>Client with
+// Read<Attr>/Write<Attr> methods, one method per command taking and
+// returning the Request/Response structs of pkg/features, and a
+// <Name>Attributes set that decodes Read results, subscription priming
+// reports and notifications:
+//
+//	ec := featureclient.NewEnergyControlClient(session, 1)
+//	resp, err := ec.SetLimit(ctx, features.SetLimitRequest{ConsumptionLimit: &limit, Cause: cause})
+//
+// The clients are built on DeviceClient and decode CBOR values leniently
+// (any integer that fits the target type). This package is separate from
+// pkg/features because pkg/interaction's tests import pkg/features.
+package featureclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/mash-protocol/mash-go/pkg/interaction"
+	"github.com/mash-protocol/mash-go/pkg/wire"
+)
+
+// Feature client errors.
+var (
+	// ErrAttributeMissing is returned when a device's response does not
+	// contain a requested attribute.
+	ErrAttributeMissing = errors.New("attribute missing from response")
+
+	// ErrUnexpectedValue is returned when a value sent by a device cannot be
+	// decoded into the attribute's or field's Go type.
+	ErrUnexpectedValue = errors.New("unexpected value type")
+
+	// ErrCommandFailed is returned when a device reports that a command
+	// without a typed response did not succeed.
+	ErrCommandFailed = errors.New("command reported failure")
+)
+
+// DeviceClient is the connection to a remote device that the feature
+// clients (EnergyControlClient, MeasurementClient, ...) are built on. Both
+// interaction.Client and service.DeviceSession implement it.
+type DeviceClient interface {
+	Read(ctx context.Context, endpointID uint8, featureID uint8, attrIDs []uint16) (map[uint16]any, error)
+	Write(ctx context.Context, endpointID uint8, featureID uint8, attrs map[uint16]any) (map[uint16]any, error)
+	Subscribe(ctx context.Context, endpointID uint8, featureID uint8, opts *interaction.SubscribeOptions) (uint32, map[uint16]any, error)
+	Unsubscribe(ctx context.Context, subscriptionID uint32) error
+	Invoke(ctx context.Context, endpointID uint8, featureID uint8, commandID uint8, params map[string]any) (any, error)
+}
+
+// Verify interaction.Client implements DeviceClient.
+var _ DeviceClient = (*interaction.Client)(nil)
+
+// readAttribute reads a single attribute from a remote feature.
+func readAttribute(ctx context.Context, client DeviceClient, endpointID, featureID uint8, attrID uint16) (any, error) {
+	values, err := client.Read(ctx, endpointID, featureID, []uint16{attrID})
+	if err != nil {
+		return nil, err
+	}
+	raw, ok := values[attrID]
+	if !ok {
+		return nil, fmt.Errorf("%w: %d", ErrAttributeMissing, attrID)
+	}
+	return raw, nil
+}
+
+// invokeCommand invokes a command and normalizes its response to a map.
+func invokeCommand(ctx context.Context, client DeviceClient, endpointID, featureID, commandID uint8, params map[string]any) (map[string]any, error) {
+	raw, err := client.Invoke(ctx, endpointID, featureID, commandID, params)
+	if err != nil {
+		return nil, err
+	}
+	if raw == nil {
+		return map[string]any{}, nil
+	}
+	m := wire.ToStringMap(raw)
+	if m == nil {
+		return nil, fmt.Errorf("%w: command response is %T", ErrUnexpectedValue, raw)
+	}
+	return m, nil
+}
+
+// checkSuccess interprets the response of a command without typed response
+// fields.
+func checkSuccess(resp map[string]any) error {
+	if success, ok := resp["success"].(bool); ok && !success {
+		return ErrCommandFailed
+	}
+	return nil
+}
+
+// decodeValue decodes a non-nullable value.
+func decodeValue[T any](raw any, decode func(any) (T, bool)) (T, error) {
+	v, ok := decode(raw)
+	if !ok {
+		var zero T
+		return zero, fmt.Errorf("%w: %T", ErrUnexpectedValue, raw)
+	}
+	return v, nil
+}
+
+// decodeNullable decodes a nullable value; null decodes to nil.
+func decodeNullable[T any](raw any, decode func(any) (T, bool)) (*T, error) {
+	if raw == nil {
+		return nil, nil
+	}
+	v, err := decodeValue(raw, decode)
+	if err != nil {
+		return nil, err
+	}
+	return &v, nil
+}
+
+// decodeCollection decodes a map or slice value; null decodes to nil.
+func decodeCollection[T any](raw any, decode func(any) (T, bool)) (T, error) {
+	if raw == nil {
+		var zero T
+		return zero, nil
+	}
+	return decodeValue(raw, decode)
+}
+
+// decodePtr decodes raw into a new value, or returns nil if raw is null or
+// cannot be decoded.
+func decodePtr[T any](raw any, decode func(any) (T, bool)) *T {
+	v, ok := decode(raw)
+	if !ok {
+		return nil
+	}
+	return &v
+}
+
+// decodeUint decodes any integer that fits into T. CBOR decoding into any
+// yields uint64 or int64; in-process clients may pass the device's own
+// typed values.
+func decodeUint[T ~uint8 | ~uint16 | ~uint32 | ~uint64](raw any) (T, bool) {
+	var n uint64
+	rv := reflect.ValueOf(raw)
+	switch rv.Kind() {
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n = rv.Uint()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if rv.Int() < 0 {
+			return 0, false
+		}
+		n = uint64(rv.Int())
+	default:
+		return 0, false
+	}
+	if uint64(T(n)) != n {
+		return 0, false
+	}
+	return T(n), true
+}
+
+// decodeInt decodes any integer that fits into T.
+func decodeInt[T ~int8 | ~int16 | ~int32 | ~int64](raw any) (T, bool) {
+	var n int64
+	rv := reflect.ValueOf(raw)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n = rv.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if rv.Uint() > 1<<63-1 {
+			return 0, false
+		}
+		n = int64(rv.Uint())
+	default:
+		return 0, false
+	}
+	if int64(T(n)) != n {
+		return 0, false
+	}
+	return T(n), true
+}
+
+// decodeFloat decodes any number into T.
+func decodeFloat[T ~float32 | ~float64](raw any) (T, bool) {
+	rv := reflect.ValueOf(raw)
+	switch rv.Kind() {
+	case reflect.Float32, reflect.Float64:
+		return T(rv.Float()), true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return T(rv.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return T(rv.Uint()), true
+	default:
+		return 0, false
+	}
+}
+
+// decodeAs decodes values that CBOR maps to a single Go type (bool, string,
+// []byte).
+func decodeAs[T any](raw any) (T, bool) {
+	v, ok := raw.(T)
+	return v, ok
+}
+
+// decodeStringMap decodes a map with string keys, as used by untyped map
+// command fields.
+func decodeStringMap(raw any) (map[string]any, bool) {
+	m := wire.ToStringMap(raw)
+	return m, m != nil
+}
+
+// mapDecoder returns a decoder for maps whose keys and values are decoded
+// with key and value. Entries that fail to decode make the whole map fail.
+func mapDecoder[K comparable, V any](key func(any) (K, bool), value func(any) (V, bool)) func(any) (map[K]V, bool) {
+	return func(raw any) (map[K]V, bool) {
+		rv := reflect.ValueOf(raw)
+		if rv.Kind() != reflect.Map {
+			return nil, false
+		}
+		result := make(map[K]V, rv.Len())
+		iter := rv.MapRange()
+		for iter.Next() {
+			k, ok := key(iter.Key().Interface())
+			if !ok {
+				return nil, false
+			}
+			v, ok := value(iter.Value().Interface())
+			if !ok {
+				return nil, false
+			}
+			result[k] = v
+		}
+		return result, true
+	}
+}
+
+// sliceDecoder returns a decoder for arrays whose items are decoded with
+// item.
+func sliceDecoder[T any](item func(any) (T, bool)) func(any) ([]T, bool) {
+	return func(raw any) ([]T, bool) {
+		rv := reflect.ValueOf(raw)
+		if rv.Kind() != reflect.Slice {
+			return nil, false
+		}
+		result := make([]T, 0, rv.Len())
+		for i := 0; i < rv.Len(); i++ {
+			v, ok := item(rv.Index(i).Interface())
+			if !ok {
+				return nil, false
+			}
+			result = append(result, v)
+		}
+		return result, true
+	}
+}
+
+// decodeField decodes a field of a command response or object array item
+// into dst. Missing and null fields leave dst unchanged.
+func decodeField[T any](m map[string]any, key string, decode func(any) (T, bool), dst *T) bool {
+	raw, ok := m[key]
+	if !ok || raw == nil {
+		return true
+	}
+	v, ok := decode(raw)
+	if !ok {
+		return false
+	}
+	*dst = v
+	return true
+}
@@ -0,0 +1,273 @@
+package featureclient
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/mash-protocol/mash-go/pkg/features"
+	"github.com/mash-protocol/mash-go/pkg/interaction"
+	"github.com/mash-protocol/mash-go/pkg/model"
+	"github.com/mash-protocol/mash-go/pkg/wire"
+)
+
+// loopbackSender connects an interaction.Client to an interaction.Server
+// through the CBOR codec, so values reach the client as they would over a
+// real connection.
+type loopbackSender struct {
+	server *interaction.Server
+	client *interaction.Client
+}
+
+func (l *loopbackSender) Send(data []byte) error {
+	req, err := wire.DecodeRequest(data)
+	if err != nil {
+		return err
+	}
+	data, err = wire.EncodeResponse(l.server.HandleRequest(context.Background(), req))
+	if err != nil {
+		return err
+	}
+	resp, err := wire.DecodeResponse(data)
+	if err != nil {
+		return err
+	}
+	go func() { _ = l.client.HandleResponse(resp) }()
+	return nil
+}
+
+// newClientTestDevice returns a client for an EVSE endpoint served through
+// a loopback connection, along with the endpoint's EnergyControl and
+// ChargingSession features.
+func newClientTestDevice(t *testing.T) (*interaction.Client, *features.EnergyControl, *features.ChargingSession) {
+	t.Helper()
+
+	device := model.NewDevice("test-device", 0x1234, 0x5678)
+	evse := model.NewEndpoint(1, model.EndpointEVCharger, "Charger")
+
+	ec := features.NewEnergyControl()
+	_ = ec.SetDeviceType(features.DeviceTypeEVSE)
+	_ = ec.SetControlState(features.ControlStateControlled)
+	_ = ec.SetAcceptsLimits(true)
+	_ = ec.SetEffectiveConsumptionLimit(11000000)
+	_ = ec.SetEffectiveCurrentLimitsConsumption(map[features.Phase]int64{features.PhaseA: 16000, features.PhaseB: 16000, features.PhaseC: 10000})
+	evse.AddFeature(ec.Feature)
+
+	cs := features.NewChargingSession()
+	_ = cs.SetEVIdentifications([]features.EVIdentification{{Type: features.EVIDTypeMACEUI48, Value: "00:11:22:33:44:55"}})
+	_ = cs.SetSupportedChargingModes([]features.ChargingMode{features.ChargingModeOff, features.ChargingModePVSurplusOnly})
+	evse.AddFeature(cs.Feature)
+
+	if err := device.AddEndpoint(evse); err != nil {
+		t.Fatalf("AddEndpoint() error = %v", err)
+	}
+
+	server := interaction.NewServer(device)
+	sender := &loopbackSender{server: server}
+	client := interaction.NewClient(sender)
+	sender.client = client
+	client.SetTimeout(5 * time.Second)
+
+	server.SetNotificationHandler(func(notif *wire.Notification) {
+		data, err := wire.EncodeNotification(notif)
+		if err != nil {
+			t.Errorf("EncodeNotification() error = %v", err)
+			return
+		}
+		decoded, err := wire.DecodeNotification(data)
+		if err != nil {
+			t.Errorf("DecodeNotification() error = %v", err)
+			return
+		}
+		client.HandleNotification(decoded)
+	})
+
+	return client, ec, cs
+}
+
+func TestFeatureClient_ReadAttributes(t *testing.T) {
+	client, _, _ := newClientTestDevice(t)
+	ctx := context.Background()
+	ec := NewEnergyControlClient(client, 1)
+
+	state, err := ec.ReadControlState(ctx)
+	if err != nil || state != features.ControlStateControlled {
+		t.Errorf("ReadControlState() = %v, %v; want CONTROLLED", state, err)
+	}
+
+	limit, err := ec.ReadEffectiveConsumptionLimit(ctx)
+	if err != nil || limit == nil || *limit != 11000000 {
+		t.Errorf("ReadEffectiveConsumptionLimit() = %v, %v; want 11000000", limit, err)
+	}
+
+	// Unset nullable attributes decode to nil.
+	limit, err = ec.ReadEffectiveProductionLimit(ctx)
+	if err != nil || limit != nil {
+		t.Errorf("ReadEffectiveProductionLimit() = %v, %v; want nil", limit, err)
+	}
+
+	perPhase, err := ec.ReadEffectiveCurrentLimitsConsumption(ctx)
+	if err != nil || perPhase[features.PhaseA] != 16000 || perPhase[features.PhaseC] != 10000 {
+		t.Errorf("ReadEffectiveCurrentLimitsConsumption() = %v, %v", perPhase, err)
+	}
+
+	attrs, err := ec.Read(ctx, features.EnergyControlAttrDeviceType, features.EnergyControlAttrAcceptsLimits)
+	if err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	if attrs.DeviceType == nil || *attrs.DeviceType != features.DeviceTypeEVSE {
+		t.Errorf("Read().DeviceType = %v, want EVSE", attrs.DeviceType)
+	}
+	if attrs.AcceptsLimits == nil || !*attrs.AcceptsLimits {
+		t.Errorf("Read().AcceptsLimits = %v, want true", attrs.AcceptsLimits)
+	}
+	if attrs.ControlState != nil {
+		t.Errorf("Read().ControlState = %v, want nil (not requested)", *attrs.ControlState)
+	}
+
+	cs := NewChargingSessionClient(client, 1)
+	ids, err := cs.ReadEVIdentifications(ctx)
+	if err != nil || len(ids) != 1 || ids[0].Type != features.EVIDTypeMACEUI48 || ids[0].Value != "00:11:22:33:44:55" {
+		t.Errorf("ReadEVIdentifications() = %v, %v", ids, err)
+	}
+	modes, err := cs.ReadSupportedChargingModes(ctx)
+	if err != nil || len(modes) != 2 || modes[1] != features.ChargingModePVSurplusOnly {
+		t.Errorf("ReadSupportedChargingModes() = %v, %v", modes, err)
+	}
+}
+
+func TestFeatureClient_Write(t *testing.T) {
+	client, _, _ := newClientTestDevice(t)
+	ctx := context.Background()
+	ec := NewEnergyControlClient(client, 1)
+
+	if err := ec.WriteOptOutState(ctx, features.OptOutStateLocal); err != nil {
+		t.Fatalf("WriteOptOutState() error = %v", err)
+	}
+	if got, err := ec.ReadOptOutState(ctx); err != nil || got != features.OptOutStateLocal {
+		t.Errorf("ReadOptOutState() = %v, %v; want LOCAL", got, err)
+	}
+
+	limit := int64(5000000)
+	if err := ec.WriteMyConsumptionLimit(ctx, &limit); err != nil {
+		t.Fatalf("WriteMyConsumptionLimit() error = %v", err)
+	}
+	if got, err := ec.ReadMyConsumptionLimit(ctx); err != nil || got == nil || *got != limit {
+		t.Errorf("ReadMyConsumptionLimit() = %v, %v; want %d", got, err, limit)
+	}
+	if err := ec.WriteMyConsumptionLimit(ctx, nil); err != nil {
+		t.Fatalf("WriteMyConsumptionLimit(nil) error = %v", err)
+	}
+	if got, err := ec.ReadMyConsumptionLimit(ctx); err != nil || got != nil {
+		t.Errorf("ReadMyConsumptionLimit() = %v, %v; want nil", got, err)
+	}
+}
+
+func TestFeatureClient_Invoke(t *testing.T) {
+	client, server, _ := newClientTestDevice(t)
+	ctx := context.Background()
+	ec := NewEnergyControlClient(client, 1)
+
+	var got features.SetLimitRequest
+	server.OnSetLimit(func(ctx context.Context, req features.SetLimitRequest) (features.SetLimitResponse, error) {
+		got = req
+		applied := *req.ConsumptionLimit
+		return features.SetLimitResponse{
+			Applied:                   true,
+			ControlState:              features.ControlStateLimited,
+			EffectiveConsumptionLimit: &applied,
+		}, nil
+	})
+
+	limit := int64(6000000)
+	resp, err := ec.SetLimit(ctx, features.SetLimitRequest{ConsumptionLimit: &limit, Cause: features.LimitCauseGridEmergency})
+	if err != nil {
+		t.Fatalf("SetLimit() error = %v", err)
+	}
+	if got.ConsumptionLimit == nil || *got.ConsumptionLimit != limit || got.Cause != features.LimitCauseGridEmergency || got.ProductionLimit != nil {
+		t.Errorf("handler received %+v", got)
+	}
+	if !resp.Applied || resp.ControlState != features.ControlStateLimited {
+		t.Errorf("SetLimit() = %+v, want applied/LIMITED", resp)
+	}
+	if resp.EffectiveConsumptionLimit == nil || *resp.EffectiveConsumptionLimit != limit {
+		t.Errorf("SetLimit().EffectiveConsumptionLimit = %v, want %d", resp.EffectiveConsumptionLimit, limit)
+	}
+	if resp.RejectReason != nil {
+		t.Errorf("SetLimit().RejectReason = %v, want nil", *resp.RejectReason)
+	}
+
+	// Handler errors surface as status errors.
+	server.OnSetLimit(func(ctx context.Context, req features.SetLimitRequest) (features.SetLimitResponse, error) {
+		return features.SetLimitResponse{}, errors.New("refused")
+	})
+	if _, err := ec.SetLimit(ctx, features.SetLimitRequest{ConsumptionLimit: &limit, Cause: features.LimitCauseGridEmergency}); err == nil {
+		t.Error("SetLimit() with failing handler: expected error")
+	}
+}
+
+func TestFeatureClient_Subscribe(t *testing.T) {
+	client, _, _ := newClientTestDevice(t)
+	ctx := context.Background()
+	ec := NewEnergyControlClient(client, 1)
+
+	notified := make(chan *wire.Notification, 1)
+	client.SetNotificationHandler(func(notif *wire.Notification) {
+		notified <- notif
+	})
+
+	subID, attrs, err := ec.Subscribe(ctx, &interaction.SubscribeOptions{
+		AttributeIDs: []uint16{features.EnergyControlAttrControlState, features.EnergyControlAttrOptOutState},
+		MinInterval:  time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("Subscribe() error = %v", err)
+	}
+	if attrs.ControlState == nil || *attrs.ControlState != features.ControlStateControlled {
+		t.Errorf("priming ControlState = %v, want CONTROLLED", attrs.ControlState)
+	}
+
+	time.Sleep(5 * time.Millisecond) // let MinInterval pass
+	if err := ec.WriteOptOutState(ctx, features.OptOutStateGrid); err != nil {
+		t.Fatalf("WriteOptOutState() error = %v", err)
+	}
+
+	select {
+	case notif := <-notified:
+		if notif.SubscriptionID != subID {
+			t.Errorf("notification subscription = %d, want %d", notif.SubscriptionID, subID)
+		}
+		attrs.Update(notif.Changes)
+		if attrs.OptOutState == nil || *attrs.OptOutState != features.OptOutStateGrid {
+			t.Errorf("OptOutState after notification = %v, want GRID", attrs.OptOutState)
+		}
+		if attrs.ControlState == nil || *attrs.ControlState != features.ControlStateControlled {
+			t.Errorf("ControlState after notification = %v, want unchanged", attrs.ControlState)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected a notification")
+	}
+}
+
+func TestDecodeUint(t *testing.T) {
+	tests := []struct {
+		raw  any
+		want uint8
+		ok   bool
+	}{
+		{uint64(7), 7, true},
+		{int64(7), 7, true},
+		{uint8(255), 255, true},
+		{uint64(256), 0, false},
+		{int64(-1), 0, false},
+		{"7", 0, false},
+		{nil, 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := decodeUint[uint8](tt.raw)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("decodeUint[uint8](%#v) = %v, %v; want %v, %v", tt.raw, got, ok, tt.want, tt.ok)
+		}
+	}
+}
@@ -0,0 +1,284 @@
+// Code generated by mash-featgen. DO NOT EDIT.
+
+package featureclient
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mash-protocol/mash-go/pkg/features"
+	"github.com/mash-protocol/mash-go/pkg/interaction"
+	"github.com/mash-protocol/mash-go/pkg/model"
+)
+
+// DeviceInfoClient is a typed client for the DeviceInfo feature of a remote device.
+type DeviceInfoClient struct {
+	client     DeviceClient
+	endpointID uint8
+}
+
+// NewDeviceInfoClient returns a client for the DeviceInfo feature on the given
+// endpoint of the device behind client.
+func NewDeviceInfoClient(client DeviceClient, endpointID uint8) *DeviceInfoClient {
+	return &DeviceInfoClient{client: client, endpointID: endpointID}
+}
+
+// EndpointID returns the endpoint the client addresses.
+func (c *DeviceInfoClient) EndpointID() uint8 {
+	return c.endpointID
+}
+
+// DeviceInfoAttributes holds decoded DeviceInfo attribute values. Attributes that were
+// not reported, or were reported as null, are nil.
+type DeviceInfoAttributes struct {
+	DeviceID        *string
+	VendorName      *string
+	ProductName     *string
+	SerialNumber    *string
+	VendorID        *uint32
+	ProductID       *uint16
+	SoftwareVersion *string
+	HardwareVersion *string
+	SpecVersion     *string
+	Endpoints       []any
+	UseCases        []any
+	Location        *string
+	Label           *string
+	ZoneCount       *uint8
+}
+
+// DecodeDeviceInfoAttributes decodes attribute values as returned by Read and
+// Subscribe and carried in subscription notifications.
+func DecodeDeviceInfoAttributes(values map[uint16]any) *DeviceInfoAttributes {
+	a := &DeviceInfoAttributes{}
+	a.Update(values)
+	return a
+}
+
+// Update applies attribute values, e.g. from a subscription notification.
+// Attributes not present in values are left unchanged; values that cannot be
+// decoded clear the attribute.
+func (a *DeviceInfoAttributes) Update(values map[uint16]any) {
+	for id, raw := range values {
+		switch id {
+		case features.DeviceInfoAttrDeviceID:
+			a.DeviceID = decodePtr(raw, decodeAs[string])
+		case features.DeviceInfoAttrVendorName:
+			a.VendorName = decodePtr(raw, decodeAs[string])
+		case features.DeviceInfoAttrProductName:
+			a.ProductName = decodePtr(raw, decodeAs[string])
+		case features.DeviceInfoAttrSerialNumber:
+			a.SerialNumber = decodePtr(raw, decodeAs[string])
+		case features.DeviceInfoAttrVendorID:
+			a.VendorID = decodePtr(raw, decodeUint[uint32])
+		case features.DeviceInfoAttrProductID:
+			a.ProductID = decodePtr(raw, decodeUint[uint16])
+		case features.DeviceInfoAttrSoftwareVersion:
+			a.SoftwareVersion = decodePtr(raw, decodeAs[string])
+		case features.DeviceInfoAttrHardwareVersion:
+			a.HardwareVersion = decodePtr(raw, decodeAs[string])
+		case features.DeviceInfoAttrSpecVersion:
+			a.SpecVersion = decodePtr(raw, decodeAs[string])
+		case features.DeviceInfoAttrEndpoints:
+			a.Endpoints, _ = decodeAs[[]any](raw)
+		case features.DeviceInfoAttrUseCases:
+			a.UseCases, _ = decodeAs[[]any](raw)
+		case features.DeviceInfoAttrLocation:
+			a.Location = decodePtr(raw, decodeAs[string])
+		case features.DeviceInfoAttrLabel:
+			a.Label = decodePtr(raw, decodeAs[string])
+		case features.DeviceInfoAttrZoneCount:
+			a.ZoneCount = decodePtr(raw, decodeUint[uint8])
+		}
+	}
+}
+
+// Read reads the given attributes, or all attributes if none are given.
+func (c *DeviceInfoClient) Read(ctx context.Context, attrIDs ...uint16) (*DeviceInfoAttributes, error) {
+	values, err := c.client.Read(ctx, c.endpointID, uint8(model.FeatureDeviceInfo), attrIDs)
+	if err != nil {
+		return nil, err
+	}
+	return DecodeDeviceInfoAttributes(values), nil
+}
+
+// Subscribe subscribes to DeviceInfo attribute changes. It returns the
+// subscription ID and the decoded priming report; decode the subsequent
+// notifications with DeviceInfoAttributes.Update.
+func (c *DeviceInfoClient) Subscribe(ctx context.Context, opts *interaction.SubscribeOptions) (uint32, *DeviceInfoAttributes, error) {
+	subID, values, err := c.client.Subscribe(ctx, c.endpointID, uint8(model.FeatureDeviceInfo), opts)
+	if err != nil {
+		return 0, nil, err
+	}
+	return subID, DecodeDeviceInfoAttributes(values), nil
+}
+
+// ReadDeviceID reads the deviceId attribute: globally unique device identifier.
+func (c *DeviceInfoClient) ReadDeviceID(ctx context.Context) (string, error) {
+	raw, err := readAttribute(ctx, c.client, c.endpointID, uint8(model.FeatureDeviceInfo), features.DeviceInfoAttrDeviceID)
+	if err != nil {
+		var zero string
+		return zero, err
+	}
+	return decodeValue(raw, decodeAs[string])
+}
+
+// ReadVendorName reads the vendorName attribute: manufacturer name.
+func (c *DeviceInfoClient) ReadVendorName(ctx context.Context) (string, error) {
+	raw, err := readAttribute(ctx, c.client, c.endpointID, uint8(model.FeatureDeviceInfo), features.DeviceInfoAttrVendorName)
+	if err != nil {
+		var zero string
+		return zero, err
+	}
+	return decodeValue(raw, decodeAs[string])
+}
+
+// ReadProductName reads the productName attribute: product name.
+func (c *DeviceInfoClient) ReadProductName(ctx context.Context) (string, error) {
+	raw, err := readAttribute(ctx, c.client, c.endpointID, uint8(model.FeatureDeviceInfo), features.DeviceInfoAttrProductName)
+	if err != nil {
+		var zero string
+		return zero, err
+	}
+	return decodeValue(raw, decodeAs[string])
+}
+
+// ReadSerialNumber reads the serialNumber attribute: device serial number.
+func (c *DeviceInfoClient) ReadSerialNumber(ctx context.Context) (string, error) {
+	raw, err := readAttribute(ctx, c.client, c.endpointID, uint8(model.FeatureDeviceInfo), features.DeviceInfoAttrSerialNumber)
+	if err != nil {
+		var zero string
+		return zero, err
+	}
+	return decodeValue(raw, decodeAs[string])
+}
+
+// ReadVendorID reads the vendorId attribute: iANA Private Enterprise Number.
+func (c *DeviceInfoClient) ReadVendorID(ctx context.Context) (*uint32, error) {
+	raw, err := readAttribute(ctx, c.client, c.endpointID, uint8(model.FeatureDeviceInfo), features.DeviceInfoAttrVendorID)
+	if err != nil {
+		return nil, err
+	}
+	return decodeNullable(raw, decodeUint[uint32])
+}
+
+// ReadProductID reads the productId attribute: vendor's product ID.
+func (c *DeviceInfoClient) ReadProductID(ctx context.Context) (*uint16, error) {
+	raw, err := readAttribute(ctx, c.client, c.endpointID, uint8(model.FeatureDeviceInfo), features.DeviceInfoAttrProductID)
+	if err != nil {
+		return nil, err
+	}
+	return decodeNullable(raw, decodeUint[uint16])
+}
+
+// ReadSoftwareVersion reads the softwareVersion attribute: firmware/software version.
+func (c *DeviceInfoClient) ReadSoftwareVersion(ctx context.Context) (string, error) {
+	raw, err := readAttribute(ctx, c.client, c.endpointID, uint8(model.FeatureDeviceInfo), features.DeviceInfoAttrSoftwareVersion)
+	if err != nil {
+		var zero string
+		return zero, err
+	}
+	return decodeValue(raw, decodeAs[string])
+}
+
+// ReadHardwareVersion reads the hardwareVersion attribute: hardware revision.
+func (c *DeviceInfoClient) ReadHardwareVersion(ctx context.Context) (*string, error) {
+	raw, err := readAttribute(ctx, c.client, c.endpointID, uint8(model.FeatureDeviceInfo), features.DeviceInfoAttrHardwareVersion)
+	if err != nil {
+		return nil, err
+	}
+	return decodeNullable(raw, decodeAs[string])
+}
+
+// ReadSpecVersion reads the specVersion attribute: mASH specification version.
+func (c *DeviceInfoClient) ReadSpecVersion(ctx context.Context) (string, error) {
+	raw, err := readAttribute(ctx, c.client, c.endpointID, uint8(model.FeatureDeviceInfo), features.DeviceInfoAttrSpecVersion)
+	if err != nil {
+		var zero string
+		return zero, err
+	}
+	return decodeValue(raw, decodeAs[string])
+}
+
+// ReadEndpoints reads the endpoints attribute: complete device endpoint structure.
+func (c *DeviceInfoClient) ReadEndpoints(ctx context.Context) ([]any, error) {
+	raw, err := readAttribute(ctx, c.client, c.endpointID, uint8(model.FeatureDeviceInfo), features.DeviceInfoAttrEndpoints)
+	if err != nil {
+		return nil, err
+	}
+	return decodeCollection(raw, decodeAs[[]any])
+}
+
+// ReadUseCases reads the useCases attribute: use cases supported by this device.
+func (c *DeviceInfoClient) ReadUseCases(ctx context.Context) ([]any, error) {
+	raw, err := readAttribute(ctx, c.client, c.endpointID, uint8(model.FeatureDeviceInfo), features.DeviceInfoAttrUseCases)
+	if err != nil {
+		return nil, err
+	}
+	return decodeCollection(raw, decodeAs[[]any])
+}
+
+// ReadLocation reads the location attribute: installation location.
+func (c *DeviceInfoClient) ReadLocation(ctx context.Context) (*string, error) {
+	raw, err := readAttribute(ctx, c.client, c.endpointID, uint8(model.FeatureDeviceInfo), features.DeviceInfoAttrLocation)
+	if err != nil {
+		return nil, err
+	}
+	return decodeNullable(raw, decodeAs[string])
+}
+
+// WriteLocation writes the location attribute: installation location.
+// A nil value clears it.
+func (c *DeviceInfoClient) WriteLocation(ctx context.Context, v *string) error {
+	var value any
+	if v != nil {
+		value = *v
+	}
+	_, err := c.client.Write(ctx, c.endpointID, uint8(model.FeatureDeviceInfo), map[uint16]any{features.DeviceInfoAttrLocation: value})
+	return err
+}
+
+// ReadLabel reads the label attribute: user-assigned name.
+func (c *DeviceInfoClient) ReadLabel(ctx context.Context) (*string, error) {
+	raw, err := readAttribute(ctx, c.client, c.endpointID, uint8(model.FeatureDeviceInfo), features.DeviceInfoAttrLabel)
+	if err != nil {
+		return nil, err
+	}
+	return decodeNullable(raw, decodeAs[string])
+}
+
+// WriteLabel writes the label attribute: user-assigned name.
+// A nil value clears it.
+func (c *DeviceInfoClient) WriteLabel(ctx context.Context, v *string) error {
+	var value any
+	if v != nil {
+		value = *v
+	}
+	_, err := c.client.Write(ctx, c.endpointID, uint8(model.FeatureDeviceInfo), map[uint16]any{features.DeviceInfoAttrLabel: value})
+	return err
+}
+
+// ReadZoneCount reads the zoneCount attribute: number of zones currently configured on this device.
+func (c *DeviceInfoClient) ReadZoneCount(ctx context.Context) (uint8, error) {
+	raw, err := readAttribute(ctx, c.client, c.endpointID, uint8(model.FeatureDeviceInfo), features.DeviceInfoAttrZoneCount)
+	if err != nil {
+		var zero uint8
+		return zero, err
+	}
+	return decodeValue(raw, decodeUint[uint8])
+}
+
+// RemoveZone invokes the removeZone command: remove a zone from this device.
+func (c *DeviceInfoClient) RemoveZone(ctx context.Context, req features.RemoveZoneRequest) (features.RemoveZoneResponse, error) {
+	params := make(map[string]any, 1)
+	params["zoneId"] = req.ZoneID
+	m, err := invokeCommand(ctx, c.client, c.endpointID, uint8(model.FeatureDeviceInfo), features.DeviceInfoCmdRemoveZone, params)
+	if err != nil {
+		return features.RemoveZoneResponse{}, err
+	}
+	var resp features.RemoveZoneResponse
+	if !decodeField(m, "removed", decodeAs[bool], &resp.Removed) {
+		return resp, fmt.Errorf("%w: removed", ErrUnexpectedValue)
+	}
+	return resp, nil
+}
@@ -0,0 +1,833 @@
+// Code generated by mash-featgen. DO NOT EDIT.
+
+package featureclient
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mash-protocol/mash-go/pkg/features"
+	"github.com/mash-protocol/mash-go/pkg/interaction"
+	"github.com/mash-protocol/mash-go/pkg/model"
+)
+
+// EnergyControlClient is a typed client for the EnergyControl feature of a remote device.
+type EnergyControlClient struct {
+	client     DeviceClient
+	endpointID uint8
+}
+
+// NewEnergyControlClient returns a client for the EnergyControl feature on the given
+// endpoint of the device behind client.
+func NewEnergyControlClient(client DeviceClient, endpointID uint8) *EnergyControlClient {
+	return &EnergyControlClient{client: client, endpointID: endpointID}
+}
+
+// EndpointID returns the endpoint the client addresses.
+func (c *EnergyControlClient) EndpointID() uint8 {
+	return c.endpointID
+}
+
+// EnergyControlAttributes holds decoded EnergyControl attribute values. Attributes that were
+// not reported, or were reported as null, are nil.
+type EnergyControlAttributes struct {
+	DeviceType                           *features.DeviceType
+	ControlState                         *features.ControlState
+	OptOutState                          *features.OptOutState
+	AcceptsLimits                        *bool
+	AcceptsCurrentLimits                 *bool
+	AcceptsSetpoints                     *bool
+	AcceptsCurrentSetpoints              *bool
+	IsPausable                           *bool
+	IsShiftable                          *bool
+	IsStoppable                          *bool
+	EffectiveConsumptionLimit            *int64
+	MyConsumptionLimit                   *int64
+	EffectiveProductionLimit             *int64
+	MyProductionLimit                    *int64
+	EffectiveCurrentLimitsConsumption    map[features.Phase]int64
+	MyCurrentLimitsConsumption           map[features.Phase]int64
+	EffectiveCurrentLimitsProduction     map[features.Phase]int64
+	MyCurrentLimitsProduction            map[features.Phase]int64
+	EffectiveConsumptionSetpoint         *int64
+	MyConsumptionSetpoint                *int64
+	EffectiveProductionSetpoint          *int64
+	MyProductionSetpoint                 *int64
+	EffectiveCurrentSetpointsConsumption map[features.Phase]int64
+	MyCurrentSetpointsConsumption        map[features.Phase]int64
+	EffectiveCurrentSetpointsProduction  map[features.Phase]int64
+	MyCurrentSetpointsProduction         map[features.Phase]int64
+	FailsafeConsumptionLimit             *int64
+	FailsafeProductionLimit              *int64
+	FailsafeDuration                     *uint32
+	ContractualConsumptionMax            *int64
+	ContractualProductionMax             *int64
+	OverrideReason                       *features.OverrideReason
+	OverrideDirection                    *features.Direction
+	ProcessState                         *features.ProcessState
+	OptionalProcess                      *bool
+	MinRunDuration                       *uint32
+	MinPauseDuration                     *uint32
+	MaxRunDuration                       *uint32
+	MaxPauseDuration                     *uint32
+	OptionalProcessPower                 *int64
+	ControlMode                          *features.ControlMode
+}
+
+// DecodeEnergyControlAttributes decodes attribute values as returned by Read and
+// Subscribe and carried in subscription notifications.
+func DecodeEnergyControlAttributes(values map[uint16]any) *EnergyControlAttributes {
+	a := &EnergyControlAttributes{}
+	a.Update(values)
+	return a
+}
+
+// Update applies attribute values, e.g. from a subscription notification.
+// Attributes not present in values are left unchanged; values that cannot be
+// decoded clear the attribute.
+func (a *EnergyControlAttributes) Update(values map[uint16]any) {
+	for id, raw := range values {
+		switch id {
+		case features.EnergyControlAttrDeviceType:
+			a.DeviceType = decodePtr(raw, decodeUint[features.DeviceType])
+		case features.EnergyControlAttrControlState:
+			a.ControlState = decodePtr(raw, decodeUint[features.ControlState])
+		case features.EnergyControlAttrOptOutState:
+			a.OptOutState = decodePtr(raw, decodeUint[features.OptOutState])
+		case features.EnergyControlAttrAcceptsLimits:
+			a.AcceptsLimits = decodePtr(raw, decodeAs[bool])
+		case features.EnergyControlAttrAcceptsCurrentLimits:
+			a.AcceptsCurrentLimits = decodePtr(raw, decodeAs[bool])
+		case features.EnergyControlAttrAcceptsSetpoints:
+			a.AcceptsSetpoints = decodePtr(raw, decodeAs[bool])
+		case features.EnergyControlAttrAcceptsCurrentSetpoints:
+			a.AcceptsCurrentSetpoints = decodePtr(raw, decodeAs[bool])
+		case features.EnergyControlAttrIsPausable:
+			a.IsPausable = decodePtr(raw, decodeAs[bool])
+		case features.EnergyControlAttrIsShiftable:
+			a.IsShiftable = decodePtr(raw, decodeAs[bool])
+		case features.EnergyControlAttrIsStoppable:
+			a.IsStoppable = decodePtr(raw, decodeAs[bool])
+		case features.EnergyControlAttrEffectiveConsumptionLimit:
+			a.EffectiveConsumptionLimit = decodePtr(raw, decodeInt[int64])
+		case features.EnergyControlAttrMyConsumptionLimit:
+			a.MyConsumptionLimit = decodePtr(raw, decodeInt[int64])
+		case features.EnergyControlAttrEffectiveProductionLimit:
+			a.EffectiveProductionLimit = decodePtr(raw, decodeInt[int64])
+		case features.EnergyControlAttrMyProductionLimit:
+			a.MyProductionLimit = decodePtr(raw, decodeInt[int64])
+		case features.EnergyControlAttrEffectiveCurrentLimitsConsumption:
+			a.EffectiveCurrentLimitsConsumption, _ = mapDecoder(decodeUint[features.Phase], decodeInt[int64])(raw)
+		case features.EnergyControlAttrMyCurrentLimitsConsumption:
+			a.MyCurrentLimitsConsumption, _ = mapDecoder(decodeUint[features.Phase], decodeInt[int64])(raw)
+		case features.EnergyControlAttrEffectiveCurrentLimitsProduction:
+			a.EffectiveCurrentLimitsProduction, _ = mapDecoder(decodeUint[features.Phase], decodeInt[int64])(raw)
+		case features.EnergyControlAttrMyCurrentLimitsProduction:
+			a.MyCurrentLimitsProduction, _ = mapDecoder(decodeUint[features.Phase], decodeInt[int64])(raw)
+		case features.EnergyControlAttrEffectiveConsumptionSetpoint:
+			a.EffectiveConsumptionSetpoint = decodePtr(raw, decodeInt[int64])
+		case features.EnergyControlAttrMyConsumptionSetpoint:
+			a.MyConsumptionSetpoint = decodePtr(raw, decodeInt[int64])
+		case features.EnergyControlAttrEffectiveProductionSetpoint:
+			a.EffectiveProductionSetpoint = decodePtr(raw, decodeInt[int64])
+		case features.EnergyControlAttrMyProductionSetpoint:
+			a.MyProductionSetpoint = decodePtr(raw, decodeInt[int64])
+		case features.EnergyControlAttrEffectiveCurrentSetpointsConsumption:
+			a.EffectiveCurrentSetpointsConsumption, _ = mapDecoder(decodeUint[features.Phase], decodeInt[int64])(raw)
+		case features.EnergyControlAttrMyCurrentSetpointsConsumption:
+			a.MyCurrentSetpointsConsumption, _ = mapDecoder(decodeUint[features.Phase], decodeInt[int64])(raw)
+		case features.EnergyControlAttrEffectiveCurrentSetpointsProduction:
+			a.EffectiveCurrentSetpointsProduction, _ = mapDecoder(decodeUint[features.Phase], decodeInt[int64])(raw)
+		case features.EnergyControlAttrMyCurrentSetpointsProduction:
+			a.MyCurrentSetpointsProduction, _ = mapDecoder(decodeUint[features.Phase], decodeInt[int64])(raw)
+		case features.EnergyControlAttrFailsafeConsumptionLimit:
+			a.FailsafeConsumptionLimit = decodePtr(raw, decodeInt[int64])
+		case features.EnergyControlAttrFailsafeProductionLimit:
+			a.FailsafeProductionLimit = decodePtr(raw, decodeInt[int64])
+		case features.EnergyControlAttrFailsafeDuration:
+			a.FailsafeDuration = decodePtr(raw, decodeUint[uint32])
+		case features.EnergyControlAttrContractualConsumptionMax:
+			a.ContractualConsumptionMax = decodePtr(raw, decodeInt[int64])
+		case features.EnergyControlAttrContractualProductionMax:
+			a.ContractualProductionMax = decodePtr(raw, decodeInt[int64])
+		case features.EnergyControlAttrOverrideReason:
+			a.OverrideReason = decodePtr(raw, decodeUint[features.OverrideReason])
+		case features.EnergyControlAttrOverrideDirection:
+			a.OverrideDirection = decodePtr(raw, decodeUint[features.Direction])
+		case features.EnergyControlAttrProcessState:
+			a.ProcessState = decodePtr(raw, decodeUint[features.ProcessState])
+		case features.EnergyControlAttrOptionalProcess:
+			a.OptionalProcess = decodePtr(raw, decodeAs[bool])
+		case features.EnergyControlAttrMinRunDuration:
+			a.MinRunDuration = decodePtr(raw, decodeUint[uint32])
+		case features.EnergyControlAttrMinPauseDuration:
+			a.MinPauseDuration = decodePtr(raw, decodeUint[uint32])
+		case features.EnergyControlAttrMaxRunDuration:
+			a.MaxRunDuration = decodePtr(raw, decodeUint[uint32])
+		case features.EnergyControlAttrMaxPauseDuration:
+			a.MaxPauseDuration = decodePtr(raw, decodeUint[uint32])
+		case features.EnergyControlAttrOptionalProcessPower:
+			a.OptionalProcessPower = decodePtr(raw, decodeInt[int64])
+		case features.EnergyControlAttrControlMode:
+			a.ControlMode = decodePtr(raw, decodeUint[features.ControlMode])
+		}
+	}
+}
+
+// Read reads the given attributes, or all attributes if none are given.
+func (c *EnergyControlClient) Read(ctx context.Context, attrIDs ...uint16) (*EnergyControlAttributes, error) {
+	values, err := c.client.Read(ctx, c.endpointID, uint8(model.FeatureEnergyControl), attrIDs)
+	if err != nil {
+		return nil, err
+	}
+	return DecodeEnergyControlAttributes(values), nil
+}
+
+// Subscribe subscribes to EnergyControl attribute changes. It returns the
+// subscription ID and the decoded priming report; decode the subsequent
+// notifications with EnergyControlAttributes.Update.
+func (c *EnergyControlClient) Subscribe(ctx context.Context, opts *interaction.SubscribeOptions) (uint32, *EnergyControlAttributes, error) {
+	subID, values, err := c.client.Subscribe(ctx, c.endpointID, uint8(model.FeatureEnergyControl), opts)
+	if err != nil {
+		return 0, nil, err
+	}
+	return subID, DecodeEnergyControlAttributes(values), nil
+}
+
+// ReadDeviceType reads the deviceType attribute: type of controllable device.
+func (c *EnergyControlClient) ReadDeviceType(ctx context.Context) (features.DeviceType, error) {
+	raw, err := readAttribute(ctx, c.client, c.endpointID, uint8(model.FeatureEnergyControl), features.EnergyControlAttrDeviceType)
+	if err != nil {
+		var zero features.DeviceType
+		return zero, err
+	}
+	return decodeValue(raw, decodeUint[features.DeviceType])
+}
+
+// ReadControlState reads the controlState attribute: control relationship state.
+func (c *EnergyControlClient) ReadControlState(ctx context.Context) (features.ControlState, error) {
+	raw, err := readAttribute(ctx, c.client, c.endpointID, uint8(model.FeatureEnergyControl), features.EnergyControlAttrControlState)
+	if err != nil {
+		var zero features.ControlState
+		return zero, err
+	}
+	return decodeValue(raw, decodeUint[features.ControlState])
+}
+
+// ReadOptOutState reads the optOutState attribute: opt-out state for external control.
+func (c *EnergyControlClient) ReadOptOutState(ctx context.Context) (features.OptOutState, error) {
+	raw, err := readAttribute(ctx, c.client, c.endpointID, uint8(model.FeatureEnergyControl), features.EnergyControlAttrOptOutState)
+	if err != nil {
+		var zero features.OptOutState
+		return zero, err
+	}
+	return decodeValue(raw, decodeUint[features.OptOutState])
+}
+
+// WriteOptOutState writes the optOutState attribute: opt-out state for external control.
+func (c *EnergyControlClient) WriteOptOutState(ctx context.Context, v features.OptOutState) error {
+	_, err := c.client.Write(ctx, c.endpointID, uint8(model.FeatureEnergyControl), map[uint16]any{features.EnergyControlAttrOptOutState: uint8(v)})
+	return err
+}
+
+// ReadAcceptsLimits reads the acceptsLimits attribute: accepts SetLimit command.
+func (c *EnergyControlClient) ReadAcceptsLimits(ctx context.Context) (bool, error) {
+	raw, err := readAttribute(ctx, c.client, c.endpointID, uint8(model.FeatureEnergyControl), features.EnergyControlAttrAcceptsLimits)
+	if err != nil {
+		var zero bool
+		return zero, err
+	}
+	return decodeValue(raw, decodeAs[bool])
+}
+
+// ReadAcceptsCurrentLimits reads the acceptsCurrentLimits attribute: accepts SetCurrentLimits command.
+func (c *EnergyControlClient) ReadAcceptsCurrentLimits(ctx context.Context) (bool, error) {
+	raw, err := readAttribute(ctx, c.client, c.endpointID, uint8(model.FeatureEnergyControl), features.EnergyControlAttrAcceptsCurrentLimits)
+	if err != nil {
+		var zero bool
+		return zero, err
+	}
+	return decodeValue(raw, decodeAs[bool])
+}
+
+// ReadAcceptsSetpoints reads the acceptsSetpoints attribute: accepts SetSetpoint command.
+func (c *EnergyControlClient) ReadAcceptsSetpoints(ctx context.Context) (bool, error) {
+	raw, err := readAttribute(ctx, c.client, c.endpointID, uint8(model.FeatureEnergyControl), features.EnergyControlAttrAcceptsSetpoints)
+	if err != nil {
+		var zero bool
+		return zero, err
+	}
+	return decodeValue(raw, decodeAs[bool])
+}
+
+// ReadAcceptsCurrentSetpoints reads the acceptsCurrentSetpoints attribute: accepts SetCurrentSetpoints command.
+func (c *EnergyControlClient) ReadAcceptsCurrentSetpoints(ctx context.Context) (bool, error) {
+	raw, err := readAttribute(ctx, c.client, c.endpointID, uint8(model.FeatureEnergyControl), features.EnergyControlAttrAcceptsCurrentSetpoints)
+	if err != nil {
+		var zero bool
+		return zero, err
+	}
+	return decodeValue(raw, decodeAs[bool])
+}
+
+// ReadIsPausable reads the isPausable attribute: accepts Pause/Resume commands.
+func (c *EnergyControlClient) ReadIsPausable(ctx context.Context) (bool, error) {
+	raw, err := readAttribute(ctx, c.client, c.endpointID, uint8(model.FeatureEnergyControl), features.EnergyControlAttrIsPausable)
+	if err != nil {
+		var zero bool
+		return zero, err
+	}
+	return decodeValue(raw, decodeAs[bool])
+}
+
+// ReadIsShiftable reads the isShiftable attribute: accepts AdjustStartTime command.
+func (c *EnergyControlClient) ReadIsShiftable(ctx context.Context) (bool, error) {
+	raw, err := readAttribute(ctx, c.client, c.endpointID, uint8(model.FeatureEnergyControl), features.EnergyControlAttrIsShiftable)
+	if err != nil {
+		var zero bool
+		return zero, err
+	}
+	return decodeValue(raw, decodeAs[bool])
+}
+
+// ReadIsStoppable reads the isStoppable attribute: accepts Stop command.
+func (c *EnergyControlClient) ReadIsStoppable(ctx context.Context) (bool, error) {
+	raw, err := readAttribute(ctx, c.client, c.endpointID, uint8(model.FeatureEnergyControl), features.EnergyControlAttrIsStoppable)
+	if err != nil {
+		var zero bool
+		return zero, err
+	}
+	return decodeValue(raw, decodeAs[bool])
+}
+
+// ReadEffectiveConsumptionLimit reads the effectiveConsumptionLimit attribute: effective consumption limit (min of all zones).
+func (c *EnergyControlClient) ReadEffectiveConsumptionLimit(ctx context.Context) (*int64, error) {
+	raw, err := readAttribute(ctx, c.client, c.endpointID, uint8(model.FeatureEnergyControl), features.EnergyControlAttrEffectiveConsumptionLimit)
+	if err != nil {
+		return nil, err
+	}
+	return decodeNullable(raw, decodeInt[int64])
+}
+
+// ReadMyConsumptionLimit reads the myConsumptionLimit attribute: this zone's consumption limit.
+func (c *EnergyControlClient) ReadMyConsumptionLimit(ctx context.Context) (*int64, error) {
+	raw, err := readAttribute(ctx, c.client, c.endpointID, uint8(model.FeatureEnergyControl), features.EnergyControlAttrMyConsumptionLimit)
+	if err != nil {
+		return nil, err
+	}
+	return decodeNullable(raw, decodeInt[int64])
+}
+
+// WriteMyConsumptionLimit writes the myConsumptionLimit attribute: this zone's consumption limit.
+// A nil value clears it.
+func (c *EnergyControlClient) WriteMyConsumptionLimit(ctx context.Context, v *int64) error {
+	var value any
+	if v != nil {
+		value = *v
+	}
+	_, err := c.client.Write(ctx, c.endpointID, uint8(model.FeatureEnergyControl), map[uint16]any{features.EnergyControlAttrMyConsumptionLimit: value})
+	return err
+}
+
+// ReadEffectiveProductionLimit reads the effectiveProductionLimit attribute: effective production limit (min of all zones).
+func (c *EnergyControlClient) ReadEffectiveProductionLimit(ctx context.Context) (*int64, error) {
+	raw, err := readAttribute(ctx, c.client, c.endpointID, uint8(model.FeatureEnergyControl), features.EnergyControlAttrEffectiveProductionLimit)
+	if err != nil {
+		return nil, err
+	}
+	return decodeNullable(raw, decodeInt[int64])
+}
+
+// ReadMyProductionLimit reads the myProductionLimit attribute: this zone's production limit.
+func (c *EnergyControlClient) ReadMyProductionLimit(ctx context.Context) (*int64, error) {
+	raw, err := readAttribute(ctx, c.client, c.endpointID, uint8(model.FeatureEnergyControl), features.EnergyControlAttrMyProductionLimit)
+	if err != nil {
+		return nil, err
+	}
+	return decodeNullable(raw, decodeInt[int64])
+}
+
+// WriteMyProductionLimit writes the myProductionLimit attribute: this zone's production limit.
+// A nil value clears it.
+func (c *EnergyControlClient) WriteMyProductionLimit(ctx context.Context, v *int64) error {
+	var value any
+	if v != nil {
+		value = *v
+	}
+	_, err := c.client.Write(ctx, c.endpointID, uint8(model.FeatureEnergyControl), map[uint16]any{features.EnergyControlAttrMyProductionLimit: value})
+	return err
+}
+
+// ReadEffectiveCurrentLimitsConsumption reads the effectiveCurrentLimitsConsumption attribute: effective per-phase current limits (consumption).
+func (c *EnergyControlClient) ReadEffectiveCurrentLimitsConsumption(ctx context.Context) (map[features.Phase]int64, error) {
+	raw, err := readAttribute(ctx, c.client, c.endpointID, uint8(model.FeatureEnergyControl), features.EnergyControlAttrEffectiveCurrentLimitsConsumption)
+	if err != nil {
+		return nil, err
+	}
+	return decodeCollection(raw, mapDecoder(decodeUint[features.Phase], decodeInt[int64]))
+}
+
+// ReadMyCurrentLimitsConsumption reads the myCurrentLimitsConsumption attribute: this zone's per-phase current limits (consumption).
+func (c *EnergyControlClient) ReadMyCurrentLimitsConsumption(ctx context.Context) (map[features.Phase]int64, error) {
+	raw, err := readAttribute(ctx, c.client, c.endpointID, uint8(model.FeatureEnergyControl), features.EnergyControlAttrMyCurrentLimitsConsumption)
+	if err != nil {
+		return nil, err
+	}
+	return decodeCollection(raw, mapDecoder(decodeUint[features.Phase], decodeInt[int64]))
+}
+
+// ReadEffectiveCurrentLimitsProduction reads the effectiveCurrentLimitsProduction attribute: effective per-phase current limits (production).
+func (c *EnergyControlClient) ReadEffectiveCurrentLimitsProduction(ctx context.Context) (map[features.Phase]int64, error) {
+	raw, err := readAttribute(ctx, c.client, c.endpointID, uint8(model.FeatureEnergyControl), features.EnergyControlAttrEffectiveCurrentLimitsProduction)
+	if err != nil {
+		return nil, err
+	}
+	return decodeCollection(raw, mapDecoder(decodeUint[features.Phase], decodeInt[int64]))
+}
+
+// ReadMyCurrentLimitsProduction reads the myCurrentLimitsProduction attribute: this zone's per-phase current limits (production).
+func (c *EnergyControlClient) ReadMyCurrentLimitsProduction(ctx context.Context) (map[features.Phase]int64, error) {
+	raw, err := readAttribute(ctx, c.client, c.endpointID, uint8(model.FeatureEnergyControl), features.EnergyControlAttrMyCurrentLimitsProduction)
+	if err != nil {
+		return nil, err
+	}
+	return decodeCollection(raw, mapDecoder(decodeUint[features.Phase], decodeInt[int64]))
+}
+
+// ReadEffectiveConsumptionSetpoint reads the effectiveConsumptionSetpoint attribute: effective consumption setpoint.
+func (c *EnergyControlClient) ReadEffectiveConsumptionSetpoint(ctx context.Context) (*int64, error) {
+	raw, err := readAttribute(ctx, c.client, c.endpointID, uint8(model.FeatureEnergyControl), features.EnergyControlAttrEffectiveConsumptionSetpoint)
+	if err != nil {
+		return nil, err
+	}
+	return decodeNullable(raw, decodeInt[int64])
+}
+
+// ReadMyConsumptionSetpoint reads the myConsumptionSetpoint attribute: this zone's consumption setpoint.
+func (c *EnergyControlClient) ReadMyConsumptionSetpoint(ctx context.Context) (*int64, error) {
+	raw, err := readAttribute(ctx, c.client, c.endpointID, uint8(model.FeatureEnergyControl), features.EnergyControlAttrMyConsumptionSetpoint)
+	if err != nil {
+		return nil, err
+	}
+	return decodeNullable(raw, decodeInt[int64])
+}
+
+// WriteMyConsumptionSetpoint writes the myConsumptionSetpoint attribute: this zone's consumption setpoint.
+// A nil value clears it.
+func (c *EnergyControlClient) WriteMyConsumptionSetpoint(ctx context.Context, v *int64) error {
+	var value any
+	if v != nil {
+		value = *v
+	}
+	_, err := c.client.Write(ctx, c.endpointID, uint8(model.FeatureEnergyControl), map[uint16]any{features.EnergyControlAttrMyConsumptionSetpoint: value})
+	return err
+}
+
+// ReadEffectiveProductionSetpoint reads the effectiveProductionSetpoint attribute: effective production setpoint.
+func (c *EnergyControlClient) ReadEffectiveProductionSetpoint(ctx context.Context) (*int64, error) {
+	raw, err := readAttribute(ctx, c.client, c.endpointID, uint8(model.FeatureEnergyControl), features.EnergyControlAttrEffectiveProductionSetpoint)
+	if err != nil {
+		return nil, err
+	}
+	return decodeNullable(raw, decodeInt[int64])
+}
+
+// ReadMyProductionSetpoint reads the myProductionSetpoint attribute: this zone's production setpoint.
+func (c *EnergyControlClient) ReadMyProductionSetpoint(ctx context.Context) (*int64, error) {
+	raw, err := readAttribute(ctx, c.client, c.endpointID, uint8(model.FeatureEnergyControl), features.EnergyControlAttrMyProductionSetpoint)
+	if err != nil {
+		return nil, err
+	}
+	return decodeNullable(raw, decodeInt[int64])
+}
+
+// WriteMyProductionSetpoint writes the myProductionSetpoint attribute: this zone's production setpoint.
+// A nil value clears it.
+func (c *EnergyControlClient) WriteMyProductionSetpoint(ctx context.Context, v *int64) error {
+	var value any
+	if v != nil {
+		value = *v
+	}
+	_, err := c.client.Write(ctx, c.endpointID, uint8(model.FeatureEnergyControl), map[uint16]any{features.EnergyControlAttrMyProductionSetpoint: value})
+	return err
+}
+
+// ReadEffectiveCurrentSetpointsConsumption reads the effectiveCurrentSetpointsConsumption attribute: effective per-phase current setpoints (consumption).
+func (c *EnergyControlClient) ReadEffectiveCurrentSetpointsConsumption(ctx context.Context) (map[features.Phase]int64, error) {
+	raw, err := readAttribute(ctx, c.client, c.endpointID, uint8(model.FeatureEnergyControl), features.EnergyControlAttrEffectiveCurrentSetpointsConsumption)
+	if err != nil {
+		return nil, err
+	}
+	return decodeCollection(raw, mapDecoder(decodeUint[features.Phase], decodeInt[int64]))
+}
+
+// ReadMyCurrentSetpointsConsumption reads the myCurrentSetpointsConsumption attribute: this zone's per-phase current setpoints (consumption).
+func (c *EnergyControlClient) ReadMyCurrentSetpointsConsumption(ctx context.Context) (map[features.Phase]int64, error) {
+	raw, err := readAttribute(ctx, c.client, c.endpointID, uint8(model.FeatureEnergyControl), features.EnergyControlAttrMyCurrentSetpointsConsumption)
+	if err != nil {
+		return nil, err
+	}
+	return decodeCollection(raw, mapDecoder(decodeUint[features.Phase], decodeInt[int64]))
+}
+
+// ReadEffectiveCurrentSetpointsProduction reads the effectiveCurrentSetpointsProduction attribute: effective per-phase current setpoints (production).
+func (c *EnergyControlClient) ReadEffectiveCurrentSetpointsProduction(ctx context.Context) (map[features.Phase]int64, error) {
+	raw, err := readAttribute(ctx, c.client, c.endpointID, uint8(model.FeatureEnergyControl), features.EnergyControlAttrEffectiveCurrentSetpointsProduction)
+	if err != nil {
+		return nil, err
+	}
+	return decodeCollection(raw, mapDecoder(decodeUint[features.Phase], decodeInt[int64]))
+}
+
+// ReadMyCurrentSetpointsProduction reads the myCurrentSetpointsProduction attribute: this zone's per-phase current setpoints (production).
+func (c *EnergyControlClient) ReadMyCurrentSetpointsProduction(ctx context.Context) (map[features.Phase]int64, error) {
+	raw, err := readAttribute(ctx, c.client, c.endpointID, uint8(model.FeatureEnergyControl), features.EnergyControlAttrMyCurrentSetpointsProduction)
+	if err != nil {
+		return nil, err
+	}
+	return decodeCollection(raw, mapDecoder(decodeUint[features.Phase], decodeInt[int64]))
+}
+
+// ReadFailsafeConsumptionLimit reads the failsafeConsumptionLimit attribute: limit to apply in FAILSAFE state.
+func (c *EnergyControlClient) ReadFailsafeConsumptionLimit(ctx context.Context) (*int64, error) {
+	raw, err := readAttribute(ctx, c.client, c.endpointID, uint8(model.FeatureEnergyControl), features.EnergyControlAttrFailsafeConsumptionLimit)
+	if err != nil {
+		return nil, err
+	}
+	return decodeNullable(raw, decodeInt[int64])
+}
+
+// WriteFailsafeConsumptionLimit writes the failsafeConsumptionLimit attribute: limit to apply in FAILSAFE state.
+// A nil value clears it.
+func (c *EnergyControlClient) WriteFailsafeConsumptionLimit(ctx context.Context, v *int64) error {
+	var value any
+	if v != nil {
+		value = *v
+	}
+	_, err := c.client.Write(ctx, c.endpointID, uint8(model.FeatureEnergyControl), map[uint16]any{features.EnergyControlAttrFailsafeConsumptionLimit: value})
+	return err
+}
+
+// ReadFailsafeProductionLimit reads the failsafeProductionLimit attribute: production limit in FAILSAFE state.
+func (c *EnergyControlClient) ReadFailsafeProductionLimit(ctx context.Context) (*int64, error) {
+	raw, err := readAttribute(ctx, c.client, c.endpointID, uint8(model.FeatureEnergyControl), features.EnergyControlAttrFailsafeProductionLimit)
+	if err != nil {
+		return nil, err
+	}
+	return decodeNullable(raw, decodeInt[int64])
+}
+
+// WriteFailsafeProductionLimit writes the failsafeProductionLimit attribute: production limit in FAILSAFE state.
+// A nil value clears it.
+func (c *EnergyControlClient) WriteFailsafeProductionLimit(ctx context.Context, v *int64) error {
+	var value any
+	if v != nil {
+		value = *v
+	}
+	_, err := c.client.Write(ctx, c.endpointID, uint8(model.FeatureEnergyControl), map[uint16]any{features.EnergyControlAttrFailsafeProductionLimit: value})
+	return err
+}
+
+// ReadFailsafeDuration reads the failsafeDuration attribute: time in FAILSAFE before returning to AUTONOMOUS (DEC-070: 2h floor, 24h cap).
+func (c *EnergyControlClient) ReadFailsafeDuration(ctx context.Context) (uint32, error) {
+	raw, err := readAttribute(ctx, c.client, c.endpointID, uint8(model.FeatureEnergyControl), features.EnergyControlAttrFailsafeDuration)
+	if err != nil {
+		var zero uint32
+		return zero, err
+	}
+	return decodeValue(raw, decodeUint[uint32])
+}
+
+// WriteFailsafeDuration writes the failsafeDuration attribute: time in FAILSAFE before returning to AUTONOMOUS (DEC-070: 2h floor, 24h cap).
+func (c *EnergyControlClient) WriteFailsafeDuration(ctx context.Context, v uint32) error {
+	_, err := c.client.Write(ctx, c.endpointID, uint8(model.FeatureEnergyControl), map[uint16]any{features.EnergyControlAttrFailsafeDuration: v})
+	return err
+}
+
+// ReadContractualConsumptionMax reads the contractualConsumptionMax attribute: building's max allowed consumption (EMS only).
+func (c *EnergyControlClient) ReadContractualConsumptionMax(ctx context.Context) (*int64, error) {
+	raw, err := readAttribute(ctx, c.client, c.endpointID, uint8(model.FeatureEnergyControl), features.EnergyControlAttrContractualConsumptionMax)
+	if err != nil {
+		return nil, err
+	}
+	return decodeNullable(raw, decodeInt[int64])
+}
+
+// ReadContractualProductionMax reads the contractualProductionMax attribute: building's max allowed feed-in (EMS only).
+func (c *EnergyControlClient) ReadContractualProductionMax(ctx context.Context) (*int64, error) {
+	raw, err := readAttribute(ctx, c.client, c.endpointID, uint8(model.FeatureEnergyControl), features.EnergyControlAttrContractualProductionMax)
+	if err != nil {
+		return nil, err
+	}
+	return decodeNullable(raw, decodeInt[int64])
+}
+
+// ReadOverrideReason reads the overrideReason attribute: why device is in OVERRIDE state.
+func (c *EnergyControlClient) ReadOverrideReason(ctx context.Context) (*features.OverrideReason, error) {
+	raw, err := readAttribute(ctx, c.client, c.endpointID, uint8(model.FeatureEnergyControl), features.EnergyControlAttrOverrideReason)
+	if err != nil {
+		return nil, err
+	}
+	return decodeNullable(raw, decodeUint[features.OverrideReason])
+}
+
+// ReadOverrideDirection reads the overrideDirection attribute: which direction triggered override.
+func (c *EnergyControlClient) ReadOverrideDirection(ctx context.Context) (*features.Direction, error) {
+	raw, err := readAttribute(ctx, c.client, c.endpointID, uint8(model.FeatureEnergyControl), features.EnergyControlAttrOverrideDirection)
+	if err != nil {
+		return nil, err
+	}
+	return decodeNullable(raw, decodeUint[features.Direction])
+}
+
+// ReadProcessState reads the processState attribute: current process lifecycle state.
+func (c *EnergyControlClient) ReadProcessState(ctx context.Context) (features.ProcessState, error) {
+	raw, err := readAttribute(ctx, c.client, c.endpointID, uint8(model.FeatureEnergyControl), features.EnergyControlAttrProcessState)
+	if err != nil {
+		var zero features.ProcessState
+		return zero, err
+	}
+	return decodeValue(raw, decodeUint[features.ProcessState])
+}
+
+// ReadOptionalProcess reads the optionalProcess attribute: whether the process is optional (can be deferred).
+func (c *EnergyControlClient) ReadOptionalProcess(ctx context.Context) (bool, error) {
+	raw, err := readAttribute(ctx, c.client, c.endpointID, uint8(model.FeatureEnergyControl), features.EnergyControlAttrOptionalProcess)
+	if err != nil {
+		var zero bool
+		return zero, err
+	}
+	return decodeValue(raw, decodeAs[bool])
+}
+
+// ReadMinRunDuration reads the minRunDuration attribute: minimum run time before pause/stop.
+func (c *EnergyControlClient) ReadMinRunDuration(ctx context.Context) (*uint32, error) {
+	raw, err := readAttribute(ctx, c.client, c.endpointID, uint8(model.FeatureEnergyControl), features.EnergyControlAttrMinRunDuration)
+	if err != nil {
+		return nil, err
+	}
+	return decodeNullable(raw, decodeUint[uint32])
+}
+
+// ReadMinPauseDuration reads the minPauseDuration attribute: minimum pause before restart.
+func (c *EnergyControlClient) ReadMinPauseDuration(ctx context.Context) (*uint32, error) {
+	raw, err := readAttribute(ctx, c.client, c.endpointID, uint8(model.FeatureEnergyControl), features.EnergyControlAttrMinPauseDuration)
+	if err != nil {
+		return nil, err
+	}
+	return decodeNullable(raw, decodeUint[uint32])
+}
+
+// ReadMaxRunDuration reads the maxRunDuration attribute: maximum continuous run time.
+func (c *EnergyControlClient) ReadMaxRunDuration(ctx context.Context) (*uint32, error) {
+	raw, err := readAttribute(ctx, c.client, c.endpointID, uint8(model.FeatureEnergyControl), features.EnergyControlAttrMaxRunDuration)
+	if err != nil {
+		return nil, err
+	}
+	return decodeNullable(raw, decodeUint[uint32])
+}
+
+// ReadMaxPauseDuration reads the maxPauseDuration attribute: maximum pause time.
+func (c *EnergyControlClient) ReadMaxPauseDuration(ctx context.Context) (*uint32, error) {
+	raw, err := readAttribute(ctx, c.client, c.endpointID, uint8(model.FeatureEnergyControl), features.EnergyControlAttrMaxPauseDuration)
+	if err != nil {
+		return nil, err
+	}
+	return decodeNullable(raw, decodeUint[uint32])
+}
+
+// ReadOptionalProcessPower reads the optionalProcessPower attribute: expected power when process runs.
+func (c *EnergyControlClient) ReadOptionalProcessPower(ctx context.Context) (*int64, error) {
+	raw, err := readAttribute(ctx, c.client, c.endpointID, uint8(model.FeatureEnergyControl), features.EnergyControlAttrOptionalProcessPower)
+	if err != nil {
+		return nil, err
+	}
+	return decodeNullable(raw, decodeInt[int64])
+}
+
+// ReadControlMode reads the controlMode attribute: how setpoints/limits are interpreted.
+func (c *EnergyControlClient) ReadControlMode(ctx context.Context) (*features.ControlMode, error) {
+	raw, err := readAttribute(ctx, c.client, c.endpointID, uint8(model.FeatureEnergyControl), features.EnergyControlAttrControlMode)
+	if err != nil {
+		return nil, err
+	}
+	return decodeNullable(raw, decodeUint[features.ControlMode])
+}
+
+// WriteControlMode writes the controlMode attribute: how setpoints/limits are interpreted.
+// A nil value clears it.
+func (c *EnergyControlClient) WriteControlMode(ctx context.Context, v *features.ControlMode) error {
+	var value any
+	if v != nil {
+		value = uint8(*v)
+	}
+	_, err := c.client.Write(ctx, c.endpointID, uint8(model.FeatureEnergyControl), map[uint16]any{features.EnergyControlAttrControlMode: value})
+	return err
+}
+
+// SetLimit invokes the setLimit command: set power limits for this zone.
+func (c *EnergyControlClient) SetLimit(ctx context.Context, req features.SetLimitRequest) (features.SetLimitResponse, error) {
+	params := make(map[string]any, 4)
+	if req.ConsumptionLimit != nil {
+		params["consumptionLimit"] = *req.ConsumptionLimit
+	}
+	if req.ProductionLimit != nil {
+		params["productionLimit"] = *req.ProductionLimit
+	}
+	if req.Duration != nil {
+		params["duration"] = *req.Duration
+	}
+	params["cause"] = uint8(req.Cause)
+	m, err := invokeCommand(ctx, c.client, c.endpointID, uint8(model.FeatureEnergyControl), features.EnergyControlCmdSetLimit, params)
+	if err != nil {
+		return features.SetLimitResponse{}, err
+	}
+	var resp features.SetLimitResponse
+	if !decodeField(m, "applied", decodeAs[bool], &resp.Applied) {
+		return resp, fmt.Errorf("%w: applied", ErrUnexpectedValue)
+	}
+	if !decodeField(m, "controlState", decodeUint[features.ControlState], &resp.ControlState) {
+		return resp, fmt.Errorf("%w: controlState", ErrUnexpectedValue)
+	}
+	resp.EffectiveConsumptionLimit = decodePtr(m["effectiveConsumptionLimit"], decodeInt[int64])
+	resp.EffectiveProductionLimit = decodePtr(m["effectiveProductionLimit"], decodeInt[int64])
+	resp.RejectReason = decodePtr(m["rejectReason"], decodeUint[features.LimitRejectReason])
+	return resp, nil
+}
+
+// ClearLimit invokes the clearLimit command: remove this zone's power limits.
+func (c *EnergyControlClient) ClearLimit(ctx context.Context, req features.ClearLimitRequest) error {
+	params := make(map[string]any, 1)
+	if req.Direction != nil {
+		params["direction"] = uint8(*req.Direction)
+	}
+	m, err := invokeCommand(ctx, c.client, c.endpointID, uint8(model.FeatureEnergyControl), features.EnergyControlCmdClearLimit, params)
+	if err != nil {
+		return err
+	}
+	return checkSuccess(m)
+}
+
+// SetCurrentLimits invokes the setCurrentLimits command: set per-phase current limits.
+func (c *EnergyControlClient) SetCurrentLimits(ctx context.Context, req features.SetCurrentLimitsRequest) error {
+	params := make(map[string]any, 4)
+	params["phases"] = req.Phases
+	params["direction"] = uint8(req.Direction)
+	if req.Duration != nil {
+		params["duration"] = *req.Duration
+	}
+	params["cause"] = uint8(req.Cause)
+	m, err := invokeCommand(ctx, c.client, c.endpointID, uint8(model.FeatureEnergyControl), features.EnergyControlCmdSetCurrentLimits, params)
+	if err != nil {
+		return err
+	}
+	return checkSuccess(m)
+}
+
+// ClearCurrentLimits invokes the clearCurrentLimits command: remove this zone's per-phase current limits.
+func (c *EnergyControlClient) ClearCurrentLimits(ctx context.Context, req features.ClearCurrentLimitsRequest) error {
+	params := make(map[string]any, 1)
+	if req.Direction != nil {
+		params["direction"] = uint8(*req.Direction)
+	}
+	m, err := invokeCommand(ctx, c.client, c.endpointID, uint8(model.FeatureEnergyControl), features.EnergyControlCmdClearCurrentLimits, params)
+	if err != nil {
+		return err
+	}
+	return checkSuccess(m)
+}
+
+// SetSetpoint invokes the setSetpoint command: set power setpoint for this zone.
+func (c *EnergyControlClient) SetSetpoint(ctx context.Context, req features.SetSetpointRequest) error {
+	params := make(map[string]any, 4)
+	if req.ConsumptionSetpoint != nil {
+		params["consumptionSetpoint"] = *req.ConsumptionSetpoint
+	}
+	if req.ProductionSetpoint != nil {
+		params["productionSetpoint"] = *req.ProductionSetpoint
+	}
+	if req.Duration != nil {
+		params["duration"] = *req.Duration
+	}
+	params["cause"] = uint8(req.Cause)
+	m, err := invokeCommand(ctx, c.client, c.endpointID, uint8(model.FeatureEnergyControl), features.EnergyControlCmdSetSetpoint, params)
+	if err != nil {
+		return err
+	}
+	return checkSuccess(m)
+}
+
+// ClearSetpoint invokes the clearSetpoint command: remove this zone's power setpoints.
+func (c *EnergyControlClient) ClearSetpoint(ctx context.Context, req features.ClearSetpointRequest) error {
+	params := make(map[string]any, 1)
+	if req.Direction != nil {
+		params["direction"] = uint8(*req.Direction)
+	}
+	m, err := invokeCommand(ctx, c.client, c.endpointID, uint8(model.FeatureEnergyControl), features.EnergyControlCmdClearSetpoint, params)
+	if err != nil {
+		return err
+	}
+	return checkSuccess(m)
+}
+
+// SetCurrentSetpoints invokes the setCurrentSetpoints command: set per-phase current setpoints.
+func (c *EnergyControlClient) SetCurrentSetpoints(ctx context.Context, req features.SetCurrentSetpointsRequest) error {
+	params := make(map[string]any, 4)
+	params["phases"] = req.Phases
+	params["direction"] = uint8(req.Direction)
+	if req.Duration != nil {
+		params["duration"] = *req.Duration
+	}
+	params["cause"] = uint8(req.Cause)
+	m, err := invokeCommand(ctx, c.client, c.endpointID, uint8(model.FeatureEnergyControl), features.EnergyControlCmdSetCurrentSetpoints, params)
+	if err != nil {
+		return err
+	}
+	return checkSuccess(m)
+}
+
+// ClearCurrentSetpoints invokes the clearCurrentSetpoints command: remove this zone's per-phase current setpoints.
+func (c *EnergyControlClient) ClearCurrentSetpoints(ctx context.Context, req features.ClearCurrentSetpointsRequest) error {
+	params := make(map[string]any, 1)
+	if req.Direction != nil {
+		params["direction"] = uint8(*req.Direction)
+	}
+	m, err := invokeCommand(ctx, c.client, c.endpointID, uint8(model.FeatureEnergyControl), features.EnergyControlCmdClearCurrentSetpoints, params)
+	if err != nil {
+		return err
+	}
+	return checkSuccess(m)
+}
+
+// Pause invokes the pause command: temporarily pause device operation.
+func (c *EnergyControlClient) Pause(ctx context.Context, req features.PauseRequest) error {
+	params := make(map[string]any, 1)
+	if req.Duration != nil {
+		params["duration"] = *req.Duration
+	}
+	m, err := invokeCommand(ctx, c.client, c.endpointID, uint8(model.FeatureEnergyControl), features.EnergyControlCmdPause, params)
+	if err != nil {
+		return err
+	}
+	return checkSuccess(m)
+}
+
+// Resume invokes the resume command: resume paused operation.
+func (c *EnergyControlClient) Resume(ctx context.Context) error {
+	var params map[string]any
+	m, err := invokeCommand(ctx, c.client, c.endpointID, uint8(model.FeatureEnergyControl), features.EnergyControlCmdResume, params)
+	if err != nil {
+		return err
+	}
+	return checkSuccess(m)
+}
+
+// Stop invokes the stop command: abort task completely.
+func (c *EnergyControlClient) Stop(ctx context.Context) error {
+	var params map[string]any
+	m, err := invokeCommand(ctx, c.client, c.endpointID, uint8(model.FeatureEnergyControl), features.EnergyControlCmdStop, params)
+	if err != nil {
+		return err
+	}
+	return checkSuccess(m)
+}
@@ -0,0 +1,359 @@
+// Code generated by mash-featgen. DO NOT EDIT.
+
+package featureclient
+
+import (
+	"context"
+
+	"github.com/mash-protocol/mash-go/pkg/features"
+	"github.com/mash-protocol/mash-go/pkg/interaction"
+	"github.com/mash-protocol/mash-go/pkg/model"
+)
+
+// MeasurementClient is a typed client for the Measurement feature of a remote device.
+type MeasurementClient struct {
+	client     DeviceClient
+	endpointID uint8
+}
+
+// NewMeasurementClient returns a client for the Measurement feature on the given
+// endpoint of the device behind client.
+func NewMeasurementClient(client DeviceClient, endpointID uint8) *MeasurementClient {
+	return &MeasurementClient{client: client, endpointID: endpointID}
+}
+
+// EndpointID returns the endpoint the client addresses.
+func (c *MeasurementClient) EndpointID() uint8 {
+	return c.endpointID
+}
+
+// MeasurementAttributes holds decoded Measurement attribute values. Attributes that were
+// not reported, or were reported as null, are nil.
+type MeasurementAttributes struct {
+	ACActivePower             *int64
+	ACReactivePower           *int64
+	ACApparentPower           *uint64
+	ACActivePowerPerPhase     map[features.Phase]int64
+	ACReactivePowerPerPhase   map[features.Phase]int64
+	ACApparentPowerPerPhase   map[features.Phase]uint64
+	ACCurrentPerPhase         map[features.Phase]int64
+	ACVoltagePerPhase         map[features.Phase]uint32
+	ACVoltagePhaseToPhasePair map[features.PhasePair]uint32
+	ACFrequency               *uint32
+	PowerFactor               *int16
+	ACEnergyConsumed          *uint64
+	ACEnergyProduced          *uint64
+	DCPower                   *int64
+	DCCurrent                 *int64
+	DCVoltage                 *uint32
+	DCEnergyIn                *uint64
+	DCEnergyOut               *uint64
+	StateOfCharge             *uint8
+	StateOfHealth             *uint8
+	StateOfEnergy             *uint64
+	UseableCapacity           *uint64
+	CycleCount                *uint32
+	Temperature               *int16
+}
+
+// DecodeMeasurementAttributes decodes attribute values as returned by Read and
+// Subscribe and carried in subscription notifications.
+func DecodeMeasurementAttributes(values map[uint16]any) *MeasurementAttributes {
+	a := &MeasurementAttributes{}
+	a.Update(values)
+	return a
+}
+
+// Update applies attribute values, e.g. from a subscription notification.
+// Attributes not present in values are left unchanged; values that cannot be
+// decoded clear the attribute.
+func (a *MeasurementAttributes) Update(values map[uint16]any) {
+	for id, raw := range values {
+		switch id {
+		case features.MeasurementAttrACActivePower:
+			a.ACActivePower = decodePtr(raw, decodeInt[int64])
+		case features.MeasurementAttrACReactivePower:
+			a.ACReactivePower = decodePtr(raw, decodeInt[int64])
+		case features.MeasurementAttrACApparentPower:
+			a.ACApparentPower = decodePtr(raw, decodeUint[uint64])
+		case features.MeasurementAttrACActivePowerPerPhase:
+			a.ACActivePowerPerPhase, _ = mapDecoder(decodeUint[features.Phase], decodeInt[int64])(raw)
+		case features.MeasurementAttrACReactivePowerPerPhase:
+			a.ACReactivePowerPerPhase, _ = mapDecoder(decodeUint[features.Phase], decodeInt[int64])(raw)
+		case features.MeasurementAttrACApparentPowerPerPhase:
+			a.ACApparentPowerPerPhase, _ = mapDecoder(decodeUint[features.Phase], decodeUint[uint64])(raw)
+		case features.MeasurementAttrACCurrentPerPhase:
+			a.ACCurrentPerPhase, _ = mapDecoder(decodeUint[features.Phase], decodeInt[int64])(raw)
+		case features.MeasurementAttrACVoltagePerPhase:
+			a.ACVoltagePerPhase, _ = mapDecoder(decodeUint[features.Phase], decodeUint[uint32])(raw)
+		case features.MeasurementAttrACVoltagePhaseToPhasePair:
+			a.ACVoltagePhaseToPhasePair, _ = mapDecoder(decodeUint[features.PhasePair], decodeUint[uint32])(raw)
+		case features.MeasurementAttrACFrequency:
+			a.ACFrequency = decodePtr(raw, decodeUint[uint32])
+		case features.MeasurementAttrPowerFactor:
+			a.PowerFactor = decodePtr(raw, decodeInt[int16])
+		case features.MeasurementAttrACEnergyConsumed:
+			a.ACEnergyConsumed = decodePtr(raw, decodeUint[uint64])
+		case features.MeasurementAttrACEnergyProduced:
+			a.ACEnergyProduced = decodePtr(raw, decodeUint[uint64])
+		case features.MeasurementAttrDCPower:
+			a.DCPower = decodePtr(raw, decodeInt[int64])
+		case features.MeasurementAttrDCCurrent:
+			a.DCCurrent = decodePtr(raw, decodeInt[int64])
+		case features.MeasurementAttrDCVoltage:
+			a.DCVoltage = decodePtr(raw, decodeUint[uint32])
+		case features.MeasurementAttrDCEnergyIn:
+			a.DCEnergyIn = decodePtr(raw, decodeUint[uint64])
+		case features.MeasurementAttrDCEnergyOut:
+			a.DCEnergyOut = decodePtr(raw, decodeUint[uint64])
+		case features.MeasurementAttrStateOfCharge:
+			a.StateOfCharge = decodePtr(raw, decodeUint[uint8])
+		case features.MeasurementAttrStateOfHealth:
+			a.StateOfHealth = decodePtr(raw, decodeUint[uint8])
+		case features.MeasurementAttrStateOfEnergy:
+			a.StateOfEnergy = decodePtr(raw, decodeUint[uint64])
+		case features.MeasurementAttrUseableCapacity:
+			a.UseableCapacity = decodePtr(raw, decodeUint[uint64])
+		case features.MeasurementAttrCycleCount:
+			a.CycleCount = decodePtr(raw, decodeUint[uint32])
+		case features.MeasurementAttrTemperature:
+			a.Temperature = decodePtr(raw, decodeInt[int16])
+		}
+	}
+}
+
+// Read reads the given attributes, or all attributes if none are given.
+func (c *MeasurementClient) Read(ctx context.Context, attrIDs ...uint16) (*MeasurementAttributes, error) {
+	values, err := c.client.Read(ctx, c.endpointID, uint8(model.FeatureMeasurement), attrIDs)
+	if err != nil {
+		return nil, err
+	}
+	return DecodeMeasurementAttributes(values), nil
+}
+
+// Subscribe subscribes to Measurement attribute changes. It returns the
+// subscription ID and the decoded priming report; decode the subsequent
+// notifications with MeasurementAttributes.Update.
+func (c *MeasurementClient) Subscribe(ctx context.Context, opts *interaction.SubscribeOptions) (uint32, *MeasurementAttributes, error) {
+	subID, values, err := c.client.Subscribe(ctx, c.endpointID, uint8(model.FeatureMeasurement), opts)
+	if err != nil {
+		return 0, nil, err
+	}
+	return subID, DecodeMeasurementAttributes(values), nil
+}
+
+// ReadACActivePower reads the acActivePower attribute: active/real power.
+func (c *MeasurementClient) ReadACActivePower(ctx context.Context) (*int64, error) {
+	raw, err := readAttribute(ctx, c.client, c.endpointID, uint8(model.FeatureMeasurement), features.MeasurementAttrACActivePower)
+	if err != nil {
+		return nil, err
+	}
+	return decodeNullable(raw, decodeInt[int64])
+}
+
+// ReadACReactivePower reads the acReactivePower attribute: reactive power.
+func (c *MeasurementClient) ReadACReactivePower(ctx context.Context) (*int64, error) {
+	raw, err := readAttribute(ctx, c.client, c.endpointID, uint8(model.FeatureMeasurement), features.MeasurementAttrACReactivePower)
+	if err != nil {
+		return nil, err
+	}
+	return decodeNullable(raw, decodeInt[int64])
+}
+
+// ReadACApparentPower reads the acApparentPower attribute: apparent power (always positive).
+func (c *MeasurementClient) ReadACApparentPower(ctx context.Context) (*uint64, error) {
+	raw, err := readAttribute(ctx, c.client, c.endpointID, uint8(model.FeatureMeasurement), features.MeasurementAttrACApparentPower)
+	if err != nil {
+		return nil, err
+	}
+	return decodeNullable(raw, decodeUint[uint64])
+}
+
+// ReadACActivePowerPerPhase reads the acActivePowerPerPhase attribute: active power per phase (Phase -> mW).
+func (c *MeasurementClient) ReadACActivePowerPerPhase(ctx context.Context) (map[features.Phase]int64, error) {
+	raw, err := readAttribute(ctx, c.client, c.endpointID, uint8(model.FeatureMeasurement), features.MeasurementAttrACActivePowerPerPhase)
+	if err != nil {
+		return nil, err
+	}
+	return decodeCollection(raw, mapDecoder(decodeUint[features.Phase], decodeInt[int64]))
+}
+
+// ReadACReactivePowerPerPhase reads the acReactivePowerPerPhase attribute: reactive power per phase (Phase -> mVAR).
+func (c *MeasurementClient) ReadACReactivePowerPerPhase(ctx context.Context) (map[features.Phase]int64, error) {
+	raw, err := readAttribute(ctx, c.client, c.endpointID, uint8(model.FeatureMeasurement), features.MeasurementAttrACReactivePowerPerPhase)
+	if err != nil {
+		return nil, err
+	}
+	return decodeCollection(raw, mapDecoder(decodeUint[features.Phase], decodeInt[int64]))
+}
+
+// ReadACApparentPowerPerPhase reads the acApparentPowerPerPhase attribute: apparent power per phase (Phase -> mVA).
+func (c *MeasurementClient) ReadACApparentPowerPerPhase(ctx context.Context) (map[features.Phase]uint64, error) {
+	raw, err := readAttribute(ctx, c.client, c.endpointID, uint8(model.FeatureMeasurement), features.MeasurementAttrACApparentPowerPerPhase)
+	if err != nil {
+		return nil, err
+	}
+	return decodeCollection(raw, mapDecoder(decodeUint[features.Phase], decodeUint[uint64]))
+}
+
+// ReadACCurrentPerPhase reads the acCurrentPerPhase attribute: current per phase (Phase -> mA, signed).
+func (c *MeasurementClient) ReadACCurrentPerPhase(ctx context.Context) (map[features.Phase]int64, error) {
+	raw, err := readAttribute(ctx, c.client, c.endpointID, uint8(model.FeatureMeasurement), features.MeasurementAttrACCurrentPerPhase)
+	if err != nil {
+		return nil, err
+	}
+	return decodeCollection(raw, mapDecoder(decodeUint[features.Phase], decodeInt[int64]))
+}
+
+// ReadACVoltagePerPhase reads the acVoltagePerPhase attribute: phase-to-neutral voltage (Phase -> mV).
+func (c *MeasurementClient) ReadACVoltagePerPhase(ctx context.Context) (map[features.Phase]uint32, error) {
+	raw, err := readAttribute(ctx, c.client, c.endpointID, uint8(model.FeatureMeasurement), features.MeasurementAttrACVoltagePerPhase)
+	if err != nil {
+		return nil, err
+	}
+	return decodeCollection(raw, mapDecoder(decodeUint[features.Phase], decodeUint[uint32]))
+}
+
+// ReadACVoltagePhaseToPhasePair reads the acVoltagePhaseToPhasePair attribute: phase-to-phase voltage (PhasePair -> mV).
+func (c *MeasurementClient) ReadACVoltagePhaseToPhasePair(ctx context.Context) (map[features.PhasePair]uint32, error) {
+	raw, err := readAttribute(ctx, c.client, c.endpointID, uint8(model.FeatureMeasurement), features.MeasurementAttrACVoltagePhaseToPhasePair)
+	if err != nil {
+		return nil, err
+	}
+	return decodeCollection(raw, mapDecoder(decodeUint[features.PhasePair], decodeUint[uint32]))
+}
+
+// ReadACFrequency reads the acFrequency attribute: grid frequency in millihertz (DEC-070 range: 45000-65000 mHz covers 50/60 Hz with headroom).
+func (c *MeasurementClient) ReadACFrequency(ctx context.Context) (*uint32, error) {
+	raw, err := readAttribute(ctx, c.client, c.endpointID, uint8(model.FeatureMeasurement), features.MeasurementAttrACFrequency)
+	if err != nil {
+		return nil, err
+	}
+	return decodeNullable(raw, decodeUint[uint32])
+}
+
+// ReadPowerFactor reads the powerFactor attribute: power factor (0.001 units, -1.0 to +1.0).
+func (c *MeasurementClient) ReadPowerFactor(ctx context.Context) (*int16, error) {
+	raw, err := readAttribute(ctx, c.client, c.endpointID, uint8(model.FeatureMeasurement), features.MeasurementAttrPowerFactor)
+	if err != nil {
+		return nil, err
+	}
+	return decodeNullable(raw, decodeInt[int16])
+}
+
+// ReadACEnergyConsumed reads the acEnergyConsumed attribute: total energy consumed from grid.
+func (c *MeasurementClient) ReadACEnergyConsumed(ctx context.Context) (*uint64, error) {
+	raw, err := readAttribute(ctx, c.client, c.endpointID, uint8(model.FeatureMeasurement), features.MeasurementAttrACEnergyConsumed)
+	if err != nil {
+		return nil, err
+	}
+	return decodeNullable(raw, decodeUint[uint64])
+}
+
+// ReadACEnergyProduced reads the acEnergyProduced attribute: total energy produced/fed-in to grid.
+func (c *MeasurementClient) ReadACEnergyProduced(ctx context.Context) (*uint64, error) {
+	raw, err := readAttribute(ctx, c.client, c.endpointID, uint8(model.FeatureMeasurement), features.MeasurementAttrACEnergyProduced)
+	if err != nil {
+		return nil, err
+	}
+	return decodeNullable(raw, decodeUint[uint64])
+}
+
+// ReadDCPower reads the dcPower attribute: dC power (+ into device, - out).
+func (c *MeasurementClient) ReadDCPower(ctx context.Context) (*int64, error) {
+	raw, err := readAttribute(ctx, c.client, c.endpointID, uint8(model.FeatureMeasurement), features.MeasurementAttrDCPower)
+	if err != nil {
+		return nil, err
+	}
+	return decodeNullable(raw, decodeInt[int64])
+}
+
+// ReadDCCurrent reads the dcCurrent attribute: dC current (+ into device, - out).
+func (c *MeasurementClient) ReadDCCurrent(ctx context.Context) (*int64, error) {
+	raw, err := readAttribute(ctx, c.client, c.endpointID, uint8(model.FeatureMeasurement), features.MeasurementAttrDCCurrent)
+	if err != nil {
+		return nil, err
+	}
+	return decodeNullable(raw, decodeInt[int64])
+}
+
+// ReadDCVoltage reads the dcVoltage attribute: dC voltage.
+func (c *MeasurementClient) ReadDCVoltage(ctx context.Context) (*uint32, error) {
+	raw, err := readAttribute(ctx, c.client, c.endpointID, uint8(model.FeatureMeasurement), features.MeasurementAttrDCVoltage)
+	if err != nil {
+		return nil, err
+	}
+	return decodeNullable(raw, decodeUint[uint32])
+}
+
+// ReadDCEnergyIn reads the dcEnergyIn attribute: energy into this component.
+func (c *MeasurementClient) ReadDCEnergyIn(ctx context.Context) (*uint64, error) {
+	raw, err := readAttribute(ctx, c.client, c.endpointID, uint8(model.FeatureMeasurement), features.MeasurementAttrDCEnergyIn)
+	if err != nil {
+		return nil, err
+	}
+	return decodeNullable(raw, decodeUint[uint64])
+}
+
+// ReadDCEnergyOut reads the dcEnergyOut attribute: energy out of this component.
+func (c *MeasurementClient) ReadDCEnergyOut(ctx context.Context) (*uint64, error) {
+	raw, err := readAttribute(ctx, c.client, c.endpointID, uint8(model.FeatureMeasurement), features.MeasurementAttrDCEnergyOut)
+	if err != nil {
+		return nil, err
+	}
+	return decodeNullable(raw, decodeUint[uint64])
+}
+
+// ReadStateOfCharge reads the stateOfCharge attribute: state of charge (0-100%).
+func (c *MeasurementClient) ReadStateOfCharge(ctx context.Context) (*uint8, error) {
+	raw, err := readAttribute(ctx, c.client, c.endpointID, uint8(model.FeatureMeasurement), features.MeasurementAttrStateOfCharge)
+	if err != nil {
+		return nil, err
+	}
+	return decodeNullable(raw, decodeUint[uint8])
+}
+
+// ReadStateOfHealth reads the stateOfHealth attribute: state of health (0-100%, battery degradation).
+func (c *MeasurementClient) ReadStateOfHealth(ctx context.Context) (*uint8, error) {
+	raw, err := readAttribute(ctx, c.client, c.endpointID, uint8(model.FeatureMeasurement), features.MeasurementAttrStateOfHealth)
+	if err != nil {
+		return nil, err
+	}
+	return decodeNullable(raw, decodeUint[uint8])
+}
+
+// ReadStateOfEnergy reads the stateOfEnergy attribute: available energy at current SoC.
+func (c *MeasurementClient) ReadStateOfEnergy(ctx context.Context) (*uint64, error) {
+	raw, err := readAttribute(ctx, c.client, c.endpointID, uint8(model.FeatureMeasurement), features.MeasurementAttrStateOfEnergy)
+	if err != nil {
+		return nil, err
+	}
+	return decodeNullable(raw, decodeUint[uint64])
+}
+
+// ReadUseableCapacity reads the useableCapacity attribute: current useable capacity.
+func (c *MeasurementClient) ReadUseableCapacity(ctx context.Context) (*uint64, error) {
+	raw, err := readAttribute(ctx, c.client, c.endpointID, uint8(model.FeatureMeasurement), features.MeasurementAttrUseableCapacity)
+	if err != nil {
+		return nil, err
+	}
+	return decodeNullable(raw, decodeUint[uint64])
+}
+
+// ReadCycleCount reads the cycleCount attribute: charge/discharge cycles.
+func (c *MeasurementClient) ReadCycleCount(ctx context.Context) (*uint32, error) {
+	raw, err := readAttribute(ctx, c.client, c.endpointID, uint8(model.FeatureMeasurement), features.MeasurementAttrCycleCount)
+	if err != nil {
+		return nil, err
+	}
+	return decodeNullable(raw, decodeUint[uint32])
+}
+
+// ReadTemperature reads the temperature attribute: temperature (e.g., 2500 = 25.00C).
+func (c *MeasurementClient) ReadTemperature(ctx context.Context) (*int16, error) {
+	raw, err := readAttribute(ctx, c.client, c.endpointID, uint8(model.FeatureMeasurement), features.MeasurementAttrTemperature)
+	if err != nil {
+		return nil, err
+	}
+	return decodeNullable(raw, decodeInt[int16])
+}
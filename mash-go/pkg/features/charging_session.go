@@ -123,7 +123,12 @@ func (c *ChargingSession) StartSession(startTime uint64) error {
 	if err := c.SetSessionEnergyDischarged(0); err != nil {
 		return err
 	}
-	return c.SetState(ChargingStatePluggedInNoDemand)
+	if err := c.SetState(ChargingStatePluggedInNoDemand); err != nil {
+		return err
+	}
+	return c.EmitEvent(ChargingSessionEventSessionStarted, map[string]any{
+		"startTime": startTime,
+	})
 }
 
 // EndSession ends the current charging session.
@@ -131,5 +136,12 @@ func (c *ChargingSession) EndSession(endTime uint64) error {
 	if err := c.SetSessionEndTime(endTime); err != nil {
 		return err
 	}
-	return c.SetState(ChargingStateNotPluggedIn)
+	if err := c.SetState(ChargingStateNotPluggedIn); err != nil {
+		return err
+	}
+	return c.EmitEvent(ChargingSessionEventSessionEnded, map[string]any{
+		"endTime":          endTime,
+		"energyCharged":    c.SessionEnergyCharged(),
+		"energyDischarged": c.SessionEnergyDischarged(),
+	})
 }
@@ -179,6 +179,20 @@ type EVIdentification struct {
 	Value string
 }
 
+// EncodeEVIdentifications encodes evIdentifications items for the wire. Nullable
+// fields are omitted when zero.
+func EncodeEVIdentifications(items []EVIdentification) []any {
+	data := make([]any, len(items))
+	for i, item := range items {
+		m := map[string]any{
+			"type":  uint8(item.Type),
+			"value": item.Value,
+		}
+		data[i] = m
+	}
+	return data
+}
+
 // ChargingSession wraps a Feature with ChargingSession-specific functionality.
 // EV charging session management, demands, and optimization.
 type ChargingSession struct {
@@ -448,6 +462,7 @@ func NewChargingSession() *ChargingSession {
 
 	c := &ChargingSession{Feature: f}
 	c.addCommands()
+	c.addEvents()
 
 	return c
 }
@@ -858,14 +873,7 @@ func (c *ChargingSession) SetEVIdentifications(evIdentifications []EVIdentificat
 	if evIdentifications == nil {
 		return attr.SetValueInternal(nil)
 	}
-	data := make([]any, len(evIdentifications))
-	for i, item := range evIdentifications {
-		data[i] = map[string]any{
-			"type":  uint8(item.Type),
-			"value": item.Value,
-		}
-	}
-	return attr.SetValueInternal(data)
+	return attr.SetValueInternal(EncodeEVIdentifications(evIdentifications))
 }
 
 // ClearEVIdentifications clears the list of EV identifiers.
@@ -1354,21 +1362,29 @@ func (c *ChargingSession) handleSetChargingMode(ctx context.Context, params map[
 	if raw, exists := params["mode"]; exists {
 		if v, ok := wire.ToUint8Public(raw); ok {
 			req.Mode = ChargingMode(v)
+		} else if raw != nil {
+			return nil, wire.NewFieldError(wire.StatusInvalidParameter, wire.FieldError{Path: "mode", Message: "expected uint8", Constraint: wire.ConstraintType})
 		}
 	}
 	if raw, exists := params["surplusThreshold"]; exists {
 		if v, ok := wire.ToInt64(raw); ok {
 			req.SurplusThreshold = &v
+		} else if raw != nil {
+			return nil, wire.NewFieldError(wire.StatusInvalidParameter, wire.FieldError{Path: "surplusThreshold", Message: "expected int64", Constraint: wire.ConstraintType})
 		}
 	}
 	if raw, exists := params["startDelay"]; exists {
 		if v, ok := wire.ToUint32(raw); ok {
 			req.StartDelay = &v
+		} else if raw != nil {
+			return nil, wire.NewFieldError(wire.StatusInvalidParameter, wire.FieldError{Path: "startDelay", Message: "expected uint32", Constraint: wire.ConstraintType})
 		}
 	}
 	if raw, exists := params["stopDelay"]; exists {
 		if v, ok := wire.ToUint32(raw); ok {
 			req.StopDelay = &v
+		} else if raw != nil {
+			return nil, wire.NewFieldError(wire.StatusInvalidParameter, wire.FieldError{Path: "stopDelay", Message: "expected uint32", Constraint: wire.ConstraintType})
 		}
 	}
 
@@ -1380,3 +1396,25 @@ func (c *ChargingSession) handleSetChargingMode(ctx context.Context, params map[
 func (c *ChargingSession) OnSetChargingMode(handler func(ctx context.Context, req SetChargingModeRequest) error) {
 	c.onSetChargingMode = handler
 }
+
+// ChargingSession event IDs.
+const (
+	ChargingSessionEventSessionStarted uint8 = 1
+	ChargingSessionEventSessionEnded   uint8 = 2
+)
+
+// addEvents registers the ChargingSession events.
+func (c *ChargingSession) addEvents() {
+	c.AddEvent(&model.EventMetadata{
+		ID:          ChargingSessionEventSessionStarted,
+		Name:        "sessionStarted",
+		Description: "EV connected and a new charging session began",
+	})
+
+	c.AddEvent(&model.EventMetadata{
+		ID:          ChargingSessionEventSessionEnded,
+		Name:        "sessionEnded",
+		Description: "EV disconnected and the charging session ended",
+	})
+
+}
@@ -0,0 +1,437 @@
+package features
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/mash-protocol/mash-go/pkg/cert"
+	"github.com/mash-protocol/mash-go/pkg/clock"
+	"github.com/mash-protocol/mash-go/pkg/model"
+	"github.com/mash-protocol/mash-go/pkg/wire"
+	"github.com/mash-protocol/mash-go/pkg/zonecontext"
+)
+
+// ConstraintSignalSourceID identifies the synthetic limit contribution that
+// a ConstraintScheduler feeds into the LimitResolver. It never collides with
+// a real zone ID (those are certificate fingerprints).
+const ConstraintSignalSourceID = "signal:constraint"
+
+// constraintSlot is a parsed ConstraintSlot that keeps track of which
+// bounds were actually sent. The generated ConstraintSlot uses plain int64
+// fields, so an absent bound would be indistinguishable from a bound of 0.
+type constraintSlot struct {
+	duration       time.Duration
+	consumptionMax *int64
+	consumptionMin *int64
+	productionMax  *int64
+	productionMin  *int64
+}
+
+// ConstraintScheduler applies the active slot of a Signals constraint
+// schedule as an effective limit. It walks StartTime plus the slot
+// durations, feeds the active slot's consumptionMax/productionMax into the
+// LimitResolver as a synthetic zone contribution, re-evaluates at every
+// slot boundary and drops the signal at ValidUntil (or after the last slot
+// when no ValidUntil was given).
+//
+// Only one constraint signal is active at a time; a new SendConstraintSignal
+// replaces the previous one. The contribution carries the sender's zone type
+// so it takes part in "most restrictive wins" resolution like any other zone.
+type ConstraintScheduler struct {
+	mu sync.Mutex
+
+	signals *Signals
+	lr      *LimitResolver
+
+	clock clock.Clock
+
+	active     bool
+	zoneID     string
+	zoneType   cert.ZoneType
+	startTime  time.Time
+	validUntil time.Time // zero if the signal has no explicit expiry
+	slots      []constraintSlot
+	current    int // index of the applied slot, -1 if none
+	timer      clock.Timer
+}
+
+// NewConstraintScheduler creates a ConstraintScheduler that reads constraint
+// signals from the Signals feature and applies them through the LimitResolver.
+func NewConstraintScheduler(signals *Signals, lr *LimitResolver) *ConstraintScheduler {
+	return &ConstraintScheduler{
+		signals: signals,
+		lr:      lr,
+		clock:   clock.Real(),
+		current: -1,
+	}
+}
+
+// SetClock sets the time source used for slot boundaries and expiry.
+// Must be called before the first signal is received.
+func (cs *ConstraintScheduler) SetClock(c clock.Clock) {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	cs.clock = clock.OrReal(c)
+}
+
+// Register wires the scheduler's SendConstraintSignal handler into the
+// Signals feature. ClearSignals remains with the device application, which
+// should call Clear when constraint signals are cleared.
+func (cs *ConstraintScheduler) Register() {
+	cs.signals.OnSendConstraintSignal(cs.HandleSendConstraintSignal)
+}
+
+// HandleSendConstraintSignal validates and installs a constraint schedule,
+// replacing any previous one, and applies the slot that is active now.
+func (cs *ConstraintScheduler) HandleSendConstraintSignal(ctx context.Context, req SendConstraintSignalRequest) error {
+	slots, err := parseConstraintSlots(req.Slots)
+	if err != nil {
+		return wire.NewFieldError(wire.StatusInvalidParameter, *err)
+	}
+
+	zoneType := zonecontext.CallerZoneTypeFromContext(ctx)
+	if zoneType == 0 {
+		zoneType = signalSourceZoneType(req.Source)
+	}
+
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+
+	// A startTime of 0 means "now".
+	startTime := req.StartTime
+	if startTime == 0 {
+		startTime = uint64(cs.clock.Now().Unix())
+	}
+	if req.ValidUntil != nil && *req.ValidUntil <= startTime {
+		return &wire.CommandError{
+			Status:  wire.StatusInvalidParameter,
+			Message: fmt.Sprintf("validUntil %d must be after startTime %d", *req.ValidUntil, startTime),
+			Fields: []wire.FieldError{{
+				Path:       "validUntil",
+				Message:    "must be after startTime",
+				Constraint: wire.ConstraintMin,
+				Limit:      startTime + 1,
+			}},
+		}
+	}
+
+	cs.stopTimerLocked()
+	replacing := cs.current >= 0
+	cs.active = true
+	cs.zoneID = zonecontext.CallerZoneIDFromContext(ctx)
+	cs.zoneType = zoneType
+	cs.startTime = time.Unix(int64(startTime), 0)
+	cs.validUntil = time.Time{}
+	if req.ValidUntil != nil {
+		cs.validUntil = time.Unix(int64(*req.ValidUntil), 0)
+	}
+	cs.slots = slots
+	cs.current = -1
+	if replacing {
+		// Withdraw the previous signal's contribution; the new schedule may
+		// not have an active slot yet.
+		cs.applyLocked()
+	}
+
+	cs.publish(cs.signals.Feature, map[uint16]any{
+		SignalsAttrSignalSource:    uint8(req.Source),
+		SignalsAttrStartTime:       startTime,
+		SignalsAttrValidUntil:      optionalUint64(req.ValidUntil),
+		SignalsAttrConstraintSlots: constraintSlotsData(slots),
+	})
+
+	log.Printf("[SIGNAL] Constraint signal from %s: %d slots starting %s", req.Source, len(slots), cs.startTime.UTC().Format(time.RFC3339))
+
+	cs.evaluateLocked()
+	return nil
+}
+
+// Clear withdraws the active constraint signal and its limit contribution.
+// Used by the device application's ClearSignals handler.
+func (cs *ConstraintScheduler) Clear() {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+
+	cs.expireLocked()
+}
+
+// ClearZone withdraws the active constraint signal if it was sent by the
+// given zone (e.g., when the zone is removed).
+func (cs *ConstraintScheduler) ClearZone(zoneID string) {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+
+	if cs.active && cs.zoneID == zoneID {
+		cs.expireLocked()
+	}
+}
+
+// ResetAll withdraws any constraint signal and stops the boundary timer.
+// This is used by TriggerResetTestState alongside the resolver resets.
+func (cs *ConstraintScheduler) ResetAll() {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+
+	cs.expireLocked()
+}
+
+// ActiveSlotIndex returns the index of the slot currently applied, or false
+// if no slot is active (no signal, before StartTime, or after the last slot).
+func (cs *ConstraintScheduler) ActiveSlotIndex() (int, bool) {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+
+	if cs.current < 0 {
+		return 0, false
+	}
+	return cs.current, true
+}
+
+// handleTimer is called at slot boundaries and at ValidUntil.
+func (cs *ConstraintScheduler) handleTimer() {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+
+	cs.evaluateLocked()
+}
+
+// evaluateLocked selects the slot that is active now, applies it if it
+// changed and arms the timer for the next boundary. Must be called with mu held.
+func (cs *ConstraintScheduler) evaluateLocked() {
+	if !cs.active {
+		return
+	}
+
+	now := cs.clock.Now()
+	if !cs.validUntil.IsZero() && !now.Before(cs.validUntil) {
+		log.Printf("[SIGNAL] Constraint signal expired at validUntil")
+		cs.expireLocked()
+		return
+	}
+
+	idx, next := cs.slotAt(now)
+	if idx < 0 && next.IsZero() && cs.validUntil.IsZero() {
+		// Past the last slot with no explicit expiry: the schedule is done.
+		log.Printf("[SIGNAL] Constraint signal schedule ended")
+		cs.expireLocked()
+		return
+	}
+
+	if idx != cs.current {
+		cs.current = idx
+		cs.applyLocked()
+	}
+
+	if !cs.validUntil.IsZero() && (next.IsZero() || cs.validUntil.Before(next)) {
+		next = cs.validUntil
+	}
+	if !next.IsZero() {
+		cs.stopTimerLocked()
+		cs.timer = cs.clock.AfterFunc(next.Sub(now), cs.handleTimer)
+	}
+}
+
+// slotAt returns the index of the slot containing t (-1 if none) and the
+// time of the next slot boundary after t (zero if there is none).
+func (cs *ConstraintScheduler) slotAt(t time.Time) (int, time.Time) {
+	if t.Before(cs.startTime) {
+		return -1, cs.startTime
+	}
+	end := cs.startTime
+	for i, s := range cs.slots {
+		end = end.Add(s.duration)
+		if t.Before(end) {
+			return i, end
+		}
+	}
+	return -1, time.Time{}
+}
+
+// applyLocked feeds the current slot's maxima into the LimitResolver and
+// notifies subscribers of the resulting EnergyControl attributes.
+// Must be called with mu held.
+func (cs *ConstraintScheduler) applyLocked() {
+	var consumption, production *int64
+	if cs.current >= 0 {
+		slot := cs.slots[cs.current]
+		consumption, production = slot.consumptionMax, slot.productionMax
+		log.Printf("[SIGNAL] Constraint slot %d active", cs.current)
+	}
+	cs.lr.SetSignalContribution(ConstraintSignalSourceID, cs.zoneType, consumption, production)
+	cs.republishLimits()
+}
+
+// expireLocked drops the signal, its limit contribution and the constraint
+// attributes. Must be called with mu held.
+func (cs *ConstraintScheduler) expireLocked() {
+	cs.stopTimerLocked()
+	if !cs.active {
+		return
+	}
+	cs.active = false
+	cs.zoneID = ""
+	cs.slots = nil
+	cs.current = -1
+
+	cs.lr.SetSignalContribution(ConstraintSignalSourceID, cs.zoneType, nil, nil)
+	cs.republishLimits()
+
+	changes := map[uint16]any{SignalsAttrConstraintSlots: nil}
+	// The source and validity window are shared with price and forecast
+	// signals; only drop them once no other signal remains.
+	if !cs.signals.HasActivePriceSignal() {
+		if _, ok := cs.signals.ForecastSlots(); !ok {
+			changes[SignalsAttrSignalSource] = nil
+			changes[SignalsAttrStartTime] = nil
+			changes[SignalsAttrValidUntil] = nil
+		}
+	}
+	cs.publish(cs.signals.Feature, changes)
+}
+
+// stopTimerLocked stops the boundary timer. Must be called with mu held.
+func (cs *ConstraintScheduler) stopTimerLocked() {
+	if cs.timer != nil {
+		cs.timer.Stop()
+		cs.timer = nil
+	}
+}
+
+// republishLimits pushes the current effective limits and control state to
+// subscribers. The LimitResolver updates these attributes silently, so
+// boundaries that occur without a command would otherwise go unnoticed.
+func (cs *ConstraintScheduler) republishLimits() {
+	f := cs.lr.ec.Feature
+	changes := make(map[uint16]any, 3)
+	for _, id := range []uint16{
+		EnergyControlAttrEffectiveConsumptionLimit,
+		EnergyControlAttrEffectiveProductionLimit,
+		EnergyControlAttrControlState,
+	} {
+		v, err := f.ReadAttribute(id)
+		if err != nil {
+			continue
+		}
+		changes[id] = v
+	}
+	cs.publish(f, changes)
+}
+
+// publish sets attributes through SetAttributeInternal so that feature
+// subscribers receive notifications.
+func (cs *ConstraintScheduler) publish(f *model.Feature, changes map[uint16]any) {
+	for id, v := range changes {
+		_ = f.SetAttributeInternal(id, v)
+	}
+}
+
+// parseConstraintSlots parses the raw slots of a SendConstraintSignal
+// request. Slots arrive as CBOR maps keyed by field name.
+func parseConstraintSlots(raw []any) ([]constraintSlot, *wire.FieldError) {
+	if len(raw) == 0 {
+		return nil, &wire.FieldError{Path: "slots", Message: "must not be empty", Constraint: wire.ConstraintRequired}
+	}
+	slots := make([]constraintSlot, 0, len(raw))
+	for i, item := range raw {
+		path := fmt.Sprintf("slots[%d]", i)
+		m := wire.ToStringMap(item)
+		if m == nil {
+			return nil, &wire.FieldError{Path: path, Message: "not a map", Constraint: wire.ConstraintType}
+		}
+		d, ok := wire.ToUint32(m["duration"])
+		if !ok || d == 0 {
+			return nil, &wire.FieldError{
+				Path:       path + ".duration",
+				Message:    "must be greater than zero",
+				Constraint: wire.ConstraintMin,
+				Limit:      int64(1),
+			}
+		}
+		slot := constraintSlot{duration: time.Duration(d) * time.Second}
+		for _, f := range []struct {
+			name string
+			dst  **int64
+		}{
+			{"consumptionMax", &slot.consumptionMax},
+			{"consumptionMin", &slot.consumptionMin},
+			{"productionMax", &slot.productionMax},
+			{"productionMin", &slot.productionMin},
+		} {
+			v, exists := m[f.name]
+			if !exists || v == nil {
+				continue
+			}
+			n, ok := wire.ToInt64(v)
+			if !ok || n < 0 {
+				return nil, &wire.FieldError{
+					Path:       path + "." + f.name,
+					Message:    "must be a non-negative integer",
+					Constraint: wire.ConstraintMin,
+					Limit:      int64(0),
+				}
+			}
+			*f.dst = &n
+		}
+		if slot.consumptionMin != nil && slot.consumptionMax != nil && *slot.consumptionMin > *slot.consumptionMax {
+			return nil, &wire.FieldError{
+				Path:       path + ".consumptionMin",
+				Message:    "exceeds consumptionMax",
+				Constraint: wire.ConstraintRelation,
+				Limit:      *slot.consumptionMax,
+			}
+		}
+		if slot.productionMin != nil && slot.productionMax != nil && *slot.productionMin > *slot.productionMax {
+			return nil, &wire.FieldError{
+				Path:       path + ".productionMin",
+				Message:    "exceeds productionMax",
+				Constraint: wire.ConstraintRelation,
+				Limit:      *slot.productionMax,
+			}
+		}
+		slots = append(slots, slot)
+	}
+	return slots, nil
+}
+
+// constraintSlotsData converts parsed slots to the constraintSlots attribute
+// representation. Absent bounds are omitted rather than stored as 0.
+func constraintSlotsData(slots []constraintSlot) []any {
+	data := make([]any, len(slots))
+	for i, s := range slots {
+		m := map[string]any{"duration": uint32(s.duration / time.Second)}
+		if s.consumptionMax != nil {
+			m["consumptionMax"] = *s.consumptionMax
+		}
+		if s.consumptionMin != nil {
+			m["consumptionMin"] = *s.consumptionMin
+		}
+		if s.productionMax != nil {
+			m["productionMax"] = *s.productionMax
+		}
+		if s.productionMin != nil {
+			m["productionMin"] = *s.productionMin
+		}
+		data[i] = m
+	}
+	return data
+}
+
+// signalSourceZoneType maps a signal source to the zone type used for
+// resolution when the sender's zone is unknown.
+func signalSourceZoneType(source SignalSource) cert.ZoneType {
+	if source == SignalSourceLocalEms {
+		return cert.ZoneTypeLocal
+	}
+	return cert.ZoneTypeGrid
+}
+
+// optionalUint64 converts a nullable uint64 to an attribute value.
+func optionalUint64(v *uint64) any {
+	if v == nil {
+		return nil
+	}
+	return *v
+}
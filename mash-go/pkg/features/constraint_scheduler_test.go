@@ -0,0 +1,376 @@
+package features
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mash-protocol/mash-go/pkg/cert"
+	"github.com/mash-protocol/mash-go/pkg/clock"
+	"github.com/mash-protocol/mash-go/pkg/model"
+	"github.com/mash-protocol/mash-go/pkg/wire"
+)
+
+var schedStart = time.Unix(1_700_000_000, 0)
+
+func newTestConstraintScheduler() (*ConstraintScheduler, *clock.Fake) {
+	lr := newTestResolverRegistered()
+	_ = lr.ec.SetControlState(ControlStateAutonomous)
+	cs := NewConstraintScheduler(NewSignals(), lr)
+	fc := clock.NewFake(schedStart)
+	cs.SetClock(fc)
+	cs.Register()
+	return cs, fc
+}
+
+func slot(duration uint32, bounds map[string]any) map[string]any {
+	m := map[string]any{"duration": duration}
+	for k, v := range bounds {
+		m[k] = v
+	}
+	return m
+}
+
+func uint64Ptr(v uint64) *uint64 { return &v }
+
+func assertEffectiveConsumption(t *testing.T, cs *ConstraintScheduler, want *int64) {
+	t.Helper()
+	got, ok := cs.lr.ec.EffectiveConsumptionLimit()
+	if want == nil {
+		if ok {
+			t.Fatalf("expected no effective consumption limit, got %d", got)
+		}
+		return
+	}
+	if !ok || got != *want {
+		t.Fatalf("expected effective consumption limit %d, got %d (ok=%v)", *want, got, ok)
+	}
+}
+
+func TestConstraintScheduler_SlotBoundaries(t *testing.T) {
+	cs, clock := newTestConstraintScheduler()
+
+	err := cs.HandleSendConstraintSignal(testCtx("zone-GRID", cert.ZoneTypeGrid), SendConstraintSignalRequest{
+		Source:    SignalSourceGrid,
+		StartTime: uint64(schedStart.Unix()),
+		Slots: []any{
+			slot(3600, map[string]any{"consumptionMax": int64(10000000)}),
+			slot(1800, map[string]any{"consumptionMax": int64(5000000)}),
+		},
+	})
+	if err != nil {
+		t.Fatalf("HandleSendConstraintSignal: %v", err)
+	}
+
+	assertEffectiveConsumption(t, cs, intPtr(10000000))
+	if cs.lr.ec.ControlState() != ControlStateControlled {
+		t.Fatalf("expected CONTROLLED, got %v", cs.lr.ec.ControlState())
+	}
+	if idx, ok := cs.ActiveSlotIndex(); !ok || idx != 0 {
+		t.Fatalf("expected slot 0 active, got %d (ok=%v)", idx, ok)
+	}
+
+	// Slot boundary: second slot becomes active.
+	clock.Set(schedStart.Add(time.Hour))
+	assertEffectiveConsumption(t, cs, intPtr(5000000))
+
+	// End of schedule without validUntil: the signal is dropped.
+	clock.Set(schedStart.Add(90 * time.Minute))
+	assertEffectiveConsumption(t, cs, nil)
+	if cs.lr.ec.ControlState() != ControlStateAutonomous {
+		t.Fatalf("expected AUTONOMOUS after schedule end, got %v", cs.lr.ec.ControlState())
+	}
+	if cs.signals.HasActiveConstraints() {
+		t.Fatal("expected constraintSlots cleared after schedule end")
+	}
+	if _, ok := cs.signals.SignalSource(); ok {
+		t.Fatal("expected signalSource cleared after schedule end")
+	}
+}
+
+func TestConstraintScheduler_ValidUntilExpiry(t *testing.T) {
+	cs, clock := newTestConstraintScheduler()
+
+	err := cs.HandleSendConstraintSignal(testCtx("zone-GRID", cert.ZoneTypeGrid), SendConstraintSignalRequest{
+		Source:     SignalSourceGrid,
+		StartTime:  uint64(schedStart.Unix()),
+		ValidUntil: uint64Ptr(uint64(schedStart.Add(30 * time.Minute).Unix())),
+		Slots:      []any{slot(3600, map[string]any{"consumptionMax": int64(4000000)})},
+	})
+	if err != nil {
+		t.Fatalf("HandleSendConstraintSignal: %v", err)
+	}
+	assertEffectiveConsumption(t, cs, intPtr(4000000))
+
+	clock.Set(schedStart.Add(30 * time.Minute))
+	assertEffectiveConsumption(t, cs, nil)
+	if cs.signals.HasActiveConstraints() {
+		t.Fatal("expected constraintSlots cleared at validUntil")
+	}
+	if _, ok := cs.signals.ValidUntil(); ok {
+		t.Fatal("expected validUntil cleared at expiry")
+	}
+}
+
+func TestConstraintScheduler_FutureStart(t *testing.T) {
+	cs, clock := newTestConstraintScheduler()
+
+	start := schedStart.Add(time.Hour)
+	err := cs.HandleSendConstraintSignal(testCtx("zone-GRID", cert.ZoneTypeGrid), SendConstraintSignalRequest{
+		Source:    SignalSourceGrid,
+		StartTime: uint64(start.Unix()),
+		Slots:     []any{slot(900, map[string]any{"consumptionMax": int64(3000000)})},
+	})
+	if err != nil {
+		t.Fatalf("HandleSendConstraintSignal: %v", err)
+	}
+
+	// Not yet started: signal stored, no contribution.
+	assertEffectiveConsumption(t, cs, nil)
+	if !cs.signals.HasActiveConstraints() {
+		t.Fatal("expected constraintSlots stored before start")
+	}
+
+	clock.Set(start)
+	assertEffectiveConsumption(t, cs, intPtr(3000000))
+}
+
+func TestConstraintScheduler_StartTimeZeroMeansNow(t *testing.T) {
+	cs, _ := newTestConstraintScheduler()
+
+	err := cs.HandleSendConstraintSignal(testCtx("zone-LOCAL", cert.ZoneTypeLocal), SendConstraintSignalRequest{
+		Source: SignalSourceLocalEms,
+		Slots:  []any{slot(3600, map[string]any{"consumptionMax": int64(10000000)})},
+	})
+	if err != nil {
+		t.Fatalf("HandleSendConstraintSignal: %v", err)
+	}
+	assertEffectiveConsumption(t, cs, intPtr(10000000))
+	if st, ok := cs.signals.StartTime(); !ok || st != uint64(schedStart.Unix()) {
+		t.Fatalf("expected startTime anchored to now (%d), got %d (ok=%v)", schedStart.Unix(), st, ok)
+	}
+}
+
+func TestConstraintScheduler_MostRestrictiveWithZoneLimits(t *testing.T) {
+	cs, clock := newTestConstraintScheduler()
+	ctxGrid := testCtx("zone-GRID", cert.ZoneTypeGrid)
+
+	// Zone sets an 8 kW limit directly.
+	_, _ = cs.lr.HandleSetLimit(ctxGrid, SetLimitRequest{ConsumptionLimit: intPtr(8000000)})
+
+	err := cs.HandleSendConstraintSignal(ctxGrid, SendConstraintSignalRequest{
+		Source:    SignalSourceGrid,
+		StartTime: uint64(schedStart.Unix()),
+		Slots: []any{
+			slot(3600, map[string]any{"consumptionMax": int64(10000000)}),
+			slot(3600, map[string]any{"consumptionMax": int64(5000000)}),
+		},
+	})
+	if err != nil {
+		t.Fatalf("HandleSendConstraintSignal: %v", err)
+	}
+
+	// 8 kW zone limit is more restrictive than the 10 kW slot.
+	assertEffectiveConsumption(t, cs, intPtr(8000000))
+
+	// 5 kW slot is more restrictive than the zone limit.
+	clock.Set(schedStart.Add(time.Hour))
+	assertEffectiveConsumption(t, cs, intPtr(5000000))
+
+	// The synthetic contribution is not the zone's own limit.
+	val := readAsZone(t, cs.lr.ec, "zone-GRID", cert.ZoneTypeGrid, EnergyControlAttrMyConsumptionLimit)
+	assertInt64(t, "GRID myConsumptionLimit", val, 8000000)
+
+	// Clearing the signal falls back to the zone limit.
+	cs.Clear()
+	assertEffectiveConsumption(t, cs, intPtr(8000000))
+	if cs.lr.ec.ControlState() != ControlStateControlled {
+		t.Fatalf("expected CONTROLLED with zone limit remaining, got %v", cs.lr.ec.ControlState())
+	}
+}
+
+func TestConstraintScheduler_AbsentBoundsAreNotZero(t *testing.T) {
+	cs, clock := newTestConstraintScheduler()
+
+	err := cs.HandleSendConstraintSignal(testCtx("zone-GRID", cert.ZoneTypeGrid), SendConstraintSignalRequest{
+		Source:    SignalSourceGrid,
+		StartTime: uint64(schedStart.Unix()),
+		Slots: []any{
+			slot(600, map[string]any{"productionMax": int64(2000000), "consumptionMax": nil}),
+			slot(600, map[string]any{"consumptionMax": int64(0)}),
+		},
+	})
+	if err != nil {
+		t.Fatalf("HandleSendConstraintSignal: %v", err)
+	}
+
+	// Only production is bounded in the first slot.
+	assertEffectiveConsumption(t, cs, nil)
+	if eff, ok := cs.lr.ec.EffectiveProductionLimit(); !ok || eff != 2000000 {
+		t.Fatalf("expected effective production limit 2000000, got %d (ok=%v)", eff, ok)
+	}
+
+	// An explicit 0 is a real bound.
+	clock.Set(schedStart.Add(10 * time.Minute))
+	assertEffectiveConsumption(t, cs, intPtr(0))
+	if _, ok := cs.lr.ec.EffectiveProductionLimit(); ok {
+		t.Fatal("expected production limit withdrawn in second slot")
+	}
+}
+
+func TestConstraintScheduler_CBORSlots(t *testing.T) {
+	cs, _ := newTestConstraintScheduler()
+
+	// Slots decoded from CBOR arrive as map[any]any with unsigned integers.
+	_, err := cs.signals.InvokeCommand(testCtx("zone-GRID", cert.ZoneTypeGrid), SignalsCmdSendConstraintSignal, map[string]any{
+		"source":    uint64(SignalSourceGrid),
+		"startTime": uint64(schedStart.Unix()),
+		"slots": []any{
+			map[any]any{"duration": uint64(3600), "consumptionMax": uint64(7000000)},
+		},
+	})
+	if err != nil {
+		t.Fatalf("InvokeCommand: %v", err)
+	}
+	assertEffectiveConsumption(t, cs, intPtr(7000000))
+}
+
+func TestConstraintScheduler_ReplaceWithdrawsPrevious(t *testing.T) {
+	cs, _ := newTestConstraintScheduler()
+	ctx := testCtx("zone-GRID", cert.ZoneTypeGrid)
+
+	_ = cs.HandleSendConstraintSignal(ctx, SendConstraintSignalRequest{
+		Source:    SignalSourceGrid,
+		StartTime: uint64(schedStart.Unix()),
+		Slots:     []any{slot(3600, map[string]any{"consumptionMax": int64(6000000)})},
+	})
+	assertEffectiveConsumption(t, cs, intPtr(6000000))
+
+	// New signal starting later replaces the active one.
+	_ = cs.HandleSendConstraintSignal(ctx, SendConstraintSignalRequest{
+		Source:    SignalSourceGrid,
+		StartTime: uint64(schedStart.Add(time.Hour).Unix()),
+		Slots:     []any{slot(3600, map[string]any{"consumptionMax": int64(9000000)})},
+	})
+	assertEffectiveConsumption(t, cs, nil)
+}
+
+func TestConstraintScheduler_ClearZone(t *testing.T) {
+	cs, _ := newTestConstraintScheduler()
+
+	_ = cs.HandleSendConstraintSignal(testCtx("zone-GRID", cert.ZoneTypeGrid), SendConstraintSignalRequest{
+		Source:    SignalSourceGrid,
+		StartTime: uint64(schedStart.Unix()),
+		Slots:     []any{slot(3600, map[string]any{"consumptionMax": int64(6000000)})},
+	})
+
+	// Another zone going away leaves the signal in place.
+	cs.ClearZone("zone-LOCAL")
+	assertEffectiveConsumption(t, cs, intPtr(6000000))
+
+	cs.ClearZone("zone-GRID")
+	assertEffectiveConsumption(t, cs, nil)
+	if cs.signals.HasActiveConstraints() {
+		t.Fatal("expected constraintSlots cleared when sender zone removed")
+	}
+}
+
+func TestConstraintScheduler_TestZoneExcluded(t *testing.T) {
+	cs, _ := newTestConstraintScheduler()
+
+	_ = cs.HandleSendConstraintSignal(testCtx("zone-TEST", cert.ZoneTypeTest), SendConstraintSignalRequest{
+		Source:    SignalSourceGrid,
+		StartTime: uint64(schedStart.Unix()),
+		Slots:     []any{slot(3600, map[string]any{"consumptionMax": int64(6000000)})},
+	})
+
+	// TEST zones are observers: the signal is stored but not enforced.
+	if !cs.signals.HasActiveConstraints() {
+		t.Fatal("expected constraintSlots stored")
+	}
+	assertEffectiveConsumption(t, cs, nil)
+}
+
+func TestConstraintScheduler_Validation(t *testing.T) {
+	ctx := testCtx("zone-GRID", cert.ZoneTypeGrid)
+	start := uint64(schedStart.Unix())
+
+	tests := []struct {
+		name string
+		req  SendConstraintSignalRequest
+	}{
+		{"no slots", SendConstraintSignalRequest{StartTime: start}},
+		{"slot not a map", SendConstraintSignalRequest{StartTime: start, Slots: []any{uint64(3600)}}},
+		{"zero duration", SendConstraintSignalRequest{StartTime: start, Slots: []any{slot(0, nil)}}},
+		{"negative bound", SendConstraintSignalRequest{StartTime: start, Slots: []any{
+			slot(60, map[string]any{"consumptionMax": int64(-1)}),
+		}}},
+		{"min above max", SendConstraintSignalRequest{StartTime: start, Slots: []any{
+			slot(60, map[string]any{"consumptionMin": int64(5000), "consumptionMax": int64(1000)}),
+		}}},
+		{"validUntil before start", SendConstraintSignalRequest{StartTime: start, ValidUntil: uint64Ptr(start), Slots: []any{
+			slot(60, nil),
+		}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cs, _ := newTestConstraintScheduler()
+			err := cs.HandleSendConstraintSignal(ctx, tt.req)
+			assertCommandStatus(t, err, wire.StatusInvalidParameter)
+			if cs.signals.HasActiveConstraints() {
+				t.Fatal("rejected signal must not be stored")
+			}
+		})
+	}
+}
+
+// attrRecorder records attribute change notifications.
+type attrRecorder struct {
+	mu      sync.Mutex
+	changes map[uint16][]any
+}
+
+func (r *attrRecorder) OnAttributeChanged(_ model.FeatureType, attrID uint16, value any) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.changes == nil {
+		r.changes = make(map[uint16][]any)
+	}
+	r.changes[attrID] = append(r.changes[attrID], value)
+}
+
+func TestConstraintScheduler_NotifiesAtBoundaries(t *testing.T) {
+	cs, clock := newTestConstraintScheduler()
+	ecRec := &attrRecorder{}
+	sigRec := &attrRecorder{}
+	cs.lr.ec.Subscribe(ecRec)
+	cs.signals.Subscribe(sigRec)
+
+	_ = cs.HandleSendConstraintSignal(testCtx("zone-GRID", cert.ZoneTypeGrid), SendConstraintSignalRequest{
+		Source:    SignalSourceGrid,
+		StartTime: uint64(schedStart.Unix()),
+		Slots: []any{
+			slot(3600, map[string]any{"consumptionMax": int64(10000000)}),
+			slot(3600, map[string]any{"consumptionMax": int64(5000000)}),
+		},
+	})
+	clock.Set(schedStart.Add(time.Hour))
+	clock.Set(schedStart.Add(2 * time.Hour))
+
+	got := ecRec.changes[EnergyControlAttrEffectiveConsumptionLimit]
+	want := []any{int64(10000000), int64(5000000), nil}
+	if len(got) != len(want) {
+		t.Fatalf("expected %d effectiveConsumptionLimit notifications, got %v", len(want), got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("notification %d: expected %v, got %v", i, want[i], got[i])
+		}
+	}
+
+	slots := sigRec.changes[SignalsAttrConstraintSlots]
+	if len(slots) != 2 || slots[1] != nil {
+		t.Fatalf("expected constraintSlots set then cleared, got %v", slots)
+	}
+}
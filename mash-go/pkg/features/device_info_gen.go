@@ -6,6 +6,7 @@ import (
 	"context"
 
 	"github.com/mash-protocol/mash-go/pkg/model"
+	"github.com/mash-protocol/mash-go/pkg/wire"
 )
 
 // DeviceInfo attribute IDs.
@@ -517,6 +518,8 @@ func (d *DeviceInfo) handleRemoveZone(ctx context.Context, params map[string]any
 	if raw, exists := params["zoneId"]; exists {
 		if v, ok := raw.(string); ok {
 			req.ZoneID = v
+		} else if raw != nil {
+			return nil, wire.NewFieldError(wire.StatusInvalidParameter, wire.FieldError{Path: "zoneId", Message: "expected string", Constraint: wire.ConstraintType})
 		}
 	}
 
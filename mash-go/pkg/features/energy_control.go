@@ -25,3 +25,35 @@ func (e *EnergyControl) IsFailsafe() bool {
 func (e *EnergyControl) IsOverride() bool {
 	return e.ControlState() == ControlStateOverride
 }
+
+// hasActiveControl reports whether any zone-driven effective limit or
+// setpoint (total power or per-phase current) is currently set. Resolvers use it to decide between CONTROLLED
+// and AUTONOMOUS so that clearing one kind of control does not discard
+// another that is still active.
+func (e *EnergyControl) hasActiveControl() bool {
+	if _, ok := e.EffectiveConsumptionLimit(); ok {
+		return true
+	}
+	if _, ok := e.EffectiveProductionLimit(); ok {
+		return true
+	}
+	if _, ok := e.EffectiveConsumptionSetpoint(); ok {
+		return true
+	}
+	if _, ok := e.EffectiveProductionSetpoint(); ok {
+		return true
+	}
+	if _, ok := e.EffectiveCurrentLimitsConsumption(); ok {
+		return true
+	}
+	if _, ok := e.EffectiveCurrentLimitsProduction(); ok {
+		return true
+	}
+	if _, ok := e.EffectiveCurrentSetpointsConsumption(); ok {
+		return true
+	}
+	if _, ok := e.EffectiveCurrentSetpointsProduction(); ok {
+		return true
+	}
+	return false
+}
@@ -2249,21 +2249,29 @@ func (e *EnergyControl) handleSetLimit(ctx context.Context, params map[string]an
 	if raw, exists := params["consumptionLimit"]; exists {
 		if v, ok := wire.ToInt64(raw); ok {
 			req.ConsumptionLimit = &v
+		} else if raw != nil {
+			return nil, wire.NewFieldError(wire.StatusInvalidParameter, wire.FieldError{Path: "consumptionLimit", Message: "expected int64", Constraint: wire.ConstraintType})
 		}
 	}
 	if raw, exists := params["productionLimit"]; exists {
 		if v, ok := wire.ToInt64(raw); ok {
 			req.ProductionLimit = &v
+		} else if raw != nil {
+			return nil, wire.NewFieldError(wire.StatusInvalidParameter, wire.FieldError{Path: "productionLimit", Message: "expected int64", Constraint: wire.ConstraintType})
 		}
 	}
 	if raw, exists := params["duration"]; exists {
 		if v, ok := wire.ToUint32(raw); ok {
 			req.Duration = &v
+		} else if raw != nil {
+			return nil, wire.NewFieldError(wire.StatusInvalidParameter, wire.FieldError{Path: "duration", Message: "expected uint32", Constraint: wire.ConstraintType})
 		}
 	}
 	if raw, exists := params["cause"]; exists {
 		if v, ok := wire.ToUint8Public(raw); ok {
 			req.Cause = LimitCause(v)
+		} else if raw != nil {
+			return nil, wire.NewFieldError(wire.StatusInvalidParameter, wire.FieldError{Path: "cause", Message: "expected uint8", Constraint: wire.ConstraintType})
 		}
 	}
 
@@ -2297,6 +2305,8 @@ func (e *EnergyControl) handleClearLimit(ctx context.Context, params map[string]
 		if v, ok := wire.ToUint8Public(raw); ok {
 			tmp := Direction(v)
 			req.Direction = &tmp
+		} else if raw != nil {
+			return nil, wire.NewFieldError(wire.StatusInvalidParameter, wire.FieldError{Path: "direction", Message: "expected uint8", Constraint: wire.ConstraintType})
 		}
 	}
 
@@ -2311,23 +2321,31 @@ func (e *EnergyControl) handleSetCurrentLimits(ctx context.Context, params map[s
 
 	req := SetCurrentLimitsRequest{}
 	if raw, exists := params["phases"]; exists {
-		if v, ok := raw.(map[string]any); ok {
+		if v := wire.ToStringMap(raw); v != nil {
 			req.Phases = v
+		} else if raw != nil {
+			return nil, wire.NewFieldError(wire.StatusInvalidParameter, wire.FieldError{Path: "phases", Message: "expected map", Constraint: wire.ConstraintType})
 		}
 	}
 	if raw, exists := params["direction"]; exists {
 		if v, ok := wire.ToUint8Public(raw); ok {
 			req.Direction = Direction(v)
+		} else if raw != nil {
+			return nil, wire.NewFieldError(wire.StatusInvalidParameter, wire.FieldError{Path: "direction", Message: "expected uint8", Constraint: wire.ConstraintType})
 		}
 	}
 	if raw, exists := params["duration"]; exists {
 		if v, ok := wire.ToUint32(raw); ok {
 			req.Duration = &v
+		} else if raw != nil {
+			return nil, wire.NewFieldError(wire.StatusInvalidParameter, wire.FieldError{Path: "duration", Message: "expected uint32", Constraint: wire.ConstraintType})
 		}
 	}
 	if raw, exists := params["cause"]; exists {
 		if v, ok := wire.ToUint8Public(raw); ok {
 			req.Cause = LimitCause(v)
+		} else if raw != nil {
+			return nil, wire.NewFieldError(wire.StatusInvalidParameter, wire.FieldError{Path: "cause", Message: "expected uint8", Constraint: wire.ConstraintType})
 		}
 	}
 
@@ -2345,6 +2363,8 @@ func (e *EnergyControl) handleClearCurrentLimits(ctx context.Context, params map
 		if v, ok := wire.ToUint8Public(raw); ok {
 			tmp := Direction(v)
 			req.Direction = &tmp
+		} else if raw != nil {
+			return nil, wire.NewFieldError(wire.StatusInvalidParameter, wire.FieldError{Path: "direction", Message: "expected uint8", Constraint: wire.ConstraintType})
 		}
 	}
 
@@ -2361,21 +2381,29 @@ func (e *EnergyControl) handleSetSetpoint(ctx context.Context, params map[string
 	if raw, exists := params["consumptionSetpoint"]; exists {
 		if v, ok := wire.ToInt64(raw); ok {
 			req.ConsumptionSetpoint = &v
+		} else if raw != nil {
+			return nil, wire.NewFieldError(wire.StatusInvalidParameter, wire.FieldError{Path: "consumptionSetpoint", Message: "expected int64", Constraint: wire.ConstraintType})
 		}
 	}
 	if raw, exists := params["productionSetpoint"]; exists {
 		if v, ok := wire.ToInt64(raw); ok {
 			req.ProductionSetpoint = &v
+		} else if raw != nil {
+			return nil, wire.NewFieldError(wire.StatusInvalidParameter, wire.FieldError{Path: "productionSetpoint", Message: "expected int64", Constraint: wire.ConstraintType})
 		}
 	}
 	if raw, exists := params["duration"]; exists {
 		if v, ok := wire.ToUint32(raw); ok {
 			req.Duration = &v
+		} else if raw != nil {
+			return nil, wire.NewFieldError(wire.StatusInvalidParameter, wire.FieldError{Path: "duration", Message: "expected uint32", Constraint: wire.ConstraintType})
 		}
 	}
 	if raw, exists := params["cause"]; exists {
 		if v, ok := wire.ToUint8Public(raw); ok {
 			req.Cause = SetpointCause(v)
+		} else if raw != nil {
+			return nil, wire.NewFieldError(wire.StatusInvalidParameter, wire.FieldError{Path: "cause", Message: "expected uint8", Constraint: wire.ConstraintType})
 		}
 	}
 
@@ -2393,6 +2421,8 @@ func (e *EnergyControl) handleClearSetpoint(ctx context.Context, params map[stri
 		if v, ok := wire.ToUint8Public(raw); ok {
 			tmp := Direction(v)
 			req.Direction = &tmp
+		} else if raw != nil {
+			return nil, wire.NewFieldError(wire.StatusInvalidParameter, wire.FieldError{Path: "direction", Message: "expected uint8", Constraint: wire.ConstraintType})
 		}
 	}
 
@@ -2407,23 +2437,31 @@ func (e *EnergyControl) handleSetCurrentSetpoints(ctx context.Context, params ma
 
 	req := SetCurrentSetpointsRequest{}
 	if raw, exists := params["phases"]; exists {
-		if v, ok := raw.(map[string]any); ok {
+		if v := wire.ToStringMap(raw); v != nil {
 			req.Phases = v
+		} else if raw != nil {
+			return nil, wire.NewFieldError(wire.StatusInvalidParameter, wire.FieldError{Path: "phases", Message: "expected map", Constraint: wire.ConstraintType})
 		}
 	}
 	if raw, exists := params["direction"]; exists {
 		if v, ok := wire.ToUint8Public(raw); ok {
 			req.Direction = Direction(v)
+		} else if raw != nil {
+			return nil, wire.NewFieldError(wire.StatusInvalidParameter, wire.FieldError{Path: "direction", Message: "expected uint8", Constraint: wire.ConstraintType})
 		}
 	}
 	if raw, exists := params["duration"]; exists {
 		if v, ok := wire.ToUint32(raw); ok {
 			req.Duration = &v
+		} else if raw != nil {
+			return nil, wire.NewFieldError(wire.StatusInvalidParameter, wire.FieldError{Path: "duration", Message: "expected uint32", Constraint: wire.ConstraintType})
 		}
 	}
 	if raw, exists := params["cause"]; exists {
 		if v, ok := wire.ToUint8Public(raw); ok {
 			req.Cause = SetpointCause(v)
+		} else if raw != nil {
+			return nil, wire.NewFieldError(wire.StatusInvalidParameter, wire.FieldError{Path: "cause", Message: "expected uint8", Constraint: wire.ConstraintType})
 		}
 	}
 
@@ -2441,6 +2479,8 @@ func (e *EnergyControl) handleClearCurrentSetpoints(ctx context.Context, params
 		if v, ok := wire.ToUint8Public(raw); ok {
 			tmp := Direction(v)
 			req.Direction = &tmp
+		} else if raw != nil {
+			return nil, wire.NewFieldError(wire.StatusInvalidParameter, wire.FieldError{Path: "direction", Message: "expected uint8", Constraint: wire.ConstraintType})
 		}
 	}
 
@@ -2457,6 +2497,8 @@ func (e *EnergyControl) handlePause(ctx context.Context, params map[string]any)
 	if raw, exists := params["duration"]; exists {
 		if v, ok := wire.ToUint32(raw); ok {
 			req.Duration = &v
+		} else if raw != nil {
+			return nil, wire.NewFieldError(wire.StatusInvalidParameter, wire.FieldError{Path: "duration", Message: "expected uint32", Constraint: wire.ConstraintType})
 		}
 	}
 
@@ -2,6 +2,7 @@ package features
 
 import (
 	"context"
+	"errors"
 	"testing"
 
 	"github.com/fxamacker/cbor/v2"
@@ -90,6 +91,22 @@ func TestDeviceInfo(t *testing.T) {
 		}
 	})
 
+	t.Run("SetterInvalidatesDataVersion", func(t *testing.T) {
+		version := di.DataVersion()
+		if err := di.SetLabel("Wallbox"); err != nil {
+			t.Fatalf("SetLabel failed: %v", err)
+		}
+
+		// A conditional write based on the version before SetLabel is stale
+		err := di.WriteAttributes(map[uint16]any{DeviceInfoAttrLabel: "Carport"}, &version)
+		if !errors.Is(err, model.ErrVersionMismatch) {
+			t.Fatalf("expected ErrVersionMismatch, got %v", err)
+		}
+		if lbl, _ := di.Label(); lbl != "Wallbox" {
+			t.Errorf("stale write must not apply, label = %s", lbl)
+		}
+	})
+
 	t.Run("SetEndpoints", func(t *testing.T) {
 		endpoints := []*model.EndpointInfo{
 			{ID: 0, Type: model.EndpointDeviceRoot, Features: []uint16{0x0006}},
@@ -2080,3 +2097,63 @@ func TestSetLimitRequestParsing(t *testing.T) {
 	})
 }
 
+// eventRecorder records events emitted by a feature.
+type eventRecorder struct {
+	ids  []uint8
+	data []map[string]any
+}
+
+func (r *eventRecorder) OnAttributeChanged(model.FeatureType, uint16, any) {}
+
+func (r *eventRecorder) OnEventEmitted(_ model.FeatureType, eventID uint8, data any) {
+	r.ids = append(r.ids, eventID)
+	r.data = append(r.data, data.(map[string]any))
+}
+
+func TestStatusEvents(t *testing.T) {
+	status := NewStatus()
+	rec := &eventRecorder{}
+	status.Subscribe(rec)
+
+	_ = status.SetFault(1001, "Overcurrent detected")
+	_ = status.SetFault(1001, "Overcurrent detected") // same fault: no new event
+	_ = status.SetFault(1002, "Overtemperature")      // new code: new event
+	_ = status.ClearFault()
+	_ = status.ClearFault() // nothing to clear: no event
+
+	want := []uint8{StatusEventFaultRaised, StatusEventFaultRaised, StatusEventFaultCleared}
+	if len(rec.ids) != len(want) {
+		t.Fatalf("expected events %v, got %v", want, rec.ids)
+	}
+	for i, id := range want {
+		if rec.ids[i] != id {
+			t.Errorf("event %d: expected %d, got %d", i, id, rec.ids[i])
+		}
+	}
+	if rec.data[1]["faultCode"] != uint32(1002) {
+		t.Errorf("expected faultCode 1002, got %v", rec.data[1]["faultCode"])
+	}
+	if rec.data[2]["faultCode"] != uint32(1002) {
+		t.Errorf("expected cleared faultCode 1002, got %v", rec.data[2]["faultCode"])
+	}
+}
+
+func TestChargingSessionEvents(t *testing.T) {
+	cs := NewChargingSession()
+	rec := &eventRecorder{}
+	cs.Subscribe(rec)
+
+	_ = cs.StartSession(1706180400)
+	_ = cs.SetSessionEnergyCharged(5_500_000)
+	_ = cs.EndSession(1706190000)
+
+	if len(rec.ids) != 2 || rec.ids[0] != ChargingSessionEventSessionStarted || rec.ids[1] != ChargingSessionEventSessionEnded {
+		t.Fatalf("expected sessionStarted, sessionEnded; got %v", rec.ids)
+	}
+	if rec.data[0]["startTime"] != uint64(1706180400) {
+		t.Errorf("expected startTime 1706180400, got %v", rec.data[0]["startTime"])
+	}
+	if rec.data[1]["energyCharged"] != uint64(5_500_000) {
+		t.Errorf("expected energyCharged 5500000, got %v", rec.data[1]["energyCharged"])
+	}
+}
@@ -7,6 +7,8 @@ import (
 	"sync"
 	"time"
 
+	"github.com/mash-protocol/mash-go/pkg/cert"
+	"github.com/mash-protocol/mash-go/pkg/clock"
 	"github.com/mash-protocol/mash-go/pkg/duration"
 	"github.com/mash-protocol/mash-go/pkg/wire"
 	"github.com/mash-protocol/mash-go/pkg/zone"
@@ -25,7 +27,9 @@ type LimitResolver struct {
 
 	consumptionLimits *zone.MultiZoneValue
 	productionLimits  *zone.MultiZoneValue
+	causes            map[causeKey]uint8
 
+	clock        clock.Clock
 	timers       *duration.Manager
 	zoneIndexMap map[string]uint8
 	indexZoneMap map[uint8]string
@@ -43,6 +47,14 @@ type LimitResolver struct {
 	// The callback receives the zone ID and a map of changed attribute IDs to values.
 	// Injected by the service layer to avoid import cycles.
 	OnZoneMyChange func(zoneID string, changes map[uint16]any)
+
+	// OnChange is called after a zone's limits are set, cleared or expire,
+	// once the resolver's lock is released. The service layer uses it to
+	// persist them.
+	OnChange func()
+
+	// changed is set under mu by changes that OnChange reports.
+	changed bool
 }
 
 // NewLimitResolver creates a new LimitResolver for the given EnergyControl feature.
@@ -51,6 +63,8 @@ func NewLimitResolver(ec *EnergyControl) *LimitResolver {
 		ec:                ec,
 		consumptionLimits: zone.NewMultiZoneValue(),
 		productionLimits:  zone.NewMultiZoneValue(),
+		causes:            make(map[causeKey]uint8),
+		clock:             clock.Real(),
 		timers:            duration.NewManager(),
 		zoneIndexMap:      make(map[string]uint8),
 		indexZoneMap:      make(map[uint8]string),
@@ -61,16 +75,35 @@ func NewLimitResolver(ec *EnergyControl) *LimitResolver {
 	return lr
 }
 
+// SetClock sets the time source for duration timers and value expiry. Must
+// be called before any limit is set.
+func (lr *LimitResolver) SetClock(c clock.Clock) {
+	lr.mu.Lock()
+	defer lr.mu.Unlock()
+
+	lr.clock = clock.OrReal(c)
+	lr.consumptionLimits = zone.NewMultiZoneValueWithClock(lr.clock)
+	lr.productionLimits = zone.NewMultiZoneValueWithClock(lr.clock)
+	lr.timers = duration.NewManagerWithClock(lr.clock)
+	lr.timers.OnExpiry(lr.handleTimerExpiry)
+}
+
 // Register wires the resolver's handlers into the EnergyControl feature.
+// Any read hook installed before Register is chained for attributes this
+// resolver does not own.
 func (lr *LimitResolver) Register() {
 	lr.ec.OnSetLimit(lr.HandleSetLimit)
 	lr.ec.OnClearLimit(lr.HandleClearLimit)
 
+	prev := lr.ec.ReadHook()
 	lr.ec.SetReadHook(func(ctx context.Context, attrID uint16) (any, bool) {
 		switch attrID {
 		case EnergyControlAttrMyConsumptionLimit, EnergyControlAttrMyProductionLimit:
 			// Intercept per-zone "my" attributes
 		default:
+			if prev != nil {
+				return prev(ctx, attrID)
+			}
 			return nil, false
 		}
 
@@ -100,7 +133,7 @@ func (lr *LimitResolver) Register() {
 // HandleSetLimit handles a SetLimit command from a zone.
 func (lr *LimitResolver) HandleSetLimit(ctx context.Context, req SetLimitRequest) (SetLimitResponse, error) {
 	lr.mu.Lock()
-	defer lr.mu.Unlock()
+	defer lr.unlock()
 
 	// Extract zone identity from context
 	zoneID := zonecontext.CallerZoneIDFromContext(ctx)
@@ -138,12 +171,14 @@ func (lr *LimitResolver) HandleSetLimit(ctx context.Context, req SetLimitRequest
 		return SetLimitResponse{}, &wire.CommandError{
 			Status:  wire.StatusConstraintError,
 			Message: fmt.Sprintf("consumptionLimit %d mW exceeds device maximum %d mW", *req.ConsumptionLimit, lr.MaxConsumption),
+			Fields:  []wire.FieldError{exceedsDeviceMaximum("consumptionLimit", lr.MaxConsumption)},
 		}
 	}
 	if req.ProductionLimit != nil && lr.MaxProduction > 0 && *req.ProductionLimit > lr.MaxProduction {
 		return SetLimitResponse{}, &wire.CommandError{
 			Status:  wire.StatusConstraintError,
 			Message: fmt.Sprintf("productionLimit %d mW exceeds device maximum %d mW", *req.ProductionLimit, lr.MaxProduction),
+			Fields:  []wire.FieldError{exceedsDeviceMaximum("productionLimit", lr.MaxProduction)},
 		}
 	}
 
@@ -160,6 +195,7 @@ func (lr *LimitResolver) HandleSetLimit(ctx context.Context, req SetLimitRequest
 	// Both nil = deactivate this zone's limits
 	if req.ConsumptionLimit == nil && req.ProductionLimit == nil {
 		lr.clearZoneLocked(zoneID)
+		lr.changed = true
 		lr.resolveAndApply()
 		if lr.OnZoneMyChange != nil {
 			lr.OnZoneMyChange(zoneID, map[uint16]any{
@@ -185,6 +221,7 @@ func (lr *LimitResolver) HandleSetLimit(ctx context.Context, req SetLimitRequest
 	// Store per-zone values
 	if req.ConsumptionLimit != nil {
 		lr.consumptionLimits.Set(zoneID, zoneType, *req.ConsumptionLimit, dur)
+		lr.causes[causeKey{zoneID, duration.CmdLimitConsumption}] = uint8(req.Cause)
 		if dur > 0 {
 			_ = lr.timers.SetTimer(zoneIdx, duration.CmdLimitConsumption, dur, *req.ConsumptionLimit)
 		} else {
@@ -193,6 +230,7 @@ func (lr *LimitResolver) HandleSetLimit(ctx context.Context, req SetLimitRequest
 	}
 	if req.ProductionLimit != nil {
 		lr.productionLimits.Set(zoneID, zoneType, *req.ProductionLimit, dur)
+		lr.causes[causeKey{zoneID, duration.CmdLimitProduction}] = uint8(req.Cause)
 		if dur > 0 {
 			_ = lr.timers.SetTimer(zoneIdx, duration.CmdLimitProduction, dur, *req.ProductionLimit)
 		} else {
@@ -200,6 +238,7 @@ func (lr *LimitResolver) HandleSetLimit(ctx context.Context, req SetLimitRequest
 		}
 	}
 
+	lr.changed = true
 	lr.resolveAndApply()
 
 	if lr.OnZoneMyChange != nil {
@@ -235,7 +274,7 @@ func (lr *LimitResolver) HandleSetLimit(ctx context.Context, req SetLimitRequest
 // HandleClearLimit handles a ClearLimit command from a zone.
 func (lr *LimitResolver) HandleClearLimit(ctx context.Context, req ClearLimitRequest) error {
 	lr.mu.Lock()
-	defer lr.mu.Unlock()
+	defer lr.unlock()
 
 	zoneID := zonecontext.CallerZoneIDFromContext(ctx)
 	if zoneID == "" {
@@ -264,6 +303,7 @@ func (lr *LimitResolver) HandleClearLimit(ctx context.Context, req ClearLimitReq
 		}
 	}
 
+	lr.changed = true
 	lr.resolveAndApply()
 
 	if lr.OnZoneMyChange != nil {
@@ -298,13 +338,15 @@ func (lr *LimitResolver) ResetAll() {
 	}
 
 	// Replace limit maps with fresh instances (clear all zone values).
-	lr.consumptionLimits = zone.NewMultiZoneValue()
-	lr.productionLimits = zone.NewMultiZoneValue()
+	lr.consumptionLimits = zone.NewMultiZoneValueWithClock(lr.clock)
+	lr.productionLimits = zone.NewMultiZoneValueWithClock(lr.clock)
+	lr.causes = make(map[causeKey]uint8)
+	lr.changed = true
 
 	// Re-resolve: sets effective limits to nil, state to AUTONOMOUS.
 	lr.resolveAndApply()
 
-	lr.mu.Unlock()
+	lr.unlock()
 
 	// Fire callbacks outside the lock to avoid deadlocks.
 	if lr.OnZoneMyChange != nil {
@@ -320,9 +362,10 @@ func (lr *LimitResolver) ResetAll() {
 // ClearZone removes all limits for a zone (e.g., on disconnect/failsafe).
 func (lr *LimitResolver) ClearZone(zoneID string) {
 	lr.mu.Lock()
-	defer lr.mu.Unlock()
+	defer lr.unlock()
 
 	lr.clearZoneLocked(zoneID)
+	lr.changed = true
 	lr.resolveAndApply()
 
 	if lr.OnZoneMyChange != nil {
@@ -333,10 +376,91 @@ func (lr *LimitResolver) ClearZone(zoneID string) {
 	}
 }
 
+// SetSignalContribution installs a limit contribution that does not come
+// from a SetLimit command, such as the active slot of a constraint signal.
+// sourceID identifies the contribution and must not collide with a zone ID;
+// a nil value withdraws that direction. Contributions take part in "most
+// restrictive wins" resolution but are not visible as any zone's my* limit.
+func (lr *LimitResolver) SetSignalContribution(sourceID string, zoneType cert.ZoneType, consumption, production *int64) {
+	lr.mu.Lock()
+	defer lr.mu.Unlock()
+
+	if consumption != nil {
+		lr.consumptionLimits.Set(sourceID, zoneType, *consumption, 0)
+	} else {
+		lr.consumptionLimits.Clear(sourceID)
+	}
+	if production != nil {
+		lr.productionLimits.Set(sourceID, zoneType, *production, 0)
+	} else {
+		lr.productionLimits.Clear(sourceID)
+	}
+
+	lr.resolveAndApply()
+}
+
+// Snapshot returns every zone's active limits with their cause and absolute
+// expiry, for persistence across restarts. Signal contributions are not
+// included.
+func (lr *LimitResolver) Snapshot() []ZoneValueSnapshot {
+	lr.mu.Lock()
+	defer lr.mu.Unlock()
+
+	var entries []ZoneValueSnapshot
+	entries = snapshotValues(entries, lr.consumptionLimits, DirectionConsumption, nil,
+		duration.CmdLimitConsumption, lr.zoneIndexMap, lr.timers, lr.causes)
+	entries = snapshotValues(entries, lr.productionLimits, DirectionProduction, nil,
+		duration.CmdLimitProduction, lr.zoneIndexMap, lr.timers, lr.causes)
+	sortSnapshots(entries)
+	return entries
+}
+
+// Restore re-applies limits captured by Snapshot and re-arms their duration
+// timers for the time each had left. Limits that expired in the meantime are
+// dropped. Intended for device startup, before any zone reconnects, so no
+// OnZoneMyChange callbacks are fired.
+func (lr *LimitResolver) Restore(entries []ZoneValueSnapshot) {
+	lr.mu.Lock()
+	defer lr.mu.Unlock()
+
+	now := lr.clock.Now()
+	for _, e := range entries {
+		var mzv *zone.MultiZoneValue
+		var cmdType duration.CommandType
+		switch e.Direction {
+		case DirectionConsumption:
+			mzv, cmdType = lr.consumptionLimits, duration.CmdLimitConsumption
+		case DirectionProduction:
+			mzv, cmdType = lr.productionLimits, duration.CmdLimitProduction
+		default:
+			continue
+		}
+
+		remaining, ok := remainingAt(e.ExpiresAt, now)
+		if !ok {
+			log.Printf("[LIMIT] Zone %s %s limit expired while offline", e.ZoneID, directionName(e.Direction))
+			continue
+		}
+
+		zoneIdx := lr.ensureZoneIndex(e.ZoneID)
+		mzv.Set(e.ZoneID, e.ZoneType, e.Value, remaining)
+		lr.causes[causeKey{e.ZoneID, cmdType}] = e.Cause
+		if remaining > 0 {
+			_ = lr.timers.SetTimer(zoneIdx, cmdType, remaining, e.Value)
+		} else {
+			_ = lr.timers.CancelTimer(zoneIdx, cmdType)
+		}
+	}
+
+	lr.resolveAndApply()
+}
+
 // clearZoneLocked clears a zone's limits and timers. Must be called with mu held.
 func (lr *LimitResolver) clearZoneLocked(zoneID string) {
 	lr.consumptionLimits.Clear(zoneID)
 	lr.productionLimits.Clear(zoneID)
+	delete(lr.causes, causeKey{zoneID, duration.CmdLimitConsumption})
+	delete(lr.causes, causeKey{zoneID, duration.CmdLimitProduction})
 
 	if zoneIdx, ok := lr.zoneIndexMap[zoneID]; ok {
 		lr.timers.CancelZoneTimers(zoneIdx)
@@ -346,7 +470,7 @@ func (lr *LimitResolver) clearZoneLocked(zoneID string) {
 // handleTimerExpiry is called by the duration manager when a timer expires.
 func (lr *LimitResolver) handleTimerExpiry(zoneIdx uint8, cmdType duration.CommandType, _ any) {
 	lr.mu.Lock()
-	defer lr.mu.Unlock()
+	defer lr.unlock()
 
 	zoneID, ok := lr.indexZoneMap[zoneIdx]
 	if !ok {
@@ -362,6 +486,7 @@ func (lr *LimitResolver) handleTimerExpiry(zoneIdx uint8, cmdType duration.Comma
 		log.Printf("[LIMIT] Zone %s production limit expired", zoneID)
 	}
 
+	lr.changed = true
 	lr.resolveAndApply()
 
 	if lr.OnZoneMyChange != nil {
@@ -381,7 +506,7 @@ func (lr *LimitResolver) handleTimerExpiry(zoneIdx uint8, cmdType duration.Comma
 //
 // State transitions:
 //   - CONTROLLED: A zone has sent a SetLimit/SetSetpoint command (controller has authority).
-//   - AUTONOMOUS: No zone has any active limits (no external control).
+//   - AUTONOMOUS: No zone has any active limits or setpoints (no external control).
 //
 // The device application layer is responsible for promoting CONTROLLED -> LIMITED
 // when it detects that its operation is actually being curtailed by the limit.
@@ -392,15 +517,27 @@ func (lr *LimitResolver) resolveAndApply() {
 	_ = lr.ec.SetEffectiveConsumptionLimitPtr(effConsumption)
 	_ = lr.ec.SetEffectiveProductionLimitPtr(effProduction)
 
-	// Update control state: CONTROLLED when any limits are active,
-	// AUTONOMOUS when all limits have been cleared.
-	if effConsumption != nil || effProduction != nil {
+	// Update control state: CONTROLLED when any limits (or setpoints from
+	// a SetpointResolver) are active, AUTONOMOUS when all have been cleared.
+	if lr.ec.hasActiveControl() {
 		_ = lr.ec.SetControlState(ControlStateControlled)
 	} else {
 		_ = lr.ec.SetControlState(ControlStateAutonomous)
 	}
 }
 
+// unlock releases mu and calls OnChange if limits changed meanwhile.
+func (lr *LimitResolver) unlock() {
+	changed := lr.changed
+	lr.changed = false
+	onChange := lr.OnChange
+	lr.mu.Unlock()
+
+	if changed && onChange != nil {
+		onChange()
+	}
+}
+
 // ensureZoneIndex returns a uint8 index for the zone, creating one if needed.
 // Must be called with mu held.
 func (lr *LimitResolver) ensureZoneIndex(zoneID string) uint8 {
@@ -413,3 +550,24 @@ func (lr *LimitResolver) ensureZoneIndex(zoneID string) uint8 {
 	lr.nextIndex++
 	return idx
 }
+
+// exceedsDeviceMaximum describes a command parameter above a device
+// capability, so controllers can clamp to the limit and retry.
+func exceedsDeviceMaximum(path string, limit int64) wire.FieldError {
+	return wire.FieldError{
+		Path:       path,
+		Message:    "exceeds device maximum",
+		Constraint: wire.ConstraintMax,
+		Limit:      limit,
+	}
+}
+
+// mustNotBeNegative describes a negative power or current parameter.
+func mustNotBeNegative(path string) wire.FieldError {
+	return wire.FieldError{
+		Path:       path,
+		Message:    "must not be negative",
+		Constraint: wire.ConstraintMin,
+		Limit:      int64(0),
+	}
+}
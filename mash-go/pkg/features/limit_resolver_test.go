@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/mash-protocol/mash-go/pkg/cert"
+	"github.com/mash-protocol/mash-go/pkg/clock"
 	"github.com/mash-protocol/mash-go/pkg/wire"
 	"github.com/mash-protocol/mash-go/pkg/zonecontext"
 )
@@ -700,6 +701,15 @@ func TestLimitResolver_ConstraintValidation(t *testing.T) {
 	if cmdErr.Status != wire.StatusConstraintError {
 		t.Fatalf("expected StatusConstraintError, got %v", cmdErr.Status)
 	}
+	want := wire.FieldError{
+		Path:       "consumptionLimit",
+		Message:    "exceeds device maximum",
+		Constraint: wire.ConstraintMax,
+		Limit:      int64(22_000_000),
+	}
+	if len(cmdErr.Fields) != 1 || cmdErr.Fields[0] != want {
+		t.Errorf("Fields = %+v, want [%+v]", cmdErr.Fields, want)
+	}
 
 	// Above max production: should return CommandError with ConstraintError.
 	_, err = lr.HandleSetLimit(ctx, SetLimitRequest{
@@ -727,3 +737,89 @@ func TestLimitResolver_ConstraintValidation(t *testing.T) {
 		t.Fatal("expected Applied=true when MaxConsumption=0")
 	}
 }
+
+func TestLimitResolver_SnapshotRestore(t *testing.T) {
+	fc := clock.NewFake(time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC))
+	lr := newTestResolver()
+	lr.SetClock(fc)
+
+	dur := uint32(3 * 3600)
+	_, _ = lr.HandleSetLimit(testCtx("zone-grid", cert.ZoneTypeGrid), SetLimitRequest{
+		ConsumptionLimit: intPtr(4000000),
+		Duration:         &dur,
+		Cause:            LimitCauseGridOptimization,
+	})
+	_, _ = lr.HandleSetLimit(testCtx("zone-local", cert.ZoneTypeLocal), SetLimitRequest{
+		ProductionLimit: intPtr(3000000),
+		Cause:           LimitCauseLocalProtection,
+	})
+	// Signal contributions are rebuilt by their source, not persisted.
+	lr.SetSignalContribution(ConstraintSignalSourceID, cert.ZoneTypeGrid, intPtr(1000000), nil)
+
+	snap := lr.Snapshot()
+	if len(snap) != 2 {
+		t.Fatalf("expected 2 entries, got %d: %+v", len(snap), snap)
+	}
+	grid, local := snap[0], snap[1]
+	if grid.ZoneID != "zone-grid" || grid.Direction != DirectionConsumption || grid.Value != 4000000 {
+		t.Errorf("unexpected grid entry %+v", grid)
+	}
+	if grid.ZoneType != cert.ZoneTypeGrid || grid.Cause != uint8(LimitCauseGridOptimization) {
+		t.Errorf("grid entry lost zone type or cause: %+v", grid)
+	}
+	if want := fc.Now().Add(3 * time.Hour); !grid.ExpiresAt.Equal(want) {
+		t.Errorf("grid ExpiresAt = %v, want %v", grid.ExpiresAt, want)
+	}
+	if local.ZoneID != "zone-local" || local.Direction != DirectionProduction || !local.ExpiresAt.IsZero() {
+		t.Errorf("unexpected local entry %+v", local)
+	}
+
+	// Restore into a fresh resolver after an hour offline.
+	fc.Advance(time.Hour)
+	lr2 := newTestResolver()
+	lr2.SetClock(fc)
+	lr2.Restore(snap)
+
+	if eff, ok := lr2.ec.EffectiveConsumptionLimit(); !ok || eff != 4000000 {
+		t.Fatalf("expected restored consumption limit 4000000, got %d (ok=%v)", eff, ok)
+	}
+	if eff, ok := lr2.ec.EffectiveProductionLimit(); !ok || eff != 3000000 {
+		t.Fatalf("expected restored production limit 3000000, got %d (ok=%v)", eff, ok)
+	}
+	if lr2.ec.ControlState() != ControlStateControlled {
+		t.Errorf("expected CONTROLLED after restore, got %s", lr2.ec.ControlState())
+	}
+	if got := lr2.Snapshot()[0].ExpiresAt; !got.Equal(grid.ExpiresAt) {
+		t.Errorf("restored ExpiresAt = %v, want %v", got, grid.ExpiresAt)
+	}
+
+	// The re-armed timer expires at the original deadline.
+	fc.Advance(2 * time.Hour)
+	if _, ok := lr2.ec.EffectiveConsumptionLimit(); ok {
+		t.Error("expected consumption limit to expire at the original deadline")
+	}
+	if _, ok := lr2.ec.EffectiveProductionLimit(); !ok {
+		t.Error("indefinite production limit should remain")
+	}
+}
+
+func TestLimitResolver_RestoreDropsExpired(t *testing.T) {
+	fc := clock.NewFake(time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC))
+	lr := newTestResolver()
+	lr.SetClock(fc)
+
+	lr.Restore([]ZoneValueSnapshot{{
+		ZoneID:    "zone-grid",
+		ZoneType:  cert.ZoneTypeGrid,
+		Direction: DirectionConsumption,
+		Value:     4000000,
+		ExpiresAt: fc.Now().Add(-time.Minute),
+	}})
+
+	if _, ok := lr.ec.EffectiveConsumptionLimit(); ok {
+		t.Error("expired limit should not be restored")
+	}
+	if len(lr.Snapshot()) != 0 {
+		t.Error("expected empty snapshot")
+	}
+}
@@ -0,0 +1,771 @@
+package features
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/mash-protocol/mash-go/pkg/clock"
+	"github.com/mash-protocol/mash-go/pkg/duration"
+	"github.com/mash-protocol/mash-go/pkg/wire"
+	"github.com/mash-protocol/mash-go/pkg/zone"
+	"github.com/mash-protocol/mash-go/pkg/zonecontext"
+)
+
+// maxPhases is the number of device phases (A, B, C).
+const maxPhases = 3
+
+// phaseCurrentValues holds per-zone values for one command family (current
+// limits or current setpoints), indexed by direction and device phase.
+type phaseCurrentValues struct {
+	consumption [maxPhases]*zone.MultiZoneValue
+	production  [maxPhases]*zone.MultiZoneValue
+}
+
+func newPhaseCurrentValues(c clock.Clock) *phaseCurrentValues {
+	v := &phaseCurrentValues{}
+	for p := range maxPhases {
+		v.consumption[p] = zone.NewMultiZoneValueWithClock(c)
+		v.production[p] = zone.NewMultiZoneValueWithClock(c)
+	}
+	return v
+}
+
+// forDirection returns the per-phase values for a single direction.
+func (v *phaseCurrentValues) forDirection(dir Direction) *[maxPhases]*zone.MultiZoneValue {
+	if dir == DirectionProduction {
+		return &v.production
+	}
+	return &v.consumption
+}
+
+// zoneMap returns the zone's own per-phase values, or nil if it has none.
+func (v *phaseCurrentValues) zoneMap(dir Direction, zoneID string) map[Phase]int64 {
+	var result map[Phase]int64
+	for p, mzv := range v.forDirection(dir) {
+		if zv := mzv.Get(zoneID); zv != nil {
+			if result == nil {
+				result = make(map[Phase]int64)
+			}
+			result[Phase(p)] = zv.Value
+		}
+	}
+	return result
+}
+
+// resolve computes the effective per-phase map for a direction. Limits use
+// "most restrictive wins", setpoints "highest priority wins". Returns nil if
+// no phase has a value.
+func (v *phaseCurrentValues) resolve(dir Direction, setpoints bool) *map[Phase]int64 {
+	var result map[Phase]int64
+	for p, mzv := range v.forDirection(dir) {
+		var eff *int64
+		if setpoints {
+			eff, _ = mzv.ResolveSetpoints()
+		} else {
+			eff, _ = mzv.ResolveLimits()
+		}
+		if eff != nil {
+			if result == nil {
+				result = make(map[Phase]int64)
+			}
+			result[Phase(p)] = *eff
+		}
+	}
+	if result == nil {
+		return nil
+	}
+	return &result
+}
+
+// PhaseCurrentResolver tracks per-zone, per-phase current limits and current
+// setpoints and resolves them into the EnergyControl effective per-phase
+// attributes. Limits use "most restrictive wins" per phase; setpoints use
+// "highest priority wins" per phase.
+//
+// Requests are validated against the Electrical feature (if provided):
+// phases must exist on the device (PhaseCount, PhaseMapping), values must not
+// exceed MaxCurrentPerPhase, and asymmetric values are only accepted in the
+// directions covered by SupportsAsymmetric.
+//
+// Duration timers run per zone, per phase and per direction, so a later
+// command touching only one phase does not reset the other phases' timers.
+//
+// Zone identity is extracted from context via pkg/zonecontext.
+type PhaseCurrentResolver struct {
+	mu sync.Mutex
+
+	ec *EnergyControl
+	el *Electrical
+
+	limits    *phaseCurrentValues
+	setpoints *phaseCurrentValues
+	causes    map[causeKey]uint8
+
+	clock        clock.Clock
+	timers       [maxPhases]*duration.Manager
+	zoneIndexMap map[string]uint8
+	indexZoneMap map[uint8]string
+	nextIndex    uint8
+
+	// OnZoneMyChange is called when a zone's "my" attribute values change.
+	// The callback receives the zone ID and a map of changed attribute IDs to values.
+	// Injected by the service layer to avoid import cycles.
+	OnZoneMyChange func(zoneID string, changes map[uint16]any)
+
+	// OnChange is called after a zone's per-phase currents are set, cleared
+	// or expire, once the resolver's lock is released. The service layer
+	// uses it to persist them.
+	OnChange func()
+
+	// changed is set under mu by changes that OnChange reports.
+	changed bool
+}
+
+// NewPhaseCurrentResolver creates a new PhaseCurrentResolver for the given
+// EnergyControl feature. el may be nil, in which case no electrical
+// constraints are enforced.
+func NewPhaseCurrentResolver(ec *EnergyControl, el *Electrical) *PhaseCurrentResolver {
+	r := &PhaseCurrentResolver{
+		ec:           ec,
+		el:           el,
+		limits:       newPhaseCurrentValues(nil),
+		setpoints:    newPhaseCurrentValues(nil),
+		causes:       make(map[causeKey]uint8),
+		zoneIndexMap: make(map[string]uint8),
+		indexZoneMap: make(map[uint8]string),
+	}
+	r.initTimers(clock.Real())
+
+	return r
+}
+
+// SetClock sets the time source for duration timers and value expiry. Must
+// be called before any current limit or setpoint is set.
+func (r *PhaseCurrentResolver) SetClock(c clock.Clock) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.initTimers(c)
+	r.limits = newPhaseCurrentValues(r.clock)
+	r.setpoints = newPhaseCurrentValues(r.clock)
+}
+
+// initTimers creates one duration manager per phase on the given clock.
+func (r *PhaseCurrentResolver) initTimers(c clock.Clock) {
+	r.clock = clock.OrReal(c)
+	for p := range maxPhases {
+		phase := Phase(p)
+		r.timers[p] = duration.NewManagerWithClock(r.clock)
+		r.timers[p].OnExpiry(func(zoneIdx uint8, cmdType duration.CommandType, _ any) {
+			r.handleTimerExpiry(phase, zoneIdx, cmdType)
+		})
+	}
+}
+
+// Register wires the resolver's handlers into the EnergyControl feature.
+// Any read hook installed before Register is chained for attributes this
+// resolver does not own.
+func (r *PhaseCurrentResolver) Register() {
+	r.ec.OnSetCurrentLimits(r.HandleSetCurrentLimits)
+	r.ec.OnClearCurrentLimits(r.HandleClearCurrentLimits)
+	r.ec.OnSetCurrentSetpoints(r.HandleSetCurrentSetpoints)
+	r.ec.OnClearCurrentSetpoints(r.HandleClearCurrentSetpoints)
+
+	prev := r.ec.ReadHook()
+	r.ec.SetReadHook(func(ctx context.Context, attrID uint16) (any, bool) {
+		var setpoints bool
+		var dir Direction
+		switch attrID {
+		case EnergyControlAttrMyCurrentLimitsConsumption:
+			setpoints, dir = false, DirectionConsumption
+		case EnergyControlAttrMyCurrentLimitsProduction:
+			setpoints, dir = false, DirectionProduction
+		case EnergyControlAttrMyCurrentSetpointsConsumption:
+			setpoints, dir = true, DirectionConsumption
+		case EnergyControlAttrMyCurrentSetpointsProduction:
+			setpoints, dir = true, DirectionProduction
+		default:
+			if prev != nil {
+				return prev(ctx, attrID)
+			}
+			return nil, false
+		}
+
+		zoneID := zonecontext.CallerZoneIDFromContext(ctx)
+		if zoneID == "" {
+			return nil, true
+		}
+
+		r.mu.Lock()
+		defer r.mu.Unlock()
+
+		m := r.family(setpoints).zoneMap(dir, zoneID)
+		if m == nil {
+			return nil, true
+		}
+		return m, true
+	})
+}
+
+// HandleSetCurrentLimits handles a SetCurrentLimits command from a zone.
+func (r *PhaseCurrentResolver) HandleSetCurrentLimits(ctx context.Context, req SetCurrentLimitsRequest) error {
+	return r.handleSet(ctx, false, req.Phases, req.Direction, req.Duration, uint8(req.Cause))
+}
+
+// HandleClearCurrentLimits handles a ClearCurrentLimits command from a zone.
+func (r *PhaseCurrentResolver) HandleClearCurrentLimits(ctx context.Context, req ClearCurrentLimitsRequest) error {
+	return r.handleClear(ctx, false, req.Direction)
+}
+
+// HandleSetCurrentSetpoints handles a SetCurrentSetpoints command from a zone.
+func (r *PhaseCurrentResolver) HandleSetCurrentSetpoints(ctx context.Context, req SetCurrentSetpointsRequest) error {
+	return r.handleSet(ctx, true, req.Phases, req.Direction, req.Duration, uint8(req.Cause))
+}
+
+// HandleClearCurrentSetpoints handles a ClearCurrentSetpoints command from a zone.
+func (r *PhaseCurrentResolver) HandleClearCurrentSetpoints(ctx context.Context, req ClearCurrentSetpointsRequest) error {
+	return r.handleClear(ctx, true, req.Direction)
+}
+
+func (r *PhaseCurrentResolver) handleSet(ctx context.Context, setpoints bool, phases map[string]any, dir Direction, durSecs *uint32, cause uint8) error {
+	r.mu.Lock()
+	defer r.unlock()
+
+	zoneID := zonecontext.CallerZoneIDFromContext(ctx)
+	if zoneID == "" {
+		return &wire.CommandError{
+			Status:  wire.StatusInvalidParameter,
+			Message: "per-phase current command requires a zone identity",
+		}
+	}
+	zoneType := zonecontext.CallerZoneTypeFromContext(ctx)
+
+	directions := phaseDirections(&dir)
+	if directions == nil {
+		return &wire.CommandError{
+			Status:  wire.StatusInvalidParameter,
+			Message: fmt.Sprintf("invalid direction %d", dir),
+		}
+	}
+
+	values, err := parsePhaseCurrents(phases)
+	if err != nil {
+		return &wire.CommandError{Status: wire.StatusInvalidParameter, Message: err.Error()}
+	}
+
+	// Validate against device electrical constraints (fail-fast, before state changes).
+	if err := r.validate(values, directions); err != nil {
+		return err
+	}
+
+	if r.ec.IsOverride() {
+		return &wire.CommandError{
+			Status:  wire.StatusBusy,
+			Message: "device is in OVERRIDE state",
+		}
+	}
+
+	zoneIdx := r.ensureZoneIndex(zoneID)
+
+	var dur time.Duration
+	if durSecs != nil && *durSecs > 0 {
+		dur = time.Duration(*durSecs) * time.Second
+	}
+
+	family := r.family(setpoints)
+	for _, d := range directions {
+		cmdType := phaseCurrentCmdType(setpoints, d)
+		perPhase := family.forDirection(d)
+		r.causes[causeKey{zoneID, cmdType}] = cause
+		for phase, v := range values {
+			if v == nil {
+				// A null phase value removes this zone's contribution for that phase.
+				perPhase[phase].Clear(zoneID)
+				_ = r.timers[phase].CancelTimer(zoneIdx, cmdType)
+				continue
+			}
+			perPhase[phase].Set(zoneID, zoneType, *v, dur)
+			if dur > 0 {
+				_ = r.timers[phase].SetTimer(zoneIdx, cmdType, dur, *v)
+			} else {
+				_ = r.timers[phase].CancelTimer(zoneIdx, cmdType)
+			}
+		}
+	}
+
+	r.changed = true
+	r.resolveAndApply()
+	r.notifyMyChange(zoneID, setpoints, directions)
+
+	if dur > 0 {
+		log.Printf("[PHASE] Zone %s set %s with duration: %ds", zoneID, phaseCurrentFamilyName(setpoints), *durSecs)
+	}
+
+	return nil
+}
+
+func (r *PhaseCurrentResolver) handleClear(ctx context.Context, setpoints bool, dir *Direction) error {
+	r.mu.Lock()
+	defer r.unlock()
+
+	zoneID := zonecontext.CallerZoneIDFromContext(ctx)
+	if zoneID == "" {
+		return nil
+	}
+
+	directions := phaseDirections(dir)
+	if directions == nil {
+		return &wire.CommandError{
+			Status:  wire.StatusInvalidParameter,
+			Message: fmt.Sprintf("invalid direction %d", *dir),
+		}
+	}
+
+	zoneIdx, hasIdx := r.zoneIndexMap[zoneID]
+	family := r.family(setpoints)
+	for _, d := range directions {
+		cmdType := phaseCurrentCmdType(setpoints, d)
+		for p, mzv := range family.forDirection(d) {
+			mzv.Clear(zoneID)
+			if hasIdx {
+				_ = r.timers[p].CancelTimer(zoneIdx, cmdType)
+			}
+		}
+	}
+
+	r.changed = true
+	r.resolveAndApply()
+	r.notifyMyChange(zoneID, setpoints, directions)
+
+	return nil
+}
+
+// ResetAll clears all per-phase limits and setpoints, cancels all timers, and
+// re-resolves the EnergyControl feature. Used by TriggerResetTestState.
+func (r *PhaseCurrentResolver) ResetAll() {
+	r.mu.Lock()
+
+	for _, zoneIdx := range r.zoneIndexMap {
+		for _, m := range r.timers {
+			m.CancelZoneTimers(zoneIdx)
+		}
+	}
+
+	var zones []string
+	for zoneID := range r.zoneIndexMap {
+		zones = append(zones, zoneID)
+	}
+
+	r.limits = newPhaseCurrentValues(r.clock)
+	r.setpoints = newPhaseCurrentValues(r.clock)
+	r.causes = make(map[causeKey]uint8)
+
+	r.changed = true
+	r.resolveAndApply()
+
+	r.unlock()
+
+	// Fire callbacks outside the lock to avoid deadlocks.
+	if r.OnZoneMyChange != nil {
+		for _, zoneID := range zones {
+			r.OnZoneMyChange(zoneID, clearedPhaseCurrentChanges())
+		}
+	}
+}
+
+// ClearZone removes all per-phase limits and setpoints for a zone
+// (e.g., on disconnect/failsafe).
+func (r *PhaseCurrentResolver) ClearZone(zoneID string) {
+	r.mu.Lock()
+	defer r.unlock()
+
+	for _, family := range []*phaseCurrentValues{r.limits, r.setpoints} {
+		for p := range maxPhases {
+			family.consumption[p].Clear(zoneID)
+			family.production[p].Clear(zoneID)
+		}
+	}
+	for key := range r.causes {
+		if key.zoneID == zoneID {
+			delete(r.causes, key)
+		}
+	}
+	if zoneIdx, ok := r.zoneIndexMap[zoneID]; ok {
+		for _, m := range r.timers {
+			m.CancelZoneTimers(zoneIdx)
+		}
+	}
+
+	r.changed = true
+	r.resolveAndApply()
+
+	if r.OnZoneMyChange != nil {
+		r.OnZoneMyChange(zoneID, clearedPhaseCurrentChanges())
+	}
+}
+
+// Snapshot returns every zone's active per-phase current limits and setpoints
+// with their cause and absolute expiry, for persistence across restarts.
+func (r *PhaseCurrentResolver) Snapshot() PhaseCurrentSnapshot {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return PhaseCurrentSnapshot{
+		Limits:    r.snapshotFamily(false),
+		Setpoints: r.snapshotFamily(true),
+	}
+}
+
+// snapshotFamily captures one command family. Must be called with mu held.
+func (r *PhaseCurrentResolver) snapshotFamily(setpoints bool) []ZoneValueSnapshot {
+	var entries []ZoneValueSnapshot
+	family := r.family(setpoints)
+	for _, dir := range []Direction{DirectionConsumption, DirectionProduction} {
+		cmdType := phaseCurrentCmdType(setpoints, dir)
+		for p, mzv := range family.forDirection(dir) {
+			phase := Phase(p)
+			entries = snapshotValues(entries, mzv, dir, &phase, cmdType, r.zoneIndexMap, r.timers[p], r.causes)
+		}
+	}
+	sortSnapshots(entries)
+	return entries
+}
+
+// Restore re-applies values captured by Snapshot and re-arms their duration
+// timers for the time each had left. Values that expired in the meantime are
+// dropped. No OnZoneMyChange callbacks are fired.
+func (r *PhaseCurrentResolver) Restore(snap PhaseCurrentSnapshot) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.restoreFamily(false, snap.Limits)
+	r.restoreFamily(true, snap.Setpoints)
+	r.resolveAndApply()
+}
+
+// restoreFamily restores one command family. Must be called with mu held.
+func (r *PhaseCurrentResolver) restoreFamily(setpoints bool, entries []ZoneValueSnapshot) {
+	now := r.clock.Now()
+	family := r.family(setpoints)
+	for _, e := range entries {
+		if e.Phase == nil || int(*e.Phase) >= maxPhases {
+			continue
+		}
+		if e.Direction != DirectionConsumption && e.Direction != DirectionProduction {
+			continue
+		}
+		phase := *e.Phase
+
+		remaining, ok := remainingAt(e.ExpiresAt, now)
+		if !ok {
+			log.Printf("[PHASE] Zone %s %s phase %s expired while offline", e.ZoneID, phaseCurrentFamilyName(setpoints), phase)
+			continue
+		}
+
+		zoneIdx := r.ensureZoneIndex(e.ZoneID)
+		cmdType := phaseCurrentCmdType(setpoints, e.Direction)
+		family.forDirection(e.Direction)[phase].Set(e.ZoneID, e.ZoneType, e.Value, remaining)
+		r.causes[causeKey{e.ZoneID, cmdType}] = e.Cause
+		if remaining > 0 {
+			_ = r.timers[phase].SetTimer(zoneIdx, cmdType, remaining, e.Value)
+		} else {
+			_ = r.timers[phase].CancelTimer(zoneIdx, cmdType)
+		}
+	}
+}
+
+// handleTimerExpiry is called by a phase's duration manager when a timer expires.
+func (r *PhaseCurrentResolver) handleTimerExpiry(phase Phase, zoneIdx uint8, cmdType duration.CommandType) {
+	r.mu.Lock()
+	defer r.unlock()
+
+	zoneID, ok := r.indexZoneMap[zoneIdx]
+	if !ok {
+		return
+	}
+
+	var setpoints bool
+	var dir Direction
+	switch cmdType {
+	case duration.CmdCurrentLimitConsumption:
+		setpoints, dir = false, DirectionConsumption
+	case duration.CmdCurrentLimitProduction:
+		setpoints, dir = false, DirectionProduction
+	case duration.CmdCurrentSetpointConsumption:
+		setpoints, dir = true, DirectionConsumption
+	case duration.CmdCurrentSetpointProduction:
+		setpoints, dir = true, DirectionProduction
+	default:
+		return
+	}
+
+	r.family(setpoints).forDirection(dir)[phase].Clear(zoneID)
+	log.Printf("[PHASE] Zone %s %s phase %s expired", zoneID, cmdType, phase)
+
+	r.changed = true
+	r.resolveAndApply()
+	r.notifyMyChange(zoneID, setpoints, []Direction{dir})
+}
+
+// resolveAndApply computes effective per-phase values and updates the
+// EnergyControl feature. Must be called with mu held.
+func (r *PhaseCurrentResolver) resolveAndApply() {
+	_ = r.ec.SetEffectiveCurrentLimitsConsumptionPtr(r.limits.resolve(DirectionConsumption, false))
+	_ = r.ec.SetEffectiveCurrentLimitsProductionPtr(r.limits.resolve(DirectionProduction, false))
+	_ = r.ec.SetEffectiveCurrentSetpointsConsumptionPtr(r.setpoints.resolve(DirectionConsumption, true))
+	_ = r.ec.SetEffectiveCurrentSetpointsProductionPtr(r.setpoints.resolve(DirectionProduction, true))
+
+	if r.ec.hasActiveControl() {
+		_ = r.ec.SetControlState(ControlStateControlled)
+	} else {
+		_ = r.ec.SetControlState(ControlStateAutonomous)
+	}
+}
+
+// unlock releases mu and calls OnChange if per-phase currents changed meanwhile.
+func (r *PhaseCurrentResolver) unlock() {
+	changed := r.changed
+	r.changed = false
+	onChange := r.OnChange
+	r.mu.Unlock()
+
+	if changed && onChange != nil {
+		onChange()
+	}
+}
+
+// validate checks parsed phase values against the Electrical feature.
+// Must be called with mu held.
+func (r *PhaseCurrentResolver) validate(values map[Phase]*int64, directions []Direction) error {
+	for phase, v := range values {
+		if v != nil && *v < 0 {
+			return &wire.CommandError{
+				Status:  wire.StatusInvalidParameter,
+				Message: fmt.Sprintf("phase %s current %d mA must not be negative", phase, *v),
+				Fields:  []wire.FieldError{mustNotBeNegative(phaseFieldPath(phase))},
+			}
+		}
+	}
+
+	if r.el == nil {
+		return nil
+	}
+
+	phaseCount := r.el.PhaseCount()
+	mapping, hasMapping := r.el.PhaseMapping()
+	maxCurrent := r.el.MaxCurrentPerPhase()
+
+	for phase, v := range values {
+		if uint8(phase) >= phaseCount {
+			return &wire.CommandError{
+				Status:  wire.StatusConstraintError,
+				Message: fmt.Sprintf("phase %s not present on %d-phase device", phase, phaseCount),
+				Fields: []wire.FieldError{{
+					Path:       phaseFieldPath(phase),
+					Message:    "phase not present",
+					Constraint: wire.ConstraintEnum,
+				}},
+			}
+		}
+		if hasMapping && len(mapping) > 0 {
+			if _, ok := mapping[phase]; !ok {
+				return &wire.CommandError{
+					Status:  wire.StatusConstraintError,
+					Message: fmt.Sprintf("phase %s is not connected", phase),
+				}
+			}
+		}
+		if v != nil && maxCurrent > 0 && *v > maxCurrent {
+			return &wire.CommandError{
+				Status:  wire.StatusConstraintError,
+				Message: fmt.Sprintf("phase %s current %d mA exceeds device maximum %d mA", phase, *v, maxCurrent),
+				Fields:  []wire.FieldError{exceedsDeviceMaximum(phaseFieldPath(phase), maxCurrent)},
+			}
+		}
+	}
+
+	for _, d := range directions {
+		if r.asymmetricAllowed(d) {
+			continue
+		}
+		var first *int64
+		for _, v := range values {
+			if v == nil {
+				continue
+			}
+			if first == nil {
+				first = v
+			} else if *v != *first {
+				return &wire.CommandError{
+					Status:  wire.StatusConstraintError,
+					Message: fmt.Sprintf("device does not support asymmetric %s currents", directionName(d)),
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// phaseFieldPath returns the error path of a phase in the phases parameter.
+func phaseFieldPath(phase Phase) string {
+	return fmt.Sprintf("phases[%s]", phase)
+}
+
+// asymmetricAllowed reports whether the device accepts different per-phase
+// values in the given direction.
+func (r *PhaseCurrentResolver) asymmetricAllowed(dir Direction) bool {
+	switch r.el.SupportsAsymmetric() {
+	case AsymmetricSupportBidirectional:
+		return true
+	case AsymmetricSupportConsumption:
+		return dir == DirectionConsumption
+	case AsymmetricSupportProduction:
+		return dir == DirectionProduction
+	default:
+		return false
+	}
+}
+
+// notifyMyChange reports the zone's current per-phase "my" values for the
+// given family and directions. Must be called with mu held.
+func (r *PhaseCurrentResolver) notifyMyChange(zoneID string, setpoints bool, directions []Direction) {
+	if r.OnZoneMyChange == nil {
+		return
+	}
+	family := r.family(setpoints)
+	changes := make(map[uint16]any, len(directions))
+	for _, d := range directions {
+		var val any
+		if m := family.zoneMap(d, zoneID); m != nil {
+			val = m
+		}
+		changes[myPhaseCurrentAttr(setpoints, d)] = val
+	}
+	r.OnZoneMyChange(zoneID, changes)
+}
+
+// family returns the value set for limits or setpoints. Must be called with mu held.
+func (r *PhaseCurrentResolver) family(setpoints bool) *phaseCurrentValues {
+	if setpoints {
+		return r.setpoints
+	}
+	return r.limits
+}
+
+// ensureZoneIndex returns a uint8 index for the zone, creating one if needed.
+// Must be called with mu held.
+func (r *PhaseCurrentResolver) ensureZoneIndex(zoneID string) uint8 {
+	if idx, ok := r.zoneIndexMap[zoneID]; ok {
+		return idx
+	}
+	idx := r.nextIndex
+	r.zoneIndexMap[zoneID] = idx
+	r.indexZoneMap[idx] = zoneID
+	r.nextIndex++
+	return idx
+}
+
+// parsePhaseCurrents converts a phases command parameter into per-phase
+// values. Keys may be phase names ("A") or CBOR-normalized enum values ("0").
+// A nil value means the zone withdraws its value for that phase.
+func parsePhaseCurrents(phases map[string]any) (map[Phase]*int64, error) {
+	if len(phases) == 0 {
+		return nil, fmt.Errorf("phases must not be empty")
+	}
+	result := make(map[Phase]*int64, len(phases))
+	for key, raw := range phases {
+		phase, ok := parsePhaseKey(key)
+		if !ok {
+			return nil, fmt.Errorf("invalid phase %q", key)
+		}
+		if raw == nil {
+			result[phase] = nil
+			continue
+		}
+		v, ok := wire.ToInt64(raw)
+		if !ok {
+			return nil, fmt.Errorf("invalid current for phase %s", phase)
+		}
+		result[phase] = &v
+	}
+	return result, nil
+}
+
+func parsePhaseKey(key string) (Phase, bool) {
+	for p := range maxPhases {
+		phase := Phase(p)
+		if key == phase.String() || key == fmt.Sprintf("%d", p) {
+			return phase, true
+		}
+	}
+	return 0, false
+}
+
+// phaseDirections expands an optional direction into the directions it
+// covers. nil and BIDIRECTIONAL cover both; unknown values return nil.
+func phaseDirections(dir *Direction) []Direction {
+	if dir == nil {
+		return []Direction{DirectionConsumption, DirectionProduction}
+	}
+	switch *dir {
+	case DirectionConsumption, DirectionProduction:
+		return []Direction{*dir}
+	case DirectionBidirectional:
+		return []Direction{DirectionConsumption, DirectionProduction}
+	default:
+		return nil
+	}
+}
+
+func phaseCurrentCmdType(setpoints bool, dir Direction) duration.CommandType {
+	switch {
+	case setpoints && dir == DirectionProduction:
+		return duration.CmdCurrentSetpointProduction
+	case setpoints:
+		return duration.CmdCurrentSetpointConsumption
+	case dir == DirectionProduction:
+		return duration.CmdCurrentLimitProduction
+	default:
+		return duration.CmdCurrentLimitConsumption
+	}
+}
+
+func myPhaseCurrentAttr(setpoints bool, dir Direction) uint16 {
+	switch {
+	case setpoints && dir == DirectionProduction:
+		return EnergyControlAttrMyCurrentSetpointsProduction
+	case setpoints:
+		return EnergyControlAttrMyCurrentSetpointsConsumption
+	case dir == DirectionProduction:
+		return EnergyControlAttrMyCurrentLimitsProduction
+	default:
+		return EnergyControlAttrMyCurrentLimitsConsumption
+	}
+}
+
+func clearedPhaseCurrentChanges() map[uint16]any {
+	return map[uint16]any{
+		EnergyControlAttrMyCurrentLimitsConsumption:    nil,
+		EnergyControlAttrMyCurrentLimitsProduction:     nil,
+		EnergyControlAttrMyCurrentSetpointsConsumption: nil,
+		EnergyControlAttrMyCurrentSetpointsProduction:  nil,
+	}
+}
+
+func phaseCurrentFamilyName(setpoints bool) string {
+	if setpoints {
+		return "current setpoints"
+	}
+	return "current limits"
+}
+
+func directionName(dir Direction) string {
+	if dir == DirectionProduction {
+		return "production"
+	}
+	return "consumption"
+}
@@ -0,0 +1,356 @@
+package features
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/mash-protocol/mash-go/pkg/cert"
+	"github.com/mash-protocol/mash-go/pkg/clock"
+	"github.com/mash-protocol/mash-go/pkg/wire"
+)
+
+func newTestPhaseCurrentResolver(asym AsymmetricSupport) *PhaseCurrentResolver {
+	ec := NewEnergyControl()
+	_ = ec.SetControlState(ControlStateAutonomous)
+	ec.SetCapabilities(false, true, false, true, false, false, false)
+
+	el := NewElectrical()
+	_ = el.SetPhaseCount(3)
+	_ = el.SetPhaseMapping(map[Phase]GridPhase{PhaseA: GridPhaseL1, PhaseB: GridPhaseL2, PhaseC: GridPhaseL3})
+	_ = el.SetMaxCurrentPerPhase(32000)
+	_ = el.SetSupportsAsymmetric(asym)
+
+	r := NewPhaseCurrentResolver(ec, el)
+	r.Register()
+	return r
+}
+
+func phases(a, b, c int64) map[string]any {
+	return map[string]any{"A": a, "B": b, "C": c}
+}
+
+func assertPhaseMap(t *testing.T, label string, got map[Phase]int64, ok bool, want map[Phase]int64) {
+	t.Helper()
+	if want == nil {
+		if ok {
+			t.Fatalf("%s: expected no value, got %v", label, got)
+		}
+		return
+	}
+	if !ok || !reflect.DeepEqual(got, want) {
+		t.Fatalf("%s: expected %v, got %v (ok=%v)", label, want, got, ok)
+	}
+}
+
+func TestPhaseCurrentResolver_LimitsMostRestrictivePerPhase(t *testing.T) {
+	r := newTestPhaseCurrentResolver(AsymmetricSupportConsumption)
+	ctxGrid := testCtx("zone-GRID", cert.ZoneTypeGrid)
+	ctxLocal := testCtx("zone-LOCAL", cert.ZoneTypeLocal)
+
+	if err := r.HandleSetCurrentLimits(ctxGrid, SetCurrentLimitsRequest{
+		Phases: phases(20000, 20000, 20000), Direction: DirectionConsumption,
+	}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := r.HandleSetCurrentLimits(ctxLocal, SetCurrentLimitsRequest{
+		Phases: phases(16000, 10000, 16000), Direction: DirectionConsumption,
+	}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	eff, ok := r.ec.EffectiveCurrentLimitsConsumption()
+	assertPhaseMap(t, "effective", eff, ok, map[Phase]int64{PhaseA: 16000, PhaseB: 10000, PhaseC: 16000})
+	if r.ec.ControlState() != ControlStateControlled {
+		t.Fatalf("expected CONTROLLED, got %s", r.ec.ControlState())
+	}
+
+	_ = r.HandleClearCurrentLimits(ctxLocal, ClearCurrentLimitsRequest{})
+
+	eff, ok = r.ec.EffectiveCurrentLimitsConsumption()
+	assertPhaseMap(t, "effective after LOCAL clear", eff, ok, map[Phase]int64{PhaseA: 20000, PhaseB: 20000, PhaseC: 20000})
+
+	_ = r.HandleClearCurrentLimits(ctxGrid, ClearCurrentLimitsRequest{})
+
+	eff, ok = r.ec.EffectiveCurrentLimitsConsumption()
+	assertPhaseMap(t, "effective after all clear", eff, ok, nil)
+	if r.ec.ControlState() != ControlStateAutonomous {
+		t.Fatalf("expected AUTONOMOUS, got %s", r.ec.ControlState())
+	}
+}
+
+func TestPhaseCurrentResolver_SetpointsHighestPriorityWinsPerPhase(t *testing.T) {
+	r := newTestPhaseCurrentResolver(AsymmetricSupportBidirectional)
+	ctxGrid := testCtx("zone-GRID", cert.ZoneTypeGrid)
+	ctxLocal := testCtx("zone-LOCAL", cert.ZoneTypeLocal)
+
+	_ = r.HandleSetCurrentSetpoints(ctxLocal, SetCurrentSetpointsRequest{
+		Phases: phases(10000, 10000, 10000), Direction: DirectionProduction,
+	})
+	// GRID only sets phase B; A and C remain LOCAL's
+	_ = r.HandleSetCurrentSetpoints(ctxGrid, SetCurrentSetpointsRequest{
+		Phases: map[string]any{"B": int64(4000)}, Direction: DirectionProduction,
+	})
+
+	eff, ok := r.ec.EffectiveCurrentSetpointsProduction()
+	assertPhaseMap(t, "effective", eff, ok, map[Phase]int64{PhaseA: 10000, PhaseB: 4000, PhaseC: 10000})
+}
+
+// TestPhaseCurrentResolver_V2HPhaseBalancing builds the worked example from
+// docs/multi-zone.md section 5.4.
+func TestPhaseCurrentResolver_V2HPhaseBalancing(t *testing.T) {
+	r := newTestPhaseCurrentResolver(AsymmetricSupportBidirectional)
+	ctxGrid := testCtx("zone-GRID", cert.ZoneTypeGrid)
+	ctxEMS := testCtx("zone-EMS", cert.ZoneTypeLocal)
+
+	// 1. Grid operator sets limit
+	if err := r.HandleSetCurrentLimits(ctxGrid, SetCurrentLimitsRequest{
+		Phases: phases(25000, 25000, 25000), Direction: DirectionProduction, Cause: LimitCauseGridEmergency,
+	}); err != nil {
+		t.Fatalf("SetCurrentLimits failed: %v", err)
+	}
+
+	// 2. Home EMS sets asymmetric discharge setpoint
+	if err := r.HandleSetCurrentSetpoints(ctxEMS, SetCurrentSetpointsRequest{
+		Phases: phases(10000, 2000, 5000), Direction: DirectionProduction, Cause: SetpointCausePhaseBalancing,
+	}); err != nil {
+		t.Fatalf("SetCurrentSetpoints failed: %v", err)
+	}
+
+	// 3. V2H EV receives
+	effLimits, ok := r.ec.EffectiveCurrentLimitsProduction()
+	assertPhaseMap(t, "effectiveCurrentLimitsProduction", effLimits, ok, map[Phase]int64{PhaseA: 25000, PhaseB: 25000, PhaseC: 25000})
+
+	effSetpoints, ok := r.ec.EffectiveCurrentSetpointsProduction()
+	assertPhaseMap(t, "effectiveCurrentSetpointsProduction", effSetpoints, ok, map[Phase]int64{PhaseA: 10000, PhaseB: 2000, PhaseC: 5000})
+
+	// Per-zone views
+	myLimits := readAsZone(t, r.ec, "zone-GRID", cert.ZoneTypeGrid, EnergyControlAttrMyCurrentLimitsProduction)
+	if !reflect.DeepEqual(myLimits, map[Phase]int64{PhaseA: 25000, PhaseB: 25000, PhaseC: 25000}) {
+		t.Fatalf("GRID myCurrentLimitsProduction: got %v", myLimits)
+	}
+	mySetpoints := readAsZone(t, r.ec, "zone-GRID", cert.ZoneTypeGrid, EnergyControlAttrMyCurrentSetpointsProduction)
+	assertNil(t, "GRID myCurrentSetpointsProduction", mySetpoints)
+
+	mySetpoints = readAsZone(t, r.ec, "zone-EMS", cert.ZoneTypeLocal, EnergyControlAttrMyCurrentSetpointsProduction)
+	if !reflect.DeepEqual(mySetpoints, map[Phase]int64{PhaseA: 10000, PhaseB: 2000, PhaseC: 5000}) {
+		t.Fatalf("EMS myCurrentSetpointsProduction: got %v", mySetpoints)
+	}
+}
+
+func TestPhaseCurrentResolver_CBORPhaseKeys(t *testing.T) {
+	r := newTestPhaseCurrentResolver(AsymmetricSupportConsumption)
+	ctx := testCtx("zone-A", cert.ZoneTypeLocal)
+
+	// Nested maps arrive with integer keys, normalized to strings by the codec.
+	params := map[string]any{
+		"phases":    wire.ToStringMap(map[any]any{uint64(0): uint64(16000), uint64(2): uint64(8000)}),
+		"direction": uint64(DirectionConsumption),
+		"cause":     uint64(LimitCauseLocalProtection),
+	}
+	if _, err := r.ec.InvokeCommand(ctx, EnergyControlCmdSetCurrentLimits, params); err != nil {
+		t.Fatalf("InvokeCommand failed: %v", err)
+	}
+
+	eff, ok := r.ec.EffectiveCurrentLimitsConsumption()
+	assertPhaseMap(t, "effective", eff, ok, map[Phase]int64{PhaseA: 16000, PhaseC: 8000})
+}
+
+func TestPhaseCurrentResolver_NullPhaseWithdraws(t *testing.T) {
+	r := newTestPhaseCurrentResolver(AsymmetricSupportConsumption)
+	ctx := testCtx("zone-A", cert.ZoneTypeLocal)
+
+	_ = r.HandleSetCurrentLimits(ctx, SetCurrentLimitsRequest{
+		Phases: phases(16000, 16000, 16000), Direction: DirectionConsumption,
+	})
+	_ = r.HandleSetCurrentLimits(ctx, SetCurrentLimitsRequest{
+		Phases: map[string]any{"B": nil}, Direction: DirectionConsumption,
+	})
+
+	eff, ok := r.ec.EffectiveCurrentLimitsConsumption()
+	assertPhaseMap(t, "effective", eff, ok, map[Phase]int64{PhaseA: 16000, PhaseC: 16000})
+}
+
+func TestPhaseCurrentResolver_Validation(t *testing.T) {
+	ctx := testCtx("zone-A", cert.ZoneTypeLocal)
+
+	tests := []struct {
+		name   string
+		asym   AsymmetricSupport
+		ctx    context.Context
+		req    SetCurrentLimitsRequest
+		status wire.Status
+	}{
+		{"no zone", AsymmetricSupportBidirectional, context.Background(),
+			SetCurrentLimitsRequest{Phases: phases(1, 1, 1)}, wire.StatusInvalidParameter},
+		{"empty phases", AsymmetricSupportBidirectional, ctx,
+			SetCurrentLimitsRequest{}, wire.StatusInvalidParameter},
+		{"unknown phase", AsymmetricSupportBidirectional, ctx,
+			SetCurrentLimitsRequest{Phases: map[string]any{"D": int64(1)}}, wire.StatusInvalidParameter},
+		{"negative", AsymmetricSupportBidirectional, ctx,
+			SetCurrentLimitsRequest{Phases: map[string]any{"A": int64(-1)}}, wire.StatusInvalidParameter},
+		{"above max current", AsymmetricSupportBidirectional, ctx,
+			SetCurrentLimitsRequest{Phases: map[string]any{"A": int64(40000)}}, wire.StatusConstraintError},
+		{"asymmetric not supported", AsymmetricSupportNone, ctx,
+			SetCurrentLimitsRequest{Phases: phases(16000, 10000, 16000)}, wire.StatusConstraintError},
+		{"asymmetric wrong direction", AsymmetricSupportConsumption, ctx,
+			SetCurrentLimitsRequest{Phases: phases(16000, 10000, 16000), Direction: DirectionProduction}, wire.StatusConstraintError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newTestPhaseCurrentResolver(tt.asym)
+			err := r.HandleSetCurrentLimits(tt.ctx, tt.req)
+			assertCommandStatus(t, err, tt.status)
+			if _, ok := r.ec.EffectiveCurrentLimitsConsumption(); ok {
+				t.Fatal("expected no state change on rejected request")
+			}
+		})
+	}
+
+	t.Run("symmetric accepted", func(t *testing.T) {
+		r := newTestPhaseCurrentResolver(AsymmetricSupportNone)
+		if err := r.HandleSetCurrentLimits(ctx, SetCurrentLimitsRequest{Phases: phases(16000, 16000, 16000)}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("phase not present", func(t *testing.T) {
+		r := newTestPhaseCurrentResolver(AsymmetricSupportBidirectional)
+		_ = r.el.SetPhaseCount(1)
+		_ = r.el.SetPhaseMapping(map[Phase]GridPhase{PhaseA: GridPhaseL2})
+		err := r.HandleSetCurrentLimits(ctx, SetCurrentLimitsRequest{Phases: map[string]any{"B": int64(6000)}})
+		assertCommandStatus(t, err, wire.StatusConstraintError)
+	})
+
+	t.Run("override", func(t *testing.T) {
+		r := newTestPhaseCurrentResolver(AsymmetricSupportBidirectional)
+		_ = r.ec.SetControlState(ControlStateOverride)
+		err := r.HandleSetCurrentSetpoints(ctx, SetCurrentSetpointsRequest{Phases: phases(1, 2, 3)})
+		assertCommandStatus(t, err, wire.StatusBusy)
+	})
+}
+
+func TestPhaseCurrentResolver_DurationExpiryPerPhase(t *testing.T) {
+	r := newTestPhaseCurrentResolver(AsymmetricSupportConsumption)
+	ctx := testCtx("zone-A", cert.ZoneTypeLocal)
+
+	// Indefinite limit on all phases
+	_ = r.HandleSetCurrentLimits(ctx, SetCurrentLimitsRequest{
+		Phases: phases(16000, 16000, 16000), Direction: DirectionConsumption,
+	})
+
+	// Phase B overridden with a short-lived value; A and C stay indefinite
+	dur := uint32(1)
+	_ = r.HandleSetCurrentLimits(ctx, SetCurrentLimitsRequest{
+		Phases: map[string]any{"B": int64(6000)}, Direction: DirectionConsumption, Duration: &dur,
+	})
+
+	eff, ok := r.ec.EffectiveCurrentLimitsConsumption()
+	assertPhaseMap(t, "before expiry", eff, ok, map[Phase]int64{PhaseA: 16000, PhaseB: 6000, PhaseC: 16000})
+
+	time.Sleep(1500 * time.Millisecond)
+
+	eff, ok = r.ec.EffectiveCurrentLimitsConsumption()
+	assertPhaseMap(t, "after expiry", eff, ok, map[Phase]int64{PhaseA: 16000, PhaseC: 16000})
+}
+
+func TestPhaseCurrentResolver_ClearByDirection(t *testing.T) {
+	r := newTestPhaseCurrentResolver(AsymmetricSupportBidirectional)
+	ctx := testCtx("zone-A", cert.ZoneTypeLocal)
+
+	_ = r.HandleSetCurrentSetpoints(ctx, SetCurrentSetpointsRequest{
+		Phases: phases(8000, 8000, 8000), Direction: DirectionBidirectional,
+	})
+
+	dir := DirectionConsumption
+	_ = r.HandleClearCurrentSetpoints(ctx, ClearCurrentSetpointsRequest{Direction: &dir})
+
+	if _, ok := r.ec.EffectiveCurrentSetpointsConsumption(); ok {
+		t.Fatal("expected consumption setpoints cleared")
+	}
+	eff, ok := r.ec.EffectiveCurrentSetpointsProduction()
+	assertPhaseMap(t, "production", eff, ok, map[Phase]int64{PhaseA: 8000, PhaseB: 8000, PhaseC: 8000})
+}
+
+func TestPhaseCurrentResolver_ClearZoneAndResetAll(t *testing.T) {
+	r := newTestPhaseCurrentResolver(AsymmetricSupportConsumption)
+	ctxA := testCtx("zone-A", cert.ZoneTypeLocal)
+	ctxB := testCtx("zone-B", cert.ZoneTypeGrid)
+
+	var notified []string
+	r.OnZoneMyChange = func(zoneID string, changes map[uint16]any) {
+		if v, ok := changes[EnergyControlAttrMyCurrentLimitsConsumption]; ok && v == nil {
+			notified = append(notified, zoneID)
+		}
+	}
+
+	_ = r.HandleSetCurrentLimits(ctxA, SetCurrentLimitsRequest{Phases: phases(10000, 10000, 10000)})
+	_ = r.HandleSetCurrentLimits(ctxB, SetCurrentLimitsRequest{Phases: phases(20000, 20000, 20000)})
+
+	r.ClearZone("zone-A")
+
+	eff, ok := r.ec.EffectiveCurrentLimitsConsumption()
+	assertPhaseMap(t, "after ClearZone", eff, ok, map[Phase]int64{PhaseA: 20000, PhaseB: 20000, PhaseC: 20000})
+	if len(notified) != 1 || notified[0] != "zone-A" {
+		t.Fatalf("expected OnZoneMyChange for zone-A, got %v", notified)
+	}
+
+	r.ResetAll()
+
+	if _, ok := r.ec.EffectiveCurrentLimitsConsumption(); ok {
+		t.Fatal("expected no effective limits after ResetAll")
+	}
+	if r.ec.ControlState() != ControlStateAutonomous {
+		t.Fatalf("expected AUTONOMOUS after ResetAll, got %s", r.ec.ControlState())
+	}
+}
+
+func TestPhaseCurrentResolver_SnapshotRestore(t *testing.T) {
+	fc := clock.NewFake(time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC))
+	r := newTestPhaseCurrentResolver(AsymmetricSupportConsumption)
+	r.SetClock(fc)
+	ctxGrid := testCtx("zone-GRID", cert.ZoneTypeGrid)
+
+	dur := uint32(7200)
+	if err := r.HandleSetCurrentLimits(ctxGrid, SetCurrentLimitsRequest{
+		Phases: phases(16000, 10000, 16000), Direction: DirectionConsumption, Duration: &dur,
+		Cause: LimitCauseLocalProtection,
+	}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := r.HandleSetCurrentSetpoints(ctxGrid, SetCurrentSetpointsRequest{
+		Phases: phases(8000, 8000, 8000), Direction: DirectionConsumption,
+	}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	snap := r.Snapshot()
+	if len(snap.Limits) != 3 || len(snap.Setpoints) != 3 {
+		t.Fatalf("expected 3 limits and 3 setpoints, got %d and %d", len(snap.Limits), len(snap.Setpoints))
+	}
+	if p := snap.Limits[1].Phase; p == nil || *p != PhaseB || snap.Limits[1].Value != 10000 {
+		t.Errorf("unexpected phase B limit entry %+v", snap.Limits[1])
+	}
+	if snap.Limits[0].Cause != uint8(LimitCauseLocalProtection) {
+		t.Errorf("Cause = %d, want %d", snap.Limits[0].Cause, LimitCauseLocalProtection)
+	}
+
+	fc.Advance(time.Hour)
+	r2 := newTestPhaseCurrentResolver(AsymmetricSupportConsumption)
+	r2.SetClock(fc)
+	r2.Restore(snap)
+
+	got, ok := r2.ec.EffectiveCurrentLimitsConsumption()
+	assertPhaseMap(t, "restored limits", got, ok, map[Phase]int64{PhaseA: 16000, PhaseB: 10000, PhaseC: 16000})
+	got, ok = r2.ec.EffectiveCurrentSetpointsConsumption()
+	assertPhaseMap(t, "restored setpoints", got, ok, map[Phase]int64{PhaseA: 8000, PhaseB: 8000, PhaseC: 8000})
+
+	// Limits expire at the original deadline; setpoints were indefinite.
+	fc.Advance(time.Hour)
+	got, ok = r2.ec.EffectiveCurrentLimitsConsumption()
+	assertPhaseMap(t, "limits after expiry", got, ok, nil)
+	got, ok = r2.ec.EffectiveCurrentSetpointsConsumption()
+	assertPhaseMap(t, "setpoints after expiry", got, ok, map[Phase]int64{PhaseA: 8000, PhaseB: 8000, PhaseC: 8000})
+}
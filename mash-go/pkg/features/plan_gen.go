@@ -61,6 +61,22 @@ type PlanSlot struct {
 	MaxPower     int64
 }
 
+// EncodePlanSlots encodes slots items for the wire. Nullable
+// fields are omitted when zero.
+func EncodePlanSlots(items []PlanSlot) []any {
+	data := make([]any, len(items))
+	for i, item := range items {
+		m := map[string]any{
+			"duration":     item.Duration,
+			"plannedPower": item.PlannedPower,
+			"minPower":     item.MinPower,
+			"maxPower":     item.MaxPower,
+		}
+		data[i] = m
+	}
+	return data
+}
+
 // Plan wraps a Feature with Plan-specific functionality.
 // Device reports intended power behavior. Commitment levels support negotiation.
 type Plan struct {
@@ -362,16 +378,7 @@ func (p *Plan) SetSlots(slots []PlanSlot) error {
 	if slots == nil {
 		return attr.SetValueInternal(nil)
 	}
-	data := make([]any, len(slots))
-	for i, item := range slots {
-		data[i] = map[string]any{
-			"duration":     item.Duration,
-			"plannedPower": item.PlannedPower,
-			"minPower":     item.MinPower,
-			"maxPower":     item.MaxPower,
-		}
-	}
-	return attr.SetValueInternal(data)
+	return attr.SetValueInternal(EncodePlanSlots(slots))
 }
 
 // ClearSlots clears the planned power behavior slots.
@@ -444,11 +451,15 @@ func (p *Plan) handleRequestPlan(ctx context.Context, params map[string]any) (ma
 	if raw, exists := params["startTime"]; exists {
 		if v, ok := raw.(uint64); ok {
 			req.StartTime = &v
+		} else if raw != nil {
+			return nil, wire.NewFieldError(wire.StatusInvalidParameter, wire.FieldError{Path: "startTime", Message: "expected uint64", Constraint: wire.ConstraintType})
 		}
 	}
 	if raw, exists := params["duration"]; exists {
 		if v, ok := wire.ToUint32(raw); ok {
 			req.Duration = &v
+		} else if raw != nil {
+			return nil, wire.NewFieldError(wire.StatusInvalidParameter, wire.FieldError{Path: "duration", Message: "expected uint32", Constraint: wire.ConstraintType})
 		}
 	}
 
@@ -471,11 +482,15 @@ func (p *Plan) handleAcceptPlan(ctx context.Context, params map[string]any) (map
 	if raw, exists := params["planId"]; exists {
 		if v, ok := wire.ToUint32(raw); ok {
 			req.PlanID = v
+		} else if raw != nil {
+			return nil, wire.NewFieldError(wire.StatusInvalidParameter, wire.FieldError{Path: "planId", Message: "expected uint32", Constraint: wire.ConstraintType})
 		}
 	}
 	if raw, exists := params["planVersion"]; exists {
 		if v, ok := wire.ToUint32(raw); ok {
 			req.PlanVersion = v
+		} else if raw != nil {
+			return nil, wire.NewFieldError(wire.StatusInvalidParameter, wire.FieldError{Path: "planVersion", Message: "expected uint32", Constraint: wire.ConstraintType})
 		}
 	}
 
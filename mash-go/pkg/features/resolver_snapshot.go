@@ -0,0 +1,116 @@
+package features
+
+import (
+	"sort"
+	"time"
+
+	"github.com/mash-protocol/mash-go/pkg/cert"
+	"github.com/mash-protocol/mash-go/pkg/duration"
+	"github.com/mash-protocol/mash-go/pkg/zone"
+)
+
+// ZoneValueSnapshot is one zone's contribution to a resolved limit or
+// setpoint, captured so it can be restored after a device restart.
+type ZoneValueSnapshot struct {
+	ZoneID    string
+	ZoneType  cert.ZoneType
+	Direction Direction
+
+	// Phase is the device phase for per-phase current values
+	// (PhaseCurrentResolver). Nil for whole-device limits and setpoints.
+	Phase *Phase
+
+	Value int64
+
+	// Cause is the LimitCause or SetpointCause sent with the command.
+	Cause uint8
+
+	// ExpiresAt is when the value's duration elapses. Zero if indefinite.
+	ExpiresAt time.Time
+}
+
+// PhaseCurrentSnapshot holds the per-phase current limits and setpoints
+// captured by PhaseCurrentResolver.Snapshot.
+type PhaseCurrentSnapshot struct {
+	Limits    []ZoneValueSnapshot
+	Setpoints []ZoneValueSnapshot
+}
+
+// causeKey identifies the cause sent with a zone's value for one command type.
+type causeKey struct {
+	zoneID  string
+	cmdType duration.CommandType
+}
+
+// snapshotValues appends one entry per zone in mzv. Entries not owned by a
+// zone (e.g. signal contributions) are skipped; they are rebuilt by their
+// source. Must be called with the resolver's mu held.
+func snapshotValues(
+	out []ZoneValueSnapshot,
+	mzv *zone.MultiZoneValue,
+	dir Direction,
+	phase *Phase,
+	cmdType duration.CommandType,
+	zoneIndexMap map[string]uint8,
+	timers *duration.Manager,
+	causes map[causeKey]uint8,
+) []ZoneValueSnapshot {
+	for zoneID, zv := range mzv.Values {
+		zoneIdx, ok := zoneIndexMap[zoneID]
+		if !ok {
+			continue
+		}
+		entry := ZoneValueSnapshot{
+			ZoneID:    zoneID,
+			ZoneType:  zv.ZoneType,
+			Direction: dir,
+			Phase:     phase,
+			Value:     zv.Value,
+			Cause:     causes[causeKey{zoneID, cmdType}],
+		}
+		if timer := timers.GetTimer(zoneIdx, cmdType); timer != nil {
+			entry.ExpiresAt = timer.ExpiresAt()
+		}
+		out = append(out, entry)
+	}
+	return out
+}
+
+// sortSnapshots orders entries by zone, direction and phase so snapshots are
+// stable across calls.
+func sortSnapshots(entries []ZoneValueSnapshot) {
+	sort.Slice(entries, func(i, j int) bool {
+		a, b := entries[i], entries[j]
+		if a.ZoneID != b.ZoneID {
+			return a.ZoneID < b.ZoneID
+		}
+		if a.Direction != b.Direction {
+			return a.Direction < b.Direction
+		}
+		return phaseOrder(a.Phase) < phaseOrder(b.Phase)
+	})
+}
+
+func phaseOrder(p *Phase) int {
+	if p == nil {
+		return -1
+	}
+	return int(*p)
+}
+
+// remainingAt returns how long a restored value has left at now. A zero
+// expiry means indefinite (0, true). Values with less than the minimum timer
+// duration left are reported as expired (0, false).
+func remainingAt(expiresAt, now time.Time) (time.Duration, bool) {
+	if expiresAt.IsZero() {
+		return 0, true
+	}
+	remaining := expiresAt.Sub(now)
+	if remaining < duration.MinDuration {
+		return 0, false
+	}
+	if remaining > duration.MaxDuration {
+		remaining = duration.MaxDuration
+	}
+	return remaining, true
+}
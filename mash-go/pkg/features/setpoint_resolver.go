@@ -0,0 +1,503 @@
+package features
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/mash-protocol/mash-go/pkg/clock"
+	"github.com/mash-protocol/mash-go/pkg/duration"
+	"github.com/mash-protocol/mash-go/pkg/wire"
+	"github.com/mash-protocol/mash-go/pkg/zone"
+	"github.com/mash-protocol/mash-go/pkg/zonecontext"
+)
+
+// SetpointResolver tracks per-zone setpoints and resolves the effective
+// setpoint using "highest priority wins" semantics. It is the setpoint
+// counterpart of LimitResolver: it manages duration timers per zone and
+// updates the EnergyControl feature attributes when setpoints change.
+//
+// Limits are resolved independently; the device application is expected to
+// cap the effective setpoint by the effective limit when acting on it.
+//
+// Zone identity is extracted from context via pkg/zonecontext.
+type SetpointResolver struct {
+	mu sync.Mutex
+
+	ec *EnergyControl
+
+	consumptionSetpoints *zone.MultiZoneValue
+	productionSetpoints  *zone.MultiZoneValue
+	causes               map[causeKey]uint8
+
+	clock        clock.Clock
+	timers       *duration.Manager
+	zoneIndexMap map[string]uint8
+	indexZoneMap map[uint8]string
+	nextIndex    uint8
+
+	// MaxConsumption is the device's nominal maximum consumption power (mW).
+	// If > 0, SetSetpoint rejects consumptionSetpoint values above this
+	// threshold with StatusConstraintError.
+	MaxConsumption int64
+	// MaxProduction is the device's nominal maximum production power (mW).
+	// If > 0, SetSetpoint rejects productionSetpoint values above this threshold.
+	MaxProduction int64
+
+	// OnZoneMyChange is called when a zone's "my" attribute values change.
+	// The callback receives the zone ID and a map of changed attribute IDs to values.
+	// Injected by the service layer to avoid import cycles.
+	OnZoneMyChange func(zoneID string, changes map[uint16]any)
+
+	// OnChange is called after a zone's setpoints are set, cleared or expire,
+	// once the resolver's lock is released. The service layer uses it to
+	// persist them.
+	OnChange func()
+
+	// changed is set under mu by changes that OnChange reports.
+	changed bool
+}
+
+// NewSetpointResolver creates a new SetpointResolver for the given EnergyControl feature.
+func NewSetpointResolver(ec *EnergyControl) *SetpointResolver {
+	sr := &SetpointResolver{
+		ec:                   ec,
+		consumptionSetpoints: zone.NewMultiZoneValue(),
+		productionSetpoints:  zone.NewMultiZoneValue(),
+		causes:               make(map[causeKey]uint8),
+		clock:                clock.Real(),
+		timers:               duration.NewManager(),
+		zoneIndexMap:         make(map[string]uint8),
+		indexZoneMap:         make(map[uint8]string),
+	}
+
+	sr.timers.OnExpiry(sr.handleTimerExpiry)
+
+	return sr
+}
+
+// SetClock sets the time source for duration timers and value expiry. Must
+// be called before any setpoint is set.
+func (sr *SetpointResolver) SetClock(c clock.Clock) {
+	sr.mu.Lock()
+	defer sr.mu.Unlock()
+
+	sr.clock = clock.OrReal(c)
+	sr.consumptionSetpoints = zone.NewMultiZoneValueWithClock(sr.clock)
+	sr.productionSetpoints = zone.NewMultiZoneValueWithClock(sr.clock)
+	sr.timers = duration.NewManagerWithClock(sr.clock)
+	sr.timers.OnExpiry(sr.handleTimerExpiry)
+}
+
+// Register wires the resolver's handlers into the EnergyControl feature.
+// Any read hook installed before Register is chained for attributes this
+// resolver does not own.
+func (sr *SetpointResolver) Register() {
+	sr.ec.OnSetSetpoint(sr.HandleSetSetpoint)
+	sr.ec.OnClearSetpoint(sr.HandleClearSetpoint)
+
+	prev := sr.ec.ReadHook()
+	sr.ec.SetReadHook(func(ctx context.Context, attrID uint16) (any, bool) {
+		switch attrID {
+		case EnergyControlAttrMyConsumptionSetpoint, EnergyControlAttrMyProductionSetpoint:
+			// Intercept per-zone "my" attributes
+		default:
+			if prev != nil {
+				return prev(ctx, attrID)
+			}
+			return nil, false
+		}
+
+		zoneID := zonecontext.CallerZoneIDFromContext(ctx)
+		if zoneID == "" {
+			return nil, true
+		}
+
+		sr.mu.Lock()
+		defer sr.mu.Unlock()
+
+		var mzv *zone.MultiZoneValue
+		if attrID == EnergyControlAttrMyConsumptionSetpoint {
+			mzv = sr.consumptionSetpoints
+		} else {
+			mzv = sr.productionSetpoints
+		}
+
+		zv := mzv.Get(zoneID)
+		if zv == nil {
+			return nil, true
+		}
+		return zv.Value, true
+	})
+}
+
+// HandleSetSetpoint handles a SetSetpoint command from a zone.
+func (sr *SetpointResolver) HandleSetSetpoint(ctx context.Context, req SetSetpointRequest) error {
+	sr.mu.Lock()
+	defer sr.unlock()
+
+	// Extract zone identity from context
+	zoneID := zonecontext.CallerZoneIDFromContext(ctx)
+	if zoneID == "" {
+		return &wire.CommandError{
+			Status:  wire.StatusInvalidParameter,
+			Message: "setpoint requires a zone identity",
+		}
+	}
+
+	zoneType := zonecontext.CallerZoneTypeFromContext(ctx)
+
+	// Validate negative values
+	if req.ConsumptionSetpoint != nil && *req.ConsumptionSetpoint < 0 {
+		return &wire.CommandError{
+			Status:  wire.StatusInvalidParameter,
+			Message: fmt.Sprintf("consumptionSetpoint %d mW must not be negative", *req.ConsumptionSetpoint),
+			Fields:  []wire.FieldError{mustNotBeNegative("consumptionSetpoint")},
+		}
+	}
+	if req.ProductionSetpoint != nil && *req.ProductionSetpoint < 0 {
+		return &wire.CommandError{
+			Status:  wire.StatusInvalidParameter,
+			Message: fmt.Sprintf("productionSetpoint %d mW must not be negative", *req.ProductionSetpoint),
+			Fields:  []wire.FieldError{mustNotBeNegative("productionSetpoint")},
+		}
+	}
+
+	// Validate against device capacity constraints (fail-fast, before state changes).
+	if req.ConsumptionSetpoint != nil && sr.MaxConsumption > 0 && *req.ConsumptionSetpoint > sr.MaxConsumption {
+		return &wire.CommandError{
+			Status:  wire.StatusConstraintError,
+			Message: fmt.Sprintf("consumptionSetpoint %d mW exceeds device maximum %d mW", *req.ConsumptionSetpoint, sr.MaxConsumption),
+			Fields:  []wire.FieldError{exceedsDeviceMaximum("consumptionSetpoint", sr.MaxConsumption)},
+		}
+	}
+	if req.ProductionSetpoint != nil && sr.MaxProduction > 0 && *req.ProductionSetpoint > sr.MaxProduction {
+		return &wire.CommandError{
+			Status:  wire.StatusConstraintError,
+			Message: fmt.Sprintf("productionSetpoint %d mW exceeds device maximum %d mW", *req.ProductionSetpoint, sr.MaxProduction),
+			Fields:  []wire.FieldError{exceedsDeviceMaximum("productionSetpoint", sr.MaxProduction)},
+		}
+	}
+
+	// Check override state
+	if sr.ec.IsOverride() {
+		return &wire.CommandError{
+			Status:  wire.StatusBusy,
+			Message: "device is in OVERRIDE state",
+		}
+	}
+
+	// Both nil = deactivate this zone's setpoints
+	if req.ConsumptionSetpoint == nil && req.ProductionSetpoint == nil {
+		sr.clearZoneLocked(zoneID)
+		sr.changed = true
+		sr.resolveAndApply()
+		if sr.OnZoneMyChange != nil {
+			sr.OnZoneMyChange(zoneID, map[uint16]any{
+				EnergyControlAttrMyConsumptionSetpoint: nil,
+				EnergyControlAttrMyProductionSetpoint:  nil,
+			})
+		}
+		return nil
+	}
+
+	// Ensure zone has an index for duration timers
+	zoneIdx := sr.ensureZoneIndex(zoneID)
+
+	// Compute duration
+	var dur time.Duration
+	if req.Duration != nil && *req.Duration > 0 {
+		dur = time.Duration(*req.Duration) * time.Second
+	}
+
+	// Store per-zone values
+	if req.ConsumptionSetpoint != nil {
+		sr.consumptionSetpoints.Set(zoneID, zoneType, *req.ConsumptionSetpoint, dur)
+		sr.causes[causeKey{zoneID, duration.CmdSetpointConsumption}] = uint8(req.Cause)
+		if dur > 0 {
+			_ = sr.timers.SetTimer(zoneIdx, duration.CmdSetpointConsumption, dur, *req.ConsumptionSetpoint)
+		} else {
+			_ = sr.timers.CancelTimer(zoneIdx, duration.CmdSetpointConsumption)
+		}
+	}
+	if req.ProductionSetpoint != nil {
+		sr.productionSetpoints.Set(zoneID, zoneType, *req.ProductionSetpoint, dur)
+		sr.causes[causeKey{zoneID, duration.CmdSetpointProduction}] = uint8(req.Cause)
+		if dur > 0 {
+			_ = sr.timers.SetTimer(zoneIdx, duration.CmdSetpointProduction, dur, *req.ProductionSetpoint)
+		} else {
+			_ = sr.timers.CancelTimer(zoneIdx, duration.CmdSetpointProduction)
+		}
+	}
+
+	sr.changed = true
+	sr.resolveAndApply()
+
+	if sr.OnZoneMyChange != nil {
+		changes := make(map[uint16]any)
+		if req.ConsumptionSetpoint != nil {
+			changes[EnergyControlAttrMyConsumptionSetpoint] = *req.ConsumptionSetpoint
+		}
+		if req.ProductionSetpoint != nil {
+			changes[EnergyControlAttrMyProductionSetpoint] = *req.ProductionSetpoint
+		}
+		sr.OnZoneMyChange(zoneID, changes)
+	}
+
+	if req.Duration != nil && *req.Duration > 0 {
+		log.Printf("[SETPOINT] Zone %s set setpoint with duration: %ds (cause %s)", zoneID, *req.Duration, req.Cause)
+	}
+
+	return nil
+}
+
+// HandleClearSetpoint handles a ClearSetpoint command from a zone.
+func (sr *SetpointResolver) HandleClearSetpoint(ctx context.Context, req ClearSetpointRequest) error {
+	sr.mu.Lock()
+	defer sr.unlock()
+
+	zoneID := zonecontext.CallerZoneIDFromContext(ctx)
+	if zoneID == "" {
+		return nil
+	}
+
+	zoneIdx, hasIdx := sr.zoneIndexMap[zoneID]
+
+	if req.Direction == nil {
+		// Clear both directions
+		sr.consumptionSetpoints.Clear(zoneID)
+		sr.productionSetpoints.Clear(zoneID)
+		if hasIdx {
+			_ = sr.timers.CancelTimer(zoneIdx, duration.CmdSetpointConsumption)
+			_ = sr.timers.CancelTimer(zoneIdx, duration.CmdSetpointProduction)
+		}
+	} else if *req.Direction == DirectionConsumption {
+		sr.consumptionSetpoints.Clear(zoneID)
+		if hasIdx {
+			_ = sr.timers.CancelTimer(zoneIdx, duration.CmdSetpointConsumption)
+		}
+	} else if *req.Direction == DirectionProduction {
+		sr.productionSetpoints.Clear(zoneID)
+		if hasIdx {
+			_ = sr.timers.CancelTimer(zoneIdx, duration.CmdSetpointProduction)
+		}
+	}
+
+	sr.changed = true
+	sr.resolveAndApply()
+
+	if sr.OnZoneMyChange != nil {
+		changes := make(map[uint16]any)
+		if req.Direction == nil || *req.Direction == DirectionConsumption {
+			changes[EnergyControlAttrMyConsumptionSetpoint] = nil
+		}
+		if req.Direction == nil || *req.Direction == DirectionProduction {
+			changes[EnergyControlAttrMyProductionSetpoint] = nil
+		}
+		sr.OnZoneMyChange(zoneID, changes)
+	}
+
+	return nil
+}
+
+// ResetAll clears all zone setpoints, cancels all timers, and re-resolves
+// the EnergyControl feature. This is used by TriggerResetTestState to prevent
+// stale timers from prior tests from firing during subsequent tests.
+func (sr *SetpointResolver) ResetAll() {
+	sr.mu.Lock()
+
+	// Cancel all timers for all known zones.
+	for _, zoneIdx := range sr.zoneIndexMap {
+		sr.timers.CancelZoneTimers(zoneIdx)
+	}
+
+	// Collect zone IDs that had setpoints so we can fire callbacks.
+	var zonesWithSetpoints []string
+	for zoneID := range sr.zoneIndexMap {
+		zonesWithSetpoints = append(zonesWithSetpoints, zoneID)
+	}
+
+	// Replace setpoint maps with fresh instances (clear all zone values).
+	sr.consumptionSetpoints = zone.NewMultiZoneValueWithClock(sr.clock)
+	sr.productionSetpoints = zone.NewMultiZoneValueWithClock(sr.clock)
+	sr.causes = make(map[causeKey]uint8)
+
+	sr.changed = true
+	sr.resolveAndApply()
+
+	sr.unlock()
+
+	// Fire callbacks outside the lock to avoid deadlocks.
+	if sr.OnZoneMyChange != nil {
+		for _, zoneID := range zonesWithSetpoints {
+			sr.OnZoneMyChange(zoneID, map[uint16]any{
+				EnergyControlAttrMyConsumptionSetpoint: nil,
+				EnergyControlAttrMyProductionSetpoint:  nil,
+			})
+		}
+	}
+}
+
+// ClearZone removes all setpoints for a zone (e.g., on disconnect/failsafe).
+func (sr *SetpointResolver) ClearZone(zoneID string) {
+	sr.mu.Lock()
+	defer sr.unlock()
+
+	sr.clearZoneLocked(zoneID)
+	sr.changed = true
+	sr.resolveAndApply()
+
+	if sr.OnZoneMyChange != nil {
+		sr.OnZoneMyChange(zoneID, map[uint16]any{
+			EnergyControlAttrMyConsumptionSetpoint: nil,
+			EnergyControlAttrMyProductionSetpoint:  nil,
+		})
+	}
+}
+
+// Snapshot returns every zone's active setpoints with their cause and
+// absolute expiry, for persistence across restarts.
+func (sr *SetpointResolver) Snapshot() []ZoneValueSnapshot {
+	sr.mu.Lock()
+	defer sr.mu.Unlock()
+
+	var entries []ZoneValueSnapshot
+	entries = snapshotValues(entries, sr.consumptionSetpoints, DirectionConsumption, nil,
+		duration.CmdSetpointConsumption, sr.zoneIndexMap, sr.timers, sr.causes)
+	entries = snapshotValues(entries, sr.productionSetpoints, DirectionProduction, nil,
+		duration.CmdSetpointProduction, sr.zoneIndexMap, sr.timers, sr.causes)
+	sortSnapshots(entries)
+	return entries
+}
+
+// Restore re-applies setpoints captured by Snapshot and re-arms their
+// duration timers for the time each had left. Setpoints that expired in the
+// meantime are dropped. Like LimitResolver.Restore, no OnZoneMyChange
+// callbacks are fired.
+func (sr *SetpointResolver) Restore(entries []ZoneValueSnapshot) {
+	sr.mu.Lock()
+	defer sr.mu.Unlock()
+
+	now := sr.clock.Now()
+	for _, e := range entries {
+		var mzv *zone.MultiZoneValue
+		var cmdType duration.CommandType
+		switch e.Direction {
+		case DirectionConsumption:
+			mzv, cmdType = sr.consumptionSetpoints, duration.CmdSetpointConsumption
+		case DirectionProduction:
+			mzv, cmdType = sr.productionSetpoints, duration.CmdSetpointProduction
+		default:
+			continue
+		}
+
+		remaining, ok := remainingAt(e.ExpiresAt, now)
+		if !ok {
+			log.Printf("[SETPOINT] Zone %s %s setpoint expired while offline", e.ZoneID, directionName(e.Direction))
+			continue
+		}
+
+		zoneIdx := sr.ensureZoneIndex(e.ZoneID)
+		mzv.Set(e.ZoneID, e.ZoneType, e.Value, remaining)
+		sr.causes[causeKey{e.ZoneID, cmdType}] = e.Cause
+		if remaining > 0 {
+			_ = sr.timers.SetTimer(zoneIdx, cmdType, remaining, e.Value)
+		} else {
+			_ = sr.timers.CancelTimer(zoneIdx, cmdType)
+		}
+	}
+
+	sr.resolveAndApply()
+}
+
+// clearZoneLocked clears a zone's setpoints and timers. Must be called with mu held.
+func (sr *SetpointResolver) clearZoneLocked(zoneID string) {
+	sr.consumptionSetpoints.Clear(zoneID)
+	sr.productionSetpoints.Clear(zoneID)
+	delete(sr.causes, causeKey{zoneID, duration.CmdSetpointConsumption})
+	delete(sr.causes, causeKey{zoneID, duration.CmdSetpointProduction})
+
+	if zoneIdx, ok := sr.zoneIndexMap[zoneID]; ok {
+		sr.timers.CancelZoneTimers(zoneIdx)
+	}
+}
+
+// handleTimerExpiry is called by the duration manager when a timer expires.
+func (sr *SetpointResolver) handleTimerExpiry(zoneIdx uint8, cmdType duration.CommandType, _ any) {
+	sr.mu.Lock()
+	defer sr.unlock()
+
+	zoneID, ok := sr.indexZoneMap[zoneIdx]
+	if !ok {
+		return
+	}
+
+	switch cmdType {
+	case duration.CmdSetpointConsumption:
+		sr.consumptionSetpoints.Clear(zoneID)
+		log.Printf("[SETPOINT] Zone %s consumption setpoint expired", zoneID)
+	case duration.CmdSetpointProduction:
+		sr.productionSetpoints.Clear(zoneID)
+		log.Printf("[SETPOINT] Zone %s production setpoint expired", zoneID)
+	}
+
+	sr.changed = true
+	sr.resolveAndApply()
+
+	if sr.OnZoneMyChange != nil {
+		changes := make(map[uint16]any)
+		switch cmdType {
+		case duration.CmdSetpointConsumption:
+			changes[EnergyControlAttrMyConsumptionSetpoint] = nil
+		case duration.CmdSetpointProduction:
+			changes[EnergyControlAttrMyProductionSetpoint] = nil
+		}
+		sr.OnZoneMyChange(zoneID, changes)
+	}
+}
+
+// resolveAndApply computes effective setpoints and updates the EnergyControl
+// feature. Must be called with mu held.
+//
+// The control state follows the same rules as LimitResolver: CONTROLLED while
+// any limit or setpoint is active, AUTONOMOUS once everything is cleared.
+func (sr *SetpointResolver) resolveAndApply() {
+	effConsumption, _ := sr.consumptionSetpoints.ResolveSetpoints()
+	effProduction, _ := sr.productionSetpoints.ResolveSetpoints()
+
+	_ = sr.ec.SetEffectiveConsumptionSetpointPtr(effConsumption)
+	_ = sr.ec.SetEffectiveProductionSetpointPtr(effProduction)
+
+	if sr.ec.hasActiveControl() {
+		_ = sr.ec.SetControlState(ControlStateControlled)
+	} else {
+		_ = sr.ec.SetControlState(ControlStateAutonomous)
+	}
+}
+
+// unlock releases mu and calls OnChange if setpoints changed meanwhile.
+func (sr *SetpointResolver) unlock() {
+	changed := sr.changed
+	sr.changed = false
+	onChange := sr.OnChange
+	sr.mu.Unlock()
+
+	if changed && onChange != nil {
+		onChange()
+	}
+}
+
+// ensureZoneIndex returns a uint8 index for the zone, creating one if needed.
+// Must be called with mu held.
+func (sr *SetpointResolver) ensureZoneIndex(zoneID string) uint8 {
+	if idx, ok := sr.zoneIndexMap[zoneID]; ok {
+		return idx
+	}
+	idx := sr.nextIndex
+	sr.zoneIndexMap[zoneID] = idx
+	sr.indexZoneMap[idx] = zoneID
+	sr.nextIndex++
+	return idx
+}
@@ -0,0 +1,170 @@
+package features
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mash-protocol/mash-go/pkg/cert"
+)
+
+func TestSetpointResolver_Integration_TwoZonePerZoneReads(t *testing.T) {
+	t.Run("consumption setpoints", func(t *testing.T) {
+		sr := newTestSetpointResolverRegistered()
+		ctxGrid := testCtx("zone-GRID", cert.ZoneTypeGrid)
+		ctxLocal := testCtx("zone-LOCAL", cert.ZoneTypeLocal)
+
+		// Step 1: Zone LOCAL sets consumptionSetpoint = 5 kW
+		_ = sr.HandleSetSetpoint(ctxLocal, SetSetpointRequest{ConsumptionSetpoint: intPtr(5000000)})
+
+		// Step 2: Zone GRID sets consumptionSetpoint = 6 kW (higher priority)
+		_ = sr.HandleSetSetpoint(ctxGrid, SetSetpointRequest{ConsumptionSetpoint: intPtr(6000000)})
+
+		// Step 3: Zone GRID reads
+		valMy := readAsZone(t, sr.ec, "zone-GRID", cert.ZoneTypeGrid, EnergyControlAttrMyConsumptionSetpoint)
+		assertInt64(t, "GRID myConsumptionSetpoint", valMy, 6000000)
+
+		valEff := readAsZone(t, sr.ec, "zone-GRID", cert.ZoneTypeGrid, EnergyControlAttrEffectiveConsumptionSetpoint)
+		assertInt64(t, "GRID effectiveConsumptionSetpoint", valEff, 6000000)
+
+		// Step 4: Zone LOCAL reads
+		valMy = readAsZone(t, sr.ec, "zone-LOCAL", cert.ZoneTypeLocal, EnergyControlAttrMyConsumptionSetpoint)
+		assertInt64(t, "LOCAL myConsumptionSetpoint", valMy, 5000000)
+
+		valEff = readAsZone(t, sr.ec, "zone-LOCAL", cert.ZoneTypeLocal, EnergyControlAttrEffectiveConsumptionSetpoint)
+		assertInt64(t, "LOCAL effectiveConsumptionSetpoint", valEff, 6000000)
+
+		// Step 5: Zone GRID clears its setpoint
+		_ = sr.HandleClearSetpoint(ctxGrid, ClearSetpointRequest{})
+
+		valMy = readAsZone(t, sr.ec, "zone-GRID", cert.ZoneTypeGrid, EnergyControlAttrMyConsumptionSetpoint)
+		assertNil(t, "GRID myConsumptionSetpoint after clear", valMy)
+
+		valEff = readAsZone(t, sr.ec, "zone-GRID", cert.ZoneTypeGrid, EnergyControlAttrEffectiveConsumptionSetpoint)
+		assertInt64(t, "GRID effectiveConsumptionSetpoint after GRID clear", valEff, 5000000)
+
+		valMy = readAsZone(t, sr.ec, "zone-LOCAL", cert.ZoneTypeLocal, EnergyControlAttrMyConsumptionSetpoint)
+		assertInt64(t, "LOCAL myConsumptionSetpoint after GRID clear", valMy, 5000000)
+
+		valEff = readAsZone(t, sr.ec, "zone-LOCAL", cert.ZoneTypeLocal, EnergyControlAttrEffectiveConsumptionSetpoint)
+		assertInt64(t, "LOCAL effectiveConsumptionSetpoint after GRID clear", valEff, 5000000)
+
+		// Step 6: Zone LOCAL clears its setpoint -- everything should be nil
+		_ = sr.HandleClearSetpoint(ctxLocal, ClearSetpointRequest{})
+
+		valMy = readAsZone(t, sr.ec, "zone-GRID", cert.ZoneTypeGrid, EnergyControlAttrMyConsumptionSetpoint)
+		assertNil(t, "GRID myConsumptionSetpoint after all clear", valMy)
+
+		valEff = readAsZone(t, sr.ec, "zone-GRID", cert.ZoneTypeGrid, EnergyControlAttrEffectiveConsumptionSetpoint)
+		assertNil(t, "GRID effectiveConsumptionSetpoint after all clear", valEff)
+
+		valMy = readAsZone(t, sr.ec, "zone-LOCAL", cert.ZoneTypeLocal, EnergyControlAttrMyConsumptionSetpoint)
+		assertNil(t, "LOCAL myConsumptionSetpoint after all clear", valMy)
+
+		valEff = readAsZone(t, sr.ec, "zone-LOCAL", cert.ZoneTypeLocal, EnergyControlAttrEffectiveConsumptionSetpoint)
+		assertNil(t, "LOCAL effectiveConsumptionSetpoint after all clear", valEff)
+	})
+
+	t.Run("production setpoints", func(t *testing.T) {
+		sr := newTestSetpointResolverRegistered()
+		ctxGrid := testCtx("zone-GRID", cert.ZoneTypeGrid)
+		ctxLocal := testCtx("zone-LOCAL", cert.ZoneTypeLocal)
+
+		// Zone GRID sets productionSetpoint = 2 kW
+		_ = sr.HandleSetSetpoint(ctxGrid, SetSetpointRequest{ProductionSetpoint: intPtr(2000000)})
+
+		// Zone LOCAL sets productionSetpoint = 4 kW (lower priority)
+		_ = sr.HandleSetSetpoint(ctxLocal, SetSetpointRequest{ProductionSetpoint: intPtr(4000000)})
+
+		// Zone GRID reads its own production setpoint
+		valMy := readAsZone(t, sr.ec, "zone-GRID", cert.ZoneTypeGrid, EnergyControlAttrMyProductionSetpoint)
+		assertInt64(t, "GRID myProductionSetpoint", valMy, 2000000)
+
+		valEff := readAsZone(t, sr.ec, "zone-GRID", cert.ZoneTypeGrid, EnergyControlAttrEffectiveProductionSetpoint)
+		assertInt64(t, "GRID effectiveProductionSetpoint", valEff, 2000000)
+
+		// Zone LOCAL reads its own production setpoint
+		valMy = readAsZone(t, sr.ec, "zone-LOCAL", cert.ZoneTypeLocal, EnergyControlAttrMyProductionSetpoint)
+		assertInt64(t, "LOCAL myProductionSetpoint", valMy, 4000000)
+
+		valEff = readAsZone(t, sr.ec, "zone-LOCAL", cert.ZoneTypeLocal, EnergyControlAttrEffectiveProductionSetpoint)
+		assertInt64(t, "LOCAL effectiveProductionSetpoint", valEff, 2000000)
+
+		// Zone GRID clears its setpoint
+		_ = sr.HandleClearSetpoint(ctxGrid, ClearSetpointRequest{})
+
+		valMy = readAsZone(t, sr.ec, "zone-GRID", cert.ZoneTypeGrid, EnergyControlAttrMyProductionSetpoint)
+		assertNil(t, "GRID myProductionSetpoint after clear", valMy)
+
+		valEff = readAsZone(t, sr.ec, "zone-GRID", cert.ZoneTypeGrid, EnergyControlAttrEffectiveProductionSetpoint)
+		assertInt64(t, "GRID effectiveProductionSetpoint after GRID clear", valEff, 4000000)
+
+		// Zone LOCAL clears its setpoint
+		_ = sr.HandleClearSetpoint(ctxLocal, ClearSetpointRequest{})
+
+		valMy = readAsZone(t, sr.ec, "zone-LOCAL", cert.ZoneTypeLocal, EnergyControlAttrMyProductionSetpoint)
+		assertNil(t, "LOCAL myProductionSetpoint after all clear", valMy)
+
+		valEff = readAsZone(t, sr.ec, "zone-LOCAL", cert.ZoneTypeLocal, EnergyControlAttrEffectiveProductionSetpoint)
+		assertNil(t, "LOCAL effectiveProductionSetpoint after all clear", valEff)
+	})
+
+	t.Run("ReadAllAttributesWithContext", func(t *testing.T) {
+		sr := newTestSetpointResolverRegistered()
+		ctxGrid := testCtx("zone-GRID", cert.ZoneTypeGrid)
+		ctxLocal := testCtx("zone-LOCAL", cert.ZoneTypeLocal)
+
+		// Both zones set consumption setpoints
+		_ = sr.HandleSetSetpoint(ctxGrid, SetSetpointRequest{ConsumptionSetpoint: intPtr(6000000)})
+		_ = sr.HandleSetSetpoint(ctxLocal, SetSetpointRequest{ConsumptionSetpoint: intPtr(5000000)})
+
+		// ReadAllAttributesWithContext as Zone GRID
+		allGrid := sr.ec.ReadAllAttributesWithContext(ctxGrid)
+
+		myC, ok := allGrid[EnergyControlAttrMyConsumptionSetpoint]
+		if !ok {
+			t.Fatal("GRID ReadAll: myConsumptionSetpoint missing from result")
+		}
+		assertInt64(t, "GRID ReadAll myConsumptionSetpoint", myC, 6000000)
+
+		effC, ok := allGrid[EnergyControlAttrEffectiveConsumptionSetpoint]
+		if !ok {
+			t.Fatal("GRID ReadAll: effectiveConsumptionSetpoint missing from result")
+		}
+		assertInt64(t, "GRID ReadAll effectiveConsumptionSetpoint", effC, 6000000)
+
+		// ReadAllAttributesWithContext as Zone LOCAL
+		allLocal := sr.ec.ReadAllAttributesWithContext(ctxLocal)
+
+		myC, ok = allLocal[EnergyControlAttrMyConsumptionSetpoint]
+		if !ok {
+			t.Fatal("LOCAL ReadAll: myConsumptionSetpoint missing from result")
+		}
+		assertInt64(t, "LOCAL ReadAll myConsumptionSetpoint", myC, 5000000)
+
+		// Zone with no setpoint set should see nil for myConsumptionSetpoint
+		ctxOther := testCtx("zone-OTHER", cert.ZoneTypeLocal)
+		allOther := sr.ec.ReadAllAttributesWithContext(ctxOther)
+
+		myC, ok = allOther[EnergyControlAttrMyConsumptionSetpoint]
+		if !ok {
+			t.Fatal("OTHER ReadAll: myConsumptionSetpoint missing from result")
+		}
+		assertNil(t, "OTHER ReadAll myConsumptionSetpoint", myC)
+
+		// Effective setpoint should still be visible to any zone
+		effC, ok = allOther[EnergyControlAttrEffectiveConsumptionSetpoint]
+		if !ok {
+			t.Fatal("OTHER ReadAll: effectiveConsumptionSetpoint missing from result")
+		}
+		assertInt64(t, "OTHER ReadAll effectiveConsumptionSetpoint", effC, 6000000)
+
+		// ReadAllAttributesWithContext with background context (no zone)
+		allNoZone := sr.ec.ReadAllAttributesWithContext(context.Background())
+
+		myC, ok = allNoZone[EnergyControlAttrMyConsumptionSetpoint]
+		if !ok {
+			t.Fatal("no-zone ReadAll: myConsumptionSetpoint missing from result")
+		}
+		assertNil(t, "no-zone ReadAll myConsumptionSetpoint", myC)
+	})
+}
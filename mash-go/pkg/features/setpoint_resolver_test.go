@@ -0,0 +1,341 @@
+package features
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/mash-protocol/mash-go/pkg/cert"
+	"github.com/mash-protocol/mash-go/pkg/clock"
+	"github.com/mash-protocol/mash-go/pkg/wire"
+)
+
+func newTestSetpointResolver() *SetpointResolver {
+	ec := NewEnergyControl()
+	_ = ec.SetControlState(ControlStateAutonomous)
+	ec.SetCapabilities(false, false, true, false, false, false, false)
+
+	return NewSetpointResolver(ec)
+}
+
+// newTestSetpointResolverRegistered creates a test resolver with Register()
+// called, which wires up the ReadHook on the underlying model.Feature.
+func newTestSetpointResolverRegistered() *SetpointResolver {
+	sr := newTestSetpointResolver()
+	sr.Register()
+	return sr
+}
+
+// assertCommandStatus asserts err is a *wire.CommandError with the given status.
+func assertCommandStatus(t *testing.T, err error, status wire.Status) {
+	t.Helper()
+	var cmdErr *wire.CommandError
+	if !errors.As(err, &cmdErr) {
+		t.Fatalf("expected *wire.CommandError, got %T (%v)", err, err)
+	}
+	if cmdErr.Status != status {
+		t.Fatalf("expected status %v, got %v", status, cmdErr.Status)
+	}
+}
+
+func TestSetpointResolver_SingleZoneSetClear(t *testing.T) {
+	sr := newTestSetpointResolver()
+	ctx := testCtx("zone-A", cert.ZoneTypeLocal)
+
+	if err := sr.HandleSetSetpoint(ctx, SetSetpointRequest{ConsumptionSetpoint: intPtr(5000000)}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if sr.ec.ControlState() != ControlStateControlled {
+		t.Fatalf("expected CONTROLLED, got %s", sr.ec.ControlState())
+	}
+	eff, ok := sr.ec.EffectiveConsumptionSetpoint()
+	if !ok || eff != 5000000 {
+		t.Fatalf("expected effective consumption setpoint of 5000000, got %d (ok=%v)", eff, ok)
+	}
+
+	if err := sr.HandleClearSetpoint(ctx, ClearSetpointRequest{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if sr.ec.ControlState() != ControlStateAutonomous {
+		t.Fatalf("expected AUTONOMOUS after clear, got %s", sr.ec.ControlState())
+	}
+	if _, ok := sr.ec.EffectiveConsumptionSetpoint(); ok {
+		t.Fatal("expected no effective consumption setpoint after clear")
+	}
+}
+
+func TestSetpointResolver_HighestPriorityWins(t *testing.T) {
+	sr := newTestSetpointResolver()
+	ctxGrid := testCtx("zone-GRID", cert.ZoneTypeGrid)
+	ctxLocal := testCtx("zone-LOCAL", cert.ZoneTypeLocal)
+
+	// LOCAL sets 5 kW first
+	_ = sr.HandleSetSetpoint(ctxLocal, SetSetpointRequest{ConsumptionSetpoint: intPtr(5000000)})
+
+	// GRID sets 3 kW; GRID has higher priority regardless of value
+	_ = sr.HandleSetSetpoint(ctxGrid, SetSetpointRequest{ConsumptionSetpoint: intPtr(3000000)})
+
+	eff, ok := sr.ec.EffectiveConsumptionSetpoint()
+	if !ok || eff != 3000000 {
+		t.Fatalf("expected GRID setpoint 3000000, got %d (ok=%v)", eff, ok)
+	}
+
+	// GRID raising its setpoint still wins
+	_ = sr.HandleSetSetpoint(ctxGrid, SetSetpointRequest{ConsumptionSetpoint: intPtr(7000000)})
+
+	eff, ok = sr.ec.EffectiveConsumptionSetpoint()
+	if !ok || eff != 7000000 {
+		t.Fatalf("expected GRID setpoint 7000000, got %d (ok=%v)", eff, ok)
+	}
+}
+
+func TestSetpointResolver_ClearPromotesRemaining(t *testing.T) {
+	sr := newTestSetpointResolver()
+	ctxGrid := testCtx("zone-GRID", cert.ZoneTypeGrid)
+	ctxLocal := testCtx("zone-LOCAL", cert.ZoneTypeLocal)
+
+	_ = sr.HandleSetSetpoint(ctxGrid, SetSetpointRequest{ConsumptionSetpoint: intPtr(3000000)})
+	_ = sr.HandleSetSetpoint(ctxLocal, SetSetpointRequest{ConsumptionSetpoint: intPtr(5000000)})
+
+	_ = sr.HandleClearSetpoint(ctxGrid, ClearSetpointRequest{})
+
+	eff, ok := sr.ec.EffectiveConsumptionSetpoint()
+	if !ok || eff != 5000000 {
+		t.Fatalf("expected LOCAL promoted to 5000000, got %d (ok=%v)", eff, ok)
+	}
+}
+
+func TestSetpointResolver_TestZoneIgnored(t *testing.T) {
+	sr := newTestSetpointResolver()
+	ctxTest := testCtx("zone-TEST", cert.ZoneTypeTest)
+
+	if err := sr.HandleSetSetpoint(ctxTest, SetSetpointRequest{ConsumptionSetpoint: intPtr(2000000)}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := sr.ec.EffectiveConsumptionSetpoint(); ok {
+		t.Fatal("expected TEST zone setpoint to be excluded from resolution")
+	}
+	if sr.ec.ControlState() != ControlStateAutonomous {
+		t.Fatalf("expected AUTONOMOUS, got %s", sr.ec.ControlState())
+	}
+}
+
+func TestSetpointResolver_DurationExpiry(t *testing.T) {
+	sr := newTestSetpointResolver()
+	ctxGrid := testCtx("zone-GRID", cert.ZoneTypeGrid)
+	ctxLocal := testCtx("zone-LOCAL", cert.ZoneTypeLocal)
+
+	// LOCAL sets an indefinite 6 kW setpoint
+	_ = sr.HandleSetSetpoint(ctxLocal, SetSetpointRequest{ConsumptionSetpoint: intPtr(6000000)})
+
+	// GRID sets 3 kW with very short duration (1s is the minimum)
+	dur := uint32(1)
+	_ = sr.HandleSetSetpoint(ctxGrid, SetSetpointRequest{
+		ConsumptionSetpoint: intPtr(3000000),
+		Duration:            &dur,
+	})
+
+	eff, ok := sr.ec.EffectiveConsumptionSetpoint()
+	if !ok || eff != 3000000 {
+		t.Fatalf("expected 3000000, got %d", eff)
+	}
+
+	// Wait for expiry
+	time.Sleep(1500 * time.Millisecond)
+
+	// After expiry, LOCAL's 6 kW should be promoted
+	eff, ok = sr.ec.EffectiveConsumptionSetpoint()
+	if !ok || eff != 6000000 {
+		t.Fatalf("after expiry, expected 6000000, got %d (ok=%v)", eff, ok)
+	}
+}
+
+func TestSetpointResolver_ClearByDirection(t *testing.T) {
+	sr := newTestSetpointResolver()
+	ctx := testCtx("zone-A", cert.ZoneTypeLocal)
+
+	_ = sr.HandleSetSetpoint(ctx, SetSetpointRequest{
+		ConsumptionSetpoint: intPtr(5000000),
+		ProductionSetpoint:  intPtr(3000000),
+	})
+
+	dir := DirectionConsumption
+	_ = sr.HandleClearSetpoint(ctx, ClearSetpointRequest{Direction: &dir})
+
+	if _, ok := sr.ec.EffectiveConsumptionSetpoint(); ok {
+		t.Fatal("expected consumption setpoint cleared")
+	}
+	effP, ok := sr.ec.EffectiveProductionSetpoint()
+	if !ok || effP != 3000000 {
+		t.Fatalf("expected production setpoint 3000000 to remain, got %d (ok=%v)", effP, ok)
+	}
+	if sr.ec.ControlState() != ControlStateControlled {
+		t.Fatalf("expected CONTROLLED while production setpoint active, got %s", sr.ec.ControlState())
+	}
+}
+
+func TestSetpointResolver_Validation(t *testing.T) {
+	t.Run("no zone ID", func(t *testing.T) {
+		sr := newTestSetpointResolver()
+		err := sr.HandleSetSetpoint(context.Background(), SetSetpointRequest{ConsumptionSetpoint: intPtr(5000000)})
+		assertCommandStatus(t, err, wire.StatusInvalidParameter)
+	})
+
+	t.Run("negative value", func(t *testing.T) {
+		sr := newTestSetpointResolver()
+		ctx := testCtx("zone-A", cert.ZoneTypeLocal)
+		err := sr.HandleSetSetpoint(ctx, SetSetpointRequest{ProductionSetpoint: intPtr(-1)})
+		assertCommandStatus(t, err, wire.StatusInvalidParameter)
+	})
+
+	t.Run("exceeds maximum", func(t *testing.T) {
+		sr := newTestSetpointResolver()
+		sr.MaxConsumption = 11000000
+		ctx := testCtx("zone-A", cert.ZoneTypeLocal)
+		err := sr.HandleSetSetpoint(ctx, SetSetpointRequest{ConsumptionSetpoint: intPtr(12000000)})
+		assertCommandStatus(t, err, wire.StatusConstraintError)
+		if _, ok := sr.ec.EffectiveConsumptionSetpoint(); ok {
+			t.Fatal("expected no state change on constraint error")
+		}
+	})
+
+	t.Run("override", func(t *testing.T) {
+		sr := newTestSetpointResolver()
+		_ = sr.ec.SetControlState(ControlStateOverride)
+		ctx := testCtx("zone-A", cert.ZoneTypeLocal)
+		err := sr.HandleSetSetpoint(ctx, SetSetpointRequest{ConsumptionSetpoint: intPtr(5000000)})
+		assertCommandStatus(t, err, wire.StatusBusy)
+		if sr.ec.ControlState() != ControlStateOverride {
+			t.Fatalf("expected OVERRIDE to be preserved, got %s", sr.ec.ControlState())
+		}
+	})
+}
+
+func TestSetpointResolver_NilSetpointsDeactivate(t *testing.T) {
+	sr := newTestSetpointResolver()
+	ctx := testCtx("zone-A", cert.ZoneTypeLocal)
+
+	_ = sr.HandleSetSetpoint(ctx, SetSetpointRequest{ConsumptionSetpoint: intPtr(5000000)})
+
+	if err := sr.HandleSetSetpoint(ctx, SetSetpointRequest{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := sr.ec.EffectiveConsumptionSetpoint(); ok {
+		t.Fatal("expected setpoint deactivated")
+	}
+	if sr.ec.ControlState() != ControlStateAutonomous {
+		t.Fatalf("expected AUTONOMOUS after deactivation, got %s", sr.ec.ControlState())
+	}
+}
+
+func TestSetpointResolver_ClearZone(t *testing.T) {
+	sr := newTestSetpointResolver()
+	ctxGrid := testCtx("zone-GRID", cert.ZoneTypeGrid)
+	ctxLocal := testCtx("zone-LOCAL", cert.ZoneTypeLocal)
+
+	var notified []string
+	sr.OnZoneMyChange = func(zoneID string, changes map[uint16]any) {
+		if _, ok := changes[EnergyControlAttrMyConsumptionSetpoint]; ok {
+			notified = append(notified, zoneID)
+		}
+	}
+
+	_ = sr.HandleSetSetpoint(ctxGrid, SetSetpointRequest{ConsumptionSetpoint: intPtr(3000000)})
+	_ = sr.HandleSetSetpoint(ctxLocal, SetSetpointRequest{ConsumptionSetpoint: intPtr(6000000)})
+	notified = nil
+
+	sr.ClearZone("zone-GRID")
+
+	eff, ok := sr.ec.EffectiveConsumptionSetpoint()
+	if !ok || eff != 6000000 {
+		t.Fatalf("expected LOCAL promoted to 6000000, got %d (ok=%v)", eff, ok)
+	}
+	if len(notified) != 1 || notified[0] != "zone-GRID" {
+		t.Fatalf("expected OnZoneMyChange for zone-GRID, got %v", notified)
+	}
+}
+
+func TestSetpointResolver_ResetAll(t *testing.T) {
+	sr := newTestSetpointResolver()
+	ctx := testCtx("zone-A", cert.ZoneTypeLocal)
+
+	dur := uint32(1)
+	_ = sr.HandleSetSetpoint(ctx, SetSetpointRequest{
+		ConsumptionSetpoint: intPtr(5000000),
+		Duration:            &dur,
+	})
+
+	sr.ResetAll()
+
+	if _, ok := sr.ec.EffectiveConsumptionSetpoint(); ok {
+		t.Fatal("expected no effective setpoint after ResetAll")
+	}
+	if sr.ec.ControlState() != ControlStateAutonomous {
+		t.Fatalf("expected AUTONOMOUS after ResetAll, got %s", sr.ec.ControlState())
+	}
+}
+
+func TestSetpointResolver_CoexistsWithLimitResolver(t *testing.T) {
+	ec := NewEnergyControl()
+	ec.SetCapabilities(true, false, true, false, false, false, false)
+	lr := NewLimitResolver(ec)
+	lr.Register()
+	sr := NewSetpointResolver(ec)
+	sr.Register()
+
+	ctx := testCtx("zone-A", cert.ZoneTypeLocal)
+
+	_, _ = lr.HandleSetLimit(ctx, SetLimitRequest{ConsumptionLimit: intPtr(4000000)})
+	_ = sr.HandleSetSetpoint(ctx, SetSetpointRequest{ConsumptionSetpoint: intPtr(7000000)})
+
+	// Both read hooks remain active after chaining
+	assertInt64(t, "myConsumptionLimit", readAsZone(t, ec, "zone-A", cert.ZoneTypeLocal, EnergyControlAttrMyConsumptionLimit), 4000000)
+	assertInt64(t, "myConsumptionSetpoint", readAsZone(t, ec, "zone-A", cert.ZoneTypeLocal, EnergyControlAttrMyConsumptionSetpoint), 7000000)
+
+	// Clearing the limit keeps the device CONTROLLED while a setpoint is active
+	_ = lr.HandleClearLimit(ctx, ClearLimitRequest{})
+	if ec.ControlState() != ControlStateControlled {
+		t.Fatalf("expected CONTROLLED with active setpoint, got %s", ec.ControlState())
+	}
+
+	_ = sr.HandleClearSetpoint(ctx, ClearSetpointRequest{})
+	if ec.ControlState() != ControlStateAutonomous {
+		t.Fatalf("expected AUTONOMOUS after clearing everything, got %s", ec.ControlState())
+	}
+}
+
+func TestSetpointResolver_SnapshotRestore(t *testing.T) {
+	fc := clock.NewFake(time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC))
+	sr := newTestSetpointResolver()
+	sr.SetClock(fc)
+
+	dur := uint32(3600)
+	_ = sr.HandleSetSetpoint(testCtx("zone-local", cert.ZoneTypeLocal), SetSetpointRequest{
+		ConsumptionSetpoint: intPtr(2000000),
+		Duration:            &dur,
+		Cause:               SetpointCauseSelfConsumption,
+	})
+
+	snap := sr.Snapshot()
+	if len(snap) != 1 {
+		t.Fatalf("expected 1 entry, got %d", len(snap))
+	}
+	if snap[0].Cause != uint8(SetpointCauseSelfConsumption) {
+		t.Errorf("Cause = %d, want %d", snap[0].Cause, SetpointCauseSelfConsumption)
+	}
+
+	fc.Advance(20 * time.Minute)
+	sr2 := newTestSetpointResolver()
+	sr2.SetClock(fc)
+	sr2.Restore(snap)
+
+	if eff, ok := sr2.ec.EffectiveConsumptionSetpoint(); !ok || eff != 2000000 {
+		t.Fatalf("expected restored setpoint 2000000, got %d (ok=%v)", eff, ok)
+	}
+
+	fc.Advance(40 * time.Minute)
+	if _, ok := sr2.ec.EffectiveConsumptionSetpoint(); ok {
+		t.Error("expected restored setpoint to expire at the original deadline")
+	}
+}
@@ -61,6 +61,29 @@ type PriceSlot struct {
 	Co2Intensity     uint16
 }
 
+// EncodePriceSlots encodes priceSlots items for the wire. Nullable
+// fields are omitted when zero.
+func EncodePriceSlots(items []PriceSlot) []any {
+	data := make([]any, len(items))
+	for i, item := range items {
+		m := map[string]any{
+			"duration": item.Duration,
+			"price":    item.Price,
+		}
+		if item.PriceLevel != 0 {
+			m["priceLevel"] = item.PriceLevel
+		}
+		if item.RenewablePercent != 0 {
+			m["renewablePercent"] = item.RenewablePercent
+		}
+		if item.Co2Intensity != 0 {
+			m["co2Intensity"] = item.Co2Intensity
+		}
+		data[i] = m
+	}
+	return data
+}
+
 // ConstraintSlot represents an item in the constraintSlots array.
 type ConstraintSlot struct {
 	Duration       uint32
@@ -70,6 +93,31 @@ type ConstraintSlot struct {
 	ProductionMin  int64
 }
 
+// EncodeConstraintSlots encodes constraintSlots items for the wire. Nullable
+// fields are omitted when zero.
+func EncodeConstraintSlots(items []ConstraintSlot) []any {
+	data := make([]any, len(items))
+	for i, item := range items {
+		m := map[string]any{
+			"duration": item.Duration,
+		}
+		if item.ConsumptionMax != 0 {
+			m["consumptionMax"] = item.ConsumptionMax
+		}
+		if item.ConsumptionMin != 0 {
+			m["consumptionMin"] = item.ConsumptionMin
+		}
+		if item.ProductionMax != 0 {
+			m["productionMax"] = item.ProductionMax
+		}
+		if item.ProductionMin != 0 {
+			m["productionMin"] = item.ProductionMin
+		}
+		data[i] = m
+	}
+	return data
+}
+
 // ForecastSlot represents an item in the forecastSlots array.
 type ForecastSlot struct {
 	Duration       uint32
@@ -77,6 +125,23 @@ type ForecastSlot struct {
 	ForecastEnergy int64
 }
 
+// EncodeForecastSlots encodes forecastSlots items for the wire. Nullable
+// fields are omitted when zero.
+func EncodeForecastSlots(items []ForecastSlot) []any {
+	data := make([]any, len(items))
+	for i, item := range items {
+		m := map[string]any{
+			"duration":      item.Duration,
+			"forecastPower": item.ForecastPower,
+		}
+		if item.ForecastEnergy != 0 {
+			m["forecastEnergy"] = item.ForecastEnergy
+		}
+		data[i] = m
+	}
+	return data
+}
+
 // Signals wraps a Feature with Signals-specific functionality.
 // Time-slotted price, constraint, and forecast signals from controller to device.
 type Signals struct {
@@ -405,17 +470,7 @@ func (s *Signals) SetPriceSlots(priceSlots []PriceSlot) error {
 	if priceSlots == nil {
 		return attr.SetValueInternal(nil)
 	}
-	data := make([]any, len(priceSlots))
-	for i, item := range priceSlots {
-		data[i] = map[string]any{
-			"duration":         item.Duration,
-			"price":            item.Price,
-			"priceLevel":       item.PriceLevel,
-			"renewablePercent": item.RenewablePercent,
-			"co2Intensity":     item.Co2Intensity,
-		}
-	}
-	return attr.SetValueInternal(data)
+	return attr.SetValueInternal(EncodePriceSlots(priceSlots))
 }
 
 // ClearPriceSlots clears the active price schedule slots.
@@ -436,17 +491,7 @@ func (s *Signals) SetConstraintSlots(constraintSlots []ConstraintSlot) error {
 	if constraintSlots == nil {
 		return attr.SetValueInternal(nil)
 	}
-	data := make([]any, len(constraintSlots))
-	for i, item := range constraintSlots {
-		data[i] = map[string]any{
-			"duration":       item.Duration,
-			"consumptionMax": item.ConsumptionMax,
-			"consumptionMin": item.ConsumptionMin,
-			"productionMax":  item.ProductionMax,
-			"productionMin":  item.ProductionMin,
-		}
-	}
-	return attr.SetValueInternal(data)
+	return attr.SetValueInternal(EncodeConstraintSlots(constraintSlots))
 }
 
 // ClearConstraintSlots clears the active power constraint slots.
@@ -467,15 +512,7 @@ func (s *Signals) SetForecastSlots(forecastSlots []ForecastSlot) error {
 	if forecastSlots == nil {
 		return attr.SetValueInternal(nil)
 	}
-	data := make([]any, len(forecastSlots))
-	for i, item := range forecastSlots {
-		data[i] = map[string]any{
-			"duration":       item.Duration,
-			"forecastPower":  item.ForecastPower,
-			"forecastEnergy": item.ForecastEnergy,
-		}
-	}
-	return attr.SetValueInternal(data)
+	return attr.SetValueInternal(EncodeForecastSlots(forecastSlots))
 }
 
 // ClearForecastSlots clears the active forecast slots.
@@ -587,21 +624,29 @@ func (s *Signals) handleSendPriceSignal(ctx context.Context, params map[string]a
 	if raw, exists := params["source"]; exists {
 		if v, ok := wire.ToUint8Public(raw); ok {
 			req.Source = SignalSource(v)
+		} else if raw != nil {
+			return nil, wire.NewFieldError(wire.StatusInvalidParameter, wire.FieldError{Path: "source", Message: "expected uint8", Constraint: wire.ConstraintType})
 		}
 	}
 	if raw, exists := params["startTime"]; exists {
 		if v, ok := raw.(uint64); ok {
 			req.StartTime = v
+		} else if raw != nil {
+			return nil, wire.NewFieldError(wire.StatusInvalidParameter, wire.FieldError{Path: "startTime", Message: "expected uint64", Constraint: wire.ConstraintType})
 		}
 	}
 	if raw, exists := params["validUntil"]; exists {
 		if v, ok := raw.(uint64); ok {
 			req.ValidUntil = &v
+		} else if raw != nil {
+			return nil, wire.NewFieldError(wire.StatusInvalidParameter, wire.FieldError{Path: "validUntil", Message: "expected uint64", Constraint: wire.ConstraintType})
 		}
 	}
 	if raw, exists := params["slots"]; exists {
 		if v, ok := raw.([]any); ok {
 			req.Slots = v
+		} else if raw != nil {
+			return nil, wire.NewFieldError(wire.StatusInvalidParameter, wire.FieldError{Path: "slots", Message: "expected array", Constraint: wire.ConstraintType})
 		}
 	}
 
@@ -618,21 +663,29 @@ func (s *Signals) handleSendConstraintSignal(ctx context.Context, params map[str
 	if raw, exists := params["source"]; exists {
 		if v, ok := wire.ToUint8Public(raw); ok {
 			req.Source = SignalSource(v)
+		} else if raw != nil {
+			return nil, wire.NewFieldError(wire.StatusInvalidParameter, wire.FieldError{Path: "source", Message: "expected uint8", Constraint: wire.ConstraintType})
 		}
 	}
 	if raw, exists := params["startTime"]; exists {
 		if v, ok := raw.(uint64); ok {
 			req.StartTime = v
+		} else if raw != nil {
+			return nil, wire.NewFieldError(wire.StatusInvalidParameter, wire.FieldError{Path: "startTime", Message: "expected uint64", Constraint: wire.ConstraintType})
 		}
 	}
 	if raw, exists := params["validUntil"]; exists {
 		if v, ok := raw.(uint64); ok {
 			req.ValidUntil = &v
+		} else if raw != nil {
+			return nil, wire.NewFieldError(wire.StatusInvalidParameter, wire.FieldError{Path: "validUntil", Message: "expected uint64", Constraint: wire.ConstraintType})
 		}
 	}
 	if raw, exists := params["slots"]; exists {
 		if v, ok := raw.([]any); ok {
 			req.Slots = v
+		} else if raw != nil {
+			return nil, wire.NewFieldError(wire.StatusInvalidParameter, wire.FieldError{Path: "slots", Message: "expected array", Constraint: wire.ConstraintType})
 		}
 	}
 
@@ -649,21 +702,29 @@ func (s *Signals) handleSendForecastSignal(ctx context.Context, params map[strin
 	if raw, exists := params["source"]; exists {
 		if v, ok := wire.ToUint8Public(raw); ok {
 			req.Source = SignalSource(v)
+		} else if raw != nil {
+			return nil, wire.NewFieldError(wire.StatusInvalidParameter, wire.FieldError{Path: "source", Message: "expected uint8", Constraint: wire.ConstraintType})
 		}
 	}
 	if raw, exists := params["startTime"]; exists {
 		if v, ok := raw.(uint64); ok {
 			req.StartTime = v
+		} else if raw != nil {
+			return nil, wire.NewFieldError(wire.StatusInvalidParameter, wire.FieldError{Path: "startTime", Message: "expected uint64", Constraint: wire.ConstraintType})
 		}
 	}
 	if raw, exists := params["validUntil"]; exists {
 		if v, ok := raw.(uint64); ok {
 			req.ValidUntil = &v
+		} else if raw != nil {
+			return nil, wire.NewFieldError(wire.StatusInvalidParameter, wire.FieldError{Path: "validUntil", Message: "expected uint64", Constraint: wire.ConstraintType})
 		}
 	}
 	if raw, exists := params["slots"]; exists {
 		if v, ok := raw.([]any); ok {
 			req.Slots = v
+		} else if raw != nil {
+			return nil, wire.NewFieldError(wire.StatusInvalidParameter, wire.FieldError{Path: "slots", Message: "expected array", Constraint: wire.ConstraintType})
 		}
 	}
 
@@ -680,6 +741,8 @@ func (s *Signals) handleClearSignals(ctx context.Context, params map[string]any)
 	if raw, exists := params["signalType"]; exists {
 		if v, ok := raw.(string); ok {
 			req.SignalType = &v
+		} else if raw != nil {
+			return nil, wire.NewFieldError(wire.StatusInvalidParameter, wire.FieldError{Path: "signalType", Message: "expected string", Constraint: wire.ConstraintType})
 		}
 	}
 
@@ -1,19 +1,39 @@
 package features
 
 // SetFault sets the fault state with code and message.
+// Emits faultRaised when entering FAULT or when the fault code changes.
 func (s *Status) SetFault(code uint32, message string) error {
+	prevCode, hadCode := s.FaultCode()
+	wasFaulted := s.IsFaulted()
+
 	if err := s.SetOperatingState(OperatingStateFault); err != nil {
 		return err
 	}
 	_ = s.SetFaultCode(code)
 	_ = s.SetFaultMessage(message)
+
+	if !wasFaulted || !hadCode || prevCode != code {
+		_ = s.EmitEvent(StatusEventFaultRaised, map[string]any{
+			"faultCode":    code,
+			"faultMessage": message,
+		})
+	}
 	return nil
 }
 
 // ClearFault clears the fault state.
+// Emits faultCleared if a fault code was set.
 func (s *Status) ClearFault() error {
+	code, hadCode := s.FaultCode()
+
 	_ = s.ClearFaultCode()
 	_ = s.ClearFaultMessage()
+
+	if hadCode {
+		_ = s.EmitEvent(StatusEventFaultCleared, map[string]any{
+			"faultCode": code,
+		})
+	}
 	return nil
 }
 
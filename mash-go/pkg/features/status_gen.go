@@ -111,7 +111,10 @@ func NewStatus() *Status {
 		Description: "Human-readable fault description",
 	}))
 
-	return &Status{Feature: f}
+	s := &Status{Feature: f}
+	s.addEvents()
+
+	return s
 }
 
 // OperatingState returns the current operating state.
@@ -245,3 +248,25 @@ func (s *Status) SetFaultMessagePtr(v *string) error {
 	}
 	return s.SetFaultMessage(*v)
 }
+
+// Status event IDs.
+const (
+	StatusEventFaultRaised  uint8 = 1
+	StatusEventFaultCleared uint8 = 2
+)
+
+// addEvents registers the Status events.
+func (s *Status) addEvents() {
+	s.AddEvent(&model.EventMetadata{
+		ID:          StatusEventFaultRaised,
+		Name:        "faultRaised",
+		Description: "Endpoint entered FAULT or reported a new fault code",
+	})
+
+	s.AddEvent(&model.EventMetadata{
+		ID:          StatusEventFaultCleared,
+		Name:        "faultCleared",
+		Description: "Previously reported fault was cleared",
+	})
+
+}
@@ -224,22 +224,30 @@ func (t *Tariff) handleSetTariff(ctx context.Context, params map[string]any) (ma
 	if raw, exists := params["tariffId"]; exists {
 		if v, ok := wire.ToUint32(raw); ok {
 			req.TariffID = v
+		} else if raw != nil {
+			return nil, wire.NewFieldError(wire.StatusInvalidParameter, wire.FieldError{Path: "tariffId", Message: "expected uint32", Constraint: wire.ConstraintType})
 		}
 	}
 	if raw, exists := params["currency"]; exists {
 		if v, ok := raw.(string); ok {
 			req.Currency = v
+		} else if raw != nil {
+			return nil, wire.NewFieldError(wire.StatusInvalidParameter, wire.FieldError{Path: "currency", Message: "expected string", Constraint: wire.ConstraintType})
 		}
 	}
 	if raw, exists := params["priceUnit"]; exists {
 		if v, ok := wire.ToUint8Public(raw); ok {
 			tmp := PriceUnit(v)
 			req.PriceUnit = &tmp
+		} else if raw != nil {
+			return nil, wire.NewFieldError(wire.StatusInvalidParameter, wire.FieldError{Path: "priceUnit", Message: "expected uint8", Constraint: wire.ConstraintType})
 		}
 	}
 	if raw, exists := params["description"]; exists {
 		if v, ok := raw.(string); ok {
 			req.Description = &v
+		} else if raw != nil {
+			return nil, wire.NewFieldError(wire.StatusInvalidParameter, wire.FieldError{Path: "description", Message: "expected string", Constraint: wire.ConstraintType})
 		}
 	}
 
@@ -113,11 +113,15 @@ func (t *TestControl) handleTriggerTestEvent(ctx context.Context, params map[str
 	if raw, exists := params["enableKey"]; exists {
 		if v, ok := raw.(string); ok {
 			req.EnableKey = v
+		} else if raw != nil {
+			return nil, wire.NewFieldError(wire.StatusInvalidParameter, wire.FieldError{Path: "enableKey", Message: "expected string", Constraint: wire.ConstraintType})
 		}
 	}
 	if raw, exists := params["eventTrigger"]; exists {
 		if v, ok := raw.(uint64); ok {
 			req.EventTrigger = v
+		} else if raw != nil {
+			return nil, wire.NewFieldError(wire.StatusInvalidParameter, wire.FieldError{Path: "eventTrigger", Message: "expected uint64", Constraint: wire.ConstraintType})
 		}
 	}
 
@@ -134,11 +138,15 @@ func (t *TestControl) handleSetCommissioningWindowDuration(ctx context.Context,
 	if raw, exists := params["enableKey"]; exists {
 		if v, ok := raw.(string); ok {
 			req.EnableKey = v
+		} else if raw != nil {
+			return nil, wire.NewFieldError(wire.StatusInvalidParameter, wire.FieldError{Path: "enableKey", Message: "expected string", Constraint: wire.ConstraintType})
 		}
 	}
 	if raw, exists := params["durationSeconds"]; exists {
 		if v, ok := wire.ToUint32(raw); ok {
 			req.DurationSeconds = v
+		} else if raw != nil {
+			return nil, wire.NewFieldError(wire.StatusInvalidParameter, wire.FieldError{Path: "durationSeconds", Message: "expected uint32", Constraint: wire.ConstraintType})
 		}
 	}
 
@@ -0,0 +1,174 @@
+// Package fleet commissions and maintains a declared set of devices for a
+// controller. An installer describes every device once (discriminator,
+// setup code, label and the limits and subscriptions it should have) and a
+// Reconciler brings the controller's zone to that state: it commissions
+// devices that are missing, reconnects devices that dropped off and
+// re-applies desired state after every new session.
+//
+//	r := fleet.NewReconciler(fleet.ServiceController(svc), fleet.DefaultConfig())
+//	r.OnProgress(func(p fleet.Progress) { log.Printf("%s: %s", p.Label, p.Phase) })
+//	_ = r.SetDesired(devices)
+//	go r.Run(ctx)
+//
+// Devices are matched to fleet entries by label. The label is passed to
+// CommissionDevice, which persists it with the commissioned device
+// (ControllerStateStore), so a restarted controller reconnects to the
+// devices it already commissioned instead of commissioning them again.
+package fleet
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/mash-protocol/mash-go/pkg/commissioning"
+	"github.com/mash-protocol/mash-go/pkg/features"
+	"github.com/mash-protocol/mash-go/pkg/model"
+)
+
+// ErrInvalidDevice is returned when a fleet entry cannot be reconciled.
+var ErrInvalidDevice = errors.New("invalid fleet device")
+
+// Device is the desired state of one device in the fleet.
+type Device struct {
+	// Label identifies the entry and the device commissioned for it
+	// (e.g. "bay-07"). Labels are unique within a fleet.
+	Label string
+
+	// Discriminator is the device discriminator from the QR code.
+	Discriminator uint16
+
+	// SetupCode is the 8-digit setup code from the QR code.
+	SetupCode string
+
+	// Limits are applied after every new session. Nil leaves the device's
+	// limits alone.
+	Limits *Limits
+
+	// Subscriptions are established after every new session.
+	Subscriptions []Subscription
+}
+
+// Limits is a power limit to hold on a device's EnergyControl feature.
+type Limits struct {
+	// EndpointID is the endpoint with the EnergyControl feature.
+	EndpointID uint8
+
+	// ConsumptionLimit is the maximum consumption in mW (nil = none).
+	ConsumptionLimit *int64
+
+	// ProductionLimit is the maximum production in mW (nil = none).
+	ProductionLimit *int64
+
+	// Cause is sent with the limit.
+	Cause features.LimitCause
+}
+
+// Subscription is an attribute subscription to hold on a device.
+type Subscription struct {
+	// EndpointID is the endpoint to subscribe on.
+	EndpointID uint8
+
+	// Feature is the feature to subscribe to.
+	Feature model.FeatureType
+
+	// AttributeIDs limits the subscription (empty = all attributes).
+	AttributeIDs []uint16
+
+	// MinInterval is the minimum time between notifications.
+	MinInterval time.Duration
+
+	// MaxInterval is the maximum time without a notification.
+	MaxInterval time.Duration
+}
+
+// Validate checks that the entry can be commissioned.
+func (d *Device) Validate() error {
+	if d.Label == "" {
+		return fmt.Errorf("%w: missing label", ErrInvalidDevice)
+	}
+	if d.Discriminator > commissioning.DiscriminatorMax {
+		return fmt.Errorf("%w %q: discriminator %d out of range", ErrInvalidDevice, d.Label, d.Discriminator)
+	}
+	sc, err := commissioning.ParseSetupCode(d.SetupCode)
+	if err == nil {
+		err = sc.Validate()
+	}
+	if err != nil {
+		return fmt.Errorf("%w %q: %v", ErrInvalidDevice, d.Label, err)
+	}
+	if l := d.Limits; l != nil && l.ConsumptionLimit == nil && l.ProductionLimit == nil {
+		return fmt.Errorf("%w %q: limits set but empty", ErrInvalidDevice, d.Label)
+	}
+	return nil
+}
+
+// Phase is where a fleet device is in reconciliation.
+type Phase uint8
+
+const (
+	// PhasePending - not yet reconciled.
+	PhasePending Phase = iota
+
+	// PhaseCommissioning - being commissioned (including waiting for a
+	// pairing request to be answered).
+	PhaseCommissioning
+
+	// PhaseReconnecting - commissioned but not connected; reconnecting.
+	PhaseReconnecting
+
+	// PhaseApplying - connected; applying limits and subscriptions.
+	PhaseApplying
+
+	// PhaseReady - connected with the desired state applied.
+	PhaseReady
+
+	// PhaseFailed - the last attempt failed. It is retried on the next pass.
+	PhaseFailed
+)
+
+// String returns the phase name.
+func (p Phase) String() string {
+	switch p {
+	case PhasePending:
+		return "PENDING"
+	case PhaseCommissioning:
+		return "COMMISSIONING"
+	case PhaseReconnecting:
+		return "RECONNECTING"
+	case PhaseApplying:
+		return "APPLYING"
+	case PhaseReady:
+		return "READY"
+	case PhaseFailed:
+		return "FAILED"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+// Status is the reconciliation state of one fleet device.
+type Status struct {
+	// Label is the fleet entry.
+	Label string
+
+	// DeviceID is the device commissioned for the entry (empty until then).
+	DeviceID string
+
+	// Phase is the current phase.
+	Phase Phase
+
+	// Error is the reason for PhaseFailed.
+	Error error
+}
+
+// Progress reports a phase change of one fleet device.
+type Progress struct {
+	Status
+
+	// Ready is the number of fleet devices in PhaseReady after the change.
+	Ready int
+
+	// Total is the number of devices in the fleet.
+	Total int
+}
@@ -0,0 +1,388 @@
+package fleet
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/mash-protocol/mash-go/pkg/clock"
+	"github.com/mash-protocol/mash-go/pkg/featureclient"
+	"github.com/mash-protocol/mash-go/pkg/features"
+	"github.com/mash-protocol/mash-go/pkg/interaction"
+	"github.com/mash-protocol/mash-go/pkg/service"
+)
+
+// ErrNotConnected is returned when a device has no session after reconnecting.
+var ErrNotConnected = errors.New("device not connected")
+
+// Controller is the part of a controller the Reconciler drives.
+// ServiceController adapts a service.ControllerService.
+type Controller interface {
+	// CommissionDevice commissions a device and stores label with it.
+	CommissionDevice(ctx context.Context, discriminator uint16, setupCode, label string) (*service.ConnectedDevice, error)
+	Reconnect(ctx context.Context, deviceID string) error
+	GetAllDevices() []*service.ConnectedDevice
+
+	// Session returns the device's current session, or nil.
+	Session(deviceID string) featureclient.DeviceClient
+}
+
+// ServiceController adapts a ControllerService to Controller.
+func ServiceController(svc *service.ControllerService) Controller {
+	return serviceController{svc}
+}
+
+type serviceController struct {
+	*service.ControllerService
+}
+
+// Session implements Controller.
+func (c serviceController) Session(deviceID string) featureclient.DeviceClient {
+	if session := c.GetSession(deviceID); session != nil {
+		return session
+	}
+	return nil
+}
+
+// Config configures a Reconciler.
+type Config struct {
+	// Concurrency is how many devices are reconciled at once. Devices with
+	// the same discriminator are always commissioned one at a time.
+	// Default: 4.
+	Concurrency int
+
+	// Interval is how often Run starts a reconciliation pass. Default: 30s.
+	Interval time.Duration
+
+	// OperationTimeout bounds reconnecting and applying desired state.
+	// Commissioning is bounded by the controller's PairingRequestTimeout
+	// instead. Default: 30s.
+	OperationTimeout time.Duration
+
+	// Clock is the time source for Run. Nil uses the real clock.
+	Clock clock.Clock
+}
+
+// DefaultConfig returns a Config with sensible defaults.
+func DefaultConfig() Config {
+	return Config{
+		Concurrency:      4,
+		Interval:         30 * time.Second,
+		OperationTimeout: 30 * time.Second,
+	}
+}
+
+// Reconciler brings a controller's devices to the desired fleet state.
+type Reconciler struct {
+	ctrl   Controller
+	config Config
+	clock  clock.Clock
+
+	// Serializes reconciliation passes
+	passMu sync.Mutex
+
+	mu       sync.Mutex
+	desired  []Device
+	status   map[string]*Status
+	applied  map[string]*appliedState
+	handlers []func(Progress)
+}
+
+// appliedState records what was applied to a device on which session, so
+// it is re-applied only after a reconnect or a change of desired state.
+type appliedState struct {
+	session         featureclient.DeviceClient
+	device          Device
+	subscriptionIDs []uint32
+}
+
+// NewReconciler creates a reconciler for ctrl with an empty fleet.
+func NewReconciler(ctrl Controller, config Config) *Reconciler {
+	defaults := DefaultConfig()
+	if config.Concurrency <= 0 {
+		config.Concurrency = defaults.Concurrency
+	}
+	if config.Interval <= 0 {
+		config.Interval = defaults.Interval
+	}
+	if config.OperationTimeout <= 0 {
+		config.OperationTimeout = defaults.OperationTimeout
+	}
+	return &Reconciler{
+		ctrl:    ctrl,
+		config:  config,
+		clock:   clock.OrReal(config.Clock),
+		status:  make(map[string]*Status),
+		applied: make(map[string]*appliedState),
+	}
+}
+
+// OnProgress registers a handler for phase changes. Handlers are called
+// synchronously from the reconciling goroutines and must not block.
+func (r *Reconciler) OnProgress(handler func(Progress)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.handlers = append(r.handlers, handler)
+}
+
+// SetDesired replaces the fleet. Devices already commissioned for a label
+// that is dropped stay in the zone; removing them is left to the caller.
+func (r *Reconciler) SetDesired(devices []Device) error {
+	labels := make(map[string]struct{}, len(devices))
+	for i := range devices {
+		if err := devices[i].Validate(); err != nil {
+			return err
+		}
+		if _, dup := labels[devices[i].Label]; dup {
+			return fmt.Errorf("%w: duplicate label %q", ErrInvalidDevice, devices[i].Label)
+		}
+		labels[devices[i].Label] = struct{}{}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.desired = append([]Device(nil), devices...)
+	for label := range r.status {
+		if _, ok := labels[label]; !ok {
+			delete(r.status, label)
+			delete(r.applied, label)
+		}
+	}
+	for _, d := range devices {
+		if _, ok := r.status[d.Label]; !ok {
+			r.status[d.Label] = &Status{Label: d.Label, Phase: PhasePending}
+		}
+	}
+	return nil
+}
+
+// Status returns the state of every fleet device in fleet order.
+func (r *Reconciler) Status() []Status {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	result := make([]Status, 0, len(r.desired))
+	for _, d := range r.desired {
+		result = append(result, *r.status[d.Label])
+	}
+	return result
+}
+
+// Run reconciles immediately and then every Interval until ctx is done.
+func (r *Reconciler) Run(ctx context.Context) error {
+	ticker := r.clock.NewTicker(r.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		_ = r.Reconcile(ctx)
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C():
+		}
+	}
+}
+
+// Reconcile runs one pass over the fleet and returns the errors of the
+// devices that failed. Failed devices are retried on the next pass.
+func (r *Reconciler) Reconcile(ctx context.Context) error {
+	r.passMu.Lock()
+	defer r.passMu.Unlock()
+
+	r.mu.Lock()
+	desired := append([]Device(nil), r.desired...)
+	r.mu.Unlock()
+
+	byLabel := make(map[string]*service.ConnectedDevice)
+	for _, device := range r.ctrl.GetAllDevices() {
+		if device.Label != "" {
+			byLabel[device.Label] = device
+		}
+	}
+
+	// A pairing request is per discriminator, so commission devices that
+	// share one in turn
+	discriminatorLocks := make(map[uint16]*sync.Mutex)
+	for _, d := range desired {
+		if discriminatorLocks[d.Discriminator] == nil {
+			discriminatorLocks[d.Discriminator] = &sync.Mutex{}
+		}
+	}
+
+	var (
+		wg     sync.WaitGroup
+		errsMu sync.Mutex
+		errs   []error
+		sem    = make(chan struct{}, r.config.Concurrency)
+	)
+	for _, d := range desired {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			wg.Wait()
+			return ctx.Err()
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			if err := r.reconcileDevice(ctx, d, byLabel[d.Label], discriminatorLocks[d.Discriminator]); err != nil {
+				errsMu.Lock()
+				errs = append(errs, fmt.Errorf("%s: %w", d.Label, err))
+				errsMu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	return errors.Join(errs...)
+}
+
+// reconcileDevice brings one fleet device to its desired state. device is
+// the controller's record for the entry, or nil if not yet commissioned.
+func (r *Reconciler) reconcileDevice(ctx context.Context, d Device, device *service.ConnectedDevice, discriminatorLock *sync.Mutex) error {
+	reconnect := device == nil || !device.Connected
+	if device == nil {
+		r.setPhase(d.Label, "", PhaseCommissioning, nil)
+
+		discriminatorLock.Lock()
+		// The label is persisted with the device, so it is found by label
+		// on the next pass and after a restart
+		commissioned, err := r.ctrl.CommissionDevice(ctx, d.Discriminator, d.SetupCode, d.Label)
+		discriminatorLock.Unlock()
+		if err != nil {
+			return r.fail(d.Label, "", fmt.Errorf("commission: %w", err))
+		}
+		device = commissioned
+	}
+
+	// A freshly commissioned device closes the commissioning connection and
+	// expects an operational one (DEC-066)
+	session := r.ctrl.Session(device.ID)
+	if reconnect || session == nil {
+		r.setPhase(d.Label, device.ID, PhaseReconnecting, nil)
+
+		opCtx, cancel := context.WithTimeout(ctx, r.config.OperationTimeout)
+		err := r.ctrl.Reconnect(opCtx, device.ID)
+		cancel()
+		if err != nil {
+			return r.fail(d.Label, device.ID, fmt.Errorf("reconnect: %w", err))
+		}
+		if session = r.ctrl.Session(device.ID); session == nil {
+			return r.fail(d.Label, device.ID, ErrNotConnected)
+		}
+	}
+
+	r.mu.Lock()
+	prev := r.applied[d.Label]
+	r.mu.Unlock()
+	if prev != nil && prev.session == session && reflect.DeepEqual(prev.device, d) {
+		r.setPhase(d.Label, device.ID, PhaseReady, nil)
+		return nil
+	}
+
+	r.setPhase(d.Label, device.ID, PhaseApplying, nil)
+
+	opCtx, cancel := context.WithTimeout(ctx, r.config.OperationTimeout)
+	defer cancel()
+
+	// Subscriptions on the same session are replaced; a new session starts
+	// without any
+	if prev != nil && prev.session == session {
+		for _, id := range prev.subscriptionIDs {
+			_ = session.Unsubscribe(opCtx, id)
+		}
+	}
+
+	state := &appliedState{session: session, device: d}
+	if err := applyLimits(opCtx, session, d.Limits); err != nil {
+		return r.fail(d.Label, device.ID, fmt.Errorf("apply limits: %w", err))
+	}
+	for _, sub := range d.Subscriptions {
+		id, _, err := session.Subscribe(opCtx, sub.EndpointID, uint8(sub.Feature), &interaction.SubscribeOptions{
+			AttributeIDs: sub.AttributeIDs,
+			MinInterval:  sub.MinInterval,
+			MaxInterval:  sub.MaxInterval,
+		})
+		if err != nil {
+			return r.fail(d.Label, device.ID, fmt.Errorf("subscribe to %s: %w", sub.Feature, err))
+		}
+		state.subscriptionIDs = append(state.subscriptionIDs, id)
+	}
+
+	r.mu.Lock()
+	r.applied[d.Label] = state
+	r.mu.Unlock()
+
+	r.setPhase(d.Label, device.ID, PhaseReady, nil)
+	return nil
+}
+
+// applyLimits sets the desired limits. A limit the device does not apply
+// is an error.
+func applyLimits(ctx context.Context, session featureclient.DeviceClient, limits *Limits) error {
+	if limits == nil {
+		return nil
+	}
+
+	ec := featureclient.NewEnergyControlClient(session, limits.EndpointID)
+	resp, err := ec.SetLimit(ctx, features.SetLimitRequest{
+		ConsumptionLimit: limits.ConsumptionLimit,
+		ProductionLimit:  limits.ProductionLimit,
+		Cause:            limits.Cause,
+	})
+	if err != nil {
+		return err
+	}
+	if !resp.Applied {
+		if resp.RejectReason != nil {
+			return fmt.Errorf("rejected: %s", *resp.RejectReason)
+		}
+		return errors.New("rejected")
+	}
+	return nil
+}
+
+// fail records PhaseFailed and returns err.
+func (r *Reconciler) fail(label, deviceID string, err error) error {
+	r.setPhase(label, deviceID, PhaseFailed, err)
+	return err
+}
+
+// setPhase updates a device's status and notifies handlers if it changed.
+func (r *Reconciler) setPhase(label, deviceID string, phase Phase, err error) {
+	r.mu.Lock()
+	status, ok := r.status[label]
+	if !ok {
+		// Dropped from the fleet while being reconciled
+		r.mu.Unlock()
+		return
+	}
+	if status.Phase == phase && status.DeviceID == deviceID && status.Error == err {
+		r.mu.Unlock()
+		return
+	}
+	status.Phase = phase
+	status.DeviceID = deviceID
+	status.Error = err
+
+	progress := Progress{Status: *status, Total: len(r.desired)}
+	for _, s := range r.status {
+		if s.Phase == PhaseReady {
+			progress.Ready++
+		}
+	}
+	handlers := r.handlers
+	r.mu.Unlock()
+
+	for _, handler := range handlers {
+		handler(progress)
+	}
+}
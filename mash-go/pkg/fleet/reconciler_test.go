@@ -0,0 +1,465 @@
+package fleet
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mash-protocol/mash-go/pkg/clock"
+	"github.com/mash-protocol/mash-go/pkg/featureclient"
+	"github.com/mash-protocol/mash-go/pkg/features"
+	"github.com/mash-protocol/mash-go/pkg/interaction"
+	"github.com/mash-protocol/mash-go/pkg/model"
+	"github.com/mash-protocol/mash-go/pkg/service"
+)
+
+// fakeSession records the commands and subscriptions it receives.
+type fakeSession struct {
+	mu            sync.Mutex
+	limits        []map[string]any
+	subscriptions []uint8
+	unsubscribed  []uint32
+	nextSubID     uint32
+}
+
+func (s *fakeSession) Read(ctx context.Context, endpointID uint8, featureID uint8, attrIDs []uint16) (map[uint16]any, error) {
+	return map[uint16]any{}, nil
+}
+
+func (s *fakeSession) Write(ctx context.Context, endpointID uint8, featureID uint8, attrs map[uint16]any) (map[uint16]any, error) {
+	return attrs, nil
+}
+
+func (s *fakeSession) Subscribe(ctx context.Context, endpointID uint8, featureID uint8, opts *interaction.SubscribeOptions) (uint32, map[uint16]any, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.nextSubID++
+	s.subscriptions = append(s.subscriptions, featureID)
+	return s.nextSubID, map[uint16]any{}, nil
+}
+
+func (s *fakeSession) Unsubscribe(ctx context.Context, subscriptionID uint32) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.unsubscribed = append(s.unsubscribed, subscriptionID)
+	return nil
+}
+
+func (s *fakeSession) Invoke(ctx context.Context, endpointID uint8, featureID uint8, commandID uint8, params map[string]any) (any, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if featureID != uint8(model.FeatureEnergyControl) || commandID != features.EnergyControlCmdSetLimit {
+		return nil, fmt.Errorf("unexpected command %d/%d", featureID, commandID)
+	}
+	s.limits = append(s.limits, params)
+	return map[string]any{"applied": true, "controlState": uint8(features.ControlStateLimited)}, nil
+}
+
+func (s *fakeSession) counts() (limits, subscriptions int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.limits), len(s.subscriptions)
+}
+
+// fakeController commissions devices instantly (after delay) and tracks
+// how many commissionings run at once.
+type fakeController struct {
+	mu       sync.Mutex
+	delay    time.Duration
+	devices  map[string]*service.ConnectedDevice
+	sessions map[string]*fakeSession
+	nextID   int
+
+	commissioned int
+	reconnects   int
+	active       int
+	maxActive    int
+	activeByDisc map[uint16]int
+	discOverlap  bool
+
+	// failCommission makes the next commissioning of a discriminator fail.
+	failCommission map[uint16]bool
+}
+
+func newFakeController() *fakeController {
+	return &fakeController{
+		devices:        make(map[string]*service.ConnectedDevice),
+		sessions:       make(map[string]*fakeSession),
+		activeByDisc:   make(map[uint16]int),
+		failCommission: make(map[uint16]bool),
+	}
+}
+
+func (c *fakeController) CommissionDevice(ctx context.Context, discriminator uint16, setupCode, label string) (*service.ConnectedDevice, error) {
+	c.mu.Lock()
+	c.active++
+	c.maxActive = max(c.maxActive, c.active)
+	c.activeByDisc[discriminator]++
+	if c.activeByDisc[discriminator] > 1 {
+		c.discOverlap = true
+	}
+	fail := c.failCommission[discriminator]
+	delete(c.failCommission, discriminator)
+	c.mu.Unlock()
+
+	time.Sleep(c.delay)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.active--
+	c.activeByDisc[discriminator]--
+	if fail {
+		return nil, service.ErrPairingRequestTimeout
+	}
+
+	c.nextID++
+	c.commissioned++
+	device := &service.ConnectedDevice{ID: fmt.Sprintf("device-%02d", c.nextID), Label: label}
+	c.devices[device.ID] = device
+	copy := *device
+	return &copy, nil
+}
+
+func (c *fakeController) Reconnect(ctx context.Context, deviceID string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	device, ok := c.devices[deviceID]
+	if !ok {
+		return service.ErrDeviceNotFound
+	}
+	c.reconnects++
+	device.Connected = true
+	c.sessions[deviceID] = &fakeSession{}
+	return nil
+}
+
+func (c *fakeController) GetAllDevices() []*service.ConnectedDevice {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var result []*service.ConnectedDevice
+	for _, d := range c.devices {
+		copy := *d
+		result = append(result, &copy)
+	}
+	return result
+}
+
+func (c *fakeController) Session(deviceID string) featureclient.DeviceClient {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if s, ok := c.sessions[deviceID]; ok {
+		return s
+	}
+	return nil
+}
+
+// disconnect drops a device's session.
+func (c *fakeController) disconnect(deviceID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.devices[deviceID].Connected = false
+	delete(c.sessions, deviceID)
+}
+
+func (c *fakeController) session(deviceID string) *fakeSession {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.sessions[deviceID]
+}
+
+func testFleet(n int) []Device {
+	limit := int64(11_000_000)
+	devices := make([]Device, n)
+	for i := range devices {
+		devices[i] = Device{
+			Label:         fmt.Sprintf("bay-%02d", i),
+			Discriminator: uint16(100 + i),
+			SetupCode:     "20202021",
+			Limits:        &Limits{EndpointID: 1, ConsumptionLimit: &limit, Cause: features.LimitCauseLocalProtection},
+			Subscriptions: []Subscription{{EndpointID: 1, Feature: model.FeatureMeasurement}},
+		}
+	}
+	return devices
+}
+
+func TestReconciler_CommissionsFleet(t *testing.T) {
+	ctrl := newFakeController()
+	ctrl.delay = 10 * time.Millisecond
+
+	r := NewReconciler(ctrl, Config{Concurrency: 3})
+	if err := r.SetDesired(testFleet(10)); err != nil {
+		t.Fatal(err)
+	}
+
+	var (
+		progressMu sync.Mutex
+		last       Progress
+	)
+	r.OnProgress(func(p Progress) {
+		progressMu.Lock()
+		defer progressMu.Unlock()
+		if p.Ready >= last.Ready {
+			last = p
+		}
+	})
+
+	if err := r.Reconcile(context.Background()); err != nil {
+		t.Fatalf("Reconcile failed: %v", err)
+	}
+
+	if ctrl.commissioned != 10 {
+		t.Errorf("commissioned %d devices, want 10", ctrl.commissioned)
+	}
+	if ctrl.maxActive > 3 {
+		t.Errorf("%d commissionings ran at once, want at most 3", ctrl.maxActive)
+	}
+	if last.Ready != 10 || last.Total != 10 {
+		t.Errorf("last progress = %d/%d ready, want 10/10", last.Ready, last.Total)
+	}
+
+	for _, s := range r.Status() {
+		if s.Phase != PhaseReady || s.DeviceID == "" {
+			t.Errorf("%s: status %s/%q, want READY with a device", s.Label, s.Phase, s.DeviceID)
+			continue
+		}
+		limits, subs := ctrl.session(s.DeviceID).counts()
+		if limits != 1 || subs != 1 {
+			t.Errorf("%s: %d limits, %d subscriptions applied, want 1 and 1", s.Label, limits, subs)
+		}
+	}
+	for _, d := range ctrl.GetAllDevices() {
+		if d.Label == "" {
+			t.Errorf("device %s not labelled", d.ID)
+		}
+	}
+
+	// A second pass finds everything in place
+	if err := r.Reconcile(context.Background()); err != nil {
+		t.Fatalf("second Reconcile failed: %v", err)
+	}
+	if ctrl.commissioned != 10 || ctrl.reconnects != 10 {
+		t.Errorf("second pass commissioned/reconnected again (%d/%d)", ctrl.commissioned, ctrl.reconnects)
+	}
+	for _, s := range r.Status() {
+		if limits, _ := ctrl.session(s.DeviceID).counts(); limits != 1 {
+			t.Errorf("%s: limits applied %d times, want 1", s.Label, limits)
+		}
+	}
+}
+
+func TestReconciler_ReappliesAfterReconnect(t *testing.T) {
+	ctrl := newFakeController()
+	r := NewReconciler(ctrl, DefaultConfig())
+	if err := r.SetDesired(testFleet(2)); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Reconcile(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	dropped := r.Status()[0].DeviceID
+	ctrl.disconnect(dropped)
+
+	if err := r.Reconcile(context.Background()); err != nil {
+		t.Fatalf("Reconcile failed: %v", err)
+	}
+	if ctrl.reconnects != 3 {
+		t.Errorf("reconnects = %d, want 3", ctrl.reconnects)
+	}
+	if limits, subs := ctrl.session(dropped).counts(); limits != 1 || subs != 1 {
+		t.Errorf("new session got %d limits, %d subscriptions, want 1 and 1", limits, subs)
+	}
+}
+
+func TestReconciler_DesiredStateChange(t *testing.T) {
+	ctrl := newFakeController()
+	r := NewReconciler(ctrl, DefaultConfig())
+	fleet := testFleet(1)
+	if err := r.SetDesired(fleet); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Reconcile(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	lower := int64(6_000_000)
+	fleet[0].Limits = &Limits{EndpointID: 1, ConsumptionLimit: &lower}
+	if err := r.SetDesired(fleet); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Reconcile(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	session := ctrl.session(r.Status()[0].DeviceID)
+	if len(session.limits) != 2 || session.limits[1]["consumptionLimit"] != lower {
+		t.Errorf("limits sent = %v, want the lowered limit last", session.limits)
+	}
+	// The old subscription is replaced, not duplicated
+	if len(session.unsubscribed) != 1 || len(session.subscriptions) != 2 {
+		t.Errorf("unsubscribed %v, subscribed %v; want one replaced", session.unsubscribed, session.subscriptions)
+	}
+}
+
+func TestReconciler_RetriesFailures(t *testing.T) {
+	ctrl := newFakeController()
+	ctrl.failCommission[101] = true
+
+	r := NewReconciler(ctrl, DefaultConfig())
+	if err := r.SetDesired(testFleet(2)); err != nil {
+		t.Fatal(err)
+	}
+
+	err := r.Reconcile(context.Background())
+	if !errors.Is(err, service.ErrPairingRequestTimeout) {
+		t.Fatalf("Reconcile error = %v, want pairing request timeout", err)
+	}
+	status := r.Status()
+	if status[0].Phase != PhaseReady || status[1].Phase != PhaseFailed || status[1].Error == nil {
+		t.Errorf("status = %+v, want bay-00 READY and bay-01 FAILED", status)
+	}
+
+	if err := r.Reconcile(context.Background()); err != nil {
+		t.Fatalf("retry failed: %v", err)
+	}
+	if status := r.Status(); status[1].Phase != PhaseReady || status[1].Error != nil {
+		t.Errorf("bay-01 after retry = %+v, want READY", status[1])
+	}
+}
+
+func TestReconciler_LabelCommissionedWithDevice(t *testing.T) {
+	ctrl := newFakeController()
+
+	r := NewReconciler(ctrl, DefaultConfig())
+	if err := r.SetDesired(testFleet(1)); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Reconcile(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if d := ctrl.GetAllDevices()[0]; d.Label != "bay-00" {
+		t.Errorf("device label = %q, want bay-00", d.Label)
+	}
+
+	// A new reconciler, as after a restart, finds the device by its label
+	ctrl.disconnect("device-01")
+	restarted := NewReconciler(ctrl, DefaultConfig())
+	if err := restarted.SetDesired(testFleet(1)); err != nil {
+		t.Fatal(err)
+	}
+	if err := restarted.Reconcile(context.Background()); err != nil {
+		t.Fatalf("Reconcile after restart failed: %v", err)
+	}
+	if ctrl.commissioned != 1 {
+		t.Errorf("commissioned %d devices, want 1", ctrl.commissioned)
+	}
+	if s := restarted.Status()[0]; s.Phase != PhaseReady || s.DeviceID != "device-01" {
+		t.Errorf("status = %+v, want device-01 READY", s)
+	}
+}
+
+func TestReconciler_RunRetriesEveryInterval(t *testing.T) {
+	ctrl := newFakeController()
+	ctrl.failCommission[100] = true
+
+	clk := clock.NewFake(time.Now())
+	r := NewReconciler(ctrl, Config{Interval: time.Minute, Clock: clk})
+	if err := r.SetDesired(testFleet(1)); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- r.Run(ctx) }()
+
+	waitPhase := func(want Phase) {
+		t.Helper()
+		deadline := time.Now().Add(2 * time.Second)
+		for r.Status()[0].Phase != want {
+			if time.Now().After(deadline) {
+				t.Fatalf("phase = %s, want %s", r.Status()[0].Phase, want)
+			}
+			time.Sleep(time.Millisecond)
+		}
+	}
+	waitPhase(PhaseFailed)
+
+	clk.Advance(time.Minute)
+	waitPhase(PhaseReady)
+
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Errorf("Run returned %v, want context.Canceled", err)
+	}
+}
+
+func TestReconciler_KnownDevicesAreReconnected(t *testing.T) {
+	ctrl := newFakeController()
+	// Restored from ControllerStateStore: labelled but not connected
+	ctrl.devices["device-07"] = &service.ConnectedDevice{ID: "device-07", Label: "bay-00"}
+
+	r := NewReconciler(ctrl, DefaultConfig())
+	if err := r.SetDesired(testFleet(1)); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Reconcile(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if ctrl.commissioned != 0 || ctrl.reconnects != 1 {
+		t.Errorf("commissioned %d, reconnected %d; want 0 and 1", ctrl.commissioned, ctrl.reconnects)
+	}
+	if s := r.Status()[0]; s.DeviceID != "device-07" || s.Phase != PhaseReady {
+		t.Errorf("status = %+v, want device-07 READY", s)
+	}
+}
+
+func TestReconciler_SharedDiscriminator(t *testing.T) {
+	ctrl := newFakeController()
+	ctrl.delay = 10 * time.Millisecond
+
+	fleet := testFleet(4)
+	for i := range fleet {
+		fleet[i].Discriminator = 1234
+	}
+	r := NewReconciler(ctrl, Config{Concurrency: 4})
+	if err := r.SetDesired(fleet); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Reconcile(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if ctrl.discOverlap {
+		t.Error("devices sharing a discriminator were commissioned concurrently")
+	}
+	if ctrl.commissioned != 4 {
+		t.Errorf("commissioned %d, want 4", ctrl.commissioned)
+	}
+}
+
+func TestReconciler_SetDesiredValidation(t *testing.T) {
+	r := NewReconciler(newFakeController(), DefaultConfig())
+
+	tests := []struct {
+		name   string
+		modify func([]Device)
+	}{
+		{"missing label", func(d []Device) { d[0].Label = "" }},
+		{"duplicate label", func(d []Device) { d[1].Label = d[0].Label }},
+		{"bad setup code", func(d []Device) { d[0].SetupCode = "123" }},
+		{"discriminator out of range", func(d []Device) { d[0].Discriminator = 0x1000 }},
+		{"empty limits", func(d []Device) { d[0].Limits = &Limits{EndpointID: 1} }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fleet := testFleet(2)
+			tt.modify(fleet)
+			if err := r.SetDesired(fleet); !errors.Is(err, ErrInvalidDevice) {
+				t.Errorf("SetDesired error = %v, want ErrInvalidDevice", err)
+			}
+		})
+	}
+}
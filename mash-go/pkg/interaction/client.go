@@ -7,6 +7,8 @@ import (
 	"sync/atomic"
 	"time"
 
+	"github.com/mash-protocol/mash-go/pkg/clock"
+	"github.com/mash-protocol/mash-go/pkg/model"
 	"github.com/mash-protocol/mash-go/pkg/wire"
 )
 
@@ -29,6 +31,12 @@ type Client struct {
 
 	sender  RequestSender
 	timeout time.Duration
+	retry   RetryPolicy
+	clock   clock.Clock
+
+	// In-flight window; nil means unlimited
+	window chan struct{}
+	done   chan struct{}
 
 	// Message ID generator
 	nextMsgID uint32
@@ -37,8 +45,14 @@ type Client struct {
 	pending   map[uint32]chan *wire.Response
 	pendingMu sync.RWMutex
 
-	// Notification handler
+	// Partially received chunked responses
+	chunks *wire.ChunkAssembler
+
+	// Notification handlers
 	notifyHandler func(*wire.Notification)
+	eventHandler  func(*wire.EventNotification)
+
+	stats clientStats
 
 	closed bool
 }
@@ -46,23 +60,104 @@ type Client struct {
 // DefaultRequestTimeout is the default timeout for requests (per DEC-044).
 const DefaultRequestTimeout = 10 * time.Second
 
+// RetryPolicy controls how requests answered with StatusBusy are retried.
+// Each retry is a new request with a new MessageID. A retry is only made if
+// its backoff ends before the call's deadline. Only Read and Subscribe are
+// retried unless NonIdempotent is set.
+type RetryPolicy struct {
+	// MaxRetries is the maximum number of retries per call (0 = no retry).
+	MaxRetries int
+
+	// NonIdempotent also retries Write and Invoke, which may not be safe
+	// to repeat.
+	NonIdempotent bool
+
+	// InitialBackoff is the wait before the first retry. It doubles with
+	// each further retry up to MaxBackoff.
+	InitialBackoff time.Duration
+
+	// MaxBackoff caps the exponential backoff.
+	MaxBackoff time.Duration
+}
+
+// DefaultRetryPolicy is the retry policy of a new client.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxRetries:     3,
+	InitialBackoff: 100 * time.Millisecond,
+	MaxBackoff:     2 * time.Second,
+}
+
+// retries reports whether requests with operation op are retried.
+func (p RetryPolicy) retries(op wire.Operation) bool {
+	if p.MaxRetries <= 0 {
+		return false
+	}
+	return p.NonIdempotent || op == wire.OpRead || op == wire.OpSubscribe
+}
+
+// backoff returns the wait before retry number attempt+1. A retry-after
+// hint from the device takes precedence over the computed backoff.
+func (p RetryPolicy) backoff(attempt int, hint time.Duration) time.Duration {
+	if hint > 0 {
+		return hint
+	}
+	d := p.InitialBackoff << attempt
+	if d <= 0 || d > p.MaxBackoff {
+		d = p.MaxBackoff
+	}
+	return d
+}
+
 // NewClient creates a new interaction client.
 func NewClient(sender RequestSender) *Client {
 	return &Client{
 		sender:    sender,
 		timeout:   DefaultRequestTimeout,
+		retry:     DefaultRetryPolicy,
+		clock:     clock.Real(),
+		done:      make(chan struct{}),
 		nextMsgID: 1,
 		pending:   make(map[uint32]chan *wire.Response),
+		chunks:    wire.NewChunkAssembler(),
 	}
 }
 
-// SetTimeout sets the request timeout.
+// SetTimeout sets the request timeout applied to calls whose context has
+// no deadline. A context deadline always takes precedence.
 func (c *Client) SetTimeout(timeout time.Duration) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 	c.timeout = timeout
 }
 
+// SetMaxInFlight limits the number of requests awaiting a response.
+// Further calls queue until a slot frees up or their context ends.
+// Zero removes the limit.
+func (c *Client) SetMaxInFlight(n int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if n <= 0 {
+		c.window = nil
+		return
+	}
+	c.window = make(chan struct{}, n)
+}
+
+// SetRetryPolicy sets how requests answered with StatusBusy are retried.
+// The zero RetryPolicy disables retries.
+func (c *Client) SetRetryPolicy(policy RetryPolicy) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.retry = policy
+}
+
+// SetClock sets the time source for retry backoff and latency statistics.
+func (c *Client) SetClock(clk clock.Clock) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.clock = clock.OrReal(clk)
+}
+
 // SetNotificationHandler sets the handler for incoming notifications.
 func (c *Client) SetNotificationHandler(handler func(*wire.Notification)) {
 	c.mu.Lock()
@@ -70,12 +165,24 @@ func (c *Client) SetNotificationHandler(handler func(*wire.Notification)) {
 	c.notifyHandler = handler
 }
 
+// SetEventHandler sets the handler for incoming event notifications.
+// Events replayed in a Subscribe response are delivered to the same handler.
+func (c *Client) SetEventHandler(handler func(*wire.EventNotification)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.eventHandler = handler
+}
+
 // Close closes the client.
 func (c *Client) Close() error {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
+	if c.closed {
+		return nil
+	}
 	c.closed = true
+	close(c.done)
 
 	// Cancel all pending requests
 	c.pendingMu.Lock()
@@ -100,7 +207,9 @@ func (c *Client) nextMessageID() uint32 {
 	}
 }
 
-// sendRequest sends a request and waits for the response.
+// sendRequest sends a request and waits for the response, retrying on
+// StatusBusy per the retry policy. The call's deadline is taken from ctx;
+// the client timeout applies only if ctx has none.
 func (c *Client) sendRequest(ctx context.Context, req *wire.Request) (*wire.Response, error) {
 	c.mu.RLock()
 	if c.closed {
@@ -108,8 +217,63 @@ func (c *Client) sendRequest(ctx context.Context, req *wire.Request) (*wire.Resp
 		return nil, ErrClientClosed
 	}
 	timeout := c.timeout
+	policy := c.retry
+	clk := c.clock
 	c.mu.RUnlock()
 
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeoutCause(ctx, timeout, ErrRequestTimeout)
+		defer cancel()
+	}
+
+	for attempt := 0; ; attempt++ {
+		if attempt > 0 {
+			req.MessageID = c.nextMessageID()
+		}
+		resp, err := c.roundTrip(ctx, clk, req)
+		if err != nil {
+			return nil, err
+		}
+		if resp.Status != wire.StatusBusy || !policy.retries(req.Operation) || attempt >= policy.MaxRetries {
+			return resp, nil
+		}
+
+		wait := policy.backoff(attempt, retryAfter(resp.Payload))
+		if deadline, _ := ctx.Deadline(); deadline.Sub(clk.Now()) <= wait {
+			return resp, nil
+		}
+		c.stats.retry()
+
+		elapsed := make(chan struct{})
+		timer := clk.AfterFunc(wait, func() { close(elapsed) })
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, c.contextError(ctx)
+		case <-c.done:
+			timer.Stop()
+			return nil, ErrClientClosed
+		case <-elapsed:
+		}
+	}
+}
+
+// roundTrip sends one request and waits for its response, first waiting
+// for a slot in the in-flight window.
+func (c *Client) roundTrip(ctx context.Context, clk clock.Clock, req *wire.Request) (*wire.Response, error) {
+	window, err := c.acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	c.stats.begin()
+	defer func() {
+		c.stats.end()
+		if window != nil {
+			<-window
+		}
+	}()
+
 	// Create response channel
 	respCh := make(chan *wire.Response, 1)
 
@@ -121,6 +285,7 @@ func (c *Client) sendRequest(ctx context.Context, req *wire.Request) (*wire.Resp
 		c.pendingMu.Lock()
 		delete(c.pending, req.MessageID)
 		c.pendingMu.Unlock()
+		c.chunks.Discard(req.MessageID)
 	}()
 
 	// Encode and send request
@@ -129,34 +294,92 @@ func (c *Client) sendRequest(ctx context.Context, req *wire.Request) (*wire.Resp
 		return nil, err
 	}
 
+	start := clk.Now()
 	if err := c.sender.Send(data); err != nil {
 		return nil, err
 	}
 
-	// Wait for response with timeout
+	// Wait for response until the call's deadline
 	select {
 	case <-ctx.Done():
-		return nil, ctx.Err()
-	case <-time.After(timeout):
-		return nil, ErrRequestTimeout
+		return nil, c.contextError(ctx)
 	case resp, ok := <-respCh:
 		if !ok {
 			return nil, ErrClientClosed
 		}
+		c.stats.response(resp.Status, clk.Since(start))
 		return resp, nil
 	}
 }
 
+// acquire waits for a slot in the in-flight window and returns the window
+// to release it to, or nil if there is no limit.
+func (c *Client) acquire(ctx context.Context) (chan struct{}, error) {
+	c.mu.RLock()
+	window := c.window
+	c.mu.RUnlock()
+
+	if window == nil {
+		return nil, nil
+	}
+	select {
+	case window <- struct{}{}:
+		return window, nil
+	default:
+	}
+
+	c.stats.queue(1)
+	defer c.stats.queue(-1)
+	select {
+	case window <- struct{}{}:
+		return window, nil
+	case <-ctx.Done():
+		return nil, c.contextError(ctx)
+	case <-c.done:
+		return nil, ErrClientClosed
+	}
+}
+
+// contextError returns the error for a call whose context ended, counting
+// deadline expiry as a timeout. The client timeout surfaces as
+// ErrRequestTimeout.
+func (c *Client) contextError(ctx context.Context) error {
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		c.stats.timeout()
+	}
+	return context.Cause(ctx)
+}
+
+// retryAfter returns the retry-after hint carried in an error payload.
+func retryAfter(payload any) time.Duration {
+	if ep := wire.ExtractErrorPayload(payload); ep != nil {
+		return time.Duration(ep.RetryAfter) * time.Millisecond
+	}
+	return 0
+}
+
 // HandleResponse should be called when a response is received.
+// Chunks of a chunked response are reassembled; the request completes when
+// the last chunk arrives.
 func (c *Client) HandleResponse(resp *wire.Response) error {
 	c.pendingMu.RLock()
 	ch, exists := c.pending[resp.MessageID]
 	c.pendingMu.RUnlock()
 
 	if !exists {
+		c.chunks.Discard(resp.MessageID)
 		return ErrUnexpectedReply
 	}
 
+	resp, err := c.chunks.Add(resp)
+	if err != nil {
+		return err
+	}
+	if resp == nil {
+		// More chunks to come
+		return nil
+	}
+
 	select {
 	case ch <- resp:
 	default:
@@ -176,6 +399,17 @@ func (c *Client) HandleNotification(notif *wire.Notification) {
 	}
 }
 
+// HandleEventNotification should be called when an event notification is received.
+func (c *Client) HandleEventNotification(notif *wire.EventNotification) {
+	c.mu.RLock()
+	handler := c.eventHandler
+	c.mu.RUnlock()
+
+	if handler != nil {
+		handler(notif)
+	}
+}
+
 // Read reads attributes from a feature.
 // If attrIDs is nil or empty, all attributes are read.
 func (c *Client) Read(ctx context.Context, endpointID uint8, featureID uint8, attrIDs []uint16) (map[uint16]any, error) {
@@ -220,6 +454,24 @@ func (c *Client) Read(ctx context.Context, endpointID uint8, featureID uint8, at
 
 // Write writes attributes to a feature.
 func (c *Client) Write(ctx context.Context, endpointID uint8, featureID uint8, attrs map[uint16]any) (map[uint16]any, error) {
+	return c.write(ctx, endpointID, featureID, attrs)
+}
+
+// WriteIfVersion writes attributes to a feature only if the feature's data
+// version still equals version, as read from its dataVersion attribute.
+// On a mismatch nothing is written and a StatusError with
+// StatusVersionMismatch is returned. On success the returned values
+// include the feature's new data version.
+func (c *Client) WriteIfVersion(ctx context.Context, endpointID uint8, featureID uint8, attrs map[uint16]any, version uint32) (map[uint16]any, error) {
+	payload := make(map[uint16]any, len(attrs)+1)
+	for id, v := range attrs {
+		payload[id] = v
+	}
+	payload[model.AttrIDDataVersion] = version
+	return c.write(ctx, endpointID, featureID, payload)
+}
+
+func (c *Client) write(ctx context.Context, endpointID uint8, featureID uint8, attrs map[uint16]any) (map[uint16]any, error) {
 	req := &wire.Request{
 		MessageID:  c.nextMessageID(),
 		Operation:  wire.OpWrite,
@@ -267,6 +519,8 @@ func (c *Client) Subscribe(ctx context.Context, endpointID uint8, featureID uint
 		payload.AttributeIDs = opts.AttributeIDs
 		payload.MinInterval = uint32(opts.MinInterval.Milliseconds())
 		payload.MaxInterval = uint32(opts.MaxInterval.Milliseconds())
+		payload.EventIDs = opts.EventIDs
+		payload.EventMin = opts.EventMin
 	}
 
 	req := &wire.Request{
@@ -287,14 +541,15 @@ func (c *Client) Subscribe(ctx context.Context, endpointID uint8, featureID uint
 	}
 
 	// Parse response payload
+	var subID uint32
+	var values map[uint16]any
+	var replay []wire.Event
 	switch p := resp.Payload.(type) {
 	case *wire.SubscribeResponsePayload:
-		return p.SubscriptionID, p.CurrentValues, nil
+		subID, values, replay = p.SubscriptionID, p.CurrentValues, p.Events
 	case wire.SubscribeResponsePayload:
-		return p.SubscriptionID, p.CurrentValues, nil
+		subID, values, replay = p.SubscriptionID, p.CurrentValues, p.Events
 	case map[any]any:
-		var subID uint32
-		var values map[uint16]any
 		if v, ok := p[uint64(1)].(uint64); ok {
 			subID = uint32(v)
 		}
@@ -307,10 +562,69 @@ func (c *Client) Subscribe(ctx context.Context, endpointID uint8, featureID uint
 				}
 			}
 		}
-		return subID, values, nil
+		replay = wire.ExtractEvents(p[uint64(3)])
 	default:
 		return 0, nil, ErrUnexpectedReply
 	}
+
+	// Deliver replayed events before returning so they precede any live events
+	if len(replay) > 0 {
+		c.HandleEventNotification(&wire.EventNotification{
+			SubscriptionID: subID,
+			EndpointID:     endpointID,
+			FeatureID:      featureID,
+			Events:         replay,
+		})
+	}
+
+	return subID, values, nil
+}
+
+// ReadPaths reads attributes from several features in one request.
+// Each result reports the status of its path; results are in path order.
+func (c *Client) ReadPaths(ctx context.Context, paths []wire.AttributePath) ([]wire.PathResult, error) {
+	_, results, err := c.sendPaths(ctx, wire.OpRead, &wire.MultiPathPayload{Paths: paths})
+	return results, err
+}
+
+// SubscribePaths subscribes to attribute changes on several features under
+// one subscription. Returns the subscription ID and the per-path priming
+// report. Paths that fail are not subscribed; the subscription ID is 0 if
+// none succeeded. Notifications carry the endpoint and feature of each
+// change. Only the intervals of opts are used.
+func (c *Client) SubscribePaths(ctx context.Context, paths []wire.AttributePath, opts *SubscribeOptions) (uint32, []wire.PathResult, error) {
+	payload := &wire.MultiPathPayload{Paths: paths}
+	if opts != nil {
+		payload.MinInterval = uint32(opts.MinInterval.Milliseconds())
+		payload.MaxInterval = uint32(opts.MaxInterval.Milliseconds())
+	}
+	return c.sendPaths(ctx, wire.OpSubscribe, payload)
+}
+
+// sendPaths sends a multi-path request and parses the response.
+func (c *Client) sendPaths(ctx context.Context, op wire.Operation, payload *wire.MultiPathPayload) (uint32, []wire.PathResult, error) {
+	req := &wire.Request{
+		MessageID:  c.nextMessageID(),
+		Operation:  op,
+		EndpointID: wire.MultiPathEndpointID,
+		FeatureID:  wire.MultiPathFeatureID,
+		Payload:    payload,
+	}
+
+	resp, err := c.sendRequest(ctx, req)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	if !resp.Status.IsSuccess() {
+		return 0, nil, statusError(resp.Status, resp.Payload)
+	}
+
+	mr := wire.ExtractMultiPathResponse(resp.Payload)
+	if mr == nil {
+		return 0, nil, ErrUnexpectedReply
+	}
+	return mr.SubscriptionID, mr.Results, nil
 }
 
 // Unsubscribe cancels a subscription.
@@ -371,12 +685,28 @@ type SubscribeOptions struct {
 
 	// MaxInterval is the maximum time without a notification (heartbeat).
 	MaxInterval time.Duration
+
+	// EventIDs subscribes to the given events. Without AttributeIDs the
+	// subscription is event-only (no priming report, no heartbeats).
+	EventIDs []uint8
+
+	// EventMin requests replay of buffered events with a number >= EventMin
+	// (0 = no replay). Use the last seen event number + 1 to catch up after
+	// a reconnect.
+	EventMin uint64
 }
 
 // StatusError represents an error response from the server.
 type StatusError struct {
 	Status  wire.Status
 	Message string
+
+	// Fields lists the invalid fields the server reported, if any, with
+	// the kind of constraint each violates and its limit.
+	Fields []wire.FieldError
+
+	// RetryAfter is the server's retry hint (StatusBusy only).
+	RetryAfter time.Duration
 }
 
 func (e *StatusError) Error() string {
@@ -386,11 +716,23 @@ func (e *StatusError) Error() string {
 	return e.Status.String()
 }
 
+// Field returns the reported error for the field at path.
+func (e *StatusError) Field(path string) (wire.FieldError, bool) {
+	for _, f := range e.Fields {
+		if f.Path == path {
+			return f, true
+		}
+	}
+	return wire.FieldError{}, false
+}
+
 // statusError creates an error from a response status.
 func statusError(status wire.Status, payload any) error {
-	msg := ""
-	if ep, ok := payload.(*wire.ErrorPayload); ok {
-		msg = ep.Message
+	err := &StatusError{Status: status}
+	if ep := wire.ExtractErrorPayload(payload); ep != nil {
+		err.Message = ep.Message
+		err.Fields = ep.Fields
+		err.RetryAfter = time.Duration(ep.RetryAfter) * time.Millisecond
 	}
-	return &StatusError{Status: status, Message: msg}
+	return err
 }
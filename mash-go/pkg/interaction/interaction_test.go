@@ -2,9 +2,12 @@ package interaction
 
 import (
 	"context"
+	"errors"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/mash-protocol/mash-go/pkg/clock"
 	"github.com/mash-protocol/mash-go/pkg/features"
 	"github.com/mash-protocol/mash-go/pkg/model"
 	"github.com/mash-protocol/mash-go/pkg/wire"
@@ -470,6 +473,461 @@ func TestClientBasics(t *testing.T) {
 	}
 }
 
+// respondingSender answers each request synchronously with a round-tripped
+// response built by respond.
+type respondingSender struct {
+	client  *Client
+	respond func(req *wire.Request) *wire.Response
+	lastReq *wire.Request
+}
+
+func (s *respondingSender) Send(data []byte) error {
+	req, err := wire.DecodeRequest(data)
+	if err != nil {
+		return err
+	}
+	s.lastReq = req
+	encoded, err := wire.EncodeResponse(s.respond(req))
+	if err != nil {
+		return err
+	}
+	resp, err := wire.DecodeResponse(encoded)
+	if err != nil {
+		return err
+	}
+	return s.client.HandleResponse(resp)
+}
+
+func TestClientSubscribeEvents(t *testing.T) {
+	sender := &respondingSender{
+		respond: func(req *wire.Request) *wire.Response {
+			return &wire.Response{
+				MessageID: req.MessageID,
+				Status:    wire.StatusSuccess,
+				Payload: &wire.SubscribeResponsePayload{
+					SubscriptionID: 9,
+					Events: []wire.Event{
+						{EventNumber: 12, Timestamp: 1000, EndpointID: 1, FeatureID: 6, EventID: 1},
+					},
+				},
+			}
+		},
+	}
+	client := NewClient(sender)
+	sender.client = client
+	defer client.Close()
+
+	var received []*wire.EventNotification
+	client.SetEventHandler(func(notif *wire.EventNotification) {
+		received = append(received, notif)
+	})
+
+	subID, values, err := client.Subscribe(context.Background(), 1, 6, &SubscribeOptions{
+		EventIDs: []uint8{1, 2},
+		EventMin: 12,
+	})
+	if err != nil {
+		t.Fatalf("Subscribe failed: %v", err)
+	}
+	if subID != 9 {
+		t.Errorf("expected subscriptionID 9, got %d", subID)
+	}
+	if len(values) != 0 {
+		t.Errorf("expected no priming values, got %v", values)
+	}
+
+	sp := wire.ExtractSubscribePayload(sender.lastReq.Payload)
+	if len(sp.EventIDs) != 2 || sp.EventMin != 12 {
+		t.Errorf("expected eventIds [1 2] and eventMin 12, got %v / %d", sp.EventIDs, sp.EventMin)
+	}
+
+	// Replayed events are delivered before Subscribe returns
+	if len(received) != 1 || received[0].Events[0].EventNumber != 12 {
+		t.Fatalf("expected replayed event 12, got %+v", received)
+	}
+	if received[0].SubscriptionID != 9 {
+		t.Errorf("expected replay for subscription 9, got %d", received[0].SubscriptionID)
+	}
+
+	// Live event notifications go to the same handler
+	client.HandleEventNotification(&wire.EventNotification{
+		SubscriptionID: 9,
+		Events:         []wire.Event{{EventNumber: 13, EventID: 2}},
+	})
+	if len(received) != 2 {
+		t.Errorf("expected 2 event notifications, got %d", len(received))
+	}
+}
+
+func TestClientSubscribePaths(t *testing.T) {
+	sender := &respondingSender{
+		respond: func(req *wire.Request) *wire.Response {
+			mp := wire.ExtractMultiPathPayload(req.Payload)
+			results := make([]wire.PathResult, len(mp.Paths))
+			for i, p := range mp.Paths {
+				results[i] = wire.PathResult{EndpointID: p.EndpointID, FeatureID: p.FeatureID, Status: wire.StatusInvalidEndpoint}
+			}
+			results[0].Status = wire.StatusSuccess
+			results[0].Values = map[uint16]any{1: "test-device"}
+			return &wire.Response{
+				MessageID: req.MessageID,
+				Status:    wire.StatusSuccess,
+				Payload:   &wire.MultiPathResponsePayload{SubscriptionID: 5, Results: results},
+			}
+		},
+	}
+	client := NewClient(sender)
+	sender.client = client
+	defer client.Close()
+
+	paths := []wire.AttributePath{
+		{EndpointID: 0, FeatureID: uint8(model.FeatureDeviceInfo), AttributeIDs: []uint16{1}},
+		{EndpointID: 7, FeatureID: uint8(model.FeatureMeasurement)},
+	}
+	subID, results, err := client.SubscribePaths(context.Background(), paths, &SubscribeOptions{MinInterval: 2 * time.Second})
+	if err != nil {
+		t.Fatalf("SubscribePaths failed: %v", err)
+	}
+	if subID != 5 {
+		t.Errorf("expected subscriptionID 5, got %d", subID)
+	}
+	if len(results) != 2 || results[0].Values[1] != "test-device" || results[1].Status != wire.StatusInvalidEndpoint {
+		t.Errorf("unexpected results: %+v", results)
+	}
+
+	if !sender.lastReq.IsMultiPath() || sender.lastReq.Operation != wire.OpSubscribe {
+		t.Errorf("expected multi-path Subscribe, got %+v", sender.lastReq)
+	}
+	mp := wire.ExtractMultiPathPayload(sender.lastReq.Payload)
+	if mp.MinInterval != 2000 || len(mp.Paths) != 2 {
+		t.Errorf("unexpected request payload: %+v", mp)
+	}
+
+	results, err = client.ReadPaths(context.Background(), paths[:1])
+	if err != nil {
+		t.Fatalf("ReadPaths failed: %v", err)
+	}
+	if len(results) != 1 || results[0].Status != wire.StatusSuccess {
+		t.Errorf("unexpected read results: %+v", results)
+	}
+	if sender.lastReq.Operation != wire.OpRead {
+		t.Errorf("expected Read, got %v", sender.lastReq.Operation)
+	}
+}
+
+// chunkingSender answers each request with the response split into frames
+// of at most maxSize bytes.
+type chunkingSender struct {
+	client  *Client
+	maxSize int
+	value   string
+	frames  int
+}
+
+func (s *chunkingSender) Send(data []byte) error {
+	req, err := wire.DecodeRequest(data)
+	if err != nil {
+		return err
+	}
+	frames, err := wire.EncodeResponseChunks(&wire.Response{
+		MessageID: req.MessageID,
+		Status:    wire.StatusSuccess,
+		Payload:   map[uint16]any{1: s.value},
+	}, s.maxSize)
+	if err != nil {
+		return err
+	}
+	s.frames = len(frames)
+	for _, frame := range frames {
+		resp, err := wire.DecodeResponse(frame)
+		if err != nil {
+			return err
+		}
+		if err := s.client.HandleResponse(resp); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func TestClientReadChunked(t *testing.T) {
+	sender := &chunkingSender{maxSize: 8192, value: strings.Repeat("x", 20000)}
+	client := NewClient(sender)
+	sender.client = client
+	defer client.Close()
+
+	values, err := client.Read(context.Background(), 1, 2, nil)
+	if err != nil {
+		t.Fatalf("Read failed: %v", err)
+	}
+	if sender.frames < 3 {
+		t.Errorf("expected the response in at least 3 frames, got %d", sender.frames)
+	}
+	if values[1] != sender.value {
+		t.Errorf("reassembled value has %d bytes, want %d", len(values[1].(string)), len(sender.value))
+	}
+	if client.chunks.Pending() != 0 {
+		t.Errorf("expected no partial responses, got %d", client.chunks.Pending())
+	}
+}
+
+func TestClientBusyRetry(t *testing.T) {
+	busy := 2
+	var msgIDs []uint32
+	sender := &respondingSender{
+		respond: func(req *wire.Request) *wire.Response {
+			msgIDs = append(msgIDs, req.MessageID)
+			if busy > 0 {
+				busy--
+				return &wire.Response{
+					MessageID: req.MessageID,
+					Status:    wire.StatusBusy,
+					Payload:   &wire.ErrorPayload{Message: "busy", RetryAfter: 10},
+				}
+			}
+			return &wire.Response{MessageID: req.MessageID, Status: wire.StatusSuccess, Payload: map[uint16]any{1: "ok"}}
+		},
+	}
+	client := NewClient(sender)
+	sender.client = client
+	defer client.Close()
+
+	start := time.Now()
+	values, err := client.Read(context.Background(), 1, 2, nil)
+	if err != nil {
+		t.Fatalf("Read failed: %v", err)
+	}
+	if values[1] != "ok" {
+		t.Errorf("unexpected values: %v", values)
+	}
+	if elapsed := time.Since(start); elapsed < 20*time.Millisecond {
+		t.Errorf("expected retry-after hint to be honoured, finished in %v", elapsed)
+	}
+	if len(msgIDs) != 3 || msgIDs[0] == msgIDs[1] || msgIDs[1] == msgIDs[2] {
+		t.Errorf("expected 3 attempts with distinct message IDs, got %v", msgIDs)
+	}
+
+	stats := client.Stats()
+	if stats.Busy != 2 || stats.Retries != 2 || stats.Responses != 3 {
+		t.Errorf("unexpected stats: busy=%d retries=%d responses=%d", stats.Busy, stats.Retries, stats.Responses)
+	}
+	if stats.Latency.Count() != 3 {
+		t.Errorf("expected 3 latency samples, got %d", stats.Latency.Count())
+	}
+}
+
+func TestClientBusyRetryStopsAtDeadline(t *testing.T) {
+	sender := &respondingSender{
+		respond: func(req *wire.Request) *wire.Response {
+			return &wire.Response{
+				MessageID: req.MessageID,
+				Status:    wire.StatusBusy,
+				Payload:   &wire.ErrorPayload{RetryAfter: 60000},
+			}
+		},
+	}
+	client := NewClient(sender)
+	sender.client = client
+	defer client.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	_, err := client.Read(ctx, 1, 2, nil)
+	var statusErr *StatusError
+	if !errors.As(err, &statusErr) || statusErr.Status != wire.StatusBusy {
+		t.Fatalf("expected busy StatusError, got %v", err)
+	}
+	if retries := client.Stats().Retries; retries != 0 {
+		t.Errorf("expected no retry past the deadline, got %d", retries)
+	}
+
+	// Without a retry policy the busy response is returned at once.
+	client.SetRetryPolicy(RetryPolicy{})
+	sender.respond = func(req *wire.Request) *wire.Response {
+		return &wire.Response{MessageID: req.MessageID, Status: wire.StatusBusy}
+	}
+	if _, err := client.Read(context.Background(), 1, 2, nil); !errors.As(err, &statusErr) {
+		t.Fatalf("expected busy StatusError, got %v", err)
+	}
+	if busy := client.Stats().Busy; busy != 2 {
+		t.Errorf("expected 2 busy responses, got %d", busy)
+	}
+}
+
+func TestClientBusyRetryReadOnly(t *testing.T) {
+	attempts := 0
+	sender := &respondingSender{
+		respond: func(req *wire.Request) *wire.Response {
+			attempts++
+			if attempts == 1 {
+				return &wire.Response{
+					MessageID: req.MessageID,
+					Status:    wire.StatusBusy,
+					Payload:   &wire.ErrorPayload{RetryAfter: 1000},
+				}
+			}
+			return &wire.Response{MessageID: req.MessageID, Status: wire.StatusSuccess}
+		},
+	}
+	client := NewClient(sender)
+	sender.client = client
+	defer client.Close()
+
+	// Invoke may not be idempotent and is not retried by default.
+	var statusErr *StatusError
+	if _, err := client.Invoke(context.Background(), 1, 2, 1, nil); !errors.As(err, &statusErr) || statusErr.Status != wire.StatusBusy {
+		t.Fatalf("expected busy StatusError, got %v", err)
+	}
+	if attempts != 1 {
+		t.Fatalf("expected 1 attempt, got %d", attempts)
+	}
+
+	// With NonIdempotent it is, after a backoff on the client's clock.
+	attempts = 0
+	fake := clock.NewFake(time.Now())
+	client.SetClock(fake)
+	client.SetRetryPolicy(RetryPolicy{MaxRetries: 1, NonIdempotent: true})
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := client.Invoke(ctx, 1, 2, 1, nil)
+		done <- err
+	}()
+	for fake.PendingTimers() == 0 && ctx.Err() == nil {
+		time.Sleep(time.Millisecond)
+	}
+	fake.Advance(time.Second)
+	if err := <-done; err != nil {
+		t.Fatalf("Invoke failed: %v", err)
+	}
+	if attempts != 2 {
+		t.Errorf("expected 2 attempts, got %d", attempts)
+	}
+}
+
+func TestClientDeadlines(t *testing.T) {
+	client := NewClient(&mockSender{})
+	defer client.Close()
+
+	// The context deadline applies, not the client timeout.
+	client.SetTimeout(time.Hour)
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := client.Read(ctx, 1, 2, nil); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected context.DeadlineExceeded, got %v", err)
+	}
+
+	// Without a context deadline the client timeout applies.
+	client.SetTimeout(20 * time.Millisecond)
+	if _, err := client.Read(context.Background(), 1, 2, nil); !errors.Is(err, ErrRequestTimeout) {
+		t.Errorf("expected ErrRequestTimeout, got %v", err)
+	}
+
+	if timeouts := client.Stats().Timeouts; timeouts != 2 {
+		t.Errorf("expected 2 timeouts, got %d", timeouts)
+	}
+}
+
+// queueSender hands each request to the test to answer.
+type queueSender struct {
+	requests chan *wire.Request
+}
+
+func (s *queueSender) Send(data []byte) error {
+	req, err := wire.DecodeRequest(data)
+	if err != nil {
+		return err
+	}
+	s.requests <- req
+	return nil
+}
+
+func TestClientMaxInFlight(t *testing.T) {
+	sender := &queueSender{requests: make(chan *wire.Request, 10)}
+	client := NewClient(sender)
+	defer client.Close()
+	client.SetMaxInFlight(2)
+
+	errs := make(chan error, 4)
+	for range 4 {
+		go func() {
+			_, err := client.Read(context.Background(), 1, 2, nil)
+			errs <- err
+		}()
+	}
+
+	waitFor := func(cond func(ClientStats) bool) {
+		t.Helper()
+		deadline := time.Now().Add(time.Second)
+		for !cond(client.Stats()) {
+			if time.Now().After(deadline) {
+				t.Fatalf("timed out waiting, stats: %+v", client.Stats())
+			}
+			time.Sleep(time.Millisecond)
+		}
+	}
+	waitFor(func(s ClientStats) bool { return s.InFlight == 2 && s.Queued == 2 })
+	if len(sender.requests) != 2 {
+		t.Fatalf("expected 2 requests sent, got %d", len(sender.requests))
+	}
+
+	// Each answer lets one queued call through.
+	for range 4 {
+		req := <-sender.requests
+		client.HandleResponse(&wire.Response{MessageID: req.MessageID, Status: wire.StatusSuccess, Payload: map[uint16]any{}})
+	}
+	for range 4 {
+		if err := <-errs; err != nil {
+			t.Errorf("Read failed: %v", err)
+		}
+	}
+
+	stats := client.Stats()
+	if stats.InFlight != 0 || stats.Queued != 0 || stats.Responses != 4 {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+}
+
+func TestClientWriteIfVersion(t *testing.T) {
+	sender := &respondingSender{
+		respond: func(req *wire.Request) *wire.Response {
+			wp := wire.ExtractWritePayload(req.Payload)
+			if v, _ := wire.ToUint32(wp[model.AttrIDDataVersion]); v != 7 {
+				return &wire.Response{MessageID: req.MessageID, Status: wire.StatusVersionMismatch}
+			}
+			return &wire.Response{
+				MessageID: req.MessageID,
+				Status:    wire.StatusSuccess,
+				Payload:   map[uint16]any{1: wp[1], model.AttrIDDataVersion: uint32(8)},
+			}
+		},
+	}
+	client := NewClient(sender)
+	sender.client = client
+	defer client.Close()
+
+	attrs := map[uint16]any{1: "Kitchen"}
+	values, err := client.WriteIfVersion(context.Background(), 0, 6, attrs, 7)
+	if err != nil {
+		t.Fatalf("WriteIfVersion failed: %v", err)
+	}
+	if v, _ := wire.ToUint32(values[model.AttrIDDataVersion]); v != 8 {
+		t.Errorf("expected new data version 8, got %v", values[model.AttrIDDataVersion])
+	}
+	if len(attrs) != 1 {
+		t.Errorf("caller's map must not be modified, got %v", attrs)
+	}
+
+	_, err = client.WriteIfVersion(context.Background(), 0, 6, attrs, 6)
+	var statusErr *StatusError
+	if !errors.As(err, &statusErr) || statusErr.Status != wire.StatusVersionMismatch {
+		t.Errorf("expected VERSION_MISMATCH, got %v", err)
+	}
+}
+
 func TestMessageIDWraparound(t *testing.T) {
 	// Test that MessageID wraps from max to 1, skipping 0 (reserved for notifications)
 	sender := &mockSender{}
@@ -530,6 +988,49 @@ func TestStatusError(t *testing.T) {
 	}
 }
 
+func TestStatusErrorDetails(t *testing.T) {
+	data, err := wire.EncodeResponse(&wire.Response{
+		MessageID: 1,
+		Status:    wire.StatusConstraintError,
+		Payload: &wire.ErrorPayload{
+			Message: "consumptionLimit 30000000 mW exceeds device maximum 22000000 mW",
+			Fields: []wire.FieldError{{
+				Path:       "consumptionLimit",
+				Message:    "exceeds device maximum",
+				Constraint: wire.ConstraintMax,
+				Limit:      int64(22000000),
+			}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("EncodeResponse failed: %v", err)
+	}
+	resp, err := wire.DecodeResponse(data)
+	if err != nil {
+		t.Fatalf("DecodeResponse failed: %v", err)
+	}
+
+	var se *StatusError
+	if !errors.As(statusError(resp.Status, resp.Payload), &se) {
+		t.Fatal("expected *StatusError")
+	}
+	f, ok := se.Field("consumptionLimit")
+	if !ok || f.Constraint != wire.ConstraintMax {
+		t.Fatalf("Field(consumptionLimit) = %+v, %v", f, ok)
+	}
+	if limit, ok := f.LimitInt64(); !ok || limit != 22000000 {
+		t.Errorf("LimitInt64() = %d, %v, want 22000000", limit, ok)
+	}
+	if _, ok := se.Field("productionLimit"); ok {
+		t.Error("Field(productionLimit) found")
+	}
+
+	busy := statusError(wire.StatusBusy, &wire.ErrorPayload{Message: "busy", RetryAfter: 250}).(*StatusError)
+	if busy.RetryAfter != 250*time.Millisecond {
+		t.Errorf("RetryAfter = %v, want 250ms", busy.RetryAfter)
+	}
+}
+
 type mockSender struct {
 	sent [][]byte
 }
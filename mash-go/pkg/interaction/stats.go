@@ -0,0 +1,142 @@
+package interaction
+
+import (
+	"sync"
+	"time"
+
+	"github.com/mash-protocol/mash-go/pkg/wire"
+)
+
+// LatencyBuckets are the upper bounds of the request latency histogram.
+var LatencyBuckets = []time.Duration{
+	5 * time.Millisecond,
+	10 * time.Millisecond,
+	25 * time.Millisecond,
+	50 * time.Millisecond,
+	100 * time.Millisecond,
+	250 * time.Millisecond,
+	500 * time.Millisecond,
+	time.Second,
+	2500 * time.Millisecond,
+	5 * time.Second,
+	10 * time.Second,
+}
+
+// ClientStats contains request statistics of a Client.
+type ClientStats struct {
+	// Responses is the number of responses received, busy ones included.
+	Responses uint64
+
+	// Timeouts is the number of calls whose deadline expired.
+	Timeouts uint64
+
+	// Busy is the number of StatusBusy responses received.
+	Busy uint64
+
+	// Retries is the number of requests resent after StatusBusy.
+	Retries uint64
+
+	// InFlight is the number of requests currently awaiting a response.
+	InFlight int
+
+	// Queued is the number of calls waiting for an in-flight slot.
+	Queued int
+
+	// Latency is the distribution of request round-trip times.
+	Latency LatencyHistogram
+}
+
+// LatencyHistogram is a histogram of request round-trip times. Counts[i]
+// is the number of requests with latency above Bounds[i-1] and up to
+// Bounds[i]; the final entry counts those above the last bound.
+type LatencyHistogram struct {
+	Bounds []time.Duration
+	Counts []uint64
+	Sum    time.Duration
+}
+
+// Count returns the number of recorded requests.
+func (h LatencyHistogram) Count() uint64 {
+	var n uint64
+	for _, c := range h.Counts {
+		n += c
+	}
+	return n
+}
+
+// Mean returns the mean latency, or 0 if nothing was recorded.
+func (h LatencyHistogram) Mean() time.Duration {
+	n := h.Count()
+	if n == 0 {
+		return 0
+	}
+	return h.Sum / time.Duration(n)
+}
+
+// clientStats accumulates ClientStats.
+type clientStats struct {
+	mu sync.Mutex
+	ClientStats
+}
+
+func (s *clientStats) begin() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.InFlight++
+}
+
+func (s *clientStats) end() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.InFlight--
+}
+
+func (s *clientStats) queue(delta int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.Queued += delta
+}
+
+func (s *clientStats) retry() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.Retries++
+}
+
+func (s *clientStats) timeout() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.Timeouts++
+}
+
+func (s *clientStats) response(status wire.Status, latency time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.Responses++
+	if status == wire.StatusBusy {
+		s.Busy++
+	}
+	if s.Latency.Counts == nil {
+		s.Latency.Counts = make([]uint64, len(LatencyBuckets)+1)
+	}
+	i := 0
+	for i < len(LatencyBuckets) && latency > LatencyBuckets[i] {
+		i++
+	}
+	s.Latency.Counts[i]++
+	s.Latency.Sum += latency
+}
+
+// Stats returns a snapshot of the client's request statistics.
+func (c *Client) Stats() ClientStats {
+	c.stats.mu.Lock()
+	defer c.stats.mu.Unlock()
+	stats := c.stats.ClientStats
+	stats.Latency.Bounds = LatencyBuckets
+	if stats.Latency.Counts == nil {
+		stats.Latency.Counts = make([]uint64, len(LatencyBuckets)+1)
+	} else {
+		stats.Latency.Counts = append([]uint64(nil), stats.Latency.Counts...)
+	}
+	return stats
+}
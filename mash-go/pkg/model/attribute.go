@@ -49,6 +49,11 @@ const (
 
 	// AttrIDCommandList is the list of supported command IDs.
 	AttrIDCommandList uint16 = 0xFFFA
+
+	// AttrIDDataVersion is the feature's data version, which changes on
+	// every attribute update. It is also the key of the version
+	// precondition in a Write request.
+	AttrIDDataVersion uint16 = 0xFFF7
 )
 
 // Access flags for attributes.
@@ -177,6 +182,14 @@ type Attribute struct {
 	metadata *AttributeMetadata
 	value    any
 	dirty    bool // True if value changed since last report
+
+	// writeMu and onChange are set by the owning feature. Every set holds
+	// writeMu, so internal updates (generated setters) cannot slip between
+	// a conditional write's version check and its store. onChange is called
+	// when the value actually changes; the feature uses it to advance its
+	// data version.
+	writeMu  *sync.Mutex
+	onChange func()
 }
 
 // Attribute errors.
@@ -228,29 +241,52 @@ func (a *Attribute) SetValueInternal(value any) error {
 }
 
 func (a *Attribute) setValueInternal(value any) error {
-	// Check nullable
-	if value == nil && !a.metadata.Nullable {
-		return ErrAttributeNotNullable
+	if err := a.validate(value); err != nil {
+		return err
 	}
 
-	// Validate type and range
-	if value != nil {
-		if err := a.validateValue(value); err != nil {
-			return err
-		}
+	a.mu.RLock()
+	writeMu := a.writeMu
+	a.mu.RUnlock()
+	if writeMu != nil {
+		writeMu.Lock()
+		defer writeMu.Unlock()
 	}
+	a.store(value)
+	return nil
+}
 
+// store stores an already validated value. The caller holds the owning
+// feature's writeMu, if any.
+func (a *Attribute) store(value any) {
 	a.mu.Lock()
-	defer a.mu.Unlock()
-
 	// Check if value actually changed.
 	// Use safeNotEqual because some attribute types (arrays/maps) are
 	// not comparable with == and would panic.
-	if safeNotEqual(a.value, value) {
+	changed := safeNotEqual(a.value, value)
+	if changed {
 		a.value = value
 		a.dirty = true
 	}
+	onChange := a.onChange
+	a.mu.Unlock()
+
+	if changed && onChange != nil {
+		onChange()
+	}
+}
+
+// validate checks that value may be stored, without storing it.
+func (a *Attribute) validate(value any) error {
+	// Check nullable
+	if value == nil && !a.metadata.Nullable {
+		return ErrAttributeNotNullable
+	}
 
+	// Validate type and range
+	if value != nil {
+		return a.validateValue(value)
+	}
 	return nil
 }
 
@@ -0,0 +1,151 @@
+package model
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/mash-protocol/mash-go/pkg/clock"
+)
+
+// Event errors.
+var (
+	ErrEventNotFound = errors.New("event not found")
+)
+
+// DefaultEventBufferSize is the default number of events retained by an
+// EventBuffer before the oldest entries are dropped.
+const DefaultEventBufferSize = 64
+
+// EventMetadata describes an event a feature can emit.
+type EventMetadata struct {
+	// ID is the event identifier within the feature.
+	ID uint8
+
+	// Name is the human-readable event name.
+	Name string
+
+	// Description is a human-readable description.
+	Description string
+}
+
+// Event is a timestamped, monotonically numbered record of something that
+// happened on a device. Unlike attribute changes, events are never coalesced:
+// every emitted event gets its own number.
+type Event struct {
+	// Number is the device-wide monotonic event number (starts at 1).
+	Number uint64
+
+	// Timestamp is when the event was recorded.
+	Timestamp time.Time
+
+	// EndpointID is the endpoint the emitting feature belongs to.
+	EndpointID uint8
+
+	// FeatureType is the emitting feature.
+	FeatureType FeatureType
+
+	// EventID identifies the event within the feature.
+	EventID uint8
+
+	// Data is the event-specific payload (may be nil).
+	Data any
+}
+
+// FeatureEventSubscriber is notified when a feature emits an event.
+// Implementations of FeatureSubscriber may additionally implement this
+// interface; Feature.EmitEvent delivers to every subscriber that does.
+type FeatureEventSubscriber interface {
+	// OnEventEmitted is called when the feature emits an event.
+	OnEventEmitted(featureType FeatureType, eventID uint8, data any)
+}
+
+// EventBuffer is a bounded, append-only log of device events.
+// It assigns event numbers and keeps the most recent events so that
+// controllers can catch up on events they missed while disconnected.
+type EventBuffer struct {
+	mu sync.RWMutex
+
+	capacity   int
+	events     []Event
+	lastNumber uint64
+	clock      clock.Clock
+}
+
+// NewEventBuffer creates an event buffer that retains up to capacity events.
+// A capacity <= 0 uses DefaultEventBufferSize.
+func NewEventBuffer(capacity int) *EventBuffer {
+	return NewEventBufferWithClock(capacity, nil)
+}
+
+// NewEventBufferWithClock creates an event buffer that timestamps events
+// using c. A nil c uses the real clock.
+func NewEventBufferWithClock(capacity int, c clock.Clock) *EventBuffer {
+	if capacity <= 0 {
+		capacity = DefaultEventBufferSize
+	}
+	return &EventBuffer{
+		capacity: capacity,
+		events:   make([]Event, 0, capacity),
+		clock:    clock.OrReal(c),
+	}
+}
+
+// Append records a new event and returns it with its assigned number and
+// timestamp. If the buffer is full, the oldest event is dropped.
+func (b *EventBuffer) Append(endpointID uint8, featureType FeatureType, eventID uint8, data any) Event {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.lastNumber++
+	ev := Event{
+		Number:      b.lastNumber,
+		Timestamp:   b.clock.Now(),
+		EndpointID:  endpointID,
+		FeatureType: featureType,
+		EventID:     eventID,
+		Data:        data,
+	}
+
+	if len(b.events) == b.capacity {
+		copy(b.events, b.events[1:])
+		b.events = b.events[:len(b.events)-1]
+	}
+	b.events = append(b.events, ev)
+
+	return ev
+}
+
+// Since returns the buffered events with a number >= minNumber, oldest first.
+func (b *EventBuffer) Since(minNumber uint64) []Event {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	var result []Event
+	for _, ev := range b.events {
+		if ev.Number >= minNumber {
+			result = append(result, ev)
+		}
+	}
+	return result
+}
+
+// LastNumber returns the number of the most recently appended event
+// (0 if no events have been recorded).
+func (b *EventBuffer) LastNumber() uint64 {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.lastNumber
+}
+
+// Len returns the number of buffered events.
+func (b *EventBuffer) Len() int {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return len(b.events)
+}
+
+// Capacity returns the maximum number of buffered events.
+func (b *EventBuffer) Capacity() int {
+	return b.capacity
+}
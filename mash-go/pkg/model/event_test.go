@@ -0,0 +1,122 @@
+package model
+
+import (
+	"testing"
+)
+
+type recordingEventSubscriber struct {
+	events []uint8
+	data   []any
+}
+
+func (r *recordingEventSubscriber) OnAttributeChanged(FeatureType, uint16, any) {}
+
+func (r *recordingEventSubscriber) OnEventEmitted(_ FeatureType, eventID uint8, data any) {
+	r.events = append(r.events, eventID)
+	r.data = append(r.data, data)
+}
+
+type attributeOnlySubscriber struct{}
+
+func (attributeOnlySubscriber) OnAttributeChanged(FeatureType, uint16, any) {}
+
+func TestFeatureEvents(t *testing.T) {
+	f := NewFeature(FeatureStatus, 1)
+	f.AddEvent(&EventMetadata{ID: 2, Name: "faultCleared"})
+	f.AddEvent(&EventMetadata{ID: 1, Name: "faultRaised"})
+
+	t.Run("EventList", func(t *testing.T) {
+		ids := f.EventList()
+		if len(ids) != 2 || ids[0] != 1 || ids[1] != 2 {
+			t.Errorf("expected [1 2], got %v", ids)
+		}
+	})
+
+	t.Run("GetEvent", func(t *testing.T) {
+		meta, err := f.GetEvent(1)
+		if err != nil {
+			t.Fatalf("GetEvent failed: %v", err)
+		}
+		if meta.Name != "faultRaised" {
+			t.Errorf("expected faultRaised, got %s", meta.Name)
+		}
+		if _, err := f.GetEvent(9); err != ErrEventNotFound {
+			t.Errorf("expected ErrEventNotFound, got %v", err)
+		}
+	})
+
+	t.Run("EmitDeliversToEventSubscribers", func(t *testing.T) {
+		sub := &recordingEventSubscriber{}
+		f.Subscribe(sub)
+		f.Subscribe(attributeOnlySubscriber{})
+		defer f.Unsubscribe(sub)
+
+		if err := f.EmitEvent(1, map[string]any{"faultCode": uint32(42)}); err != nil {
+			t.Fatalf("EmitEvent failed: %v", err)
+		}
+		if len(sub.events) != 1 || sub.events[0] != 1 {
+			t.Errorf("expected event 1 delivered, got %v", sub.events)
+		}
+	})
+
+	t.Run("EmitUnknownEvent", func(t *testing.T) {
+		if err := f.EmitEvent(99, nil); err != ErrEventNotFound {
+			t.Errorf("expected ErrEventNotFound, got %v", err)
+		}
+	})
+}
+
+func TestEventBuffer(t *testing.T) {
+	t.Run("NumbersAreMonotonic", func(t *testing.T) {
+		b := NewEventBuffer(4)
+		first := b.Append(1, FeatureStatus, 1, nil)
+		second := b.Append(1, FeatureStatus, 2, nil)
+
+		if first.Number != 1 || second.Number != 2 {
+			t.Errorf("expected numbers 1,2, got %d,%d", first.Number, second.Number)
+		}
+		if b.LastNumber() != 2 {
+			t.Errorf("expected LastNumber 2, got %d", b.LastNumber())
+		}
+		if first.Timestamp.IsZero() {
+			t.Error("expected timestamp to be set")
+		}
+	})
+
+	t.Run("DropsOldestWhenFull", func(t *testing.T) {
+		b := NewEventBuffer(3)
+		for i := 0; i < 5; i++ {
+			b.Append(1, FeatureStatus, 1, i)
+		}
+
+		if b.Len() != 3 {
+			t.Fatalf("expected 3 buffered events, got %d", b.Len())
+		}
+		events := b.Since(0)
+		if events[0].Number != 3 || events[2].Number != 5 {
+			t.Errorf("expected events 3..5, got %d..%d", events[0].Number, events[2].Number)
+		}
+	})
+
+	t.Run("Since", func(t *testing.T) {
+		b := NewEventBuffer(10)
+		for i := 0; i < 5; i++ {
+			b.Append(1, FeatureChargingSession, 1, nil)
+		}
+
+		events := b.Since(4)
+		if len(events) != 2 || events[0].Number != 4 {
+			t.Errorf("expected events 4,5, got %v", events)
+		}
+		if got := b.Since(6); len(got) != 0 {
+			t.Errorf("expected no events, got %d", len(got))
+		}
+	})
+
+	t.Run("DefaultCapacity", func(t *testing.T) {
+		b := NewEventBuffer(0)
+		if b.Capacity() != DefaultEventBufferSize {
+			t.Errorf("expected capacity %d, got %d", DefaultEventBufferSize, b.Capacity())
+		}
+	})
+}
@@ -3,14 +3,18 @@ package model
 import (
 	"context"
 	"errors"
+	"fmt"
+	"math/rand/v2"
 	"slices"
 	"sync"
+	"sync/atomic"
 )
 
 // Feature errors.
 var (
 	ErrAttributeNotFound = errors.New("attribute not found")
 	ErrFeatureReadOnly   = errors.New("feature is read-only")
+	ErrVersionMismatch   = errors.New("data version mismatch")
 )
 
 // ReadHook is called during context-aware reads to allow features to override
@@ -38,11 +42,21 @@ type Feature struct {
 	// Commands indexed by ID.
 	commands map[uint8]*Command
 
+	// Events indexed by ID.
+	events map[uint8]*EventMetadata
+
 	// Subscribers for change notifications.
 	subscribers []FeatureSubscriber
 
 	// readHook is an optional hook for context-aware attribute reads.
 	readHook ReadHook
+
+	// dataVersion changes whenever an attribute value changes.
+	dataVersion atomic.Uint32
+
+	// writeMu serializes all attribute sets, external and internal, so a
+	// version precondition and the write it guards are atomic.
+	writeMu sync.Mutex
 }
 
 // FeatureSubscriber is notified when attributes change.
@@ -58,7 +72,11 @@ func NewFeature(featureType FeatureType, revision uint16) *Feature {
 		revision:    revision,
 		attributes:  make(map[uint16]*Attribute),
 		commands:    make(map[uint8]*Command),
+		events:      make(map[uint8]*EventMetadata),
 	}
+	// Start at a random version so a version read before a restart is
+	// unlikely to match afterwards.
+	f.dataVersion.Store(rand.Uint32())
 
 	// Add global attributes
 	f.addGlobalAttributes()
@@ -95,6 +113,21 @@ func (f *Feature) addGlobalAttributes() {
 		Access:      AccessRead,
 		Description: "List of supported command IDs",
 	})
+
+	// dataVersion (dynamically computed)
+	f.attributes[AttrIDDataVersion] = NewAttribute(&AttributeMetadata{
+		ID:          AttrIDDataVersion,
+		Name:        "dataVersion",
+		Type:        DataTypeUint32,
+		Access:      AccessRead,
+		Description: "Version of the feature's attribute data",
+	})
+}
+
+// DataVersion returns the feature's data version. It changes whenever an
+// attribute is written or updated internally.
+func (f *Feature) DataVersion() uint32 {
+	return f.dataVersion.Load()
 }
 
 // Type returns the feature type.
@@ -130,6 +163,10 @@ func (f *Feature) SetFeatureMap(bitmap uint32) {
 func (f *Feature) AddAttribute(attr *Attribute) {
 	f.mu.Lock()
 	defer f.mu.Unlock()
+	attr.mu.Lock()
+	attr.writeMu = &f.writeMu
+	attr.onChange = func() { f.dataVersion.Add(1) }
+	attr.mu.Unlock()
 	f.attributes[attr.ID()] = attr
 }
 
@@ -154,6 +191,9 @@ func (f *Feature) ReadAttribute(id uint16) (any, error) {
 	if id == AttrIDCommandList {
 		return f.CommandList(), nil
 	}
+	if id == AttrIDDataVersion {
+		return f.DataVersion(), nil
+	}
 
 	attr, err := f.GetAttribute(id)
 	if err != nil {
@@ -179,6 +219,7 @@ func (f *Feature) WriteAttribute(id uint16, value any) error {
 		return err
 	}
 
+	// The attribute advances the data version
 	if err := attr.SetValue(value); err != nil {
 		return err
 	}
@@ -189,6 +230,54 @@ func (f *Feature) WriteAttribute(id uint16, value any) error {
 	return nil
 }
 
+// WriteAttributes writes several attributes as one change: every value is
+// validated before any is stored, so either all are written or none. If
+// version is non-nil the write only proceeds when it equals the current
+// data version; otherwise ErrVersionMismatch is returned.
+func (f *Feature) WriteAttributes(attrs map[uint16]any, version *uint32) error {
+	f.writeMu.Lock()
+
+	if version != nil {
+		if current := f.DataVersion(); *version != current {
+			f.writeMu.Unlock()
+			return fmt.Errorf("%w: expected %d, current %d", ErrVersionMismatch, *version, current)
+		}
+	}
+
+	targets := make(map[uint16]*Attribute, len(attrs))
+	for id, value := range attrs {
+		if id >= AttrIDGlobalBase {
+			f.writeMu.Unlock()
+			return ErrFeatureReadOnly
+		}
+		attr, err := f.GetAttribute(id)
+		if err != nil {
+			f.writeMu.Unlock()
+			return err
+		}
+		if !attr.Metadata().Access.CanWrite() {
+			f.writeMu.Unlock()
+			return ErrAttributeNotWritable
+		}
+		if err := attr.validate(value); err != nil {
+			f.writeMu.Unlock()
+			return err
+		}
+		targets[id] = attr
+	}
+
+	for id, attr := range targets {
+		attr.store(attrs[id]) // validated above; advances the data version
+	}
+	f.writeMu.Unlock()
+
+	// Notify subscribers outside the write lock so they may write back.
+	for id := range targets {
+		f.notifyAttributeChanged(id, attrs[id])
+	}
+	return nil
+}
+
 // SetAttributeInternal sets an attribute value without checking write access.
 // Used by device implementations to update read-only attributes (e.g., measurements).
 func (f *Feature) SetAttributeInternal(id uint16, value any) error {
@@ -225,6 +314,8 @@ func (f *Feature) ReadAllAttributes() map[uint16]any {
 				result[id] = f.attributeListUnlocked()
 			} else if id == AttrIDCommandList {
 				result[id] = f.commandListUnlocked()
+			} else if id == AttrIDDataVersion {
+				result[id] = f.DataVersion()
 			} else {
 				result[id] = attr.Value()
 			}
@@ -240,6 +331,14 @@ func (f *Feature) SetReadHook(hook ReadHook) {
 	f.readHook = hook
 }
 
+// ReadHook returns the currently installed read hook, or nil if none is set.
+// Callers installing a new hook can use it to chain to the previous one.
+func (f *Feature) ReadHook() ReadHook {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return f.readHook
+}
+
 // ReadAttributeWithContext reads an attribute value by ID, passing context to
 // the read hook if one is set. The hook may override the returned value based
 // on the caller's context (e.g., zone identity).
@@ -251,6 +350,9 @@ func (f *Feature) ReadAttributeWithContext(ctx context.Context, id uint16) (any,
 	if id == AttrIDCommandList {
 		return f.CommandList(), nil
 	}
+	if id == AttrIDDataVersion {
+		return f.DataVersion(), nil
+	}
 
 	attr, err := f.GetAttribute(id)
 	if err != nil {
@@ -301,6 +403,10 @@ func (f *Feature) ReadAllAttributesWithContext(ctx context.Context) map[uint16]a
 			result[id] = f.CommandList()
 			continue
 		}
+		if id == AttrIDDataVersion {
+			result[id] = f.DataVersion()
+			continue
+		}
 
 		// Try hook first
 		if hook != nil {
@@ -377,6 +483,60 @@ func (f *Feature) commandListUnlocked() []uint8 {
 	return ids
 }
 
+// AddEvent registers an event the feature can emit.
+func (f *Feature) AddEvent(meta *EventMetadata) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.events[meta.ID] = meta
+}
+
+// GetEvent returns the metadata of a registered event.
+func (f *Feature) GetEvent(id uint8) (*EventMetadata, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	meta, exists := f.events[id]
+	if !exists {
+		return nil, ErrEventNotFound
+	}
+	return meta, nil
+}
+
+// EventList returns the list of supported event IDs.
+func (f *Feature) EventList() []uint8 {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	ids := make([]uint8, 0, len(f.events))
+	for id := range f.events {
+		ids = append(ids, id)
+	}
+	slices.Sort(ids)
+	return ids
+}
+
+// EmitEvent emits a registered event to all subscribers that implement
+// FeatureEventSubscriber. Numbering and buffering are the responsibility
+// of the subscriber (see EventBuffer).
+func (f *Feature) EmitEvent(eventID uint8, data any) error {
+	f.mu.RLock()
+	_, exists := f.events[eventID]
+	subs := make([]FeatureSubscriber, len(f.subscribers))
+	copy(subs, f.subscribers)
+	f.mu.RUnlock()
+
+	if !exists {
+		return ErrEventNotFound
+	}
+
+	for _, sub := range subs {
+		if es, ok := sub.(FeatureEventSubscriber); ok {
+			es.OnEventEmitted(f.featureType, eventID, data)
+		}
+	}
+	return nil
+}
+
 // Subscribe adds a subscriber for change notifications.
 func (f *Feature) Subscribe(sub FeatureSubscriber) {
 	f.mu.Lock()
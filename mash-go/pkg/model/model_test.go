@@ -2,7 +2,10 @@ package model
 
 import (
 	"context"
+	"errors"
+	"slices"
 	"testing"
+	"time"
 )
 
 func TestAttributeBasics(t *testing.T) {
@@ -370,6 +373,112 @@ func TestFeature(t *testing.T) {
 	})
 }
 
+func TestFeatureDataVersion(t *testing.T) {
+	feature := NewFeature(FeatureStatus, 1)
+	feature.AddAttribute(NewAttribute(&AttributeMetadata{
+		ID:       AttrIDPrimary,
+		Name:     "limit",
+		Type:     DataTypeUint8,
+		Access:   AccessReadWrite,
+		MaxValue: uint8(100),
+	}))
+	feature.AddAttribute(NewAttribute(&AttributeMetadata{
+		ID:     AttrIDPrimary + 1,
+		Name:   "label",
+		Type:   DataTypeString,
+		Access: AccessReadWrite,
+	}))
+
+	v0 := feature.DataVersion()
+	if got, err := feature.ReadAttribute(AttrIDDataVersion); err != nil || got != v0 {
+		t.Fatalf("ReadAttribute(dataVersion) = %v, %v; want %d", got, err, v0)
+	}
+	if !slices.Contains(feature.AttributeList(), AttrIDDataVersion) {
+		t.Error("attributeList should contain dataVersion")
+	}
+
+	t.Run("ChangesOnWrite", func(t *testing.T) {
+		before := feature.DataVersion()
+		if err := feature.WriteAttribute(AttrIDPrimary, uint8(10)); err != nil {
+			t.Fatalf("WriteAttribute failed: %v", err)
+		}
+		if feature.DataVersion() == before {
+			t.Error("data version should change on write")
+		}
+		before = feature.DataVersion()
+		if err := feature.SetAttributeInternal(AttrIDPrimary+1, "x"); err != nil {
+			t.Fatalf("SetAttributeInternal failed: %v", err)
+		}
+		if feature.DataVersion() == before {
+			t.Error("data version should change on internal update")
+		}
+		before = feature.DataVersion()
+		if err := feature.SetAttributeInternal(AttrIDPrimary+1, "x"); err != nil {
+			t.Fatalf("SetAttributeInternal failed: %v", err)
+		}
+		if feature.DataVersion() != before {
+			t.Error("data version should not change when the value is unchanged")
+		}
+	})
+
+	t.Run("InternalSetWaitsForWrite", func(t *testing.T) {
+		// An internal update must not land between a conditional write's
+		// version check and its store.
+		feature.writeMu.Lock()
+		done := make(chan struct{})
+		go func() {
+			_ = feature.SetAttributeInternal(AttrIDPrimary+1, "y")
+			close(done)
+		}()
+		select {
+		case <-done:
+			t.Fatal("internal set ran while a write held the feature")
+		case <-time.After(20 * time.Millisecond):
+		}
+		feature.writeMu.Unlock()
+		<-done
+	})
+
+	t.Run("ConditionalWrite", func(t *testing.T) {
+		version := feature.DataVersion()
+		if err := feature.WriteAttributes(map[uint16]any{AttrIDPrimary: uint8(20)}, &version); err != nil {
+			t.Fatalf("WriteAttributes with current version failed: %v", err)
+		}
+
+		// The version used above is now stale.
+		err := feature.WriteAttributes(map[uint16]any{AttrIDPrimary: uint8(30)}, &version)
+		if !errors.Is(err, ErrVersionMismatch) {
+			t.Fatalf("expected ErrVersionMismatch, got %v", err)
+		}
+		if v, _ := feature.ReadAttribute(AttrIDPrimary); v != uint8(20) {
+			t.Errorf("stale write must not apply, value = %v", v)
+		}
+	})
+
+	t.Run("AllOrNothing", func(t *testing.T) {
+		before := feature.DataVersion()
+		err := feature.WriteAttributes(map[uint16]any{
+			AttrIDPrimary + 1: "applied?",
+			AttrIDPrimary:     uint8(200), // out of range
+		}, nil)
+		if !errors.Is(err, ErrAttributeOutOfRange) {
+			t.Fatalf("expected ErrAttributeOutOfRange, got %v", err)
+		}
+		if v, _ := feature.ReadAttribute(AttrIDPrimary + 1); v != "y" {
+			t.Errorf("no attribute may be written when one fails, label = %v", v)
+		}
+		if feature.DataVersion() != before {
+			t.Error("data version should not change on a failed write")
+		}
+	})
+
+	t.Run("NotWritable", func(t *testing.T) {
+		if err := feature.WriteAttributes(map[uint16]any{AttrIDDataVersion: uint32(1)}, nil); err != ErrFeatureReadOnly {
+			t.Errorf("expected ErrFeatureReadOnly, got %v", err)
+		}
+	})
+}
+
 func TestFeatureSubscriber(t *testing.T) {
 	feature := NewFeature(FeatureStatus, 1)
 
@@ -4,6 +4,8 @@ import (
 	"errors"
 	"sync"
 	"time"
+
+	"github.com/mash-protocol/mash-go/pkg/clock"
 )
 
 // Window state constants.
@@ -92,8 +94,9 @@ type Window struct {
 	// Timeout for the open window
 	timeout time.Duration
 
-	// Timer for auto-close
-	timer *time.Timer
+	// Time source and timer for auto-close
+	clock clock.Clock
+	timer clock.Timer
 
 	// When the window was opened
 	openedAt time.Time
@@ -111,12 +114,25 @@ type Window struct {
 
 // NewWindow creates a new commissioning window with default settings.
 func NewWindow() *Window {
+	return NewWindowWithClock(clock.Real())
+}
+
+// NewWindowWithClock creates a commissioning window with default settings
+// that reads time from c instead of the real clock.
+func NewWindowWithClock(c clock.Clock) *Window {
 	return &Window{
 		state:   WindowClosed,
 		timeout: DefaultWindowTimeout,
+		clock:   clock.OrReal(c),
 	}
 }
 
+// timeSource returns the window's clock, defaulting to the real clock for
+// zero-value windows.
+func (w *Window) timeSource() clock.Clock {
+	return clock.OrReal(w.clock)
+}
+
 // State returns the current window state.
 func (w *Window) State() WindowState {
 	w.mu.RLock()
@@ -155,7 +171,7 @@ func (w *Window) RemainingTime() time.Duration {
 		return 0
 	}
 
-	elapsed := time.Since(w.openedAt)
+	elapsed := w.timeSource().Since(w.openedAt)
 	remaining := w.timeout - elapsed
 	if remaining < 0 {
 		return 0
@@ -198,12 +214,12 @@ func (w *Window) Open(trigger OpenTrigger) error {
 
 	oldState := w.state
 	w.state = WindowOpen
-	w.openedAt = time.Now()
+	w.openedAt = w.timeSource().Now()
 	w.openTrigger = trigger
 	w.sessionID = ""
 
 	// Start timeout timer
-	w.timer = time.AfterFunc(w.timeout, func() {
+	w.timer = w.timeSource().AfterFunc(w.timeout, func() {
 		w.handleTimeout()
 	})
 
@@ -367,8 +383,8 @@ func (w *Window) resetTimer() {
 	if w.timer != nil {
 		w.timer.Stop()
 	}
-	w.openedAt = time.Now()
-	w.timer = time.AfterFunc(w.timeout, func() {
+	w.openedAt = w.timeSource().Now()
+	w.timer = w.timeSource().AfterFunc(w.timeout, func() {
 		w.handleTimeout()
 	})
 }
@@ -380,7 +396,7 @@ func (w *Window) RemainingTimeUnlocked() time.Duration {
 		return 0
 	}
 
-	elapsed := time.Since(w.openedAt)
+	elapsed := w.timeSource().Since(w.openedAt)
 	remaining := w.timeout - elapsed
 	if remaining < 0 {
 		return 0
@@ -4,6 +4,8 @@ import (
 	"sync"
 	"testing"
 	"time"
+
+	"github.com/mash-protocol/mash-go/pkg/clock"
 )
 
 func TestWindowInitialState(t *testing.T) {
@@ -209,6 +211,40 @@ func TestWindowTimeout(t *testing.T) {
 	}
 }
 
+func TestWindowTimeoutWithFakeClock(t *testing.T) {
+	fc := clock.NewFake(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+	w := NewWindowWithClock(fc)
+
+	timeouts := 0
+	w.OnTimeout(func() { timeouts++ })
+
+	if err := w.Open(TriggerButton); err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+
+	fc.Advance(10 * time.Minute)
+	if got := w.RemainingTime(); got != 5*time.Minute {
+		t.Errorf("RemainingTime() = %v, want 5m", got)
+	}
+
+	// Re-opening restarts the full timeout.
+	if err := w.Open(TriggerButton); err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	fc.Advance(10 * time.Minute)
+	if !w.IsOpen() {
+		t.Fatal("window closed before the extended timeout")
+	}
+
+	fc.Advance(5 * time.Minute)
+	if w.State() != WindowClosed {
+		t.Errorf("State() = %v, want WindowClosed after timeout", w.State())
+	}
+	if timeouts != 1 {
+		t.Errorf("OnTimeout called %d times, want 1", timeouts)
+	}
+}
+
 func TestWindowSetTimeoutValidation(t *testing.T) {
 	w := NewWindow()
 
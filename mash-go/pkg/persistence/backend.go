@@ -0,0 +1,237 @@
+package persistence
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Keys under which the state stores keep their documents when created with
+// a shared backend.
+const (
+	DeviceStateKey     = "device-state"
+	ControllerStateKey = "controller-state"
+)
+
+// ErrNotFound is returned by StateBackend.Read when no document is stored
+// under the key.
+var ErrNotFound = errors.New("persistence: state not found")
+
+// StateBackend stores state documents by key. Implementations must make
+// Write atomic: a crash during Write leaves either the old or the new
+// document, never a partial one.
+type StateBackend interface {
+	// Read returns the document stored under key, or ErrNotFound.
+	Read(key string) ([]byte, error)
+
+	// Write atomically replaces the document stored under key.
+	Write(key string, data []byte) error
+
+	// Delete removes the document stored under key. Deleting an absent
+	// key is not an error.
+	Delete(key string) error
+
+	// Keys returns the stored keys that start with prefix, sorted.
+	Keys(prefix string) ([]string, error)
+
+	// Close releases any resources held by the backend.
+	Close() error
+}
+
+// FileBackend stores each document as a file in a directory. Writes go to
+// a temporary file that is synced and renamed over the target, so a crash
+// never leaves a truncated state file.
+type FileBackend struct {
+	dir string
+}
+
+// NewFileBackend creates a backend that stores documents in dir. The
+// directory is created on first write.
+func NewFileBackend(dir string) *FileBackend {
+	return &FileBackend{dir: dir}
+}
+
+// Read returns the contents of the file named key.
+func (b *FileBackend) Read(key string) ([]byte, error) {
+	data, err := os.ReadFile(b.path(key))
+	if os.IsNotExist(err) {
+		return nil, ErrNotFound
+	}
+	return data, err
+}
+
+// Write atomically replaces the file named key.
+func (b *FileBackend) Write(key string, data []byte) error {
+	if err := os.MkdirAll(b.dir, 0755); err != nil {
+		return err
+	}
+	return writeFileAtomic(b.path(key), data, 0644)
+}
+
+// Delete removes the file named key.
+func (b *FileBackend) Delete(key string) error {
+	err := os.Remove(b.path(key))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
+
+// Keys returns the names of regular files in the directory that start with
+// prefix. Leftover temporary files from interrupted writes are skipped.
+func (b *FileBackend) Keys(prefix string) ([]string, error) {
+	entries, err := os.ReadDir(b.dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var keys []string
+	for _, e := range entries {
+		name := e.Name()
+		if !e.Type().IsRegular() || isTempFile(name) || !strings.HasPrefix(name, prefix) {
+			continue
+		}
+		keys = append(keys, name)
+	}
+	return keys, nil
+}
+
+// Close is a no-op for FileBackend.
+func (b *FileBackend) Close() error {
+	return nil
+}
+
+func (b *FileBackend) path(key string) string {
+	return filepath.Join(b.dir, key)
+}
+
+// tempFilePrefix marks in-progress atomic writes.
+const tempFilePrefix = ".tmp-"
+
+func isTempFile(name string) bool {
+	return strings.HasPrefix(name, tempFilePrefix)
+}
+
+// writeFileAtomic writes data to path via a synced temporary file in the
+// same directory followed by a rename, then syncs the directory so the
+// rename itself is durable.
+func writeFileAtomic(path string, data []byte, perm os.FileMode) error {
+	dir := filepath.Dir(path)
+
+	tmp, err := os.CreateTemp(dir, tempFilePrefix+filepath.Base(path)+"-*")
+	if err != nil {
+		return err
+	}
+	tmpName := tmp.Name()
+	committed := false
+	defer func() {
+		if !committed {
+			_ = os.Remove(tmpName)
+		}
+	}()
+
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Chmod(perm); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	if err := os.Rename(tmpName, path); err != nil {
+		return err
+	}
+	committed = true
+
+	return syncDir(dir)
+}
+
+// syncDir fsyncs a directory so that a preceding rename is durable.
+func syncDir(dir string) error {
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer d.Close()
+
+	if err := d.Sync(); err != nil && !errors.Is(err, os.ErrInvalid) {
+		return err
+	}
+	return nil
+}
+
+// MemoryBackend keeps documents in memory. It is intended for tests and
+// for devices that should not persist state.
+type MemoryBackend struct {
+	mu   sync.Mutex
+	docs map[string][]byte
+}
+
+// NewMemoryBackend creates an empty in-memory backend.
+func NewMemoryBackend() *MemoryBackend {
+	return &MemoryBackend{docs: make(map[string][]byte)}
+}
+
+// Read returns a copy of the document stored under key.
+func (b *MemoryBackend) Read(key string) ([]byte, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	data, ok := b.docs[key]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return append([]byte(nil), data...), nil
+}
+
+// Write stores a copy of data under key.
+func (b *MemoryBackend) Write(key string, data []byte) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.docs[key] = append([]byte(nil), data...)
+	return nil
+}
+
+// Delete removes the document stored under key.
+func (b *MemoryBackend) Delete(key string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	delete(b.docs, key)
+	return nil
+}
+
+// Keys returns the stored keys that start with prefix, sorted.
+func (b *MemoryBackend) Keys(prefix string) ([]string, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var keys []string
+	for k := range b.docs {
+		if strings.HasPrefix(k, prefix) {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
+
+// Close is a no-op for MemoryBackend.
+func (b *MemoryBackend) Close() error {
+	return nil
+}
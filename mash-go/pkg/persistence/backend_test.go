@@ -0,0 +1,174 @@
+package persistence
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestStateBackends(t *testing.T) {
+	backends := map[string]func(t *testing.T) StateBackend{
+		"File": func(t *testing.T) StateBackend {
+			return NewFileBackend(filepath.Join(t.TempDir(), "state"))
+		},
+		"Memory": func(t *testing.T) StateBackend {
+			return NewMemoryBackend()
+		},
+	}
+
+	for name, newBackend := range backends {
+		t.Run(name, func(t *testing.T) {
+			b := newBackend(t)
+			defer b.Close()
+
+			if _, err := b.Read("missing"); !errors.Is(err, ErrNotFound) {
+				t.Errorf("Read(missing) error = %v, want ErrNotFound", err)
+			}
+			keys, err := b.Keys("")
+			if err != nil || len(keys) != 0 {
+				t.Errorf("Keys() on empty backend = %v, %v", keys, err)
+			}
+
+			if err := b.Write("device-a", []byte("one")); err != nil {
+				t.Fatalf("Write() error = %v", err)
+			}
+			if err := b.Write("device-a", []byte("two")); err != nil {
+				t.Fatalf("Write() overwrite error = %v", err)
+			}
+			if err := b.Write("device-b", []byte("three")); err != nil {
+				t.Fatalf("Write() error = %v", err)
+			}
+			if err := b.Write("other", []byte("four")); err != nil {
+				t.Fatalf("Write() error = %v", err)
+			}
+
+			data, err := b.Read("device-a")
+			if err != nil {
+				t.Fatalf("Read() error = %v", err)
+			}
+			if string(data) != "two" {
+				t.Errorf("Read() = %q, want %q", data, "two")
+			}
+
+			keys, err = b.Keys("device-")
+			if err != nil {
+				t.Fatalf("Keys() error = %v", err)
+			}
+			if len(keys) != 2 || keys[0] != "device-a" || keys[1] != "device-b" {
+				t.Errorf("Keys() = %v, want [device-a device-b]", keys)
+			}
+
+			if err := b.Delete("device-a"); err != nil {
+				t.Fatalf("Delete() error = %v", err)
+			}
+			if err := b.Delete("device-a"); err != nil {
+				t.Errorf("Delete() of absent key error = %v", err)
+			}
+			if _, err := b.Read("device-a"); !errors.Is(err, ErrNotFound) {
+				t.Errorf("Read() after Delete error = %v, want ErrNotFound", err)
+			}
+		})
+	}
+}
+
+func TestFileBackendAtomicWrite(t *testing.T) {
+	dir := t.TempDir()
+	b := NewFileBackend(dir)
+
+	if err := b.Write("state.json", []byte("old")); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+
+	// A leftover temp file from an interrupted write must not be reported
+	// as a key or disturb the committed document.
+	leftover := filepath.Join(dir, tempFilePrefix+"state.json-123")
+	if err := os.WriteFile(leftover, []byte("partial"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := b.Write("state.json", []byte("new")); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+
+	data, err := b.Read("state.json")
+	if err != nil || string(data) != "new" {
+		t.Errorf("Read() = %q, %v; want %q", data, err, "new")
+	}
+
+	keys, err := b.Keys("")
+	if err != nil {
+		t.Fatalf("Keys() error = %v", err)
+	}
+	if len(keys) != 1 || keys[0] != "state.json" {
+		t.Errorf("Keys() = %v, want [state.json]", keys)
+	}
+
+	info, err := os.Stat(filepath.Join(dir, "state.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm := info.Mode().Perm(); perm != 0644 {
+		t.Errorf("file mode = %o, want 644", perm)
+	}
+
+	entries, _ := os.ReadDir(dir)
+	for _, e := range entries {
+		if isTempFile(e.Name()) && e.Name() != filepath.Base(leftover) {
+			t.Errorf("temp file %s left behind after successful write", e.Name())
+		}
+	}
+}
+
+func TestMemoryBackendCopiesData(t *testing.T) {
+	b := NewMemoryBackend()
+
+	buf := []byte("abc")
+	if err := b.Write("k", buf); err != nil {
+		t.Fatal(err)
+	}
+	buf[0] = 'x'
+
+	data, _ := b.Read("k")
+	data[1] = 'y'
+
+	again, _ := b.Read("k")
+	if string(again) != "abc" {
+		t.Errorf("stored document = %q, want %q", again, "abc")
+	}
+}
+
+func TestStateStoreWithBackend(t *testing.T) {
+	b := NewMemoryBackend()
+
+	devices := NewDeviceStateStoreWithBackend(b)
+	controllers := NewControllerStateStoreWithBackend(b)
+
+	if err := devices.Save(&DeviceState{ZoneIndexMap: map[string]uint8{"zone-a": 1}}); err != nil {
+		t.Fatalf("device Save() error = %v", err)
+	}
+	if err := controllers.Save(&ControllerState{ZoneID: "zone-a"}); err != nil {
+		t.Fatalf("controller Save() error = %v", err)
+	}
+
+	keys, _ := b.Keys("")
+	if len(keys) != 2 {
+		t.Errorf("Keys() = %v, want device and controller keys", keys)
+	}
+
+	ds, err := devices.Load()
+	if err != nil || ds == nil || ds.ZoneIndexMap["zone-a"] != 1 {
+		t.Errorf("device Load() = %+v, %v", ds, err)
+	}
+	cs, err := controllers.Load()
+	if err != nil || cs == nil || cs.ZoneID != "zone-a" {
+		t.Errorf("controller Load() = %+v, %v", cs, err)
+	}
+
+	if err := devices.Clear(); err != nil {
+		t.Fatalf("Clear() error = %v", err)
+	}
+	if ds, _ := devices.Load(); ds != nil {
+		t.Errorf("Load() after Clear = %+v, want nil", ds)
+	}
+}
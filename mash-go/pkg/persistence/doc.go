@@ -1,6 +1,9 @@
 // Package persistence provides runtime state persistence for MASH devices and controllers.
 //
 // This package handles the JSON serialization of runtime state (zone memberships,
-// failsafe timer snapshots, zone index mappings) that must survive device restarts.
+// failsafe timer snapshots, zone index mappings, active limits and setpoints)
+// that must survive device restarts. State is written through a StateBackend:
+// FileBackend (atomic file writes), MemoryBackend, or the SQLite backend in the
+// sqlite subpackage. Load upgrades older documents with versioned migrations.
 // Certificate storage is handled separately by the cert package's FileStore.
 package persistence
@@ -0,0 +1,80 @@
+package persistence
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// ErrUnsupportedVersion is returned when a state document was written by a
+// newer format version than this build understands.
+var ErrUnsupportedVersion = errors.New("persistence: unsupported state version")
+
+// Migration upgrades a raw state document from version From to From+1.
+// Migrate operates on the decoded JSON object and may add, rename or
+// remove fields.
+type Migration struct {
+	From    int
+	Migrate func(doc map[string]any) error
+}
+
+// deviceMigrations upgrades DeviceState documents. When StateVersion is
+// bumped, append a step from the previous version here.
+var deviceMigrations = []Migration{
+	{From: 0, Migrate: migrateUnversioned},
+}
+
+// controllerMigrations upgrades ControllerState documents.
+var controllerMigrations = []Migration{
+	{From: 0, Migrate: migrateUnversioned},
+}
+
+// migrateUnversioned upgrades documents written before the version field
+// existed. Their layout matches version 1.
+func migrateUnversioned(doc map[string]any) error {
+	return nil
+}
+
+// migrateDocument upgrades data to target using migrations. It returns the
+// upgraded document and whether any step was applied. Documents newer than
+// target are rejected with ErrUnsupportedVersion.
+func migrateDocument(data []byte, target int, migrations []Migration) ([]byte, bool, error) {
+	var doc map[string]any
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, false, err
+	}
+
+	version := 0
+	if v, ok := doc["version"].(float64); ok {
+		version = int(v)
+	}
+	if version > target {
+		return nil, false, fmt.Errorf("%w: %d (newest supported is %d)", ErrUnsupportedVersion, version, target)
+	}
+	if version == target {
+		return data, false, nil
+	}
+
+	steps := make(map[int]Migration, len(migrations))
+	for _, m := range migrations {
+		steps[m.From] = m
+	}
+
+	for version < target {
+		step, ok := steps[version]
+		if !ok {
+			return nil, false, fmt.Errorf("persistence: no migration from state version %d", version)
+		}
+		if err := step.Migrate(doc); err != nil {
+			return nil, false, fmt.Errorf("persistence: migrating state version %d: %w", version, err)
+		}
+		version++
+		doc["version"] = version
+	}
+
+	out, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, false, err
+	}
+	return out, true, nil
+}
@@ -0,0 +1,127 @@
+package persistence
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestMigrateDocument(t *testing.T) {
+	t.Run("CurrentVersionUnchanged", func(t *testing.T) {
+		in := []byte(`{"version":2,"a":1}`)
+		out, migrated, err := migrateDocument(in, 2, nil)
+		if err != nil {
+			t.Fatalf("migrateDocument() error = %v", err)
+		}
+		if migrated || string(out) != string(in) {
+			t.Errorf("migrateDocument() = %s, %v; want input unchanged", out, migrated)
+		}
+	})
+
+	t.Run("AppliesStepsInOrder", func(t *testing.T) {
+		migrations := []Migration{
+			{From: 1, Migrate: func(doc map[string]any) error {
+				doc["renamed"] = doc["old"]
+				delete(doc, "old")
+				return nil
+			}},
+			{From: 0, Migrate: func(doc map[string]any) error {
+				doc["old"] = "value"
+				return nil
+			}},
+		}
+
+		out, migrated, err := migrateDocument([]byte(`{}`), 2, migrations)
+		if err != nil {
+			t.Fatalf("migrateDocument() error = %v", err)
+		}
+		if !migrated {
+			t.Error("migrated = false, want true")
+		}
+
+		var doc map[string]any
+		if err := json.Unmarshal(out, &doc); err != nil {
+			t.Fatal(err)
+		}
+		if doc["version"] != float64(2) {
+			t.Errorf("version = %v, want 2", doc["version"])
+		}
+		if doc["renamed"] != "value" {
+			t.Errorf("renamed = %v, want value", doc["renamed"])
+		}
+		if _, ok := doc["old"]; ok {
+			t.Error("old field should have been removed")
+		}
+	})
+
+	t.Run("NewerVersionRejected", func(t *testing.T) {
+		_, _, err := migrateDocument([]byte(`{"version":3}`), 2, nil)
+		if !errors.Is(err, ErrUnsupportedVersion) {
+			t.Errorf("error = %v, want ErrUnsupportedVersion", err)
+		}
+	})
+
+	t.Run("MissingStep", func(t *testing.T) {
+		_, _, err := migrateDocument([]byte(`{"version":1}`), 2, nil)
+		if err == nil {
+			t.Error("expected error for missing migration step")
+		}
+	})
+
+	t.Run("StepError", func(t *testing.T) {
+		boom := errors.New("boom")
+		migrations := []Migration{{From: 0, Migrate: func(map[string]any) error { return boom }}}
+		_, _, err := migrateDocument([]byte(`{}`), 1, migrations)
+		if !errors.Is(err, boom) {
+			t.Errorf("error = %v, want wrapped step error", err)
+		}
+	})
+}
+
+func TestStateStoreMigratesOnLoad(t *testing.T) {
+	b := NewMemoryBackend()
+
+	// A state document written before the version field existed.
+	legacy := `{"saved_at":"2025-06-01T00:00:00Z","zone_index_map":{"zone-a":1}}`
+	if err := b.Write(DeviceStateKey, []byte(legacy)); err != nil {
+		t.Fatal(err)
+	}
+
+	store := NewDeviceStateStoreWithBackend(b)
+	state, err := store.Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if state.Version != StateVersion {
+		t.Errorf("Version = %d, want %d", state.Version, StateVersion)
+	}
+	if state.ZoneIndexMap["zone-a"] != 1 {
+		t.Errorf("ZoneIndexMap = %v, want zone-a:1", state.ZoneIndexMap)
+	}
+
+	// The upgraded document is written back.
+	data, _ := b.Read(DeviceStateKey)
+	var doc map[string]any
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatal(err)
+	}
+	if doc["version"] != float64(StateVersion) {
+		t.Errorf("stored version = %v, want %d", doc["version"], StateVersion)
+	}
+}
+
+func TestStateStoreRejectsNewerVersion(t *testing.T) {
+	b := NewMemoryBackend()
+	if err := b.Write(ControllerStateKey, []byte(`{"version":99,"zone_id":"z"}`)); err != nil {
+		t.Fatal(err)
+	}
+
+	store := NewControllerStateStoreWithBackend(b)
+	state, err := store.Load()
+	if !errors.Is(err, ErrUnsupportedVersion) {
+		t.Errorf("Load() error = %v, want ErrUnsupportedVersion", err)
+	}
+	if state != nil {
+		t.Errorf("Load() = %+v, want nil on error", state)
+	}
+}
@@ -0,0 +1,128 @@
+// Package sqlite provides a SQLite-backed persistence.StateBackend.
+//
+// It lives in its own package so that devices using the file backend do not
+// need cgo. Documents are stored as JSON text, so controllers can query
+// them directly with SQLite's JSON functions, e.g.
+//
+//	SELECT json_extract(value, '$.zone_id') FROM state WHERE key = 'controller-state'
+package sqlite
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	_ "github.com/mattn/go-sqlite3"
+
+	"github.com/mash-protocol/mash-go/pkg/persistence"
+)
+
+// Backend stores state documents in a SQLite database.
+type Backend struct {
+	db *sql.DB
+}
+
+var _ persistence.StateBackend = (*Backend)(nil)
+
+// NewBackend opens (or creates) the database at path.
+// Use ":memory:" for an in-memory database.
+func NewBackend(path string) (*Backend, error) {
+	db, err := sql.Open("sqlite3", path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+
+	// A single connection keeps ":memory:" databases shared and serialises
+	// writers; WAL with full sync makes each committed write durable.
+	db.SetMaxOpenConns(1)
+
+	_, err = db.Exec(`
+		PRAGMA journal_mode = WAL;
+		PRAGMA synchronous = FULL;
+	`)
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to configure database: %w", err)
+	}
+
+	b := &Backend{db: db}
+
+	if err := b.migrate(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to migrate database: %w", err)
+	}
+
+	return b, nil
+}
+
+// migrate creates the database schema.
+func (b *Backend) migrate() error {
+	_, err := b.db.Exec(`
+	CREATE TABLE IF NOT EXISTS state (
+		key TEXT PRIMARY KEY,
+		value TEXT NOT NULL,
+		updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+	);
+	`)
+	return err
+}
+
+// DB returns the underlying database for ad-hoc queries over stored state.
+func (b *Backend) DB() *sql.DB {
+	return b.db
+}
+
+// Read returns the document stored under key.
+func (b *Backend) Read(key string) ([]byte, error) {
+	var value string
+	err := b.db.QueryRow(`SELECT value FROM state WHERE key = ?`, key).Scan(&value)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, persistence.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return []byte(value), nil
+}
+
+// Write replaces the document stored under key in a single transaction.
+func (b *Backend) Write(key string, data []byte) error {
+	_, err := b.db.Exec(`
+		INSERT INTO state (key, value, updated_at) VALUES (?, ?, CURRENT_TIMESTAMP)
+		ON CONFLICT(key) DO UPDATE SET value = excluded.value, updated_at = excluded.updated_at
+	`, key, string(data))
+	return err
+}
+
+// Delete removes the document stored under key.
+func (b *Backend) Delete(key string) error {
+	_, err := b.db.Exec(`DELETE FROM state WHERE key = ?`, key)
+	return err
+}
+
+// Keys returns the stored keys that start with prefix, sorted.
+func (b *Backend) Keys(prefix string) ([]string, error) {
+	rows, err := b.db.Query(
+		`SELECT key FROM state WHERE substr(key, 1, length(?)) = ? ORDER BY key`,
+		prefix, prefix,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var keys []string
+	for rows.Next() {
+		var key string
+		if err := rows.Scan(&key); err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+	return keys, rows.Err()
+}
+
+// Close closes the database.
+func (b *Backend) Close() error {
+	return b.db.Close()
+}
@@ -0,0 +1,101 @@
+package sqlite
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/mash-protocol/mash-go/pkg/persistence"
+)
+
+func TestBackend(t *testing.T) {
+	b, err := NewBackend(filepath.Join(t.TempDir(), "state.db"))
+	if err != nil {
+		t.Fatalf("NewBackend failed: %v", err)
+	}
+	defer b.Close()
+
+	if _, err := b.Read("missing"); !errors.Is(err, persistence.ErrNotFound) {
+		t.Errorf("Read(missing) error = %v, want ErrNotFound", err)
+	}
+
+	if err := b.Write("device-a", []byte(`{"v":1}`)); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	if err := b.Write("device-a", []byte(`{"v":2}`)); err != nil {
+		t.Fatalf("overwrite failed: %v", err)
+	}
+	if err := b.Write("device-b", []byte(`{"v":3}`)); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	if err := b.Write("other", []byte(`{}`)); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+
+	data, err := b.Read("device-a")
+	if err != nil {
+		t.Fatalf("Read failed: %v", err)
+	}
+	if string(data) != `{"v":2}` {
+		t.Errorf("Read = %s, want {\"v\":2}", data)
+	}
+
+	keys, err := b.Keys("device-")
+	if err != nil {
+		t.Fatalf("Keys failed: %v", err)
+	}
+	if len(keys) != 2 || keys[0] != "device-a" || keys[1] != "device-b" {
+		t.Errorf("Keys = %v, want [device-a device-b]", keys)
+	}
+
+	if err := b.Delete("device-a"); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if err := b.Delete("device-a"); err != nil {
+		t.Errorf("Delete of absent key should succeed: %v", err)
+	}
+	if _, err := b.Read("device-a"); !errors.Is(err, persistence.ErrNotFound) {
+		t.Errorf("Read after Delete error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestControllerStateQueryable(t *testing.T) {
+	b, err := NewBackend(":memory:")
+	if err != nil {
+		t.Fatalf("NewBackend failed: %v", err)
+	}
+	defer b.Close()
+
+	store := persistence.NewControllerStateStoreWithBackend(b)
+	err = store.Save(&persistence.ControllerState{
+		ZoneID: "zone-1",
+		Devices: []persistence.DeviceMembership{
+			{DeviceID: "evse-1", DeviceType: "EVSE", JoinedAt: time.Now()},
+			{DeviceID: "inv-1", DeviceType: "INVERTER", JoinedAt: time.Now()},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	var count int
+	err = b.DB().QueryRow(
+		`SELECT json_array_length(value, '$.devices') FROM state WHERE key = ?`,
+		persistence.ControllerStateKey,
+	).Scan(&count)
+	if err != nil {
+		t.Fatalf("query failed: %v", err)
+	}
+	if count != 2 {
+		t.Errorf("device count = %d, want 2", count)
+	}
+
+	loaded, err := store.Load()
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if loaded == nil || loaded.ZoneID != "zone-1" || len(loaded.Devices) != 2 {
+		t.Errorf("Load = %+v, want zone-1 with 2 devices", loaded)
+	}
+}
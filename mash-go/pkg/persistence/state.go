@@ -2,7 +2,7 @@ package persistence
 
 import (
 	"encoding/json"
-	"os"
+	"errors"
 	"path/filepath"
 	"sync"
 	"time"
@@ -29,6 +29,20 @@ type DeviceState struct {
 	// ZoneIndexMap maps zone IDs to their endpoint indices.
 	// This ensures consistent endpoint assignments across restarts.
 	ZoneIndexMap map[string]uint8 `json:"zone_index_map,omitempty"`
+
+	// Limits contains the per-zone power limits in effect when saved.
+	// Limits with a duration keep their absolute expiry so the remaining
+	// time is honoured after restart.
+	Limits []ZoneValueSnapshot `json:"limits,omitempty"`
+
+	// Setpoints contains the per-zone power setpoints in effect when saved.
+	Setpoints []ZoneValueSnapshot `json:"setpoints,omitempty"`
+
+	// CurrentLimits contains the per-zone, per-phase current limits.
+	CurrentLimits []ZoneValueSnapshot `json:"current_limits,omitempty"`
+
+	// CurrentSetpoints contains the per-zone, per-phase current setpoints.
+	CurrentSetpoints []ZoneValueSnapshot `json:"current_setpoints,omitempty"`
 }
 
 // ZoneMembership contains information about a zone the device belongs to.
@@ -64,6 +78,30 @@ type FailsafeSnapshot struct {
 	Limits FailsafeLimits `json:"limits,omitempty"`
 }
 
+// ZoneValueSnapshot mirrors features.ZoneValueSnapshot for JSON serialization.
+type ZoneValueSnapshot struct {
+	// ZoneID is the zone that set the value.
+	ZoneID string `json:"zone_id"`
+
+	// ZoneType is the zone type (1=GRID, 2=LOCAL).
+	ZoneType uint8 `json:"zone_type"`
+
+	// Direction is the power direction (0=CONSUMPTION, 1=PRODUCTION).
+	Direction uint8 `json:"direction"`
+
+	// Phase is the device phase for per-phase current values.
+	Phase *uint8 `json:"phase,omitempty"`
+
+	// Value is the limit or setpoint (mW, or mA for current values).
+	Value int64 `json:"value"`
+
+	// Cause is the LimitCause or SetpointCause sent with the command.
+	Cause uint8 `json:"cause,omitempty"`
+
+	// ExpiresAt is when the value's duration elapses (zero if indefinite).
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
 // FailsafeLimits mirrors failsafe.Limits for JSON serialization.
 type FailsafeLimits struct {
 	ConsumptionLimit    int64 `json:"consumption_limit,omitempty"`
@@ -72,73 +110,61 @@ type FailsafeLimits struct {
 	HasProductionLimit  bool  `json:"has_production_limit,omitempty"`
 }
 
-// DeviceStateStore manages persistence of device state to a JSON file.
+// DeviceStateStore manages persistence of device state in a StateBackend.
 type DeviceStateStore struct {
-	mu   sync.Mutex
-	path string
+	mu      sync.Mutex
+	backend StateBackend
+	key     string
 }
 
-// NewDeviceStateStore creates a new device state store.
+// NewDeviceStateStore creates a device state store backed by the JSON file
+// at path.
 func NewDeviceStateStore(path string) *DeviceStateStore {
-	return &DeviceStateStore{path: path}
+	return &DeviceStateStore{
+		backend: NewFileBackend(filepath.Dir(path)),
+		key:     filepath.Base(path),
+	}
+}
+
+// NewDeviceStateStoreWithBackend creates a device state store that keeps its
+// state under DeviceStateKey in backend.
+func NewDeviceStateStoreWithBackend(backend StateBackend) *DeviceStateStore {
+	return &DeviceStateStore{backend: backend, key: DeviceStateKey}
 }
 
-// Save persists the device state to disk.
+// Save persists the device state.
 func (s *DeviceStateStore) Save(state *DeviceState) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	// Ensure parent directory exists
-	dir := filepath.Dir(s.path)
-	if err := os.MkdirAll(dir, 0755); err != nil {
-		return err
-	}
-
 	state.Version = StateVersion
 	if state.SavedAt.IsZero() {
 		state.SavedAt = time.Now()
 	}
 
-	data, err := json.MarshalIndent(state, "", "  ")
-	if err != nil {
-		return err
-	}
-
-	return os.WriteFile(s.path, data, 0644)
+	return saveDocument(s.backend, s.key, state)
 }
 
-// Load reads the device state from disk.
-// Returns nil, nil if the file doesn't exist (empty state).
+// Load reads the device state, upgrading documents written by older
+// versions. Returns nil, nil if no state has been saved.
 func (s *DeviceStateStore) Load() (*DeviceState, error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	data, err := os.ReadFile(s.path)
-	if os.IsNotExist(err) {
-		return nil, nil
-	}
-	if err != nil {
-		return nil, err
-	}
-
 	state := &DeviceState{}
-	if err := json.Unmarshal(data, state); err != nil {
+	found, err := loadDocument(s.backend, s.key, deviceMigrations, state)
+	if !found || err != nil {
 		return nil, err
 	}
-
 	return state, nil
 }
 
-// Clear removes the state file.
+// Clear removes the saved state.
 func (s *DeviceStateStore) Clear() error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	err := os.Remove(s.path)
-	if os.IsNotExist(err) {
-		return nil
-	}
-	return err
+	return s.backend.Delete(s.key)
 }
 
 // ControllerState contains the runtime state for a MASH controller.
@@ -154,6 +180,22 @@ type ControllerState struct {
 
 	// Devices contains info about commissioned devices.
 	Devices []DeviceMembership `json:"devices,omitempty"`
+
+	// UsedTickets are the commissioning tickets accepted and not yet
+	// expired, so that none is used again after a restart.
+	UsedTickets []UsedTicket `json:"used_tickets,omitempty"`
+}
+
+// UsedTicket records a commissioning ticket the controller has accepted.
+type UsedTicket struct {
+	// Issuer is the ticket issuer's name.
+	Issuer string `json:"issuer"`
+
+	// ID is the ticket ID, unique per issuer.
+	ID string `json:"id"`
+
+	// NotAfter is when the ticket expires.
+	NotAfter time.Time `json:"not_after"`
 }
 
 // DeviceMembership contains information about a commissioned device.
@@ -164,6 +206,9 @@ type DeviceMembership struct {
 	// DeviceType is the device type (EVSE, INVERTER, etc.).
 	DeviceType string `json:"device_type,omitempty"`
 
+	// Label is the application's name for the device, e.g. its fleet entry.
+	Label string `json:"label,omitempty"`
+
 	// JoinedAt is when the device was commissioned.
 	JoinedAt time.Time `json:"joined_at"`
 
@@ -171,70 +216,94 @@ type DeviceMembership struct {
 	LastSeenAt time.Time `json:"last_seen_at,omitempty"`
 }
 
-// ControllerStateStore manages persistence of controller state to a JSON file.
+// ControllerStateStore manages persistence of controller state in a
+// StateBackend.
 type ControllerStateStore struct {
-	mu   sync.Mutex
-	path string
+	mu      sync.Mutex
+	backend StateBackend
+	key     string
 }
 
-// NewControllerStateStore creates a new controller state store.
+// NewControllerStateStore creates a controller state store backed by the
+// JSON file at path.
 func NewControllerStateStore(path string) *ControllerStateStore {
-	return &ControllerStateStore{path: path}
+	return &ControllerStateStore{
+		backend: NewFileBackend(filepath.Dir(path)),
+		key:     filepath.Base(path),
+	}
 }
 
-// Save persists the controller state to disk.
+// NewControllerStateStoreWithBackend creates a controller state store that
+// keeps its state under ControllerStateKey in backend.
+func NewControllerStateStoreWithBackend(backend StateBackend) *ControllerStateStore {
+	return &ControllerStateStore{backend: backend, key: ControllerStateKey}
+}
+
+// Save persists the controller state.
 func (s *ControllerStateStore) Save(state *ControllerState) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	dir := filepath.Dir(s.path)
-	if err := os.MkdirAll(dir, 0755); err != nil {
-		return err
-	}
-
 	state.Version = StateVersion
 	if state.SavedAt.IsZero() {
 		state.SavedAt = time.Now()
 	}
 
-	data, err := json.MarshalIndent(state, "", "  ")
-	if err != nil {
-		return err
-	}
-
-	return os.WriteFile(s.path, data, 0644)
+	return saveDocument(s.backend, s.key, state)
 }
 
-// Load reads the controller state from disk.
-// Returns nil, nil if the file doesn't exist (empty state).
+// Load reads the controller state, upgrading documents written by older
+// versions. Returns nil, nil if no state has been saved.
 func (s *ControllerStateStore) Load() (*ControllerState, error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	data, err := os.ReadFile(s.path)
-	if os.IsNotExist(err) {
-		return nil, nil
-	}
-	if err != nil {
-		return nil, err
-	}
-
 	state := &ControllerState{}
-	if err := json.Unmarshal(data, state); err != nil {
+	found, err := loadDocument(s.backend, s.key, controllerMigrations, state)
+	if !found || err != nil {
 		return nil, err
 	}
-
 	return state, nil
 }
 
-// Clear removes the state file.
+// Clear removes the saved state.
 func (s *ControllerStateStore) Clear() error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	err := os.Remove(s.path)
-	if os.IsNotExist(err) {
-		return nil
+	return s.backend.Delete(s.key)
+}
+
+// saveDocument encodes v as indented JSON and writes it under key.
+func saveDocument(backend StateBackend, key string, v any) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
 	}
-	return err
+	return backend.Write(key, data)
+}
+
+// loadDocument reads the document under key, migrates it to StateVersion and
+// decodes it into v. A migrated document is written back so the upgrade
+// only runs once. found is false if no document exists.
+func loadDocument(backend StateBackend, key string, migrations []Migration, v any) (found bool, err error) {
+	data, err := backend.Read(key)
+	if errors.Is(err, ErrNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	data, migrated, err := migrateDocument(data, StateVersion, migrations)
+	if err != nil {
+		return true, err
+	}
+	if migrated {
+		if err := backend.Write(key, data); err != nil {
+			return true, err
+		}
+	}
+
+	return true, json.Unmarshal(data, v)
 }
@@ -178,6 +178,51 @@ func TestDeviceStateStore(t *testing.T) {
 		}
 	})
 
+	t.Run("ZoneValuesRoundTrip", func(t *testing.T) {
+		dir := t.TempDir()
+		store := NewDeviceStateStore(filepath.Join(dir, "state.json"))
+
+		expiresAt := time.Date(2026, 1, 1, 15, 0, 0, 0, time.UTC)
+		phase := uint8(2)
+		state := &DeviceState{
+			Limits: []ZoneValueSnapshot{
+				{ZoneID: "zone-grid", ZoneType: 1, Direction: 0, Value: 4200000, Cause: 1, ExpiresAt: expiresAt},
+			},
+			Setpoints: []ZoneValueSnapshot{
+				{ZoneID: "zone-local", ZoneType: 2, Direction: 1, Value: 2000000},
+			},
+			CurrentLimits: []ZoneValueSnapshot{
+				{ZoneID: "zone-grid", ZoneType: 1, Phase: &phase, Value: 16000},
+			},
+		}
+
+		if err := store.Save(state); err != nil {
+			t.Fatalf("Save() error = %v", err)
+		}
+
+		got, err := store.Load()
+		if err != nil {
+			t.Fatalf("Load() error = %v", err)
+		}
+
+		if len(got.Limits) != 1 {
+			t.Fatalf("len(Limits) = %d, want 1", len(got.Limits))
+		}
+		lim := got.Limits[0]
+		if lim.Value != 4200000 || lim.Cause != 1 || !lim.ExpiresAt.Equal(expiresAt) {
+			t.Errorf("Limits[0] = %+v", lim)
+		}
+		if len(got.Setpoints) != 1 || got.Setpoints[0].Direction != 1 || !got.Setpoints[0].ExpiresAt.IsZero() {
+			t.Errorf("Setpoints = %+v", got.Setpoints)
+		}
+		if len(got.CurrentLimits) != 1 || got.CurrentLimits[0].Phase == nil || *got.CurrentLimits[0].Phase != 2 {
+			t.Errorf("CurrentLimits = %+v", got.CurrentLimits)
+		}
+		if got.CurrentSetpoints != nil {
+			t.Errorf("CurrentSetpoints = %+v, want nil", got.CurrentSetpoints)
+		}
+	})
+
 	t.Run("Clear", func(t *testing.T) {
 		dir := t.TempDir()
 		path := filepath.Join(dir, "state.json")
@@ -50,8 +50,8 @@ func deriveClientID(controllerName string) []byte {
 // DEC-067: The certificate is stable -- generated once at startup and reused for
 // all commissioning windows. The CN encodes the device discriminator ("MASH-1234")
 // so that controllers can extract it during the TLS handshake.
-// 20-year validity avoids unnecessary regeneration.
-func generateSelfSignedCert(discriminator uint16) (tls.Certificate, error) {
+// 20-year validity avoids unnecessary regeneration. The validity starts at now.
+func generateSelfSignedCert(discriminator uint16, now time.Time) (tls.Certificate, error) {
 	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
 	if err != nil {
 		return tls.Certificate{}, fmt.Errorf("generate key: %w", err)
@@ -62,7 +62,6 @@ func generateSelfSignedCert(discriminator uint16) (tls.Certificate, error) {
 		return tls.Certificate{}, fmt.Errorf("generate serial: %w", err)
 	}
 
-	now := time.Now()
 	template := &x509.Certificate{
 		SerialNumber: serialNumber,
 		Subject: pkix.Name{
@@ -89,8 +88,9 @@ func generateSelfSignedCert(discriminator uint16) (tls.Certificate, error) {
 }
 
 // generateControllerCert generates a self-signed TLS certificate for a controller.
-// Used during commissioning as the client certificate.
-func generateControllerCert() (tls.Certificate, error) {
+// Used during commissioning as the client certificate, valid for 24 hours
+// from now.
+func generateControllerCert(now time.Time) (tls.Certificate, error) {
 	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
 	if err != nil {
 		return tls.Certificate{}, fmt.Errorf("generate key: %w", err)
@@ -101,7 +101,6 @@ func generateControllerCert() (tls.Certificate, error) {
 		return tls.Certificate{}, fmt.Errorf("generate serial: %w", err)
 	}
 
-	now := time.Now()
 	template := &x509.Certificate{
 		SerialNumber: serialNumber,
 		Subject: pkix.Name{
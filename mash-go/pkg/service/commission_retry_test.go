@@ -188,7 +188,7 @@ func TestCommissionDevice_CollisionRetry_DirectDiscovery(t *testing.T) {
 	// Both will fail because there's no real device listening, but the retry
 	// logic should attempt both. The connection error is non-retryable, so
 	// it will return after the first connection failure.
-	result, err := svc.CommissionDevice(ctx, 1234, "20202021")
+	result, err := svc.CommissionDevice(ctx, 1234, "20202021", "")
 	assert.Nil(t, result)
 	assert.Error(t, err)
 	assert.ErrorIs(t, err, ErrCommissionFailed)
@@ -255,7 +255,7 @@ func TestCommissionDevice_CollisionAllFailPASE_FallsThrough(t *testing.T) {
 	svc.mu.Unlock()
 
 	// Should fall through to pairing request and eventually timeout
-	_, err = svc.CommissionDevice(ctx, 1234, "20202021")
+	_, err = svc.CommissionDevice(ctx, 1234, "20202021", "")
 	assert.Error(t, err)
 	assert.True(t, errors.Is(err, ErrPairingRequestTimeout), "expected timeout, got: %v", err)
 }
@@ -299,7 +299,7 @@ func TestCommissionDevice_DeviceAlreadyAdvertising_UsesNewPath(t *testing.T) {
 
 	// Device found immediately -- connection will fail since no real device,
 	// but pairing request should NOT be announced.
-	result, err := svc.CommissionDevice(ctx, 1234, "20202021")
+	result, err := svc.CommissionDevice(ctx, 1234, "20202021", "")
 	assert.Nil(t, result)
 	assert.Error(t, err)
 	// Mock expectations verify AnnouncePairingRequest was NOT called
@@ -530,7 +530,7 @@ func TestE2E_DeferredCommissioning_PairingRequest(t *testing.T) {
 			}
 		}()
 
-		connectedDevice, err := controllerSvc.CommissionDevice(ctx, 2001, "20202021")
+		connectedDevice, err := controllerSvc.CommissionDevice(ctx, 2001, "20202021", "")
 		commissionResultCh <- struct {
 			device *ConnectedDevice
 			err    error
@@ -982,7 +982,7 @@ func TestE2E_ConcurrentPairingRequests(t *testing.T) {
 	for i := 0; i < numDevices; i++ {
 		discriminator := uint16(601 + i)
 		go func(d uint16) {
-			_, err := controllerSvc.CommissionDevice(ctx, d, "20202021")
+			_, err := controllerSvc.CommissionDevice(ctx, d, "20202021", "")
 			results <- err
 		}(discriminator)
 	}
@@ -458,7 +458,7 @@ func TestDeriveDeviceID(t *testing.T) {
 
 // TestGenerateSelfSignedCert verifies self-signed certificate generation.
 func TestGenerateSelfSignedCert(t *testing.T) {
-	cert, err := generateSelfSignedCert(1234)
+	cert, err := generateSelfSignedCert(1234, time.Now())
 	if err != nil {
 		t.Fatalf("generateSelfSignedCert failed: %v", err)
 	}
@@ -476,7 +476,7 @@ func TestGenerateSelfSignedCert(t *testing.T) {
 // commissioning certificate CN contains the device discriminator so that
 // the test harness can extract it during PASE handshake.
 func TestGenerateSelfSignedCert_IncludesDiscriminator(t *testing.T) {
-	cert, err := generateSelfSignedCert(1234)
+	cert, err := generateSelfSignedCert(1234, time.Now())
 	if err != nil {
 		t.Fatalf("generateSelfSignedCert: %v", err)
 	}
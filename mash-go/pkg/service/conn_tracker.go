@@ -4,6 +4,8 @@ import (
 	"net"
 	"sync"
 	"time"
+
+	"github.com/mash-protocol/mash-go/pkg/clock"
 )
 
 // connTracker tracks pre-operational connections and their creation times.
@@ -12,12 +14,19 @@ import (
 type connTracker struct {
 	mu    sync.Mutex
 	conns map[net.Conn]time.Time
+	clock clock.Clock
 }
 
 // newConnTracker creates a new connection tracker.
 func newConnTracker() *connTracker {
+	return newConnTrackerWithClock(clock.Real())
+}
+
+// newConnTrackerWithClock creates a connection tracker using the given clock.
+func newConnTrackerWithClock(c clock.Clock) *connTracker {
 	return &connTracker{
 		conns: make(map[net.Conn]time.Time),
+		clock: c,
 	}
 }
 
@@ -25,7 +34,7 @@ func newConnTracker() *connTracker {
 func (ct *connTracker) Add(conn net.Conn) {
 	ct.mu.Lock()
 	defer ct.mu.Unlock()
-	ct.conns[conn] = time.Now()
+	ct.conns[conn] = ct.clock.Now()
 }
 
 // Remove deregisters a connection. Safe to call on absent connections.
@@ -41,7 +50,7 @@ func (ct *connTracker) CloseStale(maxAge time.Duration) int {
 	ct.mu.Lock()
 	defer ct.mu.Unlock()
 
-	cutoff := time.Now().Add(-maxAge)
+	cutoff := ct.clock.Now().Add(-maxAge)
 	closed := 0
 	for conn, added := range ct.conns {
 		if added.Before(cutoff) {
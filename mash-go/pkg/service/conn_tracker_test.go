@@ -5,6 +5,8 @@ import (
 	"sync"
 	"testing"
 	"time"
+
+	"github.com/mash-protocol/mash-go/pkg/clock"
 )
 
 // mockTrackerConn implements net.Conn for tracker tests.
@@ -81,6 +83,29 @@ func TestConnTracker_CloseStale_ClosesOldConnections(t *testing.T) {
 	}
 }
 
+func TestConnTracker_CloseStale_FakeClock(t *testing.T) {
+	fc := clock.NewFake(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+	ct := newConnTrackerWithClock(fc)
+
+	first := &mockTrackerConn{}
+	second := &mockTrackerConn{}
+
+	ct.Add(first)
+	fc.Advance(60 * time.Second)
+	ct.Add(second)
+	fc.Advance(31 * time.Second)
+
+	if closed := ct.CloseStale(90 * time.Second); closed != 1 {
+		t.Errorf("CloseStale: expected 1 closed, got %d", closed)
+	}
+	if !first.isClosed() {
+		t.Error("conn older than 90s should be closed")
+	}
+	if second.isClosed() {
+		t.Error("conn younger than 90s should NOT be closed")
+	}
+}
+
 func TestConnTracker_CloseStale_NoneStale(t *testing.T) {
 	ct := newConnTracker()
 
@@ -0,0 +1,224 @@
+package service
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/x509"
+	"errors"
+	"fmt"
+
+	"github.com/mash-protocol/mash-go/pkg/cert"
+)
+
+// Zone role errors.
+var (
+	ErrNotZoneOwner      = errors.New("operation requires the zone owner (Zone CA key not held)")
+	ErrNoDelegatedSigner = errors.New("zone admin has no delegated signer configured")
+)
+
+// DelegatedSigner has device CSRs signed by the zone owner on behalf of a
+// zone admin (multi-zone.md §4.3). ControllerService implements it for the
+// owner side; admins use a client for whatever channel reaches their owner.
+type DelegatedSigner interface {
+	SignDelegated(ctx context.Context, req *cert.DelegatedSigningRequest) (*cert.DelegatedSigningResponse, error)
+}
+
+// SetDelegatedSigner sets the signer a zone admin uses to have device
+// certificates issued by the owner during Commission.
+func (s *ControllerService) SetDelegatedSigner(signer DelegatedSigner) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.delegatedSigner = signer
+}
+
+// Role returns the controller's zone role from its operational certificate,
+// or 0 if it has none yet.
+func (s *ControllerService) Role() cert.Role {
+	s.mu.RLock()
+	certStore := s.certStore
+	s.mu.RUnlock()
+
+	if certStore == nil {
+		return 0
+	}
+	controllerCert, err := certStore.GetControllerCert()
+	if err != nil {
+		return 0
+	}
+	return cert.CertRole(controllerCert.Certificate)
+}
+
+// IssueAdminCert signs an admin certificate for the CSR created by a zone
+// admin with cert.CreateAdminCSR. The caller is responsible for having the
+// user authorise the admin first (security.md §8.1). Only the zone owner
+// can issue admin certificates.
+func (s *ControllerService) IssueAdminCert(csrDER []byte) (*x509.Certificate, error) {
+	zoneCA, err := s.ownerZoneCA()
+	if err != nil {
+		return nil, err
+	}
+	return cert.SignAdminCSR(zoneCA, csrDER)
+}
+
+// InstallAdminCert configures this controller as a zone admin: it keeps the
+// owner's Zone CA certificate (without key) and uses adminCert with key as
+// its operational identity. It must be called before Start.
+func (s *ControllerService) InstallAdminCert(zoneCACert, adminCert *x509.Certificate, key crypto.Signer) error {
+	s.mu.RLock()
+	certStore := s.certStore
+	state := s.state
+	s.mu.RUnlock()
+
+	if certStore == nil {
+		return fmt.Errorf("no certificate store configured")
+	}
+	if state != StateIdle {
+		return ErrAlreadyStarted
+	}
+
+	zoneCA, err := cert.NewAdminZoneCA(zoneCACert)
+	if err != nil {
+		return err
+	}
+	if err := cert.VerifyOperationalCert(adminCert, zoneCACert); err != nil {
+		return err
+	}
+	if cert.CertRole(adminCert) != cert.RoleAdmin {
+		return cert.ErrNotAdmin
+	}
+	pub, ok := adminCert.PublicKey.(*ecdsa.PublicKey)
+	if !ok || key == nil || !pub.Equal(key.Public()) {
+		return fmt.Errorf("%w: key does not match admin certificate", cert.ErrInvalidCert)
+	}
+
+	if err := certStore.SetZoneCA(zoneCA); err != nil {
+		return fmt.Errorf("set Zone CA: %w", err)
+	}
+	if err := certStore.SetControllerCert(&cert.OperationalCert{
+		Certificate: adminCert,
+		PrivateKey:  key,
+		ZoneID:      zoneCA.ZoneID,
+		ZoneType:    zoneCA.ZoneType,
+		ZoneCACert:  zoneCACert,
+	}); err != nil {
+		return fmt.Errorf("set controller cert: %w", err)
+	}
+	return certStore.Save()
+}
+
+// SignDelegated signs a device CSR forwarded by a zone admin. The request
+// must be signed by an admin certificate issued by this owner's Zone CA.
+func (s *ControllerService) SignDelegated(ctx context.Context, req *cert.DelegatedSigningRequest) (*cert.DelegatedSigningResponse, error) {
+	zoneCA, err := s.ownerZoneCA()
+	if err != nil {
+		return nil, err
+	}
+
+	adminCert, err := cert.VerifyDelegatedSigningRequest(req, zoneCA.Certificate, s.timeSource().Now())
+	if err != nil {
+		return nil, err
+	}
+
+	deviceID, err := cert.GenerateDeviceID(req.CSR)
+	if err != nil {
+		return nil, fmt.Errorf("generate device ID: %w", err)
+	}
+	deviceCert, err := cert.SignCSRWithDeviceID(zoneCA, req.CSR, deviceID)
+	if err != nil {
+		return nil, fmt.Errorf("sign CSR: %w", err)
+	}
+
+	s.emitEvent(Event{
+		Type:     EventDelegatedCertIssued,
+		ZoneID:   zoneCA.ZoneID,
+		DeviceID: deviceID,
+		Value:    adminCert.Subject.CommonName,
+	})
+
+	return &cert.DelegatedSigningResponse{
+		Certificate: deviceCert.Raw,
+		ZoneCA:      zoneCA.Certificate.Raw,
+	}, nil
+}
+
+// ownerZoneCA returns the Zone CA if this controller holds its key.
+func (s *ControllerService) ownerZoneCA() (*cert.ZoneCA, error) {
+	s.mu.RLock()
+	certStore := s.certStore
+	s.mu.RUnlock()
+
+	if certStore == nil {
+		return nil, fmt.Errorf("no certificate store configured")
+	}
+	zoneCA, err := certStore.GetZoneCA()
+	if err != nil {
+		return nil, fmt.Errorf("get Zone CA: %w", err)
+	}
+	if zoneCA.PrivateKey == nil {
+		return nil, ErrNotZoneOwner
+	}
+	return zoneCA, nil
+}
+
+// checkDelegatedSigner returns ErrNoDelegatedSigner if this controller is a
+// zone admin without a way to reach its owner.
+func (s *ControllerService) checkDelegatedSigner() error {
+	s.mu.RLock()
+	certStore := s.certStore
+	signer := s.delegatedSigner
+	s.mu.RUnlock()
+
+	if certStore == nil || signer != nil {
+		return nil
+	}
+	if zoneCA, err := certStore.GetZoneCA(); err == nil && zoneCA.PrivateKey == nil {
+		return ErrNoDelegatedSigner
+	}
+	return nil
+}
+
+// delegatedCSRSigner returns a CSRSignFunc that forwards the device CSR to
+// the zone owner, signed with this admin's operational key.
+func (s *ControllerService) delegatedCSRSigner(zoneCA *cert.ZoneCA) (CSRSignFunc, error) {
+	s.mu.RLock()
+	signer := s.delegatedSigner
+	certStore := s.certStore
+	s.mu.RUnlock()
+
+	if signer == nil {
+		return nil, ErrNoDelegatedSigner
+	}
+	admin, err := certStore.GetControllerCert()
+	if err != nil {
+		return nil, fmt.Errorf("get admin cert: %w", err)
+	}
+
+	return func(ctx context.Context, csrDER []byte, deviceID string) (*x509.Certificate, error) {
+		req, err := cert.NewDelegatedSigningRequest(admin, csrDER)
+		if err != nil {
+			return nil, err
+		}
+		resp, err := signer.SignDelegated(ctx, req)
+		if err != nil {
+			return nil, fmt.Errorf("delegated signing: %w", err)
+		}
+
+		deviceCert, err := x509.ParseCertificate(resp.Certificate)
+		if err != nil {
+			return nil, fmt.Errorf("parse delegated certificate: %w", err)
+		}
+		// The owner must issue the certificate under the Zone CA the
+		// device was told to trust.
+		if err := deviceCert.CheckSignatureFrom(zoneCA.Certificate); err != nil {
+			return nil, fmt.Errorf("%w: delegated certificate not issued by Zone CA", cert.ErrInvalidChain)
+		}
+		if deviceCert.Subject.CommonName != deviceID {
+			return nil, fmt.Errorf("delegated certificate issued for %q, want %q", deviceCert.Subject.CommonName, deviceID)
+		}
+		return deviceCert, nil
+	}, nil
+}
+
+// Verify ControllerService implements DelegatedSigner.
+var _ DelegatedSigner = (*ControllerService)(nil)
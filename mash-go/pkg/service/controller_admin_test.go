@@ -0,0 +1,186 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mash-protocol/mash-go/pkg/cert"
+	"github.com/mash-protocol/mash-go/pkg/discovery"
+	"github.com/mash-protocol/mash-go/pkg/discovery/mocks"
+	"github.com/mash-protocol/mash-go/pkg/model"
+	"github.com/stretchr/testify/mock"
+)
+
+// newTestAdminController returns a controller installed as admin of the
+// owner's zone.
+func newTestAdminController(t *testing.T, owner *ControllerService) *ControllerService {
+	t.Helper()
+
+	kp, err := cert.GenerateKeyPair()
+	if err != nil {
+		t.Fatalf("GenerateKeyPair() error = %v", err)
+	}
+	csrDER, err := cert.CreateAdminCSR(kp.PrivateKey, "installer-app")
+	if err != nil {
+		t.Fatalf("CreateAdminCSR() error = %v", err)
+	}
+	adminCert, err := owner.IssueAdminCert(csrDER)
+	if err != nil {
+		t.Fatalf("IssueAdminCert() error = %v", err)
+	}
+	ownerCA, _ := owner.certStore.GetZoneCA()
+
+	admin, err := NewControllerService(validControllerConfig())
+	if err != nil {
+		t.Fatalf("NewControllerService failed: %v", err)
+	}
+	admin.SetCertStore(cert.NewMemoryControllerStore())
+	if err := admin.InstallAdminCert(ownerCA.Certificate, adminCert, kp.PrivateKey); err != nil {
+		t.Fatalf("InstallAdminCert() error = %v", err)
+	}
+	return admin
+}
+
+func TestControllerService_InstallAdminCert(t *testing.T) {
+	owner, _ := NewControllerService(validControllerConfig())
+	owner.SetCertStore(createControllerCertStore(t, "admin-zone"))
+	if got := owner.Role(); got != cert.RoleOwner {
+		t.Errorf("owner Role() = %v, want OWNER", got)
+	}
+
+	admin := newTestAdminController(t, owner)
+	if got := admin.Role(); got != cert.RoleAdmin {
+		t.Errorf("admin Role() = %v, want ADMIN", got)
+	}
+
+	t.Run("AdminCannotIssue", func(t *testing.T) {
+		kp, _ := cert.GenerateKeyPair()
+		csrDER, _ := cert.CreateAdminCSR(kp.PrivateKey, "another-app")
+		if _, err := admin.IssueAdminCert(csrDER); !errors.Is(err, ErrNotZoneOwner) {
+			t.Errorf("IssueAdminCert() error = %v, want ErrNotZoneOwner", err)
+		}
+		if err := admin.RotateZoneCA(context.Background()); !errors.Is(err, ErrNotZoneOwner) {
+			t.Errorf("RotateZoneCA() error = %v, want ErrNotZoneOwner", err)
+		}
+	})
+
+	t.Run("RejectsOwnerCert", func(t *testing.T) {
+		ownerCA, _ := owner.certStore.GetZoneCA()
+		ownerCert, _ := owner.certStore.GetControllerCert()
+
+		other, _ := NewControllerService(validControllerConfig())
+		other.SetCertStore(cert.NewMemoryControllerStore())
+		err := other.InstallAdminCert(ownerCA.Certificate, ownerCert.Certificate, ownerCert.PrivateKey)
+		if !errors.Is(err, cert.ErrNotAdmin) {
+			t.Errorf("InstallAdminCert(owner cert) error = %v, want ErrNotAdmin", err)
+		}
+	})
+
+	t.Run("RejectsWrongKey", func(t *testing.T) {
+		ownerCA, _ := owner.certStore.GetZoneCA()
+		adminCert, _ := admin.certStore.GetControllerCert()
+		kp, _ := cert.GenerateKeyPair()
+
+		other, _ := NewControllerService(validControllerConfig())
+		other.SetCertStore(cert.NewMemoryControllerStore())
+		err := other.InstallAdminCert(ownerCA.Certificate, adminCert.Certificate, kp.PrivateKey)
+		if !errors.Is(err, cert.ErrInvalidCert) {
+			t.Errorf("InstallAdminCert(wrong key) error = %v, want ErrInvalidCert", err)
+		}
+	})
+}
+
+// TestControllerService_AdminCommission verifies that a zone admin
+// commissions a device with a certificate issued by the owner's Zone CA.
+func TestControllerService_AdminCommission(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	device := model.NewDevice("test-device-admin", 0x1234, 0x5678)
+	deviceConfig := validDeviceConfig()
+	deviceConfig.ListenAddress = "localhost:0"
+	deviceSvc, err := NewDeviceService(device, deviceConfig)
+	if err != nil {
+		t.Fatalf("NewDeviceService failed: %v", err)
+	}
+	deviceCertStore := cert.NewMemoryStore()
+	deviceSvc.SetCertStore(deviceCertStore)
+
+	deviceAdvertiser := mocks.NewMockAdvertiser(t)
+	deviceAdvertiser.EXPECT().AdvertiseCommissionable(mock.Anything, mock.Anything).Return(nil).Maybe()
+	deviceAdvertiser.EXPECT().StopCommissionable().Return(nil).Maybe()
+	deviceAdvertiser.EXPECT().AdvertiseOperational(mock.Anything, mock.Anything).Return(nil).Maybe()
+	deviceAdvertiser.EXPECT().UpdateOperational(mock.Anything, mock.Anything).Return(nil).Maybe()
+	deviceAdvertiser.EXPECT().StopAll().Return().Maybe()
+	deviceSvc.SetAdvertiser(deviceAdvertiser)
+
+	if err := deviceSvc.Start(ctx); err != nil {
+		t.Fatalf("Device Start failed: %v", err)
+	}
+	defer func() { _ = deviceSvc.Stop() }()
+	if err := deviceSvc.EnterCommissioningMode(); err != nil {
+		t.Fatalf("EnterCommissioningMode failed: %v", err)
+	}
+
+	owner, _ := NewControllerService(validControllerConfig())
+	owner.SetCertStore(createControllerCertStore(t, "admin-zone"))
+	issued := make(chan Event, 1)
+	owner.OnEvent(func(e Event) {
+		if e.Type == EventDelegatedCertIssued {
+			issued <- e
+		}
+	})
+
+	admin := newTestAdminController(t, owner)
+	browser := mocks.NewMockBrowser(t)
+	browser.EXPECT().Stop().Return().Maybe()
+	admin.SetBrowser(browser)
+
+	if err := admin.Start(ctx); err != nil {
+		t.Fatalf("Admin Start failed: %v", err)
+	}
+	defer func() { _ = admin.Stop() }()
+
+	tcpAddr := deviceSvc.CommissioningAddr().(*net.TCPAddr)
+	commissionable := &discovery.CommissionableService{
+		Host:          "localhost",
+		Port:          uint16(tcpAddr.Port),
+		Addresses:     []string{tcpAddr.IP.String()},
+		Discriminator: 1234,
+	}
+
+	t.Run("NoDelegatedSigner", func(t *testing.T) {
+		_, err := admin.Commission(ctx, commissionable, "20202021")
+		if !errors.Is(err, ErrCommissionFailed) || !strings.Contains(err.Error(), ErrNoDelegatedSigner.Error()) {
+			t.Fatalf("Commission() error = %v, want ErrNoDelegatedSigner", err)
+		}
+	})
+
+	admin.SetDelegatedSigner(owner)
+	connectedDevice, err := admin.Commission(ctx, commissionable, "20202021")
+	if err != nil {
+		t.Fatalf("Commission failed: %v", err)
+	}
+
+	ownerCA, _ := owner.certStore.GetZoneCA()
+	opCert, err := deviceCertStore.GetOperationalCert(connectedDevice.ZoneID)
+	if err != nil {
+		t.Fatalf("GetOperationalCert() error = %v", err)
+	}
+	if err := opCert.Certificate.CheckSignatureFrom(ownerCA.Certificate); err != nil {
+		t.Errorf("device cert not issued by the owner's Zone CA: %v", err)
+	}
+
+	select {
+	case e := <-issued:
+		if e.DeviceID != connectedDevice.ID || e.Value != "installer-app" {
+			t.Errorf("EventDelegatedCertIssued = %s/%v, want %s/installer-app", e.DeviceID, e.Value, connectedDevice.ID)
+		}
+	case <-time.After(time.Second):
+		t.Error("expected EventDelegatedCertIssued from the owner")
+	}
+}
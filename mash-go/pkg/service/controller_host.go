@@ -0,0 +1,412 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mash-protocol/mash-go/pkg/cert"
+	"github.com/mash-protocol/mash-go/pkg/clock"
+	"github.com/mash-protocol/mash-go/pkg/discovery"
+	"github.com/mash-protocol/mash-go/pkg/persistence"
+)
+
+// Controller host errors.
+var (
+	ErrZoneExists   = errors.New("zone already hosted")
+	ErrZoneNotFound = errors.New("zone not found")
+)
+
+// zoneDeviceIDSeparator joins a zone ID and a device ID. Neither contains it:
+// both are hex fingerprints.
+const zoneDeviceIDSeparator = "/"
+
+// ZoneDeviceID returns the host-wide ID of a device in a zone. Device IDs
+// are only unique within the Zone CA that issued them, so a ControllerHost
+// prefixes them with the zone ID.
+func ZoneDeviceID(zoneID, deviceID string) string {
+	return zoneID + zoneDeviceIDSeparator + deviceID
+}
+
+// SplitZoneDeviceID splits a host-wide device ID into its zone ID and the
+// device ID within that zone.
+func SplitZoneDeviceID(id string) (zoneID, deviceID string, ok bool) {
+	zoneID, deviceID, ok = strings.Cut(id, zoneDeviceIDSeparator)
+	if !ok || zoneID == "" || deviceID == "" {
+		return "", "", false
+	}
+	return zoneID, deviceID, true
+}
+
+// ControllerHostConfig configures a ControllerHost.
+type ControllerHostConfig struct {
+	// StaleConnectionTimeout is the maximum age of a commissioning
+	// connection opened by any hosted zone. Older connections are
+	// force-closed by the shared reaper (DEC-064). Default: 90s.
+	// Set to 0 to disable.
+	StaleConnectionTimeout time.Duration
+
+	// ReaperInterval is how often the stale connection reaper runs.
+	// Default: 10s.
+	ReaperInterval time.Duration
+
+	// Clock is the time source for the reaper.
+	// If nil, the real clock is used.
+	Clock clock.Clock
+}
+
+// DefaultControllerHostConfig returns a ControllerHostConfig with sensible defaults.
+func DefaultControllerHostConfig() ControllerHostConfig {
+	return ControllerHostConfig{
+		StaleConnectionTimeout: 90 * time.Second,
+		ReaperInterval:         10 * time.Second,
+	}
+}
+
+// HostedZone describes one zone run by a ControllerHost.
+type HostedZone struct {
+	// Config configures the zone's ControllerService. ZoneName must be
+	// unique within the host.
+	Config ControllerConfig
+
+	// StateDir is the zone's own directory for certificates and state.
+	// It is used for any store below that is nil. If empty and a store is
+	// nil, the zone keeps that data in memory only.
+	StateDir string
+
+	// CertStore holds the zone's Zone CA and controller certificate.
+	CertStore cert.ControllerStore
+
+	// StateStore persists the zone's device list.
+	StateStore *persistence.ControllerStateStore
+}
+
+// ControllerHost runs several controller zones in one process, e.g. a LOCAL
+// zone next to a TEST zone, or one zone per tenant of a multi-dwelling
+// building. Each zone is a ControllerService with its own Zone CA, cert store
+// and state; the host shares one mDNS browser, one stale connection reaper
+// and one event stream between them.
+//
+// Events from all zones reach handlers registered with OnEvent with ZoneID
+// set and DeviceID namespaced by zone (see ZoneDeviceID).
+type ControllerHost struct {
+	mu sync.RWMutex
+
+	config ControllerHostConfig
+	state  ServiceState
+
+	// Zones in the order they were added
+	zones []*ControllerService
+
+	// Shared by all zones
+	browser       discovery.Browser
+	connTracker   *connTracker
+	eventHandlers []EventHandler
+
+	clock clock.Clock
+
+	// Context from Start that zones run under, cancelled by Stop
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+// NewControllerHost creates a controller host with no zones.
+func NewControllerHost(config ControllerHostConfig) *ControllerHost {
+	c := clock.OrReal(config.Clock)
+	return &ControllerHost{
+		config:      config,
+		state:       StateIdle,
+		connTracker: newConnTrackerWithClock(c),
+		clock:       c,
+	}
+}
+
+// SetBrowser sets the shared discovery browser (for testing/DI).
+func (h *ControllerHost) SetBrowser(browser discovery.Browser) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.browser = browser
+}
+
+// OnEvent registers a handler for events from all hosted zones.
+func (h *ControllerHost) OnEvent(handler EventHandler) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.eventHandlers = append(h.eventHandlers, handler)
+}
+
+// AddZone creates the ControllerService for a zone and, if the host is
+// running, starts it. Saved state is loaded from the zone's state store.
+func (h *ControllerHost) AddZone(zone HostedZone) (*ControllerService, error) {
+	svc, err := NewControllerService(zone.Config)
+	if err != nil {
+		return nil, err
+	}
+
+	certStore := zone.CertStore
+	if certStore == nil {
+		if zone.StateDir != "" {
+			fileStore := cert.NewFileControllerStore(zone.StateDir)
+			if err := fileStore.Load(); err != nil {
+				return nil, fmt.Errorf("load zone %q certificates: %w", zone.Config.ZoneName, err)
+			}
+			certStore = fileStore
+		} else {
+			certStore = cert.NewMemoryControllerStore()
+		}
+	}
+	stateStore := zone.StateStore
+	if stateStore == nil && zone.StateDir != "" {
+		stateStore = persistence.NewControllerStateStore(filepath.Join(zone.StateDir, "state.json"))
+	}
+
+	svc.SetCertStore(certStore)
+	svc.SetStateStore(stateStore)
+	if err := svc.LoadState(); err != nil {
+		return nil, fmt.Errorf("load zone %q state: %w", zone.Config.ZoneName, err)
+	}
+	svc.connTracker = h.connTracker
+	svc.OnEvent(func(e Event) { h.forwardEvent(svc, e) })
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for _, z := range h.zones {
+		if z.ZoneName() == svc.ZoneName() {
+			return nil, fmt.Errorf("%w: %q", ErrZoneExists, svc.ZoneName())
+		}
+	}
+
+	if h.state == StateRunning {
+		if err := h.startZoneLocked(svc); err != nil {
+			return nil, err
+		}
+	}
+	h.zones = append(h.zones, svc)
+	return svc, nil
+}
+
+// RemoveZone stops a zone and removes it from the host. Its cert store and
+// state are left in place.
+func (h *ControllerHost) RemoveZone(zoneID string) error {
+	h.mu.Lock()
+	var svc *ControllerService
+	for i, z := range h.zones {
+		if z.ZoneID() == zoneID {
+			svc = z
+			h.zones = append(h.zones[:i], h.zones[i+1:]...)
+			break
+		}
+	}
+	h.mu.Unlock()
+
+	if svc == nil {
+		return ErrZoneNotFound
+	}
+	if svc.State() == StateRunning {
+		return svc.Stop()
+	}
+	return nil
+}
+
+// Zone returns the hosted zone with the given zone ID, or nil.
+func (h *ControllerHost) Zone(zoneID string) *ControllerService {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	for _, z := range h.zones {
+		if z.ZoneID() == zoneID {
+			return z
+		}
+	}
+	return nil
+}
+
+// Zones returns all hosted zones in the order they were added.
+func (h *ControllerHost) Zones() []*ControllerService {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	zones := make([]*ControllerService, len(h.zones))
+	copy(zones, h.zones)
+	return zones
+}
+
+// Start starts the shared browser and reaper and then every zone. If a
+// zone fails to start, the zones already started are stopped again.
+func (h *ControllerHost) Start(ctx context.Context) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.state != StateIdle && h.state != StateStopped {
+		return ErrAlreadyStarted
+	}
+
+	if h.browser == nil {
+		browser, err := discovery.NewMDNSBrowser(discovery.DefaultBrowserConfig())
+		if err != nil {
+			return err
+		}
+		h.browser = browser
+	}
+
+	hostCtx, cancel := context.WithCancel(ctx)
+	h.ctx = hostCtx
+	for i, svc := range h.zones {
+		if err := h.startZoneLocked(svc); err != nil {
+			for _, started := range h.zones[:i] {
+				_ = started.Stop()
+			}
+			cancel()
+			h.ctx = nil
+			return fmt.Errorf("start zone %q: %w", svc.ZoneName(), err)
+		}
+	}
+
+	h.cancel = cancel
+	h.state = StateRunning
+
+	if h.config.StaleConnectionTimeout > 0 && h.config.ReaperInterval > 0 {
+		go h.runStaleConnectionReaper(hostCtx)
+	}
+	return nil
+}
+
+// Stop stops every zone, the reaper and the shared browser.
+func (h *ControllerHost) Stop() error {
+	h.mu.Lock()
+	if h.state != StateRunning {
+		h.mu.Unlock()
+		return ErrNotStarted
+	}
+	h.state = StateStopping
+	zones := make([]*ControllerService, len(h.zones))
+	copy(zones, h.zones)
+	h.mu.Unlock()
+
+	var errs []error
+	for _, svc := range zones {
+		if svc.State() != StateRunning {
+			continue
+		}
+		if err := svc.Stop(); err != nil {
+			errs = append(errs, fmt.Errorf("stop zone %q: %w", svc.ZoneName(), err))
+		}
+	}
+
+	h.mu.Lock()
+	h.cancel()
+	h.browser.Stop()
+	h.connTracker.CloseAll()
+	h.state = StateStopped
+	h.mu.Unlock()
+
+	return errors.Join(errs...)
+}
+
+// startZoneLocked starts a zone on the shared browser and checks that its
+// zone ID is not already hosted. The zone runs under the context passed to
+// Start. Caller holds h.mu.
+func (h *ControllerHost) startZoneLocked(svc *ControllerService) error {
+	svc.SetBrowser(sharedBrowser{h.browser})
+	if err := svc.Start(h.ctx); err != nil {
+		return err
+	}
+
+	for _, z := range h.zones {
+		if z != svc && z.State() == StateRunning && z.ZoneID() == svc.ZoneID() {
+			_ = svc.Stop()
+			return fmt.Errorf("%w: zone ID %s", ErrZoneExists, svc.ZoneID())
+		}
+	}
+	return nil
+}
+
+// DeviceIDs returns the host-wide IDs of all devices in all zones, sorted.
+func (h *ControllerHost) DeviceIDs() []string {
+	var ids []string
+	for _, svc := range h.Zones() {
+		zoneID := svc.ZoneID()
+		for _, device := range svc.GetAllDevices() {
+			ids = append(ids, ZoneDeviceID(zoneID, device.ID))
+		}
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// GetDevice returns a device by host-wide ID, or nil.
+func (h *ControllerHost) GetDevice(id string) *ConnectedDevice {
+	svc, deviceID := h.resolve(id)
+	if svc == nil {
+		return nil
+	}
+	return svc.GetDevice(deviceID)
+}
+
+// GetSession returns the session of a device by host-wide ID, or nil.
+func (h *ControllerHost) GetSession(id string) *DeviceSession {
+	svc, deviceID := h.resolve(id)
+	if svc == nil {
+		return nil
+	}
+	return svc.GetSession(deviceID)
+}
+
+// resolve finds the zone of a host-wide device ID.
+func (h *ControllerHost) resolve(id string) (*ControllerService, string) {
+	zoneID, deviceID, ok := SplitZoneDeviceID(id)
+	if !ok {
+		return nil, ""
+	}
+	return h.Zone(zoneID), deviceID
+}
+
+// forwardEvent passes a zone's event to the host's handlers with the zone
+// filled in.
+func (h *ControllerHost) forwardEvent(svc *ControllerService, e Event) {
+	zoneID := svc.ZoneID()
+	if e.ZoneID == "" {
+		e.ZoneID = zoneID
+	}
+	if e.DeviceID != "" {
+		e.DeviceID = ZoneDeviceID(zoneID, e.DeviceID)
+	}
+
+	h.mu.RLock()
+	handlers := h.eventHandlers
+	h.mu.RUnlock()
+
+	for _, handler := range handlers {
+		handler(e)
+	}
+}
+
+// runStaleConnectionReaper periodically closes commissioning connections
+// from any zone that have exceeded the StaleConnectionTimeout (DEC-064).
+func (h *ControllerHost) runStaleConnectionReaper(ctx context.Context) {
+	ticker := h.clock.NewTicker(h.config.ReaperInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C():
+			h.connTracker.CloseStale(h.config.StaleConnectionTimeout)
+		}
+	}
+}
+
+// sharedBrowser hands the host's browser to a zone. Stop is a no-op so
+// stopping one zone does not stop discovery for the others; the host stops
+// the browser itself.
+type sharedBrowser struct {
+	discovery.Browser
+}
+
+// Stop implements discovery.Browser.
+func (sharedBrowser) Stop() {}
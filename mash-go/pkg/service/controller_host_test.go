@@ -0,0 +1,298 @@
+package service
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mash-protocol/mash-go/pkg/cert"
+	"github.com/mash-protocol/mash-go/pkg/clock"
+	"github.com/mash-protocol/mash-go/pkg/discovery"
+	"github.com/mash-protocol/mash-go/pkg/discovery/mocks"
+	"github.com/mash-protocol/mash-go/pkg/transport"
+)
+
+func hostedZone(name string, zoneType cert.ZoneType, network transport.Network) HostedZone {
+	config := validControllerConfig()
+	config.ZoneName = name
+	config.ZoneType = zoneType
+	config.Network = network
+	return HostedZone{Config: config}
+}
+
+func TestZoneDeviceID(t *testing.T) {
+	id := ZoneDeviceID("a1b2c3d4e5f6a7b8", "0011223344556677")
+	if id != "a1b2c3d4e5f6a7b8/0011223344556677" {
+		t.Errorf("ZoneDeviceID = %q", id)
+	}
+	zoneID, deviceID, ok := SplitZoneDeviceID(id)
+	if !ok || zoneID != "a1b2c3d4e5f6a7b8" || deviceID != "0011223344556677" {
+		t.Errorf("SplitZoneDeviceID = %q, %q, %v", zoneID, deviceID, ok)
+	}
+	for _, bad := range []string{"", "0011223344556677", "/0011223344556677", "a1b2c3d4e5f6a7b8/"} {
+		if _, _, ok := SplitZoneDeviceID(bad); ok {
+			t.Errorf("SplitZoneDeviceID(%q) ok, want not ok", bad)
+		}
+	}
+}
+
+// TestControllerHost_TwoZones commissions a device into each of a LOCAL and
+// a TEST zone of the same host and checks the shared browser, the event
+// stream and device ID namespacing.
+func TestControllerHost_TwoZones(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	network := transport.NewPipeNetwork()
+	pipeService := func(i int) *discovery.CommissionableService {
+		addr := startPipeDevice(t, ctx, network, i).CommissioningAddr().(*net.TCPAddr)
+		return &discovery.CommissionableService{
+			Host:          "localhost",
+			Port:          uint16(addr.Port),
+			Addresses:     []string{addr.IP.String()},
+			Discriminator: 1234,
+		}
+	}
+
+	host := NewControllerHost(DefaultControllerHostConfig())
+
+	// One browser for the whole host, stopped once by the host
+	browser := mocks.NewMockBrowser(t)
+	browser.EXPECT().Stop().Return().Once()
+	host.SetBrowser(browser)
+
+	events := make(chan Event, 16)
+	host.OnEvent(func(e Event) {
+		if e.Type == EventCommissioned {
+			events <- e
+		}
+	})
+
+	local, err := host.AddZone(hostedZone("Home", cert.ZoneTypeLocal, network))
+	if err != nil {
+		t.Fatalf("AddZone(Home) failed: %v", err)
+	}
+	if err := host.Start(ctx); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	// Zones added while running start straight away
+	test, err := host.AddZone(hostedZone("Lab", cert.ZoneTypeTest, network))
+	if err != nil {
+		t.Fatalf("AddZone(Lab) failed: %v", err)
+	}
+	if test.State() != StateRunning {
+		t.Fatalf("zone added to running host is %s, want RUNNING", test.State())
+	}
+	if local.ZoneID() == test.ZoneID() {
+		t.Fatal("zones share a zone ID")
+	}
+
+	localDevice, err := local.Commission(ctx, pipeService(0), "20202021")
+	if err != nil {
+		t.Fatalf("Commission(Home) failed: %v", err)
+	}
+	testDevice, err := test.Commission(ctx, pipeService(1), "20202021")
+	if err != nil {
+		t.Fatalf("Commission(Lab) failed: %v", err)
+	}
+
+	want := map[string]string{
+		ZoneDeviceID(local.ZoneID(), localDevice.ID): local.ZoneID(),
+		ZoneDeviceID(test.ZoneID(), testDevice.ID):   test.ZoneID(),
+	}
+	for range want {
+		select {
+		case e := <-events:
+			if zoneID, ok := want[e.DeviceID]; !ok || e.ZoneID != zoneID {
+				t.Errorf("event %s/%s not namespaced by its zone", e.ZoneID, e.DeviceID)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("missing EventCommissioned")
+		}
+	}
+
+	ids := host.DeviceIDs()
+	if len(ids) != 2 {
+		t.Fatalf("DeviceIDs() = %v, want 2", ids)
+	}
+	for _, id := range ids {
+		if _, ok := want[id]; !ok {
+			t.Errorf("unexpected device ID %q", id)
+		}
+		if host.GetDevice(id) == nil {
+			t.Errorf("GetDevice(%q) = nil", id)
+		}
+	}
+	if host.GetDevice(localDevice.ID) != nil {
+		t.Error("GetDevice found a device by its bare ID")
+	}
+
+	// Removing one zone leaves the shared browser running for the other
+	if err := host.RemoveZone(test.ZoneID()); err != nil {
+		t.Fatalf("RemoveZone failed: %v", err)
+	}
+	if host.Zone(test.ZoneID()) != nil || len(host.Zones()) != 1 {
+		t.Error("zone still hosted after RemoveZone")
+	}
+	if err := host.RemoveZone(test.ZoneID()); !errors.Is(err, ErrZoneNotFound) {
+		t.Errorf("second RemoveZone error = %v, want ErrZoneNotFound", err)
+	}
+
+	if err := host.Stop(); err != nil {
+		t.Fatalf("Stop failed: %v", err)
+	}
+	if local.State() != StateStopped {
+		t.Errorf("zone state after host Stop = %s, want STOPPED", local.State())
+	}
+}
+
+func TestControllerHost_DuplicateZone(t *testing.T) {
+	host := NewControllerHost(DefaultControllerHostConfig())
+	if _, err := host.AddZone(hostedZone("Home", cert.ZoneTypeLocal, nil)); err != nil {
+		t.Fatalf("AddZone failed: %v", err)
+	}
+	if _, err := host.AddZone(hostedZone("Home", cert.ZoneTypeTest, nil)); !errors.Is(err, ErrZoneExists) {
+		t.Errorf("AddZone error = %v, want ErrZoneExists", err)
+	}
+}
+
+func TestControllerHost_ZonesRunUnderStartContext(t *testing.T) {
+	browser := mocks.NewMockBrowser(t)
+	browser.EXPECT().Stop().Return().Maybe()
+
+	host := NewControllerHost(DefaultControllerHostConfig())
+	host.SetBrowser(browser)
+	ctx, cancel := context.WithCancel(context.Background())
+	if err := host.Start(ctx); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	defer func() { _ = host.Stop() }()
+
+	// Added while running
+	svc, err := host.AddZone(hostedZone("Home", cert.ZoneTypeLocal, nil))
+	if err != nil {
+		t.Fatalf("AddZone failed: %v", err)
+	}
+
+	cancel()
+	select {
+	case <-svc.ctx.Done():
+	case <-time.After(time.Second):
+		t.Error("zone context not cancelled with the Start context")
+	}
+}
+
+func TestControllerHost_StateDir(t *testing.T) {
+	dir := t.TempDir()
+	browser := mocks.NewMockBrowser(t)
+	browser.EXPECT().Stop().Return().Maybe()
+
+	run := func() string {
+		host := NewControllerHost(DefaultControllerHostConfig())
+		host.SetBrowser(browser)
+		zone := hostedZone("Home", cert.ZoneTypeLocal, nil)
+		zone.StateDir = dir
+		svc, err := host.AddZone(zone)
+		if err != nil {
+			t.Fatalf("AddZone failed: %v", err)
+		}
+		if err := host.Start(context.Background()); err != nil {
+			t.Fatalf("Start failed: %v", err)
+		}
+		defer func() { _ = host.Stop() }()
+		if err := svc.SaveState(); err != nil {
+			t.Fatalf("SaveState failed: %v", err)
+		}
+		return svc.ZoneID()
+	}
+
+	first := run()
+	if second := run(); second != first {
+		t.Errorf("zone ID after restart = %s, want %s (Zone CA not kept in StateDir)", second, first)
+	}
+}
+
+// TestControllerHost_StaleConnectionReaper checks that the shared reaper
+// closes a commissioning connection that a device never answers.
+func TestControllerHost_StaleConnectionReaper(t *testing.T) {
+	network := transport.NewPipeNetwork()
+
+	// A "device" that completes TLS and then never speaks PASE
+	serverCert, err := generateSelfSignedCert(1234, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	listener, err := network.Listen("localhost:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			tlsConn := tls.Server(conn, &tls.Config{
+				Certificates: []tls.Certificate{serverCert},
+				NextProtos:   transport.NewCommissioningTLSConfig().NextProtos,
+			})
+			go func() { _ = tlsConn.Handshake() }()
+		}
+	}()
+	addr := listener.Addr().(*net.TCPAddr)
+
+	fake := clock.NewFake(time.Now())
+	config := DefaultControllerHostConfig()
+	config.Clock = fake
+	host := NewControllerHost(config)
+	browser := mocks.NewMockBrowser(t)
+	browser.EXPECT().Stop().Return().Maybe()
+	host.SetBrowser(browser)
+
+	zone := hostedZone("Home", cert.ZoneTypeLocal, network)
+	svc, err := host.AddZone(zone)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := host.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = host.Stop() }()
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := svc.Commission(context.Background(), &discovery.CommissionableService{
+			Host:      "localhost",
+			Port:      uint16(addr.Port),
+			Addresses: []string{addr.IP.String()},
+		}, "20202021")
+		done <- err
+	}()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for host.connTracker.Len() == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("commissioning connection never tracked")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	fake.Advance(config.StaleConnectionTimeout + config.ReaperInterval)
+
+	select {
+	case err := <-done:
+		if err == nil || !strings.Contains(err.Error(), "PASE") {
+			t.Errorf("Commission error = %v, want PASE failure", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Commission still blocked after reaper ran")
+	}
+	if n := host.connTracker.Len(); n != 0 {
+		t.Errorf("tracked connections = %d, want 0", n)
+	}
+}
@@ -1,6 +1,7 @@
 package service
 
 import (
+	"bytes"
 	"context"
 	"crypto/rand"
 	"crypto/x509"
@@ -35,8 +36,15 @@ type ControllerRenewalHandler struct {
 
 	// responseWait is used to wait for async responses.
 	responseWait chan any
+
+	// signCSR signs the device CSR during commissioning. Nil means sign
+	// locally with zoneCA.
+	signCSR CSRSignFunc
 }
 
+// CSRSignFunc signs a device CSR with a controller-assigned device ID.
+type CSRSignFunc func(ctx context.Context, csrDER []byte, deviceID string) (*x509.Certificate, error)
+
 func decodeRenewalOrCommissioningError(data []byte, phase string) (any, error) {
 	msg, err := commissioning.DecodeRenewalMessage(data)
 	if err == nil {
@@ -64,6 +72,14 @@ func NewControllerRenewalHandler(zoneCA *cert.ZoneCA, conn Sendable) *Controller
 	}
 }
 
+// SetCSRSigner replaces local signing of the commissioning CSR, e.g. with
+// a delegated signing request to the zone owner when zoneCA carries no key.
+func (h *ControllerRenewalHandler) SetCSRSigner(sign CSRSignFunc) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.signCSR = sign
+}
+
 // InitiateRenewal starts the renewal process by sending a CertRenewalRequest.
 // It waits for the CSR response from the device.
 func (h *ControllerRenewalHandler) InitiateRenewal(ctx context.Context, deviceID string) error {
@@ -178,6 +194,54 @@ func (h *ControllerRenewalHandler) HandleResponse(msg any) {
 	}
 }
 
+// UpdateZoneCA sends a new Zone CA and its cross-certificate, and waits for
+// the device to trust it alongside the current one.
+func (h *ControllerRenewalHandler) UpdateZoneCA(ctx context.Context, newCA, crossCert *x509.Certificate) error {
+	return h.exchangeZoneCA(ctx, &commissioning.ZoneCAUpdate{
+		MsgType:   commissioning.MsgZoneCAUpdate,
+		ZoneCA:    newCA.Raw,
+		CrossCert: crossCert.Raw,
+	}, newCA.Raw)
+}
+
+// RetireZoneCA tells the device to trust only zoneCA from now on.
+func (h *ControllerRenewalHandler) RetireZoneCA(ctx context.Context, zoneCA *x509.Certificate) error {
+	return h.exchangeZoneCA(ctx, &commissioning.ZoneCARetire{
+		MsgType: commissioning.MsgZoneCARetire,
+		ZoneCA:  zoneCA.Raw,
+	}, zoneCA.Raw)
+}
+
+// exchangeZoneCA sends a Zone CA rotation message and waits for its
+// acknowledgment.
+func (h *ControllerRenewalHandler) exchangeZoneCA(ctx context.Context, msg any, zoneCA []byte) error {
+	data, err := cbor.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("encode zone CA message: %w", err)
+	}
+
+	if err := h.conn.Send(data); err != nil {
+		return fmt.Errorf("send zone CA message: %w", err)
+	}
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case resp := <-h.responseWait:
+		ack, ok := resp.(*commissioning.ZoneCAAck)
+		if !ok {
+			return fmt.Errorf("unexpected response type: %T", resp)
+		}
+		if ack.Status != commissioning.RenewalStatusSuccess {
+			return fmt.Errorf("zone CA update failed with status %d", ack.Status)
+		}
+		if !bytes.Equal(ack.ZoneCAHash, commissioning.ComputeZoneCAHash(zoneCA)) {
+			return fmt.Errorf("device acknowledged a different zone CA")
+		}
+		return nil
+	}
+}
+
 // IssueInitialCertSync issues an initial operational certificate during commissioning.
 // Unlike RenewDevice, this includes the Zone CA certificate so the device can:
 // 1. Verify future connections from this zone's controllers
@@ -238,7 +302,15 @@ func (h *ControllerRenewalHandler) IssueInitialCertSync(ctx context.Context, syn
 	}
 
 	// Sign the CSR with controller-assigned device ID
-	newCert, err := cert.SignCSRWithDeviceID(h.zoneCA, csrResp.CSR, deviceID)
+	h.mu.Lock()
+	sign := h.signCSR
+	h.mu.Unlock()
+	if sign == nil {
+		sign = func(_ context.Context, csrDER []byte, deviceID string) (*x509.Certificate, error) {
+			return cert.SignCSRWithDeviceID(h.zoneCA, csrDER, deviceID)
+		}
+	}
+	newCert, err := sign(ctx, csrResp.CSR, deviceID)
 	if err != nil {
 		return nil, fmt.Errorf("sign CSR: %w", err)
 	}
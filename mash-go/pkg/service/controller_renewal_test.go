@@ -2,13 +2,23 @@ package service
 
 import (
 	"context"
+	"crypto/ecdsa"
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
 	"sync"
 	"testing"
 	"time"
 
 	"github.com/fxamacker/cbor/v2"
+	"github.com/stretchr/testify/mock"
+
 	"github.com/mash-protocol/mash-go/pkg/cert"
 	"github.com/mash-protocol/mash-go/pkg/commissioning"
+	"github.com/mash-protocol/mash-go/pkg/discovery"
+	"github.com/mash-protocol/mash-go/pkg/discovery/mocks"
+	"github.com/mash-protocol/mash-go/pkg/model"
 )
 
 // mockRenewalConn simulates a device connection for renewal testing.
@@ -382,6 +392,297 @@ func TestControllerService_RenewControllerCert(t *testing.T) {
 	}
 }
 
+// TestControllerService_RotateZoneCA_Incomplete verifies that a rotation
+// with an unreachable device keeps the old Zone CA, persists the pending one,
+// accepts both in the operational TLS config, and resumes with the same CA.
+func TestControllerService_RotateZoneCA_Incomplete(t *testing.T) {
+	tempDir := t.TempDir()
+	certStore := cert.NewFileControllerStore(tempDir)
+
+	zoneCA, err := cert.GenerateZoneCA("test-zone", cert.ZoneTypeLocal)
+	if err != nil {
+		t.Fatalf("Failed to create Zone CA: %v", err)
+	}
+	controllerCert, err := cert.GenerateControllerOperationalCert(zoneCA, "controller-test")
+	if err != nil {
+		t.Fatalf("Failed to create controller cert: %v", err)
+	}
+	_ = certStore.SetZoneCA(zoneCA)
+	_ = certStore.SetControllerCert(controllerCert)
+
+	config := DefaultControllerConfig()
+	config.ZoneName = "test-zone"
+	svc, err := NewControllerService(config)
+	if err != nil {
+		t.Fatalf("Failed to create controller service: %v", err)
+	}
+	svc.SetCertStore(certStore)
+	svc.connectedDevices["offline-device"] = &ConnectedDevice{ID: "offline-device"}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	err = svc.RotateZoneCA(ctx)
+	if !errors.Is(err, ErrZoneCARotationIncomplete) {
+		t.Fatalf("RotateZoneCA() error = %v, want ErrZoneCARotationIncomplete", err)
+	}
+	if !svc.ZoneCARotationPending() {
+		t.Error("rotation should be pending")
+	}
+
+	current, _ := certStore.GetZoneCA()
+	if !current.Certificate.Equal(zoneCA.Certificate) {
+		t.Error("controller should keep the old Zone CA until all devices moved")
+	}
+
+	// The pending CA survives a restart.
+	reloaded := cert.NewFileControllerStore(tempDir)
+	if err := reloaded.Load(); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	pending, err := reloaded.GetPendingZoneCA()
+	if err != nil {
+		t.Fatalf("GetPendingZoneCA() error = %v", err)
+	}
+
+	// Device certificates from either CA are accepted meanwhile.
+	tlsConfig, err := svc.buildOperationalTLSConfig("offline-device")
+	if err != nil {
+		t.Fatalf("buildOperationalTLSConfig() error = %v", err)
+	}
+	if n := len(tlsConfig.RootCAs.Subjects()); n != 2 {
+		t.Errorf("operational TLS trusts %d Zone CAs, want 2", n)
+	}
+
+	// Retrying resumes with the same pending CA.
+	_ = svc.RotateZoneCA(ctx)
+	again, _ := certStore.GetPendingZoneCA()
+	if !again.Certificate.Equal(pending.Certificate) {
+		t.Error("retry should reuse the pending Zone CA")
+	}
+}
+
+// TestControllerService_RotateZoneCA moves a commissioned, operationally
+// connected device to a new Zone CA without reconnecting.
+func TestControllerService_RotateZoneCA(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	device := model.NewDevice("test-device-rotation", 0x1234, 0x5678)
+	endpoint, _ := device.GetEndpoint(0)
+	endpoint.AddFeature(model.NewFeature(model.FeatureDeviceInfo, 1))
+
+	deviceConfig := validDeviceConfig()
+	deviceConfig.ListenAddress = "localhost:0"
+	deviceConfig.SetupCode = "20202021"
+	deviceSvc, err := NewDeviceService(device, deviceConfig)
+	if err != nil {
+		t.Fatalf("NewDeviceService failed: %v", err)
+	}
+	deviceCertStore := cert.NewMemoryStore()
+	deviceSvc.SetCertStore(deviceCertStore)
+
+	deviceAdvertiser := mocks.NewMockAdvertiser(t)
+	deviceAdvertiser.EXPECT().AdvertiseCommissionable(mock.Anything, mock.Anything).Return(nil).Maybe()
+	deviceAdvertiser.EXPECT().StopCommissionable().Return(nil).Maybe()
+	deviceAdvertiser.EXPECT().AdvertiseOperational(mock.Anything, mock.Anything).Return(nil).Maybe()
+	deviceAdvertiser.EXPECT().UpdateOperational(mock.Anything, mock.Anything).Return(nil).Maybe()
+	deviceAdvertiser.EXPECT().StopAll().Return().Maybe()
+	deviceSvc.SetAdvertiser(deviceAdvertiser)
+
+	if err := deviceSvc.Start(ctx); err != nil {
+		t.Fatalf("Device Start failed: %v", err)
+	}
+	defer func() { _ = deviceSvc.Stop() }()
+	if err := deviceSvc.EnterCommissioningMode(); err != nil {
+		t.Fatalf("EnterCommissioningMode failed: %v", err)
+	}
+
+	controllerConfig := validControllerConfig()
+	controllerSvc, err := NewControllerService(controllerConfig)
+	if err != nil {
+		t.Fatalf("NewControllerService failed: %v", err)
+	}
+	certStore := createControllerCertStore(t, controllerConfig.ZoneName)
+	controllerSvc.SetCertStore(certStore)
+
+	browser := mocks.NewMockBrowser(t)
+	browser.EXPECT().Stop().Return().Maybe()
+	controllerSvc.SetBrowser(browser)
+
+	rotated := make(chan struct{}, 1)
+	controllerSvc.OnEvent(func(e Event) {
+		if e.Type == EventZoneCARotated {
+			rotated <- struct{}{}
+		}
+	})
+
+	if err := controllerSvc.Start(ctx); err != nil {
+		t.Fatalf("Controller Start failed: %v", err)
+	}
+	defer func() { _ = controllerSvc.Stop() }()
+
+	// Commission, then reconnect operationally (DEC-066).
+	tcpAddr := deviceSvc.CommissioningAddr().(*net.TCPAddr)
+	connectedDevice, err := controllerSvc.Commission(ctx, &discovery.CommissionableService{
+		Host:          "localhost",
+		Port:          uint16(tcpAddr.Port),
+		Addresses:     []string{tcpAddr.IP.String()},
+		Discriminator: 1234,
+	}, "20202021")
+	if err != nil {
+		t.Fatalf("Commission failed: %v", err)
+	}
+	time.Sleep(100 * time.Millisecond)
+
+	controllerSvc.mu.Lock()
+	controllerSvc.connectedDevices[connectedDevice.ID].Port = uint16(deviceSvc.OperationalAddr().(*net.TCPAddr).Port)
+	controllerSvc.mu.Unlock()
+	// Reconnect reads device capabilities before its message loop runs, so
+	// bound how long it waits for them.
+	reconnectCtx, reconnectCancel := context.WithTimeout(ctx, 500*time.Millisecond)
+	defer reconnectCancel()
+	if err := controllerSvc.Reconnect(reconnectCtx, connectedDevice.ID); err != nil {
+		t.Fatalf("Reconnect failed: %v", err)
+	}
+	time.Sleep(100 * time.Millisecond)
+
+	session := controllerSvc.GetSession(connectedDevice.ID)
+	if session == nil {
+		t.Fatal("Expected active session after reconnection")
+	}
+
+	if _, err := session.Read(ctx, 0, uint8(model.FeatureDeviceInfo), nil); err != nil {
+		t.Fatalf("Read before rotation failed: %v", err)
+	}
+
+	oldCA, _ := certStore.GetZoneCA()
+	oldControllerCert, _ := certStore.GetControllerCert()
+	zoneID := controllerSvc.ZoneID()
+
+	if err := controllerSvc.RotateZoneCA(ctx); err != nil {
+		t.Fatalf("RotateZoneCA() error = %v", err)
+	}
+
+	// Controller: new CA and controller cert, rotation finished.
+	newCA, _ := certStore.GetZoneCA()
+	if newCA.Certificate.Equal(oldCA.Certificate) {
+		t.Fatal("Zone CA should have been replaced")
+	}
+	if controllerSvc.ZoneCARotationPending() {
+		t.Error("rotation should be complete")
+	}
+	newControllerCert, _ := certStore.GetControllerCert()
+	if err := newControllerCert.Certificate.CheckSignatureFrom(newCA.Certificate); err != nil {
+		t.Errorf("controller cert not issued by new Zone CA: %v", err)
+	}
+	select {
+	case <-rotated:
+	case <-time.After(time.Second):
+		t.Error("expected EventZoneCARotated")
+	}
+
+	// Device: trusts only the new CA and holds a certificate issued by it.
+	trusted := deviceCertStore.GetZoneCACerts(zoneID)
+	if len(trusted) != 1 || !trusted[0].Equal(newCA.Certificate) {
+		t.Fatalf("device trusts %d Zone CAs, want only the new one", len(trusted))
+	}
+	opCert, err := deviceCertStore.GetOperationalCert(zoneID)
+	if err != nil {
+		t.Fatalf("GetOperationalCert() error = %v", err)
+	}
+	if err := opCert.Certificate.CheckSignatureFrom(newCA.Certificate); err != nil {
+		t.Errorf("device cert not issued by new Zone CA: %v", err)
+	}
+	if err := deviceSvc.verifyClientCert(newControllerCert.Certificate); err != nil {
+		t.Errorf("device should accept the new controller cert: %v", err)
+	}
+	if err := deviceSvc.verifyClientCert(oldControllerCert.Certificate); err == nil {
+		t.Error("device should reject certs from the retired Zone CA")
+	}
+
+	// The session survives the rotation.
+	if _, err := session.Read(ctx, 0, uint8(model.FeatureDeviceInfo), nil); err != nil {
+		t.Fatalf("Read after rotation failed: %v", err)
+	}
+	if controllerSvc.GetSession(connectedDevice.ID) != session {
+		t.Error("Session should be preserved during rotation, not replaced")
+	}
+}
+
+// TestControllerService_RemoteKeyStore verifies that a controller whose cert
+// store uses a remote signer generates its Zone CA and operational keys in
+// the signer, and renews through it.
+func TestControllerService_RemoteKeyStore(t *testing.T) {
+	sock := filepath.Join(t.TempDir(), "signer.sock")
+	l, err := net.Listen("unix", sock)
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	defer l.Close()
+	keyDir := t.TempDir()
+	go cert.NewSignerServer(cert.NewFileKeyStore(keyDir)).Serve(l)
+
+	stateDir := t.TempDir()
+	certStore := cert.NewFileControllerStoreWithKeyStore(stateDir, cert.NewRemoteKeyStore("unix", sock))
+
+	config := validControllerConfig()
+	svc, err := NewControllerService(config)
+	if err != nil {
+		t.Fatalf("NewControllerService failed: %v", err)
+	}
+	svc.SetCertStore(certStore)
+
+	browser := mocks.NewMockBrowser(t)
+	browser.EXPECT().Stop().Return().Maybe()
+	svc.SetBrowser(browser)
+
+	if err := svc.Start(context.Background()); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	defer func() { _ = svc.Stop() }()
+
+	zoneCA, err := certStore.GetZoneCA()
+	if err != nil {
+		t.Fatalf("GetZoneCA failed: %v", err)
+	}
+	if _, ok := zoneCA.PrivateKey.(*cert.RemoteSigner); !ok {
+		t.Errorf("Zone CA key is %T, want *cert.RemoteSigner", zoneCA.PrivateKey)
+	}
+	if _, err := os.Stat(filepath.Join(stateDir, filepath.FromSlash(cert.ZoneCAKeyName))); !os.IsNotExist(err) {
+		t.Error("Zone CA key should not be written to the state directory")
+	}
+
+	if err := svc.RenewControllerCert(); err != nil {
+		t.Fatalf("RenewControllerCert failed: %v", err)
+	}
+	renewed, _ := certStore.GetControllerCert()
+	if _, ok := renewed.PrivateKey.(*cert.RemoteSigner); !ok {
+		t.Errorf("controller key is %T, want *cert.RemoteSigner", renewed.PrivateKey)
+	}
+	if err := cert.VerifyOperationalCert(renewed.Certificate, zoneCA.Certificate); err != nil {
+		t.Errorf("renewed cert does not verify against Zone CA: %v", err)
+	}
+
+	// The new key was staged under the other name and the old one deleted
+	// after the save
+	if renewed.KeyName != cert.ControllerNextKeyName {
+		t.Errorf("renewed key name = %q, want %q", renewed.KeyName, cert.ControllerNextKeyName)
+	}
+	if _, err := os.Stat(filepath.Join(keyDir, filepath.FromSlash(cert.ControllerKeyName))); !os.IsNotExist(err) {
+		t.Error("replaced controller key still held by the signer")
+	}
+
+	reloaded := cert.NewFileControllerStoreWithKeyStore(stateDir, cert.NewRemoteKeyStore("unix", sock))
+	if err := reloaded.Load(); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	got, _ := reloaded.GetControllerCert()
+	if !got.PrivateKey.Public().(*ecdsa.PublicKey).Equal(got.Certificate.PublicKey) {
+		t.Error("reloaded controller key does not match its certificate")
+	}
+}
+
 // TestControllerService_ControllerCertNeedsRenewal tests the service method.
 func TestControllerService_ControllerCertNeedsRenewal(t *testing.T) {
 	tempDir := t.TempDir()
@@ -0,0 +1,224 @@
+package service
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/mash-protocol/mash-go/pkg/cert"
+)
+
+// ErrZoneCARotationIncomplete is returned by RotateZoneCA when some devices
+// could not be moved to the new Zone CA. The rotation stays in progress and
+// the next call resumes it.
+var ErrZoneCARotationIncomplete = errors.New("zone CA rotation incomplete")
+
+// RotateZoneCA moves the zone to a new Zone CA without re-commissioning its
+// devices:
+//
+//  1. A new Zone CA is generated and cross-signed by the current one. An
+//     interrupted rotation reuses the pending CA from the cert store.
+//  2. Each device is sent the new CA over its operational connection,
+//     trusts it alongside the current one, and has its certificate reissued
+//     under it.
+//  3. Once every device has moved, the controller switches to the new CA
+//     and a fresh operational certificate, and tells devices to retire the
+//     old CA.
+//
+// Until step 3 the controller accepts device certificates from both CAs.
+// If a device is offline or fails step 2, ErrZoneCARotationIncomplete is
+// returned and the controller keeps using the old CA; call RotateZoneCA
+// again once the device is back. A device that misses step 3 keeps trusting
+// both CAs, which is harmless since the old CA no longer signs anything.
+func (s *ControllerService) RotateZoneCA(ctx context.Context) error {
+	s.zoneCARotationMu.Lock()
+	defer s.zoneCARotationMu.Unlock()
+
+	s.mu.RLock()
+	certStore := s.certStore
+	s.mu.RUnlock()
+
+	if certStore == nil {
+		return fmt.Errorf("no certificate store configured")
+	}
+
+	oldCA, err := certStore.GetZoneCA()
+	if err != nil {
+		return fmt.Errorf("get Zone CA: %w", err)
+	}
+	if oldCA.PrivateKey == nil {
+		return ErrNotZoneOwner
+	}
+
+	newCA, err := pendingZoneCA(certStore, oldCA)
+	if err != nil {
+		return err
+	}
+
+	crossCert, err := cert.CrossSignZoneCA(oldCA, newCA.Certificate, s.timeSource().Now())
+	if err != nil {
+		return fmt.Errorf("cross-sign Zone CA: %w", err)
+	}
+
+	// Move every known device, including any commissioned meanwhile under
+	// the old CA.
+	moved := make(map[string]bool)
+	var failed []string
+	for {
+		var pending []string
+		for _, deviceID := range s.knownDeviceIDs() {
+			if _, tried := moved[deviceID]; !tried {
+				pending = append(pending, deviceID)
+			}
+		}
+		if len(pending) == 0 {
+			break
+		}
+		for _, deviceID := range pending {
+			err := s.moveDeviceToZoneCA(ctx, deviceID, newCA, crossCert)
+			moved[deviceID] = err == nil
+			if err != nil {
+				failed = append(failed, fmt.Sprintf("%s: %v", deviceID, err))
+			}
+		}
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("%w: %s", ErrZoneCARotationIncomplete, strings.Join(failed, "; "))
+	}
+
+	// Switch the controller to the new CA.
+	controllerID := fmt.Sprintf("controller-%s", newCA.ZoneID)
+	controllerCert, err := generateControllerOpCert(certStore, newCA, controllerID)
+	if err != nil {
+		return fmt.Errorf("generate controller cert: %w", err)
+	}
+	if err := certStore.SetZoneCA(newCA); err != nil {
+		return fmt.Errorf("set Zone CA: %w", err)
+	}
+	if err := certStore.SetControllerCert(controllerCert); err != nil {
+		return fmt.Errorf("set controller cert: %w", err)
+	}
+	if err := certStore.SetPendingZoneCA(nil); err != nil {
+		return fmt.Errorf("clear pending Zone CA: %w", err)
+	}
+	if err := certStore.Save(); err != nil {
+		return fmt.Errorf("save Zone CA: %w", err)
+	}
+
+	s.mu.RLock()
+	zoneID := s.zoneID
+	s.mu.RUnlock()
+
+	s.emitEvent(Event{
+		Type:   EventZoneCARotated,
+		ZoneID: zoneID,
+	})
+
+	// Devices now hold certificates from the new CA only; drop the old one.
+	var retireFailed []string
+	for deviceID := range moved {
+		if err := s.retireDeviceZoneCA(ctx, deviceID, newCA); err != nil {
+			retireFailed = append(retireFailed, fmt.Sprintf("%s: %v", deviceID, err))
+		}
+	}
+	if len(retireFailed) > 0 {
+		sort.Strings(retireFailed)
+		return fmt.Errorf("retire old Zone CA: %s", strings.Join(retireFailed, "; "))
+	}
+
+	return nil
+}
+
+// ZoneCARotationPending returns true if a Zone CA rotation has started but
+// not every device has moved to the new CA yet.
+func (s *ControllerService) ZoneCARotationPending() bool {
+	s.mu.RLock()
+	certStore := s.certStore
+	s.mu.RUnlock()
+
+	if certStore == nil {
+		return false
+	}
+	_, err := certStore.GetPendingZoneCA()
+	return err == nil
+}
+
+// pendingZoneCA returns the Zone CA being rotated in, generating and
+// persisting one if no rotation is in progress.
+func pendingZoneCA(store cert.ControllerStore, current *cert.ZoneCA) (*cert.ZoneCA, error) {
+	newCA, err := store.GetPendingZoneCA()
+	if err == nil {
+		return newCA, nil
+	}
+	if !errors.Is(err, cert.ErrCertNotFound) {
+		return nil, fmt.Errorf("get pending Zone CA: %w", err)
+	}
+
+	newCA, err = generateZoneCA(store, current.ZoneID, current.ZoneType, cert.NextZoneCAKeyName(current))
+	if err != nil {
+		return nil, fmt.Errorf("generate Zone CA: %w", err)
+	}
+	if err := store.SetPendingZoneCA(newCA); err != nil {
+		return nil, fmt.Errorf("set pending Zone CA: %w", err)
+	}
+	if err := store.Save(); err != nil {
+		return nil, fmt.Errorf("save pending Zone CA: %w", err)
+	}
+	return newCA, nil
+}
+
+// knownDeviceIDs returns the IDs of all devices in the zone, connected or
+// not, sorted.
+func (s *ControllerService) knownDeviceIDs() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	ids := make([]string, 0, len(s.connectedDevices))
+	for deviceID := range s.connectedDevices {
+		ids = append(ids, deviceID)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// moveDeviceToZoneCA makes a device trust newCA and reissues its
+// certificate under it, over the device's existing session.
+func (s *ControllerService) moveDeviceToZoneCA(ctx context.Context, deviceID string, newCA *cert.ZoneCA, crossCert *x509.Certificate) error {
+	return s.withRenewalHandler(deviceID, newCA, func(h *ControllerRenewalHandler) error {
+		if err := h.UpdateZoneCA(ctx, newCA.Certificate, crossCert); err != nil {
+			return fmt.Errorf("distribute Zone CA: %w", err)
+		}
+		if _, err := h.RenewDevice(ctx, deviceID); err != nil {
+			return fmt.Errorf("reissue certificate: %w", err)
+		}
+		return nil
+	})
+}
+
+// retireDeviceZoneCA tells a device to trust only zoneCA.
+func (s *ControllerService) retireDeviceZoneCA(ctx context.Context, deviceID string, zoneCA *cert.ZoneCA) error {
+	return s.withRenewalHandler(deviceID, zoneCA, func(h *ControllerRenewalHandler) error {
+		return h.RetireZoneCA(ctx, zoneCA.Certificate)
+	})
+}
+
+// withRenewalHandler runs fn with a renewal handler for zoneCA installed on
+// the device's session, so the device's responses are routed to it.
+func (s *ControllerService) withRenewalHandler(deviceID string, zoneCA *cert.ZoneCA, fn func(*ControllerRenewalHandler) error) error {
+	s.mu.RLock()
+	session := s.deviceSessions[deviceID]
+	s.mu.RUnlock()
+
+	if session == nil {
+		return ErrNotConnected
+	}
+
+	handler := NewControllerRenewalHandler(zoneCA, session.Conn())
+	session.SetRenewalHandler(handler)
+	defer session.SetRenewalHandler(nil)
+
+	return fn(handler)
+}
@@ -12,6 +12,7 @@ import (
 	"time"
 
 	"github.com/mash-protocol/mash-go/pkg/cert"
+	"github.com/mash-protocol/mash-go/pkg/clock"
 	"github.com/mash-protocol/mash-go/pkg/commissioning"
 	"github.com/mash-protocol/mash-go/pkg/discovery"
 	"github.com/mash-protocol/mash-go/pkg/log"
@@ -61,17 +62,39 @@ type ControllerService struct {
 	certStore  cert.ControllerStore
 	stateStore *persistence.ControllerStateStore
 
+	// Serializes Zone CA rotations
+	zoneCARotationMu sync.Mutex
+
+	// Has device CSRs signed by the zone owner when this controller is a
+	// zone admin (its Zone CA has no key)
+	delegatedSigner DelegatedSigner
+
 	// Protocol logger for structured event capture (optional)
 	protocolLogger log.Logger
 
 	// Active pairing requests (keyed by discriminator)
 	activePairingRequests map[uint16]context.CancelFunc
 
+	// Commissioning tickets already used (CommissionFromTicket)
+	tickets *commissioning.TicketLedger
+
+	// Time source for LastSeen, heartbeats and renewal (config.Clock or real)
+	clock clock.Clock
+
+	// Tracks commissioning connections for a ControllerHost's stale
+	// connection reaper (nil when not hosted)
+	connTracker *connTracker
+
 	// Context for cancellation
 	ctx    context.Context
 	cancel context.CancelFunc
 }
 
+// timeSource returns the service clock, defaulting to the real clock.
+func (s *ControllerService) timeSource() clock.Clock {
+	return clock.OrReal(s.clock)
+}
+
 // generateControllerConnID generates a random connection ID for logging.
 func (s *ControllerService) generateControllerConnID() string {
 	b := make([]byte, 8) // 8 bytes = 16 hex chars
@@ -93,11 +116,14 @@ func NewControllerService(config ControllerConfig) (*ControllerService, error) {
 		deviceSessions:        make(map[string]*DeviceSession),
 		activePairingRequests: make(map[uint16]context.CancelFunc),
 		protocolLogger:        config.ProtocolLogger,
+		clock:                 clock.OrReal(config.Clock),
+		tickets:               commissioning.NewTicketLedger(),
 	}
 
 	// Initialize subscription manager
 	subConfig := subscription.DefaultConfig()
 	subConfig.SuppressBounceBack = config.EnableBounceBackSuppression
+	subConfig.Clock = svc.clock
 	svc.subscriptionManager = subscription.NewManagerWithConfig(subConfig)
 
 	return svc, nil
@@ -165,7 +191,7 @@ func (s *ControllerService) Start(ctx context.Context) error {
 				zoneID = existingZoneID // Prefer existing zone ID from state
 			}
 
-			zoneCA, err = cert.GenerateZoneCA(zoneID, config.ZoneType)
+			zoneCA, err = generateZoneCA(certStore, zoneID, config.ZoneType, cert.ZoneCAKeyName)
 			if err != nil {
 				s.mu.Lock()
 				s.state = StateIdle
@@ -207,7 +233,7 @@ func (s *ControllerService) Start(ctx context.Context) error {
 		if controllerCert == nil {
 			// Generate new controller operational certificate
 			controllerID := fmt.Sprintf("controller-%s", zoneCA.ZoneID)
-			controllerCert, err = cert.GenerateControllerOperationalCert(zoneCA, controllerID)
+			controllerCert, err = generateControllerOpCert(certStore, zoneCA, controllerID)
 			if err != nil {
 				s.mu.Lock()
 				s.state = StateIdle
@@ -345,6 +371,12 @@ func (s *ControllerService) DiscoverByDiscriminator(ctx context.Context, discrim
 
 // Commission commissions a device using a setup code.
 func (s *ControllerService) Commission(ctx context.Context, service *discovery.CommissionableService, setupCode string) (*ConnectedDevice, error) {
+	return s.commission(ctx, service, setupCode, "")
+}
+
+// commission does the work of Commission. label is stored as the device's
+// Label in the same state save that records the device.
+func (s *ControllerService) commission(ctx context.Context, service *discovery.CommissionableService, setupCode, label string) (*ConnectedDevice, error) {
 	s.mu.RLock()
 	if s.state != StateRunning {
 		s.mu.RUnlock()
@@ -358,6 +390,12 @@ func (s *ControllerService) Commission(ctx context.Context, service *discovery.C
 		return nil, fmt.Errorf("%w: invalid setup code", ErrCommissionFailed)
 	}
 
+	// A zone admin cannot issue device certificates itself; check before
+	// using up a PASE attempt on the device.
+	if err := s.checkDelegatedSigner(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrCommissionFailed, err)
+	}
+
 	// Build address to connect to
 	addr := fmt.Sprintf("%s:%d", service.Host, service.Port)
 	if len(service.Addresses) > 0 {
@@ -367,10 +405,14 @@ func (s *ControllerService) Commission(ctx context.Context, service *discovery.C
 
 	// Connect via TLS with InsecureSkipVerify (security from PASE)
 	tlsConfig := transport.NewCommissioningTLSConfig()
-	conn, err := tls.Dial("tcp", addr, tlsConfig)
+	conn, err := transport.DialTLS(ctx, s.config.Network, addr, tlsConfig)
 	if err != nil {
 		return nil, fmt.Errorf("%w: connection failed: %v", ErrCommissionFailed, err)
 	}
+	if s.connTracker != nil {
+		s.connTracker.Add(conn)
+		defer s.connTracker.Remove(conn)
+	}
 
 	// Create client and server identities for PASE
 	// These must match the identities used by the device's verifier
@@ -421,6 +463,15 @@ func (s *ControllerService) Commission(ctx context.Context, service *discovery.C
 	// Create renewal handler and perform initial cert exchange
 	// Use IssueInitialCertSync because we don't have a message loop yet
 	renewalHandler := NewControllerRenewalHandler(zoneCA, framedConn)
+	if zoneCA.PrivateKey == nil {
+		// Zone admin: the owner signs the device's CSR
+		sign, err := s.delegatedCSRSigner(zoneCA)
+		if err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("%w: %v", ErrCommissionFailed, err)
+		}
+		renewalHandler.SetCSRSigner(sign)
+	}
 	operationalCert, err := renewalHandler.IssueInitialCertSync(ctx, framedConn)
 	if err != nil {
 		conn.Close()
@@ -452,11 +503,12 @@ func (s *ControllerService) Commission(ctx context.Context, service *discovery.C
 	device := &ConnectedDevice{
 		ID:              deviceID,
 		ZoneID:          zoneID,
+		Label:           label,
 		Host:            service.Host,
 		Port:            service.Port,
 		Addresses:       service.Addresses,
 		Connected:       true,
-		LastSeen:        time.Now(),
+		LastSeen:        s.timeSource().Now(),
 		OperationalCert: operationalCert, // Store the operational cert for display
 	}
 
@@ -506,7 +558,10 @@ func (s *ControllerService) Commission(ctx context.Context, service *discovery.C
 // This method supports deferred commissioning scenarios like:
 // - SMGW pre-provisioning devices before installation
 // - Backend systems that provision devices ahead of time
-func (s *ControllerService) CommissionDevice(ctx context.Context, discriminator uint16, setupCode string) (*ConnectedDevice, error) {
+//
+// label, if not empty, becomes the device's Label and is persisted together
+// with the commissioned device, so the two cannot get out of step.
+func (s *ControllerService) CommissionDevice(ctx context.Context, discriminator uint16, setupCode, label string) (*ConnectedDevice, error) {
 	s.mu.RLock()
 	if s.state != StateRunning {
 		s.mu.RUnlock()
@@ -523,6 +578,10 @@ func (s *ControllerService) CommissionDevice(ctx context.Context, discriminator
 		return nil, ErrNotStarted
 	}
 
+	commission := func(ctx context.Context, service *discovery.CommissionableService, setupCode string) (*ConnectedDevice, error) {
+		return s.commission(ctx, service, setupCode, label)
+	}
+
 	// Apply default timeout if not configured
 	if timeout == 0 {
 		timeout = DefaultPairingRequestTimeout
@@ -534,7 +593,7 @@ func (s *ControllerService) CommissionDevice(ctx context.Context, discriminator
 	discoverCancel()
 
 	if len(candidates) > 0 {
-		result, err := commissionCandidates(ctx, candidates, setupCode, s.Commission)
+		result, err := commissionCandidates(ctx, candidates, setupCode, commission)
 		if err == nil {
 			return result, nil
 		}
@@ -612,10 +671,11 @@ func (s *ControllerService) CommissionDevice(ctx context.Context, discriminator
 	if pollInterval == 0 {
 		pollInterval = PairingRequestPollInterval
 	}
-	ticker := time.NewTicker(pollInterval)
+	ticker := s.timeSource().NewTicker(pollInterval)
 	defer ticker.Stop()
 
-	timeoutTimer := time.NewTimer(timeout)
+	timedOut := make(chan struct{})
+	timeoutTimer := s.timeSource().AfterFunc(timeout, func() { close(timedOut) })
 	defer timeoutTimer.Stop()
 
 	// Track candidates that already failed PASE (deterministic -- wrong device
@@ -631,10 +691,10 @@ func (s *ControllerService) CommissionDevice(ctx context.Context, discriminator
 			}
 			return nil, ErrCommissioningCancelled
 
-		case <-timeoutTimer.C:
+		case <-timedOut:
 			return nil, ErrPairingRequestTimeout
 
-		case <-ticker.C:
+		case <-ticker.C():
 			// Discover all matching candidates
 			discoverCtx, discoverCancel := context.WithTimeout(pairingCtx, s.config.DiscoveryTimeout)
 			found, _ := browser.FindAllByDiscriminator(discoverCtx, discriminator)
@@ -656,7 +716,7 @@ func (s *ControllerService) CommissionDevice(ctx context.Context, discriminator
 				continue
 			}
 
-			result, err := commissionCandidates(ctx, untried, setupCode, s.Commission)
+			result, err := commissionCandidates(ctx, untried, setupCode, commission)
 			if err == nil {
 				return result, nil
 			}
@@ -734,6 +794,20 @@ func (s *ControllerService) GetSession(deviceID string) *DeviceSession {
 	return s.deviceSessions[deviceID]
 }
 
+// SetDeviceLabel sets a device's label and persists it.
+func (s *ControllerService) SetDeviceLabel(deviceID, label string) error {
+	s.mu.Lock()
+	device, exists := s.connectedDevices[deviceID]
+	if !exists {
+		s.mu.Unlock()
+		return ErrDeviceNotFound
+	}
+	device.Label = label
+	s.mu.Unlock()
+
+	return s.SaveState()
+}
+
 // Decommission removes a device from the zone.
 func (s *ControllerService) Decommission(deviceID string) error {
 	s.mu.Lock()
@@ -822,7 +896,7 @@ func (s *ControllerService) HandleDeviceConnect(deviceID string) {
 
 	if device, exists := s.connectedDevices[deviceID]; exists {
 		device.Connected = true
-		device.LastSeen = time.Now()
+		device.LastSeen = s.timeSource().Now()
 	}
 
 	s.emitEvent(Event{
@@ -838,7 +912,7 @@ func (s *ControllerService) HandleDeviceDisconnect(deviceID string) {
 
 	if device, exists := s.connectedDevices[deviceID]; exists {
 		device.Connected = false
-		device.LastSeen = time.Now()
+		device.LastSeen = s.timeSource().Now()
 	}
 
 	s.emitEvent(Event{
@@ -1095,7 +1169,7 @@ func (s *ControllerService) attemptReconnection(ctx context.Context, svc *discov
 	}
 
 	// Attempt connection
-	conn, err := tls.Dial("tcp", addr, tlsConfig)
+	conn, err := transport.DialTLS(ctx, s.config.Network, addr, tlsConfig)
 	if err != nil {
 		// Connection failed - device might not be ready yet
 		s.emitEvent(Event{
@@ -1113,7 +1187,7 @@ func (s *ControllerService) attemptReconnection(ctx context.Context, svc *discov
 		device.Connected = true
 		device.Host = svc.Host
 		device.Port = svc.Port
-		device.LastSeen = time.Now()
+		device.LastSeen = s.timeSource().Now()
 	}
 	s.mu.Unlock()
 
@@ -1139,6 +1213,10 @@ func (s *ControllerService) attemptReconnection(ctx context.Context, svc *discov
 	s.deviceSessions[svc.DeviceID] = session
 	s.mu.Unlock()
 
+	// Start message loop in background so the reads below get responses.
+	// Note: runDeviceMessageLoop calls handleDeviceSessionClose on exit
+	go s.runDeviceMessageLoop(svc.DeviceID, framedConn, session)
+
 	// Check protocol version compatibility (DEC-050)
 	if err := s.checkDeviceVersion(ctx, session); err != nil {
 		s.mu.Lock()
@@ -1167,10 +1245,6 @@ func (s *ControllerService) attemptReconnection(ctx context.Context, svc *discov
 		Type:     EventDeviceReconnected,
 		DeviceID: svc.DeviceID,
 	})
-
-	// Start message loop - blocks until connection closes
-	// Note: runDeviceMessageLoop calls handleDeviceSessionClose on exit
-	s.runDeviceMessageLoop(svc.DeviceID, framedConn, session)
 }
 
 // Reconnect establishes an operational TLS connection to a previously commissioned device.
@@ -1206,8 +1280,7 @@ func (s *ControllerService) Reconnect(ctx context.Context, deviceID string) erro
 	}
 
 	// Attempt connection with context timeout
-	dialer := &tls.Dialer{Config: tlsConfig}
-	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	conn, err := transport.DialTLS(ctx, s.config.Network, addr, tlsConfig)
 	if err != nil {
 		s.emitEvent(Event{
 			Type:     EventReconnectionFailed,
@@ -1217,18 +1290,16 @@ func (s *ControllerService) Reconnect(ctx context.Context, deviceID string) erro
 		return fmt.Errorf("failed to connect to device %s at %s: %w", deviceID, addr, err)
 	}
 
-	tlsConn := conn.(*tls.Conn)
-
 	// Update device state
 	s.mu.Lock()
 	if dev, ok := s.connectedDevices[deviceID]; ok {
 		dev.Connected = true
-		dev.LastSeen = time.Now()
+		dev.LastSeen = s.timeSource().Now()
 	}
 	s.mu.Unlock()
 
 	// Create framed connection wrapper for operational messaging
-	framedConn := newFramedConnection(tlsConn)
+	framedConn := newFramedConnection(conn)
 
 	// Create device session
 	session := NewDeviceSession(deviceID, framedConn)
@@ -1244,6 +1315,10 @@ func (s *ControllerService) Reconnect(ctx context.Context, deviceID string) erro
 	s.deviceSessions[deviceID] = session
 	s.mu.Unlock()
 
+	// Start message loop in background so the reads below get responses.
+	// Note: runDeviceMessageLoop calls handleDeviceSessionClose on exit
+	go s.runDeviceMessageLoop(deviceID, framedConn, session)
+
 	// Check protocol version compatibility (DEC-050)
 	if err := s.checkDeviceVersion(ctx, session); err != nil {
 		s.mu.Lock()
@@ -1253,7 +1328,7 @@ func (s *ControllerService) Reconnect(ctx context.Context, deviceID string) erro
 		}
 		s.mu.Unlock()
 		session.Close()
-		tlsConn.Close()
+		conn.Close()
 		s.emitEvent(Event{
 			Type:     EventReconnectionFailed,
 			DeviceID: deviceID,
@@ -1273,10 +1348,6 @@ func (s *ControllerService) Reconnect(ctx context.Context, deviceID string) erro
 		DeviceID: deviceID,
 	})
 
-	// Start message loop in goroutine (non-blocking)
-	// Note: runDeviceMessageLoop calls handleDeviceSessionClose on exit
-	go s.runDeviceMessageLoop(deviceID, framedConn, session)
-
 	return nil
 }
 
@@ -1336,10 +1407,17 @@ func (s *ControllerService) buildOperationalTLSConfig(deviceID string) (*tls.Con
 		return nil, fmt.Errorf("failed to get Zone CA: %w", err)
 	}
 
+	// While the Zone CA is being rotated, devices may hold certificates
+	// from either CA.
+	zoneCAs := zoneCA.TLSClientCAs()
+	if pending, err := certStore.GetPendingZoneCA(); err == nil {
+		zoneCAs.AddCert(pending.Certificate)
+	}
+
 	// Build operational TLS config with mutual authentication
 	return transport.NewOperationalClientTLSConfig(&transport.OperationalTLSConfig{
 		ControllerCert: controllerCert.TLSCertificate(),
-		ZoneCAs:        zoneCA.TLSClientCAs(),
+		ZoneCAs:        zoneCAs,
 		ServerName:     deviceID,
 	})
 }
@@ -1395,7 +1473,7 @@ func (s *ControllerService) SaveState() error {
 	}
 
 	state := &persistence.ControllerState{
-		SavedAt: time.Now(),
+		SavedAt: s.timeSource().Now(),
 		ZoneID:  s.zoneID,
 	}
 
@@ -1404,12 +1482,22 @@ func (s *ControllerService) SaveState() error {
 		dm := persistence.DeviceMembership{
 			DeviceID:   device.ID,
 			DeviceType: device.DeviceType,
+			Label:      device.Label,
 			JoinedAt:   device.LastSeen, // Use LastSeen as proxy for JoinedAt
 			LastSeenAt: device.LastSeen,
 		}
 		state.Devices = append(state.Devices, dm)
 	}
 
+	// Save used commissioning tickets
+	for _, t := range s.tickets.Snapshot() {
+		state.UsedTickets = append(state.UsedTickets, persistence.UsedTicket{
+			Issuer:   t.Issuer,
+			ID:       t.ID,
+			NotAfter: time.Unix(t.NotAfter, 0),
+		})
+	}
+
 	s.mu.RUnlock()
 
 	return store.Save(state)
@@ -1445,11 +1533,23 @@ func (s *ControllerService) LoadState() error {
 		s.connectedDevices[dm.DeviceID] = &ConnectedDevice{
 			ID:         dm.DeviceID,
 			DeviceType: dm.DeviceType,
+			Label:      dm.Label,
 			Connected:  false, // Will be updated on reconnect
 			LastSeen:   dm.LastSeenAt,
 		}
 	}
 
+	// Restore used commissioning tickets so none can be replayed
+	used := make([]commissioning.UsedTicket, 0, len(state.UsedTickets))
+	for _, t := range state.UsedTickets {
+		used = append(used, commissioning.UsedTicket{
+			Issuer:   t.Issuer,
+			ID:       t.ID,
+			NotAfter: t.NotAfter.Unix(),
+		})
+	}
+	s.tickets.Restore(used)
+
 	return nil
 }
 
@@ -1477,6 +1577,9 @@ func (s *ControllerService) RenewDevice(ctx context.Context, deviceID string) er
 	if zoneCA == nil {
 		return fmt.Errorf("Zone CA not available")
 	}
+	if zoneCA.PrivateKey == nil {
+		return ErrNotZoneOwner
+	}
 
 	// Create renewal handler using the session's connection
 	renewalHandler := NewControllerRenewalHandler(zoneCA, session.Conn())
@@ -1503,6 +1606,51 @@ func (s *ControllerService) RenewDevice(ctx context.Context, deviceID string) er
 	return nil
 }
 
+// certKeyStore returns the key store behind store, or nil if the store does
+// not expose one.
+func certKeyStore(store cert.ControllerStore) cert.KeyStore {
+	if p, ok := store.(cert.KeyStoreProvider); ok {
+		return p.KeyStore()
+	}
+	return nil
+}
+
+// generateZoneCA creates a Zone CA whose key is generated under keyName in
+// the store's key store, so keys held by a remote signer or hardware never
+// leave it.
+func generateZoneCA(store cert.ControllerStore, zoneID string, zoneType cert.ZoneType, keyName string) (*cert.ZoneCA, error) {
+	signer, err := cert.GenerateKey(certKeyStore(store), keyName)
+	if err != nil {
+		return nil, err
+	}
+	zoneCA, err := cert.NewZoneCA(zoneID, zoneType, signer)
+	if err != nil {
+		return nil, err
+	}
+	zoneCA.KeyName = keyName
+	return zoneCA, nil
+}
+
+// generateControllerOpCert issues a controller operational certificate for a
+// key generated in the store's key store. The key is generated under the
+// name the current certificate does not use, so the current key keeps
+// working until the new certificate is saved; a leftover key from a failed
+// attempt under that name is replaced.
+func generateControllerOpCert(store cert.ControllerStore, zoneCA *cert.ZoneCA, controllerID string) (*cert.OperationalCert, error) {
+	current, _ := store.GetControllerCert()
+	keyName := cert.NextControllerKeyName(current)
+	key, err := cert.ReplaceKey(certKeyStore(store), keyName)
+	if err != nil {
+		return nil, err
+	}
+	oc, err := cert.NewControllerOperationalCert(zoneCA, controllerID, key)
+	if err != nil {
+		return nil, err
+	}
+	oc.KeyName = keyName
+	return oc, nil
+}
+
 // RenewalCheckInterval is how often to check for certificate renewal needs.
 const RenewalCheckInterval = 24 * time.Hour
 
@@ -1523,10 +1671,14 @@ func (s *ControllerService) RenewControllerCert() error {
 	if err != nil {
 		return fmt.Errorf("get Zone CA: %w", err)
 	}
+	if zoneCA.PrivateKey == nil {
+		// Admins get a new certificate from the owner via IssueAdminCert
+		return ErrNotZoneOwner
+	}
 
 	// Generate new controller operational certificate
 	controllerID := fmt.Sprintf("controller-%s", zoneCA.ZoneID)
-	newCert, err := cert.GenerateControllerOperationalCert(zoneCA, controllerID)
+	newCert, err := generateControllerOpCert(certStore, zoneCA, controllerID)
 	if err != nil {
 		return fmt.Errorf("generate controller cert: %w", err)
 	}
@@ -1562,7 +1714,7 @@ func (s *ControllerService) ControllerCertNeedsRenewal() bool {
 		return true // No cert means we need one
 	}
 
-	return controllerCert.NeedsRenewal()
+	return controllerCert.NeedsRenewalAt(s.timeSource().Now())
 }
 
 // GetControllerCert returns the controller's operational certificate.
@@ -1589,14 +1741,14 @@ func (s *ControllerService) runRenewalCheckLoop(ctx context.Context) {
 	// Check immediately on start
 	s.checkAndRenewControllerCert()
 
-	ticker := time.NewTicker(RenewalCheckInterval)
+	ticker := s.timeSource().NewTicker(RenewalCheckInterval)
 	defer ticker.Stop()
 
 	for {
 		select {
 		case <-ctx.Done():
 			return
-		case <-ticker.C:
+		case <-ticker.C():
 			s.checkAndRenewControllerCert()
 		}
 	}
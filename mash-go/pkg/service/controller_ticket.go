@@ -0,0 +1,132 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/mash-protocol/mash-go/pkg/commissioning"
+	"github.com/mash-protocol/mash-go/pkg/features"
+	"github.com/mash-protocol/mash-go/pkg/model"
+)
+
+// CommissionFromTicket commissions the device named by a signed
+// commissioning ticket from an external issuer such as a DSO backend
+// (security.md §9). The ticket is verified against
+// ControllerConfig.TicketIssuers and the device is then commissioned as in
+// CommissionDevice, announcing a pairing request if it is not advertising.
+//
+// A ticket commissions at most one device: once it has, it is rejected with
+// commissioning.ErrTicketUsed until it expires, also after a restart if a
+// state store is configured. A failed attempt does not use it up.
+//
+// Commissioning must complete before the ticket expires. The pairing request
+// is still bounded by PairingRequestTimeout, which an SMGW typically sets to
+// SMGWPairingRequestTimeout. If the ticket names a DeviceID and the device
+// reports a different one, the device is removed again and
+// ErrTicketDeviceMismatch is returned, joined with the removal error if the
+// device could not be removed. ControllerConfig.TicketCheckTimeout bounds
+// the comparison.
+//
+// The outcome is also emitted as EventTicketCommissioned or EventTicketFailed
+// so it can be reported back to the issuer.
+func (s *ControllerService) CommissionFromTicket(ctx context.Context, ticketData []byte) (*ConnectedDevice, error) {
+	ticket, device, err := s.commissionFromTicket(ctx, ticketData)
+
+	var ticketID string
+	if ticket != nil {
+		ticketID = ticket.ID
+	}
+	if err != nil {
+		s.emitEvent(Event{
+			Type:  EventTicketFailed,
+			Value: ticketID,
+			Error: err,
+		})
+		return nil, err
+	}
+
+	s.emitEvent(Event{
+		Type:     EventTicketCommissioned,
+		DeviceID: device.ID,
+		Value:    ticketID,
+	})
+	return device, nil
+}
+
+// commissionFromTicket does the work of CommissionFromTicket. It returns the
+// ticket whenever it could be verified so the outcome can name it.
+func (s *ControllerService) commissionFromTicket(ctx context.Context, ticketData []byte) (*commissioning.Ticket, *ConnectedDevice, error) {
+	now := s.timeSource().Now()
+	ticket, err := commissioning.VerifyTicket(ticketData, s.config.TicketIssuers, now)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := s.tickets.Use(ticket, now); err != nil {
+		return ticket, nil, err
+	}
+	// Persist the used ticket before acting on it, so a restart cannot
+	// replay it
+	if err := s.SaveState(); err != nil {
+		s.tickets.Release(ticket)
+		return ticket, nil, fmt.Errorf("save used ticket: %w", err)
+	}
+
+	ticketCtx, cancel := context.WithTimeout(ctx, ticket.Expires().Sub(now))
+	defer cancel()
+
+	device, err := s.CommissionDevice(ticketCtx, ticket.Discriminator, ticket.SetupCode, "")
+	if err != nil {
+		s.tickets.Release(ticket)
+		_ = s.SaveState()
+		if ctx.Err() == nil && errors.Is(ticketCtx.Err(), context.DeadlineExceeded) {
+			return ticket, nil, commissioning.ErrTicketExpired
+		}
+		return ticket, nil, err
+	}
+
+	if ticket.DeviceID == "" {
+		return ticket, device, nil
+	}
+	if err := s.checkTicketDeviceID(ctx, device.ID, ticket.DeviceID); err != nil {
+		// Never keep a device the issuer did not ask for. If the device
+		// cannot be told to leave, forget it locally and report that it
+		// still holds a certificate for the zone.
+		if removeErr := s.RemoveDevice(ctx, device.ID); removeErr != nil {
+			if decommErr := s.Decommission(device.ID); decommErr != nil {
+				removeErr = errors.Join(removeErr, decommErr)
+			}
+			err = errors.Join(err, fmt.Errorf("remove device %s: %w", device.ID, removeErr))
+		}
+		return ticket, nil, err
+	}
+	return ticket, device, nil
+}
+
+// checkTicketDeviceID reads the commissioned device's DeviceInfo deviceId
+// and compares it with the one the ticket expects. The device closes the
+// commissioning connection once it has its certificate (DEC-066), so the
+// read goes over a fresh operational connection.
+func (s *ControllerService) checkTicketDeviceID(ctx context.Context, deviceID, want string) error {
+	if s.config.TicketCheckTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.config.TicketCheckTimeout)
+		defer cancel()
+	}
+	if err := s.Reconnect(ctx, deviceID); err != nil {
+		return fmt.Errorf("%w: %v", ErrTicketDeviceMismatch, err)
+	}
+	session := s.GetSession(deviceID)
+	if session == nil {
+		return fmt.Errorf("%w: %v", ErrTicketDeviceMismatch, ErrNotConnected)
+	}
+
+	attrs, err := session.Read(ctx, 0, uint8(model.FeatureDeviceInfo), []uint16{features.DeviceInfoAttrDeviceID})
+	if err != nil {
+		return fmt.Errorf("%w: read deviceId: %v", ErrTicketDeviceMismatch, err)
+	}
+	if got, _ := attrs[features.DeviceInfoAttrDeviceID].(string); got != want {
+		return fmt.Errorf("%w: deviceId %q, want %q", ErrTicketDeviceMismatch, got, want)
+	}
+	return nil
+}
@@ -0,0 +1,293 @@
+package service
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"errors"
+	"net"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+
+	"github.com/mash-protocol/mash-go/pkg/clock"
+	"github.com/mash-protocol/mash-go/pkg/commissioning"
+	"github.com/mash-protocol/mash-go/pkg/discovery"
+	"github.com/mash-protocol/mash-go/pkg/discovery/mocks"
+	"github.com/mash-protocol/mash-go/pkg/features"
+	"github.com/mash-protocol/mash-go/pkg/model"
+	"github.com/mash-protocol/mash-go/pkg/persistence"
+	"github.com/mash-protocol/mash-go/pkg/transport"
+)
+
+// ticketTestSetup is a controller that trusts one ticket issuer and a
+// device on a pipe network reporting the given DeviceInfo deviceId.
+type ticketTestSetup struct {
+	controller *ControllerService
+	issuerKey  *ecdsa.PrivateKey
+	events     chan Event
+}
+
+func newTicketTestSetup(t *testing.T, ctx context.Context, deviceID string) *ticketTestSetup {
+	t.Helper()
+
+	issuerKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	network := transport.NewPipeNetwork()
+	device := startPipeDevice(t, ctx, network, 0)
+	info, err := device.Device().GetFeature(0, model.FeatureDeviceInfo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	attr, err := info.GetAttribute(features.DeviceInfoAttrDeviceID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := attr.SetValueInternal(deviceID); err != nil {
+		t.Fatal(err)
+	}
+	addr := device.CommissioningAddr().(*net.TCPAddr)
+
+	config := validControllerConfig()
+	config.Network = network
+	config.TicketIssuers = map[string]*ecdsa.PublicKey{"dso.example": &issuerKey.PublicKey}
+	controller, err := NewControllerService(config)
+	if err != nil {
+		t.Fatalf("NewControllerService failed: %v", err)
+	}
+	controller.SetCertStore(createControllerCertStore(t, config.ZoneName))
+
+	browser := mocks.NewMockBrowser(t)
+	browser.EXPECT().FindAllByDiscriminator(mock.Anything, uint16(1234)).
+		Return([]*discovery.CommissionableService{{
+			Host:          "localhost",
+			Port:          uint16(addr.Port),
+			Addresses:     []string{addr.IP.String()},
+			Discriminator: 1234,
+		}}, nil).Maybe()
+	browser.EXPECT().Stop().Return().Maybe()
+	controller.SetBrowser(browser)
+
+	events := make(chan Event, 16)
+	controller.OnEvent(func(e Event) {
+		if e.Type == EventTicketCommissioned || e.Type == EventTicketFailed {
+			events <- e
+		}
+	})
+
+	if err := controller.Start(ctx); err != nil {
+		t.Fatalf("Controller Start failed: %v", err)
+	}
+	t.Cleanup(func() { _ = controller.Stop() })
+
+	return &ticketTestSetup{controller: controller, issuerKey: issuerKey, events: events}
+}
+
+func (s *ticketTestSetup) sign(t *testing.T, ticket *commissioning.Ticket) []byte {
+	t.Helper()
+	data, err := commissioning.SignTicket(ticket, s.issuerKey)
+	if err != nil {
+		t.Fatalf("SignTicket failed: %v", err)
+	}
+	return data
+}
+
+func (s *ticketTestSetup) outcome(t *testing.T) Event {
+	t.Helper()
+	select {
+	case e := <-s.events:
+		return e
+	case <-time.After(5 * time.Second):
+		t.Fatal("no ticket outcome event")
+		return Event{}
+	}
+}
+
+func newTestTicket(deviceID string) *commissioning.Ticket {
+	now := time.Now()
+	return &commissioning.Ticket{
+		ID:            "ticket-42",
+		Issuer:        "dso.example",
+		Discriminator: 1234,
+		SetupCode:     "20202021",
+		DeviceID:      deviceID,
+		NotBefore:     now.Add(-time.Minute).Unix(),
+		NotAfter:      now.Add(time.Hour).Unix(),
+	}
+}
+
+func TestCommissionFromTicket(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	setup := newTicketTestSetup(t, ctx, "PEN12345.EVSE-001")
+
+	device, err := setup.controller.CommissionFromTicket(ctx, setup.sign(t, newTestTicket("PEN12345.EVSE-001")))
+	if err != nil {
+		t.Fatalf("CommissionFromTicket failed: %v", err)
+	}
+	if setup.controller.GetDevice(device.ID) == nil {
+		t.Error("commissioned device not tracked")
+	}
+
+	e := setup.outcome(t)
+	if e.Type != EventTicketCommissioned || e.Value != "ticket-42" || e.DeviceID != device.ID {
+		t.Errorf("outcome = %s/%v/%s, want TICKET_COMMISSIONED/ticket-42/%s", e.Type, e.Value, e.DeviceID, device.ID)
+	}
+}
+
+func TestCommissionFromTicket_Replay(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	setup := newTicketTestSetup(t, ctx, "PEN12345.EVSE-001")
+	ticket := setup.sign(t, newTestTicket("PEN12345.EVSE-001"))
+
+	if _, err := setup.controller.CommissionFromTicket(ctx, ticket); err != nil {
+		t.Fatalf("CommissionFromTicket failed: %v", err)
+	}
+	setup.outcome(t)
+
+	_, err := setup.controller.CommissionFromTicket(ctx, ticket)
+	if !errors.Is(err, commissioning.ErrTicketUsed) {
+		t.Fatalf("replayed ticket error = %v, want ErrTicketUsed", err)
+	}
+	if n := setup.controller.DeviceCount(); n != 1 {
+		t.Errorf("DeviceCount() = %d, want 1", n)
+	}
+
+	e := setup.outcome(t)
+	if e.Type != EventTicketFailed || e.Value != "ticket-42" {
+		t.Errorf("outcome = %s/%v, want TICKET_FAILED/ticket-42", e.Type, e.Value)
+	}
+}
+
+func TestCommissionFromTicket_ReplayAfterRestart(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	setup := newTicketTestSetup(t, ctx, "PEN12345.EVSE-001")
+	store := persistence.NewControllerStateStore(filepath.Join(t.TempDir(), "state.json"))
+	setup.controller.SetStateStore(store)
+	ticket := setup.sign(t, newTestTicket("PEN12345.EVSE-001"))
+
+	if _, err := setup.controller.CommissionFromTicket(ctx, ticket); err != nil {
+		t.Fatalf("CommissionFromTicket failed: %v", err)
+	}
+
+	config := validControllerConfig()
+	config.TicketIssuers = setup.controller.config.TicketIssuers
+	restarted, err := NewControllerService(config)
+	if err != nil {
+		t.Fatalf("NewControllerService failed: %v", err)
+	}
+	restarted.SetStateStore(store)
+	if err := restarted.LoadState(); err != nil {
+		t.Fatalf("LoadState failed: %v", err)
+	}
+
+	if _, err := restarted.CommissionFromTicket(ctx, ticket); !errors.Is(err, commissioning.ErrTicketUsed) {
+		t.Fatalf("replayed ticket after restart error = %v, want ErrTicketUsed", err)
+	}
+}
+
+func TestCommissionFromTicket_DeviceMismatch(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	setup := newTicketTestSetup(t, ctx, "PEN12345.EVSE-001")
+
+	_, err := setup.controller.CommissionFromTicket(ctx, setup.sign(t, newTestTicket("PEN12345.EVSE-999")))
+	if !errors.Is(err, ErrTicketDeviceMismatch) {
+		t.Fatalf("CommissionFromTicket error = %v, want ErrTicketDeviceMismatch", err)
+	}
+	if n := setup.controller.DeviceCount(); n != 0 {
+		t.Errorf("DeviceCount() = %d, want 0 after mismatch", n)
+	}
+
+	e := setup.outcome(t)
+	if e.Type != EventTicketFailed || e.Value != "ticket-42" || !errors.Is(e.Error, ErrTicketDeviceMismatch) {
+		t.Errorf("outcome = %s/%v/%v, want TICKET_FAILED/ticket-42/mismatch", e.Type, e.Value, e.Error)
+	}
+}
+
+func TestCommissionFromTicket_Rejected(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	setup := newTicketTestSetup(t, ctx, "PEN12345.EVSE-001")
+
+	otherKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	forged, err := commissioning.SignTicket(newTestTicket(""), otherKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = setup.controller.CommissionFromTicket(ctx, forged)
+	if !errors.Is(err, commissioning.ErrTicketSignature) {
+		t.Fatalf("CommissionFromTicket error = %v, want ErrTicketSignature", err)
+	}
+	if n := setup.controller.DeviceCount(); n != 0 {
+		t.Errorf("DeviceCount() = %d, want 0", n)
+	}
+
+	e := setup.outcome(t)
+	if e.Type != EventTicketFailed || e.Value != "" {
+		t.Errorf("outcome = %s/%v, want TICKET_FAILED with no ticket ID", e.Type, e.Value)
+	}
+}
+
+func TestCommissionFromTicket_ExpiresDuringPairingRequest(t *testing.T) {
+	issuerKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The controller's clock sits just before the ticket expires.
+	ticket := newTestTicket("")
+	fake := clock.NewFake(time.Unix(ticket.NotAfter, 0).Add(-200 * time.Millisecond))
+
+	config := validControllerConfig()
+	config.Clock = fake
+	config.DiscoveryTimeout = 20 * time.Millisecond
+	config.PairingRequestPollInterval = 20 * time.Millisecond
+	config.TicketIssuers = map[string]*ecdsa.PublicKey{"dso.example": &issuerKey.PublicKey}
+	svc, err := NewControllerService(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	browser := mocks.NewMockBrowser(t)
+	browser.EXPECT().FindAllByDiscriminator(mock.Anything, uint16(1234)).Return(nil, nil).Maybe()
+	browser.EXPECT().Stop().Return().Maybe()
+	svc.SetBrowser(browser)
+
+	advertiser := mocks.NewMockAdvertiser(t)
+	advertiser.EXPECT().AnnouncePairingRequest(mock.Anything, mock.Anything).Return(nil).Once()
+	advertiser.EXPECT().StopPairingRequest(uint16(1234)).Return(nil).Maybe()
+	advertiser.EXPECT().StopAll().Return().Maybe()
+	svc.SetAdvertiser(advertiser)
+
+	ctx := context.Background()
+	if err := svc.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = svc.Stop() }()
+
+	svc.mu.Lock()
+	svc.zoneID = "a1b2c3d4e5f6a7b8"
+	svc.mu.Unlock()
+
+	data, err := commissioning.SignTicket(ticket, issuerKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = svc.CommissionFromTicket(ctx, data)
+	if !errors.Is(err, commissioning.ErrTicketExpired) {
+		t.Errorf("CommissionFromTicket error = %v, want ErrTicketExpired", err)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"crypto/ecdsa"
 	"crypto/x509"
 	"testing"
+	"time"
 
 	"github.com/mash-protocol/mash-go/pkg/cert"
 	"github.com/mash-protocol/mash-go/pkg/commissioning"
@@ -361,6 +362,99 @@ func TestHandleCertRenewalSuccess_UpdatesOperationalAdvertisingDeviceID(t *testi
 	assert.Equal(t, newDeviceID, svc.deviceID)
 }
 
+// TestHandleZoneCAMessage verifies the device only trusts a new Zone CA that
+// is cross-signed by the current one, and only retires the old CA once its
+// own certificate chains to the new one.
+func TestHandleZoneCAMessage(t *testing.T) {
+	const zoneID = "bbcc210c3829120f"
+
+	device := model.NewDevice("test-device", 0x1234, 0x5678)
+	svc, err := NewDeviceService(device, validDeviceConfig())
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = svc.Stop() })
+
+	store := cert.NewMemoryStore()
+	svc.certStore = store
+
+	oldCA, err := cert.GenerateZoneCA(zoneID, cert.ZoneTypeLocal)
+	require.NoError(t, err)
+	newCA, err := cert.GenerateZoneCA(zoneID, cert.ZoneTypeLocal)
+	require.NoError(t, err)
+	require.NoError(t, store.SetZoneCACert(zoneID, oldCA.Certificate))
+
+	kp, err := cert.GenerateKeyPair()
+	require.NoError(t, err)
+	csr, err := cert.CreateCSR(kp, &cert.CSRInfo{
+		Identity: cert.DeviceIdentity{DeviceID: "792c69cad816d9e9", VendorID: 0x1234, ProductID: 0x5678},
+		ZoneID:   zoneID,
+	})
+	require.NoError(t, err)
+	opCert, err := cert.SignCSR(oldCA, csr)
+	require.NoError(t, err)
+	require.NoError(t, store.SetOperationalCert(&cert.OperationalCert{
+		Certificate: opCert,
+		PrivateKey:  kp.PrivateKey,
+		ZoneID:      zoneID,
+	}))
+
+	t.Run("RejectsUnendorsedCA", func(t *testing.T) {
+		rogueCA, err := cert.GenerateZoneCA(zoneID, cert.ZoneTypeLocal)
+		require.NoError(t, err)
+		cross, err := cert.CrossSignZoneCA(rogueCA, newCA.Certificate, time.Now())
+		require.NoError(t, err)
+
+		status := svc.handleZoneCAMessage(zoneID, &commissioning.ZoneCAUpdate{
+			MsgType:   commissioning.MsgZoneCAUpdate,
+			ZoneCA:    newCA.Certificate.Raw,
+			CrossCert: cross.Raw,
+		})
+		assert.Equal(t, commissioning.RenewalStatusUntrustedCA, status)
+		assert.Len(t, store.GetZoneCACerts(zoneID), 1)
+	})
+
+	t.Run("TrustsCrossSignedCA", func(t *testing.T) {
+		cross, err := cert.CrossSignZoneCA(oldCA, newCA.Certificate, time.Now())
+		require.NoError(t, err)
+
+		status := svc.handleZoneCAMessage(zoneID, &commissioning.ZoneCAUpdate{
+			MsgType:   commissioning.MsgZoneCAUpdate,
+			ZoneCA:    newCA.Certificate.Raw,
+			CrossCert: cross.Raw,
+		})
+		require.Equal(t, commissioning.RenewalStatusSuccess, status)
+		assert.Len(t, store.GetZoneCACerts(zoneID), 2)
+		assert.Len(t, svc.operationalTLSConfig.ClientCAs.Subjects(), 2)
+	})
+
+	t.Run("RefusesToRetireOwnIssuer", func(t *testing.T) {
+		status := svc.handleZoneCAMessage(zoneID, &commissioning.ZoneCARetire{
+			MsgType: commissioning.MsgZoneCARetire,
+			ZoneCA:  newCA.Certificate.Raw,
+		})
+		assert.Equal(t, commissioning.RenewalStatusInvalidCert, status)
+		assert.Len(t, store.GetZoneCACerts(zoneID), 2)
+	})
+
+	t.Run("RetiresOldCA", func(t *testing.T) {
+		newCert, err := cert.SignCSR(newCA, csr)
+		require.NoError(t, err)
+		require.NoError(t, store.SetOperationalCert(&cert.OperationalCert{
+			Certificate: newCert,
+			PrivateKey:  kp.PrivateKey,
+			ZoneID:      zoneID,
+		}))
+
+		status := svc.handleZoneCAMessage(zoneID, &commissioning.ZoneCARetire{
+			MsgType: commissioning.MsgZoneCARetire,
+			ZoneCA:  newCA.Certificate.Raw,
+		})
+		require.Equal(t, commissioning.RenewalStatusSuccess, status)
+		certs := store.GetZoneCACerts(zoneID)
+		require.Len(t, certs, 1)
+		assert.True(t, certs[0].Equal(newCA.Certificate))
+	})
+}
+
 // TestDeviceRenewalHandler_RejectStaleNonce verifies nonce binding per DEC-047.
 // When a new renewal request is sent, any cert from a previous CSR must be rejected.
 func TestDeviceRenewalHandler_RejectStaleNonce(t *testing.T) {
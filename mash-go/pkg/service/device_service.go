@@ -13,6 +13,7 @@ import (
 	"time"
 
 	"github.com/mash-protocol/mash-go/pkg/cert"
+	"github.com/mash-protocol/mash-go/pkg/clock"
 	"github.com/mash-protocol/mash-go/pkg/commissioning"
 	"github.com/mash-protocol/mash-go/pkg/discovery"
 	"github.com/mash-protocol/mash-go/pkg/duration"
@@ -74,6 +75,10 @@ type DeviceService struct {
 	// Subscription management
 	subscriptionManager dispatch.SubscriptionTracker
 
+	// Feature event log (§9), shared by all zone sessions so that events
+	// survive reconnects and can be replayed from a given event number
+	events *model.EventBuffer
+
 	// Connected zones
 	connectedZones map[string]*ConnectedZone
 
@@ -96,7 +101,7 @@ type DeviceService struct {
 	// for a short period after explicit commissioning exit.
 	disconnectReentryHoldoff      time.Duration
 	disconnectReentryBlockedUntil time.Time
-	disconnectReentryTimer        *time.Timer
+	disconnectReentryTimer        clock.Timer
 
 	// Logger for debug output (optional)
 	logger *slog.Logger
@@ -107,14 +112,30 @@ type DeviceService struct {
 	// LimitResolver (optional, set by CLI via SetLimitResolver)
 	limitResolver *features.LimitResolver
 
+	// SetpointResolver (optional, set by CLI via SetSetpointResolver)
+	setpointResolver *features.SetpointResolver
+
+	// PhaseCurrentResolver (optional, set by CLI via SetPhaseCurrentResolver)
+	phaseCurrentResolver *features.PhaseCurrentResolver
+
+	// ConstraintScheduler (optional, set by CLI via SetConstraintScheduler)
+	constraintScheduler *features.ConstraintScheduler
+
 	// Persistence (optional, set by CLI)
 	certStore  cert.Store
 	stateStore *persistence.DeviceStateStore
 
+	// Serializes SaveState so a later snapshot is never overwritten by an
+	// earlier one
+	saveMu sync.Mutex
+
 	// Context for cancellation
 	ctx    context.Context
 	cancel context.CancelFunc
 
+	// Time source for timers, expiry and heartbeats (config.Clock or real)
+	clock clock.Clock
+
 	// Test clock offset for certificate validation (set via TriggerAdjustClockBase)
 	clockOffset time.Duration
 
@@ -148,6 +169,8 @@ func NewDeviceService(device *model.Device, config DeviceConfig) (*DeviceService
 		return nil, err
 	}
 
+	c := clock.OrReal(config.Clock)
+
 	svc := &DeviceService{
 		config:                   config,
 		device:                   device,
@@ -156,21 +179,24 @@ func NewDeviceService(device *model.Device, config DeviceConfig) (*DeviceService
 		zoneSessions:             make(map[string]*ZoneSession),
 		failsafeTimers:           make(map[string]*failsafe.Timer),
 		zoneIndexMap:             make(map[string]uint8),
-		connTracker:              newConnTracker(),
+		events:                   model.NewEventBufferWithClock(config.EventBufferSize, c),
+		connTracker:              newConnTrackerWithClock(c),
 		certStore:                cert.NewMemoryStore(),
 		logger:                   config.Logger,
 		protocolLogger:           config.ProtocolLogger,
 		disconnectReentryHoldoff: defaultDisconnectReentryHoldoff,
+		clock:                    c,
 	}
 
 	// Initialize duration manager with expiry callback
-	svc.durationManager = duration.NewManager()
+	svc.durationManager = duration.NewManagerWithClock(c)
 	svc.durationManager.OnExpiry(func(zoneIndex uint8, cmdType duration.CommandType, value any) {
 		svc.handleDurationExpiry(zoneIndex, cmdType, value)
 	})
 
 	// Initialize subscription manager
 	subConfig := subscription.DefaultConfig()
+	subConfig.Clock = c
 	svc.subscriptionManager = subscription.NewManagerWithConfig(subConfig)
 
 	// Initialize PASE attempt tracker (DEC-047)
@@ -196,6 +222,11 @@ func (s *DeviceService) Device() *model.Device {
 	return s.device
 }
 
+// Events returns the device event buffer.
+func (s *DeviceService) Events() *model.EventBuffer {
+	return s.events
+}
+
 // State returns the current service state.
 func (s *DeviceService) State() ServiceState {
 	s.mu.RLock()
@@ -268,12 +299,63 @@ func (s *DeviceService) debugLog(msg string, args ...any) {
 	}
 }
 
+// timeSource returns the service clock, defaulting to the real clock.
+func (s *DeviceService) timeSource() clock.Clock {
+	return clock.OrReal(s.clock)
+}
+
 // SetLimitResolver sets the LimitResolver so that TriggerResetTestState and
-// RemoveZone can clear resolver state (limits, timers) alongside attribute state.
+// RemoveZone can clear resolver state (limits, timers) alongside attribute state,
+// and SaveState/LoadState can persist active limits. If DeviceConfig.Clock is
+// set, the resolver's timers are moved onto it; the same applies to the other
+// resolver setters below. State is saved whenever a limit is set, cleared or
+// expires, so limits survive a power cycle.
 func (s *DeviceService) SetLimitResolver(lr *features.LimitResolver) {
+	if lr != nil {
+		if s.config.Clock != nil {
+			lr.SetClock(s.config.Clock)
+		}
+		lr.OnChange = s.saveResolverState
+	}
 	s.limitResolver = lr
 }
 
+// SetSetpointResolver sets the SetpointResolver so that TriggerResetTestState
+// and RemoveZone can clear per-zone setpoints and timers alongside limits,
+// and SaveState/LoadState can persist active setpoints.
+func (s *DeviceService) SetSetpointResolver(sr *features.SetpointResolver) {
+	if sr != nil {
+		if s.config.Clock != nil {
+			sr.SetClock(s.config.Clock)
+		}
+		sr.OnChange = s.saveResolverState
+	}
+	s.setpointResolver = sr
+}
+
+// SetPhaseCurrentResolver sets the PhaseCurrentResolver so that
+// TriggerResetTestState and RemoveZone can clear per-phase current limits
+// and setpoints alongside the other resolver state.
+func (s *DeviceService) SetPhaseCurrentResolver(pr *features.PhaseCurrentResolver) {
+	if pr != nil {
+		if s.config.Clock != nil {
+			pr.SetClock(s.config.Clock)
+		}
+		pr.OnChange = s.saveResolverState
+	}
+	s.phaseCurrentResolver = pr
+}
+
+// SetConstraintScheduler sets the ConstraintScheduler so that
+// TriggerResetTestState and RemoveZone can withdraw constraint signals
+// (and their limit contribution) alongside the resolver state.
+func (s *DeviceService) SetConstraintScheduler(cs *features.ConstraintScheduler) {
+	if cs != nil && s.config.Clock != nil {
+		cs.SetClock(s.config.Clock)
+	}
+	s.constraintScheduler = cs
+}
+
 // parsePort extracts the port from a listen address (e.g., ":8443" -> 8443).
 func parsePort(addr string) uint16 {
 	// Handle formats: ":8443", "0.0.0.0:8443", "localhost:8443"
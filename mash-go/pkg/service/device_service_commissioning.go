@@ -53,7 +53,7 @@ func (s *DeviceService) acceptCommissioningConnection() (bool, commissioningReje
 	// backoff tracker escalates through tiers even if cooldown blocks the
 	// attempt from reaching the PASE handler.
 	if s.config.ConnectionCooldown > 0 {
-		elapsed := time.Since(s.lastCommissioningAttempt)
+		elapsed := s.timeSource().Since(s.lastCommissioningAttempt)
 		if elapsed < s.config.ConnectionCooldown {
 			return false, rejectCooldown, fmt.Sprintf("cooldown active (%s remaining)", s.config.ConnectionCooldown-elapsed), 0
 		}
@@ -118,7 +118,7 @@ func (s *DeviceService) releaseCommissioningConnection(gen uint64) {
 		return
 	}
 	s.commissioningConnActive = false
-	s.lastCommissioningAttempt = time.Now()
+	s.lastCommissioningAttempt = s.timeSource().Now()
 }
 
 // ResetPASETracker resets the PASE attempt tracker.
@@ -140,7 +140,7 @@ func (s *DeviceService) computeBusyRetryAfter() uint32 {
 
 	// Check if cooldown is active
 	if s.config.ConnectionCooldown > 0 {
-		elapsed := time.Since(s.lastCommissioningAttempt)
+		elapsed := s.timeSource().Since(s.lastCommissioningAttempt)
 		remaining := s.config.ConnectionCooldown - elapsed
 		if remaining > 0 {
 			return uint32(remaining.Milliseconds())
@@ -259,7 +259,7 @@ func (s *DeviceService) ExitCommissioningMode() error {
 
 	zoneCount := len(s.connectedZones)
 	dm := s.discoveryManager
-	s.disconnectReentryBlockedUntil = time.Now().Add(s.disconnectReentryHoldoff)
+	s.disconnectReentryBlockedUntil = s.timeSource().Now().Add(s.disconnectReentryHoldoff)
 	s.mu.Unlock()
 
 	// Close the commissioning ALPN gate.
@@ -309,7 +309,7 @@ func (s *DeviceService) exitCommissioningForEpoch(epoch uint64) error {
 	// Close the gate under the same lock that verified the epoch,
 	// so no concurrent EnterCommissioningMode can slip in between.
 	s.commissioningOpen.Store(false)
-	s.disconnectReentryBlockedUntil = time.Now().Add(s.disconnectReentryHoldoff)
+	s.disconnectReentryBlockedUntil = s.timeSource().Now().Add(s.disconnectReentryHoldoff)
 
 	zoneCount := len(s.connectedZones)
 	dm := s.discoveryManager
@@ -3,8 +3,10 @@ package service
 import (
 	"context"
 	"fmt"
+	"net"
 
 	"github.com/mash-protocol/mash-go/pkg/discovery"
+	"github.com/mash-protocol/mash-go/pkg/transport"
 )
 
 // DiscoveryManager returns the discovery manager (for test control commands).
@@ -27,7 +29,8 @@ func (s *DeviceService) SetAdvertiser(advertiser discovery.Advertiser) {
 		Brand:         s.config.Brand,
 		Model:         s.config.Model,
 		DeviceName:    s.config.DeviceName,
-		Port:          8443,
+		Port:          s.advertisedPortLocked(),
+		Addresses:     s.advertisedAddressesLocked(),
 	})
 
 	// Set commissioning window duration from config
@@ -65,10 +68,8 @@ func (s *DeviceService) StartOperationalAdvertising() error {
 		return nil // No discovery manager, skip
 	}
 
-	port := uint16(0)
-	if s.listener != nil {
-		port = parsePort(s.listener.Addr().String())
-	}
+	port := s.advertisedPortLocked()
+	addrs := s.advertisedAddressesLocked()
 
 	s.debugLog("StartOperationalAdvertising: advertising zones",
 		"deviceID", s.deviceID,
@@ -82,6 +83,7 @@ func (s *DeviceService) StartOperationalAdvertising() error {
 			VendorProduct: fmt.Sprintf("%04x:%04x", s.device.VendorID(), s.device.ProductID()),
 			EndpointCount: uint8(s.device.EndpointCount()),
 			Port:          port,
+			Addresses:     addrs,
 		}
 
 		s.debugLog("StartOperationalAdvertising: advertising zone",
@@ -260,3 +262,83 @@ func (s *DeviceService) updatePairingRequestListening() {
 		_ = s.StartPairingRequestListening(ctx)
 	}
 }
+
+// advertisedPortLocked returns the port to advertise over mDNS: the
+// configured AdvertisePort, else the listener's port, else the port of the
+// configured listen address. Caller must hold s.mu.
+func (s *DeviceService) advertisedPortLocked() uint16 {
+	if s.config.AdvertisePort != 0 {
+		return s.config.AdvertisePort
+	}
+	if s.listener != nil {
+		if addr, ok := s.listener.Addr().(*net.TCPAddr); ok {
+			return uint16(addr.Port)
+		}
+		return 0
+	}
+	ep, err := transport.ParseEndpoint(s.config.OperationalListenAddress)
+	if err != nil || !ep.IsTCP() {
+		return 0
+	}
+	return parsePort(ep.Address)
+}
+
+// advertisedAddressesLocked returns the IP addresses to advertise over
+// mDNS, or nil to advertise all interface addresses. See
+// DeviceConfig.AdvertiseAddresses. Caller must hold s.mu.
+func (s *DeviceService) advertisedAddressesLocked() []string {
+	if len(s.config.AdvertiseAddresses) > 0 {
+		return s.config.AdvertiseAddresses
+	}
+	ep, err := transport.ParseEndpoint(s.config.OperationalListenAddress)
+	if err != nil || !ep.IsTCP() {
+		return nil
+	}
+
+	var ip net.IP
+	if s.listener != nil {
+		if addr, ok := s.listener.Addr().(*net.TCPAddr); ok {
+			ip = addr.IP
+		}
+	} else if host, _, err := net.SplitHostPort(ep.Address); err == nil {
+		ip = net.ParseIP(host)
+	}
+	if ip != nil && !ip.IsUnspecified() {
+		return []string{ip.String()}
+	}
+
+	switch ep.Network {
+	case transport.SchemeTCP4:
+		return interfaceAddresses(s.config.MDNSInterface, true)
+	case transport.SchemeTCP6:
+		return interfaceAddresses(s.config.MDNSInterface, false)
+	}
+	return nil
+}
+
+// interfaceAddresses returns the non-loopback IPv4 or IPv6 addresses of
+// the named interface, or of all the host's interfaces if name is empty.
+func interfaceAddresses(name string, ipv4 bool) []string {
+	var addrs []net.Addr
+	var err error
+	if name == "" {
+		addrs, err = net.InterfaceAddrs()
+	} else {
+		var iface *net.Interface
+		if iface, err = net.InterfaceByName(name); err == nil {
+			addrs, err = iface.Addrs()
+		}
+	}
+	if err != nil {
+		return nil
+	}
+	var ips []string
+	for _, addr := range addrs {
+		ipNet, ok := addr.(*net.IPNet)
+		if !ok || ipNet.IP.IsLoopback() || (ipNet.IP.To4() != nil) != ipv4 {
+			continue
+		}
+		ips = append(ips, ipNet.IP.String())
+	}
+	return ips
+}
@@ -0,0 +1,165 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+
+	"github.com/mash-protocol/mash-go/pkg/discovery"
+	"github.com/mash-protocol/mash-go/pkg/discovery/mocks"
+	"github.com/mash-protocol/mash-go/pkg/model"
+	"github.com/mash-protocol/mash-go/pkg/transport"
+)
+
+func TestDeviceConfigValidate_Endpoints(t *testing.T) {
+	config := validDeviceConfig()
+	config.OperationalListenAddress = "udp://[::]:8443"
+	if err := config.Validate(); !errors.Is(err, ErrInvalidConfig) {
+		t.Errorf("udp listen address: err = %v, want ErrInvalidConfig", err)
+	}
+
+	config = validDeviceConfig()
+	config.AdvertiseAddresses = []string{"gateway.local"}
+	if err := config.Validate(); !errors.Is(err, ErrInvalidConfig) {
+		t.Errorf("hostname advertise address: err = %v, want ErrInvalidConfig", err)
+	}
+
+	config = validDeviceConfig()
+	config.OperationalListenAddress = "unix:///run/mash/device.sock"
+	config.AdvertiseAddresses = []string{"fd00::1", "192.168.1.20"}
+	if err := config.Validate(); err != nil {
+		t.Errorf("unix listener: err = %v", err)
+	}
+}
+
+// startEndpointDevice starts a device with config, opens its commissioning
+// window and returns the device with the commissionable info it advertises.
+func startEndpointDevice(t *testing.T, config DeviceConfig) (*DeviceService, *discovery.CommissionableInfo) {
+	t.Helper()
+
+	svc, err := NewDeviceService(model.NewDevice("endpoint-device", 0x1234, 0x5678), config)
+	if err != nil {
+		t.Fatalf("NewDeviceService failed: %v", err)
+	}
+
+	advertised := make(chan *discovery.CommissionableInfo, 1)
+	advertiser := mocks.NewMockAdvertiser(t)
+	advertiser.EXPECT().AdvertiseCommissionable(mock.Anything, mock.Anything).
+		Run(func(_ context.Context, info *discovery.CommissionableInfo) {
+			select {
+			case advertised <- info:
+			default:
+			}
+		}).Return(nil).Maybe()
+	advertiser.EXPECT().StopCommissionable().Return(nil).Maybe()
+	advertiser.EXPECT().StopAll().Return().Maybe()
+	svc.SetAdvertiser(advertiser)
+
+	if err := svc.Start(context.Background()); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	t.Cleanup(func() { _ = svc.Stop() })
+
+	if err := svc.EnterCommissioningMode(); err != nil {
+		t.Fatalf("EnterCommissioningMode failed: %v", err)
+	}
+
+	select {
+	case info := <-advertised:
+		return svc, info
+	case <-time.After(2 * time.Second):
+		t.Fatal("commissionable service not advertised")
+		return nil, nil
+	}
+}
+
+func TestDeviceService_UnixListener(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "device.sock")
+	config := validDeviceConfig()
+	config.ListenAddress = ""
+	config.OperationalListenAddress = "unix://" + path
+	config.AdvertisePort = 8443
+	config.AdvertiseAddresses = []string{"fd00::20"}
+
+	svc, info := startEndpointDevice(t, config)
+
+	if addr := svc.CommissioningAddr(); addr == nil || addr.Network() != "unix" {
+		t.Fatalf("CommissioningAddr() = %v, want a unix socket", addr)
+	}
+	if info.Port != 8443 || !slices.Equal(info.Addresses, []string{"fd00::20"}) {
+		t.Errorf("advertised %v port %d, want [fd00::20] port 8443", info.Addresses, info.Port)
+	}
+
+	// The commissioning TLS handshake runs over the socket.
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	conn, err := transport.DialTLS(ctx, nil, config.OperationalListenAddress, transport.NewCommissioningTLSConfig())
+	if err != nil {
+		t.Fatalf("DialTLS failed: %v", err)
+	}
+	defer conn.Close()
+	if got := conn.ConnectionState().NegotiatedProtocol; got != transport.ALPNCommissioningProtocol {
+		t.Errorf("negotiated ALPN = %q, want %q", got, transport.ALPNCommissioningProtocol)
+	}
+}
+
+func TestDeviceService_TCP4ListenerAdvertisesBoundAddress(t *testing.T) {
+	config := validDeviceConfig()
+	config.ListenAddress = ""
+	config.OperationalListenAddress = "tcp4://127.0.0.1:0"
+
+	svc, info := startEndpointDevice(t, config)
+
+	if addr := svc.CommissioningAddr(); addr == nil || addr.Network() != "tcp" {
+		t.Fatalf("CommissioningAddr() = %v, want a TCP address", addr)
+	}
+	if !slices.Equal(info.Addresses, []string{"127.0.0.1"}) {
+		t.Errorf("advertised addresses = %v, want [127.0.0.1]", info.Addresses)
+	}
+}
+
+func TestInterfaceAddressesFilter(t *testing.T) {
+	if got := interfaceAddresses("no-such-interface", true); got != nil {
+		t.Errorf("unknown interface: addresses = %v, want none", got)
+	}
+
+	ifaces, err := net.Interfaces()
+	if err != nil {
+		t.Skip(err)
+	}
+	for _, iface := range ifaces {
+		if iface.Flags&net.FlagLoopback != 0 {
+			continue
+		}
+		addrs, err := iface.Addrs()
+		if err != nil {
+			continue
+		}
+		var want []string
+		for _, addr := range addrs {
+			if ipNet, ok := addr.(*net.IPNet); ok && ipNet.IP.To4() != nil {
+				want = append(want, ipNet.IP.String())
+			}
+		}
+		if len(want) == 0 {
+			continue
+		}
+
+		config := validDeviceConfig()
+		config.ListenAddress = ""
+		config.OperationalListenAddress = "tcp4://0.0.0.0:0"
+		config.MDNSInterface = iface.Name
+		_, info := startEndpointDevice(t, config)
+		if !slices.Equal(info.Addresses, want) {
+			t.Errorf("advertised addresses = %v, want those of %s %v", info.Addresses, iface.Name, want)
+		}
+		return
+	}
+	t.Skip("no non-loopback interface with an IPv4 address")
+}
@@ -0,0 +1,58 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mash-protocol/mash-go/pkg/features"
+	"github.com/mash-protocol/mash-go/pkg/model"
+)
+
+func TestDeviceServiceEmitEvent(t *testing.T) {
+	svc := createTestDeviceService(t)
+
+	received := make(chan Event, 4)
+	svc.OnEvent(func(e Event) {
+		if e.Type == EventFeatureEvent {
+			received <- e
+		}
+	})
+
+	data := map[string]any{"faultCode": uint32(1001)}
+	if err := svc.EmitEvent(1, uint8(model.FeatureStatus), features.StatusEventFaultRaised, data); err != nil {
+		t.Fatalf("EmitEvent failed: %v", err)
+	}
+
+	select {
+	case e := <-received:
+		if e.EventID != features.StatusEventFaultRaised || e.EventNumber != 1 {
+			t.Errorf("unexpected event: id=%d number=%d", e.EventID, e.EventNumber)
+		}
+		if e.EndpointID != 1 || e.FeatureID != uint16(model.FeatureStatus) {
+			t.Errorf("unexpected source: endpoint=%d feature=%d", e.EndpointID, e.FeatureID)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected EventFeatureEvent")
+	}
+
+	buffered := svc.Events().Since(1)
+	if len(buffered) != 1 || buffered[0].EventID != features.StatusEventFaultRaised {
+		t.Errorf("expected event in buffer, got %+v", buffered)
+	}
+
+	// Unknown event IDs are rejected and not buffered
+	if err := svc.EmitEvent(1, uint8(model.FeatureStatus), 99, nil); err != model.ErrEventNotFound {
+		t.Errorf("expected ErrEventNotFound, got %v", err)
+	}
+	if svc.Events().LastNumber() != 1 {
+		t.Errorf("expected LastNumber 1, got %d", svc.Events().LastNumber())
+	}
+}
+
+func TestDeviceServiceEventBufferSize(t *testing.T) {
+	svc := createTestDeviceService(t)
+
+	if svc.Events().Capacity() != model.DefaultEventBufferSize {
+		t.Errorf("expected default capacity %d, got %d", model.DefaultEventBufferSize, svc.Events().Capacity())
+	}
+}
@@ -65,7 +65,7 @@ func (s *DeviceService) Start(ctx context.Context) error {
 
 	// DEC-067: Generate stable commissioning certificate once at startup.
 	// This cert is reused across all commissioning windows.
-	s.commissioningCert, err = generateSelfSignedCert(s.config.Discriminator)
+	s.commissioningCert, err = generateSelfSignedCert(s.config.Discriminator, s.timeSource().Now())
 	if err != nil {
 		s.mu.Lock()
 		s.state = StateIdle
@@ -121,6 +121,7 @@ func (s *DeviceService) Start(ctx context.Context) error {
 	// Initialize discovery advertiser if not already set (e.g., by tests)
 	if s.advertiser == nil {
 		advConfig := discovery.DefaultAdvertiserConfig()
+		advConfig.Interface = s.config.MDNSInterface
 		advertiser, err := discovery.NewMDNSAdvertiser(advConfig)
 		if err != nil {
 			s.stopListener()
@@ -133,7 +134,9 @@ func (s *DeviceService) Start(ctx context.Context) error {
 		s.discoveryManager = discovery.NewDiscoveryManager(advertiser)
 
 		// Use operational port for commissionable info (same port, ALPN routing).
-		commPort := parsePort(s.config.OperationalListenAddress)
+		s.mu.RLock()
+		commPort, commAddrs := s.advertisedPortLocked(), s.advertisedAddressesLocked()
+		s.mu.RUnlock()
 		s.discoveryManager.SetCommissionableInfo(&discovery.CommissionableInfo{
 			Discriminator: s.config.Discriminator,
 			Categories:    s.config.Categories,
@@ -142,6 +145,7 @@ func (s *DeviceService) Start(ctx context.Context) error {
 			Model:         s.config.Model,
 			DeviceName:    s.config.DeviceName,
 			Port:          commPort,
+			Addresses:     commAddrs,
 		})
 
 		// Set commissioning window duration from config
@@ -270,7 +274,7 @@ func (s *DeviceService) buildOperationalTLSConfig() {
 			s.mu.RLock()
 			offset := s.clockOffset
 			s.mu.RUnlock()
-			now := time.Now().Add(offset)
+			now := s.timeSource().Now().Add(offset)
 			const clockSkewTolerance = 300 * time.Second
 			if now.Before(cert.NotBefore) && cert.NotBefore.Sub(now) > clockSkewTolerance {
 				return fmt.Errorf("certificate not yet valid (notBefore=%s, now=%s)", cert.NotBefore.UTC(), now.UTC())
@@ -357,7 +361,7 @@ func (s *DeviceService) ensureListenerStarted() error {
 	if s.listener != nil {
 		return nil // Already running
 	}
-	listener, err := net.Listen("tcp", s.config.OperationalListenAddress)
+	listener, err := transport.OrSockets(s.config.Network).Listen(s.config.OperationalListenAddress)
 	if err != nil {
 		return fmt.Errorf("listener: %w", err)
 	}
@@ -442,7 +446,7 @@ func (s *DeviceService) verifyClientCert(peerCert *x509.Certificate) error {
 
 	// Verify the client certificate against known Zone CAs.
 	// Apply clock offset (from test triggers) to simulate clock skew.
-	now := time.Now().Add(offset)
+	now := s.timeSource().Now().Add(offset)
 	opts := x509.VerifyOptions{
 		Roots:       caPool,
 		KeyUsages:   []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
@@ -598,14 +602,14 @@ func (s *DeviceService) Stop() error {
 // have exceeded the StaleConnectionTimeout. This is a safety net for connections
 // that never complete commissioning (DEC-064).
 func (s *DeviceService) runStaleConnectionReaper() {
-	ticker := time.NewTicker(s.config.ReaperInterval)
+	ticker := s.timeSource().NewTicker(s.config.ReaperInterval)
 	defer ticker.Stop()
 
 	for {
 		select {
 		case <-s.ctx.Done():
 			return
-		case <-ticker.C:
+		case <-ticker.C():
 			if closed := s.connTracker.CloseStale(s.config.StaleConnectionTimeout); closed > 0 {
 				s.debugLog("staleConnectionReaper: closed connections", "count", closed)
 			}
@@ -1,10 +1,9 @@
 package service
 
 import (
-	"time"
-
 	"github.com/mash-protocol/mash-go/pkg/cert"
 	"github.com/mash-protocol/mash-go/pkg/failsafe"
+	"github.com/mash-protocol/mash-go/pkg/features"
 	"github.com/mash-protocol/mash-go/pkg/persistence"
 )
 
@@ -30,8 +29,12 @@ func (s *DeviceService) SetStateStore(store *persistence.DeviceStateStore) {
 }
 
 // SaveState persists the current device state.
-// This should be called on graceful shutdown and after commissioning changes.
+// This should be called on graceful shutdown and after commissioning changes;
+// resolver changes save it themselves (see saveResolverState).
 func (s *DeviceService) SaveState() error {
+	s.saveMu.Lock()
+	defer s.saveMu.Unlock()
+
 	s.mu.RLock()
 	store := s.stateStore
 	if store == nil {
@@ -40,7 +43,7 @@ func (s *DeviceService) SaveState() error {
 	}
 
 	state := &persistence.DeviceState{
-		SavedAt:       time.Now(),
+		SavedAt:       s.timeSource().Now(),
 		ZoneIndexMap:  make(map[string]uint8),
 		FailsafeState: make(map[string]persistence.FailsafeSnapshot),
 	}
@@ -77,11 +80,36 @@ func (s *DeviceService) SaveState() error {
 		}
 	}
 
+	lr := s.limitResolver
+	sr := s.setpointResolver
+	pr := s.phaseCurrentResolver
+
 	s.mu.RUnlock()
 
+	// Resolvers have their own locks; snapshot them outside s.mu.
+	if lr != nil {
+		state.Limits = toPersistedZoneValues(lr.Snapshot())
+	}
+	if sr != nil {
+		state.Setpoints = toPersistedZoneValues(sr.Snapshot())
+	}
+	if pr != nil {
+		snap := pr.Snapshot()
+		state.CurrentLimits = toPersistedZoneValues(snap.Limits)
+		state.CurrentSetpoints = toPersistedZoneValues(snap.Setpoints)
+	}
+
 	return store.Save(state)
 }
 
+// saveResolverState is the resolvers' OnChange hook. It runs outside the
+// resolver locks, so SaveState can snapshot them.
+func (s *DeviceService) saveResolverState() {
+	if err := s.SaveState(); err != nil {
+		s.debugLog("saveResolverState: failed", "error", err)
+	}
+}
+
 // LoadState restores the device state from persistence.
 // This should be called during Start() if a state store is configured.
 func (s *DeviceService) LoadState() error {
@@ -153,7 +181,7 @@ func (s *DeviceService) LoadState() error {
 			continue
 		}
 
-		timer := failsafe.NewTimer()
+		timer := failsafe.NewTimerWithClock(s.timeSource())
 		timerSnap := &failsafe.TimerSnapshot{
 			State:     failsafe.State(snap.State),
 			Duration:  snap.Duration,
@@ -182,8 +210,27 @@ func (s *DeviceService) LoadState() error {
 		s.failsafeTimers[zoneID] = timer
 	}
 
+	lr := s.limitResolver
+	sr := s.setpointResolver
+	pr := s.phaseCurrentResolver
+
 	s.mu.Unlock()
 
+	// Restore limits and setpoints; the resolvers re-arm their duration
+	// timers for whatever time was left when the state was saved.
+	if lr != nil && len(state.Limits) > 0 {
+		lr.Restore(fromPersistedZoneValues(state.Limits))
+	}
+	if sr != nil && len(state.Setpoints) > 0 {
+		sr.Restore(fromPersistedZoneValues(state.Setpoints))
+	}
+	if pr != nil && (len(state.CurrentLimits) > 0 || len(state.CurrentSetpoints) > 0) {
+		pr.Restore(features.PhaseCurrentSnapshot{
+			Limits:    fromPersistedZoneValues(state.CurrentLimits),
+			Setpoints: fromPersistedZoneValues(state.CurrentSetpoints),
+		})
+	}
+
 	// Emit events for restored zones (after releasing lock)
 	for _, zoneID := range restoredZones {
 		s.emitEvent(Event{
@@ -194,3 +241,48 @@ func (s *DeviceService) LoadState() error {
 
 	return nil
 }
+
+// toPersistedZoneValues converts resolver snapshots to their JSON form.
+func toPersistedZoneValues(entries []features.ZoneValueSnapshot) []persistence.ZoneValueSnapshot {
+	if len(entries) == 0 {
+		return nil
+	}
+	out := make([]persistence.ZoneValueSnapshot, 0, len(entries))
+	for _, e := range entries {
+		pv := persistence.ZoneValueSnapshot{
+			ZoneID:    e.ZoneID,
+			ZoneType:  uint8(e.ZoneType),
+			Direction: uint8(e.Direction),
+			Value:     e.Value,
+			Cause:     e.Cause,
+			ExpiresAt: e.ExpiresAt,
+		}
+		if e.Phase != nil {
+			phase := uint8(*e.Phase)
+			pv.Phase = &phase
+		}
+		out = append(out, pv)
+	}
+	return out
+}
+
+// fromPersistedZoneValues converts JSON zone values back to resolver snapshots.
+func fromPersistedZoneValues(entries []persistence.ZoneValueSnapshot) []features.ZoneValueSnapshot {
+	out := make([]features.ZoneValueSnapshot, 0, len(entries))
+	for _, pv := range entries {
+		e := features.ZoneValueSnapshot{
+			ZoneID:    pv.ZoneID,
+			ZoneType:  cert.ZoneType(pv.ZoneType),
+			Direction: features.Direction(pv.Direction),
+			Value:     pv.Value,
+			Cause:     pv.Cause,
+			ExpiresAt: pv.ExpiresAt,
+		}
+		if pv.Phase != nil {
+			phase := features.Phase(*pv.Phase)
+			e.Phase = &phase
+		}
+		out = append(out, e)
+	}
+	return out
+}
@@ -14,6 +14,7 @@ import (
 	"time"
 
 	"github.com/mash-protocol/mash-go/pkg/cert"
+	"github.com/mash-protocol/mash-go/pkg/commissioning"
 	"github.com/mash-protocol/mash-go/pkg/discovery"
 	"github.com/mash-protocol/mash-go/pkg/failsafe"
 	"github.com/mash-protocol/mash-go/pkg/model"
@@ -40,12 +41,11 @@ func (s *DeviceService) matchZoneByPeerCert(peerCert *x509.Certificate) string {
 		if cz.Connected {
 			continue
 		}
-		zoneCACert, err := s.certStore.GetZoneCACert(zoneID)
-		if err != nil {
-			continue
-		}
-		if bytes.Equal(peerCert.AuthorityKeyId, zoneCACert.SubjectKeyId) {
-			return zoneID
+		// During Zone CA rotation the zone trusts two CAs.
+		for _, zoneCACert := range s.certStore.GetZoneCACerts(zoneID) {
+			if bytes.Equal(peerCert.AuthorityKeyId, zoneCACert.SubjectKeyId) {
+				return zoneID
+			}
 		}
 	}
 	return ""
@@ -67,17 +67,23 @@ func (s *DeviceService) matchConnectedZoneByPeerCert(peerCert *x509.Certificate)
 		if !cz.Connected {
 			continue
 		}
-		zoneCACert, err := s.certStore.GetZoneCACert(zoneID)
-		if err != nil {
-			continue
-		}
-		if bytes.Equal(peerCert.AuthorityKeyId, zoneCACert.SubjectKeyId) {
-			return zoneID
+		// During Zone CA rotation the zone trusts two CAs.
+		for _, zoneCACert := range s.certStore.GetZoneCACerts(zoneID) {
+			if bytes.Equal(peerCert.AuthorityKeyId, zoneCACert.SubjectKeyId) {
+				return zoneID
+			}
 		}
 	}
 	return ""
 }
 
+// sameController reports whether peerCert belongs to the controller the
+// session was opened for: the same name with the same role.
+func sameController(session *ZoneSession, peerCert *x509.Certificate) bool {
+	return session.PeerName() == peerCert.Subject.CommonName &&
+		session.PeerRole() == cert.CertRole(peerCert)
+}
+
 // handleOperationalConnection handles a reconnection from a known zone.
 // rawConn is the underlying net.Conn from Accept, used for connTracker removal.
 func (s *DeviceService) handleOperationalConnection(rawConn net.Conn, conn *tls.Conn, releaseActiveConn func()) {
@@ -107,8 +113,26 @@ func (s *DeviceService) handleOperationalConnection(rawConn net.Conn, conn *tls.
 		targetZoneID = s.matchConnectedZoneByPeerCert(peerCerts[0])
 		needsSessionReplace = targetZoneID != ""
 	}
+	// Only a reconnect of the same controller replaces the session. Another
+	// controller of the zone (e.g. an admin while the owner is connected)
+	// must not evict it.
+	var otherController bool
+	if needsSessionReplace {
+		if existing := s.zoneSessions[targetZoneID]; existing != nil {
+			otherController = !sameController(existing, peerCerts[0])
+		}
+	}
 	s.mu.RUnlock()
 
+	if otherController {
+		s.debugLog("handleOperationalConnection: zone already connected to another controller, rejecting",
+			"zoneID", targetZoneID,
+			"peer", peerCerts[0].Subject.CommonName,
+			"role", cert.CertRole(peerCerts[0]))
+		conn.Close()
+		return
+	}
+
 	if targetZoneID == "" {
 		// No known zones match - reject connection.
 		// Log the full zone state map for diagnostics.
@@ -136,7 +160,7 @@ func (s *DeviceService) handleOperationalConnection(rawConn net.Conn, conn *tls.
 	s.mu.Lock()
 	if cz, exists := s.connectedZones[targetZoneID]; exists {
 		cz.Connected = true
-		cz.LastSeen = time.Now()
+		cz.LastSeen = s.timeSource().Now()
 	}
 
 	// Restart failsafe timer for this zone
@@ -154,6 +178,10 @@ func (s *DeviceService) handleOperationalConnection(rawConn net.Conn, conn *tls.
 	// Create zone session for this connection
 	zoneSession := NewZoneSession(targetZoneID, framedConn, s.device)
 	zoneSession.SetLogger(s.logger)
+	if s.config.Clock != nil {
+		zoneSession.SetClock(s.timeSource())
+	}
+	zoneSession.dispatcher.SetEventBuffer(s.events)
 
 	// Set zone type from connected zone metadata
 	s.mu.RLock()
@@ -162,6 +190,12 @@ func (s *DeviceService) handleOperationalConnection(rawConn net.Conn, conn *tls.
 	}
 	s.mu.RUnlock()
 
+	// Set the controller's role (owner or admin) and name from its certificate
+	if len(peerCerts) > 0 {
+		zoneSession.SetPeerRole(cert.CertRole(peerCerts[0]))
+		zoneSession.SetPeerName(peerCerts[0].Subject.CommonName)
+	}
+
 	// Set snapshot policy and protocol logger if configured
 	zoneSession.SetSnapshotPolicy(s.config.SnapshotPolicy)
 	if s.protocolLogger != nil {
@@ -175,6 +209,9 @@ func (s *DeviceService) handleOperationalConnection(rawConn net.Conn, conn *tls.
 	// Set callback to persist certificate after renewal
 	zoneSession.SetOnCertRenewalSuccess(s.handleCertRenewalSuccess)
 
+	// Set callback to apply Zone CA rotation
+	zoneSession.SetOnZoneCAMessage(s.handleZoneCAMessage)
+
 	// Set callback to emit events when attributes are written
 	zoneSession.SetOnWrite(s.makeWriteCallback(targetZoneID))
 
@@ -374,6 +411,7 @@ func (s *DeviceService) handleCertRenewalSuccess(zoneID string, handler *DeviceR
 	var (
 		dm     *discovery.DiscoveryManager
 		port   uint16
+		addrs  []string
 		ctx    context.Context
 		update bool
 	)
@@ -385,10 +423,8 @@ func (s *DeviceService) handleCertRenewalSuccess(zoneID string, handler *DeviceR
 
 	if s.discoveryManager != nil {
 		dm = s.discoveryManager
-		port = parsePort(s.config.OperationalListenAddress)
-		if s.listener != nil {
-			port = parsePort(s.listener.Addr().String())
-		}
+		port = s.advertisedPortLocked()
+		addrs = s.advertisedAddressesLocked()
 		ctx = s.ctx
 		update = true
 	}
@@ -404,6 +440,7 @@ func (s *DeviceService) handleCertRenewalSuccess(zoneID string, handler *DeviceR
 		VendorProduct: fmt.Sprintf("%04x:%04x", s.device.VendorID(), s.device.ProductID()),
 		EndpointCount: uint8(s.device.EndpointCount()),
 		Port:          port,
+		Addresses:     addrs,
 	}
 	if err := dm.UpdateZone(opInfo); err != nil {
 		if errors.Is(err, discovery.ErrNotFound) {
@@ -418,6 +455,81 @@ func (s *DeviceService) handleCertRenewalSuccess(zoneID string, handler *DeviceR
 	}
 }
 
+// handleZoneCAMessage applies a Zone CA rotation message for a zone and
+// returns the status for the acknowledgment. A ZoneCAUpdate adds the new CA
+// to the zone's trusted CAs if it is cross-signed by one of them; a
+// ZoneCARetire drops every other CA once the device's own certificate
+// chains to the remaining one.
+func (s *DeviceService) handleZoneCAMessage(zoneID string, msg any) uint8 {
+	s.mu.RLock()
+	certStore := s.certStore
+	s.mu.RUnlock()
+
+	if certStore == nil {
+		s.debugLog("handleZoneCAMessage: no cert store")
+		return commissioning.RenewalStatusInstallFailed
+	}
+	trusted := certStore.GetZoneCACerts(zoneID)
+
+	switch m := msg.(type) {
+	case *commissioning.ZoneCAUpdate:
+		newCA, err := x509.ParseCertificate(m.ZoneCA)
+		if err != nil {
+			return commissioning.RenewalStatusInvalidCert
+		}
+		cross, err := x509.ParseCertificate(m.CrossCert)
+		if err != nil {
+			return commissioning.RenewalStatusInvalidCert
+		}
+		if err := cert.VerifyZoneCACrossSign(newCA, cross, trusted, s.timeSource().Now()); err != nil {
+			s.debugLog("handleZoneCAMessage: rejected new Zone CA", "zoneID", zoneID, "error", err)
+			if errors.Is(err, cert.ErrUntrustedZoneCA) {
+				return commissioning.RenewalStatusUntrustedCA
+			}
+			return commissioning.RenewalStatusInvalidCert
+		}
+		if err := certStore.AddZoneCACert(zoneID, newCA); err != nil {
+			return commissioning.RenewalStatusInstallFailed
+		}
+
+	case *commissioning.ZoneCARetire:
+		var keep *x509.Certificate
+		for _, ca := range trusted {
+			if bytes.Equal(ca.Raw, m.ZoneCA) {
+				keep = ca
+			}
+		}
+		if keep == nil {
+			return commissioning.RenewalStatusUntrustedCA
+		}
+		// Refuse to drop the CA our own certificate chains to.
+		opCert, err := certStore.GetOperationalCert(zoneID)
+		if err != nil || opCert.Certificate.CheckSignatureFrom(keep) != nil {
+			return commissioning.RenewalStatusInvalidCert
+		}
+		if err := certStore.SetZoneCACert(zoneID, keep); err != nil {
+			return commissioning.RenewalStatusInstallFailed
+		}
+
+	default:
+		return commissioning.RenewalStatusInstallFailed
+	}
+
+	if err := certStore.Save(); err != nil {
+		s.debugLog("handleZoneCAMessage: failed to save cert store", "error", err)
+		return commissioning.RenewalStatusInstallFailed
+	}
+
+	s.mu.Lock()
+	s.buildOperationalTLSConfig()
+	s.mu.Unlock()
+
+	s.debugLog("handleZoneCAMessage: trusted Zone CAs updated",
+		"zoneID", zoneID,
+		"count", len(certStore.GetZoneCACerts(zoneID)))
+	return commissioning.RenewalStatusSuccess
+}
+
 // makeWriteCallback creates a write callback that emits events for attribute changes.
 func (s *DeviceService) makeWriteCallback(zoneID string) dispatch.WriteCallback {
 	return func(endpointID uint8, featureID uint8, attrs map[uint16]any) {
@@ -472,6 +584,23 @@ func (f *featureChangeSubscriber) OnAttributeChanged(featureType model.FeatureTy
 	f.svc.notifyZoneSessions(f.endpointID, uint8(featureType), attrID, value)
 }
 
+// OnEventEmitted implements model.FeatureEventSubscriber. It records the
+// event in the device event buffer and pushes it to subscribed controllers.
+func (f *featureChangeSubscriber) OnEventEmitted(featureType model.FeatureType, eventID uint8, data any) {
+	ev := f.svc.events.Append(f.endpointID, featureType, eventID, data)
+
+	f.svc.emitEvent(Event{
+		Type:        EventFeatureEvent,
+		EndpointID:  f.endpointID,
+		FeatureID:   uint16(featureType),
+		EventID:     eventID,
+		EventNumber: ev.Number,
+		Value:       data,
+	})
+
+	f.svc.notifyZoneSessionsEvent(ev)
+}
+
 // subscribeToFeatureChanges registers a FeatureSubscriber on all features
 // across all endpoints so that internal attribute changes (from command handlers)
 // emit EventValueChanged events.
@@ -580,7 +709,7 @@ func (s *DeviceService) handleZoneConnectInternal(zoneID string, zoneType cert.Z
 		Type:      zoneType,
 		Priority:  zoneType.Priority(),
 		Connected: connected,
-		LastSeen:  time.Now(),
+		LastSeen:  s.timeSource().Now(),
 	}
 	s.connectedZones[zoneID] = cz
 
@@ -609,22 +738,18 @@ func (s *DeviceService) handleZoneConnectInternal(zoneID string, zoneType cert.Z
 	// Prepare operational advertising info while under lock.
 	var opInfo *discovery.OperationalInfo
 	if s.discoveryManager != nil {
-		port := uint16(0)
-		if s.listener != nil {
-			port = parsePort(s.listener.Addr().String())
-		}
-
 		opInfo = &discovery.OperationalInfo{
 			ZoneID:        zoneID,
 			DeviceID:      deviceID,
 			VendorProduct: fmt.Sprintf("%04x:%04x", s.device.VendorID(), s.device.ProductID()),
 			EndpointCount: uint8(s.device.EndpointCount()),
-			Port:          port,
+			Port:          s.advertisedPortLocked(),
+			Addresses:     s.advertisedAddressesLocked(),
 		}
 	}
 
 	// Create failsafe timer for this zone
-	timer := failsafe.NewTimer()
+	timer := failsafe.NewTimerWithClock(s.timeSource())
 	if err := timer.SetDuration(s.config.FailsafeTimeout); err == nil {
 		timer.OnFailsafeEnter(func(_ failsafe.Limits) {
 			s.handleFailsafe(zoneID)
@@ -676,7 +801,7 @@ func (s *DeviceService) HandleZoneDisconnect(zoneID string) {
 
 	if cz, exists := s.connectedZones[zoneID]; exists {
 		cz.Connected = false
-		cz.LastSeen = time.Now()
+		cz.LastSeen = s.timeSource().Now()
 	}
 
 	// The failsafe timer was already started on connect
@@ -711,7 +836,7 @@ func (s *DeviceService) HandleZoneDisconnect(zoneID string) {
 		blockedUntil := s.disconnectReentryBlockedUntil
 		s.mu.RUnlock()
 		if allDisconnected {
-			if !blockedUntil.IsZero() && time.Now().Before(blockedUntil) {
+			if !blockedUntil.IsZero() && s.timeSource().Now().Before(blockedUntil) {
 				s.debugLog("HandleZoneDisconnect: auto-reentry suppressed during post-exit holdoff",
 					"blockedUntil", blockedUntil.Format(time.RFC3339Nano))
 				s.scheduleDisconnectReentry(blockedUntil)
@@ -724,7 +849,7 @@ func (s *DeviceService) HandleZoneDisconnect(zoneID string) {
 }
 
 func (s *DeviceService) scheduleDisconnectReentry(blockedUntil time.Time) {
-	delay := time.Until(blockedUntil)
+	delay := blockedUntil.Sub(s.timeSource().Now())
 	if delay <= 0 {
 		go s.tryAutoReenterCommissioningAfterHoldoff()
 		return
@@ -736,7 +861,7 @@ func (s *DeviceService) scheduleDisconnectReentry(blockedUntil time.Time) {
 	if s.disconnectReentryTimer != nil {
 		s.disconnectReentryTimer.Stop()
 	}
-	s.disconnectReentryTimer = time.AfterFunc(delay, s.tryAutoReenterCommissioningAfterHoldoff)
+	s.disconnectReentryTimer = s.timeSource().AfterFunc(delay, s.tryAutoReenterCommissioningAfterHoldoff)
 }
 
 func (s *DeviceService) tryAutoReenterCommissioningAfterHoldoff() {
@@ -755,7 +880,7 @@ func (s *DeviceService) tryAutoReenterCommissioningAfterHoldoff() {
 	if !s.isEnableKeyValid() || !allDisconnected {
 		return
 	}
-	if !blockedUntil.IsZero() && time.Now().Before(blockedUntil) {
+	if !blockedUntil.IsZero() && s.timeSource().Now().Before(blockedUntil) {
 		s.scheduleDisconnectReentry(blockedUntil)
 		return
 	}
@@ -842,6 +967,37 @@ func (s *DeviceService) notifyZoneSessions(endpointID uint8, featureID uint8, at
 	}
 }
 
+// notifyZoneSessionsEvent delivers a feature event to all zone sessions with
+// matching event subscriptions.
+func (s *DeviceService) notifyZoneSessionsEvent(ev model.Event) {
+	s.mu.RLock()
+	sessions := make([]*ZoneSession, 0, len(s.zoneSessions))
+	for _, session := range s.zoneSessions {
+		sessions = append(sessions, session)
+	}
+	s.mu.RUnlock()
+
+	for _, session := range sessions {
+		session.dispatcher.NotifyEvent(ev)
+	}
+}
+
+// EmitEvent emits a feature event on the given endpoint. The event is recorded
+// in the device event buffer and delivered to subscribed controllers.
+func (s *DeviceService) EmitEvent(endpointID uint8, featureID uint8, eventID uint8, data any) error {
+	endpoint, err := s.device.GetEndpoint(endpointID)
+	if err != nil {
+		return err
+	}
+
+	feature, err := endpoint.GetFeatureByID(featureID)
+	if err != nil {
+		return err
+	}
+
+	return feature.EmitEvent(eventID, data)
+}
+
 // RemoveZone removes a zone from this device.
 // It closes the session, removes from connectedZones, stops the failsafe timer,
 // and stops operational mDNS advertising for this zone.
@@ -875,10 +1031,13 @@ func (s *DeviceService) RemoveZone(zoneID string) error {
 		delete(s.zoneIndexMap, zoneID)
 	}
 
-	// Capture limitResolver reference; ClearZone is called outside the lock
+	// Capture resolver references; ClearZone is called outside the lock
 	// because its OnZoneMyChange callback calls NotifyZoneAttributeChange
 	// which acquires s.mu.RLock() -- would deadlock if called under s.mu.Lock().
 	lr := s.limitResolver
+	sr := s.setpointResolver
+	pr := s.phaseCurrentResolver
+	cs := s.constraintScheduler
 
 	// Remove from connected zones
 	delete(s.connectedZones, zoneID)
@@ -894,10 +1053,19 @@ func (s *DeviceService) RemoveZone(zoneID string) error {
 	hasAvailableSlots := s.nonTestZoneCountLocked() < s.config.MaxZones
 	s.mu.Unlock()
 
-	// Clear LimitResolver state outside the lock.
+	// Clear resolver state outside the lock.
 	if lr != nil {
 		lr.ClearZone(zoneID)
 	}
+	if sr != nil {
+		sr.ClearZone(zoneID)
+	}
+	if pr != nil {
+		pr.ClearZone(zoneID)
+	}
+	if cs != nil {
+		cs.ClearZone(zoneID)
+	}
 
 	// Preserve existing RemoveZone behavior for callers that expect
 	// immediate removal signal and commissioning re-entry.
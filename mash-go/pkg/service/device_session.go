@@ -7,10 +7,13 @@ import (
 	"sync"
 	"time"
 
+	"github.com/mash-protocol/mash-go/pkg/cert"
 	"github.com/mash-protocol/mash-go/pkg/commissioning"
+	"github.com/mash-protocol/mash-go/pkg/featureclient"
 	"github.com/mash-protocol/mash-go/pkg/interaction"
 	"github.com/mash-protocol/mash-go/pkg/log"
 	"github.com/mash-protocol/mash-go/pkg/service/dispatch"
+	"github.com/mash-protocol/mash-go/pkg/transport"
 	"github.com/mash-protocol/mash-go/pkg/wire"
 )
 
@@ -48,6 +51,9 @@ type DeviceSession struct {
 	renewalHandler *ControllerRenewalHandler
 }
 
+// Verify DeviceSession implements featureclient.DeviceClient.
+var _ featureclient.DeviceClient = (*DeviceSession)(nil)
+
 // NewDeviceSession creates a new device session.
 func NewDeviceSession(deviceID string, conn Sendable) *DeviceSession {
 	sender := NewTransportRequestSender(conn)
@@ -127,6 +133,11 @@ func (s *DeviceSession) OnMessage(data []byte) {
 		client.HandleResponse(resp)
 
 	case wire.MessageTypeNotification:
+		if wire.IsEventNotification(data) {
+			s.handleEventNotification(data, client, logger, protocolLogger, connID, snapshot)
+			return
+		}
+
 		// Decode and deliver to client
 		notif, err := wire.DecodeNotification(data)
 		if err != nil {
@@ -160,6 +171,51 @@ func (s *DeviceSession) OnMessage(data []byte) {
 	}
 }
 
+// handleEventNotification decodes an event notification and delivers it to the client.
+func (s *DeviceSession) handleEventNotification(data []byte, client *interaction.Client, logger *slog.Logger, protocolLogger log.Logger, connID string, snapshot *snapshotTracker) {
+	notif, err := wire.DecodeEventNotification(data)
+	if err != nil {
+		if logger != nil {
+			logger.Debug("OnMessage: failed to decode event notification",
+				"deviceID", s.deviceID,
+				"error", err)
+		}
+		return
+	}
+
+	if protocolLogger != nil {
+		subscriptionID := notif.SubscriptionID
+		endpointID := notif.EndpointID
+		featureID := notif.FeatureID
+		protocolLogger.Log(log.Event{
+			Timestamp:    time.Now(),
+			ConnectionID: connID,
+			Direction:    log.DirectionIn,
+			Layer:        log.LayerWire,
+			Category:     log.CategoryMessage,
+			Message: &log.MessageEvent{
+				Type:           log.MessageTypeNotification,
+				SubscriptionID: &subscriptionID,
+				EndpointID:     &endpointID,
+				FeatureID:      &featureID,
+				Payload:        notif.Events,
+			},
+		})
+		if snapshot != nil {
+			snapshot.onMessageLogged()
+		}
+	}
+	if logger != nil {
+		logger.Debug("OnMessage: received event notification",
+			"deviceID", s.deviceID,
+			"subscriptionID", notif.SubscriptionID,
+			"endpointID", notif.EndpointID,
+			"featureID", notif.FeatureID,
+			"eventCount", len(notif.Events))
+	}
+	client.HandleEventNotification(notif)
+}
+
 // logResponse logs an incoming response event.
 func (s *DeviceSession) logResponse(logger log.Logger, connectionID string, resp *wire.Response) {
 	if logger == nil {
@@ -268,6 +324,20 @@ func (s *DeviceSession) Write(ctx context.Context, endpointID uint8, featureID u
 	return client.Write(ctx, endpointID, featureID, attrs)
 }
 
+// WriteIfVersion writes attributes to a feature on the device only if the
+// feature's data version still equals version.
+func (s *DeviceSession) WriteIfVersion(ctx context.Context, endpointID uint8, featureID uint8, attrs map[uint16]any, version uint32) (map[uint16]any, error) {
+	s.mu.RLock()
+	if s.closed {
+		s.mu.RUnlock()
+		return nil, ErrSessionClosed
+	}
+	client := s.client
+	s.mu.RUnlock()
+
+	return client.WriteIfVersion(ctx, endpointID, featureID, attrs, version)
+}
+
 // Subscribe subscribes to attribute changes on a feature.
 // Returns the subscription ID and initial attribute values (priming report).
 func (s *DeviceSession) Subscribe(ctx context.Context, endpointID uint8, featureID uint8, opts *interaction.SubscribeOptions) (uint32, map[uint16]any, error) {
@@ -282,6 +352,32 @@ func (s *DeviceSession) Subscribe(ctx context.Context, endpointID uint8, feature
 	return client.Subscribe(ctx, endpointID, featureID, opts)
 }
 
+// ReadPaths reads attributes from several features in one request.
+func (s *DeviceSession) ReadPaths(ctx context.Context, paths []wire.AttributePath) ([]wire.PathResult, error) {
+	s.mu.RLock()
+	if s.closed {
+		s.mu.RUnlock()
+		return nil, ErrSessionClosed
+	}
+	client := s.client
+	s.mu.RUnlock()
+
+	return client.ReadPaths(ctx, paths)
+}
+
+// SubscribePaths subscribes to several features under one subscription.
+func (s *DeviceSession) SubscribePaths(ctx context.Context, paths []wire.AttributePath, opts *interaction.SubscribeOptions) (uint32, []wire.PathResult, error) {
+	s.mu.RLock()
+	if s.closed {
+		s.mu.RUnlock()
+		return 0, nil, ErrSessionClosed
+	}
+	client := s.client
+	s.mu.RUnlock()
+
+	return client.SubscribePaths(ctx, paths, opts)
+}
+
 // Unsubscribe cancels a subscription.
 func (s *DeviceSession) Unsubscribe(ctx context.Context, subscriptionID uint32) error {
 	s.mu.RLock()
@@ -321,6 +417,47 @@ func (s *DeviceSession) SetNotificationHandler(handler func(*wire.Notification))
 	client.SetNotificationHandler(handler)
 }
 
+// SetEventHandler sets the handler for incoming event notifications.
+func (s *DeviceSession) SetEventHandler(handler func(*wire.EventNotification)) {
+	s.mu.RLock()
+	if s.closed {
+		s.mu.RUnlock()
+		return
+	}
+	client := s.client
+	s.mu.RUnlock()
+
+	client.SetEventHandler(handler)
+}
+
+// SetMaxInFlight limits the number of requests to the device awaiting a
+// response; further requests queue. Zero removes the limit.
+func (s *DeviceSession) SetMaxInFlight(n int) {
+	s.mu.RLock()
+	client := s.client
+	s.mu.RUnlock()
+
+	client.SetMaxInFlight(n)
+}
+
+// SetRetryPolicy sets how requests answered with StatusBusy are retried.
+func (s *DeviceSession) SetRetryPolicy(policy interaction.RetryPolicy) {
+	s.mu.RLock()
+	client := s.client
+	s.mu.RUnlock()
+
+	client.SetRetryPolicy(policy)
+}
+
+// RequestStats returns statistics of requests sent to the device.
+func (s *DeviceSession) RequestStats() interaction.ClientStats {
+	s.mu.RLock()
+	client := s.client
+	s.mu.RUnlock()
+
+	return client.Stats()
+}
+
 // Close closes the session and cleans up resources.
 func (s *DeviceSession) Close() error {
 	s.mu.Lock()
@@ -379,6 +516,8 @@ func (s *DeviceSession) SetExposedDevice(device dispatch.DeviceModel) {
 
 	s.handler = dispatch.NewProtocolHandler(device)
 	s.handler.SetPeerID(s.deviceID)
+	// The device is not a controller of the zone: it may read and subscribe
+	s.handler.SetPeerRole(cert.RoleMember)
 
 	// Wire up notification sender so NotifyAttributeChange can send to device
 	s.handler.SetSendNotification(s.SendNotification)
@@ -440,15 +579,24 @@ func (s *DeviceSession) handleRequest(data []byte) {
 			"status", resp.Status)
 	}
 
-	// Send response
-	respData, err := wire.EncodeResponse(resp)
+	// Send response, split into chunks if it exceeds the frame limit
+	var frames [][]byte
+	if handler != nil {
+		frames, err = handler.EncodeResponse(resp)
+	} else {
+		frames, err = wire.EncodeResponseChunks(resp, transport.DefaultMaxMessageSize)
+	}
 	if err != nil {
 		// Can't encode response - send error with simpler payload
 		s.sendErrorResponse(req.MessageID, wire.StatusBusy, "failed to encode response")
 		return
 	}
 
-	s.conn.Send(respData)
+	for _, respData := range frames {
+		if s.conn.Send(respData) != nil {
+			return
+		}
+	}
 }
 
 // sendErrorResponse sends an error response.
@@ -475,6 +623,7 @@ func (s *DeviceSession) SendNotification(notif *wire.Notification) error {
 		return ErrSessionClosed
 	}
 	logger := s.logger
+	handler := s.handler
 	s.mu.RUnlock()
 
 	if logger != nil {
@@ -486,7 +635,14 @@ func (s *DeviceSession) SendNotification(notif *wire.Notification) error {
 			"changesCount", len(notif.Changes))
 	}
 
-	data, err := wire.EncodeNotification(notif)
+	// Split the notification if it exceeds the frame limit
+	var frames [][]byte
+	var err error
+	if handler != nil {
+		frames, err = handler.EncodeNotification(notif)
+	} else {
+		frames, err = wire.EncodeNotificationFrames(notif, transport.DefaultMaxMessageSize)
+	}
 	if err != nil {
 		if logger != nil {
 			logger.Debug("SendNotification: encode failed",
@@ -496,7 +652,12 @@ func (s *DeviceSession) SendNotification(notif *wire.Notification) error {
 		return err
 	}
 
-	return s.conn.Send(data)
+	for _, data := range frames {
+		if err := s.conn.Send(data); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 // Handler returns the session's ProtocolHandler (for testing and diagnostics).
@@ -6,6 +6,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/mash-protocol/mash-go/pkg/cert"
 	"github.com/mash-protocol/mash-go/pkg/interaction"
 	"github.com/mash-protocol/mash-go/pkg/log"
 	"github.com/mash-protocol/mash-go/pkg/model"
@@ -46,9 +47,9 @@ type mockResponseConnection struct {
 }
 
 func newMockResponseConnection(device *model.Device) *mockResponseConnection {
-	return &mockResponseConnection{
-		handler: dispatch.NewProtocolHandler(device),
-	}
+	handler := dispatch.NewProtocolHandler(device)
+	handler.SetPeerRole(cert.RoleOwner)
+	return &mockResponseConnection{handler: handler}
 }
 
 func (m *mockResponseConnection) Send(data []byte) error {
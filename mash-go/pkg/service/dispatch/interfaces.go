@@ -13,6 +13,9 @@ import (
 type SubscriptionTracker interface {
 	Subscribe(endpointID, featureID uint16, attributeIDs []uint16,
 		minInterval, maxInterval time.Duration, currentValues map[uint16]any) (uint32, error)
+	SubscribeEvents(endpointID, featureID uint16, eventIDs []uint8) (uint32, error)
+	SetEventIDs(subscriptionID uint32, eventIDs []uint8) error
+	MatchEvent(endpointID, featureID uint16, eventID uint8) []uint32
 	Unsubscribe(subscriptionID uint32) error
 	NotifyChange(endpointID, featureID, attrID uint16, value any)
 	NotifyChanges(endpointID, featureID uint16, changes map[uint16]any)
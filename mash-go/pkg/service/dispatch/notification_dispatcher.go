@@ -6,7 +6,9 @@ import (
 	"sync/atomic"
 	"time"
 
+	"github.com/mash-protocol/mash-go/pkg/clock"
 	"github.com/mash-protocol/mash-go/pkg/log"
+	"github.com/mash-protocol/mash-go/pkg/model"
 	"github.com/mash-protocol/mash-go/pkg/subscription"
 	"github.com/mash-protocol/mash-go/pkg/wire"
 )
@@ -23,17 +25,26 @@ type NotificationDispatcher struct {
 	handler *ProtocolHandler
 	manager SubscriptionTracker
 
+	// Device event buffer used to replay events on subscribe (optional)
+	events *model.EventBuffer
+
 	// Connection tracking
 	connections     map[uint64]*connectionInfo
 	nextConnID      uint64
 	subscriptionMap map[uint32]uint64 // subscriptionID -> connectionID
 
+	// Multi-path subscriptions: one manager subscription per path, reported
+	// under the ID of the first member.
+	groups      map[uint32][]uint32 // group subscriptionID -> member IDs
+	memberGroup map[uint32]uint32   // member subscriptionID -> group ID
+
 	// Background processing
 	ctx       context.Context
 	cancel    context.CancelFunc
 	processWg sync.WaitGroup
 	running   atomic.Bool
 	interval  time.Duration
+	clock     clock.Clock
 
 	// Protocol logging (optional)
 	logger log.Logger
@@ -54,7 +65,10 @@ func NewNotificationDispatcher(handler *ProtocolHandler) *NotificationDispatcher
 		manager:         subscription.NewManager(),
 		connections:     make(map[uint64]*connectionInfo),
 		subscriptionMap: make(map[uint32]uint64),
+		groups:          make(map[uint32][]uint32),
+		memberGroup:     make(map[uint32]uint32),
 		interval:        100 * time.Millisecond, // Default processing interval
+		clock:           clock.Real(),
 	}
 
 	// Set up notification callback
@@ -69,6 +83,35 @@ func (d *NotificationDispatcher) SetProcessingInterval(interval time.Duration) {
 	d.interval = interval
 }
 
+// SetClock sets the time source for the processing loop and subscription
+// heartbeats. A running dispatcher is restarted on the new clock. Must be
+// called before any subscription is created.
+func (d *NotificationDispatcher) SetClock(c clock.Clock) {
+	wasRunning := d.running.Load()
+	d.Stop()
+	defer func() {
+		if wasRunning {
+			d.Start()
+		}
+	}()
+
+	d.clock = clock.OrReal(c)
+
+	cfg := subscription.DefaultConfig()
+	cfg.Clock = d.clock
+	manager := subscription.NewManagerWithConfig(cfg)
+	manager.OnNotification(d.handleNotification)
+	d.manager = manager
+}
+
+// SetEventBuffer sets the device event buffer. Subscriptions that request
+// events can replay buffered events from a given event number.
+func (d *NotificationDispatcher) SetEventBuffer(events *model.EventBuffer) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.events = events
+}
+
 // SetLogger sets the protocol logger and connection ID.
 // Events logged will include the connectionID for correlation.
 func (d *NotificationDispatcher) SetLogger(logger log.Logger, connectionID string) {
@@ -137,6 +180,8 @@ func (d *NotificationDispatcher) UnregisterConnection(connID uint64) {
 	// Remove subscription mappings
 	for _, subID := range subIDs {
 		delete(d.subscriptionMap, subID)
+		delete(d.groups, subID)
+		delete(d.memberGroup, subID)
 	}
 
 	d.mu.Unlock()
@@ -163,6 +208,10 @@ func (d *NotificationDispatcher) HandleSubscribe(connID uint64, req *wire.Reques
 	}
 	d.mu.Unlock()
 
+	if req.IsMultiPath() {
+		return d.handleSubscribePaths(connID, req)
+	}
+
 	// Get the endpoint and feature for reading current values
 	endpoint, err := d.handler.Device().GetEndpoint(req.EndpointID)
 	if err != nil {
@@ -189,6 +238,25 @@ func (d *NotificationDispatcher) HandleSubscribe(connID uint64, req *wire.Reques
 	// Parse subscribe payload (handles both typed and CBOR-decoded raw maps)
 	subPayload := wire.ExtractSubscribePayload(req.Payload)
 
+	// Validate requested events against the feature's event registry
+	if subPayload != nil {
+		for _, eventID := range subPayload.EventIDs {
+			if _, err := feature.GetEvent(eventID); err != nil {
+				return &wire.Response{
+					MessageID: req.MessageID,
+					Status:    wire.StatusInvalidParameter,
+					Payload: &wire.ErrorPayload{
+						Message: "event not found",
+					},
+				}
+			}
+		}
+	}
+
+	if subPayload != nil && subPayload.IsEventOnly() {
+		return d.handleEventSubscribe(connID, req, subPayload)
+	}
+
 	// Extract intervals (default to sensible values)
 	minInterval := time.Duration(1000) * time.Millisecond
 	maxInterval := time.Duration(60000) * time.Millisecond
@@ -205,21 +273,7 @@ func (d *NotificationDispatcher) HandleSubscribe(connID uint64, req *wire.Reques
 	}
 
 	// Read current values for priming
-	// TODO(task-4): Use ReadAllAttributesWithContext / ReadAttributeWithContext here once
-	// the dispatcher tracks per-connection zone identity. Currently the dispatcher
-	// only knows connections by numeric ID and does not have access to the peer's
-	// zone ID or zone type needed to build the context.
-	var currentValues map[uint16]any
-	if len(attributeIDs) == 0 {
-		currentValues = feature.ReadAllAttributes()
-	} else {
-		currentValues = make(map[uint16]any)
-		for _, attrID := range attributeIDs {
-			if value, err := feature.ReadAttribute(attrID); err == nil {
-				currentValues[attrID] = value
-			}
-		}
-	}
+	currentValues, _ := readPath(d.handler.callerContext(), feature, attributeIDs, true)
 
 	// Create subscription in manager
 	subID, subErr := d.manager.Subscribe(
@@ -240,13 +294,13 @@ func (d *NotificationDispatcher) HandleSubscribe(connID uint64, req *wire.Reques
 		}
 	}
 
-	// Track subscription -> connection mapping
-	d.mu.Lock()
-	d.subscriptionMap[subID] = connID
-	if conn, ok := d.connections[connID]; ok {
-		conn.subscriptionIDs = append(conn.subscriptionIDs, subID)
+	var replay []wire.Event
+	if subPayload != nil && len(subPayload.EventIDs) > 0 {
+		_ = d.manager.SetEventIDs(subID, subPayload.EventIDs)
+		replay = d.replayEvents(req.EndpointID, req.FeatureID, subPayload)
 	}
-	d.mu.Unlock()
+
+	d.trackSubscription(connID, subID)
 
 	return &wire.Response{
 		MessageID: req.MessageID,
@@ -254,10 +308,138 @@ func (d *NotificationDispatcher) HandleSubscribe(connID uint64, req *wire.Reques
 		Payload: &wire.SubscribeResponsePayload{
 			SubscriptionID: subID,
 			CurrentValues:  currentValues,
+			Events:         replay,
 		},
 	}
 }
 
+// handleEventSubscribe creates an event-only subscription. No priming
+// report is sent; buffered events from EventMin are returned instead.
+func (d *NotificationDispatcher) handleEventSubscribe(connID uint64, req *wire.Request, subPayload *wire.SubscribePayload) *wire.Response {
+	subID, err := d.manager.SubscribeEvents(uint16(req.EndpointID), uint16(req.FeatureID), subPayload.EventIDs)
+	if err != nil {
+		return &wire.Response{
+			MessageID: req.MessageID,
+			Status:    wire.StatusResourceExhausted,
+			Payload: &wire.ErrorPayload{
+				Message: err.Error(),
+			},
+		}
+	}
+
+	d.trackSubscription(connID, subID)
+
+	return &wire.Response{
+		MessageID: req.MessageID,
+		Status:    wire.StatusSuccess,
+		Payload: &wire.SubscribeResponsePayload{
+			SubscriptionID: subID,
+			Events:         d.replayEvents(req.EndpointID, req.FeatureID, subPayload),
+		},
+	}
+}
+
+// handleSubscribePaths creates a multi-path subscription. Each valid path
+// gets its own manager subscription for coalescing; all of them notify
+// under the ID of the first, which is returned to the client together with
+// the combined priming report. Only the first sends heartbeats.
+func (d *NotificationDispatcher) handleSubscribePaths(connID uint64, req *wire.Request) *wire.Response {
+	mp, resp := extractPaths(req)
+	if resp != nil {
+		return resp
+	}
+
+	minInterval := time.Duration(1000) * time.Millisecond
+	maxInterval := time.Duration(60000) * time.Millisecond
+	if mp.MinInterval > 0 {
+		minInterval = time.Duration(mp.MinInterval) * time.Millisecond
+	}
+	if mp.MaxInterval > 0 {
+		maxInterval = time.Duration(mp.MaxInterval) * time.Millisecond
+	}
+
+	results, valid := d.handler.primePaths(mp.Paths)
+	var members []uint32
+	for _, i := range valid {
+		path := mp.Paths[i]
+		subID, err := d.manager.Subscribe(
+			uint16(path.EndpointID),
+			uint16(path.FeatureID),
+			path.AttributeIDs,
+			minInterval,
+			maxInterval,
+			results[i].Values,
+		)
+		if err != nil {
+			results[i].Status = wire.StatusResourceExhausted
+			results[i].Values = nil
+			continue
+		}
+		members = append(members, subID)
+	}
+
+	var groupID uint32
+	if len(members) > 0 {
+		groupID = members[0]
+		d.mu.Lock()
+		d.groups[groupID] = members
+		for _, subID := range members {
+			d.memberGroup[subID] = groupID
+		}
+		d.mu.Unlock()
+		for _, subID := range members {
+			d.trackSubscription(connID, subID)
+		}
+	}
+
+	return &wire.Response{
+		MessageID: req.MessageID,
+		Status:    wire.StatusSuccess,
+		Payload: &wire.MultiPathResponsePayload{
+			SubscriptionID: groupID,
+			Results:        results,
+		},
+	}
+}
+
+// trackSubscription records the subscription -> connection mapping.
+func (d *NotificationDispatcher) trackSubscription(connID uint64, subID uint32) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.subscriptionMap[subID] = connID
+	if conn, ok := d.connections[connID]; ok {
+		conn.subscriptionIDs = append(conn.subscriptionIDs, subID)
+	}
+}
+
+// replayEvents returns buffered events for the subscribed feature and event
+// IDs with a number >= EventMin. Returns nil if EventMin is 0 or no event
+// buffer is configured.
+func (d *NotificationDispatcher) replayEvents(endpointID, featureID uint8, subPayload *wire.SubscribePayload) []wire.Event {
+	d.mu.RLock()
+	events := d.events
+	d.mu.RUnlock()
+
+	if events == nil || subPayload.EventMin == 0 {
+		return nil
+	}
+
+	var replay []wire.Event
+	for _, ev := range events.Since(subPayload.EventMin) {
+		if ev.EndpointID != endpointID || uint8(ev.FeatureType) != featureID {
+			continue
+		}
+		for _, id := range subPayload.EventIDs {
+			if id == ev.EventID {
+				replay = append(replay, toWireEvent(ev))
+				break
+			}
+		}
+	}
+	return replay
+}
+
 // HandleUnsubscribe processes an unsubscribe request for a connection.
 func (d *NotificationDispatcher) HandleUnsubscribe(connID uint64, req *wire.Request) *wire.Response {
 	// Parse unsubscribe payload. After CBOR roundtrip, the payload may be
@@ -298,26 +480,38 @@ func (d *NotificationDispatcher) HandleUnsubscribe(connID uint64, req *wire.Requ
 		}
 	}
 
+	// A multi-path subscription is removed with all its members
+	members, isGroup := d.groups[subID]
+	if !isGroup {
+		members = []uint32{subID}
+	}
+	delete(d.groups, subID)
+
 	// Remove from tracking
-	delete(d.subscriptionMap, subID)
-	if conn, ok := d.connections[connID]; ok {
-		for i, id := range conn.subscriptionIDs {
-			if id == subID {
-				conn.subscriptionIDs = append(conn.subscriptionIDs[:i], conn.subscriptionIDs[i+1:]...)
-				break
+	for _, memberID := range members {
+		delete(d.subscriptionMap, memberID)
+		delete(d.memberGroup, memberID)
+		if conn, ok := d.connections[connID]; ok {
+			for i, id := range conn.subscriptionIDs {
+				if id == memberID {
+					conn.subscriptionIDs = append(conn.subscriptionIDs[:i], conn.subscriptionIDs[i+1:]...)
+					break
+				}
 			}
 		}
 	}
 	d.mu.Unlock()
 
 	// Unsubscribe from manager
-	if err := d.manager.Unsubscribe(subID); err != nil {
-		return &wire.Response{
-			MessageID: req.MessageID,
-			Status:    wire.StatusInvalidParameter,
-			Payload: &wire.ErrorPayload{
-				Message: err.Error(),
-			},
+	for _, memberID := range members {
+		if err := d.manager.Unsubscribe(memberID); err != nil {
+			return &wire.Response{
+				MessageID: req.MessageID,
+				Status:    wire.StatusInvalidParameter,
+				Payload: &wire.ErrorPayload{
+					Message: err.Error(),
+				},
+			}
 		}
 	}
 
@@ -337,6 +531,49 @@ func (d *NotificationDispatcher) NotifyChanges(endpointID uint8, featureID uint1
 	d.manager.NotifyChanges(uint16(endpointID), featureID, changes)
 }
 
+// NotifyEvent delivers an event to all subscriptions that include it.
+// Events are sent immediately and never coalesced.
+func (d *NotificationDispatcher) NotifyEvent(ev model.Event) {
+	subIDs := d.manager.MatchEvent(uint16(ev.EndpointID), uint16(ev.FeatureType), ev.EventID)
+	if len(subIDs) == 0 {
+		return
+	}
+
+	wireEvent := toWireEvent(ev)
+	for _, subID := range subIDs {
+		d.mu.RLock()
+		mappedConnID, exists := d.subscriptionMap[subID]
+		conn, ok := d.connections[mappedConnID]
+		d.mu.RUnlock()
+		if !exists || !ok {
+			continue
+		}
+
+		data, err := wire.EncodeEventNotification(&wire.EventNotification{
+			SubscriptionID: subID,
+			EndpointID:     ev.EndpointID,
+			FeatureID:      uint8(ev.FeatureType),
+			Events:         []wire.Event{wireEvent},
+		})
+		if err != nil {
+			continue
+		}
+		conn.sender(data)
+	}
+}
+
+// toWireEvent converts a buffered model event to its wire representation.
+func toWireEvent(ev model.Event) wire.Event {
+	return wire.Event{
+		EventNumber: ev.Number,
+		Timestamp:   uint64(ev.Timestamp.UnixMilli()),
+		EndpointID:  ev.EndpointID,
+		FeatureID:   uint8(ev.FeatureType),
+		EventID:     ev.EventID,
+		Data:        ev.Data,
+	}
+}
+
 // SubscriptionCount returns the total number of active subscriptions.
 func (d *NotificationDispatcher) SubscriptionCount() int {
 	return d.manager.Count()
@@ -357,6 +594,16 @@ func (d *NotificationDispatcher) handleNotification(notif subscription.Notificat
 		return
 	}
 
+	subID := notif.SubscriptionID
+	if groupID, ok := d.memberGroup[subID]; ok {
+		// One heartbeat per group, from its first member
+		if notif.IsHeartbeat && groupID != subID {
+			d.mu.RUnlock()
+			return
+		}
+		subID = groupID
+	}
+
 	sender := conn.sender
 	logger := d.logger
 	logConnID := d.connID
@@ -364,7 +611,7 @@ func (d *NotificationDispatcher) handleNotification(notif subscription.Notificat
 
 	// Convert to wire notification
 	wireNotif := &wire.Notification{
-		SubscriptionID: notif.SubscriptionID,
+		SubscriptionID: subID,
 		EndpointID:     uint8(notif.EndpointID),
 		FeatureID:      uint8(notif.FeatureID),
 		Changes:        notif.Attributes,
@@ -373,13 +620,17 @@ func (d *NotificationDispatcher) handleNotification(notif subscription.Notificat
 	// Log the outgoing notification
 	d.logNotification(logger, logConnID, wireNotif)
 
-	// Encode and send
-	data, err := wire.EncodeNotification(wireNotif)
+	// Encode and send, split if it exceeds the frame limit
+	frames, err := d.handler.EncodeNotification(wireNotif)
 	if err != nil {
 		return // Log error in production
 	}
 
-	sender(data)
+	for _, data := range frames {
+		if sender(data) != nil {
+			return
+		}
+	}
 }
 
 // logNotification logs an outgoing notification event.
@@ -412,14 +663,14 @@ func (d *NotificationDispatcher) logNotification(logger log.Logger, connectionID
 func (d *NotificationDispatcher) processLoop() {
 	defer d.processWg.Done()
 
-	ticker := time.NewTicker(d.interval)
+	ticker := d.clock.NewTicker(d.interval)
 	defer ticker.Stop()
 
 	for {
 		select {
 		case <-d.ctx.Done():
 			return
-		case <-ticker.C:
+		case <-ticker.C():
 			d.manager.ProcessNotifications()
 		}
 	}
@@ -5,6 +5,8 @@ import (
 	"testing"
 	"time"
 
+	"github.com/mash-protocol/mash-go/pkg/clock"
+	"github.com/mash-protocol/mash-go/pkg/features"
 	"github.com/mash-protocol/mash-go/pkg/log"
 	"github.com/mash-protocol/mash-go/pkg/model"
 	"github.com/mash-protocol/mash-go/pkg/service/dispatch"
@@ -566,3 +568,250 @@ func TestNotificationDispatcher_NoLoggerNoPanic(t *testing.T) {
 		t.Fatal("Timeout waiting for notification")
 	}
 }
+
+// createEventTestDevice returns the test device with a Status feature on
+// endpoint 1, which defines the faultRaised and faultCleared events.
+func createEventTestDevice(t *testing.T) *model.Device {
+	t.Helper()
+	device := createTestDevice()
+	evse, err := device.GetEndpoint(1)
+	if err != nil {
+		t.Fatalf("GetEndpoint: %v", err)
+	}
+	evse.AddFeature(features.NewStatus().Feature)
+	return device
+}
+
+// TestNotificationDispatcher_EventSubscription tests event-only subscriptions:
+// no priming report, replay from eventMin, and immediate event delivery.
+func TestNotificationDispatcher_EventSubscription(t *testing.T) {
+	device := createEventTestDevice(t)
+	handler := dispatch.NewProtocolHandler(device)
+	dispatcher := dispatch.NewNotificationDispatcher(handler)
+	defer dispatcher.Stop()
+
+	events := model.NewEventBuffer(8)
+	dispatcher.SetEventBuffer(events)
+
+	// Two events happened before the controller subscribed
+	events.Append(1, model.FeatureStatus, features.StatusEventFaultRaised, nil)
+	events.Append(1, model.FeatureStatus, features.StatusEventFaultCleared, nil)
+
+	var sent [][]byte
+	var mu sync.Mutex
+	connID := dispatcher.RegisterConnection(func(data []byte) error {
+		mu.Lock()
+		sent = append(sent, data)
+		mu.Unlock()
+		return nil
+	})
+	defer dispatcher.UnregisterConnection(connID)
+
+	resp := dispatcher.HandleSubscribe(connID, &wire.Request{
+		MessageID:  1,
+		Operation:  wire.OpSubscribe,
+		EndpointID: 1,
+		FeatureID:  uint8(model.FeatureStatus),
+		Payload: &wire.SubscribePayload{
+			EventIDs: wire.EventIDList{features.StatusEventFaultRaised},
+			EventMin: 1,
+		},
+	})
+	if !resp.IsSuccess() {
+		t.Fatalf("Subscribe failed: status %d", resp.Status)
+	}
+
+	payload := resp.Payload.(*wire.SubscribeResponsePayload)
+	if len(payload.CurrentValues) != 0 {
+		t.Errorf("Expected no priming report for event-only subscription, got %v", payload.CurrentValues)
+	}
+	if len(payload.Events) != 1 || payload.Events[0].EventNumber != 1 {
+		t.Fatalf("Expected replay of event 1 only, got %+v", payload.Events)
+	}
+
+	// Unsubscribed event IDs are not delivered
+	dispatcher.NotifyEvent(events.Append(1, model.FeatureStatus, features.StatusEventFaultCleared, nil))
+	// Subscribed event IDs are delivered immediately (no processing loop running)
+	dispatcher.NotifyEvent(events.Append(1, model.FeatureStatus, features.StatusEventFaultRaised,
+		map[string]any{"faultCode": uint32(7)}))
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(sent) != 1 {
+		t.Fatalf("Expected 1 event notification, got %d", len(sent))
+	}
+	notif, err := wire.DecodeEventNotification(sent[0])
+	if err != nil {
+		t.Fatalf("DecodeEventNotification: %v", err)
+	}
+	if notif.SubscriptionID != payload.SubscriptionID {
+		t.Errorf("Expected subscription %d, got %d", payload.SubscriptionID, notif.SubscriptionID)
+	}
+	if len(notif.Events) != 1 || notif.Events[0].EventNumber != 4 {
+		t.Errorf("Expected event 4, got %+v", notif.Events)
+	}
+}
+
+// TestNotificationDispatcher_UnknownEventRejected tests that subscribing to
+// an event the feature does not define is rejected.
+func TestNotificationDispatcher_UnknownEventRejected(t *testing.T) {
+	device := createEventTestDevice(t)
+	handler := dispatch.NewProtocolHandler(device)
+	dispatcher := dispatch.NewNotificationDispatcher(handler)
+	defer dispatcher.Stop()
+
+	connID := dispatcher.RegisterConnection(func([]byte) error { return nil })
+	defer dispatcher.UnregisterConnection(connID)
+
+	resp := dispatcher.HandleSubscribe(connID, &wire.Request{
+		MessageID:  1,
+		Operation:  wire.OpSubscribe,
+		EndpointID: 1,
+		FeatureID:  uint8(model.FeatureStatus),
+		Payload:    &wire.SubscribePayload{EventIDs: wire.EventIDList{99}},
+	})
+	if resp.Status != wire.StatusInvalidParameter {
+		t.Errorf("Expected StatusInvalidParameter, got %d", resp.Status)
+	}
+}
+
+// TestNotificationDispatcher_SubscribePaths tests that a multi-path
+// subscription notifies under one ID and unsubscribes as a whole.
+func TestNotificationDispatcher_SubscribePaths(t *testing.T) {
+	device := createTestDevice()
+	handler := dispatch.NewProtocolHandler(device)
+	dispatcher := dispatch.NewNotificationDispatcher(handler)
+	dispatcher.SetProcessingInterval(10 * time.Millisecond)
+	defer dispatcher.Stop()
+
+	notifyCh := make(chan *wire.Notification, 10)
+	connID := dispatcher.RegisterConnection(func(data []byte) error {
+		if notif, err := wire.DecodeNotification(data); err == nil {
+			notifyCh <- notif
+		}
+		return nil
+	})
+	defer dispatcher.UnregisterConnection(connID)
+
+	req := &wire.Request{
+		MessageID:  1,
+		Operation:  wire.OpSubscribe,
+		EndpointID: wire.MultiPathEndpointID,
+		FeatureID:  wire.MultiPathFeatureID,
+		Payload: &wire.MultiPathPayload{
+			Paths: []wire.AttributePath{
+				{EndpointID: 0, FeatureID: uint8(model.FeatureDeviceInfo), AttributeIDs: []uint16{1}},
+				{EndpointID: 1, FeatureID: uint8(model.FeatureElectrical)},
+				{EndpointID: 1, FeatureID: uint8(model.FeatureEnergyControl)},
+			},
+			MinInterval: 10,
+			MaxInterval: 60000,
+		},
+	}
+
+	resp := dispatcher.HandleSubscribe(connID, req)
+	if !resp.IsSuccess() {
+		t.Fatalf("Subscribe failed: status %d", resp.Status)
+	}
+	payload := resp.Payload.(*wire.MultiPathResponsePayload)
+	if payload.SubscriptionID == 0 {
+		t.Fatal("Expected a subscription ID")
+	}
+	if payload.Results[0].Values[1] != "test-device-001" {
+		t.Errorf("Expected DeviceID in priming report, got %v", payload.Results[0].Values)
+	}
+	if payload.Results[2].Status != wire.StatusInvalidFeature {
+		t.Errorf("Expected INVALID_FEATURE, got %v", payload.Results[2].Status)
+	}
+	if dispatcher.SubscriptionCount() != 2 {
+		t.Errorf("Expected 2 member subscriptions, got %d", dispatcher.SubscriptionCount())
+	}
+
+	dispatcher.Start()
+	dispatcher.NotifyChange(1, uint16(model.FeatureElectrical), 1, uint8(1))
+
+	select {
+	case notif := <-notifyCh:
+		if notif.SubscriptionID != payload.SubscriptionID {
+			t.Errorf("Expected subscription ID %d, got %d", payload.SubscriptionID, notif.SubscriptionID)
+		}
+		if notif.EndpointID != 1 || notif.FeatureID != uint8(model.FeatureElectrical) {
+			t.Errorf("Expected Electrical on endpoint 1, got %d/%d", notif.EndpointID, notif.FeatureID)
+		}
+	case <-time.After(500 * time.Millisecond):
+		t.Fatal("Timeout waiting for notification")
+	}
+
+	unsubResp := dispatcher.HandleUnsubscribe(connID, &wire.Request{
+		MessageID: 2,
+		Operation: wire.OpSubscribe,
+		Payload:   &wire.UnsubscribePayload{SubscriptionID: payload.SubscriptionID},
+	})
+	if !unsubResp.IsSuccess() {
+		t.Fatalf("Unsubscribe failed: status %d", unsubResp.Status)
+	}
+	if dispatcher.SubscriptionCount() != 0 {
+		t.Errorf("Expected 0 subscriptions after unsubscribe, got %d", dispatcher.SubscriptionCount())
+	}
+}
+
+// TestNotificationDispatcher_SubscribePathsHeartbeat tests that a
+// multi-path subscription sends one heartbeat per interval, not one per
+// path.
+func TestNotificationDispatcher_SubscribePathsHeartbeat(t *testing.T) {
+	device := createTestDevice()
+	handler := dispatch.NewProtocolHandler(device)
+	dispatcher := dispatch.NewNotificationDispatcher(handler)
+	fake := clock.NewFake(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+	dispatcher.SetClock(fake)
+	dispatcher.SetProcessingInterval(10 * time.Millisecond)
+	defer dispatcher.Stop()
+
+	var mu sync.Mutex
+	var heartbeats []uint32
+	connID := dispatcher.RegisterConnection(func(data []byte) error {
+		if notif, err := wire.DecodeNotification(data); err == nil {
+			mu.Lock()
+			heartbeats = append(heartbeats, notif.SubscriptionID)
+			mu.Unlock()
+		}
+		return nil
+	})
+	defer dispatcher.UnregisterConnection(connID)
+
+	resp := dispatcher.HandleSubscribe(connID, &wire.Request{
+		MessageID:  1,
+		Operation:  wire.OpSubscribe,
+		EndpointID: wire.MultiPathEndpointID,
+		FeatureID:  wire.MultiPathFeatureID,
+		Payload: &wire.MultiPathPayload{
+			Paths: []wire.AttributePath{
+				{EndpointID: 0, FeatureID: uint8(model.FeatureDeviceInfo)},
+				{EndpointID: 1, FeatureID: uint8(model.FeatureElectrical)},
+			},
+			MinInterval: 10,
+			MaxInterval: 1000,
+		},
+	})
+	if !resp.IsSuccess() {
+		t.Fatalf("Subscribe failed: status %d", resp.Status)
+	}
+	groupID := resp.Payload.(*wire.MultiPathResponsePayload).SubscriptionID
+
+	// Run 1.5 heartbeat intervals
+	dispatcher.Start()
+	for i := 0; i < 150; i++ {
+		fake.Advance(10 * time.Millisecond)
+		time.Sleep(time.Millisecond)
+	}
+	time.Sleep(50 * time.Millisecond)
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(heartbeats) != 1 {
+		t.Fatalf("Expected 1 heartbeat, got %d", len(heartbeats))
+	}
+	if heartbeats[0] != groupID {
+		t.Errorf("Expected heartbeat for subscription %d, got %d", groupID, heartbeats[0])
+	}
+}
@@ -10,7 +10,10 @@ import (
 	"github.com/mash-protocol/mash-go/pkg/cert"
 	"github.com/mash-protocol/mash-go/pkg/log"
 	"github.com/mash-protocol/mash-go/pkg/model"
+	"github.com/mash-protocol/mash-go/pkg/transport"
+	"github.com/mash-protocol/mash-go/pkg/version"
 	"github.com/mash-protocol/mash-go/pkg/wire"
+	"github.com/mash-protocol/mash-go/pkg/zone"
 	"github.com/mash-protocol/mash-go/pkg/zonecontext"
 )
 
@@ -35,6 +38,7 @@ type ProtocolHandler struct {
 	device       DeviceModel
 	peerID       string        // The remote peer's identifier (generic, works for both device and controller)
 	peerZoneType cert.ZoneType // The remote peer's zone type (GRID, LOCAL, etc.)
+	peerRole     cert.Role     // The remote peer's zone role (0 = unknown, denied everything)
 
 	// Subscription management
 	subscriptions *SessionSubscriptionTracker
@@ -52,13 +56,21 @@ type ProtocolHandler struct {
 	logger          log.Logger
 	connID          string
 	onMessageLogged func()
+
+	// Frame size above which responses are chunked
+	maxMessageSize int
+
+	// Schema validator for Write and Invoke payloads (nil = no validation)
+	validator *version.Validator
 }
 
 // NewProtocolHandler creates a new protocol handler for a device.
 func NewProtocolHandler(device DeviceModel) *ProtocolHandler {
 	return &ProtocolHandler{
-		device:        device,
-		subscriptions: NewSessionSubscriptionTracker(),
+		device:         device,
+		subscriptions:  NewSessionSubscriptionTracker(),
+		maxMessageSize: transport.DefaultMaxMessageSize,
+		validator:      version.CurrentValidator(),
 	}
 }
 
@@ -68,6 +80,8 @@ func NewProtocolHandlerWithSend(device DeviceModel, send NotificationSender) *Pr
 		device:           device,
 		subscriptions:    NewSessionSubscriptionTracker(),
 		sendNotification: send,
+		maxMessageSize:   transport.DefaultMaxMessageSize,
+		validator:        version.CurrentValidator(),
 	}
 }
 
@@ -142,6 +156,21 @@ func (h *ProtocolHandler) SetPeerZoneType(zt cert.ZoneType) {
 	h.peerZoneType = zt
 }
 
+// PeerRole returns the remote peer's zone role.
+func (h *ProtocolHandler) PeerRole() cert.Role {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return h.peerRole
+}
+
+// SetPeerRole sets the remote peer's zone role. Requests the role does not
+// allow are rejected with StatusNotAuthorized.
+func (h *ProtocolHandler) SetPeerRole(role cert.Role) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.peerRole = role
+}
+
 // SetLogger sets the protocol logger and connection ID.
 // Events logged will include the connectionID for correlation.
 func (h *ProtocolHandler) SetLogger(logger log.Logger, connectionID string) {
@@ -158,6 +187,44 @@ func (h *ProtocolHandler) SetOnMessageLogged(fn func()) {
 	h.onMessageLogged = fn
 }
 
+// SetMaxMessageSize sets the frame size above which EncodeResponse splits
+// responses into chunks and EncodeNotification splits notifications. It
+// defaults to the transport frame limit; zero
+// disables chunking.
+func (h *ProtocolHandler) SetMaxMessageSize(size int) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.maxMessageSize = size
+}
+
+// SetValidator sets the validator that checks Write and Invoke payloads
+// before they reach features. It defaults to the validator for the current
+// spec version; nil disables validation.
+func (h *ProtocolHandler) SetValidator(v *version.Validator) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.validator = v
+}
+
+// EncodeResponse encodes a response into the frames to send, splitting it
+// into chunks if it exceeds the maximum message size.
+func (h *ProtocolHandler) EncodeResponse(resp *wire.Response) ([][]byte, error) {
+	h.mu.RLock()
+	maxSize := h.maxMessageSize
+	h.mu.RUnlock()
+	return wire.EncodeResponseChunks(resp, maxSize)
+}
+
+// EncodeNotification encodes a notification into the frames to send,
+// splitting its changes over several notifications if it exceeds the
+// maximum message size.
+func (h *ProtocolHandler) EncodeNotification(notif *wire.Notification) ([][]byte, error) {
+	h.mu.RLock()
+	maxSize := h.maxMessageSize
+	h.mu.RUnlock()
+	return wire.EncodeNotificationFrames(notif, maxSize)
+}
+
 // HandleRequest processes a protocol request and returns a response.
 func (h *ProtocolHandler) HandleRequest(req *wire.Request) *wire.Response {
 	startTime := time.Now()
@@ -176,11 +243,23 @@ func (h *ProtocolHandler) HandleRequest(req *wire.Request) *wire.Response {
 				Message: "unsupported operation",
 			},
 		}
+	} else if role := h.PeerRole(); !zone.RoleAllows(role, operationAction(req.Operation)) {
+		resp = &wire.Response{
+			MessageID: req.MessageID,
+			Status:    wire.StatusNotAuthorized,
+			Payload: &wire.ErrorPayload{
+				Message: fmt.Sprintf("%s role may not %s", role, req.Operation),
+			},
+		}
 	} else {
 		// Route to appropriate handler
 		switch req.Operation {
 		case wire.OpRead:
-			resp = h.handleRead(req)
+			if req.IsMultiPath() {
+				resp = h.handleReadPaths(req)
+			} else {
+				resp = h.handleRead(req)
+			}
 		case wire.OpWrite:
 			resp = h.handleWrite(req)
 		case wire.OpSubscribe:
@@ -205,6 +284,17 @@ func (h *ProtocolHandler) HandleRequest(req *wire.Request) *wire.Response {
 	return resp
 }
 
+// operationAction maps a protocol operation to the action checked against
+// the peer's role.
+func operationAction(op wire.Operation) zone.Action {
+	switch op {
+	case wire.OpWrite, wire.OpInvoke:
+		return zone.ActionControl
+	default:
+		return zone.ActionRead
+	}
+}
+
 // logRequest logs an incoming request event.
 func (h *ProtocolHandler) logRequest(req *wire.Request) {
 	h.mu.RLock()
@@ -315,6 +405,9 @@ func (h *ProtocolHandler) handleRead(req *wire.Request) *wire.Response {
 	if h.peerZoneType != 0 {
 		ctx = zonecontext.ContextWithCallerZoneType(ctx, h.peerZoneType)
 	}
+	if h.peerRole != 0 {
+		ctx = zonecontext.ContextWithCallerRole(ctx, h.peerRole)
+	}
 
 	// Read attributes using context-aware methods
 	var result map[uint16]any
@@ -385,44 +478,70 @@ func (h *ProtocolHandler) handleWrite(req *wire.Request) *wire.Response {
 		}
 	}
 
-	// Write attributes
-	result := make(map[uint16]any)
-	var firstError error
-	var firstErrorStatus wire.Status
-
-	for attrID, value := range writePayload {
-		err := feature.WriteAttribute(attrID, value)
-		if err != nil {
-			if firstError == nil {
-				firstError = err
-				// Determine error type
-				switch err {
-				case model.ErrAttributeNotFound:
-					firstErrorStatus = wire.StatusInvalidAttribute
-				case model.ErrFeatureReadOnly, model.ErrAttributeNotWritable:
-					firstErrorStatus = wire.StatusReadOnly
-				default:
-					firstErrorStatus = wire.StatusConstraintError
-				}
+	// A dataVersion entry is the precondition of a conditional write,
+	// not an attribute to write.
+	var version *uint32
+	if raw, ok := writePayload[model.AttrIDDataVersion]; ok {
+		v, ok := wire.ToUint32(raw)
+		if !ok {
+			return &wire.Response{
+				MessageID: req.MessageID,
+				Status:    wire.StatusInvalidParameter,
+				Payload: &wire.ErrorPayload{
+					Message: "invalid dataVersion precondition",
+				},
 			}
-		} else {
-			// Read back the actual value (may have been constrained)
-			if actual, err := feature.ReadAttribute(attrID); err == nil {
-				result[attrID] = actual
+		}
+		version = &v
+		attrs := make(map[uint16]any, len(writePayload)-1)
+		for id, value := range writePayload {
+			if id != model.AttrIDDataVersion {
+				attrs[id] = value
 			}
 		}
+		writePayload = attrs
 	}
 
-	if firstError != nil {
+	if fields := h.validateWrite(feature, writePayload); len(fields) > 0 {
 		return &wire.Response{
 			MessageID: req.MessageID,
-			Status:    firstErrorStatus,
+			Status:    fieldErrorStatus(fields),
+			Payload:   fieldErrorPayload(fields),
+		}
+	}
+
+	// Write all attributes or none
+	if err := feature.WriteAttributes(writePayload, version); err != nil {
+		var status wire.Status
+		switch {
+		case errors.Is(err, model.ErrVersionMismatch):
+			status = wire.StatusVersionMismatch
+		case errors.Is(err, model.ErrAttributeNotFound):
+			status = wire.StatusInvalidAttribute
+		case errors.Is(err, model.ErrFeatureReadOnly), errors.Is(err, model.ErrAttributeNotWritable):
+			status = wire.StatusReadOnly
+		case errors.Is(err, model.ErrAttributeValueType):
+			status = wire.StatusInvalidParameter
+		default:
+			status = wire.StatusConstraintError
+		}
+		return &wire.Response{
+			MessageID: req.MessageID,
+			Status:    status,
 			Payload: &wire.ErrorPayload{
-				Message: firstError.Error(),
+				Message: err.Error(),
 			},
 		}
 	}
 
+	// Read back the actual values (may have been constrained)
+	result := make(map[uint16]any, len(writePayload))
+	for attrID := range writePayload {
+		if actual, err := feature.ReadAttribute(attrID); err == nil {
+			result[attrID] = actual
+		}
+	}
+
 	// Call write callback if set and we have successful writes
 	if len(result) > 0 {
 		h.mu.RLock()
@@ -433,6 +552,11 @@ func (h *ProtocolHandler) handleWrite(req *wire.Request) *wire.Response {
 		}
 	}
 
+	// Conditional writes return the new data version for the next write.
+	if version != nil {
+		result[model.AttrIDDataVersion] = feature.DataVersion()
+	}
+
 	return &wire.Response{
 		MessageID: req.MessageID,
 		Status:    wire.StatusSuccess,
@@ -446,6 +570,9 @@ func (h *ProtocolHandler) handleSubscribe(req *wire.Request) *wire.Response {
 	if req.FeatureID == 0 {
 		return h.handleUnsubscribe(req)
 	}
+	if req.IsMultiPath() {
+		return h.handleSubscribePaths(req)
+	}
 
 	// Get the endpoint
 	endpoint, err := h.device.GetEndpoint(req.EndpointID)
@@ -492,6 +619,9 @@ func (h *ProtocolHandler) handleSubscribe(req *wire.Request) *wire.Response {
 	if h.peerZoneType != 0 {
 		ctx = zonecontext.ContextWithCallerZoneType(ctx, h.peerZoneType)
 	}
+	if h.peerRole != 0 {
+		ctx = zonecontext.ContextWithCallerRole(ctx, h.peerRole)
+	}
 
 	// Read current values for priming report using context-aware methods
 	var currentValues map[uint16]any
@@ -516,6 +646,161 @@ func (h *ProtocolHandler) handleSubscribe(req *wire.Request) *wire.Response {
 	}
 }
 
+// handleReadPaths processes a multi-path Read request. Each path is read
+// independently and reports its own status; the request itself only fails
+// if the payload is malformed.
+func (h *ProtocolHandler) handleReadPaths(req *wire.Request) *wire.Response {
+	mp, resp := extractPaths(req)
+	if resp != nil {
+		return resp
+	}
+
+	ctx := h.callerContext()
+	results := make([]wire.PathResult, len(mp.Paths))
+	for i, path := range mp.Paths {
+		results[i] = wire.PathResult{EndpointID: path.EndpointID, FeatureID: path.FeatureID}
+		feature, status := resolvePath(h.device, path)
+		if status != wire.StatusSuccess {
+			results[i].Status = status
+			continue
+		}
+		values, status := readPath(ctx, feature, path.AttributeIDs, false)
+		results[i].Status = status
+		results[i].Values = values
+	}
+
+	return &wire.Response{
+		MessageID: req.MessageID,
+		Status:    wire.StatusSuccess,
+		Payload:   &wire.MultiPathResponsePayload{Results: results},
+	}
+}
+
+// handleSubscribePaths processes a multi-path Subscribe request. All valid
+// paths share one subscription; the results carry the combined priming
+// report. Paths that fail report their status and are not subscribed.
+func (h *ProtocolHandler) handleSubscribePaths(req *wire.Request) *wire.Response {
+	mp, resp := extractPaths(req)
+	if resp != nil {
+		return resp
+	}
+
+	results, valid := h.primePaths(mp.Paths)
+	subscribed := make([]wire.AttributePath, 0, len(valid))
+	for _, i := range valid {
+		subscribed = append(subscribed, mp.Paths[i])
+	}
+
+	var subID uint32
+	if len(subscribed) > 0 {
+		subID = h.subscriptions.AddInboundPaths(subscribed)
+	}
+
+	return &wire.Response{
+		MessageID: req.MessageID,
+		Status:    wire.StatusSuccess,
+		Payload: &wire.MultiPathResponsePayload{
+			SubscriptionID: subID,
+			Results:        results,
+		},
+	}
+}
+
+// primePaths resolves the paths of a multi-path Subscribe and reads their
+// priming values in the caller's context. It returns a result for every
+// path and the indices of the paths that can be subscribed.
+func (h *ProtocolHandler) primePaths(paths []wire.AttributePath) ([]wire.PathResult, []int) {
+	ctx := h.callerContext()
+	results := make([]wire.PathResult, len(paths))
+	var valid []int
+	for i, path := range paths {
+		results[i] = wire.PathResult{EndpointID: path.EndpointID, FeatureID: path.FeatureID}
+		feature, status := resolvePath(h.device, path)
+		if status != wire.StatusSuccess {
+			results[i].Status = status
+			continue
+		}
+		results[i].Values, _ = readPath(ctx, feature, path.AttributeIDs, true)
+		valid = append(valid, i)
+	}
+	return results, valid
+}
+
+// extractPaths parses a multi-path payload. It returns an error response if
+// the payload has no paths or too many.
+func extractPaths(req *wire.Request) (*wire.MultiPathPayload, *wire.Response) {
+	mp := wire.ExtractMultiPathPayload(req.Payload)
+	if mp == nil || len(mp.Paths) == 0 {
+		return nil, &wire.Response{
+			MessageID: req.MessageID,
+			Status:    wire.StatusInvalidParameter,
+			Payload: &wire.ErrorPayload{
+				Message: "paths required",
+			},
+		}
+	}
+	if len(mp.Paths) > wire.MaxPaths {
+		return nil, &wire.Response{
+			MessageID: req.MessageID,
+			Status:    wire.StatusConstraintError,
+			Payload: &wire.ErrorPayload{
+				Message: fmt.Sprintf("at most %d paths per request", wire.MaxPaths),
+			},
+		}
+	}
+	return mp, nil
+}
+
+// resolvePath looks up the feature addressed by a path.
+func resolvePath(device DeviceModel, path wire.AttributePath) (*model.Feature, wire.Status) {
+	endpoint, err := device.GetEndpoint(path.EndpointID)
+	if err != nil {
+		return nil, wire.StatusInvalidEndpoint
+	}
+	feature, err := endpoint.GetFeatureByID(path.FeatureID)
+	if err != nil {
+		return nil, wire.StatusInvalidFeature
+	}
+	return feature, wire.StatusSuccess
+}
+
+// readPath reads the given attributes of a feature (all if attrIDs is
+// empty). Unknown attributes fail the path unless skipMissing is set, in
+// which case they are left out as in a single-path priming report.
+func readPath(ctx context.Context, feature *model.Feature, attrIDs []uint16, skipMissing bool) (map[uint16]any, wire.Status) {
+	if len(attrIDs) == 0 {
+		return feature.ReadAllAttributesWithContext(ctx), wire.StatusSuccess
+	}
+	values := make(map[uint16]any, len(attrIDs))
+	for _, attrID := range attrIDs {
+		value, err := feature.ReadAttributeWithContext(ctx, attrID)
+		if err != nil {
+			if skipMissing {
+				continue
+			}
+			return nil, wire.StatusInvalidAttribute
+		}
+		values[attrID] = value
+	}
+	return values, wire.StatusSuccess
+}
+
+// callerContext returns a context carrying the peer's zone identity for
+// context-aware attribute reads.
+func (h *ProtocolHandler) callerContext() context.Context {
+	ctx := context.Background()
+	if h.peerID != "" {
+		ctx = zonecontext.ContextWithCallerZoneID(ctx, h.peerID)
+	}
+	if h.peerZoneType != 0 {
+		ctx = zonecontext.ContextWithCallerZoneType(ctx, h.peerZoneType)
+	}
+	if h.peerRole != 0 {
+		ctx = zonecontext.ContextWithCallerRole(ctx, h.peerRole)
+	}
+	return ctx
+}
+
 // handleUnsubscribe processes an unsubscribe request.
 func (h *ProtocolHandler) handleUnsubscribe(req *wire.Request) *wire.Response {
 	// Parse unsubscribe payload. After CBOR roundtrip, the payload may be
@@ -634,6 +919,14 @@ func (h *ProtocolHandler) handleInvoke(req *wire.Request) *wire.Response {
 		}
 	}
 
+	if fields := h.validateInvoke(feature, commandID, params); len(fields) > 0 {
+		return &wire.Response{
+			MessageID: req.MessageID,
+			Status:    fieldErrorStatus(fields),
+			Payload:   fieldErrorPayload(fields),
+		}
+	}
+
 	// Invoke the command with caller zone ID and type in context
 	ctx := context.Background()
 	if h.peerID != "" {
@@ -642,13 +935,23 @@ func (h *ProtocolHandler) handleInvoke(req *wire.Request) *wire.Response {
 	if h.peerZoneType != 0 {
 		ctx = zonecontext.ContextWithCallerZoneType(ctx, h.peerZoneType)
 	}
+	if h.peerRole != 0 {
+		ctx = zonecontext.ContextWithCallerRole(ctx, h.peerRole)
+	}
 	result, err := feature.InvokeCommand(ctx, commandID, params)
 	if err != nil {
-		status := wire.StatusInvalidCommand
 		var cmdErr *wire.CommandError
 		if errors.As(err, &cmdErr) {
-			status = cmdErr.Status
-		} else if errors.Is(err, model.ErrCommandNotFound) {
+			payload := cmdErr.ErrorPayload()
+			payload.Message = err.Error()
+			return &wire.Response{
+				MessageID: req.MessageID,
+				Status:    cmdErr.Status,
+				Payload:   payload,
+			}
+		}
+		status := wire.StatusInvalidCommand
+		if errors.Is(err, model.ErrCommandNotFound) {
 			status = wire.StatusInvalidCommand
 		} else if errors.Is(err, model.ErrInvalidParameters) {
 			status = wire.StatusInvalidParameter
@@ -732,6 +1035,64 @@ func (h *ProtocolHandler) NotifyAttributeChange(endpointID, featureID uint8, att
 	return nil
 }
 
+// validateWrite checks attribute values against the spec schema. Attributes
+// the feature does not have are left for WriteAttributes to reject.
+func (h *ProtocolHandler) validateWrite(feature *model.Feature, attrs map[uint16]any) []wire.FieldError {
+	h.mu.RLock()
+	v := h.validator
+	h.mu.RUnlock()
+	if v == nil {
+		return nil
+	}
+
+	known := make(map[uint16]any, len(attrs))
+	for id, value := range attrs {
+		if _, err := feature.GetAttribute(id); err == nil {
+			known[id] = value
+		}
+	}
+	return v.ValidateWrite(uint8(feature.Type()), known)
+}
+
+// validateInvoke checks command parameters against the spec schema. Commands
+// the feature does not have are left for InvokeCommand to reject.
+func (h *ProtocolHandler) validateInvoke(feature *model.Feature, commandID uint8, params map[string]any) []wire.FieldError {
+	h.mu.RLock()
+	v := h.validator
+	h.mu.RUnlock()
+	if v == nil {
+		return nil
+	}
+	if _, err := feature.GetCommand(commandID); err != nil {
+		return nil
+	}
+	return v.ValidateInvoke(uint8(feature.Type()), commandID, params)
+}
+
+// fieldErrorStatus returns the status for schema validation errors, the
+// same for Write and Invoke: a value of the wrong type or a missing required
+// field is INVALID_PARAMETER, a well-typed value that violates a constraint
+// is CONSTRAINT_ERROR.
+func fieldErrorStatus(fields []wire.FieldError) wire.Status {
+	for _, f := range fields {
+		switch f.Constraint {
+		case wire.ConstraintType, wire.ConstraintRequired, wire.ConstraintUnspecified:
+			return wire.StatusInvalidParameter
+		}
+	}
+	return wire.StatusConstraintError
+}
+
+// fieldErrorPayload builds the error payload for schema validation errors.
+// The message repeats the first error for peers that ignore Fields.
+func fieldErrorPayload(fields []wire.FieldError) *wire.ErrorPayload {
+	msg := "invalid payload: " + fields[0].Error()
+	if len(fields) > 1 {
+		msg += fmt.Sprintf(" (and %d more)", len(fields)-1)
+	}
+	return &wire.ErrorPayload{Message: msg, Fields: fields}
+}
+
 // convertMapAnyAnyToStringAny converts a map[any]any to map[string]any,
 // keeping only entries with string keys. CBOR decoding sometimes produces
 // map[any]any instead of map[string]any.
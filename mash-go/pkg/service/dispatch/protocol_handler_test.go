@@ -6,6 +6,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/mash-protocol/mash-go/pkg/cert"
 	"github.com/mash-protocol/mash-go/pkg/features"
 	"github.com/mash-protocol/mash-go/pkg/log"
 	"github.com/mash-protocol/mash-go/pkg/model"
@@ -84,6 +85,7 @@ func createTestDevice() *model.Device {
 func TestProtocolHandler_HandleRead_DeviceInfo(t *testing.T) {
 	device := createTestDevice()
 	handler := dispatch.NewProtocolHandler(device)
+	handler.SetPeerRole(cert.RoleOwner)
 
 	// Create read request for DeviceInfo (endpoint 0, feature 1)
 	req := &wire.Request{
@@ -123,6 +125,7 @@ func TestProtocolHandler_HandleRead_DeviceInfo(t *testing.T) {
 func TestProtocolHandler_HandleRead_SpecificAttributes(t *testing.T) {
 	device := createTestDevice()
 	handler := dispatch.NewProtocolHandler(device)
+	handler.SetPeerRole(cert.RoleOwner)
 
 	// Request only specific attributes
 	req := &wire.Request{
@@ -155,6 +158,7 @@ func TestProtocolHandler_HandleRead_SpecificAttributes(t *testing.T) {
 func TestProtocolHandler_HandleRead_InvalidEndpoint(t *testing.T) {
 	device := createTestDevice()
 	handler := dispatch.NewProtocolHandler(device)
+	handler.SetPeerRole(cert.RoleOwner)
 
 	req := &wire.Request{
 		MessageID:  3,
@@ -177,6 +181,7 @@ func TestProtocolHandler_HandleRead_InvalidEndpoint(t *testing.T) {
 func TestProtocolHandler_HandleRead_InvalidFeature(t *testing.T) {
 	device := createTestDevice()
 	handler := dispatch.NewProtocolHandler(device)
+	handler.SetPeerRole(cert.RoleOwner)
 
 	req := &wire.Request{
 		MessageID:  4,
@@ -199,6 +204,7 @@ func TestProtocolHandler_HandleRead_InvalidFeature(t *testing.T) {
 func TestProtocolHandler_HandleWrite(t *testing.T) {
 	device := createTestDevice()
 	handler := dispatch.NewProtocolHandler(device)
+	handler.SetPeerRole(cert.RoleOwner)
 
 	// Write request - try to write to a writable attribute
 	// DeviceInfo attributes are generally read-only, so this should fail
@@ -220,9 +226,57 @@ func TestProtocolHandler_HandleWrite(t *testing.T) {
 	}
 }
 
+func TestProtocolHandler_ConditionalWrite(t *testing.T) {
+	device := createTestDevice()
+	handler := dispatch.NewProtocolHandler(device)
+	handler.SetPeerRole(cert.RoleOwner)
+
+	ep, _ := device.GetEndpoint(0)
+	feature, _ := ep.GetFeatureByID(featureIDDeviceInfo)
+	version := feature.DataVersion()
+
+	write := func(msgID uint32, label string, version uint32) *wire.Response {
+		return handler.HandleRequest(&wire.Request{
+			MessageID:  msgID,
+			Operation:  wire.OpWrite,
+			EndpointID: 0,
+			FeatureID:  featureIDDeviceInfo,
+			Payload: map[any]any{ // as decoded from CBOR
+				uint64(features.DeviceInfoAttrLabel): label,
+				uint64(model.AttrIDDataVersion):      uint64(version),
+			},
+		})
+	}
+
+	resp := write(1, "Kitchen", version)
+	if !resp.IsSuccess() {
+		t.Fatalf("conditional write with current version failed: %v", resp.Status)
+	}
+	result := resp.Payload.(map[uint16]any)
+	newVersion, ok := result[model.AttrIDDataVersion].(uint32)
+	if !ok || newVersion == version {
+		t.Fatalf("expected new data version in response, got %v", result[model.AttrIDDataVersion])
+	}
+
+	// A second writer still holding the old version is rejected.
+	resp = write(2, "Garage", version)
+	if resp.Status != wire.StatusVersionMismatch {
+		t.Fatalf("expected VERSION_MISMATCH, got %v", resp.Status)
+	}
+	if label, _ := feature.ReadAttribute(features.DeviceInfoAttrLabel); label != "Kitchen" {
+		t.Errorf("rejected write must not apply, label = %v", label)
+	}
+
+	// Retrying with the version from the response succeeds.
+	if resp = write(3, "Garage", newVersion); !resp.IsSuccess() {
+		t.Errorf("write with refreshed version failed: %v", resp.Status)
+	}
+}
+
 func TestProtocolHandler_HandleSubscribe(t *testing.T) {
 	device := createTestDevice()
 	handler := dispatch.NewProtocolHandler(device)
+	handler.SetPeerRole(cert.RoleOwner)
 
 	req := &wire.Request{
 		MessageID:  6,
@@ -260,6 +314,7 @@ func TestProtocolHandler_HandleSubscribe(t *testing.T) {
 func TestProtocolHandler_HandleUnsubscribe(t *testing.T) {
 	device := createTestDevice()
 	handler := dispatch.NewProtocolHandler(device)
+	handler.SetPeerRole(cert.RoleOwner)
 
 	// First create a subscription
 	subReq := &wire.Request{
@@ -298,9 +353,78 @@ func TestProtocolHandler_HandleUnsubscribe(t *testing.T) {
 	}
 }
 
+func TestProtocolHandler_SchemaValidation(t *testing.T) {
+	device := createTestDevice()
+	signals := features.NewSignals()
+	signals.OnSendPriceSignal(func(context.Context, features.SendPriceSignalRequest) error { return nil })
+	ep, _ := device.GetEndpoint(1)
+	ep.AddFeature(signals.Feature)
+	handler := dispatch.NewProtocolHandler(device)
+	handler.SetPeerRole(cert.RoleOwner)
+
+	write := &wire.Request{
+		MessageID:  20,
+		Operation:  wire.OpWrite,
+		EndpointID: 0,
+		FeatureID:  featureIDDeviceInfo,
+		Payload:    map[any]any{uint64(features.DeviceInfoAttrLabel): uint64(42)},
+	}
+	invoke := &wire.Request{
+		MessageID:  21,
+		Operation:  wire.OpInvoke,
+		EndpointID: 1,
+		FeatureID:  uint8(model.FeatureSignals),
+		Payload: map[any]any{ // as decoded from CBOR
+			uint64(1): uint64(features.SignalsCmdSendPriceSignal),
+			uint64(2): map[any]any{
+				"source":    uint64(1),
+				"startTime": uint64(1700000000),
+				"slots": []any{
+					map[any]any{"duration": uint64(900), "price": int64(25)},
+					map[any]any{"duration": int64(-900), "price": int64(25)},
+				},
+			},
+		},
+	}
+
+	// A type error is INVALID_PARAMETER and a range error CONSTRAINT_ERROR,
+	// for Write and Invoke alike
+	resp := handler.HandleRequest(write)
+	if resp.Status != wire.StatusInvalidParameter {
+		t.Errorf("write: status = %v, want StatusInvalidParameter", resp.Status)
+	}
+	ep0 := wire.ExtractErrorPayload(resp.Payload)
+	if ep0 == nil || len(ep0.Fields) != 1 || ep0.Fields[0].Path != "label" {
+		t.Errorf("write: error payload = %+v, want a label field error", ep0)
+	}
+
+	resp = handler.HandleRequest(invoke)
+	if resp.Status != wire.StatusConstraintError {
+		t.Errorf("invoke: status = %v, want StatusConstraintError", resp.Status)
+	}
+	ep1 := wire.ExtractErrorPayload(resp.Payload)
+	want := wire.FieldError{
+		Path:       "slots[1].duration",
+		Message:    "value -900 out of range for uint32",
+		Constraint: wire.ConstraintMin,
+		Limit:      int64(0),
+	}
+	if ep1 == nil || len(ep1.Fields) != 1 || ep1.Fields[0] != want {
+		t.Errorf("invoke: error payload = %+v, want %v", ep1, want)
+	}
+
+	// Without a validator the payloads reach the features.
+	handler.SetValidator(nil)
+	resp = handler.HandleRequest(write)
+	if ep := wire.ExtractErrorPayload(resp.Payload); ep != nil && len(ep.Fields) > 0 {
+		t.Errorf("write: field errors %+v with validator disabled", ep.Fields)
+	}
+}
+
 func TestProtocolHandler_HandleInvoke(t *testing.T) {
 	device := createTestDevice()
 	handler := dispatch.NewProtocolHandler(device)
+	handler.SetPeerRole(cert.RoleOwner)
 
 	// Try to invoke a command on DeviceInfo
 	// DeviceInfo typically doesn't have commands, so this should fail
@@ -325,6 +449,7 @@ func TestProtocolHandler_HandleInvoke(t *testing.T) {
 func TestProtocolHandler_InvalidOperation(t *testing.T) {
 	device := createTestDevice()
 	handler := dispatch.NewProtocolHandler(device)
+	handler.SetPeerRole(cert.RoleOwner)
 
 	req := &wire.Request{
 		MessageID:  10,
@@ -344,9 +469,60 @@ func TestProtocolHandler_InvalidOperation(t *testing.T) {
 	}
 }
 
+func TestProtocolHandler_PeerRole(t *testing.T) {
+	device := createTestDevice()
+	handler := dispatch.NewProtocolHandler(device)
+	handler.SetPeerRole(cert.RoleOwner)
+
+	write := &wire.Request{
+		MessageID:  11,
+		Operation:  wire.OpWrite,
+		EndpointID: 0,
+		FeatureID:  featureIDDeviceInfo,
+		Payload:    wire.WritePayload{1: "new-device-id"},
+	}
+	invoke := &wire.Request{
+		MessageID:  12,
+		Operation:  wire.OpInvoke,
+		EndpointID: 0,
+		FeatureID:  featureIDDeviceInfo,
+		Payload:    &wire.InvokePayload{CommandID: 1},
+	}
+	read := &wire.Request{
+		MessageID:  13,
+		Operation:  wire.OpRead,
+		EndpointID: 0,
+		FeatureID:  featureIDDeviceInfo,
+	}
+
+	t.Run("MemberReadOnly", func(t *testing.T) {
+		handler.SetPeerRole(cert.RoleMember)
+
+		for _, req := range []*wire.Request{write, invoke} {
+			resp := handler.HandleRequest(req)
+			if resp.Status != wire.StatusNotAuthorized {
+				t.Errorf("%v: expected StatusNotAuthorized, got %d", req.Operation, resp.Status)
+			}
+		}
+		if resp := handler.HandleRequest(read); !resp.IsSuccess() {
+			t.Errorf("expected member read to succeed, got status %d", resp.Status)
+		}
+	})
+
+	t.Run("AdminMayControl", func(t *testing.T) {
+		handler.SetPeerRole(cert.RoleAdmin)
+
+		// The write still fails (read-only attribute), but not on the role.
+		if resp := handler.HandleRequest(write); resp.Status == wire.StatusNotAuthorized {
+			t.Error("expected admin write to pass the role check")
+		}
+	})
+}
+
 func TestProtocolHandler_ReadElectrical(t *testing.T) {
 	device := createTestDevice()
 	handler := dispatch.NewProtocolHandler(device)
+	handler.SetPeerRole(cert.RoleOwner)
 
 	// Read Electrical feature on endpoint 1
 	req := &wire.Request{
@@ -380,6 +556,7 @@ func TestProtocolHandler_ReadElectrical(t *testing.T) {
 func TestProtocolHandler_ZoneID(t *testing.T) {
 	device := createTestDevice()
 	handler := dispatch.NewProtocolHandler(device)
+	handler.SetPeerRole(cert.RoleOwner)
 
 	// Set zone context
 	handler.SetZoneID("zone-123")
@@ -392,6 +569,7 @@ func TestProtocolHandler_ZoneID(t *testing.T) {
 func TestProtocolHandler_SetPeerID(t *testing.T) {
 	device := createTestDevice()
 	handler := dispatch.NewProtocolHandler(device)
+	handler.SetPeerRole(cert.RoleOwner)
 
 	// Set peer ID
 	handler.SetPeerID("peer-456")
@@ -404,6 +582,7 @@ func TestProtocolHandler_SetPeerID(t *testing.T) {
 func TestProtocolHandler_SubscriptionManagerIntegration(t *testing.T) {
 	device := createTestDevice()
 	handler := dispatch.NewProtocolHandler(device)
+	handler.SetPeerRole(cert.RoleOwner)
 
 	// Create a subscription
 	req := &wire.Request{
@@ -452,6 +631,7 @@ func TestProtocolHandler_NotifyAttributeChange(t *testing.T) {
 	}
 
 	handler := dispatch.NewProtocolHandlerWithSend(device, sendFunc)
+	handler.SetPeerRole(cert.RoleOwner)
 
 	// Create a subscription to all attributes
 	req := &wire.Request{
@@ -505,6 +685,7 @@ func TestProtocolHandler_NotifyAttributeChange_WithAttributeFilter(t *testing.T)
 	}
 
 	handler := dispatch.NewProtocolHandlerWithSend(device, sendFunc)
+	handler.SetPeerRole(cert.RoleOwner)
 
 	// Create a subscription to specific attributes only (attribute 1 and 2)
 	req := &wire.Request{
@@ -554,6 +735,7 @@ func TestProtocolHandler_NotifyAttributeChange_MultipleSubscriptions(t *testing.
 	}
 
 	handler := dispatch.NewProtocolHandlerWithSend(device, sendFunc)
+	handler.SetPeerRole(cert.RoleOwner)
 
 	// Create two subscriptions to the same feature
 	for i := 0; i < 2; i++ {
@@ -592,6 +774,7 @@ func TestProtocolHandler_CustomSendFunction(t *testing.T) {
 	}
 
 	handler := dispatch.NewProtocolHandlerWithSend(device, sendFunc)
+	handler.SetPeerRole(cert.RoleOwner)
 
 	// Verify handler was created with send function
 	if handler == nil {
@@ -618,6 +801,7 @@ func TestProtocolHandler_CustomSendFunction(t *testing.T) {
 func TestProtocolHandler_UnsubscribeRemovesFromManager(t *testing.T) {
 	device := createTestDevice()
 	handler := dispatch.NewProtocolHandler(device)
+	handler.SetPeerRole(cert.RoleOwner)
 
 	// Create a subscription
 	subReq := &wire.Request{
@@ -671,6 +855,7 @@ func TestProtocolHandler_UnsubscribeRemovesFromManager(t *testing.T) {
 func TestProtocolHandler_LogsRequest(t *testing.T) {
 	device := createTestDevice()
 	handler := dispatch.NewProtocolHandler(device)
+	handler.SetPeerRole(cert.RoleOwner)
 
 	logger := newCapturingLogger()
 	handler.SetLogger(logger, "conn-123")
@@ -722,6 +907,7 @@ func TestProtocolHandler_LogsRequest(t *testing.T) {
 func TestProtocolHandler_LogsResponse(t *testing.T) {
 	device := createTestDevice()
 	handler := dispatch.NewProtocolHandler(device)
+	handler.SetPeerRole(cert.RoleOwner)
 
 	logger := newCapturingLogger()
 	handler.SetLogger(logger, "conn-456")
@@ -765,6 +951,7 @@ func TestProtocolHandler_LogsResponse(t *testing.T) {
 func TestProtocolHandler_LogsProcessingTime(t *testing.T) {
 	device := createTestDevice()
 	handler := dispatch.NewProtocolHandler(device)
+	handler.SetPeerRole(cert.RoleOwner)
 
 	logger := newCapturingLogger()
 	handler.SetLogger(logger, "conn-789")
@@ -800,6 +987,7 @@ func TestProtocolHandler_LogsProcessingTime(t *testing.T) {
 func TestProtocolHandler_NoLoggerNoPanic(t *testing.T) {
 	device := createTestDevice()
 	handler := dispatch.NewProtocolHandler(device)
+	handler.SetPeerRole(cert.RoleOwner)
 
 	// No logger set - should not panic
 	req := &wire.Request{
@@ -820,6 +1008,7 @@ func TestProtocolHandler_NoLoggerNoPanic(t *testing.T) {
 func TestProtocolHandler_LogsErrorResponse(t *testing.T) {
 	device := createTestDevice()
 	handler := dispatch.NewProtocolHandler(device)
+	handler.SetPeerRole(cert.RoleOwner)
 
 	logger := newCapturingLogger()
 	handler.SetLogger(logger, "conn-err")
@@ -908,6 +1097,7 @@ func TestProtocolHandler_HandleRead_PassesZoneContext(t *testing.T) {
 	device := createDeviceWithReadHook(&hookCalled, &capturedZoneID)
 
 	handler := dispatch.NewProtocolHandler(device)
+	handler.SetPeerRole(cert.RoleOwner)
 	handler.SetPeerID("zone-alpha")
 
 	// Read all attributes
@@ -974,6 +1164,7 @@ func TestProtocolHandler_HandleRead_NoPeerID(t *testing.T) {
 	device := createDeviceWithReadHook(&hookCalled, &capturedZoneID)
 
 	handler := dispatch.NewProtocolHandler(device)
+	handler.SetPeerRole(cert.RoleOwner)
 	// Do NOT set peerID
 
 	resp := handler.HandleRequest(&wire.Request{
@@ -1014,6 +1205,7 @@ func TestProtocolHandler_HandleSubscribe_PrimingUsesContext(t *testing.T) {
 	device := createDeviceWithReadHook(&hookCalled, &capturedZoneID)
 
 	handler := dispatch.NewProtocolHandler(device)
+	handler.SetPeerRole(cert.RoleOwner)
 	handler.SetPeerID("zone-beta")
 
 	// Subscribe to all attributes - the priming report should use context-aware reads
@@ -1075,3 +1267,196 @@ func TestProtocolHandler_HandleSubscribe_PrimingUsesContext(t *testing.T) {
 		t.Errorf("expected zone ID 'zone-beta' for specific subscribe, got %q", capturedZoneID)
 	}
 }
+
+func TestProtocolHandler_HandleReadPaths(t *testing.T) {
+	device := createTestDevice()
+	handler := dispatch.NewProtocolHandler(device)
+	handler.SetPeerRole(cert.RoleOwner)
+
+	req := &wire.Request{
+		MessageID:  1,
+		Operation:  wire.OpRead,
+		EndpointID: wire.MultiPathEndpointID,
+		FeatureID:  wire.MultiPathFeatureID,
+		Payload: &wire.MultiPathPayload{
+			Paths: []wire.AttributePath{
+				{EndpointID: 0, FeatureID: featureIDDeviceInfo, AttributeIDs: []uint16{1}},
+				{EndpointID: 1, FeatureID: uint8(model.FeatureElectrical)},
+				{EndpointID: 9, FeatureID: uint8(model.FeatureElectrical)},
+				{EndpointID: 1, FeatureID: uint8(model.FeatureEnergyControl)},
+				{EndpointID: 0, FeatureID: featureIDDeviceInfo, AttributeIDs: []uint16{0x7FFF}},
+			},
+		},
+	}
+
+	resp := handler.HandleRequest(req)
+	if !resp.IsSuccess() {
+		t.Fatalf("Expected success response, got status %d", resp.Status)
+	}
+
+	payload, ok := resp.Payload.(*wire.MultiPathResponsePayload)
+	if !ok {
+		t.Fatalf("Expected *wire.MultiPathResponsePayload, got %T", resp.Payload)
+	}
+	if len(payload.Results) != 5 {
+		t.Fatalf("Expected 5 results, got %d", len(payload.Results))
+	}
+
+	want := []wire.Status{
+		wire.StatusSuccess,
+		wire.StatusSuccess,
+		wire.StatusInvalidEndpoint,
+		wire.StatusInvalidFeature,
+		wire.StatusInvalidAttribute,
+	}
+	for i, r := range payload.Results {
+		if r.Status != want[i] {
+			t.Errorf("Result %d: expected status %v, got %v", i, want[i], r.Status)
+		}
+	}
+	if payload.Results[0].Values[1] != "test-device-001" {
+		t.Errorf("Expected DeviceID value, got %v", payload.Results[0].Values)
+	}
+	if len(payload.Results[1].Values) == 0 {
+		t.Error("Expected all Electrical attributes")
+	}
+}
+
+func TestProtocolHandler_HandleReadPaths_NoPaths(t *testing.T) {
+	handler := dispatch.NewProtocolHandler(createTestDevice())
+	handler.SetPeerRole(cert.RoleOwner)
+
+	resp := handler.HandleRequest(&wire.Request{
+		MessageID:  1,
+		Operation:  wire.OpRead,
+		EndpointID: wire.MultiPathEndpointID,
+		FeatureID:  wire.MultiPathFeatureID,
+		Payload:    &wire.MultiPathPayload{},
+	})
+	if resp.Status != wire.StatusInvalidParameter {
+		t.Errorf("Expected INVALID_PARAMETER, got %v", resp.Status)
+	}
+}
+
+func TestProtocolHandler_HandleSubscribePaths(t *testing.T) {
+	device := createTestDevice()
+
+	var sent []*wire.Notification
+	handler := dispatch.NewProtocolHandlerWithSend(device, func(n *wire.Notification) error {
+		sent = append(sent, n)
+		return nil
+	})
+	handler.SetPeerRole(cert.RoleOwner)
+
+	req := &wire.Request{
+		MessageID:  1,
+		Operation:  wire.OpSubscribe,
+		EndpointID: wire.MultiPathEndpointID,
+		FeatureID:  wire.MultiPathFeatureID,
+		Payload: &wire.MultiPathPayload{
+			Paths: []wire.AttributePath{
+				{EndpointID: 0, FeatureID: featureIDDeviceInfo, AttributeIDs: []uint16{1}},
+				{EndpointID: 1, FeatureID: uint8(model.FeatureElectrical)},
+				{EndpointID: 9, FeatureID: uint8(model.FeatureElectrical)},
+			},
+		},
+	}
+
+	resp := handler.HandleRequest(req)
+	if !resp.IsSuccess() {
+		t.Fatalf("Expected success response, got status %d", resp.Status)
+	}
+	payload := resp.Payload.(*wire.MultiPathResponsePayload)
+	if payload.SubscriptionID == 0 {
+		t.Fatal("Expected a subscription ID")
+	}
+	if payload.Results[0].Values[1] != "test-device-001" {
+		t.Errorf("Expected DeviceID in priming report, got %v", payload.Results[0].Values)
+	}
+	if payload.Results[2].Status != wire.StatusInvalidEndpoint {
+		t.Errorf("Expected INVALID_ENDPOINT for unknown endpoint, got %v", payload.Results[2].Status)
+	}
+
+	// One subscription covers both valid paths
+	if handler.SubscriptionCount() != 1 {
+		t.Errorf("Expected 1 subscription, got %d", handler.SubscriptionCount())
+	}
+
+	_ = handler.NotifyAttributeChange(1, uint8(model.FeatureElectrical), 1, uint8(1))
+	_ = handler.NotifyAttributeChange(0, featureIDDeviceInfo, 2, "ignored")
+	_ = handler.NotifyAttributeChange(0, featureIDDeviceInfo, 1, "renamed")
+
+	if len(sent) != 2 {
+		t.Fatalf("Expected 2 notifications, got %d", len(sent))
+	}
+	for _, n := range sent {
+		if n.SubscriptionID != payload.SubscriptionID {
+			t.Errorf("Expected subscription ID %d, got %d", payload.SubscriptionID, n.SubscriptionID)
+		}
+	}
+	if sent[0].EndpointID != 1 || sent[1].EndpointID != 0 || sent[1].Changes[1] != "renamed" {
+		t.Errorf("Unexpected notifications: %+v %+v", sent[0], sent[1])
+	}
+}
+
+func TestProtocolHandler_EncodeResponseChunked(t *testing.T) {
+	device := createTestDevice()
+	handler := dispatch.NewProtocolHandler(device)
+	handler.SetPeerRole(cert.RoleOwner)
+
+	resp := handler.HandleRequest(&wire.Request{
+		MessageID:  1,
+		Operation:  wire.OpRead,
+		EndpointID: 0,
+		FeatureID:  featureIDDeviceInfo,
+	})
+	frames, err := handler.EncodeResponse(resp)
+	if err != nil {
+		t.Fatalf("EncodeResponse failed: %v", err)
+	}
+	if len(frames) != 1 {
+		t.Fatalf("expected 1 frame at the default limit, got %d", len(frames))
+	}
+	size := len(frames[0])
+
+	// A response exactly at the limit is not chunked.
+	handler.SetMaxMessageSize(size)
+	if frames, _ = handler.EncodeResponse(resp); len(frames) != 1 {
+		t.Errorf("expected 1 frame at the limit, got %d", len(frames))
+	}
+
+	// One byte over the limit it is chunked and reassembles to the same values.
+	handler.SetMaxMessageSize(size - 1)
+	frames, err = handler.EncodeResponse(resp)
+	if err != nil {
+		t.Fatalf("EncodeResponse failed: %v", err)
+	}
+	if len(frames) < 2 {
+		t.Fatalf("expected chunked response, got %d frame(s)", len(frames))
+	}
+
+	assembler := wire.NewChunkAssembler()
+	var full *wire.Response
+	for i, frame := range frames {
+		if len(frame) > size-1 {
+			t.Errorf("frame %d: %d bytes exceeds limit %d", i, len(frame), size-1)
+		}
+		chunk, err := wire.DecodeResponse(frame)
+		if err != nil {
+			t.Fatalf("decode frame %d: %v", i, err)
+		}
+		if full, err = assembler.Add(chunk); err != nil {
+			t.Fatalf("Add frame %d: %v", i, err)
+		}
+	}
+	if full == nil || !full.IsSuccess() || full.MessageID != 1 {
+		t.Fatalf("unexpected reassembled response: %+v", full)
+	}
+	values, ok := full.Payload.(map[any]any)
+	if !ok {
+		t.Fatalf("expected map payload, got %T", full.Payload)
+	}
+	if values[uint64(1)] != "test-device-001" {
+		t.Errorf("expected DeviceID test-device-001, got %v", values[uint64(1)])
+	}
+}
@@ -3,6 +3,8 @@ package dispatch
 import (
 	"sync"
 	"sync/atomic"
+
+	"github.com/mash-protocol/mash-go/pkg/wire"
 )
 
 // Subscription represents a subscription to feature attributes.
@@ -11,6 +13,37 @@ type Subscription struct {
 	EndpointID uint8
 	FeatureID  uint8
 	Attributes []uint16 // Empty means all attributes
+
+	// Paths lists the subscribed paths of a multi-path subscription. When
+	// set, EndpointID and FeatureID hold the reserved multi-path pair.
+	Paths []wire.AttributePath
+}
+
+// Matches returns true if the subscription covers the given attribute.
+func (s *Subscription) Matches(endpointID, featureID uint8, attributeID uint16) bool {
+	if len(s.Paths) == 0 {
+		return s.EndpointID == endpointID && s.FeatureID == featureID && containsAttr(s.Attributes, attributeID)
+	}
+	for _, path := range s.Paths {
+		if path.EndpointID == endpointID && path.FeatureID == featureID && containsAttr(path.AttributeIDs, attributeID) {
+			return true
+		}
+	}
+	return false
+}
+
+// containsAttr reports whether attrs includes id. An empty list means all
+// attributes.
+func containsAttr(attrs []uint16, id uint16) bool {
+	if len(attrs) == 0 {
+		return true
+	}
+	for _, attrID := range attrs {
+		if attrID == id {
+			return true
+		}
+	}
+	return false
 }
 
 // SessionSubscriptionTracker manages bidirectional subscriptions with separate
@@ -54,6 +87,25 @@ func (m *SessionSubscriptionTracker) AddInbound(endpointID, featureID uint8, att
 	return id
 }
 
+// AddInboundPaths adds an inbound multi-path subscription covering all of
+// the given paths under one ID. Returns the assigned subscription ID.
+func (m *SessionSubscriptionTracker) AddInboundPaths(paths []wire.AttributePath) uint32 {
+	id := atomic.AddUint32(&m.nextInboundID, 1)
+
+	sub := &Subscription{
+		ID:         id,
+		EndpointID: wire.MultiPathEndpointID,
+		FeatureID:  wire.MultiPathFeatureID,
+		Paths:      paths,
+	}
+
+	m.mu.Lock()
+	m.inbound[id] = sub
+	m.mu.Unlock()
+
+	return id
+}
+
 // AddOutbound adds an outbound subscription (from us to remote features).
 // Returns the assigned subscription ID.
 func (m *SessionSubscriptionTracker) AddOutbound(endpointID, featureID uint8, attributes []uint16) uint32 {
@@ -143,28 +195,16 @@ func (m *SessionSubscriptionTracker) ListOutbound() []*Subscription {
 // A subscription matches if it is for the same endpoint and feature, and either:
 // - The subscription has no attribute filter (empty Attributes slice means all attributes)
 // - The subscription's attribute filter includes the specified attributeID
+//
+// A multi-path subscription matches if any of its paths does.
 func (m *SessionSubscriptionTracker) GetMatchingInbound(endpointID, featureID uint8, attributeID uint16) []*Subscription {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 
 	var matches []*Subscription
 	for _, sub := range m.inbound {
-		if sub.EndpointID != endpointID || sub.FeatureID != featureID {
-			continue
-		}
-
-		// Empty Attributes means subscribed to all attributes
-		if len(sub.Attributes) == 0 {
+		if sub.Matches(endpointID, featureID, attributeID) {
 			matches = append(matches, sub)
-			continue
-		}
-
-		// Check if specific attribute is in the subscription
-		for _, subAttrID := range sub.Attributes {
-			if subAttrID == attributeID {
-				matches = append(matches, sub)
-				break
-			}
 		}
 	}
 	return matches
@@ -9,10 +9,13 @@ import (
 	"github.com/stretchr/testify/mock"
 
 	"github.com/mash-protocol/mash-go/pkg/cert"
+	"github.com/mash-protocol/mash-go/pkg/clock"
 	"github.com/mash-protocol/mash-go/pkg/discovery/mocks"
 	"github.com/mash-protocol/mash-go/pkg/failsafe"
+	"github.com/mash-protocol/mash-go/pkg/features"
 	"github.com/mash-protocol/mash-go/pkg/model"
 	"github.com/mash-protocol/mash-go/pkg/persistence"
+	"github.com/mash-protocol/mash-go/pkg/zonecontext"
 )
 
 func TestDeviceServiceSetStateStore(t *testing.T) {
@@ -177,6 +180,108 @@ func TestDeviceServiceSaveStateWithFailsafeTimer(t *testing.T) {
 	}
 }
 
+func TestDeviceServiceSaveLoadStateWithLimits(t *testing.T) {
+	fc := clock.NewFake(time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC))
+	config := validDeviceConfig()
+	config.Clock = fc
+
+	newResolvers := func(svc *DeviceService) (*features.LimitResolver, *features.SetpointResolver) {
+		ec := features.NewEnergyControl()
+		ec.SetCapabilities(true, false, true, false, false, false, false)
+		lr := features.NewLimitResolver(ec)
+		sr := features.NewSetpointResolver(ec)
+		svc.SetLimitResolver(lr)
+		svc.SetSetpointResolver(sr)
+		return lr, sr
+	}
+
+	svc, err := NewDeviceService(model.NewDevice("test-device", 0x1234, 0x5678), config)
+	if err != nil {
+		t.Fatalf("NewDeviceService failed: %v", err)
+	}
+	lr, sr := newResolvers(svc)
+
+	store := persistence.NewDeviceStateStore(filepath.Join(t.TempDir(), "state.json"))
+	svc.SetStateStore(store)
+
+	// Grid zone limits consumption for 3 hours; local zone sets an
+	// indefinite setpoint.
+	gridCtx := zonecontext.ContextWithCallerZoneID(context.Background(), "zone-grid")
+	gridCtx = zonecontext.ContextWithCallerZoneType(gridCtx, cert.ZoneTypeGrid)
+	limit, dur := int64(4200000), uint32(3*3600)
+	if _, err := lr.HandleSetLimit(gridCtx, features.SetLimitRequest{
+		ConsumptionLimit: &limit,
+		Duration:         &dur,
+		Cause:            features.LimitCauseGridOptimization,
+	}); err != nil {
+		t.Fatalf("HandleSetLimit() error = %v", err)
+	}
+
+	localCtx := zonecontext.ContextWithCallerZoneID(context.Background(), "zone-local")
+	localCtx = zonecontext.ContextWithCallerZoneType(localCtx, cert.ZoneTypeLocal)
+	setpoint := int64(2000000)
+	if err := sr.HandleSetSetpoint(localCtx, features.SetSetpointRequest{
+		ConsumptionSetpoint: &setpoint,
+	}); err != nil {
+		t.Fatalf("HandleSetSetpoint() error = %v", err)
+	}
+
+	// Power cycle without a graceful shutdown: each change was saved as it
+	// was made. One hour passes before the device comes back.
+	lr.OnChange, sr.OnChange = nil, nil
+	svc.SetLimitResolver(nil)
+	svc.SetSetpointResolver(nil)
+	fc.Advance(time.Hour)
+
+	svc2, err := NewDeviceService(model.NewDevice("test-device", 0x1234, 0x5678), config)
+	if err != nil {
+		t.Fatalf("NewDeviceService failed: %v", err)
+	}
+	lr2, sr2 := newResolvers(svc2)
+	svc2.SetStateStore(store)
+
+	if err := svc2.LoadState(); err != nil {
+		t.Fatalf("LoadState() error = %v", err)
+	}
+
+	limits := lr2.Snapshot()
+	if len(limits) != 1 {
+		t.Fatalf("restored %d limits, want 1", len(limits))
+	}
+	if limits[0].ZoneID != "zone-grid" || limits[0].Value != limit {
+		t.Errorf("restored limit = %+v", limits[0])
+	}
+	if limits[0].Cause != uint8(features.LimitCauseGridOptimization) {
+		t.Errorf("restored cause = %d, want %d", limits[0].Cause, features.LimitCauseGridOptimization)
+	}
+	if want := fc.Now().Add(2 * time.Hour); !limits[0].ExpiresAt.Equal(want) {
+		t.Errorf("restored expiry = %v, want %v", limits[0].ExpiresAt, want)
+	}
+
+	setpoints := sr2.Snapshot()
+	if len(setpoints) != 1 || setpoints[0].Value != setpoint || !setpoints[0].ExpiresAt.IsZero() {
+		t.Fatalf("restored setpoints = %+v", setpoints)
+	}
+
+	// The re-armed timer fires after the remaining two hours.
+	fc.Advance(2 * time.Hour)
+	if got := lr2.Snapshot(); len(got) != 0 {
+		t.Errorf("limit still active after remaining duration: %+v", got)
+	}
+	if got := sr2.Snapshot(); len(got) != 1 {
+		t.Errorf("indefinite setpoint should survive, got %+v", got)
+	}
+
+	// The expiry was saved too
+	state, err := store.Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if len(state.Limits) != 0 || len(state.Setpoints) != 1 {
+		t.Errorf("saved %d limits, %d setpoints after expiry; want 0 and 1", len(state.Limits), len(state.Setpoints))
+	}
+}
+
 func TestDeviceServiceLoadStateBackwardCompatibility(t *testing.T) {
 	// Test that zones are derived from zone_index_map when state.Zones is empty
 	// (backward compatibility with old state files)
@@ -339,6 +444,43 @@ func TestControllerServiceSaveLoadState(t *testing.T) {
 	}
 }
 
+func TestControllerServiceSetDeviceLabel(t *testing.T) {
+	config := validControllerConfig()
+
+	svc, err := NewControllerService(config)
+	if err != nil {
+		t.Fatalf("NewControllerService failed: %v", err)
+	}
+	store := persistence.NewControllerStateStore(filepath.Join(t.TempDir(), "state.json"))
+	svc.SetStateStore(store)
+
+	if err := svc.SetDeviceLabel("device-001", "bay-07"); err != ErrDeviceNotFound {
+		t.Errorf("SetDeviceLabel(unknown) error = %v, want ErrDeviceNotFound", err)
+	}
+
+	svc.mu.Lock()
+	svc.connectedDevices["device-001"] = &ConnectedDevice{ID: "device-001", LastSeen: time.Now()}
+	svc.mu.Unlock()
+
+	// Setting the label saves it without an explicit SaveState
+	if err := svc.SetDeviceLabel("device-001", "bay-07"); err != nil {
+		t.Fatalf("SetDeviceLabel() error = %v", err)
+	}
+
+	svc2, err := NewControllerService(config)
+	if err != nil {
+		t.Fatalf("NewControllerService failed: %v", err)
+	}
+	svc2.SetStateStore(store)
+	if err := svc2.LoadState(); err != nil {
+		t.Fatalf("LoadState() error = %v", err)
+	}
+	device := svc2.GetDevice("device-001")
+	if device == nil || device.Label != "bay-07" {
+		t.Errorf("restored device = %+v, want label bay-07", device)
+	}
+}
+
 func TestControllerServiceLoadStateNoStore(t *testing.T) {
 	config := validControllerConfig()
 
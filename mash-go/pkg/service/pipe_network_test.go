@@ -0,0 +1,184 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+
+	"github.com/mash-protocol/mash-go/pkg/cert"
+	"github.com/mash-protocol/mash-go/pkg/discovery"
+	"github.com/mash-protocol/mash-go/pkg/discovery/mocks"
+	"github.com/mash-protocol/mash-go/pkg/features"
+	"github.com/mash-protocol/mash-go/pkg/model"
+	"github.com/mash-protocol/mash-go/pkg/persistence"
+	"github.com/mash-protocol/mash-go/pkg/transport"
+)
+
+// startPipeDevice starts a device service on the pipe network and opens
+// its commissioning window.
+func startPipeDevice(t *testing.T, ctx context.Context, network transport.Network, i int) *DeviceService {
+	t.Helper()
+
+	config := validDeviceConfig()
+	config.ListenAddress = "localhost:0"
+	config.SerialNumber = fmt.Sprintf("SN%03d", i)
+	config.Network = network
+
+	device := model.NewDevice(fmt.Sprintf("pipe-device-%03d", i), 0x1234, 0x5678)
+	deviceInfo := features.NewDeviceInfo()
+	_ = deviceInfo.SetSerialNumber(config.SerialNumber)
+	device.RootEndpoint().AddFeature(deviceInfo.Feature)
+
+	svc, err := NewDeviceService(device, config)
+	if err != nil {
+		t.Fatalf("NewDeviceService failed: %v", err)
+	}
+	svc.SetCertStore(cert.NewMemoryStore())
+
+	advertiser := mocks.NewMockAdvertiser(t)
+	advertiser.EXPECT().AdvertiseCommissionable(mock.Anything, mock.Anything).Return(nil).Maybe()
+	advertiser.EXPECT().StopCommissionable().Return(nil).Maybe()
+	advertiser.EXPECT().AdvertiseOperational(mock.Anything, mock.Anything).Return(nil).Maybe()
+	advertiser.EXPECT().StopOperational(mock.Anything).Return(nil).Maybe()
+	advertiser.EXPECT().StopAll().Return().Maybe()
+	svc.SetAdvertiser(advertiser)
+
+	if err := svc.Start(ctx); err != nil {
+		t.Fatalf("Device Start failed: %v", err)
+	}
+	t.Cleanup(func() { _ = svc.Stop() })
+
+	if err := svc.EnterCommissioningMode(); err != nil {
+		t.Fatalf("EnterCommissioningMode failed: %v", err)
+	}
+	return svc
+}
+
+// TestPipeNetwork_Fleet commissions a fleet of devices to one controller
+// over an in-process pipe network, then reconnects operationally and reads
+// from every device. No sockets are opened.
+func TestPipeNetwork_Fleet(t *testing.T) {
+	const fleetSize = 100
+
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	network := transport.NewPipeNetwork()
+
+	controllerConfig := validControllerConfig()
+	controllerConfig.Network = network
+	controller, err := NewControllerService(controllerConfig)
+	if err != nil {
+		t.Fatalf("NewControllerService failed: %v", err)
+	}
+	controller.SetCertStore(createControllerCertStore(t, controllerConfig.ZoneName))
+
+	browser := mocks.NewMockBrowser(t)
+	browser.EXPECT().Stop().Return().Maybe()
+	controller.SetBrowser(browser)
+
+	if err := controller.Start(ctx); err != nil {
+		t.Fatalf("Controller Start failed: %v", err)
+	}
+	defer func() { _ = controller.Stop() }()
+
+	deviceIDs := make([]string, 0, fleetSize)
+	for i := range fleetSize {
+		device := startPipeDevice(t, ctx, network, i)
+		addr := device.CommissioningAddr().(*net.TCPAddr)
+
+		connected, err := controller.Commission(ctx, &discovery.CommissionableService{
+			Host:          "localhost",
+			Port:          uint16(addr.Port),
+			Addresses:     []string{addr.IP.String()},
+			Discriminator: 1234,
+			Categories:    []discovery.DeviceCategory{discovery.CategoryEMobility},
+		}, "20202021")
+		if err != nil {
+			t.Fatalf("device %d: Commission failed: %v", i, err)
+		}
+		deviceIDs = append(deviceIDs, connected.ID)
+	}
+
+	if got := controller.DeviceCount(); got != fleetSize {
+		t.Errorf("DeviceCount() = %d, want %d", got, fleetSize)
+	}
+
+	for i, id := range deviceIDs {
+		if err := controller.Reconnect(ctx, id); err != nil {
+			t.Fatalf("device %d: Reconnect failed: %v", i, err)
+		}
+	}
+
+	for i, id := range deviceIDs {
+		session := controller.GetSession(id)
+		if session == nil {
+			t.Fatalf("device %d: no session after Reconnect", i)
+		}
+		values, err := session.Read(ctx, 0, uint8(model.FeatureDeviceInfo), []uint16{features.DeviceInfoAttrSerialNumber})
+		if err != nil {
+			t.Fatalf("device %d: Read failed: %v", i, err)
+		}
+		if want := fmt.Sprintf("SN%03d", i); values[features.DeviceInfoAttrSerialNumber] != want {
+			t.Errorf("device %d: serialNumber = %v, want %s", i, values[features.DeviceInfoAttrSerialNumber], want)
+		}
+	}
+}
+
+// TestPipeNetwork_CommissionDeviceStoresLabel verifies that the label given
+// to CommissionDevice is saved together with the commissioned device.
+func TestPipeNetwork_CommissionDeviceStoresLabel(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	network := transport.NewPipeNetwork()
+	device := startPipeDevice(t, ctx, network, 0)
+	addr := device.CommissioningAddr().(*net.TCPAddr)
+
+	config := validControllerConfig()
+	config.Network = network
+	controller, err := NewControllerService(config)
+	if err != nil {
+		t.Fatalf("NewControllerService failed: %v", err)
+	}
+	controller.SetCertStore(createControllerCertStore(t, config.ZoneName))
+	store := persistence.NewControllerStateStore(filepath.Join(t.TempDir(), "state.json"))
+	controller.SetStateStore(store)
+
+	browser := mocks.NewMockBrowser(t)
+	browser.EXPECT().FindAllByDiscriminator(mock.Anything, uint16(1234)).
+		Return([]*discovery.CommissionableService{{
+			Host:          "localhost",
+			Port:          uint16(addr.Port),
+			Addresses:     []string{addr.IP.String()},
+			Discriminator: 1234,
+		}}, nil).Once()
+	browser.EXPECT().Stop().Return().Maybe()
+	controller.SetBrowser(browser)
+
+	if err := controller.Start(ctx); err != nil {
+		t.Fatalf("Controller Start failed: %v", err)
+	}
+	defer func() { _ = controller.Stop() }()
+
+	connected, err := controller.CommissionDevice(ctx, 1234, "20202021", "bay-07")
+	if err != nil {
+		t.Fatalf("CommissionDevice failed: %v", err)
+	}
+	if connected.Label != "bay-07" {
+		t.Errorf("Label = %q, want bay-07", connected.Label)
+	}
+
+	state, err := store.Load()
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if len(state.Devices) != 1 || state.Devices[0].DeviceID != connected.ID || state.Devices[0].Label != "bay-07" {
+		t.Errorf("saved devices = %+v, want %s labelled bay-07", state.Devices, connected.ID)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/mash-protocol/mash-go/pkg/cert"
 	"github.com/mash-protocol/mash-go/pkg/features"
 	"github.com/mash-protocol/mash-go/pkg/model"
+	"github.com/mash-protocol/mash-go/pkg/zone"
 	"github.com/mash-protocol/mash-go/pkg/zonecontext"
 )
 
@@ -73,6 +74,18 @@ func CallerZoneTypeFromContext(ctx context.Context) cert.ZoneType {
 	return zonecontext.CallerZoneTypeFromContext(ctx)
 }
 
+// ContextWithCallerRole returns a new context with the caller's zone role.
+// Delegates to pkg/zonecontext.
+func ContextWithCallerRole(ctx context.Context, role cert.Role) context.Context {
+	return zonecontext.ContextWithCallerRole(ctx, role)
+}
+
+// CallerRoleFromContext extracts the caller's zone role from the context.
+// Returns 0 (unknown) if not set. Delegates to pkg/zonecontext.
+func CallerRoleFromContext(ctx context.Context) cert.Role {
+	return zonecontext.CallerRoleFromContext(ctx)
+}
+
 // makeRemoveZoneHandler creates a command handler for the RemoveZone command.
 // This handler validates that only the zone itself can request removal (self-removal).
 func (s *DeviceService) makeRemoveZoneHandler() model.CommandHandler {
@@ -94,6 +107,11 @@ func (s *DeviceService) makeRemoveZoneHandler() model.CommandHandler {
 			return nil, model.ErrCommandNotAllowed
 		}
 
+		// Only the zone owner may remove the zone; admins and members may not.
+		if !zone.RoleAllows(CallerRoleFromContext(ctx), zone.ActionManage) {
+			return nil, model.ErrCommandNotAllowed
+		}
+
 		// Validate self-removal only: the caller must be the zone being removed.
 		// Exception: TEST zones can remove any zone (needed for test
 		// orchestration, e.g. TC-ZTYPE-005/007).
@@ -36,6 +36,7 @@ func TestRemoveZoneHandler_SelfRemoval(t *testing.T) {
 
 	// Create context with caller zone ID
 	ctx := ContextWithCallerZoneID(context.Background(), zoneID)
+	ctx = ContextWithCallerRole(ctx, cert.RoleOwner)
 
 	// Invoke: self-removal (zone removes itself)
 	params := map[string]any{
@@ -86,6 +87,7 @@ func TestRemoveZoneHandler_RejectsOtherZone(t *testing.T) {
 
 	// Create context with caller zone A
 	ctx := ContextWithCallerZoneID(context.Background(), zoneA)
+	ctx = ContextWithCallerRole(ctx, cert.RoleOwner)
 
 	// Invoke: zone A tries to remove zone B (not allowed without enable-key)
 	params := map[string]any{
@@ -132,6 +134,7 @@ func TestRemoveZoneHandler_TestZoneAllowsCrossZone(t *testing.T) {
 	// Zone A (TEST type) removes zone B (allowed for TEST zones)
 	ctx := ContextWithCallerZoneID(context.Background(), zoneA)
 	ctx = ContextWithCallerZoneType(ctx, cert.ZoneTypeTest)
+	ctx = ContextWithCallerRole(ctx, cert.RoleOwner)
 	params := map[string]any{
 		features.RemoveZoneParamZoneID: zoneB,
 	}
@@ -162,6 +165,7 @@ func TestRemoveZoneHandler_InvalidParameters(t *testing.T) {
 
 	handler := svc.makeRemoveZoneHandler()
 	ctx := ContextWithCallerZoneID(context.Background(), "zone-abc")
+	ctx = ContextWithCallerRole(ctx, cert.RoleOwner)
 
 	t.Run("MissingZoneID", func(t *testing.T) {
 		params := map[string]any{}
@@ -262,3 +266,37 @@ func TestDeviceInfo_HasRemoveZoneCommand(t *testing.T) {
 		t.Errorf("expected RemoveZone command (0x%02x) in command list, got: %v", features.DeviceInfoCmdRemoveZone, cmdList)
 	}
 }
+
+func TestRemoveZoneHandler_RejectsAdminRole(t *testing.T) {
+	device := model.NewDevice("test-device", 1234, 5678)
+	device.AddEndpoint(&model.Endpoint{})
+
+	svc := &DeviceService{
+		deviceID:       "test-device",
+		device:         device,
+		connectedZones: make(map[string]*ConnectedZone),
+		zoneSessions:   make(map[string]*ZoneSession),
+		zoneIndexMap:   make(map[string]uint8),
+		failsafeTimers: make(map[string]*failsafe.Timer),
+	}
+
+	zoneID := "zone-abc123"
+	svc.connectedZones[zoneID] = &ConnectedZone{ID: zoneID}
+
+	handler := svc.makeRemoveZoneHandler()
+
+	// An admin of the zone may not remove it; that is left to the owner.
+	ctx := ContextWithCallerZoneID(context.Background(), zoneID)
+	ctx = ContextWithCallerRole(ctx, cert.RoleAdmin)
+
+	params := map[string]any{
+		features.RemoveZoneParamZoneID: zoneID,
+	}
+	_, err := handler(ctx, params)
+	if err != model.ErrCommandNotAllowed {
+		t.Errorf("expected ErrCommandNotAllowed, got: %v", err)
+	}
+	if len(svc.connectedZones) != 1 {
+		t.Errorf("expected 1 zone, got %d", len(svc.connectedZones))
+	}
+}
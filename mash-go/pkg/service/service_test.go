@@ -5,6 +5,7 @@ import (
 	"crypto/tls"
 	"crypto/x509"
 	"errors"
+	"io"
 	"net"
 	"strings"
 	"sync"
@@ -1482,6 +1483,7 @@ func TestEventTypeString(t *testing.T) {
 		{EventDeviceRediscovered, "DEVICE_REDISCOVERED"},
 		{EventDeviceReconnected, "DEVICE_RECONNECTED"},
 		{EventReconnectionFailed, "RECONNECTION_FAILED"},
+		{EventFeatureEvent, "FEATURE_EVENT"},
 		{EventType(99), "UNKNOWN"},
 	}
 
@@ -2065,6 +2067,80 @@ func TestHandleZoneSessionClose_StaleSessionDoesNotCloseActiveSession(t *testing
 	}
 }
 
+func TestHandleOperationalConnection_AdminDoesNotEvictOwner(t *testing.T) {
+	device := model.NewDevice("test-owner-admin", 0x1234, 0x5678)
+	svc, err := NewDeviceService(device, validDeviceConfig())
+	if err != nil {
+		t.Fatalf("NewDeviceService: %v", err)
+	}
+
+	const zoneID = "zone-1"
+	zoneCA, _ := cert.GenerateZoneCA(zoneID, cert.ZoneTypeLocal)
+	store := cert.NewMemoryStore()
+	_ = store.SetZoneCACert(zoneID, zoneCA.Certificate)
+	svc.SetCertStore(store)
+
+	ownerCert, _ := cert.GenerateControllerOperationalCert(zoneCA, "owner-controller")
+	adminKey, _ := cert.GenerateKeyPair()
+	adminCSR, _ := cert.CreateAdminCSR(adminKey.PrivateKey, "installer-app")
+	adminX509, err := cert.SignAdminCSR(zoneCA, adminCSR)
+	if err != nil {
+		t.Fatalf("SignAdminCSR: %v", err)
+	}
+	adminCert := &cert.OperationalCert{Certificate: adminX509, PrivateKey: adminKey.PrivateKey, ZoneID: zoneID}
+
+	// The owner is connected
+	ownerSession := NewZoneSession(zoneID, newMockSendableConnection(), device)
+	ownerSession.SetPeerRole(cert.RoleOwner)
+	ownerSession.SetPeerName(ownerCert.Certificate.Subject.CommonName)
+	t.Cleanup(ownerSession.Close)
+	svc.mu.Lock()
+	svc.connectedZones[zoneID] = &ConnectedZone{ID: zoneID, Type: cert.ZoneTypeLocal, Connected: true}
+	svc.zoneSessions[zoneID] = ownerSession
+	svc.mu.Unlock()
+
+	// The admin connects to the same zone
+	deviceCert, _ := cert.GenerateControllerOperationalCert(zoneCA, "device")
+	serverRaw, clientRaw := net.Pipe()
+	server := tls.Server(serverRaw, &tls.Config{
+		Certificates: []tls.Certificate{deviceCert.TLSCertificate()},
+		ClientAuth:   tls.RequireAnyClientCert,
+	})
+	client := tls.Client(clientRaw, &tls.Config{
+		Certificates:       []tls.Certificate{adminCert.TLSCertificate()},
+		InsecureSkipVerify: true,
+	})
+	defer client.Close()
+	go func() {
+		// Keep reading so the device's close_notify does not block the pipe
+		if client.Handshake() == nil {
+			_, _ = io.Copy(io.Discard, client)
+		}
+	}()
+	if err := server.Handshake(); err != nil {
+		t.Fatalf("handshake: %v", err)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		svc.handleOperationalConnection(serverRaw, server, func() {})
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("admin connection was accepted in place of the owner's")
+	}
+
+	svc.mu.RLock()
+	gotSession := svc.zoneSessions[zoneID]
+	connected := svc.connectedZones[zoneID].Connected
+	svc.mu.RUnlock()
+	if gotSession != ownerSession || !connected {
+		t.Error("admin connection evicted the owner's session")
+	}
+}
+
 func TestEvictDisconnectedZone_SkipsTESTZones(t *testing.T) {
 	device := model.NewDevice("test-evict", 0x1234, 0x5678)
 	config := validDeviceConfig()
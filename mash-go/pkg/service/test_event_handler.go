@@ -288,6 +288,18 @@ func (s *DeviceService) handleCommissioningTrigger(_ context.Context, trigger ui
 		if s.limitResolver != nil {
 			s.limitResolver.ResetAll()
 		}
+		// Reset SetpointResolver state (clears per-zone setpoints and timers).
+		if s.setpointResolver != nil {
+			s.setpointResolver.ResetAll()
+		}
+		// Reset PhaseCurrentResolver state (clears per-phase limits, setpoints and timers).
+		if s.phaseCurrentResolver != nil {
+			s.phaseCurrentResolver.ResetAll()
+		}
+		// Reset ConstraintScheduler state (withdraws constraint signals and boundary timers).
+		if s.constraintScheduler != nil {
+			s.constraintScheduler.ResetAll()
+		}
 		// Reset clock offset.
 		s.clockOffset = 0
 		// Reset commissioning window: exit clears timers and mDNS, then
@@ -1,17 +1,22 @@
 package service
 
 import (
+	"crypto/ecdsa"
 	"crypto/tls"
 	"crypto/x509"
 	"errors"
 	"fmt"
 	"log/slog"
+	"net"
 	"time"
 
 	"github.com/mash-protocol/mash-go/pkg/cert"
+	"github.com/mash-protocol/mash-go/pkg/clock"
 	"github.com/mash-protocol/mash-go/pkg/commissioning"
 	"github.com/mash-protocol/mash-go/pkg/discovery"
 	"github.com/mash-protocol/mash-go/pkg/log"
+	"github.com/mash-protocol/mash-go/pkg/model"
+	"github.com/mash-protocol/mash-go/pkg/transport"
 )
 
 // Service errors.
@@ -30,6 +35,7 @@ var (
 	ErrCommissioningCancelled = errors.New("commissioning cancelled")
 	ErrNoPairingRequestActive = errors.New("no pairing request active for discriminator")
 	ErrZoneIDRequired         = errors.New("zone ID required for pairing request")
+	ErrTicketDeviceMismatch   = fmt.Errorf("%w: device does not match ticket", ErrCommissionFailed)
 )
 
 // Pairing request timing constants.
@@ -89,12 +95,30 @@ type DeviceConfig struct {
 	// OperationalListenAddress is the address for the unified TLS listener.
 	// Default: ":8443". A single listener handles both commissioning (mash-comm/1 ALPN)
 	// and operational (mash/1 ALPN) connections via GetConfigForClient routing.
+	// Besides host:port it accepts tcp4://, tcp6:// and unix:// endpoints
+	// (see transport.ParseEndpoint), e.g. "unix:///run/mash/device.sock"
+	// behind a local proxy.
 	OperationalListenAddress string
 
 	// ListenAddress is deprecated. If set and OperationalListenAddress is empty,
 	// it is used as OperationalListenAddress for backward compatibility.
 	ListenAddress string
 
+	// AdvertiseAddresses are the IP addresses advertised over mDNS as
+	// reachable. If empty, they follow the listener: one bound to a single
+	// IP advertises that IP, a tcp4:// or tcp6:// listener the interface
+	// addresses of that family, and any other all interface addresses.
+	AdvertiseAddresses []string
+
+	// AdvertisePort is the port advertised over mDNS. If 0, the listener's
+	// port is used. Set it for a unix:// listener behind a proxy.
+	AdvertisePort uint16
+
+	// MDNSInterface restricts mDNS advertising, and the interface
+	// addresses advertised for a tcp4:// or tcp6:// listener, to the named
+	// network interface. If empty, all interfaces are used.
+	MDNSInterface string
+
 	// TLSConfig provides TLS configuration for the server.
 	// If nil, the service will generate a self-signed certificate.
 	TLSConfig *tls.Config
@@ -144,6 +168,10 @@ type DeviceConfig struct {
 	// EnableAutoReconnect enables automatic reconnection to zones.
 	EnableAutoReconnect bool
 
+	// EventBufferSize is the number of feature events retained for replay to
+	// controllers that reconnect. Default: model.DefaultEventBufferSize.
+	EventBufferSize int
+
 	// Security Hardening (DEC-047)
 
 	// TLSHandshakeTimeout is the maximum time allowed for the TLS handshake to
@@ -215,6 +243,14 @@ type DeviceConfig struct {
 	// SnapshotPolicy controls when capability snapshots are emitted to the protocol log.
 	SnapshotPolicy SnapshotPolicy
 
+	// Clock is the time source for timers, expiry and heartbeats.
+	// If nil, the real clock is used. Tests can pass a *clock.Fake.
+	Clock clock.Clock
+
+	// Network provides the listener for OperationalListenAddress.
+	// If nil, real sockets are used. Tests can pass a *transport.PipeNetwork.
+	Network transport.Network
+
 	// Logger is the optional logger for debug output.
 	// If nil, logging is disabled.
 	Logger *slog.Logger
@@ -273,9 +309,27 @@ type ControllerConfig struct {
 	// Default: 5 seconds.
 	PairingRequestPollInterval time.Duration
 
+	// TicketIssuers holds the public keys of parties allowed to delegate
+	// commissioning through signed tickets, keyed by issuer name
+	// (security.md §9). If empty, CommissionFromTicket rejects every ticket.
+	TicketIssuers map[string]*ecdsa.PublicKey
+
+	// TicketCheckTimeout bounds the operational reconnection and read that
+	// compare a ticket's DeviceID with the commissioned device's.
+	// Default: 30 seconds. Zero leaves only the ticket's expiry as a bound.
+	TicketCheckTimeout time.Duration
+
 	// SnapshotPolicy controls when capability snapshots are emitted to the protocol log.
 	SnapshotPolicy SnapshotPolicy
 
+	// Clock is the time source for timers, expiry and heartbeats.
+	// If nil, the real clock is used. Tests can pass a *clock.Fake.
+	Clock clock.Clock
+
+	// Network is used to dial devices.
+	// If nil, real sockets are used. Tests can pass a *transport.PipeNetwork.
+	Network transport.Network
+
 	// Logger is the optional logger for debug output.
 	// If nil, logging is disabled.
 	Logger *slog.Logger
@@ -337,6 +391,7 @@ func DefaultDeviceConfig() DeviceConfig {
 		HeartbeatInterval:           30 * time.Second,
 		CommissioningWindowDuration: 15 * time.Minute, // DEC-048: Aligned with Matter
 		EnableAutoReconnect:         true,
+		EventBufferSize:             model.DefaultEventBufferSize,
 		ReconnectBackoff: BackoffConfig{
 			InitialInterval: 1 * time.Second,
 			MaxInterval:     5 * time.Minute,
@@ -377,6 +432,7 @@ func DefaultControllerConfig() ControllerConfig {
 		SubscriptionMaxInterval:     60 * time.Second,
 		EnableAutoReconnect:         true,
 		EnableBounceBackSuppression: true,
+		TicketCheckTimeout:          30 * time.Second,
 		ReconnectBackoff: BackoffConfig{
 			InitialInterval: 1 * time.Second,
 			MaxInterval:     5 * time.Minute,
@@ -408,6 +464,19 @@ func (c *DeviceConfig) Validate() error {
 	if len(c.Categories) == 0 {
 		return ErrInvalidConfig
 	}
+	for _, addr := range []string{c.OperationalListenAddress, c.ListenAddress} {
+		if addr == "" {
+			continue
+		}
+		if _, err := transport.ParseEndpoint(addr); err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidConfig, err)
+		}
+	}
+	for _, ip := range c.AdvertiseAddresses {
+		if net.ParseIP(ip) == nil {
+			return fmt.Errorf("%w: advertise address %q is not an IP address", ErrInvalidConfig, ip)
+		}
+	}
 	return nil
 }
 
@@ -442,6 +511,10 @@ type ConnectedDevice struct {
 	// DeviceType is the device type string (e.g., "EVSE", "INVERTER").
 	DeviceType string
 
+	// Label is an optional name the application gave the device, such as
+	// its entry in a fleet. It is persisted with the controller state.
+	Label string
+
 	// VendorProduct is the vendor:product ID if available.
 	VendorProduct string
 
@@ -555,6 +628,24 @@ const (
 
 	// EventError - an error occurred during background operations.
 	EventError
+
+	// EventFeatureEvent - a feature emitted a protocol event (§9).
+	EventFeatureEvent
+
+	// EventZoneCARotated - the zone moved to a new Zone CA.
+	EventZoneCARotated
+
+	// EventDelegatedCertIssued - the zone owner signed a device certificate
+	// for a zone admin. Value holds the admin's controller ID.
+	EventDelegatedCertIssued
+
+	// EventTicketCommissioned - a device was commissioned from a ticket.
+	// Value holds the ticket ID.
+	EventTicketCommissioned
+
+	// EventTicketFailed - commissioning from a ticket failed. Value holds
+	// the ticket ID (empty if the ticket could not be read).
+	EventTicketFailed
 )
 
 // String returns the event type name.
@@ -604,6 +695,16 @@ func (e EventType) String() string {
 		return "COMMAND_INVOKED"
 	case EventError:
 		return "ERROR"
+	case EventFeatureEvent:
+		return "FEATURE_EVENT"
+	case EventZoneCARotated:
+		return "ZONE_CA_ROTATED"
+	case EventDelegatedCertIssued:
+		return "DELEGATED_CERT_ISSUED"
+	case EventTicketCommissioned:
+		return "TICKET_COMMISSIONED"
+	case EventTicketFailed:
+		return "TICKET_FAILED"
 	default:
 		return "UNKNOWN"
 	}
@@ -638,6 +739,12 @@ type Event struct {
 	// CommandParams are the parameters passed to the command (for command events).
 	CommandParams map[string]any
 
+	// EventID identifies the emitted feature event (for feature events).
+	EventID uint8
+
+	// EventNumber is the device-wide event number (for feature events).
+	EventNumber uint64
+
 	// DiscoveredService contains the discovered service info (for discovery events).
 	DiscoveredService any
 
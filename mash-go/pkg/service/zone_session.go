@@ -7,11 +7,13 @@ import (
 	"time"
 
 	"github.com/mash-protocol/mash-go/pkg/cert"
+	"github.com/mash-protocol/mash-go/pkg/clock"
 	"github.com/mash-protocol/mash-go/pkg/commissioning"
 	"github.com/mash-protocol/mash-go/pkg/interaction"
 	"github.com/mash-protocol/mash-go/pkg/log"
 	"github.com/mash-protocol/mash-go/pkg/service/dispatch"
 	"github.com/mash-protocol/mash-go/pkg/wire"
+	"github.com/mash-protocol/mash-go/pkg/zone"
 )
 
 // ZoneSession manages a device-side session with a connected controller zone.
@@ -30,6 +32,10 @@ type ZoneSession struct {
 	closed  bool
 	logger  *slog.Logger
 
+	// peerName is the controller's certificate subject (its controller or
+	// admin ID), used to tell a reconnect from another controller.
+	peerName string
+
 	// Notification dispatcher for subscription heartbeats and coalescing
 	dispatcher       *dispatch.NotificationDispatcher
 	dispatcherConnID uint64
@@ -49,6 +55,7 @@ type ZoneSession struct {
 	// Renewal handling
 	renewalHandler       *DeviceRenewalHandler
 	onCertRenewalSuccess func(zoneID string, handler *DeviceRenewalHandler)
+	onZoneCAMessage      func(zoneID string, msg any) uint8
 }
 
 // NewZoneSession creates a new zone session.
@@ -102,8 +109,16 @@ func (s *ZoneSession) OnMessage(data []byte) {
 	snapshot := s.snapshot
 	s.mu.RUnlock()
 
-	// Check for renewal messages first (MsgType 30-33 at key 1)
+	// Check for renewal messages first (MsgType 30-36 at key 1)
 	if isRenewalMessage(data) {
+		// Certificates are only renewed and rotated by the zone owner,
+		// which holds the Zone CA.
+		if role := s.handler.PeerRole(); !zone.RoleAllows(role, zone.ActionManage) {
+			if logger != nil {
+				logger.Debug("OnMessage: renewal message rejected", "zoneID", s.zoneID, "role", role)
+			}
+			return
+		}
 		if renewalHandler != nil {
 			s.handleRenewalMessage(data, renewalHandler)
 		}
@@ -176,8 +191,8 @@ func (s *ZoneSession) handleRequest(data []byte) {
 			"status", resp.Status)
 	}
 
-	// Send response
-	respData, err := wire.EncodeResponse(resp)
+	// Send response, split into chunks if it exceeds the frame limit
+	frames, err := s.handler.EncodeResponse(resp)
 	if err != nil {
 		if s.logger != nil {
 			s.logger.Debug("handleRequest: encode failed", "zoneID", s.zoneID, "error", err)
@@ -187,12 +202,16 @@ func (s *ZoneSession) handleRequest(data []byte) {
 		return
 	}
 
-	if sendErr := s.conn.Send(respData); sendErr != nil {
-		if s.logger != nil {
-			s.logger.Debug("handleRequest: Send failed", "zoneID", s.zoneID, "error", sendErr)
+	for _, respData := range frames {
+		if sendErr := s.conn.Send(respData); sendErr != nil {
+			if s.logger != nil {
+				s.logger.Debug("handleRequest: Send failed", "zoneID", s.zoneID, "error", sendErr)
+			}
+			return
 		}
-	} else if s.logger != nil {
-		s.logger.Debug("handleRequest: response sent", "zoneID", s.zoneID, "len", len(respData))
+	}
+	if s.logger != nil {
+		s.logger.Debug("handleRequest: response sent", "zoneID", s.zoneID, "frames", len(frames))
 	}
 }
 
@@ -330,7 +349,7 @@ func (s *ZoneSession) SendNotification(notif *wire.Notification) error {
 			"changesCount", len(notif.Changes))
 	}
 
-	data, err := wire.EncodeNotification(notif)
+	frames, err := s.handler.EncodeNotification(notif)
 	if err != nil {
 		if logger != nil {
 			logger.Debug("SendNotification: encode failed",
@@ -340,13 +359,17 @@ func (s *ZoneSession) SendNotification(notif *wire.Notification) error {
 		return err
 	}
 
-	if logger != nil {
-		logger.Debug("SendNotification: sending",
-			"zoneID", s.zoneID,
-			"dataLen", len(data))
+	for _, data := range frames {
+		if logger != nil {
+			logger.Debug("SendNotification: sending",
+				"zoneID", s.zoneID,
+				"dataLen", len(data))
+		}
+		if err := s.conn.Send(data); err != nil {
+			return err
+		}
 	}
-
-	return s.conn.Send(data)
+	return nil
 }
 
 // SetZoneType sets the zone type on the underlying protocol handler.
@@ -354,6 +377,39 @@ func (s *ZoneSession) SetZoneType(zt cert.ZoneType) {
 	s.handler.SetPeerZoneType(zt)
 }
 
+// SetPeerRole sets the controller's zone role, taken from its operational
+// certificate. Requests and renewal-channel messages the role does not allow
+// are rejected.
+func (s *ZoneSession) SetPeerRole(role cert.Role) {
+	s.handler.SetPeerRole(role)
+}
+
+// PeerRole returns the controller's zone role.
+func (s *ZoneSession) PeerRole() cert.Role {
+	return s.handler.PeerRole()
+}
+
+// SetPeerName sets the controller's name, taken from its operational
+// certificate.
+func (s *ZoneSession) SetPeerName(name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.peerName = name
+}
+
+// PeerName returns the controller's name.
+func (s *ZoneSession) PeerName() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.peerName
+}
+
+// SetClock sets the time source for subscription heartbeats. Must be called
+// before the controller subscribes.
+func (s *ZoneSession) SetClock(c clock.Clock) {
+	s.dispatcher.SetClock(c)
+}
+
 // SetLogger sets the logger for this session.
 func (s *ZoneSession) SetLogger(logger *slog.Logger) {
 	s.mu.Lock()
@@ -457,8 +513,10 @@ func (s *ZoneSession) clearSubscriptions() {
 	}
 	// Fallback: recreate handler to clear subscriptions
 	device := s.handler.Device()
+	role := s.handler.PeerRole()
 	s.handler = dispatch.NewProtocolHandler(device)
 	s.handler.SetZoneID(s.zoneID)
+	s.handler.SetPeerRole(role)
 }
 
 // SetRenewalHandler sets the handler for certificate renewal messages.
@@ -487,6 +545,10 @@ func (s *ZoneSession) handleRenewalMessage(data []byte, handler *DeviceRenewalHa
 		if ack, ok := resp.(*commissioning.CertRenewalAck); ok && ack.Status == commissioning.RenewalStatusSuccess {
 			installSuccess = true
 		}
+	case *commissioning.ZoneCAUpdate:
+		resp = s.handleZoneCAMessage(m, m.ZoneCA)
+	case *commissioning.ZoneCARetire:
+		resp = s.handleZoneCAMessage(m, m.ZoneCA)
 	default:
 		// Unknown renewal message type
 		return
@@ -517,9 +579,29 @@ func (s *ZoneSession) handleRenewalMessage(data []byte, handler *DeviceRenewalHa
 	}
 }
 
-// isRenewalMessage checks if data is a renewal message (MsgType 30-33).
+// handleZoneCAMessage passes a Zone CA rotation message to the
+// onZoneCAMessage callback and builds the acknowledgment.
+func (s *ZoneSession) handleZoneCAMessage(msg any, zoneCA []byte) *commissioning.ZoneCAAck {
+	s.mu.RLock()
+	callback := s.onZoneCAMessage
+	zoneID := s.zoneID
+	s.mu.RUnlock()
+
+	status := commissioning.RenewalStatusInstallFailed
+	if callback != nil {
+		status = callback(zoneID, msg)
+	}
+
+	return &commissioning.ZoneCAAck{
+		MsgType:    commissioning.MsgZoneCAAck,
+		Status:     status,
+		ZoneCAHash: commissioning.ComputeZoneCAHash(zoneCA),
+	}
+}
+
+// isRenewalMessage checks if data is a renewal message (MsgType 30-36).
 func isRenewalMessage(data []byte) bool {
-	// Renewal messages use CBOR key 1 as MsgType (30-33) and have 2-3 keys.
+	// Renewal messages use CBOR key 1 as MsgType (30-36) and have 2-3 keys.
 	// Regular requests also use key 1 (as messageID) which can collide when
 	// the messageID counter reaches 30-33. To distinguish them, verify that
 	// key 4 (featureID, present in all requests) is absent -- renewal messages
@@ -532,13 +614,29 @@ func isRenewalMessage(data []byte) bool {
 	if _, hasKey4 := raw[4]; hasKey4 {
 		return false
 	}
+	// Response chunks carry keys 7 and 8; renewal messages never do.
+	if _, hasKey7 := raw[7]; hasKey7 {
+		return false
+	}
+	if _, hasKey8 := raw[8]; hasKey8 {
+		return false
+	}
 	// Now safe to try full decode.
 	msg, err := commissioning.DecodeRenewalMessage(data)
 	if err != nil {
 		return false
 	}
 	msgType := commissioning.RenewalMessageType(msg)
-	return msgType >= commissioning.MsgCertRenewalRequest && msgType <= commissioning.MsgCertRenewalAck
+	if msgType >= commissioning.MsgZoneCAUpdate {
+		// Zone CA messages only use key 1 and keys 10 and up; a response
+		// with messageID 34-36 always has key 2 (status).
+		for key := range raw {
+			if key != 1 && key < 10 {
+				return false
+			}
+		}
+	}
+	return msgType >= commissioning.MsgCertRenewalRequest && msgType <= commissioning.MsgZoneCAAck
 }
 
 // InitializeRenewalHandler creates and sets a renewal handler with the given identity.
@@ -562,6 +660,15 @@ func (s *ZoneSession) SetOnCertRenewalSuccess(callback func(zoneID string, handl
 	s.onCertRenewalSuccess = callback
 }
 
+// SetOnZoneCAMessage sets the callback that applies Zone CA rotation
+// messages (*commissioning.ZoneCAUpdate or *commissioning.ZoneCARetire). It
+// returns the RenewalStatus* code sent back in the ZoneCAAck.
+func (s *ZoneSession) SetOnZoneCAMessage(callback func(zoneID string, msg any) uint8) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.onZoneCAMessage = callback
+}
+
 // ============================================================================
 // Bidirectional Support: Methods for sending requests to controller
 // ============================================================================
@@ -4,6 +4,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/mash-protocol/mash-go/pkg/cert"
 	"github.com/mash-protocol/mash-go/pkg/features"
 	"github.com/mash-protocol/mash-go/pkg/model"
 	"github.com/mash-protocol/mash-go/pkg/wire"
@@ -53,6 +54,7 @@ func TestZoneSession_SubscribeDispatchesHeartbeat(t *testing.T) {
 	device := createTestDeviceWithMeasurement()
 	conn := newMockSendableConnection()
 	session := NewZoneSession("zone-1", conn, device)
+	session.SetPeerRole(cert.RoleOwner)
 	defer session.Close()
 
 	// Subscribe to Measurement (endpoint 1) with short maxInterval for heartbeat
@@ -119,6 +121,7 @@ func TestZoneSession_SubscribeCoalesces(t *testing.T) {
 	device := createTestDeviceWithMeasurement()
 	conn := newMockSendableConnection()
 	session := NewZoneSession("zone-1", conn, device)
+	session.SetPeerRole(cert.RoleOwner)
 	defer session.Close()
 
 	// Subscribe with minInterval=200ms to allow coalescing
@@ -185,6 +188,7 @@ func TestZoneSession_MultipleSubscriptionsIndependent(t *testing.T) {
 	device := createTestDeviceWithMeasurement()
 	conn := newMockSendableConnection()
 	session := NewZoneSession("zone-1", conn, device)
+	session.SetPeerRole(cert.RoleOwner)
 	defer session.Close()
 
 	// Subscribe twice to Measurement (different subscription IDs)
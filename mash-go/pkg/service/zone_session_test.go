@@ -7,6 +7,8 @@ import (
 	"testing"
 	"time"
 
+	"github.com/mash-protocol/mash-go/pkg/cert"
+	"github.com/mash-protocol/mash-go/pkg/commissioning"
 	"github.com/mash-protocol/mash-go/pkg/log"
 	"github.com/mash-protocol/mash-go/pkg/model"
 	"github.com/mash-protocol/mash-go/pkg/wire"
@@ -70,6 +72,7 @@ func TestZoneSession_HandleReadRequest(t *testing.T) {
 
 	// Create zone session
 	session := NewZoneSession("zone-1", conn, device)
+	session.SetPeerRole(cert.RoleOwner)
 
 	// Create a read request for DeviceInfo (endpoint 0, feature 6)
 	req := &wire.Request{
@@ -122,6 +125,7 @@ func TestZoneSession_HandleReadRequest_InvalidEndpoint(t *testing.T) {
 	device := createTestDevice()
 	conn := newMockSendableConnection()
 	session := NewZoneSession("zone-1", conn, device)
+	session.SetPeerRole(cert.RoleOwner)
 
 	// Request for non-existent endpoint
 	req := &wire.Request{
@@ -151,6 +155,7 @@ func TestZoneSession_HandleWriteRequest(t *testing.T) {
 	device := createTestDevice()
 	conn := newMockSendableConnection()
 	session := NewZoneSession("zone-1", conn, device)
+	session.SetPeerRole(cert.RoleOwner)
 
 	// Write request - note: most DeviceInfo attributes are read-only
 	// This test verifies the flow even if the write is rejected
@@ -202,6 +207,7 @@ func TestZoneSession_InvokeResponseWaitsForCommandCompletion(t *testing.T) {
 
 	conn := newMockSendableConnection()
 	session := NewZoneSession("zone-1", conn, device)
+	session.SetPeerRole(cert.RoleOwner)
 
 	req := &wire.Request{
 		MessageID:  99,
@@ -252,6 +258,7 @@ func TestZoneSession_HandleSubscribeRequest(t *testing.T) {
 	device := createTestDevice()
 	conn := newMockSendableConnection()
 	session := NewZoneSession("zone-1", conn, device)
+	session.SetPeerRole(cert.RoleOwner)
 
 	// Subscribe request
 	req := &wire.Request{
@@ -287,6 +294,7 @@ func TestZoneSession_Close(t *testing.T) {
 	device := createTestDevice()
 	conn := newMockSendableConnection()
 	session := NewZoneSession("zone-1", conn, device)
+	session.SetPeerRole(cert.RoleOwner)
 
 	// Create a subscription first
 	req := &wire.Request{
@@ -318,6 +326,7 @@ func TestZoneSession_ZoneID(t *testing.T) {
 	device := createTestDevice()
 	conn := newMockSendableConnection()
 	session := NewZoneSession("my-zone-id", conn, device)
+	session.SetPeerRole(cert.RoleOwner)
 
 	if session.ZoneID() != "my-zone-id" {
 		t.Errorf("Expected zone ID 'my-zone-id', got %q", session.ZoneID())
@@ -328,6 +337,7 @@ func TestZoneSession_HandleMultipleRequests(t *testing.T) {
 	device := createTestDevice()
 	conn := newMockSendableConnection()
 	session := NewZoneSession("zone-1", conn, device)
+	session.SetPeerRole(cert.RoleOwner)
 
 	// Send multiple read requests
 	for i := uint32(1); i <= 3; i++ {
@@ -425,6 +435,7 @@ func TestZoneSession_OnMessage_HandlesResponse(t *testing.T) {
 	device := createTestDevice()
 	conn := newMockBidirectionalConnection()
 	session := NewZoneSession("zone-1", conn, device)
+	session.SetPeerRole(cert.RoleOwner)
 
 	// Simulate that we sent a request with MessageID 42 and are awaiting response
 	// First, we need to trigger the client to wait for a response
@@ -471,10 +482,60 @@ func TestZoneSession_OnMessage_HandlesResponse(t *testing.T) {
 	}
 }
 
+func TestIsRenewalMessage_ResponseIDsInZoneCARange(t *testing.T) {
+	hash := commissioning.ComputeZoneCAHash([]byte{0x30, 0x82})
+	for _, id := range []uint32{
+		uint32(commissioning.MsgZoneCAUpdate),
+		uint32(commissioning.MsgZoneCARetire),
+		uint32(commissioning.MsgZoneCAAck),
+	} {
+		// A plain response, one whose payload looks like a Zone CA hash,
+		// and every chunk of a large response
+		frames := make([][]byte, 0, 8)
+		for _, payload := range []any{nil, hash} {
+			data, err := wire.EncodeResponse(&wire.Response{MessageID: id, Status: wire.StatusSuccess, Payload: payload})
+			if err != nil {
+				t.Fatal(err)
+			}
+			frames = append(frames, data)
+		}
+		chunks, err := wire.EncodeResponseChunks(&wire.Response{
+			MessageID: id,
+			Status:    wire.StatusSuccess,
+			Payload:   map[uint16]any{1: make([]byte, 20*1024)},
+		}, 4096)
+		if err != nil {
+			t.Fatal(err)
+		}
+		frames = append(frames, chunks...)
+
+		for i, data := range frames {
+			if isRenewalMessage(data) {
+				t.Errorf("messageID %d: frame %d detected as renewal message", id, i)
+			}
+		}
+	}
+
+	for _, msg := range []any{
+		&commissioning.ZoneCAUpdate{MsgType: commissioning.MsgZoneCAUpdate, ZoneCA: []byte{0x30}, CrossCert: []byte{0x30}},
+		&commissioning.ZoneCARetire{MsgType: commissioning.MsgZoneCARetire, ZoneCA: []byte{0x30}},
+		&commissioning.ZoneCAAck{MsgType: commissioning.MsgZoneCAAck, Status: commissioning.RenewalStatusSuccess, ZoneCAHash: hash},
+	} {
+		data, err := commissioning.EncodeRenewalMessage(msg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !isRenewalMessage(data) {
+			t.Errorf("%T not detected as renewal message", msg)
+		}
+	}
+}
+
 func TestZoneSession_OnMessage_HandlesNotification(t *testing.T) {
 	device := createTestDevice()
 	conn := newMockSendableConnection()
 	session := NewZoneSession("zone-1", conn, device)
+	session.SetPeerRole(cert.RoleOwner)
 
 	// Set up notification handler
 	var receivedNotif *wire.Notification
@@ -521,6 +582,7 @@ func TestZoneSession_Read_SendsRequestAndReceivesResponse(t *testing.T) {
 	device := createTestDevice()
 	conn := newMockBidirectionalConnection()
 	session := NewZoneSession("zone-1", conn, device)
+	session.SetPeerRole(cert.RoleOwner)
 
 	// Auto-respond to read requests
 	conn.SetOnSend(func(data []byte) {
@@ -559,6 +621,7 @@ func TestZoneSession_Write_SendsRequestAndReceivesResponse(t *testing.T) {
 	device := createTestDevice()
 	conn := newMockBidirectionalConnection()
 	session := NewZoneSession("zone-1", conn, device)
+	session.SetPeerRole(cert.RoleOwner)
 
 	var capturedReq *wire.Request
 	conn.SetOnSend(func(data []byte) {
@@ -608,6 +671,7 @@ func TestZoneSession_ClearSubscriptions(t *testing.T) {
 	device := createTestDevice()
 	conn := newMockBidirectionalConnection()
 	session := NewZoneSession("zone-1", conn, device)
+	session.SetPeerRole(cert.RoleOwner)
 
 	// Create an inbound subscription via handleRequest (subscribe request).
 	subscribeReq := &wire.Request{
@@ -652,6 +716,7 @@ func TestZoneSession_Subscribe_TracksInSubscriptionManager(t *testing.T) {
 	device := createTestDevice()
 	conn := newMockBidirectionalConnection()
 	session := NewZoneSession("zone-1", conn, device)
+	session.SetPeerRole(cert.RoleOwner)
 
 	conn.SetOnSend(func(data []byte) {
 		req, err := wire.DecodeRequest(data)
@@ -695,6 +760,7 @@ func TestZoneSession_Invoke_SendsRequestAndReceivesResponse(t *testing.T) {
 	device := createTestDevice()
 	conn := newMockBidirectionalConnection()
 	session := NewZoneSession("zone-1", conn, device)
+	session.SetPeerRole(cert.RoleOwner)
 
 	var capturedReq *wire.Request
 	conn.SetOnSend(func(data []byte) {
@@ -744,6 +810,7 @@ func TestZoneSession_ConcurrentRequests(t *testing.T) {
 	device := createTestDevice()
 	conn := newMockBidirectionalConnection()
 	session := NewZoneSession("zone-1", conn, device)
+	session.SetPeerRole(cert.RoleOwner)
 
 	// Track message IDs to ensure each request gets its own response
 	var messageIDsMu sync.Mutex
@@ -822,6 +889,7 @@ func TestZoneSession_RequestTimeout(t *testing.T) {
 	device := createTestDevice()
 	conn := newMockBidirectionalConnection()
 	session := NewZoneSession("zone-1", conn, device)
+	session.SetPeerRole(cert.RoleOwner)
 	session.SetTimeout(50 * time.Millisecond) // Short timeout for test
 
 	// Don't send any response - request should timeout
@@ -842,6 +910,7 @@ func TestZoneSession_NotificationHandlerCalled(t *testing.T) {
 	device := createTestDevice()
 	conn := newMockSendableConnection()
 	session := NewZoneSession("zone-1", conn, device)
+	session.SetPeerRole(cert.RoleOwner)
 
 	var callCount int32
 	session.SetNotificationHandler(func(notif *wire.Notification) {
@@ -872,6 +941,7 @@ func TestZoneSession_CloseStopsClient(t *testing.T) {
 	device := createTestDevice()
 	conn := newMockBidirectionalConnection()
 	session := NewZoneSession("zone-1", conn, device)
+	session.SetPeerRole(cert.RoleOwner)
 
 	// Start a request in background
 	errCh := make(chan error, 1)
@@ -907,6 +977,7 @@ func TestZoneSession_EmitsInitialSnapshot(t *testing.T) {
 	device := createTestDevice()
 	conn := newMockSendableConnection()
 	session := NewZoneSession("zone-1", conn, device)
+	session.SetPeerRole(cert.RoleOwner)
 
 	logger := &snapshotCapturingLogger{}
 	session.SetSnapshotPolicy(SnapshotPolicy{MaxMessages: 1000})
@@ -941,6 +1012,7 @@ func TestZoneSession_SnapshotOnMessageCount(t *testing.T) {
 	device := createTestDevice()
 	conn := newMockSendableConnection()
 	session := NewZoneSession("zone-1", conn, device)
+	session.SetPeerRole(cert.RoleOwner)
 
 	logger := &snapshotCapturingLogger{}
 	// Each request generates 2 handler log events (logRequest + logResponse).
@@ -976,6 +1048,7 @@ func TestZoneSession_NoSnapshotWithoutLogger(t *testing.T) {
 	device := createTestDevice()
 	conn := newMockSendableConnection()
 	session := NewZoneSession("zone-1", conn, device)
+	session.SetPeerRole(cert.RoleOwner)
 
 	// No SetProtocolLogger call - process messages normally, should not panic.
 	for i := uint32(1); i <= 5; i++ {
@@ -995,6 +1068,7 @@ func TestZoneSession_IncomingRequestStillWorks(t *testing.T) {
 	device := createTestDevice()
 	conn := newMockBidirectionalConnection()
 	session := NewZoneSession("zone-1", conn, device)
+	session.SetPeerRole(cert.RoleOwner)
 
 	// Send an incoming read request (from controller)
 	req := &wire.Request{
@@ -1031,6 +1105,7 @@ func TestZoneSession_InvalidCBORReturnsError(t *testing.T) {
 	device := createTestDevice()
 	conn := newMockSendableConnection()
 	session := NewZoneSession("zone-1", conn, device)
+	session.SetPeerRole(cert.RoleOwner)
 
 	// Send invalid CBOR with duplicate keys:
 	// A3 01 01 01 02 02 03 = map(3) with key 1 appearing twice
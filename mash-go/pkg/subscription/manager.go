@@ -3,6 +3,8 @@ package subscription
 import (
 	"sync"
 	"time"
+
+	"github.com/mash-protocol/mash-go/pkg/clock"
 )
 
 // Notification represents a subscription notification to send.
@@ -36,6 +38,9 @@ type Manager struct {
 	// Configuration
 	config Config
 
+	// Time source for subscriptions and notification timestamps
+	clock clock.Clock
+
 	// Active subscriptions by ID
 	subscriptions map[uint32]*Subscription
 
@@ -68,6 +73,7 @@ func NewManagerWithConfig(config Config) *Manager {
 
 	return &Manager{
 		config:        config,
+		clock:         clock.OrReal(config.Clock),
 		subscriptions: make(map[uint32]*Subscription),
 		featureIndex:  make(map[featureKey][]*Subscription),
 	}
@@ -108,7 +114,7 @@ func (m *Manager) Subscribe(
 
 	// Create subscription
 	id := nextID()
-	sub := NewSubscription(id, featureID, endpointID, attributeIDs, minInterval, maxInterval)
+	sub := newSubscription(m.clock, id, featureID, endpointID, attributeIDs, minInterval, maxInterval)
 
 	// Filter current values to subscribed attributes
 	primingValues := filterAttributes(currentValues, attributeIDs)
@@ -134,13 +140,73 @@ func (m *Manager) Subscribe(
 			EndpointID:     endpointID,
 			Attributes:     primingValues,
 			IsPriming:      true,
-			Timestamp:      time.Now(),
+			Timestamp:      m.clock.Now(),
 		})
 	}
 
 	return id, nil
 }
 
+// SubscribeEvents creates an event-only subscription and returns its ID.
+// Event-only subscriptions receive no priming report, attribute changes or
+// heartbeats; events are matched with MatchEvent and delivered by the caller.
+func (m *Manager) SubscribeEvents(endpointID, featureID uint16, eventIDs []uint8) (uint32, error) {
+	if len(eventIDs) == 0 {
+		return 0, ErrNoEvents
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if len(m.subscriptions) >= m.config.MaxSubscriptions {
+		return 0, ErrResourceExhausted
+	}
+
+	id := nextID()
+	sub := newSubscription(m.clock, id, featureID, endpointID, nil, 0, 0)
+	sub.EventIDs = eventIDs
+	sub.eventsOnly = true
+
+	m.subscriptions[id] = sub
+	key := featureKey{endpointID: endpointID, featureID: featureID}
+	m.featureIndex[key] = append(m.featureIndex[key], sub)
+
+	return id, nil
+}
+
+// SetEventIDs adds event delivery to an existing attribute subscription.
+func (m *Manager) SetEventIDs(subscriptionID uint32, eventIDs []uint8) error {
+	m.mu.RLock()
+	sub, exists := m.subscriptions[subscriptionID]
+	m.mu.RUnlock()
+	if !exists {
+		return ErrSubscriptionNotFound
+	}
+
+	sub.mu.Lock()
+	sub.EventIDs = eventIDs
+	sub.mu.Unlock()
+	return nil
+}
+
+// MatchEvent returns the IDs of subscriptions that include the given event.
+// Unlike attribute changes, events are not coalesced: the caller delivers
+// each event to every matching subscription immediately.
+func (m *Manager) MatchEvent(endpointID, featureID uint16, eventID uint8) []uint32 {
+	m.mu.RLock()
+	key := featureKey{endpointID: endpointID, featureID: featureID}
+	subs := m.featureIndex[key]
+	m.mu.RUnlock()
+
+	var ids []uint32
+	for _, sub := range subs {
+		if sub.MatchesEvent(eventID) {
+			ids = append(ids, sub.ID)
+		}
+	}
+	return ids
+}
+
 // Unsubscribe removes a subscription.
 func (m *Manager) Unsubscribe(subscriptionID uint32) error {
 	m.mu.Lock()
@@ -221,7 +287,7 @@ func (m *Manager) ProcessNotifications() {
 				FeatureID:      sub.FeatureID,
 				EndpointID:     sub.EndpointID,
 				Attributes:     attrs,
-				Timestamp:      time.Now(),
+				Timestamp:      m.clock.Now(),
 			})
 		}
 
@@ -232,7 +298,7 @@ func (m *Manager) ProcessNotifications() {
 				FeatureID:      sub.FeatureID,
 				EndpointID:     sub.EndpointID,
 				IsHeartbeat:    true,
-				Timestamp:      time.Now(),
+				Timestamp:      m.clock.Now(),
 			}
 
 			if config.HeartbeatMode == HeartbeatFull {
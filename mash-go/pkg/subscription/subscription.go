@@ -5,6 +5,8 @@ import (
 	"sync"
 	"sync/atomic"
 	"time"
+
+	"github.com/mash-protocol/mash-go/pkg/clock"
 )
 
 // Subscription errors.
@@ -13,6 +15,7 @@ var (
 	ErrResourceExhausted   = errors.New("maximum subscriptions reached")
 	ErrSubscriptionNotFound = errors.New("subscription not found")
 	ErrInvalidAttributeID  = errors.New("invalid attribute ID")
+	ErrNoEvents            = errors.New("no event IDs given")
 )
 
 // Default subscription limits.
@@ -62,6 +65,10 @@ type Config struct {
 
 	// AutoCorrectIntervals swaps min/max if min > max.
 	AutoCorrectIntervals bool
+
+	// Clock is the time source for coalescing windows and heartbeats.
+	// Nil uses the real clock.
+	Clock clock.Clock
 }
 
 // DefaultConfig returns the default subscription configuration.
@@ -91,6 +98,9 @@ type Subscription struct {
 	// AttributeIDs lists subscribed attributes (empty = all).
 	AttributeIDs []uint16
 
+	// EventIDs lists subscribed events (empty = no events).
+	EventIDs []uint8
+
 	// MinInterval is the minimum time between notifications.
 	MinInterval time.Duration
 
@@ -112,12 +122,24 @@ type Subscription struct {
 	// hasChanges indicates pending changes exist.
 	hasChanges bool
 
+	// eventsOnly indicates the subscription carries events but no attributes.
+	eventsOnly bool
+
 	// active indicates if subscription is active.
 	active bool
+
+	// clock is the time source for coalescing and heartbeats.
+	clock clock.Clock
 }
 
 // NewSubscription creates a new subscription.
 func NewSubscription(id uint32, featureID, endpointID uint16, attributeIDs []uint16, minInterval, maxInterval time.Duration) *Subscription {
+	return newSubscription(clock.Real(), id, featureID, endpointID, attributeIDs, minInterval, maxInterval)
+}
+
+// newSubscription creates a subscription that reads time from c.
+func newSubscription(c clock.Clock, id uint32, featureID, endpointID uint16, attributeIDs []uint16, minInterval, maxInterval time.Duration) *Subscription {
+	c = clock.OrReal(c)
 	return &Subscription{
 		ID:             id,
 		FeatureID:      featureID,
@@ -125,10 +147,11 @@ func NewSubscription(id uint32, featureID, endpointID uint16, attributeIDs []uin
 		AttributeIDs:   attributeIDs,
 		MinInterval:    minInterval,
 		MaxInterval:    maxInterval,
-		lastNotified:   time.Now(),
+		lastNotified:   c.Now(),
 		lastValues:     make(map[uint16]any),
 		pendingChanges: make(map[uint16]any),
 		active:         true,
+		clock:          c,
 	}
 }
 
@@ -152,7 +175,7 @@ func (s *Subscription) RecordChange(attrID uint16, value any) bool {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	if !s.active {
+	if !s.active || s.eventsOnly {
 		return false
 	}
 
@@ -173,7 +196,7 @@ func (s *Subscription) RecordChange(attrID uint16, value any) bool {
 	// Start coalescing window if this is first change
 	isNewWindow := !s.hasChanges
 	if isNewWindow {
-		s.changeWindowStart = time.Now()
+		s.changeWindowStart = s.clock.Now()
 	}
 
 	s.pendingChanges[attrID] = value
@@ -194,7 +217,7 @@ func (s *Subscription) GetPendingNotification(suppressBounceBack bool) map[uint1
 	}
 
 	// Check if coalescing window has elapsed
-	if time.Since(s.changeWindowStart) < s.MinInterval {
+	if s.clock.Since(s.changeWindowStart) < s.MinInterval {
 		return nil
 	}
 
@@ -217,7 +240,7 @@ func (s *Subscription) GetPendingNotification(suppressBounceBack bool) map[uint1
 	// Clear pending changes
 	s.pendingChanges = make(map[uint16]any)
 	s.hasChanges = false
-	s.lastNotified = time.Now()
+	s.lastNotified = s.clock.Now()
 
 	if len(notification) == 0 {
 		return nil // All changes were bounce-backs
@@ -231,18 +254,41 @@ func (s *Subscription) NeedsHeartbeat() bool {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
-	if !s.active {
+	if !s.active || s.eventsOnly {
 		return false
 	}
 
-	return time.Since(s.lastNotified) >= s.MaxInterval
+	return s.clock.Since(s.lastNotified) >= s.MaxInterval
+}
+
+// IsEventsOnly returns true if the subscription carries events but no attributes.
+func (s *Subscription) IsEventsOnly() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.eventsOnly
+}
+
+// MatchesEvent returns true if the subscription includes the given event.
+func (s *Subscription) MatchesEvent(eventID uint8) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if !s.active {
+		return false
+	}
+	for _, id := range s.EventIDs {
+		if id == eventID {
+			return true
+		}
+	}
+	return false
 }
 
 // RecordHeartbeat records that a heartbeat was sent.
 func (s *Subscription) RecordHeartbeat() {
 	s.mu.Lock()
 	defer s.mu.Unlock()
-	s.lastNotified = time.Now()
+	s.lastNotified = s.clock.Now()
 }
 
 // SetPrimingValues sets the initial values from priming notification.
@@ -253,14 +299,14 @@ func (s *Subscription) SetPrimingValues(values map[uint16]any) {
 	for attrID, value := range values {
 		s.lastValues[attrID] = value
 	}
-	s.lastNotified = time.Now()
+	s.lastNotified = s.clock.Now()
 }
 
 // TimeSinceLastNotification returns time since the last notification.
 func (s *Subscription) TimeSinceLastNotification() time.Duration {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
-	return time.Since(s.lastNotified)
+	return s.clock.Since(s.lastNotified)
 }
 
 // TimeUntilCoalesceExpiry returns time until coalescing window expires.
@@ -273,7 +319,7 @@ func (s *Subscription) TimeUntilCoalesceExpiry() time.Duration {
 		return 0
 	}
 
-	elapsed := time.Since(s.changeWindowStart)
+	elapsed := s.clock.Since(s.changeWindowStart)
 	if elapsed >= s.MinInterval {
 		return 0
 	}
@@ -4,6 +4,8 @@ import (
 	"sync"
 	"testing"
 	"time"
+
+	"github.com/mash-protocol/mash-go/pkg/clock"
 )
 
 func TestSubscriptionBasic(t *testing.T) {
@@ -399,6 +401,44 @@ func TestManagerHeartbeat(t *testing.T) {
 	}
 }
 
+func TestManagerHeartbeatWithFakeClock(t *testing.T) {
+	fc := clock.NewFake(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+	config := DefaultConfig()
+	config.Clock = fc
+	m := NewManagerWithConfig(config)
+
+	var notifications []Notification
+	m.OnNotification(func(n Notification) {
+		notifications = append(notifications, n)
+	})
+
+	_, err := m.Subscribe(1, 0x0003, nil, time.Second, 60*time.Second, map[uint16]any{20: int64(5000000)})
+	if err != nil {
+		t.Fatalf("Subscribe() error = %v", err)
+	}
+	if len(notifications) != 1 || !notifications[0].IsPriming {
+		t.Fatalf("Expected 1 priming notification, got %d", len(notifications))
+	}
+
+	fc.Advance(59 * time.Second)
+	m.ProcessNotifications()
+	if len(notifications) != 1 {
+		t.Fatalf("Heartbeat sent early: got %d notifications", len(notifications))
+	}
+
+	fc.Advance(time.Second)
+	m.ProcessNotifications()
+	if len(notifications) != 2 {
+		t.Fatalf("Expected heartbeat at maxInterval, got %d notifications", len(notifications))
+	}
+	if !notifications[1].IsHeartbeat {
+		t.Error("Second notification should be heartbeat")
+	}
+	if want := fc.Now(); !notifications[1].Timestamp.Equal(want) {
+		t.Errorf("Heartbeat timestamp = %v, want %v", notifications[1].Timestamp, want)
+	}
+}
+
 func TestManagerClearAll(t *testing.T) {
 	m := NewManager()
 
@@ -491,3 +531,66 @@ func TestPartialAttributeSubscription(t *testing.T) {
 		t.Error("Priming should contain attr 20")
 	}
 }
+
+func TestManagerSubscribeEvents(t *testing.T) {
+	m := NewManager()
+
+	var notifications []Notification
+	m.OnNotification(func(n Notification) {
+		notifications = append(notifications, n)
+	})
+
+	id, err := m.SubscribeEvents(1, 0x0006, []uint8{1, 2})
+	if err != nil {
+		t.Fatalf("SubscribeEvents() error = %v", err)
+	}
+
+	sub, _ := m.Get(id)
+	if !sub.IsEventsOnly() {
+		t.Error("expected event-only subscription")
+	}
+
+	// Attribute changes and heartbeats must not reach event-only subscriptions
+	m.NotifyChange(1, 0x0006, 1, int64(1))
+	sub.mu.Lock()
+	sub.lastNotified = time.Now().Add(-time.Hour)
+	sub.mu.Unlock()
+	m.ProcessNotifications()
+	if len(notifications) != 0 {
+		t.Errorf("expected no notifications, got %d", len(notifications))
+	}
+
+	if got := m.MatchEvent(1, 0x0006, 2); len(got) != 1 || got[0] != id {
+		t.Errorf("MatchEvent(2) = %v, want [%d]", got, id)
+	}
+	if got := m.MatchEvent(1, 0x0006, 3); len(got) != 0 {
+		t.Errorf("MatchEvent(3) = %v, want none", got)
+	}
+	if got := m.MatchEvent(2, 0x0006, 1); len(got) != 0 {
+		t.Errorf("MatchEvent on other endpoint = %v, want none", got)
+	}
+
+	if _, err := m.SubscribeEvents(1, 0x0006, nil); err != ErrNoEvents {
+		t.Errorf("SubscribeEvents(nil) error = %v, want ErrNoEvents", err)
+	}
+}
+
+func TestManagerSetEventIDs(t *testing.T) {
+	m := NewManager()
+
+	id, _ := m.Subscribe(1, 0x0006, nil, time.Second, 60*time.Second, nil)
+	if got := m.MatchEvent(1, 0x0006, 1); len(got) != 0 {
+		t.Errorf("MatchEvent before SetEventIDs = %v, want none", got)
+	}
+
+	if err := m.SetEventIDs(id, []uint8{1}); err != nil {
+		t.Fatalf("SetEventIDs() error = %v", err)
+	}
+	if got := m.MatchEvent(1, 0x0006, 1); len(got) != 1 {
+		t.Errorf("MatchEvent after SetEventIDs = %v, want [%d]", got, id)
+	}
+
+	if err := m.SetEventIDs(9999, []uint8{1}); err != ErrSubscriptionNotFound {
+		t.Errorf("SetEventIDs(unknown) error = %v, want ErrSubscriptionNotFound", err)
+	}
+}
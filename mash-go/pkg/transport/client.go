@@ -72,7 +72,8 @@ func NewClient(config ClientConfig) (*Client, error) {
 	}, nil
 }
 
-// Connect establishes a connection to the specified address.
+// Connect establishes a connection to the specified address, which may be
+// a TCP host:port or any endpoint accepted by ParseEndpoint.
 func (c *Client) Connect(ctx context.Context, address string) (*ClientConn, error) {
 	// Apply timeout from config if context doesn't have one
 	if _, hasDeadline := ctx.Deadline(); !hasDeadline {
@@ -82,8 +83,7 @@ func (c *Client) Connect(ctx context.Context, address string) (*ClientConn, erro
 	}
 
 	// Dial TCP connection
-	dialer := &net.Dialer{}
-	conn, err := dialer.DialContext(ctx, "tcp", address)
+	conn, err := Sockets().DialContext(ctx, address)
 	if err != nil {
 		return nil, fmt.Errorf("dial failed: %w", err)
 	}
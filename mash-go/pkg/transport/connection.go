@@ -150,7 +150,8 @@ func (c *Connection) State() ConnectionState {
 	return ConnectionState(c.state.Load())
 }
 
-// Connect establishes a connection to the specified address.
+// Connect establishes a connection to the specified address, which may be
+// a TCP host:port or any endpoint accepted by ParseEndpoint.
 func (c *Connection) Connect(ctx context.Context, address string) error {
 	if !c.state.CompareAndSwap(int32(StateDisconnected), int32(StateConnecting)) {
 		return ErrAlreadyConnected
@@ -164,8 +165,7 @@ func (c *Connection) Connect(ctx context.Context, address string) error {
 	c.notifyStateChange(StateDisconnected, StateConnecting)
 
 	// Dial with timeout from context
-	dialer := &net.Dialer{}
-	conn, err := dialer.DialContext(ctx, "tcp", address)
+	conn, err := Sockets().DialContext(ctx, address)
 	if err != nil {
 		c.state.Store(int32(StateDisconnected))
 		c.notifyStateChange(StateConnecting, StateDisconnected)
@@ -39,4 +39,12 @@
 //   - Pong timeout: 5 seconds
 //   - Max missed pongs: 3
 //   - Maximum detection delay: 95 seconds
+//
+// # Networks
+//
+// Services listen and dial through a Network. Sockets is the default and
+// accepts plain host:port TCP addresses as well as tcp4://, tcp6:// and
+// unix:// endpoints (see ParseEndpoint). PipeNetwork is an in-process network of net.Pipe connections that runs
+// the same TLS 1.3 handshake, ALPN routing and framing without sockets, so
+// tests can simulate a whole fleet of devices in one process.
 package transport
@@ -0,0 +1,66 @@
+package transport
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+)
+
+// Endpoint schemes accepted by ParseEndpoint.
+const (
+	SchemeTCP  = "tcp"
+	SchemeTCP4 = "tcp4"
+	SchemeTCP6 = "tcp6"
+	SchemeUnix = "unix"
+)
+
+// ErrInvalidEndpoint is returned for malformed listen or dial addresses.
+var ErrInvalidEndpoint = errors.New("invalid endpoint")
+
+// Endpoint is a listen or dial address with its network.
+type Endpoint struct {
+	// Network is the net package network: "tcp", "tcp4", "tcp6" or "unix".
+	Network string
+
+	// Address is host:port for TCP networks or a socket path for "unix".
+	Address string
+}
+
+// ParseEndpoint parses a listen or dial address. A plain host:port is
+// TCP; otherwise the address carries a scheme:
+//
+//	tcp://[::]:8443         TCP, IPv6 and IPv4
+//	tcp6://[fe80::1]:8443   TCP over IPv6 only
+//	tcp4://0.0.0.0:8443     TCP over IPv4 only
+//	unix:///run/mash.sock   Unix domain socket
+func ParseEndpoint(s string) (Endpoint, error) {
+	scheme, rest, ok := strings.Cut(s, "://")
+	if !ok {
+		scheme, rest = SchemeTCP, s
+	}
+
+	switch scheme {
+	case SchemeTCP, SchemeTCP4, SchemeTCP6:
+		if _, _, err := net.SplitHostPort(rest); err != nil {
+			return Endpoint{}, fmt.Errorf("%w: %q: %v", ErrInvalidEndpoint, s, err)
+		}
+	case SchemeUnix:
+		if rest == "" {
+			return Endpoint{}, fmt.Errorf("%w: %q: empty socket path", ErrInvalidEndpoint, s)
+		}
+	default:
+		return Endpoint{}, fmt.Errorf("%w: %q: unknown scheme %q", ErrInvalidEndpoint, s, scheme)
+	}
+	return Endpoint{Network: scheme, Address: rest}, nil
+}
+
+// IsTCP returns true for the TCP networks.
+func (e Endpoint) IsTCP() bool {
+	return e.Network != SchemeUnix
+}
+
+// String returns the endpoint in scheme://address form.
+func (e Endpoint) String() string {
+	return e.Network + "://" + e.Address
+}
@@ -0,0 +1,42 @@
+package transport
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseEndpoint(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    Endpoint
+		wantErr bool
+	}{
+		{in: ":8443", want: Endpoint{Network: "tcp", Address: ":8443"}},
+		{in: "[fe80::1]:8443", want: Endpoint{Network: "tcp", Address: "[fe80::1]:8443"}},
+		{in: "tcp://localhost:0", want: Endpoint{Network: "tcp", Address: "localhost:0"}},
+		{in: "tcp4://0.0.0.0:8443", want: Endpoint{Network: "tcp4", Address: "0.0.0.0:8443"}},
+		{in: "tcp6://[::]:8443", want: Endpoint{Network: "tcp6", Address: "[::]:8443"}},
+		{in: "unix:///run/mash.sock", want: Endpoint{Network: "unix", Address: "/run/mash.sock"}},
+		{in: "localhost", wantErr: true},
+		{in: "tcp4://0.0.0.0", wantErr: true},
+		{in: "unix://", wantErr: true},
+		{in: "udp://[::]:5353", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseEndpoint(tt.in)
+		if tt.wantErr {
+			if !errors.Is(err, ErrInvalidEndpoint) {
+				t.Errorf("ParseEndpoint(%q) err = %v, want ErrInvalidEndpoint", tt.in, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParseEndpoint(%q) err = %v", tt.in, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseEndpoint(%q) = %+v, want %+v", tt.in, got, tt.want)
+		}
+	}
+}
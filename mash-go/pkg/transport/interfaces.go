@@ -83,4 +83,5 @@ var (
 	_ ClientConnection = (*ClientConn)(nil)
 	_ TransportServer  = (*Server)(nil)
 	_ FrameReadWriter  = (*Framer)(nil)
+	_ Network          = (*PipeNetwork)(nil)
 )
@@ -0,0 +1,114 @@
+package transport
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"os"
+	"time"
+)
+
+// UnixSocketMode is the file mode of unix:// listener sockets: the service
+// user and its group may connect.
+const UnixSocketMode os.FileMode = 0o660
+
+// Network creates the listeners and connections MASH runs TLS over.
+// Sockets is used in production; PipeNetwork connects services in-process.
+type Network interface {
+	// Listen announces on the given address, e.g. ":8443" or "localhost:0".
+	Listen(address string) (net.Listener, error)
+
+	// DialContext connects to the given address.
+	DialContext(ctx context.Context, address string) (net.Conn, error)
+}
+
+// Sockets returns the network of real sockets. Addresses are parsed with
+// ParseEndpoint: host:port for TCP, or tcp4://, tcp6:// and unix:// forms.
+func Sockets() Network {
+	return socketNetwork{}
+}
+
+// OrSockets returns n, or Sockets if n is nil.
+func OrSockets(n Network) Network {
+	if n == nil {
+		return Sockets()
+	}
+	return n
+}
+
+type socketNetwork struct{}
+
+func (socketNetwork) Listen(address string) (net.Listener, error) {
+	ep, err := ParseEndpoint(address)
+	if err != nil {
+		return nil, err
+	}
+	if !ep.IsTCP() {
+		return listenUnix(ep.Address)
+	}
+	return net.Listen(ep.Network, ep.Address)
+}
+
+// listenUnix listens on a unix socket at path. A socket left behind by an
+// earlier process is removed first, unless something still accepts on it.
+// The socket is restricted to UnixSocketMode.
+func listenUnix(path string) (net.Listener, error) {
+	if info, err := os.Lstat(path); err == nil && info.Mode()&os.ModeSocket != 0 {
+		if conn, err := net.DialTimeout("unix", path, time.Second); err == nil {
+			conn.Close()
+			return nil, fmt.Errorf("listen unix %s: socket in use", path)
+		}
+		if err := os.Remove(path); err != nil {
+			return nil, err
+		}
+	}
+
+	l, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.Chmod(path, UnixSocketMode); err != nil {
+		l.Close()
+		return nil, err
+	}
+	return l, nil
+}
+
+func (socketNetwork) DialContext(ctx context.Context, address string) (net.Conn, error) {
+	ep, err := ParseEndpoint(address)
+	if err != nil {
+		return nil, err
+	}
+	var d net.Dialer
+	return d.DialContext(ctx, ep.Network, ep.Address)
+}
+
+// DialTLS connects to address on n and performs the TLS handshake, like
+// tls.Dialer. If config has neither ServerName nor InsecureSkipVerify set,
+// the host part of a TCP address is used as ServerName.
+func DialTLS(ctx context.Context, n Network, address string, config *tls.Config) (*tls.Conn, error) {
+	if config.ServerName == "" && !config.InsecureSkipVerify {
+		ep, err := ParseEndpoint(address)
+		if err != nil {
+			return nil, err
+		}
+		if ep.IsTCP() {
+			host, _, _ := net.SplitHostPort(ep.Address)
+			config = config.Clone()
+			config.ServerName = host
+		}
+	}
+
+	conn, err := OrSockets(n).DialContext(ctx, address)
+	if err != nil {
+		return nil, err
+	}
+
+	tlsConn := tls.Client(conn, config)
+	if err := tlsConn.HandshakeContext(ctx); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return tlsConn, nil
+}
@@ -0,0 +1,48 @@
+package transport
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSocketsListenUnix(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "mash.sock")
+
+	// A socket file left behind by a crashed process
+	stale, err := net.Listen("unix", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stale.(*net.UnixListener).SetUnlinkOnClose(false)
+	stale.Close()
+
+	l, err := Sockets().Listen("unix://" + path)
+	if err != nil {
+		t.Fatalf("Listen over a stale socket: %v", err)
+	}
+	defer l.Close()
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mode := info.Mode().Perm(); mode != UnixSocketMode {
+		t.Errorf("socket mode = %v, want %v", mode, UnixSocketMode)
+	}
+
+	// A socket in use is left alone
+	if _, err := Sockets().Listen("unix://" + path); err == nil {
+		t.Error("Listen on a socket in use succeeded")
+	}
+
+	// So is a file that is not a socket
+	file := filepath.Join(t.TempDir(), "data")
+	if err := os.WriteFile(file, nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Sockets().Listen("unix://" + file); err == nil {
+		t.Error("Listen replaced a regular file")
+	}
+}
@@ -0,0 +1,333 @@
+package transport
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Pipe network errors.
+var (
+	ErrAddressInUse      = errors.New("address already in use")
+	ErrConnectionRefused = errors.New("connection refused")
+)
+
+// Ports handed out for listeners on port 0 and for the dialing side of
+// connections, as from the IANA dynamic port range.
+const (
+	pipeFirstPort = 49152
+	pipeLastPort  = 65535
+)
+
+// Writes a connection may queue before Write blocks, and how long Close
+// keeps flushing queued writes to the peer.
+const (
+	pipeQueueLen = 64
+	pipeLinger   = time.Second
+)
+
+// PipeNetwork is an in-process Network whose connections are net.Pipe
+// pairs. It behaves like a single loopback host: listeners are identified
+// by port alone, the host part of an address is ignored, and all addresses
+// are reported as 127.0.0.1 TCP addresses so callers can treat them like
+// real ones. TLS and framing run over the pipes unchanged.
+//
+// A PipeNetwork lets many devices and controllers run in one process
+// without opening sockets. It is safe for concurrent use.
+type PipeNetwork struct {
+	mu        sync.Mutex
+	listeners map[int]*pipeListener
+	nextPort  int
+}
+
+// NewPipeNetwork creates an empty in-process network.
+func NewPipeNetwork() *PipeNetwork {
+	return &PipeNetwork{
+		listeners: make(map[int]*pipeListener),
+		nextPort:  pipeFirstPort,
+	}
+}
+
+// Listen announces on address. A port of 0 picks a free port.
+func (n *PipeNetwork) Listen(address string) (net.Listener, error) {
+	port, err := pipePort(address)
+	if err != nil {
+		return nil, err
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if port == 0 {
+		port = n.allocPort()
+	} else if _, ok := n.listeners[port]; ok {
+		return nil, fmt.Errorf("%w: %s", ErrAddressInUse, address)
+	}
+
+	l := &pipeListener{
+		network: n,
+		addr:    pipeAddr(port),
+		conns:   make(chan net.Conn),
+		done:    make(chan struct{}),
+	}
+	n.listeners[port] = l
+	return l, nil
+}
+
+// DialContext connects to the listener on address's port. It blocks until
+// the listener accepts the connection or ctx is done.
+func (n *PipeNetwork) DialContext(ctx context.Context, address string) (net.Conn, error) {
+	port, err := pipePort(address)
+	if err != nil {
+		return nil, err
+	}
+
+	n.mu.Lock()
+	l := n.listeners[port]
+	localPort := n.allocPort()
+	n.mu.Unlock()
+	if l == nil {
+		return nil, fmt.Errorf("%w: %s", ErrConnectionRefused, address)
+	}
+
+	p1, p2 := net.Pipe()
+	local := pipeAddr(localPort)
+	client := newPipeConn(p1, local, l.addr)
+	server := newPipeConn(p2, l.addr, local)
+	select {
+	case l.conns <- server:
+		return client, nil
+	case <-l.done:
+		err = fmt.Errorf("%w: %s", ErrConnectionRefused, address)
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+	client.Close()
+	server.Close()
+	return nil, err
+}
+
+// allocPort returns the next port without a listener. Caller holds n.mu.
+func (n *PipeNetwork) allocPort() int {
+	for {
+		port := n.nextPort
+		n.nextPort++
+		if n.nextPort > pipeLastPort {
+			n.nextPort = pipeFirstPort
+		}
+		if _, ok := n.listeners[port]; !ok {
+			return port
+		}
+	}
+}
+
+// pipePort parses the port of a TCP address.
+func pipePort(address string) (int, error) {
+	ep, err := ParseEndpoint(address)
+	if err != nil {
+		return 0, err
+	}
+	if !ep.IsTCP() {
+		return 0, fmt.Errorf("%w: %q: pipe network is TCP only", ErrInvalidEndpoint, address)
+	}
+	_, portStr, _ := net.SplitHostPort(ep.Address)
+	if portStr == "" {
+		return 0, nil
+	}
+	port, err := strconv.ParseUint(portStr, 10, 16)
+	if err != nil {
+		return 0, fmt.Errorf("%w: invalid port in %q", ErrInvalidEndpoint, address)
+	}
+	return int(port), nil
+}
+
+func pipeAddr(port int) *net.TCPAddr {
+	return &net.TCPAddr{IP: net.IPv4(127, 0, 0, 1), Port: port}
+}
+
+// pipeListener is a net.Listener on a PipeNetwork.
+type pipeListener struct {
+	network *PipeNetwork
+	addr    *net.TCPAddr
+	conns   chan net.Conn
+
+	closeOnce sync.Once
+	done      chan struct{}
+}
+
+func (l *pipeListener) Accept() (net.Conn, error) {
+	select {
+	case conn := <-l.conns:
+		return conn, nil
+	case <-l.done:
+		return nil, net.ErrClosed
+	}
+}
+
+func (l *pipeListener) Close() error {
+	l.closeOnce.Do(func() {
+		l.network.mu.Lock()
+		if l.network.listeners[l.addr.Port] == l {
+			delete(l.network.listeners, l.addr.Port)
+		}
+		l.network.mu.Unlock()
+		close(l.done)
+	})
+	return nil
+}
+
+func (l *pipeListener) Addr() net.Addr {
+	return l.addr
+}
+
+// pipeConn is one end of a net.Pipe with the addresses of its listener
+// and dialer. net.Pipe is unbuffered, so a write blocks until the peer
+// reads it; two peers writing at once, as when both send close_notify on
+// Close, would deadlock. pipeConn queues writes like a socket send buffer
+// and a pump goroutine copies them into the pipe.
+type pipeConn struct {
+	pipe          net.Conn
+	local, remote *net.TCPAddr
+
+	queue     chan []byte
+	closed    chan struct{}
+	closeOnce sync.Once
+	pumpDone  chan struct{}
+	writeErr  atomic.Value // error from the pump
+
+	mu            sync.Mutex
+	writeDeadline time.Time
+}
+
+func newPipeConn(pipe net.Conn, local, remote *net.TCPAddr) *pipeConn {
+	c := &pipeConn{
+		pipe:     pipe,
+		local:    local,
+		remote:   remote,
+		queue:    make(chan []byte, pipeQueueLen),
+		closed:   make(chan struct{}),
+		pumpDone: make(chan struct{}),
+	}
+	go c.pump()
+	return c
+}
+
+// pump copies queued writes into the pipe. After Close it flushes what is
+// queued, for at most pipeLinger, then closes the pipe.
+func (c *pipeConn) pump() {
+	defer close(c.pumpDone)
+	defer c.pipe.Close()
+	for {
+		select {
+		case b := <-c.queue:
+			if _, err := c.pipe.Write(b); err != nil {
+				c.writeErr.Store(err)
+				return
+			}
+		case <-c.closed:
+			_ = c.pipe.SetWriteDeadline(time.Now().Add(pipeLinger))
+			for {
+				select {
+				case b := <-c.queue:
+					if _, err := c.pipe.Write(b); err != nil {
+						return
+					}
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+func (c *pipeConn) Read(b []byte) (int, error) {
+	n, err := c.pipe.Read(b)
+	if err != nil {
+		select {
+		case <-c.closed:
+			return n, net.ErrClosed
+		default:
+		}
+	}
+	return n, err
+}
+
+func (c *pipeConn) Write(b []byte) (int, error) {
+	buf := make([]byte, len(b))
+	copy(buf, b)
+
+	var timeout <-chan time.Time
+	c.mu.Lock()
+	deadline := c.writeDeadline
+	c.mu.Unlock()
+	if !deadline.IsZero() {
+		timer := time.NewTimer(time.Until(deadline))
+		defer timer.Stop()
+		timeout = timer.C
+	}
+
+	select {
+	case <-c.closed:
+		return 0, net.ErrClosed
+	case <-c.pumpDone:
+		return 0, c.pumpErr()
+	default:
+	}
+	select {
+	case c.queue <- buf:
+		return len(b), nil
+	case <-c.closed:
+		return 0, net.ErrClosed
+	case <-c.pumpDone:
+		return 0, c.pumpErr()
+	case <-timeout:
+		return 0, os.ErrDeadlineExceeded
+	}
+}
+
+// pumpErr returns the write error that stopped the pump.
+func (c *pipeConn) pumpErr() error {
+	if err, ok := c.writeErr.Load().(error); ok {
+		return err
+	}
+	return net.ErrClosed
+}
+
+// Close closes the connection. Reads fail at once; queued writes are
+// still delivered to the peer.
+func (c *pipeConn) Close() error {
+	c.closeOnce.Do(func() {
+		close(c.closed)
+		_ = c.pipe.SetReadDeadline(time.Now())
+	})
+	return nil
+}
+
+func (c *pipeConn) LocalAddr() net.Addr  { return c.local }
+func (c *pipeConn) RemoteAddr() net.Addr { return c.remote }
+
+func (c *pipeConn) SetDeadline(t time.Time) error {
+	_ = c.SetWriteDeadline(t)
+	return c.SetReadDeadline(t)
+}
+
+func (c *pipeConn) SetReadDeadline(t time.Time) error {
+	select {
+	case <-c.closed:
+		return net.ErrClosed
+	default:
+	}
+	return c.pipe.SetReadDeadline(t)
+}
+
+func (c *pipeConn) SetWriteDeadline(t time.Time) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.writeDeadline = t
+	return nil
+}
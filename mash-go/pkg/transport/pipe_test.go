@@ -0,0 +1,157 @@
+package transport
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"net"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestPipeNetworkListenDial(t *testing.T) {
+	n := NewPipeNetwork()
+
+	l, err := n.Listen("localhost:0")
+	if err != nil {
+		t.Fatalf("Listen: %v", err)
+	}
+	defer l.Close()
+
+	addr := l.Addr().(*net.TCPAddr)
+	if addr.Port == 0 {
+		t.Fatal("Listen on port 0 did not pick a port")
+	}
+	if _, err := n.Listen(addr.String()); !errors.Is(err, ErrAddressInUse) {
+		t.Errorf("second Listen on %s: err = %v, want ErrAddressInUse", addr, err)
+	}
+
+	accepted := make(chan net.Conn, 1)
+	go func() {
+		conn, err := l.Accept()
+		if err != nil {
+			t.Errorf("Accept: %v", err)
+		}
+		accepted <- conn
+	}()
+
+	// The host is ignored: only the port identifies the listener.
+	client, err := n.DialContext(context.Background(), "[::1]:"+strconv.Itoa(addr.Port))
+	if err != nil {
+		t.Fatalf("DialContext: %v", err)
+	}
+	defer client.Close()
+	server := <-accepted
+	defer server.Close()
+
+	if client.RemoteAddr().String() != addr.String() {
+		t.Errorf("client RemoteAddr = %s, want %s", client.RemoteAddr(), addr)
+	}
+	if server.RemoteAddr().String() != client.LocalAddr().String() {
+		t.Errorf("server RemoteAddr = %s, want client LocalAddr %s", server.RemoteAddr(), client.LocalAddr())
+	}
+
+	go func() { _, _ = client.Write([]byte("ping")) }()
+	buf := make([]byte, 4)
+	if _, err := server.Read(buf); err != nil || string(buf) != "ping" {
+		t.Errorf("Read = %q, %v; want ping", buf, err)
+	}
+}
+
+func TestPipeNetworkRefused(t *testing.T) {
+	n := NewPipeNetwork()
+	ctx := context.Background()
+
+	if _, err := n.DialContext(ctx, "localhost:8443"); !errors.Is(err, ErrConnectionRefused) {
+		t.Errorf("dial without listener: err = %v, want ErrConnectionRefused", err)
+	}
+
+	l, err := n.Listen(":8443")
+	if err != nil {
+		t.Fatalf("Listen: %v", err)
+	}
+
+	// Accept is unblocked by Close and the port is released.
+	done := make(chan error, 1)
+	go func() {
+		_, err := l.Accept()
+		done <- err
+	}()
+	l.Close()
+	if err := <-done; !errors.Is(err, net.ErrClosed) {
+		t.Errorf("Accept after Close: err = %v, want net.ErrClosed", err)
+	}
+	if _, err := n.DialContext(ctx, "localhost:8443"); !errors.Is(err, ErrConnectionRefused) {
+		t.Errorf("dial after Close: err = %v, want ErrConnectionRefused", err)
+	}
+
+	// A dial nobody accepts honours the context.
+	l, err = n.Listen(":8443")
+	if err != nil {
+		t.Fatalf("Listen after Close: %v", err)
+	}
+	defer l.Close()
+	ctx, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+	defer cancel()
+	if _, err := n.DialContext(ctx, "localhost:8443"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("unaccepted dial: err = %v, want DeadlineExceeded", err)
+	}
+}
+
+func TestPipeNetworkTLS(t *testing.T) {
+	cert, _ := generateTestCertificate(t)
+	serverConf, err := NewServerTLSConfig(&TLSConfig{Certificate: cert})
+	if err != nil {
+		t.Fatalf("NewServerTLSConfig: %v", err)
+	}
+	serverConf.ClientAuth = tls.NoClientCert
+
+	n := NewPipeNetwork()
+	l, err := n.Listen(":0")
+	if err != nil {
+		t.Fatalf("Listen: %v", err)
+	}
+	defer l.Close()
+
+	go func() {
+		conn, err := l.Accept()
+		if err != nil {
+			return
+		}
+		tlsConn := tls.Server(conn, serverConf)
+		defer tlsConn.Close()
+		if err := tlsConn.Handshake(); err != nil {
+			return
+		}
+		framer := NewFramer(tlsConn)
+		if msg, err := framer.ReadFrame(); err == nil {
+			_ = framer.WriteFrame(msg)
+		}
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	conn, err := DialTLS(ctx, n, l.Addr().String(), &tls.Config{
+		MinVersion:         tls.VersionTLS13,
+		InsecureSkipVerify: true,
+		NextProtos:         []string{ALPNProtocol},
+	})
+	if err != nil {
+		t.Fatalf("DialTLS: %v", err)
+	}
+	defer conn.Close()
+
+	if err := VerifyConnection(conn.ConnectionState()); err != nil {
+		t.Errorf("VerifyConnection: %v", err)
+	}
+
+	framer := NewFramer(conn)
+	if err := framer.WriteFrame([]byte("hello")); err != nil {
+		t.Fatalf("WriteFrame: %v", err)
+	}
+	msg, err := framer.ReadFrame()
+	if err != nil || string(msg) != "hello" {
+		t.Errorf("ReadFrame = %q, %v; want echo", msg, err)
+	}
+}
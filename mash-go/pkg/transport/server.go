@@ -19,7 +19,8 @@ type ServerConfig struct {
 	// TLSConfig contains TLS settings.
 	TLSConfig *TLSConfig
 
-	// Address to listen on (e.g., ":8443" or "127.0.0.1:8443").
+	// Address to listen on (e.g., ":8443", "tcp4://0.0.0.0:8443" or
+	// "unix:///run/mash.sock"). See ParseEndpoint.
 	Address string
 
 	// RequireClientCert requires clients to present a valid certificate.
@@ -102,7 +103,7 @@ func (s *Server) Start(ctx context.Context) error {
 	s.ctx, s.cancel = context.WithCancel(ctx)
 
 	// Create listener
-	listener, err := net.Listen("tcp", s.config.Address)
+	listener, err := Sockets().Listen(s.config.Address)
 	if err != nil {
 		return fmt.Errorf("failed to listen: %w", err)
 	}
@@ -10,6 +10,8 @@ import (
 	"crypto/x509/pkix"
 	"math/big"
 	"net"
+	"path/filepath"
+	"strings"
 	"sync"
 	"testing"
 	"time"
@@ -1215,3 +1217,81 @@ func TestConnectionLogsStateChanges(t *testing.T) {
 		t.Error("Expected DISCONNECTED state change event")
 	}
 }
+
+// TestServerClientEndpoints verifies server and client over unix:// and
+// tcp4:// endpoints.
+func TestServerClientEndpoints(t *testing.T) {
+	tests := []struct {
+		name    string
+		listen  string
+		connect func(addr net.Addr) string
+	}{
+		{
+			name:    "unix",
+			listen:  "unix://" + filepath.Join(t.TempDir(), "mash.sock"),
+			connect: func(addr net.Addr) string { return "unix://" + addr.String() },
+		},
+		{
+			name:    "tcp4",
+			listen:  "tcp4://127.0.0.1:0",
+			connect: func(addr net.Addr) string { return "tcp4://" + addr.String() },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			serverCert, serverKey := generateTestCert(t)
+			clientCert, clientKey := generateTestCert(t)
+
+			server, err := transport.NewServer(transport.ServerConfig{
+				TLSConfig: &transport.TLSConfig{
+					Certificate:        loadCert(t, serverCert, serverKey),
+					InsecureSkipVerify: true,
+				},
+				Address: tt.listen,
+				OnMessage: func(conn *transport.ServerConn, msg []byte) {
+					_ = conn.Send(msg)
+				},
+			})
+			if err != nil {
+				t.Fatalf("Failed to create server: %v", err)
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			if err := server.Start(ctx); err != nil {
+				t.Fatalf("Failed to start server: %v", err)
+			}
+			defer server.Stop()
+
+			if got, want := server.Addr().Network(), strings.TrimSuffix(tt.name, "4"); got != want {
+				t.Errorf("listener network = %q, want %q", got, want)
+			}
+
+			client, err := transport.NewClient(transport.ClientConfig{
+				TLSConfig: &transport.TLSConfig{
+					Certificate:        loadCert(t, clientCert, clientKey),
+					InsecureSkipVerify: true,
+				},
+			})
+			if err != nil {
+				t.Fatalf("Failed to create client: %v", err)
+			}
+
+			conn, err := client.Connect(ctx, tt.connect(server.Addr()))
+			if err != nil {
+				t.Fatalf("Failed to connect: %v", err)
+			}
+			defer conn.Close()
+
+			if err := conn.Send([]byte("ping")); err != nil {
+				t.Fatalf("Failed to send: %v", err)
+			}
+			response, err := conn.Receive(2 * time.Second)
+			if err != nil || string(response) != "ping" {
+				t.Errorf("Receive = %q, %v; want echo", response, err)
+			}
+		})
+	}
+}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/mash-protocol/mash-go/pkg/features"
 	"github.com/mash-protocol/mash-go/pkg/model"
+	"github.com/mash-protocol/mash-go/pkg/wire"
 )
 
 // DeviceReader is the subset of DeviceClient needed for discovery.
@@ -13,6 +14,12 @@ type DeviceReader interface {
 	Read(ctx context.Context, endpointID uint8, featureID uint8, attrIDs []uint16) (map[uint16]any, error)
 }
 
+// PathReader is implemented by clients that support multi-path reads.
+// DiscoverDevice uses it to probe all endpoints in a single round-trip.
+type PathReader interface {
+	ReadPaths(ctx context.Context, paths []wire.AttributePath) ([]wire.PathResult, error)
+}
+
 // knownFeatureIDs lists the feature types to probe on each endpoint.
 var knownFeatureIDs = []uint8{
 	uint8(model.FeatureStatus),
@@ -34,7 +41,17 @@ var energyControlCapabilityAttrs = []uint16{
 	features.EnergyControlAttrIsStoppable,
 }
 
-// DiscoverDevice probes a device to build a DeviceProfile.
+// probeAttrs are the global attributes read from every probed feature.
+var probeAttrs = []uint16{
+	model.AttrIDAttributeList,
+	model.AttrIDCommandList,
+	model.AttrIDFeatureMap,
+}
+
+// DiscoverDevice probes a device to build a DeviceProfile. If the client is
+// a PathReader the features of all endpoints are probed with one batched
+// read; otherwise, or if the device rejects it, each feature is probed
+// separately.
 func DiscoverDevice(ctx context.Context, client DeviceReader, deviceID string) (*DeviceProfile, error) {
 	// Read endpoints from DeviceInfo on endpoint 0
 	attrs, err := client.Read(ctx, 0, uint8(model.FeatureDeviceInfo), []uint16{features.DeviceInfoAttrEndpoints})
@@ -52,6 +69,13 @@ func DiscoverDevice(ctx context.Context, client DeviceReader, deviceID string) (
 		Endpoints: make(map[uint8]*EndpointProfile),
 	}
 
+	if pr, ok := client.(PathReader); ok {
+		if eps, err := discoverEndpointPaths(ctx, pr, endpoints); err == nil {
+			profile.Endpoints = eps
+			return profile, nil
+		}
+	}
+
 	for _, epInfo := range endpoints {
 		ep, err := discoverEndpoint(ctx, client, epInfo.id, epInfo.epType, knownFeatureIDs)
 		if err != nil {
@@ -254,6 +278,83 @@ func discoverEndpoint(ctx context.Context, client DeviceReader, epID uint8, epTy
 	return ep, nil
 }
 
+// discoverEndpointPaths probes the known features of all endpoints with one
+// multi-path read, plus a second one for EnergyControl capabilities.
+func discoverEndpointPaths(ctx context.Context, client PathReader, endpoints []endpointInfo) (map[uint8]*EndpointProfile, error) {
+	eps := make(map[uint8]*EndpointProfile, len(endpoints))
+	var paths []wire.AttributePath
+	for _, epInfo := range endpoints {
+		eps[epInfo.id] = &EndpointProfile{
+			EndpointID:   epInfo.id,
+			EndpointType: epInfo.epType,
+			Features:     make(map[uint8]*FeatureProfile),
+		}
+		for _, fID := range knownFeatureIDs {
+			paths = append(paths, wire.AttributePath{EndpointID: epInfo.id, FeatureID: fID, AttributeIDs: probeAttrs})
+		}
+	}
+
+	results, err := client.ReadPaths(ctx, paths)
+	if err != nil {
+		return nil, err
+	}
+
+	var capPaths []wire.AttributePath
+	for _, r := range results {
+		ep, ok := eps[r.EndpointID]
+		if !ok || r.Status != wire.StatusSuccess {
+			continue // Feature absent
+		}
+		fp := &FeatureProfile{
+			FeatureID:    r.FeatureID,
+			AttributeIDs: parseUint16List(r.Values[model.AttrIDAttributeList]),
+			CommandIDs:   parseUint8List(r.Values[model.AttrIDCommandList]),
+			Attributes:   make(map[uint16]any),
+		}
+		if v, ok := toUint32(r.Values[model.AttrIDFeatureMap]); ok {
+			fp.FeatureMap = v
+		}
+		ep.Features[r.FeatureID] = fp
+
+		// Only request capabilities the feature has, so the path cannot fail
+		if r.FeatureID == uint8(model.FeatureEnergyControl) {
+			var attrIDs []uint16
+			for _, id := range energyControlCapabilityAttrs {
+				for _, have := range fp.AttributeIDs {
+					if id == have {
+						attrIDs = append(attrIDs, id)
+						break
+					}
+				}
+			}
+			if len(attrIDs) > 0 {
+				capPaths = append(capPaths, wire.AttributePath{EndpointID: r.EndpointID, FeatureID: r.FeatureID, AttributeIDs: attrIDs})
+			}
+		}
+	}
+
+	if len(capPaths) > 0 {
+		capResults, err := client.ReadPaths(ctx, capPaths)
+		if err == nil {
+			for _, r := range capResults {
+				if r.Status != wire.StatusSuccess {
+					continue
+				}
+				ep, ok := eps[r.EndpointID]
+				if !ok || ep.Features[r.FeatureID] == nil {
+					continue
+				}
+				fp := ep.Features[r.FeatureID]
+				for id, val := range r.Values {
+					fp.Attributes[id] = val
+				}
+			}
+		}
+	}
+
+	return eps, nil
+}
+
 func probeFeature(ctx context.Context, client DeviceReader, epID uint8, featureID uint8) (*FeatureProfile, error) {
 	// Step 1: Read attributeList to determine presence
 	attrs, err := client.Read(ctx, epID, featureID, []uint16{model.AttrIDAttributeList})
@@ -7,6 +7,7 @@ import (
 
 	"github.com/mash-protocol/mash-go/pkg/interaction"
 	"github.com/mash-protocol/mash-go/pkg/model"
+	"github.com/mash-protocol/mash-go/pkg/wire"
 )
 
 // mockClient implements examples.DeviceClient for testing.
@@ -474,3 +475,100 @@ func TestDiscoverDevice_ErrorReadingDeviceInfo(t *testing.T) {
 		t.Fatal("expected error when DeviceInfo read fails")
 	}
 }
+
+// mockPathClient adds multi-path reads to mockClient.
+type mockPathClient struct {
+	mockClient
+	readPathsFunc func(ctx context.Context, paths []wire.AttributePath) ([]wire.PathResult, error)
+	batches       int
+}
+
+func (m *mockPathClient) ReadPaths(ctx context.Context, paths []wire.AttributePath) ([]wire.PathResult, error) {
+	m.batches++
+	return m.readPathsFunc(ctx, paths)
+}
+
+// singleEndpointInfo answers the DeviceInfo endpoints read with one
+// EV_CHARGER endpoint 1 and fails every other single-path read.
+func singleEndpointInfo(_ context.Context, epID uint8, fID uint8, attrIDs []uint16) (map[uint16]any, error) {
+	if epID == 0 && fID == uint8(model.FeatureDeviceInfo) {
+		return map[uint16]any{
+			20: []any{
+				map[any]any{uint64(1): uint64(1), uint64(2): uint64(0x05)},
+			},
+		}, nil
+	}
+	return nil, fmt.Errorf("not found")
+}
+
+func TestDiscoverDevice_ReadPaths(t *testing.T) {
+	var capRequest []uint16
+	client := &mockPathClient{mockClient: mockClient{readFunc: singleEndpointInfo}}
+	client.readPathsFunc = func(_ context.Context, paths []wire.AttributePath) ([]wire.PathResult, error) {
+		results := make([]wire.PathResult, len(paths))
+		for i, p := range paths {
+			results[i] = wire.PathResult{EndpointID: p.EndpointID, FeatureID: p.FeatureID, Status: wire.StatusInvalidFeature}
+			if p.EndpointID != 1 || p.FeatureID != uint8(model.FeatureEnergyControl) {
+				continue
+			}
+			results[i].Status = wire.StatusSuccess
+			if len(p.AttributeIDs) == len(probeAttrs) && p.AttributeIDs[0] == model.AttrIDAttributeList {
+				results[i].Values = map[uint16]any{
+					model.AttrIDAttributeList: []any{uint64(10), uint64(14), uint64(model.AttrIDFeatureMap)},
+					model.AttrIDCommandList:   []any{uint64(1)},
+					model.AttrIDFeatureMap:    uint64(3),
+				}
+				continue
+			}
+			capRequest = p.AttributeIDs
+			results[i].Values = map[uint16]any{10: true}
+		}
+		return results, nil
+	}
+
+	profile, err := DiscoverDevice(context.Background(), client, "test-device")
+	if err != nil {
+		t.Fatalf("DiscoverDevice: %v", err)
+	}
+	if client.batches != 2 {
+		t.Errorf("batches = %d, want 2", client.batches)
+	}
+
+	ep := profile.Endpoints[1]
+	if ep == nil || len(ep.Features) != 1 {
+		t.Fatalf("expected endpoint 1 with one feature, got %+v", ep)
+	}
+	fp := ep.Features[uint8(model.FeatureEnergyControl)]
+	if fp == nil {
+		t.Fatal("missing EnergyControl")
+	}
+	if fp.FeatureMap != 3 || len(fp.CommandIDs) != 1 || len(fp.AttributeIDs) != 3 {
+		t.Errorf("unexpected profile: %+v", fp)
+	}
+	if fp.Attributes[10] != true {
+		t.Errorf("expected capability attribute 10, got %v", fp.Attributes)
+	}
+	// Only capabilities listed in attributeList are requested
+	if len(capRequest) != 2 {
+		t.Errorf("capability request = %v, want 2 attributes", capRequest)
+	}
+}
+
+func TestDiscoverDevice_ReadPathsFallback(t *testing.T) {
+	client := &mockPathClient{mockClient: mockClient{readFunc: singleEndpointInfo}}
+	client.readPathsFunc = func(_ context.Context, _ []wire.AttributePath) ([]wire.PathResult, error) {
+		return nil, fmt.Errorf("unsupported")
+	}
+
+	profile, err := DiscoverDevice(context.Background(), client, "test-device")
+	if err != nil {
+		t.Fatalf("DiscoverDevice: %v", err)
+	}
+	if client.batches != 1 {
+		t.Errorf("batches = %d, want 1", client.batches)
+	}
+	// Per-feature probing still builds the endpoint
+	if _, ok := profile.Endpoints[1]; !ok {
+		t.Error("missing endpoint 1 after fallback")
+	}
+}
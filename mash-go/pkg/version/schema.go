@@ -0,0 +1,345 @@
+package version
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"sort"
+	"strconv"
+	"sync"
+
+	"github.com/mash-protocol/mash-go/pkg/wire"
+)
+
+// FeatureSchema describes the payloads a feature accepts: the types of its
+// writable attributes and of its command parameters.
+type FeatureSchema struct {
+	Attributes map[uint16]*TypeSpec           `yaml:"attributes"`
+	Commands   map[uint8]map[string]*TypeSpec `yaml:"commands"`
+}
+
+// TypeSpec describes the type of an attribute, parameter or nested value.
+type TypeSpec struct {
+	// Type is the wire type: uint8..uint64, int8..int64, string, bool,
+	// array, map or object.
+	Type string `yaml:"type"`
+
+	// Enum lists the allowed values of an enum-typed integer.
+	Enum []int64 `yaml:"enum"`
+
+	// Nullable allows a null value.
+	Nullable bool `yaml:"nullable"`
+
+	// Required marks a command parameter that must be present.
+	Required bool `yaml:"required"`
+
+	// Min and Max bound integer values.
+	Min *int64 `yaml:"min"`
+	Max *int64 `yaml:"max"`
+
+	// MaxItems bounds the length of an array (0 = unbounded).
+	MaxItems int `yaml:"maxItems"`
+
+	// Key and Value type map entries; Items types array elements.
+	Key   *TypeSpec `yaml:"key"`
+	Value *TypeSpec `yaml:"value"`
+	Items *TypeSpec `yaml:"items"`
+
+	// Fields types the fields of an object by name.
+	Fields map[string]*TypeSpec `yaml:"fields"`
+}
+
+// Validator checks Write and Invoke payloads against the schema of a spec
+// manifest before they are dispatched to features.
+//
+// Features, attributes and parameters the manifest does not describe are
+// not checked, so vendor extensions pass through unchanged.
+type Validator struct {
+	features map[uint8]*validatorFeature
+}
+
+type validatorFeature struct {
+	schema    FeatureSchema
+	attrNames map[uint16]string
+}
+
+// NewValidator compiles the schema of a spec manifest into a validator.
+func NewValidator(spec *SpecManifest) *Validator {
+	v := &Validator{features: make(map[uint8]*validatorFeature)}
+	for _, fs := range spec.Features {
+		names := make(map[uint16]string)
+		for _, a := range fs.Attributes.Mandatory {
+			names[a.ID] = a.Name
+		}
+		for _, a := range fs.Attributes.Optional {
+			names[a.ID] = a.Name
+		}
+		v.features[fs.ID] = &validatorFeature{schema: fs.Schema, attrNames: names}
+	}
+	return v
+}
+
+var currentValidator = sync.OnceValue(func() *Validator {
+	spec, err := LoadCurrentSpec()
+	if err != nil {
+		return &Validator{}
+	}
+	return NewValidator(spec)
+})
+
+// CurrentValidator returns the validator for the current protocol version.
+func CurrentValidator() *Validator {
+	return currentValidator()
+}
+
+// ValidateWrite checks the attribute values of a Write request. It returns
+// one error per invalid field, in attribute ID order, or nil.
+func (v *Validator) ValidateWrite(featureID uint8, attrs map[uint16]any) []wire.FieldError {
+	f := v.features[featureID]
+	if f == nil || len(f.schema.Attributes) == 0 {
+		return nil
+	}
+
+	ids := make([]uint16, 0, len(attrs))
+	for id := range attrs {
+		ids = append(ids, id)
+	}
+	slices.Sort(ids)
+
+	var errs []wire.FieldError
+	for _, id := range ids {
+		spec := f.schema.Attributes[id]
+		if spec == nil {
+			continue
+		}
+		name := f.attrNames[id]
+		if name == "" {
+			name = strconv.Itoa(int(id))
+		}
+		errs = checkValue(errs, name, spec, attrs[id])
+	}
+	return errs
+}
+
+// ValidateInvoke checks the parameters of an Invoke request. It returns one
+// error per invalid or missing parameter, in name order, or nil.
+func (v *Validator) ValidateInvoke(featureID, commandID uint8, params map[string]any) []wire.FieldError {
+	f := v.features[featureID]
+	if f == nil {
+		return nil
+	}
+	specs := f.schema.Commands[commandID]
+	if len(specs) == 0 {
+		return nil
+	}
+
+	names := make([]string, 0, len(specs))
+	for name := range specs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var errs []wire.FieldError
+	for _, name := range names {
+		spec := specs[name]
+		value, present := params[name]
+		if !present {
+			if spec.Required {
+				errs = append(errs, wire.FieldError{
+					Path:       name,
+					Message:    "required parameter missing",
+					Constraint: wire.ConstraintRequired,
+				})
+			}
+			continue
+		}
+		// An explicit null stands in for an omitted optional parameter.
+		if isNull(value) && !spec.Required {
+			continue
+		}
+		errs = checkValue(errs, name, spec, value)
+	}
+	return errs
+}
+
+// checkValue appends the errors of value at path to errs.
+func checkValue(errs []wire.FieldError, path string, spec *TypeSpec, value any) []wire.FieldError {
+	fail := func(c wire.Constraint, limit any, format string, args ...any) []wire.FieldError {
+		return append(errs, wire.FieldError{
+			Path:       path,
+			Message:    fmt.Sprintf(format, args...),
+			Constraint: c,
+			Limit:      limit,
+		})
+	}
+
+	if isNull(value) {
+		if spec.Nullable {
+			return errs
+		}
+		return fail(wire.ConstraintNotNull, nil, "must not be null")
+	}
+	rv := reflect.Indirect(reflect.ValueOf(value))
+
+	switch spec.Type {
+	case "bool":
+		if rv.Kind() != reflect.Bool {
+			return fail(wire.ConstraintType, nil, "expected bool, got %s", typeName(rv))
+		}
+
+	case "string":
+		if rv.Kind() != reflect.String {
+			return fail(wire.ConstraintType, nil, "expected string, got %s", typeName(rv))
+		}
+
+	case "uint8", "uint16", "uint32", "uint64", "int8", "int16", "int32", "int64":
+		return checkInteger(errs, path, spec, rv)
+
+	case "array":
+		if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+			return fail(wire.ConstraintType, nil, "expected array, got %s", typeName(rv))
+		}
+		if spec.MaxItems > 0 && rv.Len() > spec.MaxItems {
+			return fail(wire.ConstraintMaxItems, int64(spec.MaxItems), "has %d items, maximum is %d", rv.Len(), spec.MaxItems)
+		}
+		if spec.Items != nil {
+			for i := 0; i < rv.Len(); i++ {
+				errs = checkValue(errs, fmt.Sprintf("%s[%d]", path, i), spec.Items, rv.Index(i).Interface())
+			}
+		}
+
+	case "map":
+		if rv.Kind() != reflect.Map {
+			return fail(wire.ConstraintType, nil, "expected map, got %s", typeName(rv))
+		}
+		for _, key := range sortedKeys(rv) {
+			entryPath := fmt.Sprintf("%s[%v]", path, key.Interface())
+			if spec.Key != nil {
+				errs = checkValue(errs, entryPath, spec.Key, key.Interface())
+			}
+			if spec.Value != nil {
+				errs = checkValue(errs, entryPath, spec.Value, rv.MapIndex(key).Interface())
+			}
+		}
+
+	case "object":
+		switch rv.Kind() {
+		case reflect.Struct:
+			// Typed structs from in-process callers are already well-formed.
+		case reflect.Map:
+			for _, key := range sortedKeys(rv) {
+				name, ok := key.Interface().(string)
+				if !ok {
+					continue
+				}
+				if field := spec.Fields[name]; field != nil {
+					errs = checkValue(errs, path+"."+name, field, rv.MapIndex(key).Interface())
+				}
+			}
+		default:
+			return fail(wire.ConstraintType, nil, "expected object, got %s", typeName(rv))
+		}
+	}
+	return errs
+}
+
+// integerRanges holds the value range of each integer wire type.
+var integerRanges = map[string]struct {
+	min int64
+	max uint64
+}{
+	"uint8":  {0, 1<<8 - 1},
+	"uint16": {0, 1<<16 - 1},
+	"uint32": {0, 1<<32 - 1},
+	"uint64": {0, 1<<64 - 1},
+	"int8":   {-1 << 7, 1<<7 - 1},
+	"int16":  {-1 << 15, 1<<15 - 1},
+	"int32":  {-1 << 31, 1<<31 - 1},
+	"int64":  {-1 << 63, 1<<63 - 1},
+}
+
+// checkInteger checks an integer of any Go kind against the width, enum and
+// range of spec.
+func checkInteger(errs []wire.FieldError, path string, spec *TypeSpec, rv reflect.Value) []wire.FieldError {
+	fail := func(c wire.Constraint, limit any, format string, args ...any) []wire.FieldError {
+		return append(errs, wire.FieldError{
+			Path:       path,
+			Message:    fmt.Sprintf(format, args...),
+			Constraint: c,
+			Limit:      limit,
+		})
+	}
+
+	var neg bool
+	var i int64
+	var u uint64
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i = rv.Int()
+		neg = i < 0
+		u = uint64(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		u = rv.Uint()
+		i = int64(u)
+	default:
+		return fail(wire.ConstraintType, nil, "expected %s, got %s", spec.Type, typeName(rv))
+	}
+
+	var shown any = u
+	if neg {
+		shown = i
+	}
+
+	r := integerRanges[spec.Type]
+	if neg && i < r.min {
+		return fail(wire.ConstraintMin, r.min, "value %d out of range for %s", shown, spec.Type)
+	}
+	if !neg && u > r.max {
+		return fail(wire.ConstraintMax, r.max, "value %d out of range for %s", shown, spec.Type)
+	}
+	// Values above the int64 range (uint64 only) are compared as unsigned.
+	large := !neg && u > 1<<63-1
+
+	if len(spec.Enum) > 0 && (large || !slices.Contains(spec.Enum, i)) {
+		return fail(wire.ConstraintEnum, nil, "value %d not in enum %v", shown, spec.Enum)
+	}
+	if spec.Min != nil && !large && i < *spec.Min {
+		return fail(wire.ConstraintMin, *spec.Min, "value %d below minimum %d", shown, *spec.Min)
+	}
+	if spec.Max != nil && (large || i > *spec.Max) {
+		return fail(wire.ConstraintMax, *spec.Max, "value %d above maximum %d", shown, *spec.Max)
+	}
+	return errs
+}
+
+// isNull reports whether v is a null value: nil or a nil pointer.
+func isNull(v any) bool {
+	if v == nil {
+		return true
+	}
+	rv := reflect.ValueOf(v)
+	return rv.Kind() == reflect.Pointer && rv.IsNil()
+}
+
+// typeName names the type of a value in error messages.
+func typeName(rv reflect.Value) string {
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Array:
+		return "array"
+	case reflect.Map:
+		return "map"
+	case reflect.Struct:
+		return "object"
+	default:
+		return rv.Kind().String()
+	}
+}
+
+// sortedKeys returns the keys of a map in a stable order for error reporting.
+func sortedKeys(rv reflect.Value) []reflect.Value {
+	keys := rv.MapKeys()
+	sort.Slice(keys, func(i, j int) bool {
+		return fmt.Sprint(keys[i].Interface()) < fmt.Sprint(keys[j].Interface())
+	})
+	return keys
+}
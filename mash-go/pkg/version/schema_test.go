@@ -0,0 +1,186 @@
+package version
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/mash-protocol/mash-go/pkg/wire"
+)
+
+const (
+	testFeatureEnergyControl = 0x05
+	testFeatureSignals       = 0x08
+	testCmdSendPriceSignal   = 1
+)
+
+func TestValidateWrite(t *testing.T) {
+	v := CurrentValidator()
+
+	tests := []struct {
+		name  string
+		attrs map[uint16]any
+		want  []wire.FieldError
+	}{
+		{
+			name:  "valid values of any integer kind",
+			attrs: map[uint16]any{3: uint64(1), 21: int64(-5000), 72: 7200},
+		},
+		{
+			name:  "null for nullable attribute",
+			attrs: map[uint16]any{21: nil},
+		},
+		{
+			name:  "read-only and unknown attributes are not checked",
+			attrs: map[uint16]any{1: "x", 999: "y"},
+		},
+		{
+			name:  "wrong type",
+			attrs: map[uint16]any{21: "5000"},
+			want:  []wire.FieldError{{Path: "myConsumptionLimit", Message: "expected int64, got string", Constraint: wire.ConstraintType}},
+		},
+		{
+			name:  "enum, range and nullability in ID order",
+			attrs: map[uint16]any{72: uint64(60), 3: uint64(9), 70: int64(1)},
+			want: []wire.FieldError{
+				{Path: "optOutState", Message: "value 9 not in enum [0 1 2 3]", Constraint: wire.ConstraintEnum},
+				{Path: "failsafeDuration", Message: "value 60 below minimum 7200", Constraint: wire.ConstraintMin, Limit: int64(7200)},
+			},
+		},
+		{
+			name:  "null for non-nullable attribute",
+			attrs: map[uint16]any{72: nil},
+			want:  []wire.FieldError{{Path: "failsafeDuration", Message: "must not be null", Constraint: wire.ConstraintNotNull}},
+		},
+		{
+			name:  "integer wider than type",
+			attrs: map[uint16]any{72: uint64(1 << 40)},
+			want:  []wire.FieldError{{Path: "failsafeDuration", Message: "value 1099511627776 out of range for uint32", Constraint: wire.ConstraintMax, Limit: uint64(1<<32 - 1)}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := v.ValidateWrite(testFeatureEnergyControl, tt.attrs)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ValidateWrite() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateInvoke(t *testing.T) {
+	v := CurrentValidator()
+
+	slot := func(duration any) map[any]any {
+		return map[any]any{"duration": duration, "price": int64(-20), "priceLevel": nil}
+	}
+
+	tests := []struct {
+		name   string
+		params map[string]any
+		want   []wire.FieldError
+	}{
+		{
+			name: "valid CBOR-decoded payload",
+			params: map[string]any{
+				"source":    uint64(1),
+				"startTime": uint64(1700000000),
+				"slots":     []any{slot(uint64(900)), slot(uint64(900))},
+			},
+		},
+		{
+			name: "null optional parameter",
+			params: map[string]any{
+				"source":     uint64(1),
+				"startTime":  uint64(1700000000),
+				"validUntil": nil,
+				"slots":      []any{},
+			},
+		},
+		{
+			name:   "missing required parameters",
+			params: map[string]any{"source": uint64(1)},
+			want: []wire.FieldError{
+				{Path: "slots", Message: "required parameter missing", Constraint: wire.ConstraintRequired},
+				{Path: "startTime", Message: "required parameter missing", Constraint: wire.ConstraintRequired},
+			},
+		},
+		{
+			name: "nested slot field",
+			params: map[string]any{
+				"source":    uint64(1),
+				"startTime": uint64(1700000000),
+				"slots":     []any{slot(uint64(900)), slot("15m"), slot(nil)},
+			},
+			want: []wire.FieldError{
+				{Path: "slots[1].duration", Message: "expected uint32, got string", Constraint: wire.ConstraintType},
+				{Path: "slots[2].duration", Message: "must not be null", Constraint: wire.ConstraintNotNull},
+			},
+		},
+		{
+			name: "slots not an array",
+			params: map[string]any{
+				"source":    uint64(1),
+				"startTime": uint64(1700000000),
+				"slots":     map[any]any{},
+			},
+			want: []wire.FieldError{{Path: "slots", Message: "expected array, got map", Constraint: wire.ConstraintType}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := v.ValidateInvoke(testFeatureSignals, testCmdSendPriceSignal, tt.params)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ValidateInvoke() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateMaxItems(t *testing.T) {
+	spec := &SpecManifest{Features: map[string]FeatureSpec{
+		"Test": {
+			ID: 0x80,
+			Schema: FeatureSchema{Commands: map[uint8]map[string]*TypeSpec{
+				1: {"slots": {Type: "array", Required: true, MaxItems: 3, Items: &TypeSpec{Type: "uint32"}}},
+			}},
+		},
+	}}
+	v := NewValidator(spec)
+
+	if errs := v.ValidateInvoke(0x80, 1, map[string]any{"slots": []any{uint64(1), uint64(2), uint64(3)}}); errs != nil {
+		t.Errorf("3 items: errs = %+v, want nil", errs)
+	}
+	got := v.ValidateInvoke(0x80, 1, map[string]any{"slots": make([]any, 4)})
+	want := []wire.FieldError{{Path: "slots", Message: "has 4 items, maximum is 3", Constraint: wire.ConstraintMaxItems, Limit: int64(3)}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ValidateInvoke() = %+v, want %+v", got, want)
+	}
+}
+
+func TestValidateMapEntries(t *testing.T) {
+	spec := &SpecManifest{Features: map[string]FeatureSpec{
+		"Test": {
+			ID:         0x80,
+			Attributes: AttributeSpec{Optional: []AttrDef{{ID: 1, Name: "perPhase"}}},
+			Schema: FeatureSchema{Attributes: map[uint16]*TypeSpec{
+				1: {Type: "map", Key: &TypeSpec{Type: "uint8", Enum: []int64{0, 1, 2}}, Value: &TypeSpec{Type: "int64"}},
+			}},
+		},
+	}}
+	v := NewValidator(spec)
+
+	got := v.ValidateWrite(0x80, map[uint16]any{1: map[any]any{uint64(0): int64(1), uint64(3): "x"}})
+	want := []wire.FieldError{
+		{Path: "perPhase[3]", Message: "value 3 not in enum [0 1 2]", Constraint: wire.ConstraintEnum},
+		{Path: "perPhase[3]", Message: "expected int64, got string", Constraint: wire.ConstraintType},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ValidateWrite() = %+v, want %+v", got, want)
+	}
+
+	if errs := v.ValidateWrite(0x81, map[uint16]any{1: "x"}); errs != nil {
+		t.Errorf("unknown feature: errs = %+v, want nil", errs)
+	}
+}
@@ -27,6 +27,7 @@ type FeatureSpec struct {
 	Mandatory  bool          `yaml:"mandatory"`
 	Attributes AttributeSpec `yaml:"attributes"`
 	Commands   CommandSpec   `yaml:"commands"`
+	Schema     FeatureSchema `yaml:"schema"`
 }
 
 // AttributeSpec lists the mandatory and optional attributes of a feature.
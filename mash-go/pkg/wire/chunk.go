@@ -0,0 +1,243 @@
+package wire
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+)
+
+// Responses that do not fit in one transport frame are split into chunks.
+// The response payload is CBOR-encoded once and the encoding is cut into
+// byte-string segments. Each chunk is a Response carrying the original
+// messageId and status, one segment as its payload, a 1-based chunk
+// sequence number and a more flag set on all but the last chunk.
+const (
+	// MaxChunkedPayloadSize is the largest encoded payload that may be
+	// split into chunks or reassembled from them.
+	MaxChunkedPayloadSize = 1 << 20
+
+	// chunkHeaderSlack covers the growth of the byte-string length header
+	// from an empty segment (1 byte) to a full one (up to 5 bytes).
+	chunkHeaderSlack = 4
+)
+
+// Chunking errors.
+var (
+	ErrResponseTooLarge  = errors.New("response exceeds maximum chunked size")
+	ErrChunkSizeTooSmall = errors.New("maximum message size too small for chunking")
+	ErrChunkSequence     = errors.New("chunk out of sequence")
+	ErrInvalidChunk      = errors.New("invalid chunk")
+
+	ErrNotificationTooLarge = errors.New("notification exceeds maximum message size")
+)
+
+// IsChunk returns true if the response is one chunk of a larger response.
+func (r *Response) IsChunk() bool {
+	return r.Chunk != 0
+}
+
+// EncodeResponseChunks encodes a response into one or more frames of at
+// most maxSize bytes each. A response that fits is encoded unchanged as a
+// single frame; otherwise its payload is split into chunks. A maxSize of
+// zero disables chunking.
+func EncodeResponseChunks(resp *Response, maxSize int) ([][]byte, error) {
+	data, err := EncodeResponse(resp)
+	if err != nil {
+		return nil, err
+	}
+	if maxSize <= 0 || len(data) <= maxSize {
+		return [][]byte{data}, nil
+	}
+
+	payload, err := Marshal(resp.Payload)
+	if err != nil {
+		return nil, err
+	}
+	if len(payload) > MaxChunkedPayloadSize {
+		return nil, fmt.Errorf("%w: %d > %d", ErrResponseTooLarge, len(payload), MaxChunkedPayloadSize)
+	}
+
+	// Size the segments from the largest possible empty chunk.
+	probe, err := EncodeResponse(&Response{
+		MessageID: resp.MessageID,
+		Status:    resp.Status,
+		Payload:   []byte{},
+		Chunk:     ^uint16(0),
+		More:      true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	segSize := maxSize - len(probe) - chunkHeaderSlack
+	if segSize <= 0 {
+		return nil, fmt.Errorf("%w: %d", ErrChunkSizeTooSmall, maxSize)
+	}
+	count := (len(payload) + segSize - 1) / segSize
+	if count > int(^uint16(0)) {
+		return nil, fmt.Errorf("%w: %d chunks", ErrResponseTooLarge, count)
+	}
+
+	frames := make([][]byte, 0, count)
+	for i := 0; i < count; i++ {
+		end := min((i+1)*segSize, len(payload))
+		frame, err := EncodeResponse(&Response{
+			MessageID: resp.MessageID,
+			Status:    resp.Status,
+			Payload:   payload[i*segSize : end],
+			Chunk:     uint16(i + 1),
+			More:      i < count-1,
+		})
+		if err != nil {
+			return nil, err
+		}
+		frames = append(frames, frame)
+	}
+	return frames, nil
+}
+
+// EncodeNotificationFrames encodes a notification into one or more frames
+// of at most maxSize bytes each. Notifications are not chunked: one that
+// does not fit is split into several notifications for the same
+// subscription, endpoint and feature, each carrying some of the changes,
+// which a receiver applies like any others. A change too large for a frame
+// on its own fails with ErrNotificationTooLarge. A maxSize of zero
+// disables splitting.
+func EncodeNotificationFrames(notif *Notification, maxSize int) ([][]byte, error) {
+	data, err := EncodeNotification(notif)
+	if err != nil {
+		return nil, err
+	}
+	if maxSize <= 0 || len(data) <= maxSize {
+		return [][]byte{data}, nil
+	}
+
+	attrIDs := make([]uint16, 0, len(notif.Changes))
+	for attrID := range notif.Changes {
+		attrIDs = append(attrIDs, attrID)
+	}
+	slices.Sort(attrIDs)
+	if len(attrIDs) == 0 {
+		return nil, fmt.Errorf("%w: %d > %d", ErrNotificationTooLarge, len(data), maxSize)
+	}
+
+	var frames [][]byte
+	part := &Notification{
+		SubscriptionID: notif.SubscriptionID,
+		EndpointID:     notif.EndpointID,
+		FeatureID:      notif.FeatureID,
+		Changes:        make(map[uint16]any),
+	}
+	var last []byte
+	for _, attrID := range attrIDs {
+		part.Changes[attrID] = notif.Changes[attrID]
+		data, err := EncodeNotification(part)
+		if err != nil {
+			return nil, err
+		}
+		if len(data) <= maxSize {
+			last = data
+			continue
+		}
+		if len(part.Changes) == 1 {
+			return nil, fmt.Errorf("%w: attribute %d: %d > %d", ErrNotificationTooLarge, attrID, len(data), maxSize)
+		}
+
+		// Flush the changes that fit and start the next frame with attrID
+		frames = append(frames, last)
+		part.Changes = map[uint16]any{attrID: notif.Changes[attrID]}
+		if last, err = EncodeNotification(part); err != nil {
+			return nil, err
+		}
+		if len(last) > maxSize {
+			return nil, fmt.Errorf("%w: attribute %d: %d > %d", ErrNotificationTooLarge, attrID, len(last), maxSize)
+		}
+	}
+	return append(frames, last), nil
+}
+
+// ChunkAssembler reassembles chunked responses, keyed by messageId.
+// It is safe for concurrent use.
+type ChunkAssembler struct {
+	mu      sync.Mutex
+	pending map[uint32]*chunkBuffer
+}
+
+type chunkBuffer struct {
+	status Status
+	next   uint16
+	data   []byte
+}
+
+// NewChunkAssembler creates an empty chunk assembler.
+func NewChunkAssembler() *ChunkAssembler {
+	return &ChunkAssembler{
+		pending: make(map[uint32]*chunkBuffer),
+	}
+}
+
+// Add feeds a received response into the assembler. Unchunked responses
+// are returned unchanged. For chunked responses Add returns nil until the
+// last chunk arrives, then returns the reassembled response. On error the
+// partial response for that messageId is discarded.
+func (a *ChunkAssembler) Add(resp *Response) (*Response, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if !resp.IsChunk() {
+		delete(a.pending, resp.MessageID)
+		return resp, nil
+	}
+
+	segment, ok := resp.Payload.([]byte)
+	if !ok && resp.Payload != nil {
+		delete(a.pending, resp.MessageID)
+		return nil, fmt.Errorf("%w: message %d: payload is %T", ErrInvalidChunk, resp.MessageID, resp.Payload)
+	}
+
+	buf := a.pending[resp.MessageID]
+	if resp.Chunk == 1 {
+		buf = &chunkBuffer{status: resp.Status, next: 1}
+		a.pending[resp.MessageID] = buf
+	}
+	if buf == nil || resp.Chunk != buf.next {
+		delete(a.pending, resp.MessageID)
+		return nil, fmt.Errorf("%w: message %d: chunk %d", ErrChunkSequence, resp.MessageID, resp.Chunk)
+	}
+	if len(buf.data)+len(segment) > MaxChunkedPayloadSize {
+		delete(a.pending, resp.MessageID)
+		return nil, fmt.Errorf("%w: message %d", ErrResponseTooLarge, resp.MessageID)
+	}
+
+	buf.data = append(buf.data, segment...)
+	buf.next++
+	if resp.More {
+		return nil, nil
+	}
+
+	delete(a.pending, resp.MessageID)
+	var payload any
+	if err := Unmarshal(buf.data, &payload); err != nil {
+		return nil, fmt.Errorf("%w: message %d: %v", ErrInvalidChunk, resp.MessageID, err)
+	}
+	return &Response{
+		MessageID: resp.MessageID,
+		Status:    buf.status,
+		Payload:   payload,
+	}, nil
+}
+
+// Discard drops any partial response for messageID, e.g. after the
+// request timed out.
+func (a *ChunkAssembler) Discard(messageID uint32) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	delete(a.pending, messageID)
+}
+
+// Pending returns the number of partially received responses.
+func (a *ChunkAssembler) Pending() int {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return len(a.pending)
+}
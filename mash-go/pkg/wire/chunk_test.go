@@ -0,0 +1,238 @@
+package wire
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// largeResponse returns a Read response whose payload holds a string of n
+// bytes.
+func largeResponse(n int) *Response {
+	return &Response{
+		MessageID: 42,
+		Status:    StatusSuccess,
+		Payload:   map[uint16]any{1: strings.Repeat("x", n)},
+	}
+}
+
+// reassemble decodes frames and feeds them through a fresh assembler.
+func reassemble(t *testing.T, frames [][]byte) *Response {
+	t.Helper()
+	a := NewChunkAssembler()
+	var out *Response
+	for i, frame := range frames {
+		msgType, err := PeekMessageType(frame)
+		if err != nil || msgType != MessageTypeResponse {
+			t.Fatalf("frame %d: PeekMessageType = %v, %v; want response", i, msgType, err)
+		}
+		resp, err := DecodeResponse(frame)
+		if err != nil {
+			t.Fatalf("frame %d: decode: %v", i, err)
+		}
+		got, err := a.Add(resp)
+		if err != nil {
+			t.Fatalf("frame %d: Add: %v", i, err)
+		}
+		if got != nil && i != len(frames)-1 {
+			t.Fatalf("frame %d: response completed early", i)
+		}
+		out = got
+	}
+	if out == nil {
+		t.Fatal("response not completed")
+	}
+	if a.Pending() != 0 {
+		t.Errorf("Pending() = %d after completion, want 0", a.Pending())
+	}
+	return out
+}
+
+func assertSamePayload(t *testing.T, got, want any) {
+	t.Helper()
+	gotData, err := Marshal(got)
+	if err != nil {
+		t.Fatalf("marshal got: %v", err)
+	}
+	wantData, err := Marshal(want)
+	if err != nil {
+		t.Fatalf("marshal want: %v", err)
+	}
+	if !bytes.Equal(gotData, wantData) {
+		t.Errorf("payload mismatch: got %d bytes, want %d bytes", len(gotData), len(wantData))
+	}
+}
+
+func TestEncodeResponseChunksBoundary(t *testing.T) {
+	resp := largeResponse(8000)
+	data, err := EncodeResponse(resp)
+	if err != nil {
+		t.Fatalf("encode: %v", err)
+	}
+
+	// Exactly at the limit: sent as a single, unchunked frame.
+	frames, err := EncodeResponseChunks(resp, len(data))
+	if err != nil {
+		t.Fatalf("EncodeResponseChunks at limit: %v", err)
+	}
+	if len(frames) != 1 || !bytes.Equal(frames[0], data) {
+		t.Fatalf("at limit: got %d frames, want the plain encoding", len(frames))
+	}
+
+	// One byte over the limit: chunked, each frame within the limit.
+	maxSize := len(data) - 1
+	frames, err = EncodeResponseChunks(resp, maxSize)
+	if err != nil {
+		t.Fatalf("EncodeResponseChunks over limit: %v", err)
+	}
+	if len(frames) < 2 {
+		t.Fatalf("over limit: got %d frames, want at least 2", len(frames))
+	}
+	for i, frame := range frames {
+		if len(frame) > maxSize {
+			t.Errorf("frame %d: %d bytes exceeds %d", i, len(frame), maxSize)
+		}
+	}
+
+	got := reassemble(t, frames)
+	if got.MessageID != resp.MessageID || got.Status != resp.Status || got.IsChunk() {
+		t.Errorf("reassembled header = {%d %v %d}, want {%d %v 0}", got.MessageID, got.Status, got.Chunk, resp.MessageID, resp.Status)
+	}
+	assertSamePayload(t, got.Payload, resp.Payload)
+}
+
+func TestEncodeResponseChunksManyFrames(t *testing.T) {
+	resp := largeResponse(50000)
+	frames, err := EncodeResponseChunks(resp, 8192)
+	if err != nil {
+		t.Fatalf("EncodeResponseChunks: %v", err)
+	}
+	if len(frames) != 7 {
+		t.Errorf("got %d frames, want 7", len(frames))
+	}
+	for i, frame := range frames {
+		if len(frame) > 8192 {
+			t.Errorf("frame %d: %d bytes exceeds 8192", i, len(frame))
+		}
+	}
+	assertSamePayload(t, reassemble(t, frames).Payload, resp.Payload)
+}
+
+func TestEncodeResponseChunksErrors(t *testing.T) {
+	if _, err := EncodeResponseChunks(largeResponse(100), 8); !errors.Is(err, ErrChunkSizeTooSmall) {
+		t.Errorf("tiny maxSize: err = %v, want ErrChunkSizeTooSmall", err)
+	}
+	if _, err := EncodeResponseChunks(largeResponse(MaxChunkedPayloadSize), 8192); !errors.Is(err, ErrResponseTooLarge) {
+		t.Errorf("oversized payload: err = %v, want ErrResponseTooLarge", err)
+	}
+
+	frames, err := EncodeResponseChunks(largeResponse(100), 0)
+	if err != nil || len(frames) != 1 {
+		t.Errorf("maxSize 0: got %d frames, %v; want 1 frame", len(frames), err)
+	}
+}
+
+func TestChunkAssemblerSequence(t *testing.T) {
+	frames, err := EncodeResponseChunks(largeResponse(3000), 1024)
+	if err != nil {
+		t.Fatalf("EncodeResponseChunks: %v", err)
+	}
+	chunks := make([]*Response, len(frames))
+	for i, frame := range frames {
+		if chunks[i], err = DecodeResponse(frame); err != nil {
+			t.Fatalf("decode frame %d: %v", i, err)
+		}
+	}
+
+	t.Run("missing first chunk", func(t *testing.T) {
+		a := NewChunkAssembler()
+		if _, err := a.Add(chunks[1]); !errors.Is(err, ErrChunkSequence) {
+			t.Errorf("err = %v, want ErrChunkSequence", err)
+		}
+	})
+
+	t.Run("skipped chunk", func(t *testing.T) {
+		a := NewChunkAssembler()
+		if _, err := a.Add(chunks[0]); err != nil {
+			t.Fatalf("Add first: %v", err)
+		}
+		if _, err := a.Add(chunks[2]); !errors.Is(err, ErrChunkSequence) {
+			t.Errorf("err = %v, want ErrChunkSequence", err)
+		}
+		if a.Pending() != 0 {
+			t.Errorf("Pending() = %d after error, want 0", a.Pending())
+		}
+	})
+
+	t.Run("discard", func(t *testing.T) {
+		a := NewChunkAssembler()
+		if _, err := a.Add(chunks[0]); err != nil {
+			t.Fatalf("Add first: %v", err)
+		}
+		a.Discard(chunks[0].MessageID)
+		if a.Pending() != 0 {
+			t.Errorf("Pending() = %d after Discard, want 0", a.Pending())
+		}
+	})
+
+	t.Run("unchunked passes through", func(t *testing.T) {
+		a := NewChunkAssembler()
+		resp := &Response{MessageID: 7, Status: StatusSuccess}
+		got, err := a.Add(resp)
+		if err != nil || got != resp {
+			t.Errorf("Add = %v, %v; want the same response", got, err)
+		}
+	})
+}
+
+func TestEncodeNotificationFrames(t *testing.T) {
+	notif := &Notification{SubscriptionID: 7, EndpointID: 1, FeatureID: 2, Changes: map[uint16]any{}}
+	for attrID := uint16(1); attrID <= 10; attrID++ {
+		notif.Changes[attrID] = strings.Repeat("x", 2000)
+	}
+
+	frames, err := EncodeNotificationFrames(notif, 8192)
+	if err != nil {
+		t.Fatalf("EncodeNotificationFrames: %v", err)
+	}
+	if len(frames) != 3 {
+		t.Errorf("got %d frames, want 3", len(frames))
+	}
+	got := make(map[uint16]any)
+	for i, frame := range frames {
+		if len(frame) > 8192 {
+			t.Errorf("frame %d: %d bytes exceeds 8192", i, len(frame))
+		}
+		if msgType, err := PeekMessageType(frame); err != nil || msgType != MessageTypeNotification {
+			t.Fatalf("frame %d: PeekMessageType = %v, %v; want notification", i, msgType, err)
+		}
+		part, err := DecodeNotification(frame)
+		if err != nil {
+			t.Fatalf("frame %d: decode: %v", i, err)
+		}
+		if part.SubscriptionID != 7 || part.EndpointID != 1 || part.FeatureID != 2 {
+			t.Errorf("frame %d: header = {%d %d %d}, want {7 1 2}", i, part.SubscriptionID, part.EndpointID, part.FeatureID)
+		}
+		for attrID, value := range part.Changes {
+			got[attrID] = value
+		}
+	}
+	assertSamePayload(t, got, notif.Changes)
+
+	// A notification that fits is a single frame.
+	frames, err = EncodeNotificationFrames(notif, 0)
+	if err != nil || len(frames) != 1 {
+		t.Errorf("maxSize 0: got %d frames, %v; want 1 frame", len(frames), err)
+	}
+}
+
+func TestEncodeNotificationFramesTooLarge(t *testing.T) {
+	notif := &Notification{SubscriptionID: 7, Changes: map[uint16]any{
+		1: "small",
+		2: strings.Repeat("x", 9000),
+	}}
+	if _, err := EncodeNotificationFrames(notif, 8192); !errors.Is(err, ErrNotificationTooLarge) {
+		t.Errorf("err = %v, want ErrNotificationTooLarge", err)
+	}
+}
@@ -145,6 +145,59 @@ func DecodeNotification(data []byte) (*Notification, error) {
 	}, nil
 }
 
+// eventNotificationWire is the wire format of an EventNotification.
+type eventNotificationWire struct {
+	MessageID      uint32  `cbor:"1,keyasint"`
+	SubscriptionID uint32  `cbor:"2,keyasint"`
+	EndpointID     uint8   `cbor:"3,keyasint"`
+	FeatureID      uint8   `cbor:"4,keyasint"`
+	Events         []Event `cbor:"6,keyasint"`
+}
+
+// EncodeEventNotification encodes an event notification to CBOR bytes.
+// Like attribute notifications, event notifications have messageId=0.
+func EncodeEventNotification(notif *EventNotification) ([]byte, error) {
+	return Marshal(eventNotificationWire{
+		MessageID:      NotificationMessageID,
+		SubscriptionID: notif.SubscriptionID,
+		EndpointID:     notif.EndpointID,
+		FeatureID:      notif.FeatureID,
+		Events:         notif.Events,
+	})
+}
+
+// DecodeEventNotification decodes CBOR bytes into an event notification.
+func DecodeEventNotification(data []byte) (*EventNotification, error) {
+	var wireMsg eventNotificationWire
+	if err := Unmarshal(data, &wireMsg); err != nil {
+		return nil, fmt.Errorf("failed to decode event notification: %w", err)
+	}
+	if wireMsg.MessageID != NotificationMessageID {
+		return nil, fmt.Errorf("not a notification message: messageId=%d", wireMsg.MessageID)
+	}
+	if wireMsg.Events == nil {
+		return nil, fmt.Errorf("not an event notification: missing events")
+	}
+	return &EventNotification{
+		SubscriptionID: wireMsg.SubscriptionID,
+		EndpointID:     wireMsg.EndpointID,
+		FeatureID:      wireMsg.FeatureID,
+		Events:         wireMsg.Events,
+	}, nil
+}
+
+// IsEventNotification reports whether data is a notification carrying
+// event records (key 6) rather than attribute changes. It should only be
+// called on messages PeekMessageType classified as notifications.
+func IsEventNotification(data []byte) bool {
+	var rawMsg map[uint64]cbor.RawMessage
+	if err := Unmarshal(data, &rawMsg); err != nil {
+		return false
+	}
+	_, hasEvents := rawMsg[KeyEvents]
+	return hasEvents
+}
+
 // EncodeControlMessage encodes a control message (ping/pong/close) to CBOR bytes.
 func EncodeControlMessage(msg *ControlMessage) ([]byte, error) {
 	return Marshal(msg)
@@ -237,8 +290,8 @@ func PeekMessageType(data []byte) (MessageType, error) {
 		}
 	}
 
-	// If field 2 is a valid status (0-13) and > 4 (not a valid operation), it's Response
-	// Status values 5-13 are not valid Operation values
+	// If field 2 is a valid status (0-14) and > 4 (not a valid operation), it's Response
+	// Status values 5-14 are not valid Operation values
 	if field2 > 4 {
 		return MessageTypeResponse, nil
 	}
@@ -168,6 +168,40 @@ func TestResponseRoundTrip(t *testing.T) {
 	}
 }
 
+func TestErrorPayloadFieldsRoundTrip(t *testing.T) {
+	resp := Response{
+		MessageID: 4,
+		Status:    StatusInvalidParameter,
+		Payload: &ErrorPayload{
+			Message: "invalid parameters",
+			Fields: []FieldError{
+				{Path: "slots[3].duration", Message: "expected uint32, got string"},
+				{Path: "source", Message: "required parameter missing"},
+			},
+		},
+	}
+
+	data, err := EncodeResponse(&resp)
+	if err != nil {
+		t.Fatalf("EncodeResponse failed: %v", err)
+	}
+	decoded, err := DecodeResponse(data)
+	if err != nil {
+		t.Fatalf("DecodeResponse failed: %v", err)
+	}
+
+	ep := ExtractErrorPayload(decoded.Payload)
+	if ep == nil {
+		t.Fatal("ExtractErrorPayload returned nil")
+	}
+	if len(ep.Fields) != 2 || ep.Fields[0] != resp.Payload.(*ErrorPayload).Fields[0] {
+		t.Errorf("Fields = %+v", ep.Fields)
+	}
+	if got := ep.Fields[1].Error(); got != "source: required parameter missing" {
+		t.Errorf("Error() = %q", got)
+	}
+}
+
 func TestNotificationRoundTrip(t *testing.T) {
 	notif := Notification{
 		SubscriptionID: 5001,
@@ -660,3 +694,236 @@ func TestNotificationChangesTypeCoercion(t *testing.T) {
 		t.Logf("Note: Direct int64 assertion failed (type is %T), ToInt64 correctly handles this", rawVal)
 	}
 }
+
+func TestEventNotificationRoundTrip(t *testing.T) {
+	notif := EventNotification{
+		SubscriptionID: 7001,
+		EndpointID:     1,
+		FeatureID:      0x06,
+		Events: []Event{
+			{EventNumber: 41, Timestamp: 1706180400000, EndpointID: 1, FeatureID: 0x06, EventID: 1,
+				Data: map[string]any{"faultCode": uint64(42)}},
+			{EventNumber: 42, Timestamp: 1706180400500, EndpointID: 1, FeatureID: 0x06, EventID: 2},
+		},
+	}
+
+	data, err := EncodeEventNotification(&notif)
+	if err != nil {
+		t.Fatalf("EncodeEventNotification failed: %v", err)
+	}
+
+	msgType, err := PeekMessageType(data)
+	if err != nil {
+		t.Fatalf("PeekMessageType failed: %v", err)
+	}
+	if msgType != MessageTypeNotification {
+		t.Errorf("expected MessageTypeNotification, got %v", msgType)
+	}
+	if !IsEventNotification(data) {
+		t.Error("expected IsEventNotification to be true")
+	}
+
+	decoded, err := DecodeEventNotification(data)
+	if err != nil {
+		t.Fatalf("DecodeEventNotification failed: %v", err)
+	}
+	if decoded.SubscriptionID != notif.SubscriptionID {
+		t.Errorf("SubscriptionID mismatch: got %d, want %d", decoded.SubscriptionID, notif.SubscriptionID)
+	}
+	if len(decoded.Events) != 2 {
+		t.Fatalf("expected 2 events, got %d", len(decoded.Events))
+	}
+	if decoded.Events[0].EventNumber != 41 || decoded.Events[1].EventID != 2 {
+		t.Errorf("unexpected events: %+v", decoded.Events)
+	}
+	if decoded.Events[0].Timestamp != 1706180400000 {
+		t.Errorf("Timestamp mismatch: got %d", decoded.Events[0].Timestamp)
+	}
+	fields := ToStringMap(decoded.Events[0].Data)
+	if code, _ := ToUint32(fields["faultCode"]); code != 42 {
+		t.Errorf("expected faultCode 42, got %v", fields["faultCode"])
+	}
+}
+
+func TestIsEventNotificationAttributeNotification(t *testing.T) {
+	data, err := EncodeNotification(&Notification{
+		SubscriptionID: 1,
+		EndpointID:     1,
+		FeatureID:      2,
+		Changes:        map[uint16]any{1: int64(100)},
+	})
+	if err != nil {
+		t.Fatalf("EncodeNotification failed: %v", err)
+	}
+	if IsEventNotification(data) {
+		t.Error("attribute notification must not be reported as event notification")
+	}
+	if _, err := DecodeEventNotification(data); err == nil {
+		t.Error("expected DecodeEventNotification to reject attribute notification")
+	}
+}
+
+func TestSubscribePayloadEventIDs(t *testing.T) {
+	req := Request{
+		MessageID:  10,
+		Operation:  OpSubscribe,
+		EndpointID: 1,
+		FeatureID:  0x06,
+		Payload: &SubscribePayload{
+			EventIDs: EventIDList{1, 2},
+			EventMin: 40,
+		},
+	}
+
+	data, err := EncodeRequest(&req)
+	if err != nil {
+		t.Fatalf("EncodeRequest failed: %v", err)
+	}
+
+	// eventIds must be encoded as an array, not a byte string
+	var raw map[uint64]any
+	if err := Unmarshal(data, &raw); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	payload, ok := raw[KeyPayload].(map[any]any)
+	if !ok {
+		t.Fatalf("expected map payload, got %T", raw[KeyPayload])
+	}
+	if _, ok := payload[uint64(4)].([]any); !ok {
+		t.Errorf("expected eventIds encoded as array, got %T", payload[uint64(4)])
+	}
+
+	decoded, err := DecodeRequest(data)
+	if err != nil {
+		t.Fatalf("DecodeRequest failed: %v", err)
+	}
+	sp := ExtractSubscribePayload(decoded.Payload)
+	if sp == nil {
+		t.Fatal("ExtractSubscribePayload returned nil")
+	}
+	if len(sp.EventIDs) != 2 || sp.EventIDs[0] != 1 || sp.EventIDs[1] != 2 {
+		t.Errorf("expected eventIds [1 2], got %v", sp.EventIDs)
+	}
+	if sp.EventMin != 40 {
+		t.Errorf("expected eventMin 40, got %d", sp.EventMin)
+	}
+	if !sp.IsEventOnly() {
+		t.Error("expected event-only subscription")
+	}
+}
+
+func TestSubscribeResponseEvents(t *testing.T) {
+	resp := Response{
+		MessageID: 10,
+		Status:    StatusSuccess,
+		Payload: &SubscribeResponsePayload{
+			SubscriptionID: 3,
+			Events: []Event{
+				{EventNumber: 5, Timestamp: 1000, EndpointID: 1, FeatureID: 0x06, EventID: 1},
+			},
+		},
+	}
+
+	data, err := EncodeResponse(&resp)
+	if err != nil {
+		t.Fatalf("EncodeResponse failed: %v", err)
+	}
+	decoded, err := DecodeResponse(data)
+	if err != nil {
+		t.Fatalf("DecodeResponse failed: %v", err)
+	}
+
+	m, ok := decoded.Payload.(map[any]any)
+	if !ok {
+		t.Fatalf("expected map payload, got %T", decoded.Payload)
+	}
+	events := ExtractEvents(m[uint64(3)])
+	if len(events) != 1 || events[0].EventNumber != 5 {
+		t.Errorf("expected replayed event 5, got %+v", events)
+	}
+}
+
+func TestMultiPathRequestRoundTrip(t *testing.T) {
+	req := Request{
+		MessageID:  11,
+		Operation:  OpSubscribe,
+		EndpointID: MultiPathEndpointID,
+		FeatureID:  MultiPathFeatureID,
+		Payload: &MultiPathPayload{
+			Paths: []AttributePath{
+				{EndpointID: 0, FeatureID: 0x01},
+				{EndpointID: 1, FeatureID: 0x05, AttributeIDs: []uint16{1, 20}},
+			},
+			MinInterval: 500,
+		},
+	}
+
+	data, err := EncodeRequest(&req)
+	if err != nil {
+		t.Fatalf("EncodeRequest failed: %v", err)
+	}
+	decoded, err := DecodeRequest(data)
+	if err != nil {
+		t.Fatalf("DecodeRequest failed: %v", err)
+	}
+	if !decoded.IsMultiPath() {
+		t.Fatal("expected multi-path request")
+	}
+
+	mp := ExtractMultiPathPayload(decoded.Payload)
+	if mp == nil {
+		t.Fatal("ExtractMultiPathPayload returned nil")
+	}
+	if len(mp.Paths) != 2 {
+		t.Fatalf("expected 2 paths, got %d", len(mp.Paths))
+	}
+	if p := mp.Paths[1]; p.EndpointID != 1 || p.FeatureID != 0x05 || len(p.AttributeIDs) != 2 || p.AttributeIDs[1] != 20 {
+		t.Errorf("unexpected second path: %+v", p)
+	}
+	if len(mp.Paths[0].AttributeIDs) != 0 {
+		t.Errorf("expected all attributes on first path, got %v", mp.Paths[0].AttributeIDs)
+	}
+	if mp.MinInterval != 500 {
+		t.Errorf("expected minInterval 500, got %d", mp.MinInterval)
+	}
+}
+
+func TestMultiPathResponseRoundTrip(t *testing.T) {
+	resp := Response{
+		MessageID: 11,
+		Status:    StatusSuccess,
+		Payload: &MultiPathResponsePayload{
+			SubscriptionID: 4,
+			Results: []PathResult{
+				{EndpointID: 0, FeatureID: 0x01, Status: StatusSuccess, Values: map[uint16]any{1: "dev-1"}},
+				{EndpointID: 9, FeatureID: 0x05, Status: StatusInvalidEndpoint},
+			},
+		},
+	}
+
+	data, err := EncodeResponse(&resp)
+	if err != nil {
+		t.Fatalf("EncodeResponse failed: %v", err)
+	}
+	decoded, err := DecodeResponse(data)
+	if err != nil {
+		t.Fatalf("DecodeResponse failed: %v", err)
+	}
+
+	mr := ExtractMultiPathResponse(decoded.Payload)
+	if mr == nil {
+		t.Fatal("ExtractMultiPathResponse returned nil")
+	}
+	if mr.SubscriptionID != 4 {
+		t.Errorf("expected subscriptionId 4, got %d", mr.SubscriptionID)
+	}
+	if len(mr.Results) != 2 {
+		t.Fatalf("expected 2 results, got %d", len(mr.Results))
+	}
+	if mr.Results[0].Values[1] != "dev-1" {
+		t.Errorf("expected value dev-1, got %v", mr.Results[0].Values[1])
+	}
+	if mr.Results[1].Status != StatusInvalidEndpoint || mr.Results[1].Values != nil {
+		t.Errorf("unexpected failed result: %+v", mr.Results[1])
+	}
+}
@@ -16,6 +16,11 @@ const (
 
 	// Notification-specific keys (messageId=0 indicates notification)
 	KeySubscriptionID = 2 // Replaces operation/status for notifications
+	KeyEvents         = 6 // Event records (event notifications only)
+
+	// Chunked response keys, distinct from all request and notification keys
+	KeyChunk = 7 // Chunk sequence number
+	KeyMore  = 8 // Further chunks follow
 )
 
 // MessageID 0 is reserved to indicate a notification message.
@@ -58,12 +63,20 @@ func (r *Request) Validate() error {
 //	{
 //	  1: messageId,    // uint32: matches request
 //	  2: status,       // uint8: 0=success, or error code
-//	  3: payload       // operation-specific response data (if success)
+//	  3: payload,      // operation-specific response data (if success)
+//	  7: chunk,        // uint16: chunk sequence number (chunked responses only)
+//	  8: more          // bool: further chunks follow (chunked responses only)
 //	}
+//
+// The chunk keys are distinct from all request and notification keys so a
+// chunk is never mistaken for either. See EncodeResponseChunks for how
+// oversized responses are split.
 type Response struct {
 	MessageID uint32 `cbor:"1,keyasint"`
 	Status    Status `cbor:"2,keyasint"`
 	Payload   any    `cbor:"3,keyasint,omitempty"`
+	Chunk     uint16 `cbor:"7,keyasint,omitempty"`
+	More      bool   `cbor:"8,keyasint,omitempty"`
 }
 
 // IsSuccess returns true if the response indicates success.
@@ -89,6 +102,96 @@ type Notification struct {
 	Changes        map[uint16]any `cbor:"5,keyasint"`
 }
 
+// Event represents a single event record (interaction model §9.1).
+//
+// CBOR encoding:
+//
+//	{
+//	  1: eventNumber,  // uint64: monotonic counter
+//	  2: timestamp,    // uint64: Unix timestamp (ms)
+//	  3: endpointId,   // uint8
+//	  4: featureId,    // uint8
+//	  5: eventId,      // uint8
+//	  6: data          // event-specific payload
+//	}
+type Event struct {
+	EventNumber uint64 `cbor:"1,keyasint"`
+	Timestamp   uint64 `cbor:"2,keyasint"`
+	EndpointID  uint8  `cbor:"3,keyasint"`
+	FeatureID   uint8  `cbor:"4,keyasint"`
+	EventID     uint8  `cbor:"5,keyasint"`
+	Data        any    `cbor:"6,keyasint,omitempty"`
+}
+
+// EventNotification delivers one or more events to an event subscription.
+// It shares the notification envelope (messageId 0) but carries event
+// records at key 6 instead of attribute changes at key 5.
+//
+// CBOR encoding:
+//
+//	{
+//	  1: 0,                // messageId 0 = notification
+//	  2: subscriptionId,   // uint32
+//	  3: endpointId,       // uint8
+//	  4: featureId,        // uint8
+//	  6: events            // array of event records
+//	}
+type EventNotification struct {
+	SubscriptionID uint32
+	EndpointID     uint8
+	FeatureID      uint8
+	Events         []Event
+}
+
+// EventIDList is a list of event IDs. It is encoded as a CBOR array of
+// integers rather than a byte string (the default for []uint8).
+type EventIDList []uint8
+
+// MarshalCBOR encodes the list as an array of unsigned integers.
+func (l EventIDList) MarshalCBOR() ([]byte, error) {
+	ids := make([]uint16, len(l))
+	for i, id := range l {
+		ids[i] = uint16(id)
+	}
+	return Marshal(ids)
+}
+
+// UnmarshalCBOR decodes an array of unsigned integers into the list.
+func (l *EventIDList) UnmarshalCBOR(data []byte) error {
+	var ids []uint16
+	if err := decMode.Unmarshal(data, &ids); err != nil {
+		return err
+	}
+	list := make(EventIDList, len(ids))
+	for i, id := range ids {
+		list[i] = uint8(id)
+	}
+	*l = list
+	return nil
+}
+
+// ExtractEvents extracts event records from a raw CBOR-decoded value
+// (e.g. the events field of a Subscribe response after round-trip).
+// Returns nil if the value is not a valid event list.
+func ExtractEvents(v any) []Event {
+	if v == nil {
+		return nil
+	}
+	if events, ok := v.([]Event); ok {
+		return events
+	}
+
+	data, err := Marshal(v)
+	if err != nil {
+		return nil
+	}
+	var events []Event
+	if err := Unmarshal(data, &events); err != nil {
+		return nil
+	}
+	return events
+}
+
 // ReadPayload represents the payload for a Read request.
 //
 // CBOR encoding: array of attribute IDs to read (empty = all)
@@ -200,12 +303,25 @@ type WriteResponsePayload map[uint16]any
 //	{
 //	  1: attributeIds,  // array (empty = all)
 //	  2: minInterval,   // uint32: minimum ms between notifications
-//	  3: maxInterval    // uint32: maximum ms without notification (heartbeat)
+//	  3: maxInterval,   // uint32: maximum ms without notification (heartbeat)
+//	  4: eventIds,      // array of uint8: events to subscribe to
+//	  5: eventMin       // uint64: replay buffered events from this number
 //	}
+//
+// A subscription with eventIds but no attributeIds is event-only: it
+// receives no priming report and no heartbeats.
 type SubscribePayload struct {
-	AttributeIDs []uint16 `cbor:"1,keyasint,omitempty"`
-	MinInterval  uint32   `cbor:"2,keyasint,omitempty"`
-	MaxInterval  uint32   `cbor:"3,keyasint,omitempty"`
+	AttributeIDs []uint16    `cbor:"1,keyasint,omitempty"`
+	MinInterval  uint32      `cbor:"2,keyasint,omitempty"`
+	MaxInterval  uint32      `cbor:"3,keyasint,omitempty"`
+	EventIDs     EventIDList `cbor:"4,keyasint,omitempty"`
+	EventMin     uint64      `cbor:"5,keyasint,omitempty"`
+}
+
+// IsEventOnly returns true if the subscription requests events but no
+// attributes.
+func (sp *SubscribePayload) IsEventOnly() bool {
+	return len(sp.EventIDs) > 0 && len(sp.AttributeIDs) == 0
 }
 
 // ExtractSubscribePayload extracts a subscribe payload from a raw
@@ -254,6 +370,18 @@ func ExtractSubscribePayload(payload any) *SubscribePayload {
 	if v, ok := m[3].(uint64); ok {
 		sp.MaxInterval = uint32(v)
 	}
+	// key 4: eventIDs
+	if arr, ok := m[4].([]any); ok {
+		for _, item := range arr {
+			if id, ok := toUint8(item); ok {
+				sp.EventIDs = append(sp.EventIDs, id)
+			}
+		}
+	}
+	// key 5: eventMin
+	if v, ok := m[5].(uint64); ok {
+		sp.EventMin = v
+	}
 
 	return sp
 }
@@ -264,11 +392,13 @@ func ExtractSubscribePayload(payload any) *SubscribePayload {
 //
 //	{
 //	  1: subscriptionId,  // uint32
-//	  2: currentValues    // map of current attribute values (priming report)
+//	  2: currentValues,   // map of current attribute values (priming report)
+//	  3: events           // buffered events replayed from eventMin
 //	}
 type SubscribeResponsePayload struct {
 	SubscriptionID uint32         `cbor:"1,keyasint"`
 	CurrentValues  map[uint16]any `cbor:"2,keyasint,omitempty"`
+	Events         []Event        `cbor:"3,keyasint,omitempty"`
 }
 
 // UnsubscribePayload represents the payload for an Unsubscribe request.
@@ -305,10 +435,62 @@ type InvokeResponsePayload any
 // CBOR encoding:
 //
 //	{
-//	  1: message  // string: human-readable error message
+//	  1: message,    // string: human-readable error message
+//	  2: retryAfter, // uint32: ms to wait before retrying (StatusBusy only)
+//	  3: fields      // array: per-field errors (FieldError)
 //	}
 type ErrorPayload struct {
-	Message string `cbor:"1,keyasint,omitempty"`
+	Message    string       `cbor:"1,keyasint,omitempty"`
+	RetryAfter uint32       `cbor:"2,keyasint,omitempty"`
+	Fields     []FieldError `cbor:"3,keyasint,omitempty"`
+}
+
+// FieldError identifies an invalid field in a request payload and the
+// constraint it violates. Peers may ignore keys they do not know, so new
+// details can be added without a version change.
+//
+// CBOR encoding:
+//
+//	{
+//	  1: path,       // string: attribute or parameter path, e.g. "slots[3].duration"
+//	  2: message,    // string: what is wrong with the value
+//	  3: constraint, // uint8: Constraint kind
+//	  4: limit       // the violated limit, e.g. a device maximum (MIN, MAX, MAX_ITEMS)
+//	}
+type FieldError struct {
+	Path       string     `cbor:"1,keyasint"`
+	Message    string     `cbor:"2,keyasint,omitempty"`
+	Constraint Constraint `cbor:"3,keyasint,omitempty"`
+	Limit      any        `cbor:"4,keyasint,omitempty"`
+}
+
+// Error implements the error interface.
+func (e FieldError) Error() string {
+	return e.Path + ": " + e.Message
+}
+
+// LimitInt64 returns the limit as an integer, if it is one.
+func (e FieldError) LimitInt64() (int64, bool) {
+	if e.Limit == nil {
+		return 0, false
+	}
+	return ToInt64(e.Limit)
+}
+
+// ExtractErrorPayload extracts an error payload from a raw CBOR-decoded
+// value. Returns nil if the payload is missing or malformed.
+func ExtractErrorPayload(payload any) *ErrorPayload {
+	if payload == nil {
+		return nil
+	}
+	if ep, ok := payload.(*ErrorPayload); ok {
+		return ep
+	}
+	var ep ErrorPayload
+	if !remarshal(payload, &ep) {
+		return nil
+	}
+	return &ep
 }
 
 // ControlMessage represents a transport-level control message.
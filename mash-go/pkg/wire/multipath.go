@@ -0,0 +1,126 @@
+package wire
+
+// Multi-path requests batch Read or Subscribe across several endpoints and
+// features in one round-trip. They are addressed to the reserved
+// MultiPathEndpointID/MultiPathFeatureID pair and carry a MultiPathPayload.
+const (
+	// MultiPathEndpointID is the endpoint ID addressing a multi-path request.
+	MultiPathEndpointID uint8 = 0xFF
+
+	// MultiPathFeatureID is the feature ID addressing a multi-path request.
+	MultiPathFeatureID uint8 = 0xFF
+
+	// MaxPaths is the maximum number of paths in one multi-path request.
+	MaxPaths = 64
+)
+
+// IsMultiPath returns true if the request is addressed to the reserved
+// multi-path endpoint/feature pair.
+func (r *Request) IsMultiPath() bool {
+	return r.EndpointID == MultiPathEndpointID && r.FeatureID == MultiPathFeatureID
+}
+
+// AttributePath addresses attributes of one feature on one endpoint.
+//
+// CBOR encoding:
+//
+//	{
+//	  1: endpointId,    // uint8
+//	  2: featureId,     // uint8
+//	  3: attributeIds   // array (empty = all)
+//	}
+type AttributePath struct {
+	EndpointID   uint8    `cbor:"1,keyasint"`
+	FeatureID    uint8    `cbor:"2,keyasint"`
+	AttributeIDs []uint16 `cbor:"3,keyasint,omitempty"`
+}
+
+// MultiPathPayload represents the payload for a multi-path Read or
+// Subscribe request.
+//
+// CBOR encoding:
+//
+//	{
+//	  1: paths,        // array of AttributePath
+//	  2: minInterval,  // uint32: Subscribe only, as SubscribePayload
+//	  3: maxInterval   // uint32: Subscribe only, as SubscribePayload
+//	}
+type MultiPathPayload struct {
+	Paths       []AttributePath `cbor:"1,keyasint"`
+	MinInterval uint32          `cbor:"2,keyasint,omitempty"`
+	MaxInterval uint32          `cbor:"3,keyasint,omitempty"`
+}
+
+// ExtractMultiPathPayload extracts a multi-path payload from a raw
+// CBOR-decoded value. Returns nil if the payload is missing or malformed.
+func ExtractMultiPathPayload(payload any) *MultiPathPayload {
+	if payload == nil {
+		return nil
+	}
+	if mp, ok := payload.(*MultiPathPayload); ok {
+		return mp
+	}
+	var mp MultiPathPayload
+	if !remarshal(payload, &mp) {
+		return nil
+	}
+	return &mp
+}
+
+// PathResult is the outcome of one path of a multi-path request.
+//
+// CBOR encoding:
+//
+//	{
+//	  1: endpointId,  // uint8
+//	  2: featureId,   // uint8
+//	  3: status,      // uint8: status code for this path
+//	  4: values       // map of attribute values (success only)
+//	}
+type PathResult struct {
+	EndpointID uint8          `cbor:"1,keyasint"`
+	FeatureID  uint8          `cbor:"2,keyasint"`
+	Status     Status         `cbor:"3,keyasint"`
+	Values     map[uint16]any `cbor:"4,keyasint,omitempty"`
+}
+
+// MultiPathResponsePayload represents the payload for a multi-path Read or
+// Subscribe response. Results are in request order.
+//
+// CBOR encoding:
+//
+//	{
+//	  1: subscriptionId,  // uint32: Subscribe only (0 = no path subscribed)
+//	  2: results          // array of PathResult (priming report on Subscribe)
+//	}
+type MultiPathResponsePayload struct {
+	SubscriptionID uint32       `cbor:"1,keyasint,omitempty"`
+	Results        []PathResult `cbor:"2,keyasint"`
+}
+
+// ExtractMultiPathResponse extracts a multi-path response payload from a
+// raw CBOR-decoded value. Returns nil if the payload is missing or
+// malformed.
+func ExtractMultiPathResponse(payload any) *MultiPathResponsePayload {
+	if payload == nil {
+		return nil
+	}
+	if mr, ok := payload.(*MultiPathResponsePayload); ok {
+		return mr
+	}
+	var mr MultiPathResponsePayload
+	if !remarshal(payload, &mr) {
+		return nil
+	}
+	return &mr
+}
+
+// remarshal converts a raw CBOR-decoded value into a typed payload by
+// re-encoding it.
+func remarshal(payload any, v any) bool {
+	data, err := Marshal(payload)
+	if err != nil {
+		return false
+	}
+	return Unmarshal(data, v) == nil
+}
@@ -45,6 +45,10 @@ const (
 
 	// StatusResourceExhausted indicates a resource limit has been reached.
 	StatusResourceExhausted Status = 13
+
+	// StatusVersionMismatch indicates a conditional write's data version
+	// precondition did not match the feature's current data version.
+	StatusVersionMismatch Status = 14
 )
 
 // String returns the status name.
@@ -78,6 +82,8 @@ func (s Status) String() string {
 		return "TIMEOUT"
 	case StatusResourceExhausted:
 		return "RESOURCE_EXHAUSTED"
+	case StatusVersionMismatch:
+		return "VERSION_MISMATCH"
 	default:
 		return "UNKNOWN"
 	}
@@ -96,9 +102,87 @@ func (s Status) IsError() bool {
 // CommandError is returned by command handlers to indicate a specific
 // wire-level error status. The protocol handler extracts the Status
 // field instead of falling through to the generic StatusInvalidCommand.
+// Fields and RetryAfter are carried to the peer in the ErrorPayload.
 type CommandError struct {
 	Status  Status
 	Message string
+
+	// Fields identifies the offending parameters (optional).
+	Fields []FieldError
+
+	// RetryAfter is a retry hint in milliseconds (StatusBusy only).
+	RetryAfter uint32
 }
 
 func (e *CommandError) Error() string { return e.Message }
+
+// ErrorPayload returns the error payload to send for e.
+func (e *CommandError) ErrorPayload() *ErrorPayload {
+	return &ErrorPayload{Message: e.Message, RetryAfter: e.RetryAfter, Fields: e.Fields}
+}
+
+// NewFieldError returns a CommandError with the given status for a single
+// invalid field. The message is the field error's text.
+func NewFieldError(status Status, field FieldError) *CommandError {
+	return &CommandError{Status: status, Message: field.Error(), Fields: []FieldError{field}}
+}
+
+// Constraint identifies the kind of constraint a field violates.
+type Constraint uint8
+
+const (
+	// ConstraintUnspecified is used when no more specific kind applies.
+	ConstraintUnspecified Constraint = 0
+
+	// ConstraintType indicates the value has the wrong wire type.
+	ConstraintType Constraint = 1
+
+	// ConstraintRequired indicates a required field is missing.
+	ConstraintRequired Constraint = 2
+
+	// ConstraintNotNull indicates a null value for a non-nullable field.
+	ConstraintNotNull Constraint = 3
+
+	// ConstraintEnum indicates the value is not one of the enum values.
+	ConstraintEnum Constraint = 4
+
+	// ConstraintMin indicates the value is below the limit.
+	ConstraintMin Constraint = 5
+
+	// ConstraintMax indicates the value is above the limit, e.g. a device
+	// capacity.
+	ConstraintMax Constraint = 6
+
+	// ConstraintMaxItems indicates an array has more items than the limit.
+	ConstraintMaxItems Constraint = 7
+
+	// ConstraintRelation indicates the value conflicts with another field,
+	// e.g. a minimum above the matching maximum.
+	ConstraintRelation Constraint = 8
+)
+
+// String returns the constraint name.
+func (c Constraint) String() string {
+	switch c {
+	case ConstraintUnspecified:
+		return "UNSPECIFIED"
+	case ConstraintType:
+		return "TYPE"
+	case ConstraintRequired:
+		return "REQUIRED"
+	case ConstraintNotNull:
+		return "NOT_NULL"
+	case ConstraintEnum:
+		return "ENUM"
+	case ConstraintMin:
+		return "MIN"
+	case ConstraintMax:
+		return "MAX"
+	case ConstraintMaxItems:
+		return "MAX_ITEMS"
+	case ConstraintRelation:
+		return "RELATION"
+	default:
+		return "UNKNOWN"
+	}
+}
@@ -0,0 +1,50 @@
+package zone
+
+import "github.com/mash-protocol/mash-go/pkg/cert"
+
+// Action is something a zone peer asks a device to do, checked against the
+// peer's role.
+type Action uint8
+
+// Actions, from least to most privileged.
+const (
+	ActionRead    Action = 1 // Read attributes and subscribe
+	ActionControl Action = 2 // Write attributes and invoke commands
+	ActionManage  Action = 3 // Remove the zone, renew certificates, rotate the Zone CA
+)
+
+// String returns a human-readable action name.
+func (a Action) String() string {
+	switch a {
+	case ActionRead:
+		return "READ"
+	case ActionControl:
+		return "CONTROL"
+	case ActionManage:
+		return "MANAGE"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+// RoleAllows reports whether a peer with the given role may perform action.
+//
+//   - Owners may do everything.
+//   - Admins may read and control, but zone management stays with the owner
+//     that holds the Zone CA.
+//   - Members may only read.
+//
+// A zero role means the peer's role is unknown (e.g. a session set up
+// without a certificate); like any unknown role it is allowed nothing.
+func RoleAllows(role cert.Role, action Action) bool {
+	switch role {
+	case cert.RoleOwner:
+		return true
+	case cert.RoleAdmin:
+		return action <= ActionControl
+	case cert.RoleMember:
+		return action <= ActionRead
+	default:
+		return false
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/mash-protocol/mash-go/pkg/cert"
+	"github.com/mash-protocol/mash-go/pkg/clock"
 )
 
 // Zone errors.
@@ -63,6 +64,8 @@ type ZoneValue struct {
 
 	// ExpiresAt is when the value expires (zero if indefinite).
 	ExpiresAt time.Time
+
+	clock clock.Clock
 }
 
 // IsExpired returns true if the value has expired.
@@ -70,7 +73,7 @@ func (v *ZoneValue) IsExpired() bool {
 	if v.ExpiresAt.IsZero() {
 		return false
 	}
-	return time.Now().After(v.ExpiresAt)
+	return clock.OrReal(v.clock).Now().After(v.ExpiresAt)
 }
 
 // Priority returns the zone's priority for resolution.
@@ -88,24 +91,35 @@ type MultiZoneValue struct {
 
 	// WinningZoneID is the zone whose value is currently effective.
 	WinningZoneID string
+
+	clock clock.Clock
 }
 
 // NewMultiZoneValue creates a new multi-zone value tracker.
 func NewMultiZoneValue() *MultiZoneValue {
+	return NewMultiZoneValueWithClock(nil)
+}
+
+// NewMultiZoneValueWithClock creates a multi-zone value tracker that stamps
+// and expires values using c. A nil c uses the real clock.
+func NewMultiZoneValueWithClock(c clock.Clock) *MultiZoneValue {
 	return &MultiZoneValue{
 		Values: make(map[string]*ZoneValue),
+		clock:  clock.OrReal(c),
 	}
 }
 
 // Set sets a value for a specific zone.
 func (m *MultiZoneValue) Set(zoneID string, zoneType cert.ZoneType, value int64, duration time.Duration) {
-	now := time.Now()
+	c := clock.OrReal(m.clock)
+	now := c.Now()
 	zv := &ZoneValue{
 		ZoneID:   zoneID,
 		ZoneType: zoneType,
 		Value:    value,
 		Duration: duration,
 		SetAt:    now,
+		clock:    c,
 	}
 	if duration > 0 {
 		zv.ExpiresAt = now.Add(duration)
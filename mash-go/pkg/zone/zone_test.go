@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/mash-protocol/mash-go/pkg/cert"
+	"github.com/mash-protocol/mash-go/pkg/clock"
 )
 
 func TestMultiZoneValueLimits(t *testing.T) {
@@ -62,14 +63,17 @@ func TestMultiZoneValueLimits(t *testing.T) {
 	})
 
 	t.Run("ExpiredValuesIgnored", func(t *testing.T) {
-		mzv := NewMultiZoneValue()
+		fake := clock.NewFake(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+		mzv := NewMultiZoneValueWithClock(fake)
 
-		// Set a value that expires immediately
-		mzv.Set("zone-1", cert.ZoneTypeGrid, 1000, 1*time.Millisecond)
+		mzv.Set("zone-1", cert.ZoneTypeGrid, 1000, time.Hour)
 		mzv.Set("zone-2", cert.ZoneTypeLocal, 5000, 0) // No expiry
 
-		// Wait for first to expire
-		time.Sleep(5 * time.Millisecond)
+		if val, _ := mzv.ResolveLimits(); val == nil || *val != 1000 {
+			t.Fatalf("ResolveLimits() = %v, want 1000 before expiry", val)
+		}
+
+		fake.Advance(time.Hour + time.Second)
 
 		val, zoneID := mzv.ResolveLimits()
 		if val == nil || *val != 5000 {
@@ -501,3 +505,30 @@ func TestZoneTypeEnforcement(t *testing.T) {
 		}
 	})
 }
+
+func TestRoleAllows(t *testing.T) {
+	tests := []struct {
+		role                  cert.Role
+		read, control, manage bool
+	}{
+		{0, false, false, false},
+		{cert.RoleOwner, true, true, true},
+		{cert.RoleAdmin, true, true, false},
+		{cert.RoleMember, true, false, false},
+		{cert.Role(99), false, false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.role.String(), func(t *testing.T) {
+			if got := RoleAllows(tt.role, ActionRead); got != tt.read {
+				t.Errorf("RoleAllows(%v, READ) = %v, want %v", tt.role, got, tt.read)
+			}
+			if got := RoleAllows(tt.role, ActionControl); got != tt.control {
+				t.Errorf("RoleAllows(%v, CONTROL) = %v, want %v", tt.role, got, tt.control)
+			}
+			if got := RoleAllows(tt.role, ActionManage); got != tt.manage {
+				t.Errorf("RoleAllows(%v, MANAGE) = %v, want %v", tt.role, got, tt.manage)
+			}
+		})
+	}
+}
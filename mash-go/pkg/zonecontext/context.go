@@ -1,5 +1,5 @@
 // Package zonecontext provides context keys for propagating caller zone
-// identity (zone ID and zone type) through context.Context. This package
+// identity (zone ID, zone type and role) through context.Context. This package
 // exists as a neutral dependency that both pkg/service and pkg/features
 // can import without creating an import cycle.
 package zonecontext
@@ -45,3 +45,21 @@ func CallerZoneTypeFromContext(ctx context.Context) cert.ZoneType {
 	}
 	return 0
 }
+
+type callerRoleKey struct{}
+
+// ContextWithCallerRole returns a new context with the caller's zone role.
+func ContextWithCallerRole(ctx context.Context, role cert.Role) context.Context {
+	return context.WithValue(ctx, callerRoleKey{}, role)
+}
+
+// CallerRoleFromContext extracts the caller's zone role from the context.
+// Returns 0 (unknown) if not set.
+func CallerRoleFromContext(ctx context.Context) cert.Role {
+	if v := ctx.Value(callerRoleKey{}); v != nil {
+		if role, ok := v.(cert.Role); ok {
+			return role
+		}
+	}
+	return 0
+}
@@ -43,3 +43,13 @@ func TestBothValuesCompose(t *testing.T) {
 		t.Errorf("CallerZoneTypeFromContext = %v, want %v", got, cert.ZoneTypeGrid)
 	}
 }
+
+func TestCallerRoleRoundTrip(t *testing.T) {
+	if got := CallerRoleFromContext(context.Background()); got != 0 {
+		t.Errorf("CallerRoleFromContext on empty ctx = %v, want 0", got)
+	}
+	ctx := ContextWithCallerRole(context.Background(), cert.RoleAdmin)
+	if got := CallerRoleFromContext(ctx); got != cert.RoleAdmin {
+		t.Errorf("CallerRoleFromContext = %v, want %v", got, cert.RoleAdmin)
+	}
+}